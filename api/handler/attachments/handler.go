@@ -0,0 +1,146 @@
+package attachments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+// Path is where the appeal attachments are served. The attachments of an appeal are listed and uploaded at
+// Path?appeal_id=<appeal id>, and the content of an attachment is downloaded at Path/<attachment id>
+const Path = "/attachments"
+
+// ActorVerifier verifies the bearer token of the caller, e.g. the OIDC verifier
+type ActorVerifier = auth.ActorOrganizationVerifier
+
+// Handler serves the supporting documents of the appeals to their requester, approvers, and the admins. The caller
+// is identified by the bearer token if the verifier is set, otherwise by the identity-aware proxy header
+type Handler struct {
+	verifier      ActorVerifier
+	logger        *zap.Logger
+	appealService domain.AppealService
+}
+
+// NewHandler returns the appeal attachments handler
+func NewHandler(verifier ActorVerifier, logger *zap.Logger, appealService domain.AppealService) *Handler {
+	return &Handler{verifier, logger, appealService}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, actor, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	if id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, Path), "/"); id != "" {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		attachmentID, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			http.Error(w, "invalid attachment id", http.StatusBadRequest)
+			return
+		}
+		h.download(ctx, w, uint(attachmentID), actor)
+		return
+	}
+
+	appealID, err := strconv.ParseUint(r.URL.Query().Get("appeal_id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid appeal id", http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		attachments, err := h.appealService.ListAttachments(ctx, uint(appealID), actor)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, attachments)
+	case http.MethodPost:
+		defer r.Body.Close()
+		attachment, err := h.appealService.AddAttachment(ctx, uint(appealID), actor, r.URL.Query().Get("filename"), r.Body)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, attachment)
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authenticate returns the request context carrying the verified actor along with their organization if any
+func (h *Handler) authenticate(w http.ResponseWriter, r *http.Request) (context.Context, string, bool) {
+	ctx, actor, err := auth.AuthenticateRequest(r, h.verifier)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil, "", false
+	}
+	return ctx, actor, true
+}
+
+func (h *Handler) download(ctx context.Context, w http.ResponseWriter, id uint, actor string) {
+	attachment, content, err := h.appealService.GetAttachmentContent(ctx, id, actor)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	// the content is served as is, the browsers mustn't render it as another type
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.Warn("failed to write attachment content", zap.Uint("attachment_id", id), zap.Error(err))
+	}
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	statusCodes := []struct {
+		err        error
+		statusCode int
+	}{
+		{appeal.ErrAppealNotFound, http.StatusNotFound},
+		{appeal.ErrAttachmentNotFound, http.StatusNotFound},
+		{appeal.ErrAttachmentForbidden, http.StatusForbidden},
+		{appeal.ErrAppealIDEmptyParam, http.StatusBadRequest},
+		{appeal.ErrAttachmentFilenameRequired, http.StatusBadRequest},
+		{appeal.ErrAttachmentTooLarge, http.StatusRequestEntityTooLarge},
+		{appeal.ErrAttachmentTypeNotAllowed, http.StatusUnsupportedMediaType},
+		{appeal.ErrAttachmentRejected, http.StatusUnprocessableEntity},
+		{appeal.ErrAttachmentStorageNotConfigured, http.StatusNotImplemented},
+	}
+	for _, sc := range statusCodes {
+		if errors.Is(err, sc.err) {
+			http.Error(w, err.Error(), sc.statusCode)
+			return
+		}
+	}
+
+	h.logger.Error("appeal attachment request failed", zap.Error(err))
+	http.Error(w, "something went wrong, please try again later", http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,138 @@
+package attachments_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/odpf/guardian/api/handler/attachments"
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type verifierFunc func(ctx context.Context, token string) (string, string, error)
+
+func (f verifierFunc) VerifyActorOrganization(ctx context.Context, token string) (string, string, error) {
+	return f(ctx, token)
+}
+
+func TestHandler(t *testing.T) {
+	serve := func(h http.Handler, method, target string, body io.Reader, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, body)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	proxyHeader := map[string]string{"X-Goog-Authenticated-User-Email": "requester@email.com"}
+
+	t.Run("should return unauthorized if the caller is not identified", func(t *testing.T) {
+		h := attachments.NewHandler(nil, zap.NewNop(), new(mocks.AppealService))
+
+		rec := serve(h, http.MethodGet, "/attachments?appeal_id=1", nil, nil)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("should return unauthorized if the token is not verified", func(t *testing.T) {
+		verifier := verifierFunc(func(ctx context.Context, token string) (string, string, error) {
+			return "", "", errors.New("invalid token")
+		})
+		h := attachments.NewHandler(verifier, zap.NewNop(), new(mocks.AppealService))
+
+		rec := serve(h, http.MethodGet, "/attachments?appeal_id=1", nil, map[string]string{"Authorization": "Bearer invalid"})
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("should return forbidden if the caller is not allowed to access the attachments", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		appealService.On("ListAttachments", mock.Anything, uint(1), "outsider@email.com").Return(nil, appeal.ErrAttachmentForbidden).Once()
+		h := attachments.NewHandler(nil, zap.NewNop(), appealService)
+
+		rec := serve(h, http.MethodGet, "/attachments?appeal_id=1", nil, map[string]string{"X-Goog-Authenticated-User-Email": "outsider@email.com"})
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("should list the attachments of the appeal as the verified actor", func(t *testing.T) {
+		verifier := verifierFunc(func(ctx context.Context, token string) (string, string, error) {
+			return "approver@email.com", "org-1", nil
+		})
+		appealService := new(mocks.AppealService)
+		appealService.On("ListAttachments", mock.MatchedBy(func(ctx context.Context) bool {
+			organizationID, _ := auth.OrganizationFromContext(ctx)
+			return organizationID == "org-1"
+		}), uint(1), "approver@email.com").Return([]*domain.Attachment{{ID: 2, AppealID: 1, Filename: "evidence.txt"}}, nil).Once()
+		h := attachments.NewHandler(verifier, zap.NewNop(), appealService)
+
+		rec := serve(h, http.MethodGet, "/attachments?appeal_id=1", nil, map[string]string{"Authorization": "Bearer token"})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"filename":"evidence.txt"`)
+		appealService.AssertExpectations(t)
+	})
+
+	t.Run("should upload the request body as the attachment", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		appealService.On("AddAttachment", mock.Anything, uint(1), "requester@email.com", "evidence.txt", mock.MatchedBy(func(r io.Reader) bool {
+			content, err := ioutil.ReadAll(r)
+			return err == nil && string(content) == "content"
+		})).Return(&domain.Attachment{ID: 2, AppealID: 1, Filename: "evidence.txt"}, nil).Once()
+		h := attachments.NewHandler(nil, zap.NewNop(), appealService)
+
+		rec := serve(h, http.MethodPost, "/attachments?appeal_id=1&filename=evidence.txt", strings.NewReader("content"), proxyHeader)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		appealService.AssertExpectations(t)
+	})
+
+	t.Run("should return the error status of a rejected attachment", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		appealService.On("AddAttachment", mock.Anything, uint(1), "requester@email.com", "evidence.txt", mock.Anything).Return(nil, appeal.ErrAttachmentTooLarge).Once()
+		h := attachments.NewHandler(nil, zap.NewNop(), appealService)
+
+		rec := serve(h, http.MethodPost, "/attachments?appeal_id=1&filename=evidence.txt", strings.NewReader("content"), proxyHeader)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("should download the attachment content", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		appealService.On("GetAttachmentContent", mock.Anything, uint(2), "requester@email.com").Return(
+			&domain.Attachment{ID: 2, AppealID: 1, Filename: "evidence.txt", ContentType: "text/plain", Size: 7},
+			ioutil.NopCloser(strings.NewReader("content")),
+			nil,
+		).Once()
+		h := attachments.NewHandler(nil, zap.NewNop(), appealService)
+
+		rec := serve(h, http.MethodGet, "/attachments/2", nil, proxyHeader)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=evidence.txt`, rec.Header().Get("Content-Disposition"))
+		assert.Equal(t, "content", rec.Body.String())
+	})
+
+	t.Run("should return bad request if the ids are invalid", func(t *testing.T) {
+		h := attachments.NewHandler(nil, zap.NewNop(), new(mocks.AppealService))
+
+		for _, target := range []string{"/attachments", "/attachments?appeal_id=a", "/attachments/a"} {
+			rec := serve(h, http.MethodGet, target, nil, proxyHeader)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
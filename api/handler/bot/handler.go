@@ -0,0 +1,248 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	// ResultDone is the result of an action made on the approval step
+	ResultDone = "done"
+	// ResultAlreadyDone is the result of an action the actor has already made on the approval step, e.g. on a double click
+	ResultAlreadyDone = "already_done"
+	// ResultFailed is the result of an action that couldn't be made, the text explains why
+	ResultFailed = "failed"
+
+	responseTypeInChannel = "in_channel"
+	responseTypeEphemeral = "ephemeral"
+
+	unknownActionMessage = "Unknown action, it should be either approve, reject, request_changes, or resubmit"
+)
+
+// Config configures the action endpoint for chat-bot integrations
+type Config struct {
+	// Token is the shared secret the bot sends as the bearer token. The endpoint is disabled if it's empty
+	Token string `mapstructure:"token"`
+	// SlackSigningSecret verifies the interactive message requests of Slack. The Slack endpoint is disabled if it's empty
+	SlackSigningSecret string `mapstructure:"slack_signing_secret"`
+	// SlackRequestMaxAge rejects the Slack requests signed longer ago, against replayed requests
+	SlackRequestMaxAge time.Duration `mapstructure:"slack_request_max_age" default:"5m"`
+}
+
+// ActionRequest is an action made by the actor through the bot, e.g. by clicking an approve button. The approvers
+// approve, reject, or request changes on the approval step, and the requester resubmits the appeal once the
+// requested changes are made. The comment is required by the request changes and resubmit actions
+type ActionRequest struct {
+	AppealID     uint   `json:"appeal_id"`
+	ApprovalName string `json:"approval_name"`
+	Actor        string `json:"actor"`
+	Action       string `json:"action"`
+	Comment      string `json:"comment"`
+}
+
+// ActionResponse is the outcome of the action. Text, ResponseType, and ReplaceOriginal follow the
+// Slack interactive message response so it can be sent back to Slack as is
+type ActionResponse struct {
+	Result          string `json:"result"`
+	AppealStatus    string `json:"appeal_status,omitempty"`
+	Text            string `json:"text"`
+	ResponseType    string `json:"response_type"`
+	ReplaceOriginal bool   `json:"replace_original"`
+}
+
+// Handler serves the approval actions made through the bots. The failed actions are responded with
+// 200 and ResultFailed so the bot can show the text to the actor
+type Handler struct {
+	token         string
+	logger        *zap.Logger
+	appealService domain.AppealService
+}
+
+// NewHandler returns the action endpoint handler
+func NewHandler(c Config, logger *zap.Logger, appealService domain.AppealService) *Handler {
+	return &Handler{c.Token, logger, appealService}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeResponse(w, http.StatusMethodNotAllowed, failed("Method not allowed"))
+		return
+	}
+	if !h.isAuthorized(r) {
+		writeResponse(w, http.StatusUnauthorized, failed("Unauthorized"))
+		return
+	}
+
+	var req ActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, failed("Invalid request body"))
+		return
+	}
+
+	writeResponse(w, http.StatusOK, h.makeAction(r.Context(), req))
+}
+
+func (h *Handler) isAuthorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return h.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
+
+// makeAction makes the action as the actor within the organization of the appeal, the bots aren't bound to any
+func (h *Handler) makeAction(ctx context.Context, req ActionRequest) *ActionResponse {
+	current, err := h.appealService.GetByID(ctx, req.AppealID)
+	if err != nil {
+		return h.failedAction(req, err)
+	}
+	if current == nil {
+		return failed("The appeal is not found")
+	}
+	ctx = auth.NewContext(ctx, req.Actor)
+	if current.OrganizationID != "" {
+		ctx = auth.NewOrganizationContext(ctx, current.OrganizationID)
+	}
+
+	var a *domain.Appeal
+	if req.Action == domain.AppealActionNameResubmit {
+		a, err = h.appealService.Resubmit(ctx, req.AppealID, req.Actor, req.Comment)
+	} else {
+		a, err = h.appealService.MakeAction(ctx, domain.ApprovalAction{
+			AppealID:     req.AppealID,
+			ApprovalName: req.ApprovalName,
+			Actor:        req.Actor,
+			Action:       req.Action,
+			Comment:      req.Comment,
+		})
+	}
+	if err != nil {
+		if res := getPreviousAction(current, req); res != nil {
+			return res
+		}
+		return h.failedAction(req, err)
+	}
+	if a == nil {
+		return failed("The appeal is not found")
+	}
+
+	text := fmt.Sprintf("You %s the appeal from %s to access %s", getActionPastTense(req.Action), a.User, getResourceURN(a))
+	if req.Action == domain.AppealActionNameResubmit {
+		text = fmt.Sprintf("You resubmitted your appeal to access %s", getResourceURN(a))
+	}
+	return &ActionResponse{
+		Result:          ResultDone,
+		AppealStatus:    a.Status,
+		Text:            text,
+		ResponseType:    responseTypeInChannel,
+		ReplaceOriginal: true,
+	}
+}
+
+// getPreviousAction returns the response of the same action if the actor has already made it on the appeal as it
+// was before the action, or nil otherwise
+func getPreviousAction(a *domain.Appeal, req ActionRequest) *ActionResponse {
+	expectedStatus := map[string]string{
+		domain.AppealActionNameApprove: domain.ApprovalStatusApproved,
+		domain.AppealActionNameReject:  domain.ApprovalStatusRejected,
+	}[req.Action]
+	if expectedStatus == "" {
+		return nil
+	}
+
+	for _, approval := range a.Approvals {
+		if approval.Name != req.ApprovalName {
+			continue
+		}
+		if approval.Status != expectedStatus || approval.Actor == nil || *approval.Actor != req.Actor {
+			return nil
+		}
+		return &ActionResponse{
+			Result:          ResultAlreadyDone,
+			AppealStatus:    a.Status,
+			Text:            fmt.Sprintf("You already %s the appeal from %s to access %s", getActionPastTense(req.Action), a.User, getResourceURN(a)),
+			ResponseType:    responseTypeInChannel,
+			ReplaceOriginal: true,
+		}
+	}
+	return nil
+}
+
+func (h *Handler) failedAction(req ActionRequest, err error) *ActionResponse {
+	messages := []struct {
+		err     error
+		message string
+	}{
+		{appeal.ErrActionForbidden, "You're not an approver of this approval step"},
+		{appeal.ErrActorMismatch, "You're not an approver of this approval step"},
+		{appeal.ErrActionInvalidValue, unknownActionMessage},
+		{appeal.ErrChangesCommentRequired, "A comment explaining the changes needed is required to request changes"},
+		{appeal.ErrResubmitCommentRequired, "A comment describing the changes made is required to resubmit the appeal"},
+		{appeal.ErrResubmitForbidden, "Only the requester can resubmit the appeal"},
+		{appeal.ErrChangesNotRequested, "No changes are requested on the appeal"},
+		{appeal.ErrAppealNotFound, "The appeal is not found"},
+		{appeal.ErrApprovalNameNotFound, "The approval step is not found"},
+		{appeal.ErrApprovalDependencyIsPending, "The previous approval steps are still pending"},
+		{appeal.ErrApprovalStatusApproved, "The approval step has already been approved"},
+		{appeal.ErrApprovalStatusRejected, "The approval step has already been rejected"},
+		{appeal.ErrApprovalStatusSkipped, "The approval step has been skipped"},
+		{appeal.ErrAppealStatusApproved, "The appeal has already been approved"},
+		{appeal.ErrAppealStatusRejected, "The appeal has already been rejected"},
+		{appeal.ErrAppealStatusCanceled, "The appeal has been canceled"},
+		{appeal.ErrAppealStatusTerminated, "The access has already been revoked"},
+	}
+	for _, m := range messages {
+		if errors.Is(err, m.err) {
+			return failed(m.message)
+		}
+	}
+
+	h.logger.Error("failed to make action through the bot",
+		zap.Uint("appeal_id", req.AppealID),
+		zap.String("approval_name", req.ApprovalName),
+		zap.String("actor", req.Actor),
+		zap.String("action", req.Action),
+		zap.Error(err),
+	)
+	return failed("Something went wrong, please try again later")
+}
+
+func failed(text string) *ActionResponse {
+	return &ActionResponse{
+		Result:       ResultFailed,
+		Text:         text,
+		ResponseType: responseTypeEphemeral,
+	}
+}
+
+func getActionPastTense(action string) string {
+	switch action {
+	case domain.AppealActionNameReject:
+		return "rejected"
+	case domain.AppealActionNameRequestChanges:
+		return "requested changes on"
+	}
+	return "approved"
+}
+
+func getResourceURN(a *domain.Appeal) string {
+	if a.Resource == nil {
+		return fmt.Sprintf("resource %d", a.ResourceID)
+	}
+	return a.Resource.URN
+}
+
+func writeResponse(w http.ResponseWriter, status int, res *ActionResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
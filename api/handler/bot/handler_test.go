@@ -0,0 +1,180 @@
+package bot_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/odpf/guardian/api/handler/bot"
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestHandler(t *testing.T) {
+	actor := "approver@email.com"
+	action := domain.ApprovalAction{
+		AppealID:     1,
+		ApprovalName: "approval_0",
+		Actor:        actor,
+		Action:       domain.AppealActionNameApprove,
+	}
+	body := `{"appeal_id":1,"approval_name":"approval_0","actor":"approver@email.com","action":"approve"}`
+	serve := func(h http.Handler, token string) (int, *bot.ActionResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/bot/actions", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		var res bot.ActionResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&res))
+		return rec.Code, &res
+	}
+
+	t.Run("should return unauthorized if the token doesn't match", func(t *testing.T) {
+		h := bot.NewHandler(bot.Config{Token: "secret"}, zap.NewNop(), new(mocks.AppealService))
+
+		actualStatus, actualResponse := serve(h, "invalid")
+
+		assert.Equal(t, http.StatusUnauthorized, actualStatus)
+		assert.Equal(t, bot.ResultFailed, actualResponse.Result)
+	})
+
+	t.Run("should make the action on the approval step", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := bot.NewHandler(bot.Config{Token: "secret"}, zap.NewNop(), appealService)
+		appealService.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1}, nil).Once()
+		appealService.On("MakeAction", mock.Anything, action).Return(&domain.Appeal{
+			User:     "user@email.com",
+			Status:   domain.AppealStatusActive,
+			Resource: &domain.Resource{URN: "urn"},
+		}, nil).Once()
+
+		actualStatus, actualResponse := serve(h, "secret")
+
+		assert.Equal(t, http.StatusOK, actualStatus)
+		assert.Equal(t, &bot.ActionResponse{
+			Result:          bot.ResultDone,
+			AppealStatus:    domain.AppealStatusActive,
+			Text:            "You approved the appeal from user@email.com to access urn",
+			ResponseType:    "in_channel",
+			ReplaceOriginal: true,
+		}, actualResponse)
+	})
+
+	t.Run("should make the action as the actor within the organization of the appeal", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := bot.NewHandler(bot.Config{Token: "secret"}, zap.NewNop(), appealService)
+		appealService.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1, OrganizationID: "org-a"}, nil).Once()
+		appealService.On("MakeAction", mock.MatchedBy(func(ctx context.Context) bool {
+			actualActor, _ := auth.ActorFromContext(ctx)
+			organizationID, _ := auth.OrganizationFromContext(ctx)
+			return actualActor == actor && organizationID == "org-a"
+		}), action).Return(&domain.Appeal{
+			User:     "user@email.com",
+			Status:   domain.AppealStatusActive,
+			Resource: &domain.Resource{URN: "urn"},
+		}, nil).Once()
+
+		actualStatus, actualResponse := serve(h, "secret")
+
+		assert.Equal(t, http.StatusOK, actualStatus)
+		assert.Equal(t, bot.ResultDone, actualResponse.Result)
+		appealService.AssertExpectations(t)
+	})
+
+	t.Run("should respond with failed result if the appeal is not found", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := bot.NewHandler(bot.Config{Token: "secret"}, zap.NewNop(), appealService)
+		appealService.On("GetByID", mock.Anything, uint(1)).Return(nil, appeal.ErrAppealNotFound).Once()
+
+		actualStatus, actualResponse := serve(h, "secret")
+
+		assert.Equal(t, http.StatusOK, actualStatus)
+		assert.Equal(t, bot.ResultFailed, actualResponse.Result)
+		assert.Equal(t, "The appeal is not found", actualResponse.Text)
+		appealService.AssertNotCalled(t, "MakeAction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not fail if the actor has already made the same action", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := bot.NewHandler(bot.Config{Token: "secret"}, zap.NewNop(), appealService)
+		appealService.On("MakeAction", mock.Anything, action).Return(nil, appeal.ErrAppealStatusApproved).Once()
+		appealService.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{
+			User:     "user@email.com",
+			Status:   domain.AppealStatusActive,
+			Resource: &domain.Resource{URN: "urn"},
+			Approvals: []*domain.Approval{
+				{Name: "approval_0", Status: domain.ApprovalStatusApproved, Actor: &actor},
+			},
+		}, nil).Once()
+
+		actualStatus, actualResponse := serve(h, "secret")
+
+		assert.Equal(t, http.StatusOK, actualStatus)
+		assert.Equal(t, bot.ResultAlreadyDone, actualResponse.Result)
+		assert.Equal(t, "You already approved the appeal from user@email.com to access urn", actualResponse.Text)
+	})
+
+	t.Run("should resubmit the appeal on behalf of the requester", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := bot.NewHandler(bot.Config{Token: "secret"}, zap.NewNop(), appealService)
+		appealService.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1}, nil).Once()
+		appealService.On("Resubmit", mock.Anything, uint(1), "user@email.com", "linked the ticket").Return(&domain.Appeal{
+			User:     "user@email.com",
+			Status:   domain.AppealStatusPending,
+			Resource: &domain.Resource{URN: "urn"},
+		}, nil).Once()
+		req := httptest.NewRequest(http.MethodPost, "/bot/actions", strings.NewReader(
+			`{"appeal_id":1,"actor":"user@email.com","action":"resubmit","comment":"linked the ticket"}`,
+		))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, req)
+
+		var actualResponse bot.ActionResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&actualResponse))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, bot.ResultDone, actualResponse.Result)
+		assert.Equal(t, "You resubmitted your appeal to access urn", actualResponse.Text)
+		appealService.AssertNotCalled(t, "MakeAction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should map the errors to friendly messages", func(t *testing.T) {
+		testCases := []struct {
+			err          error
+			expectedText string
+		}{
+			{appeal.ErrActionForbidden, "You're not an approver of this approval step"},
+			{appeal.ErrApprovalDependencyIsPending, "The previous approval steps are still pending"},
+			{appeal.ErrAppealStatusCanceled, "The appeal has been canceled"},
+			{appeal.ErrChangesCommentRequired, "A comment explaining the changes needed is required to request changes"},
+			{errors.New("unexpected error"), "Something went wrong, please try again later"},
+		}
+
+		for _, tc := range testCases {
+			appealService := new(mocks.AppealService)
+			h := bot.NewHandler(bot.Config{Token: "secret"}, zap.NewNop(), appealService)
+			appealService.On("MakeAction", mock.Anything, action).Return(nil, tc.err).Once()
+			appealService.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{
+				Approvals: []*domain.Approval{{Name: "approval_0", Status: domain.ApprovalStatusPending}},
+			}, nil).Once()
+
+			actualStatus, actualResponse := serve(h, "secret")
+
+			assert.Equal(t, http.StatusOK, actualStatus)
+			assert.Equal(t, bot.ResultFailed, actualResponse.Result)
+			assert.Equal(t, tc.expectedText, actualResponse.Text)
+			assert.Equal(t, "ephemeral", actualResponse.ResponseType)
+		}
+	})
+}
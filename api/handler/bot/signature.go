@@ -0,0 +1,88 @@
+package bot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMissingSignature is the error value if the request doesn't carry a signature or its timestamp
+	ErrMissingSignature = errors.New("missing request signature")
+	// ErrInvalidSignature is the error value if the signature doesn't match the request body
+	ErrInvalidSignature = errors.New("invalid request signature")
+	// ErrStaleRequest is the error value if the request is signed too long ago, e.g. a replayed request
+	ErrStaleRequest = errors.New("stale request")
+)
+
+// SignatureVerifier authenticates the inbound webhook requests of a chat platform
+type SignatureVerifier interface {
+	Verify(header http.Header, body []byte) error
+}
+
+// HMACSignatureVerifier verifies the timestamped HMAC-SHA256 signatures, the signing scheme used by
+// Slack and the platforms alike. The signature is the hex-encoded HMAC of the base string
+type HMACSignatureVerifier struct {
+	Secret          string
+	SignatureHeader string
+	TimestampHeader string
+	// SignaturePrefix is prepended to the hex-encoded HMAC, e.g. "v0="
+	SignaturePrefix string
+	// BaseString builds the signed content out of the unix timestamp and the raw body
+	BaseString func(timestamp string, body []byte) []byte
+	// MaxAge rejects the requests signed earlier, or later, than the duration from now
+	MaxAge  time.Duration
+	TimeNow func() time.Time
+}
+
+// NewSlackSignatureVerifier returns the verifier of the Slack signing secret
+func NewSlackSignatureVerifier(secret string, maxAge time.Duration) *HMACSignatureVerifier {
+	return &HMACSignatureVerifier{
+		Secret:          secret,
+		SignatureHeader: "X-Slack-Signature",
+		TimestampHeader: "X-Slack-Request-Timestamp",
+		SignaturePrefix: "v0=",
+		BaseString: func(timestamp string, body []byte) []byte {
+			return append([]byte("v0:"+timestamp+":"), body...)
+		},
+		MaxAge:  maxAge,
+		TimeNow: time.Now,
+	}
+}
+
+// Verify checks the signature against the body and the timestamp against the max age
+func (v *HMACSignatureVerifier) Verify(header http.Header, body []byte) error {
+	signature := header.Get(v.SignatureHeader)
+	timestamp := header.Get(v.TimestampHeader)
+	if signature == "" || timestamp == "" {
+		return ErrMissingSignature
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	age := v.TimeNow().Sub(time.Unix(seconds, 0))
+	if age > v.MaxAge || age < -v.MaxAge {
+		return ErrStaleRequest
+	}
+
+	if !strings.HasPrefix(signature, v.SignaturePrefix) {
+		return ErrInvalidSignature
+	}
+	actual, err := hex.DecodeString(strings.TrimPrefix(signature, v.SignaturePrefix))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(v.Secret))
+	mac.Write(v.BaseString(timestamp, body))
+	if !hmac.Equal(actual, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package bot_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/api/handler/bot"
+	"github.com/stretchr/testify/assert"
+)
+
+func signSlackRequest(secret string, timestamp time.Time, body string) http.Header {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":" + body))
+
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", ts)
+	header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func TestHMACSignatureVerifier(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	verifier := bot.NewSlackSignatureVerifier("signing-secret", 5*time.Minute)
+	verifier.TimeNow = func() time.Time { return now }
+	body := []byte("payload=%7B%7D")
+
+	testCases := []struct {
+		name          string
+		header        http.Header
+		expectedError error
+	}{
+		{
+			name:   "valid signature",
+			header: signSlackRequest("signing-secret", now.Add(-time.Minute), string(body)),
+		},
+		{
+			name:          "unsigned request",
+			header:        http.Header{},
+			expectedError: bot.ErrMissingSignature,
+		},
+		{
+			name:          "signed with another secret",
+			header:        signSlackRequest("another-secret", now, string(body)),
+			expectedError: bot.ErrInvalidSignature,
+		},
+		{
+			name:          "signed for another body",
+			header:        signSlackRequest("signing-secret", now, "payload=%7B%22type%22%7D"),
+			expectedError: bot.ErrInvalidSignature,
+		},
+		{
+			name:          "stale request",
+			header:        signSlackRequest("signing-secret", now.Add(-10*time.Minute), string(body)),
+			expectedError: bot.ErrStaleRequest,
+		},
+		{
+			name:          "request from the future",
+			header:        signSlackRequest("signing-secret", now.Add(10*time.Minute), string(body)),
+			expectedError: bot.ErrStaleRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualError := verifier.Verify(tc.header, body)
+
+			assert.Equal(t, tc.expectedError, actualError)
+		})
+	}
+}
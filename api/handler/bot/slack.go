@@ -0,0 +1,208 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	slackHost = "https://slack.com"
+
+	slackInteractionBlockActions = "block_actions"
+
+	// maxSlackRequestSize limits the interaction payloads read from Slack
+	maxSlackRequestSize = 1 << 20
+)
+
+// SlackUserResolver resolves the email of the Slack user who made the interaction
+type SlackUserResolver interface {
+	GetEmail(userID string) (string, error)
+}
+
+type slackUserClient struct {
+	accessToken string
+	httpClient  *http.Client
+}
+
+// NewSlackUserResolver returns the resolver backed by the Slack users.info API. The access token
+// requires the users:read.email scope
+func NewSlackUserResolver(accessToken string) SlackUserResolver {
+	return &slackUserClient{accessToken, &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (c *slackUserClient) GetEmail(userID string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, slackHost+"/api/users.info?user="+url.QueryEscape(userID), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		User  *struct {
+			Profile struct {
+				Email string `json:"email"`
+			} `json:"profile"`
+		} `json:"user"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	if !result.OK {
+		return "", errors.New(result.Error)
+	}
+	if result.User == nil || result.User.Profile.Email == "" {
+		return "", fmt.Errorf("email of slack user %q is not found", userID)
+	}
+	return result.User.Profile.Email, nil
+}
+
+// slackInteraction is the payload of the interactive components of a Slack message
+type slackInteraction struct {
+	Type string `json:"type"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	// State holds the values of the input blocks of the message by block_id and action_id, the comment of the
+	// request changes and resubmit actions is read from the input with the SlackCommentActionID action_id
+	State struct {
+		Values map[string]map[string]struct {
+			Value string `json:"value"`
+		} `json:"values"`
+	} `json:"state"`
+}
+
+// SlackCommentActionID is the action_id of the plain text input carrying the comment of the action
+const SlackCommentActionID = "comment"
+
+// getComment returns the value of the comment input of the message, if any
+func (i slackInteraction) getComment() string {
+	for _, inputs := range i.State.Values {
+		if input, ok := inputs[SlackCommentActionID]; ok {
+			return input.Value
+		}
+	}
+	return ""
+}
+
+// SlackActionValue returns the value of an action button, the button's action_id being the action
+func SlackActionValue(appealID uint, approvalName string) string {
+	return fmt.Sprintf("%d:%s", appealID, approvalName)
+}
+
+// SlackHandler serves the action buttons of the Slack messages. Unlike the bot token endpoint,
+// the actor is resolved from the Slack user of the signed request rather than trusted from the payload
+type SlackHandler struct {
+	verifier SignatureVerifier
+	users    SlackUserResolver
+	actions  *Handler
+}
+
+// NewSlackHandler returns the Slack interactivity endpoint handler
+func NewSlackHandler(verifier SignatureVerifier, users SlackUserResolver, logger *zap.Logger, appealService domain.AppealService) *SlackHandler {
+	return &SlackHandler{
+		verifier: verifier,
+		users:    users,
+		actions:  &Handler{logger: logger, appealService: appealService},
+	}
+}
+
+func (h *SlackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeResponse(w, http.StatusMethodNotAllowed, failed("Method not allowed"))
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSlackRequestSize))
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, failed("Invalid request body"))
+		return
+	}
+	if err := h.verifier.Verify(r.Header, body); err != nil {
+		h.actions.logger.Warn("rejected slack interaction", zap.Error(err))
+		writeResponse(w, http.StatusUnauthorized, failed("Unauthorized"))
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, failed("Invalid request body"))
+		return
+	}
+	var interaction slackInteraction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &interaction); err != nil {
+		writeResponse(w, http.StatusBadRequest, failed("Invalid request body"))
+		return
+	}
+
+	req, res := h.parseAction(interaction)
+	if res == nil {
+		res = h.actions.makeAction(r.Context(), *req)
+	}
+	writeResponse(w, http.StatusOK, res)
+}
+
+// parseAction returns the action request of the interaction, or the failed response if it isn't an approval action
+func (h *SlackHandler) parseAction(interaction slackInteraction) (*ActionRequest, *ActionResponse) {
+	if interaction.Type != slackInteractionBlockActions || len(interaction.Actions) == 0 {
+		return nil, failed("Unsupported interaction")
+	}
+
+	action := interaction.Actions[0]
+	switch action.ActionID {
+	case domain.AppealActionNameApprove,
+		domain.AppealActionNameReject,
+		domain.AppealActionNameRequestChanges,
+		domain.AppealActionNameResubmit:
+	default:
+		return nil, failed(unknownActionMessage)
+	}
+	parts := strings.SplitN(action.Value, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, failed("Invalid action value")
+	}
+	appealID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, failed("Invalid action value")
+	}
+
+	actor, err := h.users.GetEmail(interaction.User.ID)
+	if err != nil {
+		h.actions.logger.Error("failed to resolve the slack user",
+			zap.String("slack_user_id", interaction.User.ID),
+			zap.Error(err),
+		)
+		return nil, failed("Unable to identify you, please try again later")
+	}
+
+	return &ActionRequest{
+		AppealID:     uint(appealID),
+		ApprovalName: parts[1],
+		Actor:        actor,
+		Action:       action.ActionID,
+		Comment:      interaction.getComment(),
+	}, nil
+}
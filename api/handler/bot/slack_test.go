@@ -0,0 +1,177 @@
+package bot_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/api/handler/bot"
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type slackUserResolverFunc func(userID string) (string, error)
+
+func (f slackUserResolverFunc) GetEmail(userID string) (string, error) {
+	return f(userID)
+}
+
+func TestSlackHandler(t *testing.T) {
+	secret := "signing-secret"
+	users := slackUserResolverFunc(func(userID string) (string, error) {
+		if userID == "U123" {
+			return "approver@email.com", nil
+		}
+		return "", errors.New("users_not_found")
+	})
+	newBody := func(userID, actionID, value string) string {
+		payload := map[string]interface{}{
+			"type": "block_actions",
+			"user": map[string]string{"id": userID},
+			"actions": []map[string]string{
+				{"action_id": actionID, "value": value},
+			},
+		}
+		b, _ := json.Marshal(payload)
+		return "payload=" + url.QueryEscape(string(b))
+	}
+	serve := func(h http.Handler, header http.Header, body string) (int, *bot.ActionResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/bot/slack/actions", strings.NewReader(body))
+		for k := range header {
+			req.Header.Set(k, header.Get(k))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		var res bot.ActionResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&res))
+		return rec.Code, &res
+	}
+
+	t.Run("should return unauthorized if the request is not signed by slack", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := bot.NewSlackHandler(bot.NewSlackSignatureVerifier(secret, 5*time.Minute), users, zap.NewNop(), appealService)
+		body := newBody("U123", domain.AppealActionNameApprove, bot.SlackActionValue(1, "approval_0"))
+
+		unsignedStatus, unsignedResponse := serve(h, http.Header{}, body)
+		staleStatus, _ := serve(h, signSlackRequest(secret, time.Now().Add(-time.Hour), body), body)
+
+		assert.Equal(t, http.StatusUnauthorized, unsignedStatus)
+		assert.Equal(t, bot.ResultFailed, unsignedResponse.Result)
+		assert.Equal(t, http.StatusUnauthorized, staleStatus)
+		appealService.AssertNotCalled(t, "MakeAction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should make the action on behalf of the slack user", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := bot.NewSlackHandler(bot.NewSlackSignatureVerifier(secret, 5*time.Minute), users, zap.NewNop(), appealService)
+		appealService.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1}, nil).Once()
+		appealService.On("MakeAction", mock.Anything, domain.ApprovalAction{
+			AppealID:     1,
+			ApprovalName: "approval:0",
+			Actor:        "approver@email.com",
+			Action:       domain.AppealActionNameReject,
+		}).Return(&domain.Appeal{
+			User:     "user@email.com",
+			Status:   domain.AppealStatusRejected,
+			Resource: &domain.Resource{URN: "urn"},
+		}, nil).Once()
+		body := newBody("U123", domain.AppealActionNameReject, bot.SlackActionValue(1, "approval:0"))
+
+		actualStatus, actualResponse := serve(h, signSlackRequest(secret, time.Now(), body), body)
+
+		assert.Equal(t, http.StatusOK, actualStatus)
+		assert.Equal(t, &bot.ActionResponse{
+			Result:          bot.ResultDone,
+			AppealStatus:    domain.AppealStatusRejected,
+			Text:            "You rejected the appeal from user@email.com to access urn",
+			ResponseType:    "in_channel",
+			ReplaceOriginal: true,
+		}, actualResponse)
+		appealService.AssertExpectations(t)
+	})
+
+	t.Run("should request changes with the comment of the message input", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := bot.NewSlackHandler(bot.NewSlackSignatureVerifier(secret, 5*time.Minute), users, zap.NewNop(), appealService)
+		appealService.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1}, nil).Once()
+		appealService.On("MakeAction", mock.Anything, domain.ApprovalAction{
+			AppealID:     1,
+			ApprovalName: "approval_0",
+			Actor:        "approver@email.com",
+			Action:       domain.AppealActionNameRequestChanges,
+			Comment:      "please link the incident ticket",
+		}).Return(&domain.Appeal{
+			User:     "user@email.com",
+			Status:   domain.AppealStatusPending,
+			Resource: &domain.Resource{URN: "urn"},
+		}, nil).Once()
+		payload, _ := json.Marshal(map[string]interface{}{
+			"type": "block_actions",
+			"user": map[string]string{"id": "U123"},
+			"actions": []map[string]string{
+				{"action_id": domain.AppealActionNameRequestChanges, "value": bot.SlackActionValue(1, "approval_0")},
+			},
+			"state": map[string]interface{}{
+				"values": map[string]interface{}{
+					"comment_block": map[string]interface{}{
+						bot.SlackCommentActionID: map[string]string{"type": "plain_text_input", "value": "please link the incident ticket"},
+					},
+				},
+			},
+		})
+		body := "payload=" + url.QueryEscape(string(payload))
+
+		actualStatus, actualResponse := serve(h, signSlackRequest(secret, time.Now(), body), body)
+
+		assert.Equal(t, http.StatusOK, actualStatus)
+		assert.Equal(t, bot.ResultDone, actualResponse.Result)
+		assert.Equal(t, "You requested changes on the appeal from user@email.com to access urn", actualResponse.Text)
+		appealService.AssertExpectations(t)
+	})
+
+	t.Run("should respond with failed result if the interaction can't be made into an action", func(t *testing.T) {
+		testCases := []struct {
+			name         string
+			body         string
+			expectedText string
+		}{
+			{
+				name:         "unknown action",
+				body:         newBody("U123", "escalate", bot.SlackActionValue(1, "approval_0")),
+				expectedText: "Unknown action, it should be either approve, reject, request_changes, or resubmit",
+			},
+			{
+				name:         "invalid value",
+				body:         newBody("U123", domain.AppealActionNameApprove, "approval_0"),
+				expectedText: "Invalid action value",
+			},
+			{
+				name:         "unknown slack user",
+				body:         newBody("U999", domain.AppealActionNameApprove, bot.SlackActionValue(1, "approval_0")),
+				expectedText: "Unable to identify you, please try again later",
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				appealService := new(mocks.AppealService)
+				h := bot.NewSlackHandler(bot.NewSlackSignatureVerifier(secret, 5*time.Minute), users, zap.NewNop(), appealService)
+
+				actualStatus, actualResponse := serve(h, signSlackRequest(secret, time.Now(), tc.body), tc.body)
+
+				assert.Equal(t, http.StatusOK, actualStatus)
+				assert.Equal(t, bot.ResultFailed, actualResponse.Result)
+				assert.Equal(t, tc.expectedText, actualResponse.Text)
+				appealService.AssertNotCalled(t, "MakeAction", mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
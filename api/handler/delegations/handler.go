@@ -0,0 +1,97 @@
+package delegations
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/delegation"
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+// Path is where the approval delegations are served. The caller lists their delegations, either as the delegator
+// or the delegate, and delegates their approvals by posting to Path
+const Path = "/delegations"
+
+// ActorVerifier verifies the bearer token of the caller, e.g. the OIDC verifier
+type ActorVerifier = auth.ActorOrganizationVerifier
+
+type createRequest struct {
+	Delegate  string    `json:"delegate"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// Handler lets the approvers delegate their approvals for a period of time. The caller is identified by the bearer
+// token if the verifier is set, otherwise by the identity-aware proxy header
+type Handler struct {
+	verifier          ActorVerifier
+	logger            *zap.Logger
+	delegationService domain.ApprovalDelegationService
+}
+
+// NewHandler returns the approval delegations handler
+func NewHandler(verifier ActorVerifier, logger *zap.Logger, delegationService domain.ApprovalDelegationService) *Handler {
+	return &Handler{verifier, logger, delegationService}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, actor, err := auth.AuthenticateRequest(r, h.verifier)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		delegations, err := h.delegationService.GetByUser(ctx, actor)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, delegations)
+	case http.MethodPost:
+		var req createRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		d := &domain.ApprovalDelegation{
+			Delegator: actor,
+			Delegate:  req.Delegate,
+			StartTime: req.StartTime,
+			EndTime:   req.EndTime,
+		}
+		if err := h.delegationService.Create(ctx, d); err != nil {
+			h.writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, d)
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) ||
+		errors.Is(err, delegation.ErrInvalidTimeRange) ||
+		errors.Is(err, delegation.ErrAlreadyEnded) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Error("approval delegation request failed", zap.Error(err))
+	http.Error(w, "something went wrong, please try again later", http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,100 @@
+package delegations_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/api/handler/delegations"
+	"github.com/odpf/guardian/delegation"
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type verifierFunc func(ctx context.Context, token string) (string, string, error)
+
+func (f verifierFunc) VerifyActorOrganization(ctx context.Context, token string) (string, string, error) {
+	return f(ctx, token)
+}
+
+func TestHandler(t *testing.T) {
+	serve := func(h http.Handler, method string, body io.Reader, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, delegations.Path, body)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	proxyHeader := map[string]string{"X-Goog-Authenticated-User-Email": "approver@email.com"}
+
+	t.Run("should return unauthorized if the caller is not identified", func(t *testing.T) {
+		h := delegations.NewHandler(nil, zap.NewNop(), new(mocks.ApprovalDelegationService))
+
+		rec := serve(h, http.MethodGet, nil, nil)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("should return unauthorized if the token is not verified", func(t *testing.T) {
+		verifier := verifierFunc(func(ctx context.Context, token string) (string, string, error) {
+			return "", "", errors.New("invalid token")
+		})
+		h := delegations.NewHandler(verifier, zap.NewNop(), new(mocks.ApprovalDelegationService))
+
+		rec := serve(h, http.MethodGet, nil, map[string]string{"Authorization": "Bearer invalid"})
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("should list the delegations of the caller", func(t *testing.T) {
+		delegationService := new(mocks.ApprovalDelegationService)
+		delegationService.On("GetByUser", mock.Anything, "approver@email.com").Return([]*domain.ApprovalDelegation{
+			{ID: 1, Delegator: "approver@email.com", Delegate: "delegate@email.com"},
+		}, nil).Once()
+		h := delegations.NewHandler(nil, zap.NewNop(), delegationService)
+
+		rec := serve(h, http.MethodGet, nil, proxyHeader)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"delegate":"delegate@email.com"`)
+	})
+
+	t.Run("should delegate the approvals of the caller", func(t *testing.T) {
+		startTime := time.Date(2022, 1, 6, 8, 0, 0, 0, time.UTC)
+		delegationService := new(mocks.ApprovalDelegationService)
+		delegationService.On("Create", mock.Anything, &domain.ApprovalDelegation{
+			Delegator: "approver@email.com",
+			Delegate:  "delegate@email.com",
+			StartTime: startTime,
+			EndTime:   startTime.Add(48 * time.Hour),
+		}).Return(nil).Once()
+		h := delegations.NewHandler(nil, zap.NewNop(), delegationService)
+		body := `{"delegate":"delegate@email.com","start_time":"2022-01-06T08:00:00Z","end_time":"2022-01-08T08:00:00Z"}`
+
+		rec := serve(h, http.MethodPost, strings.NewReader(body), proxyHeader)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		delegationService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request if the delegation is invalid", func(t *testing.T) {
+		delegationService := new(mocks.ApprovalDelegationService)
+		delegationService.On("Create", mock.Anything, mock.Anything).Return(delegation.ErrInvalidTimeRange).Once()
+		h := delegations.NewHandler(nil, zap.NewNop(), delegationService)
+		body := `{"delegate":"delegate@email.com","start_time":"2022-01-08T08:00:00Z","end_time":"2022-01-06T08:00:00Z"}`
+
+		rec := serve(h, http.MethodPost, strings.NewReader(body), proxyHeader)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
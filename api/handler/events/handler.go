@@ -0,0 +1,190 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxAppealIDs is the maximum number of appeals subscribed to by a single stream
+	MaxAppealIDs = 100
+
+	// EventStatus carries an appeal status event
+	EventStatus = "status"
+	// EventReset tells the client some of the events since its last event are lost, to fetch the appeals again
+	EventReset = "reset"
+
+	retryInterval = time.Second
+)
+
+// ActorVerifier verifies the bearer token of the subscriber, e.g. the OIDC verifier
+type ActorVerifier = auth.ActorOrganizationVerifier
+
+// Handler streams the status events of the appeals as server-sent events. A stream lasts up to the stream
+// duration, after which the client reconnects along with the Last-Event-ID header and resumes from there.
+// The subscriber only receives the events of the appeals they requested or are an approver of
+type Handler struct {
+	broker         *appeal.StatusBroker
+	verifier       ActorVerifier
+	appealService  domain.AppealService
+	streamDuration time.Duration
+	logger         *zap.Logger
+}
+
+// NewHandler returns the appeal events handler. The subscriber is identified by the bearer token if the verifier
+// is set, otherwise by the identity-aware proxy header
+func NewHandler(broker *appeal.StatusBroker, verifier ActorVerifier, appealService domain.AppealService, streamDuration time.Duration, logger *zap.Logger) *Handler {
+	return &Handler{broker, verifier, appealService, streamDuration, logger}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, actor, ok := h.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	appealIDs, err := parseAppealIDs(r.URL.Query().Get("appeal_ids"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if appealIDs, err = h.getVisibleAppealIDs(ctx, appealIDs, actor); err != nil {
+		h.logger.Error("failed to get the subscribed appeals", zap.Error(err))
+		http.Error(w, "something went wrong, please try again later", http.StatusInternalServerError)
+		return
+	}
+	if len(appealIDs) == 0 {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sub, complete := h.broker.Subscribe(appealIDs, lastEventID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", retryInterval.Milliseconds())
+	if !complete {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", EventReset)
+	}
+	flusher.Flush()
+
+	timer := time.NewTimer(h.streamDuration)
+	defer timer.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// the client resumes from its last event once it reconnects
+				h.logger.Warn("appeal events subscriber is closed for falling behind", zap.Uints("appeal_ids", appealIDs))
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				h.logger.Error("failed to encode appeal status event", zap.Error(err))
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, EventStatus, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// authenticate returns the request context carrying the verified actor along with their organization if any
+func (h *Handler) authenticate(w http.ResponseWriter, r *http.Request) (context.Context, string, bool) {
+	ctx, actor, err := auth.AuthenticateRequest(r, h.verifier)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil, "", false
+	}
+	return ctx, actor, true
+}
+
+// getVisibleAppealIDs drops the appeals the actor isn't the requester or an approver of. The appeals of another
+// organization aren't found as the context carries the actor's organization
+func (h *Handler) getVisibleAppealIDs(ctx context.Context, appealIDs []uint, actor string) ([]uint, error) {
+	visibleIDs := []uint{}
+	for _, id := range appealIDs {
+		a, err := h.appealService.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if a != nil && (a.User == actor || a.IsApprover(actor)) {
+			visibleIDs = append(visibleIDs, id)
+		}
+	}
+	return visibleIDs, nil
+}
+
+func parseAppealIDs(value string) ([]uint, error) {
+	ids := []uint{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(v, 10, 0)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid appeal id %q", v)
+		}
+		ids = append(ids, uint(id))
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("appeal_ids is required")
+	}
+	if len(ids) > MaxAppealIDs {
+		return nil, fmt.Errorf("at most %d appeal ids can be subscribed to", MaxAppealIDs)
+	}
+	return ids, nil
+}
+
+// parseLastEventID returns the event id the client resumes from, sent by the browsers as the Last-Event-ID header
+// on reconnecting. The last_event_id query param is used if the client can't set the header
+func parseLastEventID(r *http.Request) (uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid last event id %q", value)
+	}
+	return id, nil
+}
@@ -0,0 +1,147 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/api/handler/events"
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type verifierFunc func(ctx context.Context, token string) (string, string, error)
+
+func (f verifierFunc) VerifyActorOrganization(ctx context.Context, token string) (string, string, error) {
+	return f(ctx, token)
+}
+
+func TestHandler(t *testing.T) {
+	serve := func(h http.Handler, target string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	proxyHeader := map[string]string{"X-Goog-Authenticated-User-Email": "requester@email.com"}
+	requestedAppeal := &domain.Appeal{ID: 1, User: "requester@email.com"}
+
+	t.Run("should return bad request if the appeal ids are invalid", func(t *testing.T) {
+		h := events.NewHandler(appeal.NewStatusBroker(0, 0), nil, new(mocks.AppealService), time.Millisecond, zap.NewNop())
+		tooMany := strings.Repeat("1,", events.MaxAppealIDs+1)
+
+		for _, target := range []string{"/events/appeals", "/events/appeals?appeal_ids=a", "/events/appeals?appeal_ids=" + tooMany} {
+			rec := serve(h, target, proxyHeader)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("should return unauthorized if the subscriber is not identified", func(t *testing.T) {
+		h := events.NewHandler(appeal.NewStatusBroker(0, 0), nil, new(mocks.AppealService), time.Millisecond, zap.NewNop())
+
+		rec := serve(h, "/events/appeals?appeal_ids=1", nil)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("should return unauthorized if the token is not verified", func(t *testing.T) {
+		verifier := verifierFunc(func(ctx context.Context, token string) (string, string, error) {
+			return "", "", errors.New("invalid token")
+		})
+		h := events.NewHandler(appeal.NewStatusBroker(0, 0), verifier, new(mocks.AppealService), time.Millisecond, zap.NewNop())
+
+		rec := serve(h, "/events/appeals?appeal_ids=1", map[string]string{"Authorization": "Bearer invalid"})
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("should return forbidden if the subscriber is neither the requester nor an approver of the appeals", func(t *testing.T) {
+		verifier := verifierFunc(func(ctx context.Context, token string) (string, string, error) {
+			return "outsider@email.com", "org-1", nil
+		})
+		broker := appeal.NewStatusBroker(0, 0)
+		appealService := new(mocks.AppealService)
+		appealService.On("GetByID", mock.MatchedBy(func(ctx context.Context) bool {
+			organizationID, _ := auth.OrganizationFromContext(ctx)
+			return organizationID == "org-1"
+		}), uint(1)).Return(requestedAppeal, nil).Once()
+		h := events.NewHandler(broker, verifier, appealService, time.Millisecond, zap.NewNop())
+
+		rec := serve(h, "/events/appeals?appeal_ids=1", map[string]string{"Authorization": "Bearer token"})
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, 0, broker.SubscriberCount())
+		appealService.AssertExpectations(t)
+	})
+
+	t.Run("should only stream the events of the appeals visible to the subscriber", func(t *testing.T) {
+		broker := appeal.NewStatusBroker(0, 0)
+		broker.Publish(domain.AppealStatusEvent{AppealID: 1, Status: domain.AppealStatusPending})
+		broker.Publish(domain.AppealStatusEvent{AppealID: 2, Status: domain.AppealStatusPending})
+		broker.Publish(domain.AppealStatusEvent{AppealID: 3, Status: domain.AppealStatusPending})
+		appealService := new(mocks.AppealService)
+		appealService.On("GetByID", mock.Anything, uint(2)).Return(&domain.Appeal{
+			ID:        2,
+			User:      "user@email.com",
+			Approvals: []*domain.Approval{{Approvers: []string{"requester@email.com"}}},
+		}, nil).Once()
+		appealService.On("GetByID", mock.Anything, uint(3)).Return(&domain.Appeal{ID: 3, User: "user@email.com"}, nil).Once()
+		// the appeals of another organization aren't found
+		appealService.On("GetByID", mock.Anything, uint(4)).Return(nil, nil).Once()
+		h := events.NewHandler(broker, nil, appealService, 10*time.Millisecond, zap.NewNop())
+
+		rec := serve(h, "/events/appeals?appeal_ids=2,3,4&last_event_id=1", proxyHeader)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "retry: 1000\n\n"+
+			`id: 2`+"\nevent: status\n"+
+			`data: {"id":2,"appeal_id":2,"status":"pending","previous_status":"","action":"","actor":"","time":"0001-01-01T00:00:00Z"}`+"\n\n",
+			rec.Body.String())
+	})
+
+	t.Run("should stream the events since the last event id", func(t *testing.T) {
+		broker := appeal.NewStatusBroker(0, 0)
+		broker.Publish(domain.AppealStatusEvent{AppealID: 1, Status: domain.AppealStatusPending})
+		broker.Publish(domain.AppealStatusEvent{AppealID: 2, Status: domain.AppealStatusActive})
+		broker.Publish(domain.AppealStatusEvent{AppealID: 1, Status: domain.AppealStatusActive, PreviousStatus: domain.AppealStatusPending, Action: domain.AppealActionNameApprove})
+		appealService := new(mocks.AppealService)
+		appealService.On("GetByID", mock.Anything, uint(1)).Return(requestedAppeal, nil).Once()
+		h := events.NewHandler(broker, nil, appealService, 10*time.Millisecond, zap.NewNop())
+
+		rec := serve(h, "/events/appeals?appeal_ids=1", map[string]string{
+			"X-Goog-Authenticated-User-Email": "requester@email.com",
+			"Last-Event-ID":                   "1",
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "retry: 1000\n\n"+
+			`id: 3`+"\nevent: status\n"+
+			`data: {"id":3,"appeal_id":1,"status":"active","previous_status":"pending","action":"approve","actor":"","time":"0001-01-01T00:00:00Z"}`+"\n\n",
+			rec.Body.String())
+		assert.Equal(t, 0, broker.SubscriberCount())
+	})
+
+	t.Run("should tell the client to fetch the appeals again if some events are lost", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		appealService.On("GetByID", mock.Anything, uint(1)).Return(requestedAppeal, nil).Once()
+		h := events.NewHandler(appeal.NewStatusBroker(0, 0), nil, appealService, time.Millisecond, zap.NewNop())
+
+		rec := serve(h, "/events/appeals?appeal_ids=1&last_event_id=5", proxyHeader)
+
+		assert.Equal(t, "retry: 1000\n\nevent: reset\ndata: {}\n\n", rec.Body.String())
+	})
+}
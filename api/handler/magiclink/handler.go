@@ -0,0 +1,256 @@
+package magiclink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+// Path is where the magic link dashboard is served
+const Path = "/magic-link"
+
+// Config configures the magic links of the approvers without an account, opening a dashboard scoped to the
+// appeals pending their approval
+type Config struct {
+	// Secret signs the magic link tokens. The dashboard is disabled if it's empty
+	Secret string `mapstructure:"secret"`
+	// TTL is how long a magic link is valid for after it's generated
+	TTL time.Duration `mapstructure:"ttl" default:"15m"`
+	// URL is the base URL of the guardian server the magic links point to, e.g. https://guardian.example.com
+	URL string `mapstructure:"url"`
+}
+
+// GetLink returns the magic link of the token
+func (c Config) GetLink(token string) string {
+	return strings.TrimSuffix(c.URL, "/") + Path + "?" + url.Values{"token": {token}}.Encode()
+}
+
+// Handler serves the approval dashboard of the magic links. The dashboard lists the appeals of the session still
+// pending the approver, and the approver can approve or reject them only, as themselves. Every action made through
+// the dashboard is logged along with the session
+type Handler struct {
+	signer        *Signer
+	logger        *zap.Logger
+	appealService domain.AppealService
+}
+
+// NewHandler returns the magic link dashboard handler
+func NewHandler(signer *Signer, logger *zap.Logger, appealService domain.AppealService) *Handler {
+	return &Handler{signer, logger, appealService}
+}
+
+type dashboard struct {
+	Token     string
+	Approver  string
+	ExpiresAt time.Time
+	Message   string
+	Appeals   []*domain.Appeal
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the token is part of the url, it mustn't be cached nor leaked to other sites
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	switch r.Method {
+	case http.MethodGet:
+		session, token, ok := h.getSession(w, r.URL.Query().Get("token"))
+		if !ok {
+			return
+		}
+		h.render(r.Context(), w, session, token, "")
+	case http.MethodPost:
+		session, token, ok := h.getSession(w, r.PostFormValue("token"))
+		if !ok {
+			return
+		}
+		appealID, err := strconv.ParseUint(r.PostFormValue("appeal_id"), 10, 32)
+		if err != nil {
+			http.Error(w, "invalid appeal id", http.StatusBadRequest)
+			return
+		}
+		if !session.HasAppeal(uint(appealID)) {
+			h.logger.Warn("magic link action out of the session scope",
+				zap.String("approver", session.Approver),
+				zap.Uint64("appeal_id", appealID),
+			)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		message := h.makeAction(r.Context(), session, domain.ApprovalAction{
+			AppealID:     uint(appealID),
+			ApprovalName: r.PostFormValue("approval_name"),
+			Actor:        session.Approver,
+			Action:       r.PostFormValue("action"),
+		})
+		h.render(r.Context(), w, session, token, message)
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) getSession(w http.ResponseWriter, token string) (*Session, string, bool) {
+	session, err := h.signer.Verify(token)
+	if err != nil {
+		if errors.Is(err, ErrExpiredToken) {
+			http.Error(w, "the link has expired, please ask for a new one", http.StatusUnauthorized)
+		} else {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		}
+		return nil, "", false
+	}
+	return session, token, true
+}
+
+// makeAction makes the action as the approver within the organization of the appeal, the magic links aren't bound
+// to any
+func (h *Handler) makeAction(ctx context.Context, session *Session, action domain.ApprovalAction) string {
+	fields := []zap.Field{
+		zap.String("approver", session.Approver),
+		zap.Uints("session_appeal_ids", session.AppealIDs),
+		zap.Time("session_expires_at", time.Unix(session.ExpiresAt, 0)),
+		zap.Uint("appeal_id", action.AppealID),
+		zap.String("approval_name", action.ApprovalName),
+		zap.String("action", action.Action),
+	}
+
+	current, err := h.appealService.GetByID(ctx, action.AppealID)
+	if err != nil {
+		h.logger.Warn("magic link action failed", append(fields, zap.Error(err))...)
+		return getFailedMessage(err)
+	}
+	if current == nil {
+		h.logger.Warn("magic link action failed", append(fields, zap.String("error", "appeal not found"))...)
+		return "The appeal is not found"
+	}
+	ctx = auth.NewContext(ctx, action.Actor)
+	if current.OrganizationID != "" {
+		ctx = auth.NewOrganizationContext(ctx, current.OrganizationID)
+	}
+
+	a, err := h.appealService.MakeAction(ctx, action)
+	if err != nil {
+		h.logger.Warn("magic link action failed", append(fields, zap.Error(err))...)
+		return getFailedMessage(err)
+	}
+	if a == nil {
+		h.logger.Warn("magic link action failed", append(fields, zap.String("error", "appeal not found"))...)
+		return "The appeal is not found"
+	}
+
+	h.logger.Info("magic link action made", append(fields, zap.String("appeal_status", a.Status))...)
+	pastTense := "approved"
+	if action.Action == domain.AppealActionNameReject {
+		pastTense = "rejected"
+	}
+	return fmt.Sprintf("You %s appeal #%d from %s", pastTense, a.ID, a.User)
+}
+
+// render writes the dashboard of the session's appeals still pending the approver
+func (h *Handler) render(ctx context.Context, w http.ResponseWriter, session *Session, token, message string) {
+	pendingAppeals, err := h.appealService.GetPendingForApprover(ctx, session.Approver)
+	if err != nil {
+		h.logger.Error("failed to get the pending appeals of the magic link", zap.String("approver", session.Approver), zap.Error(err))
+		http.Error(w, "something went wrong, please try again later", http.StatusInternalServerError)
+		return
+	}
+	appeals := []*domain.Appeal{}
+	for _, a := range pendingAppeals {
+		if session.HasAppeal(a.ID) {
+			appeals = append(appeals, a)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTemplate.Execute(w, &dashboard{
+		Token:     token,
+		Approver:  session.Approver,
+		ExpiresAt: time.Unix(session.ExpiresAt, 0).UTC(),
+		Message:   message,
+		Appeals:   appeals,
+	}); err != nil {
+		h.logger.Error("failed to render the magic link dashboard", zap.Error(err))
+	}
+}
+
+func getFailedMessage(err error) string {
+	messages := []struct {
+		err     error
+		message string
+	}{
+		{appeal.ErrActionForbidden, "You're not an approver of this approval step"},
+		{appeal.ErrActorMismatch, "You're not an approver of this approval step"},
+		{appeal.ErrActionInvalidValue, "Unknown action, it should be either approve or reject"},
+		{appeal.ErrAppealNotFound, "The appeal is not found"},
+		{appeal.ErrApprovalNameNotFound, "The approval step is not found"},
+		{appeal.ErrApprovalDependencyIsPending, "The previous approval steps are still pending"},
+		{appeal.ErrApprovalStatusApproved, "The approval step has already been approved"},
+		{appeal.ErrApprovalStatusRejected, "The approval step has already been rejected"},
+		{appeal.ErrApprovalStatusSkipped, "The approval step has been skipped"},
+		{appeal.ErrAppealStatusApproved, "The appeal has already been approved"},
+		{appeal.ErrAppealStatusRejected, "The appeal has already been rejected"},
+		{appeal.ErrAppealStatusCanceled, "The appeal has been canceled"},
+		{appeal.ErrAppealStatusTerminated, "The access has already been revoked"},
+	}
+	for _, m := range messages {
+		if errors.Is(err, m.err) {
+			return m.message
+		}
+	}
+	return "Something went wrong, please try again later"
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Guardian - pending approvals</title>
+</head>
+<body>
+<h1>Appeals pending your approval</h1>
+<p>Signed in as {{.Approver}} until {{.ExpiresAt.Format "2006-01-02 15:04 MST"}}</p>
+{{if .Message}}<p><strong>{{.Message}}</strong></p>{{end}}
+{{if .Appeals}}
+<table>
+<tr><th>ID</th><th>Priority</th><th>User</th><th>Resource</th><th>Role</th><th>Approval step</th><th>Created at</th><th></th></tr>
+{{range .Appeals}}{{$approval := .GetNextPendingApproval}}
+<tr>
+<td>{{.ID}}</td>
+<td>{{.Priority}}</td>
+<td>{{.User}}</td>
+<td>{{if .Resource}}{{.Resource.URN}}{{else}}{{.ResourceID}}{{end}}</td>
+<td>{{.Role}}</td>
+<td>{{$approval.Name}}</td>
+<td>{{.CreatedAt.Format "2006-01-02 15:04 MST"}}</td>
+<td>
+<form method="post">
+<input type="hidden" name="token" value="{{$.Token}}">
+<input type="hidden" name="appeal_id" value="{{.ID}}">
+<input type="hidden" name="approval_name" value="{{$approval.Name}}">
+<button type="submit" name="action" value="approve">Approve</button>
+<button type="submit" name="action" value="reject">Reject</button>
+</form>
+</td>
+</tr>
+{{end}}
+</table>
+{{else}}
+<p>There is no appeal pending your approval.</p>
+{{end}}
+</body>
+</html>
+`))
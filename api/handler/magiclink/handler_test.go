@@ -0,0 +1,139 @@
+package magiclink_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/api/handler/magiclink"
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestHandler(t *testing.T) {
+	approver := "approver@email.com"
+	signer := magiclink.NewSigner("secret", 15*time.Minute)
+	token, _, err := signer.Sign(approver, []uint{1})
+	assert.Nil(t, err)
+	pendingAppeals := []*domain.Appeal{
+		{
+			ID:       1,
+			User:     "user@email.com",
+			Role:     "viewer",
+			Resource: &domain.Resource{URN: "urn-1"},
+			Approvals: []*domain.Approval{
+				{Name: "approval_0", Status: domain.ApprovalStatusPending, Approvers: []string{approver}},
+			},
+		},
+		{
+			ID:       2,
+			User:     "other-user@email.com",
+			Role:     "viewer",
+			Resource: &domain.Resource{URN: "urn-2"},
+			Approvals: []*domain.Approval{
+				{Name: "approval_0", Status: domain.ApprovalStatusPending, Approvers: []string{approver}},
+			},
+		},
+	}
+	post := func(h http.Handler, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, magiclink.Path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("should return unauthorized if the token is invalid", func(t *testing.T) {
+		h := magiclink.NewHandler(signer, zap.NewNop(), new(mocks.AppealService))
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, magiclink.Path+"?token=invalid", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("should list the pending appeals of the session only", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := magiclink.NewHandler(signer, zap.NewNop(), appealService)
+		appealService.On("GetPendingForApprover", mock.Anything, approver).Return(pendingAppeals, nil).Once()
+		rec := httptest.NewRecorder()
+
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, magiclink.Path+"?token="+token, nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		assert.Contains(t, rec.Body.String(), "urn-1")
+		assert.NotContains(t, rec.Body.String(), "urn-2")
+	})
+
+	t.Run("should make the action as the approver of the session within the organization of the appeal", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := magiclink.NewHandler(signer, zap.NewNop(), appealService)
+		appealService.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1, OrganizationID: "org-a"}, nil).Once()
+		appealService.On("MakeAction", mock.MatchedBy(func(ctx context.Context) bool {
+			actor, _ := auth.ActorFromContext(ctx)
+			organizationID, _ := auth.OrganizationFromContext(ctx)
+			return actor == approver && organizationID == "org-a"
+		}), domain.ApprovalAction{
+			AppealID:     1,
+			ApprovalName: "approval_0",
+			Actor:        approver,
+			Action:       domain.AppealActionNameApprove,
+		}).Return(&domain.Appeal{ID: 1, User: "user@email.com", Status: domain.AppealStatusActive}, nil).Once()
+		appealService.On("GetPendingForApprover", mock.Anything, approver).Return([]*domain.Appeal{}, nil).Once()
+
+		rec := post(h, url.Values{
+			"token":         {token},
+			"appeal_id":     {"1"},
+			"approval_name": {"approval_0"},
+			"action":        {domain.AppealActionNameApprove},
+			"actor":         {"another-approver@email.com"},
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "You approved appeal #1 from user@email.com")
+		appealService.AssertExpectations(t)
+	})
+
+	t.Run("should show the reason if the action fails", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := magiclink.NewHandler(signer, zap.NewNop(), appealService)
+		appealService.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1}, nil).Once()
+		appealService.On("MakeAction", mock.Anything, mock.Anything).Return(nil, appeal.ErrAppealStatusApproved).Once()
+		appealService.On("GetPendingForApprover", mock.Anything, approver).Return([]*domain.Appeal{}, nil).Once()
+
+		rec := post(h, url.Values{
+			"token":         {token},
+			"appeal_id":     {"1"},
+			"approval_name": {"approval_0"},
+			"action":        {domain.AppealActionNameApprove},
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "The appeal has already been approved")
+	})
+
+	t.Run("should return forbidden if the appeal is out of the session scope", func(t *testing.T) {
+		appealService := new(mocks.AppealService)
+		h := magiclink.NewHandler(signer, zap.NewNop(), appealService)
+
+		rec := post(h, url.Values{
+			"token":         {token},
+			"appeal_id":     {"2"},
+			"approval_name": {"approval_0"},
+			"action":        {domain.AppealActionNameApprove},
+		})
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		appealService.AssertNotCalled(t, "MakeAction", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,101 @@
+package magiclink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is the error value if the token is malformed or its signature doesn't match
+	ErrInvalidToken = errors.New("invalid magic link token")
+	// ErrExpiredToken is the error value if the token is used after its expiry
+	ErrExpiredToken = errors.New("magic link expired")
+	// ErrEmptyScope is the error value if the token is requested for no appeal
+	ErrEmptyScope = errors.New("magic link requires at least one appeal")
+)
+
+// Session is the approver and the appeals a magic link is scoped to
+type Session struct {
+	Approver  string `json:"approver"`
+	AppealIDs []uint `json:"appeal_ids"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// HasAppeal returns true if the appeal is in the scope of the session
+func (s *Session) HasAppeal(id uint) bool {
+	for _, appealID := range s.AppealIDs {
+		if appealID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Signer signs and verifies the magic link tokens. A token is the base64-encoded session along with its
+// HMAC-SHA256 signature, so it's validated without being stored
+type Signer struct {
+	Secret  string
+	TTL     time.Duration
+	TimeNow func() time.Time
+}
+
+// NewSigner returns the signer of the magic link tokens expiring after the ttl
+func NewSigner(secret string, ttl time.Duration) *Signer {
+	return &Signer{secret, ttl, time.Now}
+}
+
+// Sign returns the token of the approver scoped to the appeals, along with its expiry
+func (s *Signer) Sign(approver string, appealIDs []uint) (string, time.Time, error) {
+	if len(appealIDs) == 0 {
+		return "", time.Time{}, ErrEmptyScope
+	}
+
+	expiresAt := s.TimeNow().Add(s.TTL)
+	payload, err := json.Marshal(&Session{
+		Approver:  approver,
+		AppealIDs: appealIDs,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(s.sign(encodedPayload)), expiresAt, nil
+}
+
+// Verify returns the session of the token if the signature matches and the token hasn't expired
+func (s *Signer) Verify(token string) (*Session, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, s.sign(parts[0])) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var session Session
+	if err := json.Unmarshal(payload, &session); err != nil || session.Approver == "" || len(session.AppealIDs) == 0 {
+		return nil, ErrInvalidToken
+	}
+	if !s.TimeNow().Before(time.Unix(session.ExpiresAt, 0)) {
+		return nil, ErrExpiredToken
+	}
+	return &session, nil
+}
+
+func (s *Signer) sign(encodedPayload string) []byte {
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write([]byte(encodedPayload))
+	return mac.Sum(nil)
+}
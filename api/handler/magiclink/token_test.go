@@ -0,0 +1,68 @@
+package magiclink_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/api/handler/magiclink"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	newSigner := func(secret string, at time.Time) *magiclink.Signer {
+		s := magiclink.NewSigner(secret, 15*time.Minute)
+		s.TimeNow = func() time.Time { return at }
+		return s
+	}
+
+	t.Run("should return the session of a valid token", func(t *testing.T) {
+		token, expiresAt, err := newSigner("secret", now).Sign("approver@email.com", []uint{1, 2})
+		assert.Nil(t, err)
+		assert.Equal(t, now.Add(15*time.Minute), expiresAt)
+
+		actualSession, actualError := newSigner("secret", now.Add(14*time.Minute)).Verify(token)
+
+		assert.Nil(t, actualError)
+		assert.Equal(t, &magiclink.Session{
+			Approver:  "approver@email.com",
+			AppealIDs: []uint{1, 2},
+			ExpiresAt: expiresAt.Unix(),
+		}, actualSession)
+		assert.True(t, actualSession.HasAppeal(2))
+		assert.False(t, actualSession.HasAppeal(3))
+	})
+
+	t.Run("should return error if the token has expired", func(t *testing.T) {
+		token, _, err := newSigner("secret", now).Sign("approver@email.com", []uint{1})
+		assert.Nil(t, err)
+
+		_, actualError := newSigner("secret", now.Add(15*time.Minute)).Verify(token)
+
+		assert.ErrorIs(t, actualError, magiclink.ErrExpiredToken)
+	})
+
+	t.Run("should return error if the token is signed by another secret or tampered", func(t *testing.T) {
+		token, _, err := newSigner("another-secret", now).Sign("approver@email.com", []uint{1})
+		assert.Nil(t, err)
+		validToken, _, err := newSigner("secret", now).Sign("approver@email.com", []uint{1})
+		assert.Nil(t, err)
+		otherToken, _, err := newSigner("secret", now).Sign("approver@email.com", []uint{1, 2})
+		assert.Nil(t, err)
+		// the payload of one token along with the signature of another
+		tamperedToken := strings.Split(otherToken, ".")[0] + "." + strings.Split(validToken, ".")[1]
+
+		for _, token := range []string{token, tamperedToken, "", "invalid", "a.b.c"} {
+			_, actualError := newSigner("secret", now).Verify(token)
+
+			assert.ErrorIs(t, actualError, magiclink.ErrInvalidToken)
+		}
+	})
+
+	t.Run("should return error if the token isn't scoped to any appeal", func(t *testing.T) {
+		_, _, actualError := newSigner("secret", now).Sign("approver@email.com", nil)
+
+		assert.ErrorIs(t, actualError, magiclink.ErrEmptyScope)
+	})
+}
@@ -11,7 +11,16 @@ import (
 )
 
 type resourceOptions struct {
-	Duration string `json:"duration"`
+	Duration     string               `json:"duration"`
+	StartDate    string               `mapstructure:"start_date"`
+	AccessWindow *accessWindowOptions `mapstructure:"access_window"`
+}
+
+type accessWindowOptions struct {
+	Days      []string `mapstructure:"days"`
+	StartTime string   `mapstructure:"start_time"`
+	EndTime   string   `mapstructure:"end_time"`
+	Timezone  string   `mapstructure:"timezone"`
 }
 
 type adapter struct{}
@@ -97,6 +106,16 @@ func (a *adapter) ToProviderProto(p *domain.Provider) (*pb.Provider, error) {
 	}, nil
 }
 
+func (a *adapter) ToProviderCapabilitiesProto(c *domain.ProviderCapabilities) *pb.ProviderCapabilities {
+	return &pb.ProviderCapabilities{
+		BatchGrant:         c.BatchGrant,
+		AccessListing:      c.AccessListing,
+		AccessVerification: c.AccessVerification,
+		PermanentAccess:    c.PermanentAccess,
+		Revocation:         c.Revocation,
+	}
+}
+
 func (a *adapter) ToProviderConfigProto(pc *domain.ProviderConfig) (*pb.ProviderConfig, error) {
 	credentials, err := structpb.NewValue(pc.Credentials)
 	if err != nil {
@@ -311,6 +330,7 @@ func (a *adapter) FromAppealProto(appeal *pb.Appeal) (*domain.Appeal, error) {
 		RevokedBy:     appeal.GetRevokedBy(),
 		RevokedAt:     appeal.GetRevokedAt().AsTime(),
 		RevokeReason:  appeal.GetRevokeReason(),
+		Priority:      appeal.GetPriority(),
 		Resource:      resource,
 		Approvals:     approvals,
 		CreatedAt:     appeal.GetCreatedAt().AsTime(),
@@ -359,6 +379,7 @@ func (a *adapter) ToAppealProto(appeal *domain.Appeal) (*pb.Appeal, error) {
 		RevokedBy:     appeal.RevokedBy,
 		RevokedAt:     timestamppb.New(appeal.RevokedAt),
 		RevokeReason:  appeal.RevokeReason,
+		Priority:      appeal.Priority,
 		Resource:      resource,
 		Approvals:     approvals,
 		CreatedAt:     timestamppb.New(appeal.CreatedAt),
@@ -377,6 +398,16 @@ func (a *adapter) FromCreateAppealProto(ca *pb.CreateAppealRequest) ([]*domain.A
 			return nil, err
 		}
 
+		startTime := time.Now()
+		if resOptions.StartDate != "" {
+			startDate, err := time.Parse(time.RFC3339, resOptions.StartDate)
+			if err != nil {
+				return nil, err
+			}
+			options.StartDate = &startDate
+			startTime = startDate
+		}
+
 		var expirationDate time.Time
 		if r.GetOptions() != nil {
 			if resOptions.Duration != "" {
@@ -384,16 +415,25 @@ func (a *adapter) FromCreateAppealProto(ca *pb.CreateAppealRequest) ([]*domain.A
 				if err != nil {
 					return nil, err
 				}
-				expirationDate = time.Now().Add(duration)
+				expirationDate = startTime.Add(duration)
 			}
 		}
 		options.ExpirationDate = &expirationDate
+		if resOptions.AccessWindow != nil {
+			options.AccessWindow = &domain.AccessWindow{
+				Days:      resOptions.AccessWindow.Days,
+				StartTime: resOptions.AccessWindow.StartTime,
+				EndTime:   resOptions.AccessWindow.EndTime,
+				Timezone:  resOptions.AccessWindow.Timezone,
+			}
+		}
 
 		appeals = append(appeals, &domain.Appeal{
 			User:       ca.GetUser(),
 			ResourceID: uint(r.GetId()),
 			Role:       r.GetRole(),
 			Options:    &options,
+			Priority:   ca.GetPriority(),
 		})
 	}
 
@@ -3,13 +3,18 @@ package v1
 import (
 	"context"
 	"errors"
+	"math"
+	"strconv"
 	"time"
 
 	pb "github.com/odpf/guardian/api/proto/odpf/guardian"
 	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/auth"
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/policy"
+	"github.com/odpf/guardian/provider"
 	"github.com/odpf/guardian/resource"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
@@ -20,6 +25,7 @@ type ProtoAdapter interface {
 	FromProviderConfigProto(*pb.ProviderConfig) (*domain.ProviderConfig, error)
 	ToProviderProto(*domain.Provider) (*pb.Provider, error)
 	ToProviderConfigProto(*domain.ProviderConfig) (*pb.ProviderConfig, error)
+	ToProviderCapabilitiesProto(*domain.ProviderCapabilities) *pb.ProviderCapabilities
 
 	FromPolicyProto(*pb.Policy) (*domain.Policy, error)
 	ToPolicyProto(*domain.Policy) (*pb.Policy, error)
@@ -65,14 +71,14 @@ func NewGRPCServer(
 }
 
 func (s *GRPCServer) ListProviders(ctx context.Context, req *pb.ListProvidersRequest) (*pb.ListProvidersResponse, error) {
-	providers, err := s.providerService.Find()
+	providers, err := s.providerService.Find(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	providerProtos := []*pb.Provider{}
 	for _, p := range providers {
-		providerProto, err := s.adapter.ToProviderProto(p)
+		providerProto, err := s.toProviderProto(p)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "%s: failed to parse provider %s", err.Error(), p.URN)
 		}
@@ -95,11 +101,11 @@ func (s *GRPCServer) CreateProvider(ctx context.Context, req *pb.CreateProviderR
 		URN:    providerConfig.URN,
 		Config: providerConfig,
 	}
-	if err := s.providerService.Create(p); err != nil {
+	if err := s.providerService.Create(ctx, p); err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to create provider", err)
 	}
 
-	providerProto, err := s.adapter.ToProviderProto(p)
+	providerProto, err := s.toProviderProto(p)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to parse provider", err)
 	}
@@ -122,11 +128,14 @@ func (s *GRPCServer) UpdateProvider(ctx context.Context, req *pb.UpdateProviderR
 		URN:    providerConfig.URN,
 		Config: providerConfig,
 	}
-	if err := s.providerService.Update(p); err != nil {
+	if err := s.providerService.Update(ctx, p); err != nil {
+		if errors.Is(err, provider.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, "provider not found")
+		}
 		return nil, status.Errorf(codes.Internal, "%s: failed to update provider", err)
 	}
 
-	providerProto, err := s.adapter.ToProviderProto(p)
+	providerProto, err := s.toProviderProto(p)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to parse provider", err)
 	}
@@ -136,8 +145,25 @@ func (s *GRPCServer) UpdateProvider(ctx context.Context, req *pb.UpdateProviderR
 	}, nil
 }
 
+// toProviderProto returns the provider along with the operations supported by its type, so the clients can leave
+// out the flows the provider doesn't support
+func (s *GRPCServer) toProviderProto(p *domain.Provider) (*pb.Provider, error) {
+	providerProto, err := s.adapter.ToProviderProto(p)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities, err := s.providerService.GetCapabilities(p.Type)
+	if err != nil {
+		return nil, err
+	}
+	providerProto.Capabilities = s.adapter.ToProviderCapabilitiesProto(capabilities)
+
+	return providerProto, nil
+}
+
 func (s *GRPCServer) ListPolicies(ctx context.Context, req *pb.ListPoliciesRequest) (*pb.ListPoliciesResponse, error) {
-	policies, err := s.policyService.Find()
+	policies, err := s.policyService.Find(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to get policy list", err)
 	}
@@ -157,16 +183,19 @@ func (s *GRPCServer) ListPolicies(ctx context.Context, req *pb.ListPoliciesReque
 }
 
 func (s *GRPCServer) CreatePolicy(ctx context.Context, req *pb.CreatePolicyRequest) (*pb.CreatePolicyResponse, error) {
-	policy, err := s.adapter.FromPolicyProto(req.GetPolicy())
+	p, err := s.adapter.FromPolicyProto(req.GetPolicy())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: cannot deserialize policy", err)
 	}
 
-	if err := s.policyService.Create(policy); err != nil {
+	if err := s.policyService.Create(ctx, p); err != nil {
+		if errors.Is(err, policy.ErrPolicyVersionInUse) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return nil, status.Errorf(codes.Internal, "%s: failed to create policy", err)
 	}
 
-	policyProto, err := s.adapter.ToPolicyProto(policy)
+	policyProto, err := s.adapter.ToPolicyProto(p)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to parse policy", err)
 	}
@@ -183,11 +212,13 @@ func (s *GRPCServer) UpdatePolicy(ctx context.Context, req *pb.UpdatePolicyReque
 	}
 
 	p.ID = req.GetId()
-	if err := s.policyService.Update(p); err != nil {
+	if err := s.policyService.Update(ctx, p); err != nil {
 		if errors.Is(err, policy.ErrPolicyDoesNotExists) {
 			return nil, status.Error(codes.NotFound, "policy id not found")
 		} else if errors.Is(err, policy.ErrEmptyIDParam) {
 			return nil, status.Error(codes.InvalidArgument, err.Error())
+		} else if errors.Is(err, policy.ErrPolicyVersionInUse) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		}
 
 		return nil, status.Errorf(codes.Internal, "%s: failed to update policy", err)
@@ -204,7 +235,11 @@ func (s *GRPCServer) UpdatePolicy(ctx context.Context, req *pb.UpdatePolicyReque
 }
 
 func (s *GRPCServer) ListResources(ctx context.Context, req *pb.ListResourcesRequest) (*pb.ListResourcesResponse, error) {
-	resources, err := s.resourceService.Find(map[string]interface{}{})
+	filters := map[string]interface{}{}
+	if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+		filters["organization_id"] = organizationID
+	}
+	resources, err := s.resourceService.Find(ctx, filters)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to get resource list", err)
 	}
@@ -227,7 +262,19 @@ func (s *GRPCServer) UpdateResource(ctx context.Context, req *pb.UpdateResourceR
 	r := s.adapter.FromResourceProto(req.GetResource())
 	r.ID = uint(req.GetId())
 
-	if err := s.resourceService.Update(r); err != nil {
+	if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+		resources, err := s.resourceService.Find(ctx, map[string]interface{}{
+			"ids":             []uint{r.ID},
+			"organization_id": organizationID,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "%s: failed to get resource", err)
+		}
+		if len(resources) == 0 {
+			return nil, status.Error(codes.NotFound, "resource not found")
+		}
+	}
+	if err := s.resourceService.Update(ctx, r); err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to update resource", err)
 	}
 
@@ -250,7 +297,7 @@ func (s *GRPCServer) ListAppeals(ctx context.Context, req *pb.ListAppealsRequest
 		filters["user"] = req.GetUser()
 	}
 
-	appeals, err := s.appealService.Find(filters)
+	appeals, err := s.appealService.Find(ctx, filters)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to get appeal list", err)
 	}
@@ -275,10 +322,25 @@ func (s *GRPCServer) CreateAppeal(ctx context.Context, req *pb.CreateAppealReque
 		return nil, status.Errorf(codes.Internal, "%s: cannot deserialize payload", err)
 	}
 
-	if err := s.appealService.Create(appeals); err != nil {
+	if err := s.appealService.Create(ctx, appeals); err != nil {
 		if errors.Is(err, appeal.ErrAppealDuplicate) {
 			return nil, status.Errorf(codes.AlreadyExists, "%s: appeal already exists", err)
 		}
+		var rateLimitErr *appeal.RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			retryAfter := int64(math.Ceil(rateLimitErr.RetryAfter.Seconds()))
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.FormatInt(retryAfter, 10)))
+			return nil, status.Errorf(codes.ResourceExhausted, "%s: failed to create appeal", err)
+		}
+		if errors.Is(err, appeal.ErrResourceNotAppealable) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s: failed to create appeal", err)
+		}
+		if errors.Is(err, appeal.ErrResourceNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s: failed to create appeal", err)
+		}
+		if errors.Is(err, appeal.ErrInvalidRole) || errors.Is(err, appeal.ErrDurationExceedsRoleMax) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: failed to create appeal", err)
+		}
 		return nil, status.Errorf(codes.Internal, "%s: failed to create appeal", err)
 	}
 
@@ -297,7 +359,7 @@ func (s *GRPCServer) CreateAppeal(ctx context.Context, req *pb.CreateAppealReque
 }
 
 func (s *GRPCServer) ListApprovals(ctx context.Context, req *pb.ListApprovalsRequest) (*pb.ListApprovalsResponse, error) {
-	approvals, err := s.approvalService.ListApprovals(&domain.ListApprovalsFilter{
+	approvals, err := s.approvalService.ListApprovals(ctx, &domain.ListApprovalsFilter{
 		User:     req.GetUser(),
 		Statuses: req.GetStatuses(),
 	})
@@ -321,7 +383,7 @@ func (s *GRPCServer) ListApprovals(ctx context.Context, req *pb.ListApprovalsReq
 
 func (s *GRPCServer) GetAppeal(ctx context.Context, req *pb.GetAppealRequest) (*pb.GetAppealResponse, error) {
 	id := req.GetId()
-	appeal, err := s.appealService.GetByID(uint(id))
+	appeal, err := s.appealService.GetByID(ctx, uint(id))
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to retrieve appeal", err)
 	}
@@ -346,7 +408,7 @@ func (s *GRPCServer) UpdateApproval(ctx context.Context, req *pb.UpdateApprovalR
 	}
 
 	id := req.GetId()
-	a, err := s.appealService.MakeAction(domain.ApprovalAction{
+	a, err := s.appealService.MakeAction(ctx, domain.ApprovalAction{
 		AppealID:     uint(id),
 		ApprovalName: req.GetApprovalName(),
 		Actor:        actor,
@@ -365,9 +427,10 @@ func (s *GRPCServer) UpdateApproval(ctx context.Context, req *pb.UpdateApprovalR
 			appeal.ErrApprovalStatusApproved,
 			appeal.ErrApprovalStatusRejected,
 			appeal.ErrApprovalStatusSkipped,
-			appeal.ErrActionInvalidValue:
+			appeal.ErrActionInvalidValue,
+			appeal.ErrChangesCommentRequired:
 			return nil, status.Errorf(codes.InvalidArgument, "unable to process the request: %s", err)
-		case appeal.ErrActionForbidden:
+		case appeal.ErrActionForbidden, appeal.ErrActorMismatch:
 			return nil, status.Error(codes.PermissionDenied, "permission denied")
 		case appeal.ErrApprovalNameNotFound:
 			return nil, status.Errorf(codes.NotFound, "appeal not found: %v", id)
@@ -388,7 +451,12 @@ func (s *GRPCServer) UpdateApproval(ctx context.Context, req *pb.UpdateApprovalR
 
 func (s *GRPCServer) CancelAppeal(ctx context.Context, req *pb.CancelAppealRequest) (*pb.CancelAppealResponse, error) {
 	id := req.GetId()
-	a, err := s.appealService.Cancel(uint(id))
+	actor, err := s.getActor(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get metadata: actor")
+	}
+
+	a, err := s.appealService.Cancel(ctx, uint(id), actor)
 	if err != nil {
 		switch err {
 		case appeal.ErrAppealStatusCanceled,
@@ -420,11 +488,13 @@ func (s *GRPCServer) RevokeAppeal(ctx context.Context, req *pb.RevokeAppealReque
 	}
 	reason := req.GetReason().GetReason()
 
-	a, err := s.appealService.Revoke(uint(id), actor, reason)
+	a, err := s.appealService.Revoke(ctx, uint(id), actor, reason)
 	if err != nil {
-		switch err {
-		case appeal.ErrAppealNotFound:
+		switch {
+		case err == appeal.ErrAppealNotFound:
 			return nil, status.Errorf(codes.NotFound, "appeal not found: %v", id)
+		case errors.Is(err, appeal.ErrAppealNotActive):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		default:
 			return nil, status.Errorf(codes.Internal, "%s: failed to cancel appeal", err)
 		}
@@ -440,7 +510,11 @@ func (s *GRPCServer) RevokeAppeal(ctx context.Context, req *pb.RevokeAppealReque
 	}, nil
 }
 
+// getActor prefers the actor verified from the request token over the identity-aware proxy header
 func (s *GRPCServer) getActor(ctx context.Context) (string, error) {
+	if actor, ok := auth.ActorFromContext(ctx); ok {
+		return actor, nil
+	}
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
 		if userEmail, ok := md["x-goog-authenticated-user-email"]; ok {
 			return userEmail[0], nil
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,14 +10,23 @@ import (
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/odpf/guardian/api/handler/attachments"
+	"github.com/odpf/guardian/api/handler/bot"
+	"github.com/odpf/guardian/api/handler/delegations"
+	"github.com/odpf/guardian/api/handler/events"
+	"github.com/odpf/guardian/api/handler/magiclink"
 	v1 "github.com/odpf/guardian/api/handler/v1"
 	pb "github.com/odpf/guardian/api/proto/odpf/guardian"
 	"github.com/odpf/guardian/appeal"
 	"github.com/odpf/guardian/approval"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/blob"
 	"github.com/odpf/guardian/crypto"
+	"github.com/odpf/guardian/delegation"
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/iam"
 	"github.com/odpf/guardian/logger"
+	"github.com/odpf/guardian/metrics"
 	"github.com/odpf/guardian/model"
 	"github.com/odpf/guardian/notifier"
 	"github.com/odpf/guardian/policy"
@@ -28,9 +38,11 @@ import (
 	"github.com/odpf/guardian/resource"
 	"github.com/odpf/guardian/scheduler"
 	"github.com/odpf/guardian/store"
+	"go.uber.org/zap"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"gorm.io/gorm"
 )
 
@@ -39,13 +51,31 @@ var (
 	ServiceConfigFileExtension = "yaml"
 )
 
+const serverWriteTimeout = 10 * time.Second
+
 type ServiceConfig struct {
-	Port                   int              `mapstructure:"port" default:"8080"`
-	EncryptionSecretKeyKey string           `mapstructure:"encryption_secret_key"`
-	SlackAccessToken       string           `mapstructure:"slack_access_token"`
-	IAM                    iam.ClientConfig `mapstructure:"iam"`
-	Log                    logger.Config    `mapstructure:"log"`
-	DB                     store.Config     `mapstructure:"db"`
+	Port                       int                         `mapstructure:"port" default:"8080"`
+	EncryptionSecretKeyKey     string                      `mapstructure:"encryption_secret_key"`
+	SigningSecretKey           string                      `mapstructure:"signing_secret_key"`
+	SlackAccessToken           string                      `mapstructure:"slack_access_token"`
+	NotificationDefaultChannel string                      `mapstructure:"notification_default_channel" default:"slack"`
+	NotificationQuietHours     notifier.QuietHoursConfig   `mapstructure:"notification_quiet_hours"`
+	NotificationSlackChannels  map[string]string           `mapstructure:"notification_slack_channels"`
+	NotificationLabelRouting   []notifier.LabelRoutingRule `mapstructure:"notification_label_routing"`
+	IAM                        iam.ClientConfig            `mapstructure:"iam"`
+	Log                        logger.Config               `mapstructure:"log"`
+	DB                         store.Config                `mapstructure:"db"`
+	Appeal                     appeal.Config               `mapstructure:"appeal"`
+	AttachmentStorage          blob.Config                 `mapstructure:"attachment_storage"`
+	ProviderRetry              provider.RetryConfig        `mapstructure:"provider_retry"`
+	ProviderConcurrency        provider.ConcurrencyConfig  `mapstructure:"provider_concurrency"`
+	RoleMappings               provider.RoleMappings       `mapstructure:"role_mappings"`
+	ProviderProfile            provider.ProfileConfig      `mapstructure:"provider_profile"`
+	OIDC                       auth.OIDCConfig             `mapstructure:"oidc"`
+	Worker                     WorkerConfig                `mapstructure:"worker"`
+	Bot                        bot.Config                  `mapstructure:"bot"`
+	MagicLink                  magiclink.Config            `mapstructure:"magic_link"`
+	Metrics                    metrics.Config              `mapstructure:"metrics"`
 }
 
 // LoadServiceConfig returns service configuration
@@ -58,20 +88,41 @@ func LoadServiceConfig() (*ServiceConfig, error) {
 	return &config, nil
 }
 
-// RunServer runs the application server
-func RunServer(c *ServiceConfig) error {
+// Services holds the initialized business services and their shared dependencies
+type Services struct {
+	DB       *gorm.DB
+	Logger   *zap.Logger
+	Notifier domain.Notifier
+	// QuietHoursNotifier holds the notifications deferred during the quiet hours, it's nil if they're disabled
+	QuietHoursNotifier *notifier.QuietHoursNotifier
+
+	ResourceService *resource.Service
+	PolicyService   *policy.Service
+	ProviderService *provider.Service
+	ApprovalService domain.ApprovalService
+	AppealService   *appeal.Service
+	// DelegationService manages the approval delegations, the appeal service accepts the actions of the delegates
+	DelegationService domain.ApprovalDelegationService
+}
+
+// InitServices initializes all the business services based on the service configuration
+func InitServices(c *ServiceConfig) (*Services, error) {
 	db, err := getDB(c)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	logger, err := logger.New(&logger.Config{
 		Level: c.Log.Level,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	if c.Appeal.ApprovalAttestation && c.SigningSecretKey == "" {
+		return nil, fmt.Errorf("signing secret key is required to enable the approval attestation")
+	}
+	signer := crypto.NewHMAC(c.SigningSecretKey)
 	crypto := crypto.NewAES(c.EncryptionSecretKeyKey)
 
 	providerRepository := provider.NewRepository(db)
@@ -82,7 +133,7 @@ func RunServer(c *ServiceConfig) error {
 
 	iamClient, err := iam.NewClient(&c.IAM)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	iamService := iam.NewService(iamClient)
 
@@ -92,16 +143,73 @@ func RunServer(c *ServiceConfig) error {
 		grafana.NewProvider(domain.ProviderTypeGrafana, crypto),
 		tableau.NewProvider(domain.ProviderTypeTableau, crypto),
 	}
+	// the slots are released while waiting for the next retry
+	concurrencyLimiter := provider.NewConcurrencyLimiter(c.ProviderConcurrency)
+	if concurrencyLimiter != nil && expvar.Get("provider_concurrency") == nil {
+		expvar.Publish("provider_concurrency", expvar.Func(func() interface{} {
+			return concurrencyLimiter.Stats()
+		}))
+	}
+	for i, p := range providers {
+		providers[i] = provider.WithRetry(provider.WithConcurrencyLimit(p, concurrencyLimiter), c.ProviderRetry)
+	}
+
+	slackNotifier := notifier.NewSlackNotifier(c.SlackAccessToken)
+	notificationChannels := map[string]domain.Notifier{
+		notifier.ChannelSlack: slackNotifier,
+		notifier.ChannelAll:   notifier.NewComposite(slackNotifier),
+	}
+	for name, slackChannel := range c.NotificationSlackChannels {
+		if notificationChannels[name] != nil {
+			return nil, fmt.Errorf("notification slack channel %q conflicts with a built-in channel", name)
+		}
+		notificationChannels[name] = notifier.NewSlackChannelNotifier(slackNotifier, slackChannel)
+	}
+	// the reminders escalated to an unregistered channel would silently go through the default channel
+	for _, channel := range c.Appeal.Reminder.EscalationChannels {
+		if channel != "" && notificationChannels[channel] == nil {
+			return nil, fmt.Errorf("%w: %q of the reminder escalation channels", notifier.ErrChannelNotFound, channel)
+		}
+	}
+	router, err := notifier.NewRouter(c.NotificationDefaultChannel, notificationChannels)
+	if err != nil {
+		return nil, err
+	}
+	var defaultNotifier domain.Notifier = router
+	var quietHoursNotifier *notifier.QuietHoursNotifier
+	if c.NotificationQuietHours.Enabled {
+		quietHoursNotifier, err = notifier.NewQuietHoursNotifier(router, notifier.NewRepository(db), c.NotificationQuietHours)
+		if err != nil {
+			return nil, err
+		}
+		defaultNotifier = quietHoursNotifier
+	}
+	// the channels are chosen before the quiet hours, the deferred notifications don't keep their labels
+	if len(c.NotificationLabelRouting) > 0 {
+		defaultNotifier, err = notifier.NewLabelRouter(defaultNotifier, c.NotificationLabelRouting, notificationChannels, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	notifier := notifier.NewSlackNotifier(c.SlackAccessToken)
+	blobStorage, err := blob.New(&c.AttachmentStorage)
+	if err != nil {
+		return nil, err
+	}
 
-	resourceService := resource.NewService(resourceRepository)
-	policyService := policy.NewService(policyRepository)
+	resourceService := resource.NewService(resourceRepository, c.Appeal.Admins)
+	policyService := policy.NewService(policyRepository, appealRepository)
 	providerService := provider.NewService(
 		providerRepository,
 		resourceService,
 		providers,
 	)
+	providerService.RoleMappings = c.RoleMappings
+	providerService.Profile = c.ProviderProfile
+	if err := providerService.ValidateProfiles(context.Background()); err != nil {
+		return nil, err
+	}
+	logger.Info("provider config profile", zap.String("active", c.ProviderProfile.Active), zap.Strings("required", c.ProviderProfile.Required))
 	approvalService := approval.NewService(approvalRepository, policyService)
 	appealService := appeal.NewService(
 		appealRepository,
@@ -110,43 +218,79 @@ func RunServer(c *ServiceConfig) error {
 		providerService,
 		policyService,
 		iamService,
-		notifier,
+		defaultNotifier,
+		blobStorage,
 		logger,
+		&c.Appeal,
 	)
+	decisionEvaluator, err := c.Appeal.RegoPolicy.NewEvaluator(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	appealService.DecisionEvaluator = decisionEvaluator
+	appealService.Signer = signer
+	m, err := metrics.New(&c.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	appealService.Metrics = m
+	delegationService := delegation.NewService(delegation.NewRepository(db), defaultNotifier, logger)
+	appealService.DelegationService = delegationService
+
+	return &Services{
+		DB:                 db,
+		Logger:             logger,
+		Notifier:           defaultNotifier,
+		QuietHoursNotifier: quietHoursNotifier,
+		ResourceService:    resourceService,
+		PolicyService:      policyService,
+		ProviderService:    providerService,
+		ApprovalService:    approvalService,
+		AppealService:      appealService,
+		DelegationService:  delegationService,
+	}, nil
+}
 
-	providerJobHandler := provider.NewJobHandler(providerService)
-	appealJobHandler := appeal.NewJobHandler(logger, appealService, notifier)
-
-	// init scheduler
-	tasks := []*scheduler.Task{
-		{
-			CronTab: "0 */2 * * *",
-			Func:    providerJobHandler.GetResources,
-		},
-		{
-			CronTab: "0/20 * * * *",
-			Func:    appealJobHandler.RevokeExpiredAccess,
-		},
-		{
-			CronTab: "0 9 * * *", // at 09.00
-			Func:    appealJobHandler.NotifyAboutToExpireAccess,
-		},
-	}
-	s, err := scheduler.New(tasks)
+// RunServer runs the application server
+func RunServer(c *ServiceConfig) error {
+	services, err := InitServices(c)
 	if err != nil {
 		return err
 	}
-	s.Run()
+
+	if c.Worker.RunInServer {
+		s, err := scheduler.New(getJobs(services))
+		if err != nil {
+			return err
+		}
+		s.Run()
+	}
 
 	// init grpc server
-	grpcServer := grpc.NewServer()
+	interceptors := []grpc.UnaryServerInterceptor{loggerUnaryInterceptor(services.Logger)}
+	var eventsVerifier events.ActorVerifier
+	var attachmentsVerifier attachments.ActorVerifier
+	var delegationsVerifier delegations.ActorVerifier
+	if c.OIDC.Enabled {
+		verifier, err := auth.NewOIDCVerifier(&c.OIDC)
+		if err != nil {
+			return err
+		}
+		interceptors = append(interceptors, auth.UnaryServerInterceptor(verifier))
+		eventsVerifier = verifier
+		attachmentsVerifier = verifier
+		delegationsVerifier = verifier
+	}
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors...),
+	)
 	protoAdapter := v1.NewAdapter()
 	pb.RegisterGuardianServiceServer(grpcServer, v1.NewGRPCServer(
-		resourceService,
-		providerService,
-		policyService,
-		appealService,
-		approvalService,
+		services.ResourceService,
+		services.ProviderService,
+		services.PolicyService,
+		services.AppealService,
+		services.ApprovalService,
 		protoAdapter,
 	))
 
@@ -176,12 +320,44 @@ func RunServer(c *ServiceConfig) error {
 		fmt.Fprint(w, "pong")
 	})
 	baseMux.Handle("/api/", http.StripPrefix("/api", gwmux))
+	expvar.NewString("provider_profile").Set(c.ProviderProfile.Active)
+	baseMux.Handle("/debug/vars", expvar.Handler())
+	// the event streams end before the write timeout, the clients reconnect and resume from their last event
+	baseMux.Handle("/events/appeals", events.NewHandler(
+		services.AppealService.StatusBroker,
+		eventsVerifier,
+		services.AppealService,
+		serverWriteTimeout-time.Second,
+		services.Logger,
+	))
+	attachmentsHandler := attachments.NewHandler(attachmentsVerifier, services.Logger, services.AppealService)
+	baseMux.Handle(attachments.Path, attachmentsHandler)
+	baseMux.Handle(attachments.Path+"/", attachmentsHandler)
+	baseMux.Handle(delegations.Path, delegations.NewHandler(delegationsVerifier, services.Logger, services.DelegationService))
+	if c.Bot.Token != "" {
+		baseMux.Handle("/bot/actions", bot.NewHandler(c.Bot, services.Logger, services.AppealService))
+	}
+	if c.Bot.SlackSigningSecret != "" {
+		baseMux.Handle("/bot/slack/actions", bot.NewSlackHandler(
+			bot.NewSlackSignatureVerifier(c.Bot.SlackSigningSecret, c.Bot.SlackRequestMaxAge),
+			bot.NewSlackUserResolver(c.SlackAccessToken),
+			services.Logger,
+			services.AppealService,
+		))
+	}
+	if c.MagicLink.Secret != "" {
+		baseMux.Handle(magiclink.Path, magiclink.NewHandler(
+			magiclink.NewSigner(c.MagicLink.Secret, c.MagicLink.TTL),
+			services.Logger,
+			services.AppealService,
+		))
+	}
 
 	server := &http.Server{
 		Handler:      grpcHandlerFunc(grpcServer, baseMux),
 		Addr:         address,
 		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		WriteTimeout: serverWriteTimeout,
 		IdleTimeout:  120 * time.Second,
 	}
 
@@ -209,6 +385,11 @@ func Migrate(c *ServiceConfig) error {
 		&model.Appeal{},
 		&model.Approval{},
 		&model.Approver{},
+		&model.Attachment{},
+		&model.AppealComment{},
+		&model.ApprovalDelegation{},
+		&model.DeferredNotification{},
+		&model.OutboxMessage{},
 	}
 	return store.Migrate(db, models...)
 }
@@ -236,9 +417,29 @@ func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Ha
 
 func headerMatcher(key string) (string, bool) {
 	switch key {
-	case "X-Goog-Authenticated-User-Email":
+	case "X-Goog-Authenticated-User-Email",
+		"X-Request-Id",
+		"X-Cloud-Trace-Context":
 		return key, true
 	default:
 		return runtime.DefaultHeaderMatcher(key)
 	}
 }
+
+// loggerUnaryInterceptor attaches a request-scoped logger to the request context
+// annotated with the incoming trace id so the logs can be correlated to the request
+func loggerUnaryInterceptor(l *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		requestLogger := l.With(zap.String("method", info.FullMethod))
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			for _, key := range []string{"x-request-id", "x-cloud-trace-context"} {
+				if values := md.Get(key); len(values) > 0 {
+					requestLogger = requestLogger.With(zap.String("trace_id", values[0]))
+					break
+				}
+			}
+		}
+
+		return handler(logger.NewContext(ctx, requestLogger), req)
+	}
+}
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/delegation"
+	"github.com/odpf/guardian/provider"
+	"github.com/odpf/guardian/scheduler"
+	"github.com/odpf/guardian/store"
+	"go.uber.org/zap"
+)
+
+// WorkerConfig configures the background jobs
+type WorkerConfig struct {
+	// RunInServer keeps running the jobs within the server. It can be disabled once the jobs are run by `guardian worker`
+	RunInServer bool `mapstructure:"run_in_server" default:"true"`
+	// ShutdownTimeout is the maximum time to wait for the running jobs to complete on shutdown
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout" default:"1m"`
+}
+
+// getJobs returns the periodic maintenance jobs. Each run holds a database lock named after the job
+// so multiple server or worker instances don't execute the same job at the same time
+func getJobs(services *Services) []*scheduler.Task {
+	providerJobHandler := provider.NewJobHandler(services.ProviderService)
+	appealJobHandler := appeal.NewJobHandler(services.Logger, services.AppealService, services.Notifier)
+	delegationJobHandler := delegation.NewJobHandler(services.DelegationService)
+
+	tasks := []*scheduler.Task{
+		{
+			Name:    "fetch_resources",
+			CronTab: "0 */2 * * *",
+			Func:    providerJobHandler.GetResources,
+		},
+		{
+			Name:    "revoke_expired_access",
+			CronTab: "0/20 * * * *",
+			Func:    appealJobHandler.RevokeExpiredAccess,
+		},
+		{
+			Name:    "retry_pending_revocations",
+			CronTab: "*/5 * * * *",
+			Func:    appealJobHandler.RetryPendingRevocations,
+		},
+		{
+			Name:    "dispatch_outbox_messages",
+			CronTab: "*/5 * * * *",
+			Func:    appealJobHandler.DispatchOutboxMessages,
+		},
+		{
+			Name:    "notify_about_to_expire_access",
+			CronTab: "0 9 * * *", // at 09.00
+			Func:    appealJobHandler.NotifyAboutToExpireAccess,
+		},
+		{
+			Name:    "toggle_access_windows",
+			CronTab: "*/5 * * * *",
+			Func:    appealJobHandler.ToggleAccessWindows,
+		},
+		{
+			Name:    "activate_scheduled_access",
+			CronTab: "*/5 * * * *",
+			Func:    appealJobHandler.ActivateScheduledAccess,
+		},
+		{
+			Name:    "remind_pending_approvers",
+			CronTab: "0 * * * *",
+			Func:    appealJobHandler.RemindPendingApprovers,
+		},
+		{
+			Name:    "cancel_abandoned_appeals",
+			CronTab: "30 * * * *",
+			Func:    appealJobHandler.CancelAbandonedAppeals,
+		},
+		{
+			Name:    "reassign_idle_approvals",
+			CronTab: "*/15 * * * *",
+			Func:    appealJobHandler.ReassignIdleApprovals,
+		},
+		{
+			Name:    "resolve_pending_approvers",
+			CronTab: "*/5 * * * *",
+			Func:    appealJobHandler.ResolvePendingApprovers,
+		},
+		{
+			Name:    "send_pending_approvals_digest",
+			CronTab: "0 9 * * *", // at 09.00
+			Func:    appealJobHandler.SendPendingApprovalsDigest,
+		},
+		{
+			Name:    "notify_started_delegations",
+			CronTab: "*/5 * * * *",
+			Func:    delegationJobHandler.NotifyStartedDelegations,
+		},
+		{
+			Name:    "delete_expired_delegations",
+			CronTab: "*/5 * * * *",
+			Func:    delegationJobHandler.DeleteExpiredDelegations,
+		},
+	}
+	if services.QuietHoursNotifier != nil {
+		tasks = append(tasks, &scheduler.Task{
+			Name:    "flush_deferred_notifications",
+			CronTab: "*/10 * * * *",
+			Func:    services.QuietHoursNotifier.Flush,
+		})
+	}
+	for _, t := range tasks {
+		t.Func = lockedJob(services, t.Name, t.Func)
+	}
+
+	return tasks
+}
+
+func lockedJob(services *Services, name string, fn func() error) func() error {
+	return func() error {
+		logger := services.Logger.With(zap.String("job", name))
+		acquired, err := store.WithAdvisoryLock(context.Background(), services.DB, "job:"+name, fn)
+		if err != nil {
+			return err
+		}
+		if !acquired {
+			logger.Info("skipping job, it's being run by another instance")
+			return nil
+		}
+		logger.Info("job completed")
+		return nil
+	}
+}
+
+// RunWorker runs the background jobs until it receives a termination signal, then waits for the
+// running jobs to complete
+func RunWorker(c *ServiceConfig) error {
+	services, err := InitServices(c)
+	if err != nil {
+		return err
+	}
+
+	s, err := scheduler.New(getJobs(services))
+	if err != nil {
+		return err
+	}
+	s.Run()
+	services.Logger.Info("worker started")
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	services.Logger.Info("stopping worker, waiting for the running jobs")
+	select {
+	case <-s.Stop().Done():
+		services.Logger.Info("worker stopped")
+	case <-time.After(c.Worker.ShutdownTimeout):
+		services.Logger.Warn("worker stopped before the running jobs completed")
+	}
+
+	return nil
+}
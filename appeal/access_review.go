@@ -0,0 +1,100 @@
+package appeal
+
+import (
+	"context"
+	"sort"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
+)
+
+// GetAccessReview lists the access granted within the period of the filter, along with the approvers of each and
+// whether it has since expired or been revoked, ordered by the time it's granted. The team of an access is the
+// appeal label of the SLA report teams
+func (s *Service) GetAccessReview(ctx context.Context, filter domain.AccessReviewFilter) ([]*domain.AccessReviewEntry, error) {
+	if filter.From.IsZero() || filter.To.IsZero() || !filter.From.Before(filter.To) {
+		return nil, ErrInvalidAccessReviewPeriod
+	}
+
+	appeals, err := s.repo.Find(ctx, map[string]interface{}{
+		"statuses": []string{domain.AppealStatusActive, domain.AppealStatusRevocationPending, domain.AppealStatusTerminated},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	teamLabel := s.config.getSLAReportTeamLabel()
+	entries := []*domain.AccessReviewEntry{}
+	for _, a := range appeals {
+		appeal, err := s.repo.GetByID(ctx, a.ID)
+		if err != nil {
+			return nil, err
+		}
+		if appeal == nil || appeal.Resource == nil {
+			continue
+		}
+
+		entry := newAccessReviewEntry(appeal, appeal.Labels[teamLabel])
+		if entry.GrantedAt.Before(filter.From) || !entry.GrantedAt.Before(filter.To) {
+			continue
+		}
+		if len(filter.ProviderTypes) > 0 && !utils.ContainsString(filter.ProviderTypes, entry.ProviderType) ||
+			len(filter.ProviderURNs) > 0 && !utils.ContainsString(filter.ProviderURNs, entry.ProviderURN) ||
+			len(filter.Teams) > 0 && !utils.ContainsString(filter.Teams, entry.Team) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].GrantedAt.Before(entries[j].GrantedAt)
+	})
+	return entries, nil
+}
+
+func newAccessReviewEntry(a *domain.Appeal, team string) *domain.AccessReviewEntry {
+	e := &domain.AccessReviewEntry{
+		AppealID:     a.ID,
+		User:         a.User,
+		ProviderType: a.Resource.ProviderType,
+		ProviderURN:  a.Resource.ProviderURN,
+		ResourceType: a.Resource.Type,
+		ResourceURN:  a.Resource.URN,
+		Role:         a.Role,
+		Team:         team,
+		Approvals:    []*domain.ApprovalProvenance{},
+		GrantedAt:    a.CreatedAt,
+	}
+	if a.Options != nil {
+		e.ExpirationDate = a.Options.ExpirationDate
+	}
+	for _, approval := range a.Approvals {
+		if approval.Status != domain.ApprovalStatusApproved {
+			continue
+		}
+		provenance := approval.GetProvenance()
+		e.Approvals = append(e.Approvals, provenance)
+		if provenance.ActedAt != nil && provenance.ActedAt.After(e.GrantedAt) {
+			e.GrantedAt = *provenance.ActedAt
+		}
+	}
+
+	switch a.Status {
+	case domain.AppealStatusActive:
+		e.Outcome = domain.AccessReviewOutcomeActive
+	case domain.AppealStatusRevocationPending:
+		e.Outcome = domain.AccessReviewOutcomeRevocationPending
+	default:
+		e.Outcome = domain.AccessReviewOutcomeRevoked
+		if a.RevokedBy == domain.SystemActorName && e.ExpirationDate != nil && !a.RevokedAt.Before(*e.ExpirationDate) {
+			e.Outcome = domain.AccessReviewOutcomeExpired
+		}
+	}
+	if !a.RevokedAt.IsZero() {
+		revokedAt := a.RevokedAt
+		e.RevokedAt = &revokedAt
+		e.RevokedBy = a.RevokedBy
+		e.RevokeReason = a.RevokeReason
+	}
+	return e
+}
@@ -0,0 +1,122 @@
+package appeal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+const accessWindowTimeLayout = "15:04"
+
+var weekDays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+type accessWindow struct {
+	days     map[time.Weekday]bool
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+}
+
+func parseAccessWindow(w *domain.AccessWindow) (*accessWindow, error) {
+	days := map[time.Weekday]bool{}
+	for _, d := range w.Days {
+		weekDay, ok := weekDays[strings.ToLower(d)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unrecognized day %q", ErrInvalidAccessWindow, d)
+		}
+		days[weekDay] = true
+	}
+
+	start, err := time.Parse(accessWindowTimeLayout, w.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid start time %q", ErrInvalidAccessWindow, w.StartTime)
+	}
+	end, err := time.Parse(accessWindowTimeLayout, w.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid end time %q", ErrInvalidAccessWindow, w.EndTime)
+	}
+	if start.Equal(end) {
+		return nil, fmt.Errorf("%w: start time and end time can't be the same", ErrInvalidAccessWindow)
+	}
+
+	location := time.UTC
+	if w.Timezone != "" {
+		location, err = time.LoadLocation(w.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid timezone %q", ErrInvalidAccessWindow, w.Timezone)
+		}
+	}
+
+	return &accessWindow{
+		days:     days,
+		start:    time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		end:      time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute,
+		location: location,
+	}, nil
+}
+
+// isOpen checks whether t is within the window. For an overnight window, the hours after
+// midnight belong to the day the window is opened
+func (w *accessWindow) isOpen(t time.Time) bool {
+	t = t.In(w.location)
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	day := t.Weekday()
+
+	var open bool
+	if w.start < w.end {
+		open = clock >= w.start && clock < w.end
+	} else if clock >= w.start {
+		open = true
+	} else if clock < w.end {
+		open = true
+		day = (day + 6) % 7
+	}
+
+	return open && (len(w.days) == 0 || w.days[day])
+}
+
+// nextToggle returns the first time after t the window opens or closes, which is on its start or end time within
+// a week
+func (w *accessWindow) nextToggle(t time.Time) time.Time {
+	open := w.isOpen(t)
+	boundaries := []time.Duration{w.start, w.end}
+	if w.end < w.start {
+		boundaries = []time.Duration{w.end, w.start}
+	}
+
+	local := t.In(w.location)
+	for day := 0; day <= 7; day++ {
+		date := local.AddDate(0, 0, day)
+		for _, clock := range boundaries {
+			boundary := time.Date(date.Year(), date.Month(), date.Day(), int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, w.location)
+			if boundary.After(t) && w.isOpen(boundary) != open {
+				return boundary
+			}
+		}
+	}
+	return local.AddDate(0, 0, 1)
+}
+
+// isAccessWindowOpen returns true if the appeal doesn't have any access window
+func isAccessWindowOpen(a *domain.Appeal, t time.Time) (bool, error) {
+	if a.Options == nil || a.Options.AccessWindow == nil {
+		return true, nil
+	}
+
+	w, err := parseAccessWindow(a.Options.AccessWindow)
+	if err != nil {
+		return false, err
+	}
+
+	return w.isOpen(t), nil
+}
@@ -0,0 +1,108 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
+	"go.uber.org/zap"
+)
+
+// AcknowledgeApproval records that the approver is reviewing the current approval step, without making any
+// action on it, so the requester and the other approvers know the appeal is being looked into. A step is
+// acknowledged by a single approver. The acknowledgment is recorded as a public comment on the appeal and
+// the requester is notified
+func (s *Service) AcknowledgeApproval(ctx context.Context, appealID uint, approvalName, actor string) (result *domain.Appeal, err error) {
+	logger := s.getLogger(ctx).With(
+		zap.Uint("appeal_id", appealID),
+		zap.String("approval_name", approvalName),
+		zap.String("actor", actor),
+	)
+	defer func() {
+		if err != nil {
+			logger.Error("failed to acknowledge approval step", zap.Error(err))
+		}
+	}()
+
+	if appealID == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+	if approvalName == "" {
+		return nil, ErrApprovalNameNotFound
+	}
+	if err := s.validator.Var(actor, "required,email"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	}
+	if err := checkActor(ctx, actor); err != nil {
+		return nil, err
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+	if err := checkIfAppealStatusStillPending(appeal.Status); err != nil {
+		return nil, err
+	}
+
+	for _, approval := range appeal.Approvals {
+		if approval.Name != approvalName {
+			if err := checkPreviousApprovalStatus(approval.Status); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if approval.Status != domain.ApprovalStatusPending {
+			return nil, checkApprovalStatus(approval.Status)
+		}
+		if !utils.ContainsString(approval.Approvers, actor) {
+			return nil, ErrActionForbidden
+		}
+		if approval.AcknowledgedBy == actor {
+			return appeal, nil
+		}
+		if approval.AcknowledgedBy != "" {
+			return nil, fmt.Errorf("%w: %s", ErrApprovalAlreadyAcknowledged, approval.AcknowledgedBy)
+		}
+
+		now := s.TimeNow()
+		approval.AcknowledgedBy = actor
+		approval.AcknowledgedAt = &now
+
+		var message *domain.OutboxMessage
+		if err := s.repo.WithTransaction(ctx, func(tx domain.AppealRepository) error {
+			if err := tx.Update(ctx, appeal); err != nil {
+				return err
+			}
+			if err := tx.AddComment(ctx, &domain.AppealComment{
+				AppealID:   appeal.ID,
+				CreatedBy:  actor,
+				Body:       fmt.Sprintf("acknowledged the approval step %q and is reviewing it", approval.Name),
+				Visibility: domain.CommentVisibilityPublic,
+			}); err != nil {
+				return err
+			}
+
+			var err error
+			message, err = s.addOutboxMessage(ctx, tx, nil, []domain.Notification{{
+				User:    appeal.User,
+				Message: fmt.Sprintf("%s is reviewing your appeal to %s", actor, appeal.Resource.URN),
+				Labels:  appeal.Labels,
+			}})
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		logger.Info("approval step acknowledged")
+
+		s.dispatchOutboxMessage(ctx, message)
+		return appeal, nil
+	}
+
+	return nil, ErrApprovalNameNotFound
+}
@@ -0,0 +1,87 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// applyApprovalConditions narrows down the role and the access duration of the appeal to the conditions of the
+// approval. The granted access must be a subset of the requested one: the role's permissions are a subset of the
+// requested role's permissions, and the access doesn't last longer than requested. The requested role and options
+// are kept in the appeal on the first conditional approval
+func (s *Service) applyApprovalConditions(ctx context.Context, a *domain.Appeal, conditions *domain.ApprovalConditions) error {
+	if conditions.Role == "" && conditions.Duration == "" {
+		return fmt.Errorf("%w: either the role or the duration is required", ErrInvalidApprovalConditions)
+	}
+
+	role := a.Role
+	if conditions.Role != "" && conditions.Role != a.Role {
+		if err := s.checkRoleSubset(ctx, a.Resource, a.Role, conditions.Role); err != nil {
+			return err
+		}
+		role = conditions.Role
+	}
+
+	var expirationDate *time.Time
+	if a.Options != nil {
+		expirationDate = a.Options.ExpirationDate
+	}
+	if conditions.Duration != "" {
+		duration, err := time.ParseDuration(conditions.Duration)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("%w: invalid duration %q", ErrInvalidApprovalConditions, conditions.Duration)
+		}
+		grantedExpirationDate := getAccessStartTime(a, s.TimeNow()).Add(duration)
+		if expirationDate != nil && grantedExpirationDate.After(*expirationDate) {
+			return fmt.Errorf("%w: the access can't last longer than requested, until %s", ErrInvalidApprovalConditions, expirationDate.Format(time.RFC3339))
+		}
+		expirationDate = &grantedExpirationDate
+	}
+
+	if a.Requested == nil {
+		a.Requested = &domain.GrantParameters{Role: a.Role}
+		if a.Options != nil {
+			requestedOptions := *a.Options
+			a.Requested.Options = &requestedOptions
+		}
+	}
+	a.Role = role
+	if expirationDate != nil {
+		if a.Options == nil {
+			a.Options = &domain.AppealOptions{}
+		}
+		a.Options.ExpirationDate = expirationDate
+	}
+	return nil
+}
+
+// checkRoleSubset checks that every permission of the granted role is also a permission of the requested role
+// on the resource
+func (s *Service) checkRoleSubset(ctx context.Context, r *domain.Resource, requestedRole, grantedRole string) error {
+	requestedPermissions, err := s.providerService.GetRolePermissions(ctx, r.ProviderType, r.ProviderURN, r.Type, requestedRole)
+	if err != nil {
+		return err
+	}
+	grantedPermissions, err := s.providerService.GetRolePermissions(ctx, r.ProviderType, r.ProviderURN, r.Type, grantedRole)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidApprovalConditions, err)
+	}
+
+	for _, granted := range grantedPermissions {
+		found := false
+		for _, requested := range requestedPermissions {
+			if reflect.DeepEqual(granted, requested) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: role %q has the permission %v which the requested role %q doesn't have", ErrInvalidApprovalConditions, grantedRole, granted, requestedRole)
+		}
+	}
+	return nil
+}
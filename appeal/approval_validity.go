@@ -0,0 +1,78 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// reopenStaleApprovals reopens the approval steps before the index which approval is older than the approval
+// validity of the appeal's policy, for their approvers to approve again. The steps resolved by the system,
+// e.g. by the conditions or the external checks, don't go stale. It returns the reopened steps
+func (s *Service) reopenStaleApprovals(ctx context.Context, a *domain.Appeal, index int) ([]*domain.Approval, error) {
+	candidates := []*domain.Approval{}
+	for _, approval := range a.Approvals[:index] {
+		if approval.Status == domain.ApprovalStatusApproved && approval.Actor != nil && *approval.Actor != domain.SystemActorName {
+			candidates = append(candidates, approval)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	validity, err := s.getApprovalValidity(ctx, a)
+	if err != nil || validity == 0 {
+		return nil, err
+	}
+
+	now := s.TimeNow()
+	reopened := []*domain.Approval{}
+	for _, approval := range candidates {
+		// the validity counts from the approval itself, the later writes of the step don't extend it. The steps
+		// approved before the status change time was recorded fall back to their last write time
+		approvedAt := approval.UpdatedAt
+		if approval.StatusChangedAt != nil {
+			approvedAt = *approval.StatusChangedAt
+		}
+		if now.Sub(approvedAt) <= validity {
+			continue
+		}
+		approval.Status = domain.ApprovalStatusPending
+		approval.Actor = nil
+		approval.Reason = ""
+		approval.IsOverridden = false
+		approval.OnBehalfOf = ""
+		approval.SLAMet = nil
+		approval.Attestation = nil
+		approval.LastRemindedAt = nil
+		approval.ReminderLevel = 0
+		approval.StatusChangedAt = &now
+		reopened = append(reopened, approval)
+	}
+	return reopened, nil
+}
+
+// getApprovalValidity returns the approval validity of the appeal's policy, or zero if the approvals don't expire
+func (s *Service) getApprovalValidity(ctx context.Context, a *domain.Appeal) (time.Duration, error) {
+	p := a.Policy
+	if p == nil {
+		if a.PolicyID == "" {
+			return 0, nil
+		}
+		var err error
+		if p, err = s.policyService.GetOne(ctx, a.PolicyID, a.PolicyVersion); err != nil {
+			return 0, err
+		}
+	}
+	if p == nil || p.ApprovalValidity == "" {
+		return 0, nil
+	}
+
+	validity, err := time.ParseDuration(p.ApprovalValidity)
+	if err != nil {
+		return 0, fmt.Errorf("parsing approval validity of policy %q: %w", p.ID, err)
+	}
+	return validity, nil
+}
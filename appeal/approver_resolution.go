@@ -0,0 +1,161 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+// approverResolutionError is the error of the IAM resolving the user approvers, the approver resolution fallback
+// of the policy applies to it
+type approverResolutionError struct {
+	err error
+}
+
+func (e *approverResolutionError) Error() string {
+	return fmt.Sprintf("resolving the user approvers: %v", e.err)
+}
+
+func (e *approverResolutionError) Unwrap() error {
+	return e.err
+}
+
+// applyApproverResolutionFallback returns the approvers and the status of the step which approvers failed to be
+// resolved according to the approver resolution fallback of the appeal's policy. The error is returned as is if
+// it's not an IAM failure or the policy has no fallback
+func (s *Service) applyApproverResolutionFallback(logger *zap.Logger, a *domain.Appeal, step *domain.Step, err error) ([]string, string, error) {
+	resolutionErr, ok := err.(*approverResolutionError)
+	if !ok || a.Policy == nil || a.Policy.ApproverResolutionFallback == nil {
+		return nil, "", err
+	}
+
+	fallback := a.Policy.ApproverResolutionFallback
+	logger.Warn("unable to resolve the approvers, applying the fallback",
+		zap.String("user", a.User),
+		zap.String("approval_name", step.Name),
+		zap.String("strategy", fallback.Strategy),
+		zap.Error(resolutionErr.err),
+	)
+	switch fallback.Strategy {
+	case domain.ApproverResolutionFallbackDefer:
+		return nil, domain.ApprovalStatusPendingApproverResolution, nil
+	case domain.ApproverResolutionFallbackDefaultApprovers:
+		return fallback.Approvers, domain.ApprovalStatusPending, nil
+	default:
+		return nil, "", err
+	}
+}
+
+// ResolvePendingApprovers retries resolving the approvers of the steps deferred by the approver resolution
+// fallback. The resolved steps become pending, and the approvers of the appeal's current step are notified. The
+// steps still failing to be resolved are retried on the next run
+func (s *Service) ResolvePendingApprovers(ctx context.Context) error {
+	logger := s.getLogger(ctx)
+
+	appeals, err := s.getPendingAppealDetails(ctx)
+	if err != nil {
+		return err
+	}
+
+	policies := map[string]*domain.Policy{}
+	loads := map[string]*domain.ApproverLoad{}
+	for _, a := range appeals {
+		resolved := 0
+		for _, approval := range a.Approvals {
+			if approval.Status != domain.ApprovalStatusPendingApproverResolution {
+				continue
+			}
+
+			step, err := s.getApprovalStep(ctx, approval, policies)
+			if err != nil {
+				return err
+			}
+			if step == nil {
+				logger.Error("approval step not found in the policy",
+					zap.Uint("appeal_id", a.ID),
+					zap.String("approval_name", approval.Name),
+					zap.String("policy_id", approval.PolicyID),
+					zap.Uint("policy_version", approval.PolicyVersion),
+				)
+				break
+			}
+
+			approvers, err := s.resolveApprovers(a.User, a.Resource, step)
+			if err == nil && len(approvers) == 0 {
+				err = ErrApproversNotFound
+			}
+			if err != nil {
+				logger.Warn("unable to resolve the approvers, retrying on the next run",
+					zap.Uint("appeal_id", a.ID),
+					zap.String("approval_name", approval.Name),
+					zap.Error(err),
+				)
+				break
+			}
+
+			for _, email := range approvers {
+				if err := s.approvalService.AddApprover(ctx, &domain.Approver{
+					ApprovalID: approval.ID,
+					AppealID:   a.ID,
+					Email:      email,
+				}); err != nil {
+					return err
+				}
+			}
+			approval.Approvers = approvers
+			approval.Status = domain.ApprovalStatusPending
+			resolvedAt := s.TimeNow()
+			approval.StatusChangedAt = &resolvedAt
+			resolved++
+		}
+		if resolved == 0 {
+			continue
+		}
+
+		if err := s.assignApprover(ctx, a, loads); err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, a); err != nil {
+			return err
+		}
+		logger.Info("approvers resolved", zap.Uint("appeal_id", a.ID), zap.Int("approval_count", resolved))
+
+		if notifications := s.getApprovalNotifications(a); len(notifications) > 0 {
+			if err := s.notifier.Notify(notifications); err != nil {
+				logger.Error(err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// getApprovalStep returns the policy step of the approval, including the steps of the sensitivity tiers, or nil if
+// the policy doesn't have it. The policies are cached by id and version across the calls
+func (s *Service) getApprovalStep(ctx context.Context, approval *domain.Approval, policies map[string]*domain.Policy) (*domain.Step, error) {
+	key := fmt.Sprintf("%s@%d", approval.PolicyID, approval.PolicyVersion)
+	p, cached := policies[key]
+	if !cached {
+		var err error
+		if p, err = s.policyService.GetOne(ctx, approval.PolicyID, approval.PolicyVersion); err != nil {
+			return nil, err
+		}
+		policies[key] = p
+	}
+	if p == nil {
+		return nil, nil
+	}
+
+	steps := p.Steps
+	for _, tier := range p.SensitivityTiers {
+		steps = append(append([]*domain.Step{}, steps...), tier.Steps...)
+	}
+	for _, step := range steps {
+		if step.Name == approval.Name {
+			return step, nil
+		}
+	}
+	return nil, nil
+}
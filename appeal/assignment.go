@@ -0,0 +1,147 @@
+package appeal
+
+import (
+	"context"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+// assignApprover assigns the current approval step of the appeal to a single approver if its assignment
+// strategy asks for it. The loads are shared across the calls of a batch so the appeals created together
+// are spread across the approvers as well
+func (s *Service) assignApprover(ctx context.Context, appeal *domain.Appeal, loads map[string]*domain.ApproverLoad) error {
+	approval := appeal.GetNextPendingApproval()
+	if approval == nil || !approval.IsAssignable() || approval.Assignee != "" {
+		return nil
+	}
+
+	assignee, err := s.pickAssignee(ctx, approval, "", loads)
+	if err != nil {
+		return err
+	}
+	s.setAssignee(approval, assignee, loads)
+	return nil
+}
+
+// ReassignIdleApprovals reassigns the approval steps which assignee hasn't acted on within the step's
+// reassign_after duration to another approver, picked by the step's assignment strategy, and notifies them
+func (s *Service) ReassignIdleApprovals(ctx context.Context) error {
+	logger := s.getLogger(ctx)
+
+	appeals, err := s.getPendingAppealDetails(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.TimeNow()
+	loads := map[string]*domain.ApproverLoad{}
+	for _, a := range appeals {
+		approval := a.GetNextPendingApproval()
+		if approval == nil || !approval.IsAssignable() || approval.Assignee == "" || approval.AssignedAt == nil || approval.ReassignAfter == "" {
+			continue
+		}
+		reassignAfter, err := time.ParseDuration(approval.ReassignAfter)
+		if err != nil {
+			logger.Error("invalid reassign_after", zap.Uint("appeal_id", a.ID), zap.String("approval_name", approval.Name), zap.Error(err))
+			continue
+		}
+		if now.Sub(*approval.AssignedAt) < reassignAfter {
+			continue
+		}
+
+		previousAssignee := approval.Assignee
+		assignee, err := s.pickAssignee(ctx, approval, previousAssignee, loads)
+		if err != nil {
+			return err
+		}
+		if assignee == "" {
+			continue
+		}
+		s.setAssignee(approval, assignee, loads)
+		if load := loads[previousAssignee]; load != nil && load.PendingCount > 0 {
+			load.PendingCount--
+		}
+
+		if err := s.repo.Update(ctx, a); err != nil {
+			return err
+		}
+		logger.Info("approval step reassigned",
+			zap.Uint("appeal_id", a.ID),
+			zap.String("approval_name", approval.Name),
+			zap.String("previous_assignee", previousAssignee),
+			zap.String("assignee", assignee),
+		)
+
+		if err := s.notifier.Notify(s.getApprovalNotifications(a)); err != nil {
+			logger.Error(err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) setAssignee(approval *domain.Approval, assignee string, loads map[string]*domain.ApproverLoad) {
+	if assignee == "" {
+		return
+	}
+
+	now := s.TimeNow()
+	approval.Assignee = assignee
+	approval.AssignedAt = &now
+	if load := loads[assignee]; load != nil {
+		load.PendingCount++
+		load.LastAssignedAt = &now
+	}
+}
+
+// pickAssignee returns the approver of the step preferred by its assignment strategy, other than the excluded
+// one. The loads missing from the cache are fetched. It returns an empty assignee if there's no other approver
+func (s *Service) pickAssignee(ctx context.Context, approval *domain.Approval, exclude string, loads map[string]*domain.ApproverLoad) (string, error) {
+	missing := []string{}
+	for _, approver := range approval.Approvers {
+		if loads[approver] == nil {
+			missing = append(missing, approver)
+		}
+	}
+	if len(missing) > 0 {
+		fetched, err := s.approvalService.GetApproverLoads(ctx, missing)
+		if err != nil {
+			return "", err
+		}
+		for _, approver := range missing {
+			if load := fetched[approver]; load != nil {
+				loads[approver] = load
+			} else {
+				loads[approver] = &domain.ApproverLoad{Approver: approver}
+			}
+		}
+	}
+
+	var picked *domain.ApproverLoad
+	for _, approver := range approval.Approvers {
+		if approver == exclude {
+			continue
+		}
+		if load := loads[approver]; picked == nil || isPreferredAssignee(approval.AssignmentStrategy, load, picked) {
+			picked = load
+		}
+	}
+	if picked == nil {
+		return "", nil
+	}
+	return picked.Approver, nil
+}
+
+// isPreferredAssignee returns true if a should be assigned rather than b. The least loaded strategy prefers
+// the approver with fewer pending assignments, then both strategies prefer the least recently assigned one
+func isPreferredAssignee(strategy string, a, b *domain.ApproverLoad) bool {
+	if strategy == domain.AssignmentStrategyLeastLoaded && a.PendingCount != b.PendingCount {
+		return a.PendingCount < b.PendingCount
+	}
+	if a.LastAssignedAt == nil || b.LastAssignedAt == nil {
+		return a.LastAssignedAt == nil && b.LastAssignedAt != nil
+	}
+	return a.LastAssignedAt.Before(*b.LastAssignedAt)
+}
@@ -0,0 +1,81 @@
+package appeal
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// attestationPayload is the appeal state an actor agrees on when making an action on an approval step
+type attestationPayload struct {
+	AppealID      uint                  `json:"appeal_id"`
+	User          string                `json:"user"`
+	ResourceID    uint                  `json:"resource_id"`
+	Role          string                `json:"role"`
+	Options       *domain.AppealOptions `json:"options"`
+	Labels        map[string]string     `json:"labels"`
+	PolicyID      string                `json:"policy_id"`
+	PolicyVersion uint                  `json:"policy_version"`
+	ApprovalName  string                `json:"approval_name"`
+	Actor         string                `json:"actor"`
+	Action        string                `json:"action"`
+	CreatedAt     time.Time             `json:"created_at"`
+}
+
+// NewApprovalAttestation returns the attestation of the actor making the action on the approval step, signed
+// by the signer so it can't be forged without the server-held key
+func NewApprovalAttestation(signer domain.Signer, a *domain.Appeal, approvalName, actor, action string, createdAt time.Time) (*domain.ApprovalAttestation, error) {
+	payload, err := getAttestationPayload(a, approvalName, actor, action, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ApprovalAttestation{
+		Actor:     actor,
+		Action:    action,
+		Signature: signature,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// VerifyApprovalAttestation checks whether the appeal is still the same as what the actor of the approval step attested
+// and whether the attestation is signed by the signer
+func VerifyApprovalAttestation(signer domain.Signer, a *domain.Appeal, approval *domain.Approval) (bool, error) {
+	if approval.Attestation == nil {
+		return false, ErrAttestationNotFound
+	}
+
+	payload, err := getAttestationPayload(a, approval.Name, approval.Attestation.Actor, approval.Attestation.Action, approval.Attestation.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	return signer.Verify(payload, approval.Attestation.Signature)
+}
+
+func getAttestationPayload(a *domain.Appeal, approvalName, actor, action string, createdAt time.Time) (string, error) {
+	payload, err := json.Marshal(attestationPayload{
+		AppealID:      a.ID,
+		User:          a.User,
+		ResourceID:    a.ResourceID,
+		Role:          a.Role,
+		Options:       a.Options,
+		Labels:        a.Labels,
+		PolicyID:      a.PolicyID,
+		PolicyVersion: a.PolicyVersion,
+		ApprovalName:  approvalName,
+		Actor:         actor,
+		Action:        action,
+		CreatedAt:     createdAt.UTC(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(payload), nil
+}
@@ -0,0 +1,101 @@
+package appeal_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/crypto"
+	"github.com/odpf/guardian/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyApprovalAttestation(t *testing.T) {
+	signer := crypto.NewHMAC("secret")
+	expirationDate := time.Now().Add(24 * time.Hour)
+	newAppeal := func() *domain.Appeal {
+		return &domain.Appeal{
+			ID:            1,
+			User:          "user@email.com",
+			ResourceID:    2,
+			Role:          "viewer",
+			PolicyID:      "policy_1",
+			PolicyVersion: 1,
+			Options: &domain.AppealOptions{
+				ExpirationDate: &expirationDate,
+			},
+			Labels: map[string]string{"team": "data"},
+		}
+	}
+	newAttestedApproval := func(t *testing.T, a *domain.Appeal) *domain.Approval {
+		attestation, err := appeal.NewApprovalAttestation(signer, a, "approval_1", "approver@email.com", domain.AppealActionNameApprove, time.Now())
+		assert.Nil(t, err)
+		return &domain.Approval{
+			Name:        "approval_1",
+			Attestation: attestation,
+		}
+	}
+
+	t.Run("should return error if approval has no attestation", func(t *testing.T) {
+		actualResult, actualError := appeal.VerifyApprovalAttestation(signer, newAppeal(), &domain.Approval{Name: "approval_1"})
+
+		assert.False(t, actualResult)
+		assert.EqualError(t, actualError, appeal.ErrAttestationNotFound.Error())
+	})
+
+	t.Run("should return true if appeal is unchanged", func(t *testing.T) {
+		a := newAppeal()
+		approval := newAttestedApproval(t, a)
+
+		actualResult, actualError := appeal.VerifyApprovalAttestation(signer, newAppeal(), approval)
+
+		assert.True(t, actualResult)
+		assert.Nil(t, actualError)
+	})
+
+	t.Run("should return false if appeal is changed after the action", func(t *testing.T) {
+		a := newAppeal()
+		approval := newAttestedApproval(t, a)
+		a.Role = "owner"
+
+		actualResult, actualError := appeal.VerifyApprovalAttestation(signer, a, approval)
+
+		assert.False(t, actualResult)
+		assert.Nil(t, actualError)
+	})
+
+	t.Run("should return false if attestation is attributed to another actor", func(t *testing.T) {
+		a := newAppeal()
+		approval := newAttestedApproval(t, a)
+		approval.Attestation.Actor = "another.approver@email.com"
+
+		actualResult, actualError := appeal.VerifyApprovalAttestation(signer, a, approval)
+
+		assert.False(t, actualResult)
+		assert.Nil(t, actualError)
+	})
+
+	t.Run("should return false if attestation is signed with another key", func(t *testing.T) {
+		a := newAppeal()
+		approval := newAttestedApproval(t, a)
+
+		actualResult, actualError := appeal.VerifyApprovalAttestation(crypto.NewHMAC("another-secret"), a, approval)
+
+		assert.False(t, actualResult)
+		assert.Nil(t, actualError)
+	})
+
+	t.Run("should return false if attestation signature is forged from the appeal", func(t *testing.T) {
+		a := newAppeal()
+		approval := newAttestedApproval(t, a)
+		a.Role = "owner"
+		forgedAttestation, err := appeal.NewApprovalAttestation(crypto.NewHMAC("forger-secret"), a, approval.Name, approval.Attestation.Actor, approval.Attestation.Action, approval.Attestation.CreatedAt)
+		assert.Nil(t, err)
+		approval.Attestation = forgedAttestation
+
+		actualResult, actualError := appeal.VerifyApprovalAttestation(signer, a, approval)
+
+		assert.False(t, actualResult)
+		assert.Nil(t, actualError)
+	})
+}
@@ -0,0 +1,91 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+// CancelAbandonedAppeals cancels the pending appeals which policy enables the auto-cancel, once they're
+// pending longer than the policy's age without any action from the approvers. The requesters are notified
+// that they can appeal again if the access is still needed
+func (s *Service) CancelAbandonedAppeals(ctx context.Context) error {
+	logger := s.getLogger(ctx)
+
+	appeals, err := s.getPendingAppealDetails(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.TimeNow()
+	policies := map[string]*domain.Policy{}
+	for _, a := range appeals {
+		pendingFor, err := s.getAutoCancelPendingFor(ctx, a, policies)
+		if err != nil {
+			return err
+		}
+		if pendingFor <= 0 || now.Sub(a.CreatedAt) < pendingFor || isActionedByApprover(a) {
+			continue
+		}
+
+		canceledAppeal, err := s.Cancel(ctx, a.ID, domain.SystemActorName)
+		if err != nil {
+			logger.Error("failed to cancel abandoned appeal", zap.Uint("appeal_id", a.ID), zap.Error(err))
+			continue
+		}
+		if canceledAppeal == nil {
+			continue
+		}
+
+		if err := s.notifier.Notify([]domain.Notification{{
+			User:    a.User,
+			Message: fmt.Sprintf("Your appeal to access %s as %s has been canceled after being pending for %s without any action from the approvers. You can appeal again if the access is still needed.", a.Resource.URN, a.Role, pendingFor),
+			Labels:  a.Labels,
+		}}); err != nil {
+			logger.Error("failed to notify about the canceled appeal", zap.Uint("appeal_id", a.ID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// getAutoCancelPendingFor returns the auto-cancel age of the appeal's policy, or zero if the policy doesn't
+// enable the auto-cancel. The policies are cached by id and version across the calls
+func (s *Service) getAutoCancelPendingFor(ctx context.Context, a *domain.Appeal, policies map[string]*domain.Policy) (time.Duration, error) {
+	if a.PolicyID == "" {
+		return 0, nil
+	}
+
+	key := fmt.Sprintf("%s@%d", a.PolicyID, a.PolicyVersion)
+	p, cached := policies[key]
+	if !cached {
+		var err error
+		if p, err = s.policyService.GetOne(ctx, a.PolicyID, a.PolicyVersion); err != nil {
+			return 0, err
+		}
+		policies[key] = p
+	}
+	if p == nil || p.AutoCancel == nil || !p.AutoCancel.Enabled {
+		return 0, nil
+	}
+
+	pendingFor, err := time.ParseDuration(p.AutoCancel.PendingFor)
+	if err != nil {
+		return 0, fmt.Errorf("parsing auto cancel pending_for of policy %q: %w", key, err)
+	}
+	return pendingFor, nil
+}
+
+// isActionedByApprover returns true if any of the approval steps has been actioned by a user, as opposed
+// to the steps resolved automatically
+func isActionedByApprover(a *domain.Appeal) bool {
+	for _, approval := range a.Approvals {
+		if approval.Actor != nil && *approval.Actor != domain.SystemActorName {
+			return true
+		}
+	}
+	return false
+}
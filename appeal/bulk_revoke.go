@@ -0,0 +1,71 @@
+package appeal
+
+import (
+	"context"
+
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+// PreviewRevoke returns the active appeals RevokeByFilter would terminate with the same filters, along
+// with their resources, without revoking anything
+func (s *Service) PreviewRevoke(ctx context.Context, filters map[string]interface{}) ([]*domain.Appeal, error) {
+	return s.findRevocableAppeals(ctx, filters)
+}
+
+// RevokeByFilter revokes every active appeal matching the filters, e.g. to offboard a user. The filters
+// should narrow down the appeals by resource_id or user unless FilterKeyAllowBroad is set. It returns the
+// revoked appeals along with a *BulkActionError listing the appeals that failed
+func (s *Service) RevokeByFilter(ctx context.Context, filters map[string]interface{}, actor, reason string) ([]*domain.Appeal, error) {
+	logger := s.getLogger(ctx).With(zap.String("actor", actor))
+
+	appeals, err := s.findRevocableAppeals(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*domain.Appeal{}
+	failed := map[uint]error{}
+	for _, a := range appeals {
+		revokedAppeal, err := s.Revoke(ctx, a.ID, actor, reason)
+		if err != nil {
+			failed[a.ID] = err
+			continue
+		}
+		result = append(result, revokedAppeal)
+	}
+
+	logger.Info("appeals revoked by filter", zap.Int("matched", len(appeals)), zap.Int("succeeded", len(result)), zap.Int("failed", len(failed)))
+	if len(failed) > 0 {
+		return result, &BulkActionError{Failed: failed}
+	}
+
+	return result, nil
+}
+
+// findRevocableAppeals returns the active appeals matching the filters of a bulk revoke, with their details
+func (s *Service) findRevocableAppeals(ctx context.Context, filters map[string]interface{}) ([]*domain.Appeal, error) {
+	conditions, err := getBulkConditions(filters, domain.AppealStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	appeals, err := s.repo.Find(ctx, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*domain.Appeal{}
+	for _, a := range appeals {
+		appeal, err := s.repo.GetByID(ctx, a.ID)
+		if err != nil {
+			return nil, err
+		}
+		if appeal == nil || appeal.Status != domain.AppealStatusActive {
+			continue
+		}
+		result = append(result, appeal)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
+)
+
+const defaultAppealableResourcesLimit = 100
+
+// GetAppealableResources returns the resources the user is eligible to appeal for, i.e. the resources of the
+// configured resource types having at least one role the user is allowed to appeal for, excluding the
+// resources on the deny list. The filters are the resource filters, e.g. tags, paginated by limit and offset.
+// The limit defaults to 100
+func (s *Service) GetAppealableResources(ctx context.Context, user string, filters map[string]interface{}) ([]*domain.Resource, error) {
+	if err := s.validator.Var(user, "required,email"); err != nil {
+		return nil, ErrInvalidUser
+	}
+
+	providerConfigs, err := s.getProviderConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the groups are only resolved if any role is restricted to groups
+	var userGroups []string
+	userGroupsResolved := false
+	typeKeys := []domain.ResourceTypeKey{}
+	for providerType, configs := range providerConfigs {
+		for providerURN, pc := range configs {
+			for resourceType, rc := range pc.resources {
+				eligible := false
+				for _, role := range rc.availableRoleIDs {
+					allowedGroups := rc.roleAllowedGroups[role]
+					if len(allowedGroups) > 0 && !userGroupsResolved {
+						if userGroups, err = s.iamService.GetUserGroups(user); err != nil {
+							return nil, fmt.Errorf("resolving the groups of %q: %w", user, err)
+						}
+						userGroupsResolved = true
+					}
+					if len(allowedGroups) == 0 || isRoleEligible(allowedGroups, userGroups) {
+						eligible = true
+						break
+					}
+				}
+				if eligible {
+					typeKeys = append(typeKeys, domain.ResourceTypeKey{
+						ProviderType: providerType,
+						ProviderURN:  providerURN,
+						Type:         resourceType,
+					})
+				}
+			}
+		}
+	}
+	if len(typeKeys) == 0 {
+		return []*domain.Resource{}, nil
+	}
+	sort.Slice(typeKeys, func(i, j int) bool {
+		a, b := typeKeys[i], typeKeys[j]
+		if a.ProviderType != b.ProviderType {
+			return a.ProviderType < b.ProviderType
+		}
+		if a.ProviderURN != b.ProviderURN {
+			return a.ProviderURN < b.ProviderURN
+		}
+		return a.Type < b.Type
+	})
+
+	resourceFilters := map[string]interface{}{}
+	for k, v := range filters {
+		resourceFilters[k] = v
+	}
+	resourceFilters["type_keys"] = typeKeys
+	resourceFilters["deny_appeals"] = false
+	if limit, _ := resourceFilters["limit"].(int); limit <= 0 {
+		resourceFilters["limit"] = defaultAppealableResourcesLimit
+	}
+
+	return s.findResources(ctx, resourceFilters)
+}
+
+// isRoleEligible checks whether the user belongs to any of the groups allowed to appeal for the role
+func isRoleEligible(allowedGroups, userGroups []string) bool {
+	for _, g := range allowedGroups {
+		if utils.ContainsString(userGroups, g) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package appeal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/mcuadros/go-lookup"
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultStepCheckTimeout = 10 * time.Second
+
+	// maxStepCheckResponseSize limits the check responses read for the expected value
+	maxStepCheckResponseSize = 1 << 20
+
+	stepCheckPassedReason = "passed the external check"
+)
+
+// StepCheckInput is the request body of the external check of an approval step
+type StepCheckInput struct {
+	Step   string         `json:"step"`
+	Appeal *domain.Appeal `json:"appeal"`
+}
+
+// StepChecker evaluates the external check of an approval step, it returns whether the check passes
+type StepChecker interface {
+	Check(ctx context.Context, check *domain.StepCheck, input *StepCheckInput) (bool, error)
+}
+
+type httpStepChecker struct {
+	client *http.Client
+}
+
+// NewHTTPStepChecker returns the checker calling the check URL. The timeout is set per check
+func NewHTTPStepChecker() StepChecker {
+	return &httpStepChecker{&http.Client{}}
+}
+
+func (c *httpStepChecker) Check(ctx context.Context, check *domain.StepCheck, input *StepCheckInput) (bool, error) {
+	timeout := defaultStepCheckTimeout
+	if check.Timeout != "" {
+		d, err := time.ParseDuration(check.Timeout)
+		if err != nil {
+			return false, err
+		}
+		timeout = d
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, check.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return false, nil
+	}
+	if check.ResponseField == "" {
+		return true, nil
+	}
+
+	resBody, err := ioutil.ReadAll(io.LimitReader(res.Body, maxStepCheckResponseSize))
+	if err != nil {
+		return false, err
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal(resBody, &response); err != nil {
+		return false, fmt.Errorf("invalid check response: %w", err)
+	}
+	value, err := lookup.LookupString(response, check.ResponseField)
+	if err != nil {
+		return false, nil
+	}
+	expectedValue, err := normalizeJSONValue(check.ExpectedValue)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(value.Interface(), expectedValue), nil
+}
+
+// normalizeJSONValue converts the value into its decoded JSON form, e.g. an int into a float64, to be compared
+// with the values decoded from the responses
+func normalizeJSONValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// runStepChecks auto-approves the pending approval steps whose external check passes, on behalf of the system.
+// A step whose check fails or errors goes to its approvers. It returns whether any step is auto-approved
+func (s *Service) runStepChecks(ctx context.Context, logger *zap.Logger, a *domain.Appeal) bool {
+	if a.Status != domain.AppealStatusPending || a.Policy == nil {
+		return false
+	}
+
+	approved := false
+	for _, approval := range a.Approvals {
+		if approval.Status != domain.ApprovalStatusPending || approval.Index >= len(a.Policy.Steps) {
+			continue
+		}
+		step := a.Policy.Steps[approval.Index]
+		if step.Check == nil {
+			continue
+		}
+
+		passed, err := s.StepChecker.Check(ctx, step.Check, &StepCheckInput{Step: step.Name, Appeal: a})
+		if err != nil {
+			logger.Warn("approval step check failed, falling back to the approvers",
+				zap.String("user", a.User),
+				zap.Uint("resource_id", a.ResourceID),
+				zap.String("step", step.Name),
+				zap.Error(err),
+			)
+			continue
+		}
+		if !passed {
+			continue
+		}
+
+		actor := domain.SystemActorName
+		approval.Status = domain.ApprovalStatusApproved
+		approval.Actor = &actor
+		approval.Reason = stepCheckPassedReason
+		now := s.TimeNow()
+		approval.UpdatedAt = now
+		approval.StatusChangedAt = &now
+		approved = true
+	}
+	return approved
+}
@@ -0,0 +1,102 @@
+package appeal_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPStepChecker(t *testing.T) {
+	// the inputs are received by the server goroutine
+	var inputsMu sync.Mutex
+	var inputs []appeal.StepCheckInput
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input appeal.StepCheckInput
+		json.NewDecoder(r.Body).Decode(&input)
+		inputsMu.Lock()
+		inputs = append(inputs, input)
+		inputsMu.Unlock()
+		switch r.URL.Path {
+		case "/completed":
+			w.Write([]byte(`{"training": {"completed": true, "score": 90}}`))
+		case "/incomplete":
+			w.Write([]byte(`{"training": {"completed": false}}`))
+		case "/failed":
+			w.WriteHeader(http.StatusForbidden)
+		case "/slow":
+			time.Sleep(100 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+	checker := appeal.NewHTTPStepChecker()
+	input := &appeal.StepCheckInput{
+		Step:   "training",
+		Appeal: &domain.Appeal{ID: 1, User: "user@email.com"},
+	}
+
+	testCases := []struct {
+		name           string
+		check          *domain.StepCheck
+		expectedPassed bool
+		expectedError  bool
+	}{
+		{
+			name:           "2xx response without expected value",
+			check:          &domain.StepCheck{URL: server.URL + "/completed"},
+			expectedPassed: true,
+		},
+		{
+			name:           "matching response field",
+			check:          &domain.StepCheck{URL: server.URL + "/completed", ResponseField: "training.completed", ExpectedValue: true},
+			expectedPassed: true,
+		},
+		{
+			name:           "matching numeric response field",
+			check:          &domain.StepCheck{URL: server.URL + "/completed", ResponseField: "training.score", ExpectedValue: 90},
+			expectedPassed: true,
+		},
+		{
+			name:  "mismatching response field",
+			check: &domain.StepCheck{URL: server.URL + "/incomplete", ResponseField: "training.completed", ExpectedValue: true},
+		},
+		{
+			name:  "missing response field",
+			check: &domain.StepCheck{URL: server.URL + "/completed", ResponseField: "training.passed", ExpectedValue: true},
+		},
+		{
+			name:  "non-2xx response",
+			check: &domain.StepCheck{URL: server.URL + "/failed"},
+		},
+		{
+			name:          "timeout",
+			check:         &domain.StepCheck{URL: server.URL + "/slow", Timeout: "10ms"},
+			expectedError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inputsMu.Lock()
+			inputs = nil
+			inputsMu.Unlock()
+
+			actualPassed, actualError := checker.Check(context.Background(), tc.check, input)
+
+			assert.Equal(t, tc.expectedPassed, actualPassed)
+			assert.Equal(t, tc.expectedError, actualError != nil)
+			inputsMu.Lock()
+			defer inputsMu.Unlock()
+			if assert.Len(t, inputs, 1) {
+				assert.Equal(t, "training", inputs[0].Step)
+				assert.Equal(t, "user@email.com", inputs[0].Appeal.User)
+			}
+		})
+	}
+}
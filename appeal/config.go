@@ -0,0 +1,130 @@
+package appeal
+
+import (
+	"strings"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
+)
+
+// Config holds the appeal service configuration
+type Config struct {
+	// Admins are the users allowed to override approval steps
+	Admins []string `mapstructure:"admins"`
+	// VerifyGrantedAccess makes sure the access exists in the provider before activating the appeal
+	VerifyGrantedAccess bool `mapstructure:"verify_granted_access"`
+	// ApprovalAttestation stores an attestation of the appeal state along with every action on approval steps
+	ApprovalAttestation bool `mapstructure:"approval_attestation"`
+	// DefaultPolicy is used for resources that don't have any policy configured in the provider,
+	// either on the resource type or the provider level. Leave it empty to disable the fallback
+	DefaultPolicy DefaultPolicyConfig `mapstructure:"default_policy"`
+	// Attachment limits the supporting documents uploaded to the appeals
+	Attachment AttachmentConfig `mapstructure:"attachment"`
+	// ApproverAllowedDomains are the email domains the resolved approvers must belong to, e.g. company.com.
+	// Leave it empty to accept any domain
+	ApproverAllowedDomains []string `mapstructure:"approver_allowed_domains"`
+	// SLAReportTeamLabel is the appeal label grouping the approval steps by team in the SLA report
+	SLAReportTeamLabel string `mapstructure:"sla_report_team_label" default:"team"`
+	// Warnings configures the rules flagging unusual appeals to the approvers
+	Warnings WarningsConfig `mapstructure:"warnings"`
+	// RegoPolicy is the policy-as-code approving or rejecting the appeals beyond the static approval steps
+	RegoPolicy RegoPolicyConfig `mapstructure:"rego_policy"`
+	// Reminder configures the escalating reminders of the idle approval steps
+	Reminder ReminderConfig `mapstructure:"reminder"`
+	// AccessWebhook calls the webhooks around the access changes in the providers
+	AccessWebhook AccessWebhookConfig `mapstructure:"access_webhook"`
+	// CreateConcurrency is the maximum number of appeals of a batch validated concurrently on creation
+	CreateConcurrency int `mapstructure:"create_concurrency" default:"8"`
+	// LabelSchema restricts the appeal labels to the keys of the schema, validating their values. The labels
+	// are free-form if it's empty
+	LabelSchema []LabelSchemaField `mapstructure:"label_schema"`
+	// RateLimit caps the appeals created by a user within a window
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	// UndoWindow defers granting the access of the approved appeals by the duration, during which the final
+	// approval can be undone. The access is granted right away if it's 0
+	UndoWindow time.Duration `mapstructure:"undo_window"`
+	// Digest configures the periodic digest of the pending approvals
+	Digest DigestConfig `mapstructure:"digest"`
+	// RevocationRetry configures the retry of the failed revocations
+	RevocationRetry RevocationRetryConfig `mapstructure:"revocation_retry"`
+	// NormalizeResourceURN makes the duplicate appeal and active access checks compare the resources by their
+	// provider and URN, normalized with the provider's URN format, rather than only by id. It prevents the
+	// resources re-imported under another id from bypassing the checks
+	NormalizeResourceURN bool `mapstructure:"normalize_resource_urn"`
+}
+
+// AttachmentConfig holds the restrictions of the appeal attachments
+type AttachmentConfig struct {
+	// MaxSize is the maximum attachment size in bytes
+	MaxSize int64 `mapstructure:"max_size" default:"10485760"`
+	// AllowedContentTypes are the accepted media types, e.g. application/pdf. Leave it empty to accept any type
+	AllowedContentTypes []string `mapstructure:"allowed_content_types"`
+}
+
+const (
+	defaultAttachmentMaxSize  int64 = 10 << 20
+	defaultSLAReportTeamLabel       = "team"
+	defaultCreateConcurrency        = 8
+)
+
+// DefaultPolicyConfig refers to the policy used as the fallback
+type DefaultPolicyConfig struct {
+	ID      string `mapstructure:"id"`
+	Version int    `mapstructure:"version"`
+}
+
+func (c *Config) getDefaultPolicy() *domain.PolicyConfig {
+	if c.DefaultPolicy.ID == "" {
+		return nil
+	}
+	return &domain.PolicyConfig{
+		ID:      c.DefaultPolicy.ID,
+		Version: c.DefaultPolicy.Version,
+	}
+}
+
+func (c *Config) isAdmin(user string) bool {
+	return utils.ContainsString(c.Admins, user)
+}
+
+func (c *Config) getAttachmentMaxSize() int64 {
+	if c.Attachment.MaxSize <= 0 {
+		return defaultAttachmentMaxSize
+	}
+	return c.Attachment.MaxSize
+}
+
+func (c *Config) getSLAReportTeamLabel() string {
+	if c.SLAReportTeamLabel == "" {
+		return defaultSLAReportTeamLabel
+	}
+	return c.SLAReportTeamLabel
+}
+
+func (c *Config) getCreateConcurrency() int {
+	if c.CreateConcurrency <= 0 {
+		return defaultCreateConcurrency
+	}
+	return c.CreateConcurrency
+}
+
+func (c *Config) isApproverDomainAllowed(email string) bool {
+	if len(c.ApproverAllowedDomains) == 0 {
+		return true
+	}
+	emailDomain := email[strings.LastIndex(email, "@")+1:]
+	for _, d := range c.ApproverAllowedDomains {
+		if strings.EqualFold(d, emailDomain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) isAttachmentTypeAllowed(contentType string) bool {
+	if len(c.Attachment.AllowedContentTypes) == 0 {
+		return true
+	}
+	return utils.ContainsString(c.Attachment.AllowedContentTypes, contentType)
+}
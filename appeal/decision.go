@@ -0,0 +1,193 @@
+package appeal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/open-policy-agent/opa/rego"
+	"go.uber.org/zap"
+)
+
+const (
+	DecisionPointCreate = "create"
+	DecisionPointAction = "action"
+
+	DecisionApprove         = "approve"
+	DecisionReject          = "reject"
+	DecisionRequireApproval = "require_approval"
+
+	defaultRegoPolicyQuery = "data.guardian.appeal.decision"
+)
+
+// Decision is the outcome of the policy-as-code evaluation. An empty result requires the approval
+// steps the same way as DecisionRequireApproval
+type Decision struct {
+	Result string `json:"result"`
+	Reason string `json:"reason"`
+}
+
+// DecisionInput is the input of the policy-as-code evaluation
+type DecisionInput struct {
+	// Point is where the decision is made, either DecisionPointCreate or DecisionPointAction
+	Point  string         `json:"point"`
+	Appeal *domain.Appeal `json:"appeal"`
+	// Action is the approval action that has just been applied, only set on DecisionPointAction
+	Action *domain.ApprovalAction `json:"action,omitempty"`
+	// DurationSeconds is the requested access duration, 0 for permanent access
+	DurationSeconds int64 `json:"duration_seconds"`
+}
+
+// DecisionEvaluator decides whether an appeal is approved, rejected, or requires the approval
+// steps of its policy. It returns nil if it doesn't make any decision
+type DecisionEvaluator interface {
+	Evaluate(ctx context.Context, input *DecisionInput) (*Decision, error)
+}
+
+// RegoPolicyConfig configures the Rego policy consulted on appeal creation and on every approval
+type RegoPolicyConfig struct {
+	// Path is the Rego policy file. The policy-as-code is disabled if it's empty
+	Path string `mapstructure:"path"`
+	// Query evaluates to the decision object, e.g. {"result": "approve", "reason": "..."}
+	Query string `mapstructure:"query" default:"data.guardian.appeal.decision"`
+}
+
+// NewEvaluator compiles the configured Rego policy. It returns nil if the policy is not configured
+func (c RegoPolicyConfig) NewEvaluator(ctx context.Context) (DecisionEvaluator, error) {
+	if c.Path == "" {
+		return nil, nil
+	}
+	module, err := ioutil.ReadFile(c.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	query := c.Query
+	if query == "" {
+		query = defaultRegoPolicyQuery
+	}
+	return NewRegoEvaluator(ctx, string(module), query)
+}
+
+type regoEvaluator struct {
+	query rego.PreparedEvalQuery
+}
+
+// NewRegoEvaluator compiles the Rego policy module, the query must evaluate to a decision object
+func NewRegoEvaluator(ctx context.Context, module, query string) (DecisionEvaluator, error) {
+	prepared, err := rego.New(
+		rego.Query(query),
+		rego.Module("guardian.rego", module),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRegoPolicy, err)
+	}
+	return &regoEvaluator{prepared}, nil
+}
+
+func (e *regoEvaluator) Evaluate(ctx context.Context, input *DecisionInput) (*Decision, error) {
+	// the rego input is built from the json representation to follow the json field names
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	var inputValue interface{}
+	if err := json.Unmarshal(inputJSON, &inputValue); err != nil {
+		return nil, err
+	}
+
+	rs, err := e.query.Eval(ctx, rego.EvalInput(inputValue))
+	if err != nil {
+		return nil, err
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return nil, nil
+	}
+
+	resultJSON, err := json.Marshal(rs[0].Expressions[0].Value)
+	if err != nil {
+		return nil, err
+	}
+	var decision Decision
+	if err := json.Unmarshal(resultJSON, &decision); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDecision, err)
+	}
+	return &decision, nil
+}
+
+func newDecisionInput(point string, a *domain.Appeal, action *domain.ApprovalAction, now time.Time) *DecisionInput {
+	input := &DecisionInput{
+		Point:  point,
+		Appeal: a,
+		Action: action,
+	}
+	if hasExpirationDate(a) {
+		input.DurationSeconds = int64(a.Options.ExpirationDate.Sub(getAccessStartTime(a, now)).Seconds())
+	}
+	return input
+}
+
+// applyDecision resolves the pending approval steps on behalf of the system if the appeal is
+// approved or rejected by the decision. On rejection, the steps after the rejected one are skipped
+func applyDecision(a *domain.Appeal, d *Decision, now time.Time) error {
+	if d == nil {
+		return nil
+	}
+
+	var approvalStatus string
+	switch d.Result {
+	case DecisionApprove:
+		approvalStatus = domain.ApprovalStatusApproved
+	case DecisionReject:
+		approvalStatus = domain.ApprovalStatusRejected
+		a.Status = domain.AppealStatusRejected
+	case DecisionRequireApproval, "":
+		return nil
+	default:
+		return fmt.Errorf("%w: unrecognized result %q", ErrInvalidDecision, d.Result)
+	}
+
+	for _, approval := range a.Approvals {
+		if approval.Status != domain.ApprovalStatusPending {
+			continue
+		}
+		approval.Status = approvalStatus
+		approval.UpdatedAt = now
+		approval.StatusChangedAt = &now
+		if approvalStatus == domain.ApprovalStatusSkipped {
+			continue
+		}
+
+		actor := domain.SystemActorName
+		approval.Actor = &actor
+		approval.Reason = d.Reason
+		if approvalStatus == domain.ApprovalStatusRejected {
+			approvalStatus = domain.ApprovalStatusSkipped
+		}
+	}
+	return nil
+}
+
+// evaluateDecision returns nil if the policy-as-code is not enabled
+func (s *Service) evaluateDecision(ctx context.Context, point string, a *domain.Appeal, action *domain.ApprovalAction) (*Decision, error) {
+	if s.DecisionEvaluator == nil {
+		return nil, nil
+	}
+
+	decision, err := s.DecisionEvaluator.Evaluate(ctx, newDecisionInput(point, a, action, s.TimeNow()))
+	if err != nil {
+		return nil, err
+	}
+	if decision != nil {
+		s.getLogger(ctx).Info("policy decision evaluated",
+			zap.Uint("appeal_id", a.ID),
+			zap.String("point", point),
+			zap.String("result", decision.Result),
+			zap.String("reason", decision.Reason),
+		)
+	}
+	return decision, nil
+}
@@ -0,0 +1,92 @@
+package appeal_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRegoPolicy = `
+package guardian.appeal
+
+decision = {"result": "approve", "reason": "short viewer access"} {
+	input.point == "create"
+	input.appeal.role == "viewer"
+	input.duration_seconds > 0
+	input.duration_seconds <= 86400
+}
+
+decision = {"result": "reject", "reason": "owner access can't be approved by the requester's team"} {
+	input.point == "action"
+	input.appeal.role == "owner"
+	input.appeal.labels.team == "data"
+}
+`
+
+func TestRegoEvaluator(t *testing.T) {
+	t.Run("should return error if the policy is invalid", func(t *testing.T) {
+		actualResult, actualError := appeal.NewRegoEvaluator(context.Background(), "package guardian.appeal\n\ndecision = {", "data.guardian.appeal.decision")
+
+		assert.Nil(t, actualResult)
+		assert.True(t, errors.Is(actualError, appeal.ErrInvalidRegoPolicy))
+	})
+
+	evaluator, err := appeal.NewRegoEvaluator(context.Background(), testRegoPolicy, "data.guardian.appeal.decision")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	expirationDate := time.Now().Add(12 * time.Hour)
+	testCases := []struct {
+		name             string
+		input            *appeal.DecisionInput
+		expectedDecision *appeal.Decision
+	}{
+		{
+			name: "approve based on the role and the duration",
+			input: &appeal.DecisionInput{
+				Point: appeal.DecisionPointCreate,
+				Appeal: &domain.Appeal{
+					Role:    "viewer",
+					Options: &domain.AppealOptions{ExpirationDate: &expirationDate},
+				},
+				DurationSeconds: 43200,
+			},
+			expectedDecision: &appeal.Decision{Result: appeal.DecisionApprove, Reason: "short viewer access"},
+		},
+		{
+			name: "reject based on the labels",
+			input: &appeal.DecisionInput{
+				Point: appeal.DecisionPointAction,
+				Appeal: &domain.Appeal{
+					Role:   "owner",
+					Labels: map[string]string{"team": "data"},
+				},
+				Action: &domain.ApprovalAction{Action: domain.AppealActionNameApprove},
+			},
+			expectedDecision: &appeal.Decision{Result: appeal.DecisionReject, Reason: "owner access can't be approved by the requester's team"},
+		},
+		{
+			name: "no decision if the policy is undefined",
+			input: &appeal.DecisionInput{
+				Point:  appeal.DecisionPointCreate,
+				Appeal: &domain.Appeal{Role: "viewer"},
+			},
+			expectedDecision: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualDecision, actualError := evaluator.Evaluate(context.Background(), tc.input)
+
+			assert.Nil(t, actualError)
+			assert.Equal(t, tc.expectedDecision, actualDecision)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
+	"go.uber.org/zap"
+)
+
+// getDelegatingApprover returns the approver of the step the actor is allowed to act for, either as their delegate
+// through an active delegation or as a member of the delegation chain of an unavailable approver. It returns an
+// empty string if the actor can't act for any approver. An approver is considered available if the availability
+// can't be checked, consistently with the approver assignment
+func (s *Service) getDelegatingApprover(ctx context.Context, approval *domain.Approval, actor string) (string, error) {
+	if s.DelegationService != nil {
+		delegators, err := s.DelegationService.GetActiveDelegators(ctx, actor)
+		if err != nil {
+			return "", fmt.Errorf("getting the active delegators of %q: %w", actor, err)
+		}
+		for _, approver := range approval.Approvers {
+			if utils.ContainsString(delegators, approver) {
+				return approver, nil
+			}
+		}
+	}
+
+	if approval.DelegationChain == nil {
+		return "", nil
+	}
+
+	for _, approver := range approval.Approvers {
+		isAvailable, err := s.iamService.IsUserAvailable(approver)
+		if err != nil {
+			s.logger.Warn("unable to check approver availability for the delegation chain",
+				zap.String("approval_name", approval.Name),
+				zap.String("approver", approver),
+				zap.Error(err),
+			)
+			continue
+		}
+		if isAvailable {
+			continue
+		}
+
+		chain, err := s.getDelegationChain(approver, approval.DelegationChain)
+		if err != nil {
+			return "", fmt.Errorf("resolving the delegation chain of %q: %w", approver, err)
+		}
+		if utils.ContainsString(chain, actor) {
+			return approver, nil
+		}
+	}
+	return "", nil
+}
+
+// isAllowedActor checks whether the actor is one of the approvers of the step or can act for one of them, either
+// as their delegate or as a member of their delegation chain
+func (s *Service) isAllowedActor(ctx context.Context, approval *domain.Approval, actor string) (bool, error) {
+	if utils.ContainsString(approval.Approvers, actor) {
+		return true, nil
+	}
+	approver, err := s.getDelegatingApprover(ctx, approval, actor)
+	if err != nil {
+		return false, err
+	}
+	return approver != "", nil
+}
+
+// getDelegationChain expands the chain members of the approver in order, without duplicates, up to the chain depth
+func (s *Service) getDelegationChain(approver string, config *domain.DelegationChain) ([]string, error) {
+	chain := []string{}
+	seen := map[string]bool{approver: true}
+	add := func(member string) {
+		if !seen[member] {
+			seen[member] = true
+			chain = append(chain, member)
+		}
+	}
+
+	for _, member := range config.Members {
+		if member != domain.DelegationKeyManagerChain {
+			add(member)
+			continue
+		}
+		managers, err := s.iamService.GetManagerChain(approver)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range managers {
+			add(m)
+		}
+	}
+
+	if config.Depth > 0 && len(chain) > config.Depth {
+		chain = chain[:config.Depth]
+	}
+	return chain, nil
+}
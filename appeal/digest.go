@@ -0,0 +1,91 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
+	"go.uber.org/zap"
+)
+
+// DigestConfig configures the digest of the pending approvals, summarizing the appeals waiting for an approver in
+// a single periodic message instead of a notification for every appeal
+type DigestConfig struct {
+	// Approvers are the approvers opting into the digest. They're still notified right away about the high
+	// priority appeals. The other approvers are notified about every appeal
+	Approvers []string `mapstructure:"approvers"`
+}
+
+func (c DigestConfig) isOptedIn(approver string) bool {
+	return utils.ContainsString(c.Approvers, approver)
+}
+
+// SendPendingApprovalsDigest sends each approver opting into the digest a single notification summarizing the
+// appeals waiting for their approval, along with the priority and the age of each appeal. The approvers without
+// any pending appeal aren't notified
+func (s *Service) SendPendingApprovalsDigest(ctx context.Context) error {
+	if len(s.config.Digest.Approvers) == 0 {
+		return nil
+	}
+
+	appeals, err := s.getPendingAppealDetails(ctx)
+	if err != nil {
+		return err
+	}
+
+	pendingAppeals := map[string][]*domain.Appeal{}
+	for _, a := range appeals {
+		approval := a.GetNextPendingApproval()
+		if approval == nil {
+			continue
+		}
+		for _, approver := range approval.GetNotifiedApprovers() {
+			if s.config.Digest.isOptedIn(approver) {
+				pendingAppeals[approver] = append(pendingAppeals[approver], a)
+			}
+		}
+	}
+
+	approvers := make([]string, 0, len(pendingAppeals))
+	for approver := range pendingAppeals {
+		approvers = append(approvers, approver)
+	}
+	sort.Strings(approvers)
+
+	now := s.TimeNow()
+	notifications := []domain.Notification{}
+	for _, approver := range approvers {
+		sortByPriority(pendingAppeals[approver])
+		notifications = append(notifications, domain.Notification{
+			User:    approver,
+			Message: getDigestMessage(pendingAppeals[approver], now),
+		})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	if err := s.notifier.Notify(notifications); err != nil {
+		return err
+	}
+	s.getLogger(ctx).Info("pending approvals digest sent", zap.Int("approvers", len(notifications)))
+	return nil
+}
+
+func getDigestMessage(appeals []*domain.Appeal, now time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "You have %d appeal(s) pending your approval:", len(appeals))
+	for _, a := range appeals {
+		priority := a.Priority
+		if priority == "" {
+			priority = domain.AppealPriorityNormal
+		}
+		fmt.Fprintf(&sb, "\n- [%s] #%d from %s to access %s as %s, pending for %s",
+			strings.ToUpper(priority), a.ID, a.User, a.Resource.URN, a.Role, now.Sub(a.CreatedAt).Round(time.Minute))
+	}
+	return sb.String()
+}
@@ -1,9 +1,18 @@
 package appeal
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
 
 var (
-	ErrAppealIDEmptyParam = errors.New("appeal id is required")
+	ErrAppealIDEmptyParam       = errors.New("appeal id is required")
+	ErrInvalidUser              = errors.New("user should be a valid email")
+	ErrAccessCheckInvalidParams = errors.New("user, resource id, and role are required")
+	ErrUserEmptyParam           = errors.New("user is required")
 
 	ErrAppealStatusCanceled     = errors.New("appeal already canceled")
 	ErrAppealStatusApproved     = errors.New("appeal already approved")
@@ -11,28 +20,265 @@ var (
 	ErrAppealStatusTerminated   = errors.New("appeal already terminated")
 	ErrAppealStatusUnrecognized = errors.New("unrecognized appeal status")
 	ErrAppealDuplicate          = errors.New("appeal with the same resource and role already exists")
+	ErrGroupIDEmptyParam        = errors.New("appeal group id is required")
+	ErrAppealGroupNotFound      = errors.New("appeal group not found")
 
 	ErrApprovalDependencyIsPending = errors.New("found previous approval step that is still in pending")
 	ErrApprovalStatusApproved      = errors.New("approval already approved")
 	ErrApprovalStatusRejected      = errors.New("approval already rejected")
 	ErrApprovalStatusSkipped       = errors.New("approval already skipped")
 	ErrApprovalStatusUnrecognized  = errors.New("unrecognized approval status")
+	ErrApprovalApproversUnresolved = errors.New("approval step is waiting for its approvers to be resolved")
 	ErrApprovalNameNotFound        = errors.New("approval step name not found")
 
-	ErrActionForbidden    = errors.New("user is not allowed to make action on this approval step")
-	ErrActionInvalidValue = errors.New("invalid action value")
+	ErrActionForbidden           = errors.New("user is not allowed to make action on this approval step")
+	ErrSeparationOfDuties        = errors.New("the same person is not allowed to approve more than one step of the appeal")
+	ErrInvalidApprovalConditions = errors.New("invalid approval conditions")
+	ErrActionInvalidValue        = errors.New("invalid action value")
+	ErrActorMismatch             = errors.New("actor doesn't match the authenticated user")
+	ErrBroadFilter               = errors.New("filter should narrow down the appeals by resource_id or user, otherwise set allow_broad to true")
+
+	ErrOverrideForbidden       = errors.New("only admins are allowed to override approval steps")
+	ErrOverrideReasonRequired  = errors.New("reason is required to override approval step")
+	ErrReasonRequired          = errors.New("reason is required by the policy")
+	ErrChangesCommentRequired  = errors.New("comment explaining the changes needed is required to request changes")
+	ErrResubmitCommentRequired = errors.New("comment describing the changes made is required to resubmit the appeal")
+	ErrResubmitForbidden       = errors.New("only the requester is allowed to resubmit the appeal")
+	ErrChangesNotRequested     = errors.New("changes are not requested on the appeal")
+	ErrInvalidLabels           = errors.New("invalid appeal labels")
+	ErrTeamQuotaExceeded       = errors.New("the requester's team already holds the maximum active grants allowed by the team quota")
+
+	ErrApprovalStepNameRequired     = errors.New("approval step name is required")
+	ErrApprovalStepApproversInvalid = errors.New("approval step requires approvers with valid emails")
+	ErrApprovalStepDuplicate        = errors.New("approval step with the same name already exists")
+	ErrInvalidApprovalStepPosition  = errors.New("invalid approval step position, it should be within the steps and after the approved ones")
+	ErrAddApprovalStepForbidden     = errors.New("only admins and approvers are allowed to add approval steps")
+
+	ErrRevertForbidden           = errors.New("only the approver who approved the approval step is allowed to revert it")
+	ErrRevertApprovalNotApproved = errors.New("only approved approval steps can be reverted")
+	ErrRevertNextStepActioned    = errors.New("unable to revert, the next approval steps have been actioned")
+
+	ErrApprovalAlreadyAcknowledged = errors.New("approval step is already acknowledged by another approver")
+
+	ErrUndoWindowClosed = errors.New("appeal approval can only be undone within the undo window before the access is granted")
+	ErrUndoForbidden    = errors.New("only the approver of the final approval step and the admins are allowed to undo it")
+
+	ErrAccessNotGranted               = errors.New("access is not found in the provider after being granted")
+	ErrAccessHookAborted              = errors.New("access change aborted by the hook")
+	ErrAttestationNotFound            = errors.New("approval doesn't have any attestation")
+	ErrAttestationSignerNotConfigured = errors.New("approval attestation signer is not configured")
+
+	ErrAttachmentStorageNotConfigured = errors.New("attachment storage is not configured")
+	ErrAttachmentFilenameRequired     = errors.New("attachment filename is required")
+	ErrAttachmentTooLarge             = errors.New("attachment exceeds the maximum allowed size")
+	ErrAttachmentTypeNotAllowed       = errors.New("attachment content type is not allowed")
+	ErrAttachmentForbidden            = errors.New("only the requester, the approvers, and the admins are allowed to access the appeal attachments")
+	ErrAttachmentRejected             = errors.New("attachment is rejected by the scanner")
+	ErrAttachmentNotFound             = errors.New("attachment not found")
+
+	ErrInvalidImportHeader     = errors.New("invalid import header")
+	ErrInvalidImportStatus     = errors.New("invalid import status, it should be either pending or active")
+	ErrInvalidImportDate       = errors.New("invalid import date")
+	ErrImportResourceAmbiguous = errors.New("multiple resources match the urn, provider_type is required")
+	ErrImportAccessExpired     = errors.New("active access to be imported has already expired")
+
+	ErrCommentBodyRequired      = errors.New("comment body is required")
+	ErrInvalidCommentVisibility = errors.New("invalid comment visibility")
+	ErrCommentForbidden         = errors.New("user is not allowed to access the comments of this appeal")
+	ErrPrivateCommentForbidden  = errors.New("only approvers are allowed to leave private comments")
 
 	ErrProviderTypeNotFound                = errors.New("provider is not registered")
 	ErrProviderURNNotFound                 = errors.New("provider with specified urn is not registered")
 	ErrResourceTypeNotFound                = errors.New("unable to find matching resource config for specified resource type")
 	ErrOptionsExpirationDateOptionNotFound = errors.New("expiration date is required, unable to find expiration date option")
 	ErrInvalidRole                         = errors.New("invalid role")
+	ErrRoleNotEligible                     = errors.New("user is not a member of any of the groups allowed to appeal for the role")
+	ErrRoleIncompatibleWithResource        = errors.New("role is not compatible with the resource")
+	ErrSensitivityTierNotFound             = errors.New("policy has no sensitivity tier for the role sensitivity")
+	ErrInvalidPriority                     = errors.New("invalid priority")
+	ErrInvalidAccessWindow                 = errors.New("invalid access window")
+	ErrInvalidStartDate                    = errors.New("invalid start date")
+	ErrInvalidRegoPolicy                   = errors.New("invalid rego policy")
+	ErrInvalidDecision                     = errors.New("invalid policy decision")
+	ErrPermanentAccessNotSupported         = errors.New("permanent access is not supported by the provider")
 	ErrExpirationDateIsRequired            = errors.New("having permanent access to this resource is not allowed, access duration is required")
 	ErrPolicyIDNotFound                    = errors.New("unable to find approval policy for specified id")
 	ErrPolicyVersionNotFound               = errors.New("unable to find approval policy for specified version")
 	ErrResourceNotFound                    = errors.New("resource not found")
+	ErrInvalidResourceIdentifier           = errors.New("resource is identified by either its id or its provider_type, provider_urn, and urn")
+	ErrResourceNotAppealable               = errors.New("resource is on the deny list, appeals to it are not allowed")
 	ErrAppealNotFound                      = errors.New("appeal not found")
+	ErrOrganizationMismatch                = errors.New("appeal belongs to another organization")
+	ErrAppealNotDeadlocked                 = errors.New("appeal current approval step already has approvers")
+	ErrAppealNotActive                     = errors.New("only active appeals can be revoked")
+	ErrDurationExceedsRoleMax              = errors.New("requested access duration exceeds the max duration of the role")
+	ErrInvalidMaxDuration                  = errors.New("invalid max duration")
+	ErrInvalidAccessReviewPeriod           = errors.New("access review period requires a from date before the to date")
+	ErrAppealRateLimited                   = errors.New("too many appeals created")
 
 	ErrApproverKeyNotRecognized = errors.New("unrecognized approvers key")
 	ErrApproverInvalidType      = errors.New("invalid approver type, expected an email or array of email")
+	ErrInvalidStepMinDuration   = errors.New("invalid approval step min duration")
+	ErrInvalidStepSLA           = errors.New("invalid approval step sla")
+	ErrApproversNotFound        = errors.New("unable to resolve any approver for the approval step")
+	ErrApproverDomainNotAllowed = errors.New("approver email domain is not allowed")
 )
+
+// InvalidRoleError is returned when the requested role is not available for the resource type.
+// It wraps ErrInvalidRole and carries the available roles so the client can suggest valid options
+type InvalidRoleError struct {
+	ResourceType   string
+	Role           string
+	AvailableRoles []string
+}
+
+func (e *InvalidRoleError) Error() string {
+	return fmt.Sprintf("%s: %q is not available for resource type %q, available roles: %s",
+		ErrInvalidRole, e.Role, e.ResourceType, strings.Join(e.AvailableRoles, ", "))
+}
+
+func (e *InvalidRoleError) Unwrap() error {
+	return ErrInvalidRole
+}
+
+// ResourceTypeNotFoundError is returned when the resource type doesn't match any of the configured resource
+// types or their aliases. It wraps ErrResourceTypeNotFound and lists the valid types and aliases
+type ResourceTypeNotFoundError struct {
+	ResourceType   string
+	AvailableTypes []string
+}
+
+func (e *ResourceTypeNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %q, available resource types: %s",
+		ErrResourceTypeNotFound, e.ResourceType, strings.Join(e.AvailableTypes, ", "))
+}
+
+func (e *ResourceTypeNotFoundError) Unwrap() error {
+	return ErrResourceTypeNotFound
+}
+
+// UnresolvedResourceError is returned when the resource identified by its provider and urn doesn't exist. It
+// wraps ErrResourceNotFound
+type UnresolvedResourceError struct {
+	ProviderType string
+	ProviderURN  string
+	URN          string
+}
+
+func (e *UnresolvedResourceError) Error() string {
+	return fmt.Sprintf("%s: %q of provider %q with urn %q", ErrResourceNotFound, e.URN, e.ProviderType, e.ProviderURN)
+}
+
+func (e *UnresolvedResourceError) Unwrap() error {
+	return ErrResourceNotFound
+}
+
+// RateLimitError is returned when the user exceeds the appeal creation rate limit. It wraps ErrAppealRateLimited
+// and carries the duration to wait for before creating the appeals again
+type RateLimitError struct {
+	User       string
+	MaxAppeals int
+	Window     time.Duration
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s: %q is allowed to create %d appeal(s) per %s, retry after %s",
+		ErrAppealRateLimited, e.User, e.MaxAppeals, e.Window, e.RetryAfter.Round(time.Second))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrAppealRateLimited
+}
+
+// BulkActionError summarizes the appeals that failed to be processed in a bulk action
+type BulkActionError struct {
+	Failed map[uint]error
+}
+
+func (e *BulkActionError) Error() string {
+	ids := make([]uint, 0, len(e.Failed))
+	for id := range e.Failed {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	messages := make([]string, 0, len(ids))
+	for _, id := range ids {
+		messages = append(messages, fmt.Sprintf("appeal %d: %s", id, e.Failed[id]))
+	}
+	return fmt.Sprintf("failed to make action on %d appeal(s): %s", len(ids), strings.Join(messages, "; "))
+}
+
+// BatchCreateError summarizes the appeals of a batch that failed to be created, by their index in the batch
+type BatchCreateError struct {
+	Failed map[int]error
+}
+
+// newBatchCreateError returns the only error as is, or a *BatchCreateError if more than one appeal failed
+func newBatchCreateError(errs []error) error {
+	failed := map[int]error{}
+	var firstErr error
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		failed[i] = err
+	}
+	if len(failed) <= 1 {
+		return firstErr
+	}
+	return &BatchCreateError{Failed: failed}
+}
+
+func (e *BatchCreateError) Error() string {
+	indexes := make([]int, 0, len(e.Failed))
+	for i := range e.Failed {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	messages := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		messages = append(messages, fmt.Sprintf("appeal #%d: %s", i, e.Failed[i]))
+	}
+	return fmt.Sprintf("failed to create %d appeal(s): %s", len(indexes), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the error of the first failed appeal in the batch
+func (e *BatchCreateError) Unwrap() error {
+	first := -1
+	for i := range e.Failed {
+		if first == -1 || i < first {
+			first = i
+		}
+	}
+	return e.Failed[first]
+}
+
+// InvalidLabelsError is returned when the appeal labels don't match the label schema. It wraps
+// ErrInvalidLabels and carries the reason of each invalid label by key
+type InvalidLabelsError struct {
+	Fields map[string]string
+}
+
+func (e *InvalidLabelsError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	messages := make([]string, 0, len(keys))
+	for _, key := range keys {
+		messages = append(messages, fmt.Sprintf("%s %s", key, e.Fields[key]))
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidLabels, strings.Join(messages, "; "))
+}
+
+func (e *InvalidLabelsError) Unwrap() error {
+	return ErrInvalidLabels
+}
@@ -0,0 +1,170 @@
+package appeal
+
+import (
+	"sync"
+
+	"github.com/odpf/guardian/domain"
+)
+
+const (
+	defaultSubscriptionBufferSize = 32
+	defaultEventHistorySize       = 1024
+)
+
+// StatusBroker fans the appeal status events out to the subscribers of the appeals, in process. It keeps the
+// recent events for the subscribers resuming after a disconnect. A subscriber not keeping up with its events is
+// closed instead of blocking the publisher, it resumes from the last event it received
+type StatusBroker struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []domain.AppealStatusEvent
+	historySize int
+	bufferSize  int
+	subscribers map[*Subscription]struct{}
+}
+
+// NewStatusBroker returns a broker buffering bufferSize events per subscriber and keeping the last historySize
+// events to resume from. The defaults are used for the non-positive sizes
+func NewStatusBroker(bufferSize, historySize int) *StatusBroker {
+	if bufferSize <= 0 {
+		bufferSize = defaultSubscriptionBufferSize
+	}
+	if historySize <= 0 {
+		historySize = defaultEventHistorySize
+	}
+	return &StatusBroker{
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Subscription receives the status events of a set of appeals
+type Subscription struct {
+	broker    *StatusBroker
+	appealIDs map[uint]bool
+	events    chan domain.AppealStatusEvent
+	closed    bool
+	// overflowed is set if the subscription is closed for not keeping up with its events
+	overflowed bool
+}
+
+// Events returns the channel of the events, closed once the subscription is closed
+func (sub *Subscription) Events() <-chan domain.AppealStatusEvent {
+	return sub.events
+}
+
+// Overflowed checks whether the subscription is closed by the broker for not keeping up with its events
+func (sub *Subscription) Overflowed() bool {
+	sub.broker.mu.Lock()
+	defer sub.broker.mu.Unlock()
+	return sub.overflowed
+}
+
+// Close unsubscribes, it's safe to call more than once
+func (sub *Subscription) Close() {
+	sub.broker.mu.Lock()
+	defer sub.broker.mu.Unlock()
+	sub.broker.remove(sub)
+}
+
+// Publish assigns the event the next id and sends it to the subscribers of its appeal
+func (b *StatusBroker) Publish(event domain.AppealStatusEvent) domain.AppealStatusEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		if !sub.appealIDs[event.AppealID] {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			sub.overflowed = true
+			b.remove(sub)
+		}
+	}
+	return event
+}
+
+// Subscribe subscribes to the status events of the appeals. If lastEventID is set, the events published after it
+// are sent first. It returns false along with the subscription if some of those events are no longer kept, e.g. the
+// subscriber has been away for too long or the service restarted, for the subscriber to fetch the appeals again
+func (b *StatusBroker) Subscribe(appealIDs []uint, lastEventID uint64) (*Subscription, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ids := map[uint]bool{}
+	for _, id := range appealIDs {
+		ids[id] = true
+	}
+
+	missed := []domain.AppealStatusEvent{}
+	complete := true
+	if lastEventID > 0 {
+		if lastEventID > b.lastID || (len(b.history) > 0 && lastEventID < b.history[0].ID-1) {
+			complete = false
+		}
+		for _, e := range b.history {
+			if e.ID > lastEventID && ids[e.AppealID] {
+				missed = append(missed, e)
+			}
+		}
+	}
+
+	sub := &Subscription{
+		broker:    b,
+		appealIDs: ids,
+		events:    make(chan domain.AppealStatusEvent, b.bufferSize+len(missed)),
+	}
+	for _, e := range missed {
+		sub.events <- e
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub, complete
+}
+
+// SubscriberCount returns the number of the open subscriptions
+func (b *StatusBroker) SubscriberCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// remove closes the subscription, the caller holds the lock
+func (b *StatusBroker) remove(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
+
+// publishStatusEvent publishes the change made on the appeal if the broker is set
+func (s *Service) publishStatusEvent(a *domain.Appeal, previousStatus, action, approvalName, actor string) {
+	if s.StatusBroker == nil {
+		return
+	}
+	s.StatusBroker.Publish(*s.newStatusEvent(a, previousStatus, action, approvalName, actor))
+}
+
+// newStatusEvent returns the event of the change made on the appeal, its id is assigned once it's published
+func (s *Service) newStatusEvent(a *domain.Appeal, previousStatus, action, approvalName, actor string) *domain.AppealStatusEvent {
+	return &domain.AppealStatusEvent{
+		AppealID:       a.ID,
+		Status:         a.Status,
+		PreviousStatus: previousStatus,
+		Action:         action,
+		ApprovalName:   approvalName,
+		Actor:          actor,
+		Time:           s.TimeNow(),
+	}
+}
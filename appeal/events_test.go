@@ -0,0 +1,86 @@
+package appeal_test
+
+import (
+	"testing"
+
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusBroker(t *testing.T) {
+	t.Run("should only send the events of the subscribed appeals", func(t *testing.T) {
+		b := appeal.NewStatusBroker(0, 0)
+		sub, complete := b.Subscribe([]uint{1, 2}, 0)
+		defer sub.Close()
+
+		b.Publish(domain.AppealStatusEvent{AppealID: 1, Status: domain.AppealStatusActive})
+		b.Publish(domain.AppealStatusEvent{AppealID: 3, Status: domain.AppealStatusActive})
+		b.Publish(domain.AppealStatusEvent{AppealID: 2, Status: domain.AppealStatusRejected})
+
+		assert.True(t, complete)
+		assert.Equal(t, domain.AppealStatusEvent{ID: 1, AppealID: 1, Status: domain.AppealStatusActive}, <-sub.Events())
+		assert.Equal(t, domain.AppealStatusEvent{ID: 3, AppealID: 2, Status: domain.AppealStatusRejected}, <-sub.Events())
+		assert.Len(t, sub.Events(), 0)
+	})
+
+	t.Run("should resume from the last event id", func(t *testing.T) {
+		b := appeal.NewStatusBroker(0, 0)
+		for i := 0; i < 3; i++ {
+			b.Publish(domain.AppealStatusEvent{AppealID: 1})
+		}
+
+		sub, complete := b.Subscribe([]uint{1}, 1)
+		defer sub.Close()
+
+		assert.True(t, complete)
+		assert.Equal(t, uint64(2), (<-sub.Events()).ID)
+		assert.Equal(t, uint64(3), (<-sub.Events()).ID)
+	})
+
+	t.Run("should report the missed events no longer kept", func(t *testing.T) {
+		b := appeal.NewStatusBroker(0, 2)
+		for i := 0; i < 5; i++ {
+			b.Publish(domain.AppealStatusEvent{AppealID: 1})
+		}
+
+		sub, complete := b.Subscribe([]uint{1}, 1)
+		defer sub.Close()
+		_, completeAfterRestart := b.Subscribe([]uint{1}, 10)
+
+		assert.False(t, complete)
+		assert.False(t, completeAfterRestart)
+		assert.Equal(t, uint64(4), (<-sub.Events()).ID)
+		assert.Equal(t, uint64(5), (<-sub.Events()).ID)
+	})
+
+	t.Run("should close the subscriber falling behind without blocking the publisher", func(t *testing.T) {
+		b := appeal.NewStatusBroker(1, 0)
+		slow, _ := b.Subscribe([]uint{1}, 0)
+		other, _ := b.Subscribe([]uint{1}, 0)
+		defer other.Close()
+
+		b.Publish(domain.AppealStatusEvent{AppealID: 1})
+		<-other.Events()
+		b.Publish(domain.AppealStatusEvent{AppealID: 1})
+
+		assert.True(t, slow.Overflowed())
+		assert.False(t, other.Overflowed())
+		assert.Equal(t, 1, b.SubscriberCount())
+		_, ok := <-slow.Events()
+		assert.True(t, ok)
+		_, ok = <-slow.Events()
+		assert.False(t, ok)
+	})
+
+	t.Run("should unsubscribe on close", func(t *testing.T) {
+		b := appeal.NewStatusBroker(0, 0)
+		sub, _ := b.Subscribe([]uint{1}, 0)
+
+		sub.Close()
+		sub.Close()
+
+		assert.Equal(t, 0, b.SubscriberCount())
+		assert.False(t, sub.Overflowed())
+	})
+}
@@ -0,0 +1,228 @@
+package appeal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
+	"go.uber.org/zap"
+)
+
+// newGroupID returns a random id grouping the appeals created together
+func newGroupID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// MakeGroupAction makes the same action on every appeal of the group which current approval step is
+// the action's approval name and waiting for the actor. The action's appeal id is ignored. The other
+// appeals, e.g. already approved or waiting for another approver, are skipped. A failure on an appeal,
+// e.g. while granting the access, doesn't stop the action on the others, the outcome of every appeal
+// is reported in the result
+func (s *Service) MakeGroupAction(ctx context.Context, groupID string, approvalAction domain.ApprovalAction) (*domain.GroupActionResult, error) {
+	logger := s.getLogger(ctx).With(
+		zap.String("group_id", groupID),
+		zap.String("approval_name", approvalAction.ApprovalName),
+		zap.String("actor", approvalAction.Actor),
+		zap.String("action", approvalAction.Action),
+	)
+
+	if groupID == "" {
+		return nil, ErrGroupIDEmptyParam
+	}
+
+	appeals, err := s.repo.Find(ctx, map[string]interface{}{"group_id": groupID})
+	if err != nil {
+		return nil, err
+	}
+	if len(appeals) == 0 {
+		return nil, ErrAppealGroupNotFound
+	}
+
+	result := &domain.GroupActionResult{
+		GroupID:  groupID,
+		Actioned: []*domain.Appeal{},
+		Skipped:  map[uint]string{},
+		Failed:   map[uint]string{},
+	}
+	for _, a := range appeals {
+		action := approvalAction
+		action.AppealID = a.ID
+		if err := validateApprovalAction(action); err != nil {
+			return nil, err
+		}
+
+		if err := checkIfAppealStatusStillPending(a.Status); err != nil {
+			result.Skipped[a.ID] = err.Error()
+			continue
+		}
+
+		appeal, err := s.repo.GetByID(ctx, a.ID)
+		if err != nil {
+			result.Failed[a.ID] = err.Error()
+			continue
+		}
+		if appeal == nil {
+			result.Skipped[a.ID] = ErrAppealNotFound.Error()
+			continue
+		}
+
+		approval := appeal.GetNextPendingApproval()
+		if approval == nil || approval.Name != action.ApprovalName {
+			result.Skipped[a.ID] = fmt.Sprintf("current approval step is not %q", action.ApprovalName)
+			continue
+		}
+		isAllowed, err := s.isAllowedActor(ctx, approval, action.Actor)
+		if err != nil {
+			result.Failed[a.ID] = err.Error()
+			continue
+		}
+		if !isAllowed {
+			result.Skipped[a.ID] = ErrActionForbidden.Error()
+			continue
+		}
+
+		updatedAppeal, err := s.applyApprovalAction(ctx, appeal, action, false)
+		if err != nil {
+			result.Failed[a.ID] = err.Error()
+			continue
+		}
+		result.Actioned = append(result.Actioned, updatedAppeal)
+	}
+
+	logger.Info("action made on appeal group",
+		zap.Int("appeals", len(appeals)),
+		zap.Int("actioned", len(result.Actioned)),
+		zap.Int("skipped", len(result.Skipped)),
+		zap.Int("failed", len(result.Failed)),
+	)
+	return result, nil
+}
+
+// getGroupSubmittedNotifications summarizes the appeals created together for each of their requesters, along with
+// the approvers the pending ones are waiting for. The appeals created alone aren't summarized
+func getGroupSubmittedNotifications(appeals []*domain.Appeal) []domain.Notification {
+	if len(appeals) < 2 {
+		return nil
+	}
+
+	users := []string{}
+	appealsByUser := map[string][]*domain.Appeal{}
+	for _, a := range appeals {
+		if appealsByUser[a.User] == nil {
+			users = append(users, a.User)
+		}
+		appealsByUser[a.User] = append(appealsByUser[a.User], a)
+	}
+
+	notifications := []domain.Notification{}
+	for _, user := range users {
+		userAppeals := appealsByUser[user]
+		if len(userAppeals) < 2 {
+			continue
+		}
+
+		approved, rejected, pending := 0, 0, 0
+		approvers := []string{}
+		for _, a := range userAppeals {
+			switch a.Status {
+			case domain.AppealStatusActive:
+				approved++
+			case domain.AppealStatusRejected:
+				rejected++
+			default:
+				pending++
+				if approval := a.GetNextPendingApproval(); approval != nil {
+					for _, approver := range approval.GetNotifiedApprovers() {
+						if !utils.ContainsString(approvers, approver) {
+							approvers = append(approvers, approver)
+						}
+					}
+				}
+			}
+		}
+		sort.Strings(approvers)
+		awaiting := "awaiting approval"
+		if len(approvers) > 0 {
+			awaiting = fmt.Sprintf("%s from %s", awaiting, strings.Join(approvers, ", "))
+		}
+
+		var message string
+		if pending == len(userAppeals) {
+			message = fmt.Sprintf("%d appeals submitted, %s", len(userAppeals), awaiting)
+		} else {
+			parts := []string{}
+			if approved > 0 {
+				parts = append(parts, fmt.Sprintf("%d approved", approved))
+			}
+			if rejected > 0 {
+				parts = append(parts, fmt.Sprintf("%d rejected", rejected))
+			}
+			if pending > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", pending, awaiting))
+			}
+			message = fmt.Sprintf("%d appeals submitted: %s", len(userAppeals), strings.Join(parts, ", "))
+		}
+		notifications = append(notifications, domain.Notification{
+			User:    user,
+			Message: message,
+			Labels:  userAppeals[0].Labels,
+		})
+	}
+	return notifications
+}
+
+// getGroupResolvedNotifications summarizes the appeals the requester created together with the appeal, once the
+// appeal was the last of them pending. It's called after the appeal leaves the pending status, with the repository
+// of the transaction storing it if any
+func (s *Service) getGroupResolvedNotifications(ctx context.Context, repo domain.AppealRepository, appeal *domain.Appeal) []domain.Notification {
+	if appeal.GroupID == "" {
+		return nil
+	}
+
+	appeals, err := repo.Find(ctx, map[string]interface{}{"group_id": appeal.GroupID})
+	if err != nil {
+		s.getLogger(ctx).Error("failed to get the appeal group", zap.String("group_id", appeal.GroupID), zap.Error(err))
+		return nil
+	}
+
+	total, approved, rejected, canceled := 0, 0, 0, 0
+	for _, a := range appeals {
+		if a.User != appeal.User {
+			continue
+		}
+		status := a.Status
+		if a.ID == appeal.ID {
+			status = appeal.Status
+		}
+		total++
+		switch status {
+		case domain.AppealStatusPending:
+			return nil
+		case domain.AppealStatusRejected:
+			rejected++
+		case domain.AppealStatusCanceled:
+			canceled++
+		default:
+			// the access may have been revoked since, it's still an approved appeal
+			approved++
+		}
+	}
+	if total < 2 {
+		return nil
+	}
+
+	return []domain.Notification{{
+		User:    appeal.User,
+		Message: fmt.Sprintf("All %d appeals you submitted together are resolved: %d approved, %d rejected, %d canceled", total, approved, rejected, canceled),
+		Labels:  appeal.Labels,
+	}}
+}
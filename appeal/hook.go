@@ -0,0 +1,164 @@
+package appeal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	AccessOperationGrant  = "grant"
+	AccessOperationRevoke = "revoke"
+
+	AccessHookPhasePre  = "pre"
+	AccessHookPhasePost = "post"
+
+	defaultAccessWebhookTimeout = 10 * time.Second
+)
+
+// AccessHookEvent is an access change in the provider
+type AccessHookEvent struct {
+	// Operation is either AccessOperationGrant or AccessOperationRevoke
+	Operation string         `json:"operation"`
+	Appeal    *domain.Appeal `json:"appeal"`
+}
+
+// AccessHook runs custom logic around the access changes in the providers, e.g. creating a ticket or
+// notifying a compliance system. The compensating changes restoring the provider state after a failed
+// appeal update don't run the hooks
+type AccessHook interface {
+	// Before is called before the access change. An error aborts the operation
+	Before(ctx context.Context, e *AccessHookEvent) error
+	// After is called once the access is changed. It's best-effort, the error is only logged
+	After(ctx context.Context, e *AccessHookEvent) error
+}
+
+// AccessHookFuncs adapts Go callbacks into an AccessHook. Nil callbacks are skipped
+type AccessHookFuncs struct {
+	BeforeFunc func(ctx context.Context, e *AccessHookEvent) error
+	AfterFunc  func(ctx context.Context, e *AccessHookEvent) error
+}
+
+func (h *AccessHookFuncs) Before(ctx context.Context, e *AccessHookEvent) error {
+	if h.BeforeFunc == nil {
+		return nil
+	}
+	return h.BeforeFunc(ctx, e)
+}
+
+func (h *AccessHookFuncs) After(ctx context.Context, e *AccessHookEvent) error {
+	if h.AfterFunc == nil {
+		return nil
+	}
+	return h.AfterFunc(ctx, e)
+}
+
+// AccessWebhookConfig configures the HTTP webhooks called around the access changes. The webhooks
+// receive the phase, the operation, and the appeal as a JSON POST request
+type AccessWebhookConfig struct {
+	// PreURL is called before the access change, a non-2xx response aborts the operation
+	PreURL string `mapstructure:"pre_url"`
+	// PostURL is called once the access is changed
+	PostURL string        `mapstructure:"post_url"`
+	Timeout time.Duration `mapstructure:"timeout" default:"10s"`
+}
+
+type accessWebhookPayload struct {
+	Phase string `json:"phase"`
+	*AccessHookEvent
+}
+
+type accessWebhook struct {
+	preURL  string
+	postURL string
+	client  *http.Client
+}
+
+// NewAccessWebhook returns the hook calling the configured webhooks, or nil if none is configured
+func NewAccessWebhook(c AccessWebhookConfig) AccessHook {
+	if c.PreURL == "" && c.PostURL == "" {
+		return nil
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultAccessWebhookTimeout
+	}
+	return &accessWebhook{
+		preURL:  c.PreURL,
+		postURL: c.PostURL,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (h *accessWebhook) Before(ctx context.Context, e *AccessHookEvent) error {
+	return h.call(ctx, h.preURL, AccessHookPhasePre, e)
+}
+
+func (h *accessWebhook) After(ctx context.Context, e *AccessHookEvent) error {
+	return h.call(ctx, h.postURL, AccessHookPhasePost, e)
+}
+
+func (h *accessWebhook) call(ctx context.Context, url, phase string, e *AccessHookEvent) error {
+	if url == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(accessWebhookPayload{phase, e})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("%s webhook responded with status %d", phase, res.StatusCode)
+	}
+	return nil
+}
+
+// changeAccess runs the access change in the provider along with the access hooks
+func (s *Service) changeAccess(ctx context.Context, operation string, a *domain.Appeal, change func() error) (err error) {
+	start := time.Now()
+	defer func() {
+		tags := getResourceTags(a)
+		tags["operation"] = operation
+		s.recordDuration(metrics.AccessChangeDuration, start, err, tags)
+	}()
+
+	e := &AccessHookEvent{Operation: operation, Appeal: a}
+	for _, h := range s.AccessHooks {
+		if err := h.Before(ctx, e); err != nil {
+			return fmt.Errorf("%w: %v", ErrAccessHookAborted, err)
+		}
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	for _, h := range s.AccessHooks {
+		if err := h.After(ctx, e); err != nil {
+			s.getLogger(ctx).Error("access post-hook failed",
+				zap.Uint("appeal_id", a.ID),
+				zap.String("operation", operation),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
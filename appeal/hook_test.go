@@ -0,0 +1,58 @@
+package appeal_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessWebhook(t *testing.T) {
+	t.Run("should return nil if no webhook is configured", func(t *testing.T) {
+		assert.Nil(t, appeal.NewAccessWebhook(appeal.AccessWebhookConfig{}))
+	})
+
+	var payloads []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+		if r.URL.Path == "/reject" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+	e := &appeal.AccessHookEvent{
+		Operation: appeal.AccessOperationGrant,
+		Appeal:    &domain.Appeal{ID: 1, User: "user@email.com"},
+	}
+
+	t.Run("should call the webhooks with the phase and the event", func(t *testing.T) {
+		payloads = nil
+		hook := appeal.NewAccessWebhook(appeal.AccessWebhookConfig{
+			PreURL:  server.URL + "/pre",
+			PostURL: server.URL + "/post",
+		})
+
+		assert.Nil(t, hook.Before(context.Background(), e))
+		assert.Nil(t, hook.After(context.Background(), e))
+		if assert.Len(t, payloads, 2) {
+			assert.Equal(t, "pre", payloads[0]["phase"])
+			assert.Equal(t, "post", payloads[1]["phase"])
+			assert.Equal(t, "grant", payloads[1]["operation"])
+			assert.Equal(t, "user@email.com", payloads[1]["appeal"].(map[string]interface{})["user"])
+		}
+	})
+
+	t.Run("should return error on non-2xx response", func(t *testing.T) {
+		hook := appeal.NewAccessWebhook(appeal.AccessWebhookConfig{PreURL: server.URL + "/reject"})
+
+		assert.Error(t, hook.Before(context.Background(), e))
+		assert.Nil(t, hook.After(context.Background(), e))
+	})
+}
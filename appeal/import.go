@@ -0,0 +1,261 @@
+package appeal
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	importColumnUser           = "user"
+	importColumnResourceURN    = "resource_urn"
+	importColumnRole           = "role"
+	importColumnExpirationDate = "expiration_date"
+	importColumnStatus         = "status"
+	importColumnProviderType   = "provider_type"
+)
+
+var importRequiredColumns = []string{importColumnUser, importColumnResourceURN, importColumnRole}
+
+// ImportReport is the outcome of an appeal import, one result per row
+type ImportReport struct {
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Rows      []*ImportRowResult `json:"rows"`
+}
+
+// ImportRowResult is the outcome of a single imported row. Error is empty if the appeal is created
+type ImportRowResult struct {
+	// Line is the row number in the CSV, the header being line 1
+	Line        int    `json:"line"`
+	User        string `json:"user"`
+	ResourceURN string `json:"resource_urn"`
+	Role        string `json:"role"`
+	AppealID    uint   `json:"appeal_id,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type importRow struct {
+	line           int
+	user           string
+	resourceURN    string
+	role           string
+	expirationDate string
+	status         string
+	providerType   string
+}
+
+// Import creates appeals from CSV rows. The header names the columns: user, resource_urn, and role are
+// required, while expiration_date (RFC 3339 or YYYY-MM-DD), status (pending or active), and provider_type
+// are optional. The pending rows go through the same validation and approval flow as Create. The active
+// rows represent existing access, they're stored as active appeals without approval steps and the access
+// isn't granted again. The rows are imported independently, the failures are reported per row and an
+// error is only returned if the CSV can't be read
+func (s *Service) Import(ctx context.Context, rows io.Reader) (*ImportReport, error) {
+	importRows, err := readImportRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	urns := []string{}
+	for _, row := range importRows {
+		if !utils.ContainsString(urns, row.resourceURN) {
+			urns = append(urns, row.resourceURN)
+		}
+	}
+	resources := map[string][]*domain.Resource{}
+	if len(urns) > 0 {
+		rs, err := s.findResources(ctx, map[string]interface{}{"urns": urns})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			resources[r.URN] = append(resources[r.URN], r)
+		}
+	}
+	providerConfigs, err := s.getProviderConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := s.getLogger(ctx)
+	report := &ImportReport{Rows: []*ImportRowResult{}}
+	for _, row := range importRows {
+		result := &ImportRowResult{
+			Line:        row.line,
+			User:        row.user,
+			ResourceURN: row.resourceURN,
+			Role:        row.role,
+		}
+		report.Rows = append(report.Rows, result)
+
+		a, err := s.importRow(ctx, row, resources[row.resourceURN], providerConfigs)
+		if err != nil {
+			logger.Warn("failed to import appeal", zap.Int("line", row.line), zap.Error(err))
+			result.Error = err.Error()
+			report.Failed++
+			continue
+		}
+		result.AppealID = a.ID
+		result.Status = a.Status
+		report.Succeeded++
+	}
+
+	return report, nil
+}
+
+func (s *Service) importRow(ctx context.Context, row *importRow, resources []*domain.Resource, providerConfigs map[string]map[string]*providerConfig) (*domain.Appeal, error) {
+	if err := s.validator.Var(row.user, "required,email"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	}
+
+	var resource *domain.Resource
+	for _, r := range resources {
+		if row.providerType != "" && r.ProviderType != row.providerType {
+			continue
+		}
+		if resource != nil {
+			return nil, ErrImportResourceAmbiguous
+		}
+		resource = r
+	}
+	if resource == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	a := &domain.Appeal{
+		User:       row.user,
+		ResourceID: resource.ID,
+		Role:       row.role,
+	}
+	if row.expirationDate != "" {
+		expirationDate, err := parseImportDate(row.expirationDate)
+		if err != nil {
+			return nil, err
+		}
+		a.Options = &domain.AppealOptions{ExpirationDate: &expirationDate}
+	}
+
+	switch row.status {
+	case "", domain.AppealStatusPending:
+		if err := s.Create(withoutRateLimit(ctx), []*domain.Appeal{a}); err != nil {
+			return nil, err
+		}
+		return a, nil
+	case domain.AppealStatusActive:
+		a.Resource = resource
+		if err := s.importActiveAccess(ctx, a, providerConfigs); err != nil {
+			return nil, err
+		}
+		return a, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidImportStatus, row.status)
+	}
+}
+
+// importActiveAccess stores the existing access as an active appeal without granting it
+func (s *Service) importActiveAccess(ctx context.Context, a *domain.Appeal, providerConfigs map[string]map[string]*providerConfig) error {
+	if a.Options != nil && a.Options.ExpirationDate != nil && !a.Options.ExpirationDate.After(s.TimeNow()) {
+		return ErrImportAccessExpired
+	}
+
+	if providerConfigs[a.Resource.ProviderType] == nil {
+		return ErrProviderTypeNotFound
+	}
+	providerConfig := providerConfigs[a.Resource.ProviderType][a.Resource.ProviderURN]
+	if providerConfig == nil {
+		return ErrProviderURNNotFound
+	}
+	resourceConfig, err := providerConfig.getResourceConfig(a.Resource.Type)
+	if err != nil {
+		return err
+	}
+	if !utils.ContainsString(resourceConfig.availableRoleIDs, a.Role) {
+		return &InvalidRoleError{
+			ResourceType:   a.Resource.Type,
+			Role:           a.Role,
+			AvailableRoles: resourceConfig.availableRoleIDs,
+		}
+	}
+
+	existing, err := s.getActiveAccess(ctx, a.User, a.Resource, a.Role)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrAppealDuplicate
+	}
+
+	a.Status = domain.AppealStatusActive
+	a.Priority = domain.AppealPriorityNormal
+	return s.repo.BulkInsert(ctx, []*domain.Appeal{a})
+}
+
+func readImportRows(r io.Reader) ([]*importRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: header is missing", ErrInvalidImportHeader)
+		}
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: %q column is missing", ErrInvalidImportHeader, name)
+		}
+	}
+	get := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	rows := []*importRow{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, &importRow{
+			line:           line,
+			user:           get(record, importColumnUser),
+			resourceURN:    get(record, importColumnResourceURN),
+			role:           get(record, importColumnRole),
+			expirationDate: get(record, importColumnExpirationDate),
+			status:         strings.ToLower(get(record, importColumnStatus)),
+			providerType:   get(record, importColumnProviderType),
+		})
+	}
+	return rows, nil
+}
+
+func parseImportDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q should be in RFC 3339 or YYYY-MM-DD format", ErrInvalidImportDate, value)
+	}
+	return t, nil
+}
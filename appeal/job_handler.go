@@ -1,6 +1,7 @@
 package appeal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -25,13 +26,14 @@ func NewJobHandler(logger *zap.Logger, as domain.AppealService, notifier domain.
 }
 
 func (h *JobHandler) RevokeExpiredAccess() error {
+	ctx := context.Background()
 	filters := map[string]interface{}{
 		"statuses":           []string{domain.AppealStatusActive},
 		"expiration_date_lt": time.Now(),
 	}
 
 	log.Println("retrieving access...")
-	appeals, err := h.appealService.Find(filters)
+	appeals, err := h.appealService.Find(ctx, filters)
 	if err != nil {
 		return err
 	}
@@ -41,7 +43,7 @@ func (h *JobHandler) RevokeExpiredAccess() error {
 	failedRevoke := []map[string]interface{}{}
 	for _, a := range appeals {
 		log.Printf("revoking access with appeal id: %d\n", a.ID)
-		if _, err := h.appealService.Revoke(a.ID, domain.SystemActorName, ""); err != nil {
+		if _, err := h.appealService.Revoke(ctx, a.ID, domain.SystemActorName, ""); err != nil {
 			log.Printf("failed to revoke access %d, error: %s\n", a.ID, err.Error())
 			failedRevoke = append(failedRevoke, map[string]interface{}{
 				"id":    a.ID,
@@ -67,6 +69,7 @@ func (h *JobHandler) RevokeExpiredAccess() error {
 }
 
 func (h *JobHandler) NotifyAboutToExpireAccess() error {
+	ctx := context.Background()
 	daysBeforeExpired := []int{7, 3, 1}
 	for _, d := range daysBeforeExpired {
 		h.logger.Info(fmt.Sprintf("collecting access that will expire in %v day(s)", d))
@@ -82,7 +85,7 @@ func (h *JobHandler) NotifyAboutToExpireAccess() error {
 			"expiration_date_lt": to,
 		}
 
-		appeals, err := h.appealService.Find(filters)
+		appeals, err := h.appealService.Find(ctx, filters)
 		if err != nil {
 			h.logger.Error(fmt.Sprintf("unable to list appeals: %v", err))
 			continue
@@ -95,6 +98,7 @@ func (h *JobHandler) NotifyAboutToExpireAccess() error {
 			notifications = append(notifications, domain.Notification{
 				User:    a.User,
 				Message: fmt.Sprintf("Access to %s %s is going to be expired at %s. You can extend the access if it's still needed.", a.Resource.ProviderType, a.Resource.Name, a.Options.ExpirationDate),
+				Labels:  a.Labels,
 			})
 		}
 
@@ -105,3 +109,46 @@ func (h *JobHandler) NotifyAboutToExpireAccess() error {
 
 	return nil
 }
+
+func (h *JobHandler) ToggleAccessWindows() error {
+	return h.appealService.ToggleAccessWindows(context.Background())
+}
+
+func (h *JobHandler) ActivateScheduledAccess() error {
+	return h.appealService.ActivateScheduledAccess(context.Background())
+}
+
+// RemindPendingApprovers reminds the approvers of the approval steps idle for the configured duration
+func (h *JobHandler) RemindPendingApprovers() error {
+	return h.appealService.RemindPendingApprovers(context.Background(), 0)
+}
+
+// CancelAbandonedAppeals cancels the appeals pending past the auto-cancel age of their policy
+func (h *JobHandler) CancelAbandonedAppeals() error {
+	return h.appealService.CancelAbandonedAppeals(context.Background())
+}
+
+// ResolvePendingApprovers retries resolving the approvers of the steps deferred during an IAM failure
+func (h *JobHandler) ResolvePendingApprovers() error {
+	return h.appealService.ResolvePendingApprovers(context.Background())
+}
+
+// ReassignIdleApprovals reassigns the approval steps which assignee hasn't acted on in time
+func (h *JobHandler) ReassignIdleApprovals() error {
+	return h.appealService.ReassignIdleApprovals(context.Background())
+}
+
+// SendPendingApprovalsDigest sends the digest of the pending approvals to the approvers opting into it
+func (h *JobHandler) SendPendingApprovalsDigest() error {
+	return h.appealService.SendPendingApprovalsDigest(context.Background())
+}
+
+// RetryPendingRevocations retries revoking the access of the revocation pending appeals
+func (h *JobHandler) RetryPendingRevocations() error {
+	return h.appealService.RetryPendingRevocations(context.Background(), false)
+}
+
+// DispatchOutboxMessages sends the status events and the notifications left unsent after their changes were stored
+func (h *JobHandler) DispatchOutboxMessages() error {
+	return h.appealService.DispatchOutboxMessages(context.Background())
+}
@@ -0,0 +1,82 @@
+package appeal
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/odpf/guardian/utils"
+)
+
+const (
+	LabelTypeString  = "string"
+	LabelTypeNumber  = "number"
+	LabelTypeBoolean = "boolean"
+)
+
+// LabelSchemaField is an appeal label allowed by the label schema
+type LabelSchemaField struct {
+	Key string `mapstructure:"key"`
+	// Type is either string, number, or boolean. Default: string
+	Type     string `mapstructure:"type"`
+	Required bool   `mapstructure:"required"`
+	// AllowedValues restricts the label to one of the values, any value of the type is allowed if it's empty
+	AllowedValues []string `mapstructure:"allowed_values"`
+}
+
+// validateLabels checks the appeal labels against the label schema. The labels are free-form if the schema
+// is empty, otherwise only the keys of the schema are allowed. It returns an *InvalidLabelsError listing
+// every invalid label
+func validateLabels(labels map[string]string, schema []LabelSchemaField) error {
+	if len(schema) == 0 {
+		return nil
+	}
+
+	fields := map[string]string{}
+	known := map[string]bool{}
+	for _, f := range schema {
+		known[f.Key] = true
+
+		value, exists := labels[f.Key]
+		if !exists {
+			if f.Required {
+				fields[f.Key] = "is required"
+			}
+			continue
+		}
+
+		if reason := validateLabelValue(value, f); reason != "" {
+			fields[f.Key] = reason
+		}
+	}
+	for key := range labels {
+		if !known[key] {
+			fields[key] = "is not allowed by the label schema"
+		}
+	}
+
+	if len(fields) > 0 {
+		return &InvalidLabelsError{Fields: fields}
+	}
+	return nil
+}
+
+func validateLabelValue(value string, f LabelSchemaField) string {
+	switch f.Type {
+	case "", LabelTypeString:
+	case LabelTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Sprintf("%q is not a number", value)
+		}
+	case LabelTypeBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Sprintf("%q is not a boolean", value)
+		}
+	default:
+		return fmt.Sprintf("has an unsupported type %q in the label schema", f.Type)
+	}
+
+	if len(f.AllowedValues) > 0 && !utils.ContainsString(f.AllowedValues, value) {
+		return fmt.Sprintf("%q is not one of the allowed values", value)
+	}
+	return ""
+}
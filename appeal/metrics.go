@@ -0,0 +1,35 @@
+package appeal
+
+import (
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+const (
+	metricResultSuccess = "success"
+	metricResultFailure = "failure"
+)
+
+// recordDuration records the duration since start, tagged with the result of the operation
+func (s *Service) recordDuration(name string, start time.Time, err error, tags map[string]string) {
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	tags["result"] = metricResultSuccess
+	if err != nil {
+		tags["result"] = metricResultFailure
+	}
+	s.Metrics.Timing(name, time.Since(start), tags)
+}
+
+// getResourceTags returns the metric tags of the appeal resource, which is the common dimension of
+// the appeal metrics
+func getResourceTags(a *domain.Appeal) map[string]string {
+	tags := map[string]string{}
+	if a != nil && a.Resource != nil {
+		tags["provider_type"] = a.Resource.ProviderType
+		tags["resource_type"] = a.Resource.Type
+	}
+	return tags
+}
@@ -0,0 +1,75 @@
+package appeal
+
+import (
+	"context"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	// outboxDispatchDelay leaves the recent messages to the calls storing them, they're sent right after the commit
+	outboxDispatchDelay = time.Minute
+	outboxDispatchLimit = 100
+)
+
+// addOutboxMessage stores the status event and the notifications of a change within its transaction, to be sent
+// with dispatchOutboxMessage once the transaction is committed. Nothing is stored if there's nothing to send
+func (s *Service) addOutboxMessage(ctx context.Context, tx domain.AppealRepository, event *domain.AppealStatusEvent, notifications []domain.Notification) (*domain.OutboxMessage, error) {
+	if event == nil && len(notifications) == 0 {
+		return nil, nil
+	}
+
+	message := &domain.OutboxMessage{
+		StatusEvent:   event,
+		Notifications: notifications,
+	}
+	if err := tx.AddOutboxMessage(ctx, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// dispatchOutboxMessage sends the message of a committed change and deletes it. A message failing to be deleted is
+// sent again by DispatchOutboxMessages
+func (s *Service) dispatchOutboxMessage(ctx context.Context, message *domain.OutboxMessage) {
+	if message == nil {
+		return
+	}
+
+	s.sendOutboxMessage(ctx, message)
+	if err := s.repo.DeleteOutboxMessages(ctx, []uint{message.ID}); err != nil {
+		s.getLogger(ctx).Error("failed to delete the sent outbox message", zap.Uint("outbox_message_id", message.ID), zap.Error(err))
+	}
+}
+
+// DispatchOutboxMessages sends the messages left unsent after their changes were committed, e.g. the service stopped
+// right after the commit. The messages are sent at least once, the recent ones are left to the calls storing them
+func (s *Service) DispatchOutboxMessages(ctx context.Context) error {
+	return s.repo.WithTransaction(ctx, func(tx domain.AppealRepository) error {
+		messages, err := tx.GetOutboxMessages(ctx, s.TimeNow().Add(-outboxDispatchDelay), outboxDispatchLimit)
+		if err != nil {
+			return err
+		}
+
+		ids := []uint{}
+		for _, m := range messages {
+			s.sendOutboxMessage(ctx, m)
+			ids = append(ids, m.ID)
+		}
+		return tx.DeleteOutboxMessages(ctx, ids)
+	})
+}
+
+// sendOutboxMessage publishes the status event and sends the notifications of the message
+func (s *Service) sendOutboxMessage(ctx context.Context, message *domain.OutboxMessage) {
+	if message.StatusEvent != nil && s.StatusBroker != nil {
+		s.StatusBroker.Publish(*message.StatusEvent)
+	}
+	if len(message.Notifications) > 0 {
+		if err := s.notifier.Notify(message.Notifications); err != nil {
+			s.getLogger(ctx).Error("failed to send the outbox notifications", zap.Uint("outbox_message_id", message.ID), zap.Error(err))
+		}
+	}
+}
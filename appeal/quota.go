@@ -0,0 +1,69 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// getTeamQuota returns the team quota of the appeal's resource and role, or nil if there's none
+func (s *Service) getTeamQuota(ctx context.Context, a *domain.Appeal) (*domain.TeamQuotaConfig, error) {
+	return s.providerService.GetTeamQuota(ctx, a.Resource.ProviderType, a.Resource.ProviderURN, a.Resource.Type, a.Resource.URN, a.Role)
+}
+
+// isTeamQuotaExceeded returns true if the requester's team already holds as many active grants of the appeal's
+// resource and role as the quota allows. The grants of the requester don't count as they're replaced by the appeal
+func (s *Service) isTeamQuotaExceeded(ctx context.Context, repo domain.AppealRepository, a *domain.Appeal, quota *domain.TeamQuotaConfig) (bool, error) {
+	team, err := s.iamService.GetUserTeam(a.User)
+	if err != nil {
+		return false, err
+	}
+
+	grants, err := repo.Find(ctx, map[string]interface{}{
+		"resource_id": a.ResourceID,
+		"role":        a.Role,
+		"statuses":    []string{domain.AppealStatusActive},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	teamGrants := 0
+	for _, g := range grants {
+		if g.ID == a.ID || g.User == a.User {
+			continue
+		}
+		// the teams are cached by the iam service across the calls
+		memberTeam, err := s.iamService.GetUserTeam(g.User)
+		if err != nil {
+			return false, err
+		}
+		if memberTeam == team {
+			teamGrants++
+		}
+	}
+
+	return teamGrants >= quota.Limit, nil
+}
+
+// enforceTeamQuota checks the team quota of the activated appeal again in the transaction storing it, while
+// holding the lock of the grants of its resource and role, so the concurrent approvals can't exceed the quota
+func (s *Service) enforceTeamQuota(ctx context.Context, tx domain.AppealRepository, stepAction *approvalStepAction) error {
+	appeal, quota := stepAction.appeal, stepAction.teamQuota
+	if quota == nil || appeal.Status != domain.AppealStatusActive {
+		return nil
+	}
+
+	if err := tx.LockGrants(ctx, appeal.ResourceID, appeal.Role); err != nil {
+		return err
+	}
+	exceeded, err := s.isTeamQuotaExceeded(ctx, tx, appeal, quota)
+	if err != nil {
+		return err
+	}
+	if exceeded {
+		return fmt.Errorf("%w: %d active grant(s) of %s", ErrTeamQuotaExceeded, quota.Limit, appeal.Role)
+	}
+	return nil
+}
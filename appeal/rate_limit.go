@@ -0,0 +1,79 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+const defaultRateLimitWindow = time.Hour
+
+type skipRateLimitKey struct{}
+
+// withoutRateLimit exempts the appeals created with the context from the rate limit, e.g. the imported appeals
+func withoutRateLimit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipRateLimitKey{}, true)
+}
+
+// RateLimitConfig caps the appeals created by a user within a sliding window. The appeals already stored are
+// counted, so the limit holds across the service instances sharing the database
+type RateLimitConfig struct {
+	// MaxAppeals is the maximum number of appeals a user creates within the window. The creation isn't
+	// limited if it's 0
+	MaxAppeals int `mapstructure:"max_appeals"`
+	// Window is the duration the appeals are counted over
+	Window time.Duration `mapstructure:"window" default:"1h"`
+}
+
+func (c RateLimitConfig) getWindow() time.Duration {
+	if c.Window <= 0 {
+		return defaultRateLimitWindow
+	}
+	return c.Window
+}
+
+// checkRateLimit returns a *RateLimitError if any of the users would create more appeals than the rate limit
+// allows within the window along with the appeals of the batch
+func (s *Service) checkRateLimit(ctx context.Context, appeals []*domain.Appeal) error {
+	limit := s.config.RateLimit
+	if skip, _ := ctx.Value(skipRateLimitKey{}).(bool); skip || limit.MaxAppeals <= 0 {
+		return nil
+	}
+
+	users := []string{}
+	batchCounts := map[string]int{}
+	for _, a := range appeals {
+		if batchCounts[a.User] == 0 {
+			users = append(users, a.User)
+		}
+		batchCounts[a.User]++
+	}
+
+	now := s.TimeNow()
+	window := limit.getWindow()
+	for _, user := range users {
+		createdTimes, err := s.repo.GetCreatedTimes(ctx, user, now.Add(-window))
+		if err != nil {
+			return fmt.Errorf("counting the appeals created by %q: %w", user, err)
+		}
+
+		excess := len(createdTimes) + batchCounts[user] - limit.MaxAppeals
+		if excess <= 0 {
+			continue
+		}
+		// the batch fits once the excess oldest appeals leave the window
+		retryAfter := window
+		if excess <= len(createdTimes) {
+			retryAfter = createdTimes[excess-1].Add(window).Sub(now)
+		}
+		return &RateLimitError{
+			User:       user,
+			MaxAppeals: limit.MaxAppeals,
+			Window:     window,
+			RetryAfter: retryAfter,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/notifier"
+	"github.com/odpf/guardian/utils"
+	"go.uber.org/zap"
+)
+
+const defaultReminderIdleFor = 24 * time.Hour
+
+// defaultReminderEscalationChannels reminds through the approval step channel first, then through every channel
+var defaultReminderEscalationChannels = []string{"", notifier.ChannelAll}
+
+// ReminderConfig configures the reminders sent to the approvers of the idle approval steps
+type ReminderConfig struct {
+	// IdleFor is the minimum idle duration of an approval step before its approvers are reminded
+	IdleFor time.Duration `mapstructure:"idle_for" default:"24h"`
+	// EscalationChannels are the notification channels of the successive reminders, e.g. ",slack,all". Every
+	// channel has to be registered. An empty channel is the approval step channel. Defaults to the approval step
+	// channel, then every channel
+	EscalationChannels []string `mapstructure:"escalation_channels"`
+	// OnCall are reminded along with the approvers once the escalation channels are exhausted
+	OnCall []string `mapstructure:"on_call"`
+}
+
+func (c ReminderConfig) getIdleFor() time.Duration {
+	if c.IdleFor <= 0 {
+		return defaultReminderIdleFor
+	}
+	return c.IdleFor
+}
+
+// getEscalation returns the default escalation ladder, used for the policies without their own ladder
+func (c ReminderConfig) getEscalation() []*domain.ReminderRung {
+	channels := c.EscalationChannels
+	if len(channels) == 0 {
+		channels = defaultReminderEscalationChannels
+	}
+
+	ladder := []*domain.ReminderRung{}
+	for _, channel := range channels {
+		ladder = append(ladder, &domain.ReminderRung{Channel: channel})
+	}
+	if len(c.OnCall) > 0 {
+		ladder = append(ladder, &domain.ReminderRung{
+			Channel:    channels[len(channels)-1],
+			Recipients: c.OnCall,
+		})
+	}
+	return ladder
+}
+
+// getReminderRung returns the rung of the next reminder of the approval step. The policies are cached
+// by id and version across the calls
+func (s *Service) getReminderRung(ctx context.Context, approval *domain.Approval, policies map[string]*domain.Policy) (*domain.ReminderRung, error) {
+	ladder := s.config.Reminder.getEscalation()
+	if approval.PolicyID != "" {
+		key := fmt.Sprintf("%s@%d", approval.PolicyID, approval.PolicyVersion)
+		p, cached := policies[key]
+		if !cached {
+			var err error
+			if p, err = s.policyService.GetOne(ctx, approval.PolicyID, approval.PolicyVersion); err != nil {
+				return nil, err
+			}
+			policies[key] = p
+		}
+		if p != nil && len(p.ReminderEscalation) > 0 {
+			ladder = p.ReminderEscalation
+		}
+	}
+
+	level := approval.ReminderLevel
+	if level >= len(ladder) {
+		level = len(ladder) - 1
+	}
+	rung := *ladder[level]
+	if rung.Channel == "" {
+		rung.Channel = approval.NotificationChannel
+	}
+	return &rung, nil
+}
+
+// MuteReminders stops reminding the approvers of the appeal's idle approval steps, e.g. while it's legitimately
+// parked waiting on something external, until the reminders are unmuted. The other notifications, including the
+// final decision, are still sent. Only the requester and the approvers of the appeal can mute its reminders
+func (s *Service) MuteReminders(ctx context.Context, appealID uint, actor, reason string) (*domain.Appeal, error) {
+	appeal, err := s.getAppealForReminderMute(ctx, appealID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if appeal.RemindersMutedBy != "" {
+		return appeal, nil
+	}
+
+	now := s.TimeNow()
+	appeal.RemindersMutedBy = actor
+	appeal.RemindersMutedAt = &now
+	appeal.RemindersMutedReason = reason
+	if err := s.repo.Update(ctx, appeal); err != nil {
+		return nil, err
+	}
+	s.getLogger(ctx).Info("appeal reminders muted", zap.Uint("appeal_id", appeal.ID), zap.String("actor", actor))
+
+	return appeal, nil
+}
+
+// UnmuteReminders resumes the reminders of the appeal muted by MuteReminders. The idle approval steps are reminded
+// on the next run of the reminder job
+func (s *Service) UnmuteReminders(ctx context.Context, appealID uint, actor string) (*domain.Appeal, error) {
+	appeal, err := s.getAppealForReminderMute(ctx, appealID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if appeal.RemindersMutedBy == "" {
+		return appeal, nil
+	}
+
+	appeal.RemindersMutedBy = ""
+	appeal.RemindersMutedAt = nil
+	appeal.RemindersMutedReason = ""
+	if err := s.repo.Update(ctx, appeal); err != nil {
+		return nil, err
+	}
+	s.getLogger(ctx).Info("appeal reminders unmuted", zap.Uint("appeal_id", appeal.ID), zap.String("actor", actor))
+
+	return appeal, nil
+}
+
+// getAppealForReminderMute returns the pending appeal which reminders the actor is allowed to mute or unmute
+func (s *Service) getAppealForReminderMute(ctx context.Context, appealID uint, actor string) (*domain.Appeal, error) {
+	if appealID == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+	if err := s.validator.Var(actor, "required,email"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	}
+	if err := checkActor(ctx, actor); err != nil {
+		return nil, err
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+	if err := checkIfAppealStatusStillPending(appeal.Status); err != nil {
+		return nil, err
+	}
+
+	if appeal.User == actor {
+		return appeal, nil
+	}
+	for _, approval := range appeal.Approvals {
+		if utils.ContainsString(approval.Approvers, actor) {
+			return appeal, nil
+		}
+	}
+	return nil, ErrActionForbidden
+}
@@ -1,23 +1,42 @@
 package appeal
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"hash/fnv"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
+	"github.com/odpf/guardian/auth"
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/model"
 	"github.com/odpf/guardian/utils"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type findFilters struct {
 	User                      string    `mapstructure:"user" validate:"omitempty,required"`
 	ResourceID                uint      `mapstructure:"resource_id" validate:"omitempty,required"`
 	Role                      string    `mapstructure:"role" validate:"omitempty,required"`
+	Roles                     []string  `mapstructure:"roles" validate:"omitempty,min=1,dive,required"`
+	PolicyID                  string    `mapstructure:"policy_id" validate:"omitempty,required"`
+	PolicyVersion             uint      `mapstructure:"policy_version" validate:"omitempty,required"`
 	Statuses                  []string  `mapstructure:"statuses" validate:"omitempty,min=1"`
+	GroupID                   string    `mapstructure:"group_id" validate:"omitempty,required"`
 	ExpirationDateLessThan    time.Time `mapstructure:"expiration_date_lt" validate:"omitempty,required"`
 	ExpirationDateGreaterThan time.Time `mapstructure:"expiration_date_gt" validate:"omitempty,required"`
+	// AccessWindowToggleDue matches the appeals whose access window opens or closes by the time, along with the
+	// appeals having an access window that isn't tracked yet
+	AccessWindowToggleDue time.Time `mapstructure:"access_window_toggle_due" validate:"omitempty,required"`
+	// AccessScheduled matches only the appeals which access is yet to be granted on their start date
+	AccessScheduled bool `mapstructure:"access_scheduled"`
+	// WithApprovals loads the approvals, the approvers, and the resource of the appeals the same as GetByID,
+	// in a query per relation rather than per appeal
+	WithApprovals bool `mapstructure:"with_approvals"`
 }
 
 // Repository talks to the store to read or insert data
@@ -30,10 +49,45 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db}
 }
 
+// scoped restricts the queries to the appeals of the organization carried by ctx, if any, so an organization
+// can't see another organization's appeals regardless of the filters
+func (r *Repository) scoped(ctx context.Context) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+		db = db.Where(`"appeals"."organization_id" = ?`, organizationID)
+	}
+	return db
+}
+
+// scopedByAppeal restricts the queries of the appeal records, e.g. the attachments, to the ones of the appeals of
+// the organization carried by ctx, if any
+func (r *Repository) scopedByAppeal(ctx context.Context) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if _, ok := auth.OrganizationFromContext(ctx); ok {
+		db = db.Where(`"appeal_id" IN (?)`, r.scoped(ctx).Model(&model.Appeal{}).Select(`"id"`))
+	}
+	return db
+}
+
+// checkOrganization stamps the appeal with the organization carried by ctx, if any, and rejects the appeal of
+// another organization
+func checkOrganization(ctx context.Context, a *domain.Appeal) error {
+	organizationID, ok := auth.OrganizationFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if a.OrganizationID == "" {
+		a.OrganizationID = organizationID
+	} else if a.OrganizationID != organizationID {
+		return ErrOrganizationMismatch
+	}
+	return nil
+}
+
 // GetByID returns appeal record by id along with the approvals and the approvers
-func (r *Repository) GetByID(id uint) (*domain.Appeal, error) {
+func (r *Repository) GetByID(ctx context.Context, id uint) (*domain.Appeal, error) {
 	m := new(model.Appeal)
-	if err := r.db.
+	if err := r.scoped(ctx).
 		Preload("Approvals", func(db *gorm.DB) *gorm.DB {
 			return db.Order("Approvals.index ASC")
 		}).
@@ -55,7 +109,141 @@ func (r *Repository) GetByID(id uint) (*domain.Appeal, error) {
 	return a, nil
 }
 
-func (r *Repository) Find(filters map[string]interface{}) ([]*domain.Appeal, error) {
+// GetActiveAccess returns the latest active and unexpired appeal of the user for the resource and role.
+// It returns nil if the user doesn't have any
+func (r *Repository) GetActiveAccess(ctx context.Context, user string, resourceID uint, role string, now time.Time) (*domain.Appeal, error) {
+	m := new(model.Appeal)
+	if err := r.scoped(ctx).
+		Where(`"user" = ? AND "resource_id" = ? AND "role" = ?`, user, resourceID, role).
+		Where(`"status" = ? AND "access_window_closed" = ? AND "access_scheduled" = ?`, domain.AppealStatusActive, false, false).
+		Where(`("options" ->> 'expiration_date' IS NULL OR ("options" ->> 'expiration_date')::timestamptz > ?)`, now).
+		Order(`"created_at" DESC`).
+		First(&m).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return m.ToDomain()
+}
+
+// accessEntry is a row of the access summary query
+type accessEntry struct {
+	AppealID     uint
+	ProviderType string
+	ProviderURN  string
+	ResourceID   uint
+	ResourceType string
+	ResourceURN  string
+	ResourceName string
+	Role         string
+	Options      datatypes.JSON
+}
+
+// GetAccessSummary returns the active and unexpired access of the user across the providers, along with
+// the resources they're on, ordered by provider, resource, and role. The access is aggregated in a single
+// query joining the resources instead of loading the appeals one by one
+func (r *Repository) GetAccessSummary(ctx context.Context, user string, now time.Time) ([]domain.AccessEntry, error) {
+	var rows []*accessEntry
+	if err := r.scoped(ctx).
+		Model(&model.Appeal{}).
+		Select(`"appeals"."id" AS "appeal_id", "resources"."provider_type", "resources"."provider_urn", "resources"."id" AS "resource_id", "resources"."type" AS "resource_type", "resources"."urn" AS "resource_urn", "resources"."name" AS "resource_name", "appeals"."role", "appeals"."options"`).
+		Joins(`JOIN "resources" ON "resources"."id" = "appeals"."resource_id"`).
+		Where(`"appeals"."user" = ? AND "appeals"."status" = ?`, user, domain.AppealStatusActive).
+		Where(`"appeals"."access_window_closed" = ? AND "appeals"."access_scheduled" = ?`, false, false).
+		Where(`("appeals"."options" ->> 'expiration_date' IS NULL OR ("appeals"."options" ->> 'expiration_date')::timestamptz > ?)`, now).
+		Order(`"resources"."provider_type", "resources"."provider_urn", "resources"."urn", "appeals"."role"`).
+		Find(&rows).
+		Error; err != nil {
+		return nil, err
+	}
+
+	entries := []domain.AccessEntry{}
+	for _, row := range rows {
+		entry := domain.AccessEntry{
+			AppealID:     row.AppealID,
+			ProviderType: row.ProviderType,
+			ProviderURN:  row.ProviderURN,
+			ResourceID:   row.ResourceID,
+			ResourceType: row.ResourceType,
+			ResourceURN:  row.ResourceURN,
+			ResourceName: row.ResourceName,
+			Role:         row.Role,
+		}
+		if row.Options != nil {
+			var options *domain.AppealOptions
+			if err := json.Unmarshal(row.Options, &options); err != nil {
+				return nil, err
+			}
+			if options != nil {
+				entry.ExpirationDate = options.ExpirationDate
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GetCreatedTimes returns the creation times of the appeals the user created since the time, oldest first
+func (r *Repository) GetCreatedTimes(ctx context.Context, user string, since time.Time) ([]time.Time, error) {
+	var createdTimes []time.Time
+	if err := r.scoped(ctx).
+		Model(&model.Appeal{}).
+		Where(`"user" = ? AND "created_at" >= ?`, user, since).
+		Order(`"created_at"`).
+		Pluck("created_at", &createdTimes).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return createdTimes, nil
+}
+
+// GetHistory summarizes the user's previous appeals in a single aggregate query instead of loading them
+func (r *Repository) GetHistory(ctx context.Context, user, role string) (*domain.AppealHistory, error) {
+	var result struct {
+		RoleGranted              bool
+		PermanentAccessRequested bool
+		LongestRequestedDuration float64
+	}
+	if err := r.scoped(ctx).
+		Model(&model.Appeal{}).
+		Select(`COALESCE(BOOL_OR("role" = ? AND "status" IN ?), false) AS "role_granted", `+
+			`COALESCE(BOOL_OR("options" ->> 'expiration_date' IS NULL), false) AS "permanent_access_requested", `+
+			`COALESCE(MAX(EXTRACT(EPOCH FROM ("options" ->> 'expiration_date')::timestamptz - "created_at")), 0) AS "longest_requested_duration"`,
+			role, []string{domain.AppealStatusActive, domain.AppealStatusTerminated}).
+		Where(`"user" = ?`, user).
+		Find(&result).
+		Error; err != nil {
+		return nil, err
+	}
+
+	return &domain.AppealHistory{
+		RoleGranted:              result.RoleGranted,
+		PermanentAccessRequested: result.PermanentAccessRequested,
+		LongestRequestedDuration: time.Duration(result.LongestRequestedDuration * float64(time.Second)),
+	}, nil
+}
+
+// IsPolicyVersionUsed tells whether any appeal ran under the policy version. It isn't scoped to the organization
+// carried by ctx since a policy version used by any organization must not be rewritten
+func (r *Repository) IsPolicyVersionUsed(ctx context.Context, policyID string, policyVersion uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.Appeal{}).
+		Where(`"policy_id" = ? AND "policy_version" = ?`, policyID, policyVersion).
+		Count(&count).
+		Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *Repository) Find(ctx context.Context, filters map[string]interface{}) ([]*domain.Appeal, error) {
 	var conditions findFilters
 	if err := mapstructure.Decode(filters, &conditions); err != nil {
 		return nil, err
@@ -64,7 +252,7 @@ func (r *Repository) Find(filters map[string]interface{}) ([]*domain.Appeal, err
 		return nil, err
 	}
 
-	db := r.db
+	db := r.scoped(ctx)
 	if conditions.User != "" {
 		db = db.Where(`"user" = ?`, conditions.User)
 	}
@@ -74,15 +262,46 @@ func (r *Repository) Find(filters map[string]interface{}) ([]*domain.Appeal, err
 	if conditions.ResourceID != 0 {
 		db = db.Where(`"resource_id" = ?`, conditions.ResourceID)
 	}
+	if conditions.GroupID != "" {
+		db = db.Where(`"group_id" = ?`, conditions.GroupID)
+	}
 	if conditions.Role != "" {
 		db = db.Where(`"role" = ?`, conditions.Role)
 	}
+	if conditions.Roles != nil {
+		db = db.Where(`"role" IN ?`, conditions.Roles)
+	}
+	if conditions.PolicyID != "" {
+		db = db.Where(`"policy_id" = ?`, conditions.PolicyID)
+	}
+	if conditions.PolicyVersion != 0 {
+		db = db.Where(`"policy_version" = ?`, conditions.PolicyVersion)
+	}
 	if !conditions.ExpirationDateLessThan.IsZero() {
 		db = db.Where(`"options" -> 'expiration_date' < ?`, conditions.ExpirationDateLessThan)
 	}
 	if !conditions.ExpirationDateGreaterThan.IsZero() {
 		db = db.Where(`"options" -> 'expiration_date' > ?`, conditions.ExpirationDateGreaterThan)
 	}
+	if !conditions.AccessWindowToggleDue.IsZero() {
+		db = db.Where(
+			`("access_window_toggle_at" <= ? OR ("access_window_toggle_at" IS NULL AND "options" -> 'access_window' IS NOT NULL))`,
+			conditions.AccessWindowToggleDue,
+		)
+	}
+
+	if conditions.AccessScheduled {
+		db = db.Where(`"access_scheduled" = ?`, true)
+	}
+
+	if conditions.WithApprovals {
+		db = db.
+			Preload("Approvals", func(db *gorm.DB) *gorm.DB {
+				return db.Order("Approvals.index ASC")
+			}).
+			Preload("Approvals.Approvers").
+			Preload("Resource")
+	}
 
 	var models []*model.Appeal
 	if err := db.Debug().Find(&models).Error; err != nil {
@@ -102,10 +321,79 @@ func (r *Repository) Find(filters map[string]interface{}) ([]*domain.Appeal, err
 	return records, nil
 }
 
+// WithTransaction runs fn with a repository bound to a single transaction. The transaction is committed if
+// fn returns nil and rolled back otherwise
+func (r *Repository) WithTransaction(ctx context.Context, fn func(domain.AppealRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&Repository{tx})
+	})
+}
+
+// LockGrants holds a transaction-level advisory lock keyed by the resource and the role, released once the
+// transaction ends
+func (r *Repository) LockGrants(ctx context.Context, resourceID uint, role string) error {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "appeal_grants:%d:%s", resourceID, role)
+	return r.db.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(?)", int64(h.Sum64())).Error
+}
+
+// AddOutboxMessage stores the message of a change
+func (r *Repository) AddOutboxMessage(ctx context.Context, o *domain.OutboxMessage) error {
+	m := new(model.OutboxMessage)
+	if err := m.FromDomain(o); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	o.ID = m.ID
+	o.CreatedAt = m.CreatedAt
+	return nil
+}
+
+// GetOutboxMessages returns the oldest messages, of every organization, created before the time. The messages are
+// locked with FOR UPDATE SKIP LOCKED until the end of the transaction
+func (r *Repository) GetOutboxMessages(ctx context.Context, createdBefore time.Time, limit int) ([]*domain.OutboxMessage, error) {
+	var models []*model.OutboxMessage
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where(`"created_at" < ?`, createdBefore).
+		Order("id").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	records := []*domain.OutboxMessage{}
+	for _, m := range models {
+		o, err := m.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, o)
+	}
+
+	return records, nil
+}
+
+// DeleteOutboxMessages deletes the sent messages
+func (r *Repository) DeleteOutboxMessages(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&model.OutboxMessage{}).Error
+}
+
 // Create new record to database
-func (r *Repository) BulkInsert(appeals []*domain.Appeal) error {
+func (r *Repository) BulkInsert(ctx context.Context, appeals []*domain.Appeal) error {
 	models := []*model.Appeal{}
 	for _, a := range appeals {
+		if err := checkOrganization(ctx, a); err != nil {
+			return err
+		}
 		m := new(model.Appeal)
 		if err := m.FromDomain(a); err != nil {
 			return err
@@ -113,7 +401,7 @@ func (r *Repository) BulkInsert(appeals []*domain.Appeal) error {
 		models = append(models, m)
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(models).Error; err != nil {
 			return err
 		}
@@ -132,13 +420,16 @@ func (r *Repository) BulkInsert(appeals []*domain.Appeal) error {
 }
 
 // Update an approval step
-func (r *Repository) Update(a *domain.Appeal) error {
+func (r *Repository) Update(ctx context.Context, a *domain.Appeal) error {
+	if err := checkOrganization(ctx, a); err != nil {
+		return err
+	}
 	m := new(model.Appeal)
 	if err := m.FromDomain(a); err != nil {
 		return err
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Omit("Approvals.Approvers").Session(&gorm.Session{FullSaveAssociations: true}).Save(&m).Error; err != nil {
 			return err
 		}
@@ -153,3 +444,125 @@ func (r *Repository) Update(a *domain.Appeal) error {
 		return nil
 	})
 }
+
+// AddApproval inserts an approval step, along with its approvers, to an existing appeal
+func (r *Repository) AddApproval(ctx context.Context, a *domain.Approval) error {
+	m := new(model.Approval)
+	if err := m.FromDomain(a); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	newRecord, err := m.ToDomain()
+	if err != nil {
+		return err
+	}
+
+	*a = *newRecord
+
+	return nil
+}
+
+// AddAttachment stores the attachment metadata of an appeal
+func (r *Repository) AddAttachment(ctx context.Context, a *domain.Attachment) error {
+	m := new(model.Attachment)
+	if err := m.FromDomain(a); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	newRecord, err := m.ToDomain()
+	if err != nil {
+		return err
+	}
+
+	*a = *newRecord
+
+	return nil
+}
+
+// GetAttachments returns the attachments of an appeal ordered by the upload time
+func (r *Repository) GetAttachments(ctx context.Context, appealID uint) ([]*domain.Attachment, error) {
+	var models []*model.Attachment
+	if err := r.scopedByAppeal(ctx).
+		Where(`"appeal_id" = ?`, appealID).
+		Order("created_at").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	records := []*domain.Attachment{}
+	for _, m := range models {
+		a, err := m.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, a)
+	}
+
+	return records, nil
+}
+
+// GetAttachmentByID returns attachment record by id
+func (r *Repository) GetAttachmentByID(ctx context.Context, id uint) (*domain.Attachment, error) {
+	m := new(model.Attachment)
+	if err := r.scopedByAppeal(ctx).First(&m, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return m.ToDomain()
+}
+
+// AddComment stores a comment of an appeal
+func (r *Repository) AddComment(ctx context.Context, c *domain.AppealComment) error {
+	m := new(model.AppealComment)
+	if err := m.FromDomain(c); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	newRecord, err := m.ToDomain()
+	if err != nil {
+		return err
+	}
+
+	*c = *newRecord
+
+	return nil
+}
+
+// GetComments returns the comments of an appeal ordered by the creation time
+func (r *Repository) GetComments(ctx context.Context, appealID uint) ([]*domain.AppealComment, error) {
+	var models []*model.AppealComment
+	if err := r.scopedByAppeal(ctx).
+		Where(`"appeal_id" = ?`, appealID).
+		Order("created_at").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	records := []*domain.AppealComment{}
+	for _, m := range models {
+		c, err := m.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, c)
+	}
+
+	return records, nil
+}
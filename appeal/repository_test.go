@@ -1,15 +1,18 @@
 package appeal_test
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/auth"
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/mocks"
 	"github.com/odpf/guardian/utils"
@@ -82,7 +85,7 @@ func (s *RepositoryTestSuite) TestGetByID() {
 			WithArgs(expectedID).
 			WillReturnError(expectedError)
 
-		actualResult, actualError := s.repository.GetByID(expectedID)
+		actualResult, actualError := s.repository.GetByID(context.Background(), expectedID)
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -96,7 +99,7 @@ func (s *RepositoryTestSuite) TestGetByID() {
 			WithArgs(expectedID).
 			WillReturnError(expectedError)
 
-		actualResult, actualError := s.repository.GetByID(expectedID)
+		actualResult, actualError := s.repository.GetByID(context.Background(), expectedID)
 
 		s.Nil(actualResult)
 		s.Nil(actualError)
@@ -200,13 +203,389 @@ func (s *RepositoryTestSuite) TestGetByID() {
 				WithArgs(11, 12).
 				WillReturnRows(expectedApproverRows)
 
-			actualRecord, actualError := s.repository.GetByID(tc.expectedID)
+			actualRecord, actualError := s.repository.GetByID(context.Background(), tc.expectedID)
 
 			s.Nil(actualError)
 			s.Equal(tc.expectedRecord, actualRecord)
 		}
 
 	})
+
+	s.Run("should restrict the appeal to the organization carried by the context", func() {
+		expectedID := uint(1)
+		expectedOrganizationQuery := regexp.QuoteMeta(`SELECT * FROM "appeals" WHERE ("appeals"."organization_id" = $1) AND "appeals"."id" = $2 AND "appeals"."deleted_at" IS NULL ORDER BY "appeals"."id" LIMIT 1`)
+		s.dbmock.
+			ExpectQuery(expectedOrganizationQuery).
+			WithArgs("org-a", expectedID).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		actualResult, actualError := s.repository.GetByID(ctx, expectedID)
+
+		s.Nil(actualResult)
+		s.Nil(actualError)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetActiveAccess() {
+	expectedQuery := regexp.QuoteMeta(`SELECT * FROM "appeals" WHERE ("user" = $1 AND "resource_id" = $2 AND "role" = $3) AND ("status" = $4 AND "access_window_closed" = $5 AND "access_scheduled" = $6) AND (("options" ->> 'expiration_date' IS NULL OR ("options" ->> 'expiration_date')::timestamptz > $7)) AND "appeals"."deleted_at" IS NULL ORDER BY "created_at" DESC,"appeals"."id" LIMIT 1`)
+	user := "user@email.com"
+	resourceID := uint(1)
+	role := "viewer"
+	timeNow := time.Now()
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs(user, resourceID, role, domain.AppealStatusActive, false, false, timeNow).
+			WillReturnError(expectedError)
+
+		actualResult, actualError := s.repository.GetActiveAccess(context.Background(), user, resourceID, role, timeNow)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return nil result and nil error if record not found", func() {
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs(user, resourceID, role, domain.AppealStatusActive, false, false, timeNow).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		actualResult, actualError := s.repository.GetActiveAccess(context.Background(), user, resourceID, role, timeNow)
+
+		s.Nil(actualResult)
+		s.Nil(actualError)
+	})
+
+	s.Run("should return the appeal on success", func() {
+		expectedRecord := &domain.Appeal{
+			ID:         1,
+			ResourceID: resourceID,
+			PolicyID:   "policy_1",
+			Status:     domain.AppealStatusActive,
+			User:       user,
+			Role:       role,
+			CreatedAt:  timeNow,
+			UpdatedAt:  timeNow,
+		}
+		expectedRows := sqlmock.NewRows(s.columnNames).
+			AddRow(
+				expectedRecord.ID,
+				expectedRecord.ResourceID,
+				expectedRecord.PolicyID,
+				expectedRecord.PolicyVersion,
+				expectedRecord.Status,
+				expectedRecord.User,
+				expectedRecord.Role,
+				"null",
+				"null",
+				timeNow,
+				timeNow,
+			)
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs(user, resourceID, role, domain.AppealStatusActive, false, false, timeNow).
+			WillReturnRows(expectedRows)
+
+		actualResult, actualError := s.repository.GetActiveAccess(context.Background(), user, resourceID, role, timeNow)
+
+		s.Nil(actualError)
+		s.Equal(expectedRecord, actualResult)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetCreatedTimes() {
+	expectedQuery := regexp.QuoteMeta(`SELECT "created_at" FROM "appeals" WHERE ("user" = $1 AND "created_at" >= $2) AND "appeals"."deleted_at" IS NULL ORDER BY "created_at"`)
+	user := "user@email.com"
+	since := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(user, since).WillReturnError(expectedError)
+
+		actualResult, actualError := s.repository.GetCreatedTimes(context.Background(), user, since)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return the creation times oldest first", func() {
+		expectedResult := []time.Time{since.Add(time.Minute), since.Add(time.Hour)}
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(user, since).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(expectedResult[0]).AddRow(expectedResult[1]))
+
+		actualResult, actualError := s.repository.GetCreatedTimes(context.Background(), user, since)
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetHistory() {
+	expectedQuery := regexp.QuoteMeta(`SELECT COALESCE(BOOL_OR("role" = $1 AND "status" IN ($2,$3)), false) AS "role_granted", COALESCE(BOOL_OR("options" ->> 'expiration_date' IS NULL), false) AS "permanent_access_requested", COALESCE(MAX(EXTRACT(EPOCH FROM ("options" ->> 'expiration_date')::timestamptz - "created_at")), 0) AS "longest_requested_duration" FROM "appeals" WHERE "user" = $4 AND "appeals"."deleted_at" IS NULL`)
+	user := "user@email.com"
+	role := "owner"
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs(role, domain.AppealStatusActive, domain.AppealStatusTerminated, user).
+			WillReturnError(expectedError)
+
+		actualResult, actualError := s.repository.GetHistory(context.Background(), user, role)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return the summary of the user's appeals", func() {
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs(role, domain.AppealStatusActive, domain.AppealStatusTerminated, user).
+			WillReturnRows(sqlmock.NewRows([]string{"role_granted", "permanent_access_requested", "longest_requested_duration"}).
+				AddRow(true, false, float64(86400)))
+		expectedResult := &domain.AppealHistory{
+			RoleGranted:              true,
+			LongestRequestedDuration: 24 * time.Hour,
+		}
+
+		actualResult, actualError := s.repository.GetHistory(context.Background(), user, role)
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+	})
+}
+
+func (s *RepositoryTestSuite) TestAddApproval() {
+	expectedApprovalQuery := regexp.QuoteMeta(`INSERT INTO "approvals" ("name","index","appeal_id","status","actor","policy_id","policy_version","reason","is_overridden","added_by","last_reminded_at","reminder_level","attestation","notification_channel","sla","sla_met","assignment_strategy","reassign_after","require_reason","delegation_chain","on_behalf_of","assignee","assigned_at","acknowledged_by","acknowledged_at","status_changed_at","changes_requested_at","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30) RETURNING "id"`)
+	expectedApproverQuery := regexp.QuoteMeta(`INSERT INTO "approvers"`)
+	getApproval := func() *domain.Approval {
+		return &domain.Approval{
+			Name:          "legal_approval",
+			Index:         1,
+			AppealID:      1,
+			Status:        domain.ApprovalStatusPending,
+			PolicyID:      "policy_1",
+			PolicyVersion: 1,
+			AddedBy:       "approver@email.com",
+			Approvers:     []string{"legal@email.com"},
+		}
+	}
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedApprovalQuery).WillReturnError(expectedError)
+		s.dbmock.ExpectRollback()
+
+		actualError := s.repository.AddApproval(context.Background(), getApproval())
+
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should insert the approval along with its approvers", func() {
+		approval := getApproval()
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedApprovalQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+		s.dbmock.ExpectQuery(expectedApproverQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(21))
+		s.dbmock.ExpectCommit()
+
+		actualError := s.repository.AddApproval(context.Background(), approval)
+
+		s.Nil(actualError)
+		s.Equal(uint(11), approval.ID)
+		s.Equal([]string{"legal@email.com"}, approval.Approvers)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+}
+
+func (s *RepositoryTestSuite) TestIsPolicyVersionUsed() {
+	expectedQuery := regexp.QuoteMeta(`SELECT count(1) FROM "appeals" WHERE ("policy_id" = $1 AND "policy_version" = $2) AND "appeals"."deleted_at" IS NULL`)
+	policyID := "policy_1"
+	policyVersion := uint(2)
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(policyID, policyVersion).WillReturnError(expectedError)
+
+		actualResult, actualError := s.repository.IsPolicyVersionUsed(context.Background(), policyID, policyVersion)
+
+		s.False(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should check the appeals of every organization", func() {
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(policyID, policyVersion).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+
+		actualResult, actualError := s.repository.IsPolicyVersionUsed(ctx, policyID, policyVersion)
+
+		s.Nil(actualError)
+		s.True(actualResult)
+	})
+}
+
+func (s *RepositoryTestSuite) TestLockGrants() {
+	expectedQuery := regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectExec(expectedQuery).WithArgs(sqlmock.AnyArg()).WillReturnError(expectedError)
+
+		actualError := s.repository.LockGrants(context.Background(), 1, "viewer")
+
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should lock the grants of the role on the resource", func() {
+		s.dbmock.ExpectExec(expectedQuery).WithArgs(sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		actualError := s.repository.LockGrants(context.Background(), 1, "viewer")
+
+		s.Nil(actualError)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+}
+
+func (s *RepositoryTestSuite) TestAddOutboxMessage() {
+	expectedQuery := regexp.QuoteMeta(`INSERT INTO "outbox_messages" ("status_event","notifications","created_at") VALUES ($1,$2,$3) RETURNING "id"`)
+	message := &domain.OutboxMessage{
+		StatusEvent:   &domain.AppealStatusEvent{AppealID: 1, Status: domain.AppealStatusActive},
+		Notifications: []domain.Notification{{User: "user@email.com", Message: "approved"}},
+	}
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedQuery).WillReturnError(expectedError)
+		s.dbmock.ExpectRollback()
+
+		actualError := s.repository.AddOutboxMessage(context.Background(), message)
+
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should store the message and set its id", func() {
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		s.dbmock.ExpectCommit()
+
+		actualError := s.repository.AddOutboxMessage(context.Background(), message)
+
+		s.Nil(actualError)
+		s.Equal(uint(1), message.ID)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetOutboxMessages() {
+	expectedQuery := regexp.QuoteMeta(`SELECT * FROM "outbox_messages" WHERE "created_at" < $1 ORDER BY id LIMIT 10 FOR UPDATE SKIP LOCKED`)
+	createdBefore := time.Now()
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(createdBefore).WillReturnError(expectedError)
+
+		actualResult, actualError := s.repository.GetOutboxMessages(context.Background(), createdBefore, 10)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return the messages of every organization", func() {
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(createdBefore).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status_event", "notifications"}).
+				AddRow(1, `{"appeal_id":1,"status":"active"}`, `[{"User":"user@email.com","Message":"approved"}]`).
+				AddRow(2, nil, `[]`))
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+
+		actualResult, actualError := s.repository.GetOutboxMessages(ctx, createdBefore, 10)
+
+		s.Nil(actualError)
+		s.Equal([]*domain.OutboxMessage{
+			{
+				ID:            1,
+				StatusEvent:   &domain.AppealStatusEvent{AppealID: 1, Status: domain.AppealStatusActive},
+				Notifications: []domain.Notification{{User: "user@email.com", Message: "approved"}},
+			},
+			{ID: 2, Notifications: []domain.Notification{}},
+		}, actualResult)
+	})
+}
+
+func (s *RepositoryTestSuite) TestDeleteOutboxMessages() {
+	expectedQuery := regexp.QuoteMeta(`DELETE FROM "outbox_messages" WHERE id IN ($1,$2)`)
+
+	s.Run("should do nothing without any message", func() {
+		actualError := s.repository.DeleteOutboxMessages(context.Background(), nil)
+
+		s.Nil(actualError)
+	})
+
+	s.Run("should delete the messages", func() {
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectExec(expectedQuery).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 2))
+		s.dbmock.ExpectCommit()
+
+		actualError := s.repository.DeleteOutboxMessages(context.Background(), []uint{1, 2})
+
+		s.Nil(actualError)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetAccessSummary() {
+	expectedQuery := regexp.QuoteMeta(`SELECT "appeals"."id" AS "appeal_id", "resources"."provider_type", "resources"."provider_urn", "resources"."id" AS "resource_id", "resources"."type" AS "resource_type", "resources"."urn" AS "resource_urn", "resources"."name" AS "resource_name", "appeals"."role", "appeals"."options" FROM "appeals" JOIN "resources" ON "resources"."id" = "appeals"."resource_id" WHERE ("appeals"."user" = $1 AND "appeals"."status" = $2) AND ("appeals"."access_window_closed" = $3 AND "appeals"."access_scheduled" = $4) AND (("appeals"."options" ->> 'expiration_date' IS NULL OR ("appeals"."options" ->> 'expiration_date')::timestamptz > $5)) AND "appeals"."deleted_at" IS NULL ORDER BY "resources"."provider_type", "resources"."provider_urn", "resources"."urn", "appeals"."role"`)
+	user := "user@email.com"
+	timeNow := time.Now()
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs(user, domain.AppealStatusActive, false, false, timeNow).
+			WillReturnError(expectedError)
+
+		actualResult, actualError := s.repository.GetAccessSummary(context.Background(), user, timeNow)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return the access entries on success", func() {
+		expirationDate := timeNow.Add(24 * time.Hour).UTC().Truncate(time.Second)
+		expectedRows := sqlmock.NewRows([]string{"appeal_id", "provider_type", "provider_urn", "resource_id", "resource_type", "resource_urn", "resource_name", "role", "options"}).
+			AddRow(1, "google_bigquery", "provider_1", 2, "dataset", "project:dataset_1", "dataset_1", "viewer", fmt.Sprintf(`{"expiration_date":%q}`, expirationDate.Format(time.RFC3339))).
+			AddRow(3, "metabase", "provider_2", 4, "collection", "collection:1", "collection_1", "editor", "null")
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs(user, domain.AppealStatusActive, false, false, timeNow).
+			WillReturnRows(expectedRows)
+		expectedResult := []domain.AccessEntry{
+			{
+				AppealID:       1,
+				ProviderType:   "google_bigquery",
+				ProviderURN:    "provider_1",
+				ResourceID:     2,
+				ResourceType:   "dataset",
+				ResourceURN:    "project:dataset_1",
+				ResourceName:   "dataset_1",
+				Role:           "viewer",
+				ExpirationDate: &expirationDate,
+			},
+			{
+				AppealID:     3,
+				ProviderType: "metabase",
+				ProviderURN:  "provider_2",
+				ResourceID:   4,
+				ResourceType: "collection",
+				ResourceURN:  "collection:1",
+				ResourceName: "collection_1",
+				Role:         "editor",
+			},
+		}
+
+		actualResult, actualError := s.repository.GetAccessSummary(context.Background(), user, timeNow)
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+	})
 }
 
 func (s *RepositoryTestSuite) TestFind() {
@@ -216,12 +595,21 @@ func (s *RepositoryTestSuite) TestFind() {
 			ExpectQuery(".*").
 			WillReturnError(expectedError)
 
-		actualResult, actualError := s.repository.Find(map[string]interface{}{})
+		actualResult, actualError := s.repository.Find(context.Background(), map[string]interface{}{})
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
 	})
 
+	s.Run("should return error if roles filter contains an empty role", func() {
+		actualResult, actualError := s.repository.Find(context.Background(), map[string]interface{}{
+			"roles": []string{"viewer", ""},
+		})
+
+		s.Nil(actualResult)
+		s.Error(actualError)
+	})
+
 	s.Run("should run query based on filters", func() {
 		timeNow := time.Now()
 		testCases := []struct {
@@ -240,6 +628,13 @@ func (s *RepositoryTestSuite) TestFind() {
 				expectedQuery: regexp.QuoteMeta(`SELECT * FROM "appeals" WHERE "user" = $1 AND "appeals"."deleted_at" IS NULL`),
 				expectedArgs:  []driver.Value{"user@email.com"},
 			},
+			{
+				filters: map[string]interface{}{
+					"group_id": "group-1",
+				},
+				expectedQuery: regexp.QuoteMeta(`SELECT * FROM "appeals" WHERE "group_id" = $1 AND "appeals"."deleted_at" IS NULL`),
+				expectedArgs:  []driver.Value{"group-1"},
+			},
 			{
 				filters: map[string]interface{}{
 					"statuses": []string{domain.AppealStatusActive, domain.AppealStatusTerminated},
@@ -261,6 +656,22 @@ func (s *RepositoryTestSuite) TestFind() {
 				expectedQuery: regexp.QuoteMeta(`SELECT * FROM "appeals" WHERE "role" = $1 AND "appeals"."deleted_at" IS NULL`),
 				expectedArgs:  []driver.Value{"test-role"},
 			},
+			{
+				filters: map[string]interface{}{
+					"resource_id": uint(1),
+					"roles":       []string{"viewer", "editor"},
+				},
+				expectedQuery: regexp.QuoteMeta(`SELECT * FROM "appeals" WHERE "resource_id" = $1 AND "role" IN ($2,$3) AND "appeals"."deleted_at" IS NULL`),
+				expectedArgs:  []driver.Value{uint(1), "viewer", "editor"},
+			},
+			{
+				filters: map[string]interface{}{
+					"policy_id":      "policy_1",
+					"policy_version": uint(2),
+				},
+				expectedQuery: regexp.QuoteMeta(`SELECT * FROM "appeals" WHERE "policy_id" = $1 AND "policy_version" = $2 AND "appeals"."deleted_at" IS NULL`),
+				expectedArgs:  []driver.Value{"policy_1", uint(2)},
+			},
 			{
 				filters: map[string]interface{}{
 					"expiration_date_lt": timeNow,
@@ -268,6 +679,14 @@ func (s *RepositoryTestSuite) TestFind() {
 				expectedQuery: regexp.QuoteMeta(`SELECT * FROM "appeals" WHERE "options" -> 'expiration_date' < $1 AND "appeals"."deleted_at" IS NULL`),
 				expectedArgs:  []driver.Value{timeNow},
 			},
+			{
+				filters: map[string]interface{}{
+					"statuses":         []string{domain.AppealStatusActive},
+					"access_scheduled": true,
+				},
+				expectedQuery: regexp.QuoteMeta(`SELECT * FROM "appeals" WHERE "status" IN ($1) AND "access_scheduled" = $2 AND "appeals"."deleted_at" IS NULL`),
+				expectedArgs:  []driver.Value{domain.AppealStatusActive, true},
+			},
 		}
 
 		for _, tc := range testCases {
@@ -276,7 +695,7 @@ func (s *RepositoryTestSuite) TestFind() {
 				WithArgs(tc.expectedArgs...).
 				WillReturnRows(sqlmock.NewRows(s.columnNames))
 
-			_, actualError := s.repository.Find(tc.filters)
+			_, actualError := s.repository.Find(context.Background(), tc.filters)
 
 			s.Nil(actualError)
 		}
@@ -325,15 +744,112 @@ func (s *RepositoryTestSuite) TestFind() {
 			ExpectQuery(expectedQuery).
 			WillReturnRows(expecterRows)
 
-		actualRecords, actualError := s.repository.Find(expectedFilters)
+		actualRecords, actualError := s.repository.Find(context.Background(), expectedFilters)
 
 		s.Equal(expectedRecords, actualRecords)
 		s.Nil(actualError)
 	})
+
+	s.Run("should load the approvals of every appeal in a single query if with_approvals is set", func() {
+		timeNow := time.Now()
+		expectedQuery := regexp.QuoteMeta(`SELECT * FROM "appeals" WHERE "status" IN ($1) AND "appeals"."deleted_at" IS NULL`)
+		expectedRecords := []*domain.Appeal{
+			{
+				ID:            1,
+				PolicyID:      "policy_1",
+				PolicyVersion: 1,
+				Status:        domain.AppealStatusPending,
+				Approvals: []*domain.Approval{
+					{
+						ID:            11,
+						Name:          "approval_1",
+						AppealID:      1,
+						Status:        domain.ApprovalStatusPending,
+						PolicyID:      "policy_1",
+						PolicyVersion: 1,
+						CreatedAt:     timeNow,
+						UpdatedAt:     timeNow,
+					},
+				},
+				CreatedAt: timeNow,
+				UpdatedAt: timeNow,
+			},
+			{
+				ID:            2,
+				PolicyID:      "policy_1",
+				PolicyVersion: 1,
+				Status:        domain.AppealStatusPending,
+				Approvals: []*domain.Approval{
+					{
+						ID:            21,
+						Name:          "approval_1",
+						AppealID:      2,
+						Status:        domain.ApprovalStatusPending,
+						PolicyID:      "policy_1",
+						PolicyVersion: 1,
+						CreatedAt:     timeNow,
+						UpdatedAt:     timeNow,
+					},
+				},
+				CreatedAt: timeNow,
+				UpdatedAt: timeNow,
+			},
+		}
+		expectedRows := sqlmock.NewRows(s.columnNames)
+		expectedApprovalRows := sqlmock.NewRows(s.approvalColumnNames)
+		for _, r := range expectedRecords {
+			expectedRows.AddRow(
+				r.ID,
+				r.ResourceID,
+				r.PolicyID,
+				r.PolicyVersion,
+				r.Status,
+				r.User,
+				r.Role,
+				"null",
+				"null",
+				timeNow,
+				timeNow,
+			)
+			for _, a := range r.Approvals {
+				expectedApprovalRows.AddRow(
+					a.ID,
+					a.Name,
+					a.AppealID,
+					a.Status,
+					a.PolicyID,
+					a.PolicyVersion,
+					timeNow,
+					timeNow,
+				)
+			}
+		}
+		s.dbmock.
+			ExpectQuery(expectedQuery).
+			WithArgs(domain.AppealStatusPending).
+			WillReturnRows(expectedRows)
+		s.dbmock.
+			ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "approvals" WHERE "approvals"."appeal_id" IN ($1,$2) AND "approvals"."deleted_at" IS NULL`)).
+			WithArgs(1, 2).
+			WillReturnRows(expectedApprovalRows)
+		s.dbmock.
+			ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "approvers" WHERE "approvers"."approval_id" IN ($1,$2) AND "approvers"."deleted_at" IS NULL`)).
+			WithArgs(11, 21).
+			WillReturnRows(sqlmock.NewRows(s.approverColumnNames))
+
+		actualRecords, actualError := s.repository.Find(context.Background(), map[string]interface{}{
+			"statuses":       []string{domain.AppealStatusPending},
+			"with_approvals": true,
+		})
+
+		s.Nil(actualError)
+		s.Equal(expectedRecords, actualRecords)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
 }
 
 func (s *RepositoryTestSuite) TestBulkInsert() {
-	expectedQuery := regexp.QuoteMeta(`INSERT INTO "appeals" ("resource_id","policy_id","policy_version","status","user","role","options","labels","revoked_by","revoked_at","revoke_reason","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14),($15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28) RETURNING "id"`)
+	expectedQuery := regexp.QuoteMeta(`INSERT INTO "appeals" ("resource_id","policy_id","policy_version","status","user","role","priority","organization_id","group_id","options","labels","revoked_by","revoked_at","revoke_reason","canceled_by","revocation_attempts","revocation_error","access_window_closed","access_window_toggle_at","access_scheduled","undo_deadline","warnings","requested","reminders_muted_by","reminders_muted_at","reminders_muted_reason","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29),($30,$31,$32,$33,$34,$35,$36,$37,$38,$39,$40,$41,$42,$43,$44,$45,$46,$47,$48,$49,$50,$51,$52,$53,$54,$55,$56,$57,$58) RETURNING "id"`)
 
 	appeals := []*domain.Appeal{
 		{
@@ -358,11 +874,26 @@ func (s *RepositoryTestSuite) TestBulkInsert() {
 			a.Status,
 			a.User,
 			a.Role,
+			a.Priority,
+			a.OrganizationID,
+			a.GroupID,
 			"null",
 			"null",
 			a.RevokedBy,
 			utils.AnyTime{},
 			a.RevokeReason,
+			a.CanceledBy,
+			a.RevocationAttempts,
+			a.RevocationError,
+			a.AccessWindowClosed,
+			a.AccessWindowToggleAt,
+			a.AccessScheduled,
+			a.UndoDeadline,
+			"null",
+			"null",
+			a.RemindersMutedBy,
+			a.RemindersMutedAt,
+			a.RemindersMutedReason,
 			utils.AnyTime{},
 			utils.AnyTime{},
 			gorm.DeletedAt{},
@@ -376,7 +907,7 @@ func (s *RepositoryTestSuite) TestBulkInsert() {
 			WillReturnError(expectedError)
 		s.dbmock.ExpectRollback()
 
-		actualError := s.repository.BulkInsert(appeals)
+		actualError := s.repository.BulkInsert(context.Background(), appeals)
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -394,7 +925,7 @@ func (s *RepositoryTestSuite) TestBulkInsert() {
 			WillReturnRows(expectedRows)
 		s.dbmock.ExpectCommit()
 
-		actualError := s.repository.BulkInsert(appeals)
+		actualError := s.repository.BulkInsert(context.Background(), appeals)
 
 		s.Nil(actualError)
 		for i, a := range appeals {
@@ -411,13 +942,20 @@ func (s *RepositoryTestSuite) TestUpdate() {
 			WillReturnError(expectedError)
 		s.dbmock.ExpectRollback()
 
-		actualError := s.repository.Update(&domain.Appeal{ID: 1})
+		actualError := s.repository.Update(context.Background(), &domain.Appeal{ID: 1})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
-	expectedUpdateApprovalsQuery := regexp.QuoteMeta(`INSERT INTO "approvals" ("name","index","appeal_id","status","actor","policy_id","policy_version","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11),($12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22) ON CONFLICT ("id") DO UPDATE SET "name"="excluded"."name","index"="excluded"."index","appeal_id"="excluded"."appeal_id","status"="excluded"."status","actor"="excluded"."actor","policy_id"="excluded"."policy_id","policy_version"="excluded"."policy_version","created_at"="excluded"."created_at","updated_at"="excluded"."updated_at","deleted_at"="excluded"."deleted_at" RETURNING "id"`)
-	expectedUpdateAppealQuery := regexp.QuoteMeta(`UPDATE "appeals" SET "resource_id"=$1,"policy_id"=$2,"policy_version"=$3,"status"=$4,"user"=$5,"role"=$6,"options"=$7,"labels"=$8,"revoked_by"=$9,"revoked_at"=$10,"revoke_reason"=$11,"created_at"=$12,"updated_at"=$13,"deleted_at"=$14 WHERE "id" = $15`)
+	s.Run("should return error if the appeal belongs to another organization than the context's", func() {
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		actualError := s.repository.Update(ctx, &domain.Appeal{ID: 1, OrganizationID: "org-b"})
+
+		s.ErrorIs(actualError, appeal.ErrOrganizationMismatch)
+	})
+
+	expectedUpdateApprovalsQuery := regexp.QuoteMeta(`INSERT INTO "approvals" ("name","index","appeal_id","status","actor","policy_id","policy_version","reason","is_overridden","added_by","last_reminded_at","reminder_level","attestation","notification_channel","sla","sla_met","assignment_strategy","reassign_after","require_reason","delegation_chain","on_behalf_of","assignee","assigned_at","acknowledged_by","acknowledged_at","status_changed_at","changes_requested_at","created_at","updated_at","deleted_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31),($32,$33,$34,$35,$36,$37,$38,$39,$40,$41,$42,$43,$44,$45,$46,$47,$48,$49,$50,$51,$52,$53,$54,$55,$56,$57,$58,$59,$60,$61,$62) ON CONFLICT ("id") DO UPDATE SET "name"="excluded"."name","index"="excluded"."index","appeal_id"="excluded"."appeal_id","status"="excluded"."status","actor"="excluded"."actor","policy_id"="excluded"."policy_id","policy_version"="excluded"."policy_version","reason"="excluded"."reason","is_overridden"="excluded"."is_overridden","added_by"="excluded"."added_by","last_reminded_at"="excluded"."last_reminded_at","reminder_level"="excluded"."reminder_level","attestation"="excluded"."attestation","notification_channel"="excluded"."notification_channel","sla"="excluded"."sla","sla_met"="excluded"."sla_met","assignment_strategy"="excluded"."assignment_strategy","reassign_after"="excluded"."reassign_after","require_reason"="excluded"."require_reason","delegation_chain"="excluded"."delegation_chain","on_behalf_of"="excluded"."on_behalf_of","assignee"="excluded"."assignee","assigned_at"="excluded"."assigned_at","acknowledged_by"="excluded"."acknowledged_by","acknowledged_at"="excluded"."acknowledged_at","status_changed_at"="excluded"."status_changed_at","changes_requested_at"="excluded"."changes_requested_at","created_at"="excluded"."created_at","updated_at"="excluded"."updated_at","deleted_at"="excluded"."deleted_at" RETURNING "id"`)
+	expectedUpdateAppealQuery := regexp.QuoteMeta(`UPDATE "appeals" SET "resource_id"=$1,"policy_id"=$2,"policy_version"=$3,"status"=$4,"user"=$5,"role"=$6,"priority"=$7,"organization_id"=$8,"group_id"=$9,"options"=$10,"labels"=$11,"revoked_by"=$12,"revoked_at"=$13,"revoke_reason"=$14,"canceled_by"=$15,"revocation_attempts"=$16,"revocation_error"=$17,"access_window_closed"=$18,"access_window_toggle_at"=$19,"access_scheduled"=$20,"undo_deadline"=$21,"warnings"=$22,"requested"=$23,"reminders_muted_by"=$24,"reminders_muted_at"=$25,"reminders_muted_reason"=$26,"created_at"=$27,"updated_at"=$28,"deleted_at"=$29 WHERE "id" = $30`)
 	s.Run("should return nil on success", func() {
 		expectedID := uint(1)
 		appeal := &domain.Appeal{
@@ -448,6 +986,26 @@ func (s *RepositoryTestSuite) TestUpdate() {
 				approval.Actor,
 				approval.PolicyID,
 				approval.PolicyVersion,
+				approval.Reason,
+				approval.IsOverridden,
+				approval.AddedBy,
+				approval.LastRemindedAt,
+				approval.ReminderLevel,
+				nil,
+				approval.NotificationChannel,
+				approval.SLA,
+				approval.SLAMet,
+				approval.AssignmentStrategy,
+				approval.ReassignAfter,
+				approval.RequireReason,
+				nil,
+				approval.OnBehalfOf,
+				approval.Assignee,
+				approval.AssignedAt,
+				approval.AcknowledgedBy,
+				approval.AcknowledgedAt,
+				approval.StatusChangedAt,
+				approval.ChangesRequestedAt,
 				utils.AnyTime{},
 				utils.AnyTime{},
 				gorm.DeletedAt{},
@@ -462,12 +1020,111 @@ func (s *RepositoryTestSuite) TestUpdate() {
 			WillReturnRows(expectedApprovalRows)
 		s.dbmock.ExpectCommit()
 
-		err := s.repository.Update(appeal)
+		err := s.repository.Update(context.Background(), appeal)
 
 		s.Nil(err)
 	})
 }
 
+func (s *RepositoryTestSuite) TestGetAttachments() {
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.
+			ExpectQuery(".*").
+			WillReturnError(expectedError)
+
+		actualResult, actualError := s.repository.GetAttachments(context.Background(), 1)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return the attachments of the appeal", func() {
+		timeNow := time.Now()
+		expectedQuery := regexp.QuoteMeta(`SELECT * FROM "attachments" WHERE "appeal_id" = $1 AND "attachments"."deleted_at" IS NULL ORDER BY created_at`)
+		expectedRecords := []*domain.Attachment{
+			{
+				ID:          1,
+				AppealID:    1,
+				Filename:    "evidence.pdf",
+				ContentType: "application/pdf",
+				Size:        10,
+				StorageKey:  "appeals/1/evidence.pdf",
+				CreatedAt:   timeNow,
+			},
+		}
+		expectedRows := sqlmock.NewRows([]string{"id", "appeal_id", "filename", "content_type", "size", "storage_key", "created_at"}).
+			AddRow(1, 1, "evidence.pdf", "application/pdf", 10, "appeals/1/evidence.pdf", timeNow)
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(1).WillReturnRows(expectedRows)
+
+		actualResult, actualError := s.repository.GetAttachments(context.Background(), 1)
+
+		s.Nil(actualError)
+		s.Equal(expectedRecords, actualResult)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetComments() {
+	s.Run("should return the comments of the appeal", func() {
+		timeNow := time.Now()
+		expectedQuery := regexp.QuoteMeta(`SELECT * FROM "appeal_comments" WHERE "appeal_id" = $1 AND "appeal_comments"."deleted_at" IS NULL ORDER BY created_at`)
+		expectedRecords := []*domain.AppealComment{
+			{
+				ID:         1,
+				AppealID:   1,
+				CreatedBy:  "approver@email.com",
+				Body:       "checked with security",
+				Visibility: domain.CommentVisibilityPrivate,
+				CreatedAt:  timeNow,
+				UpdatedAt:  timeNow,
+			},
+		}
+		expectedRows := sqlmock.NewRows([]string{"id", "appeal_id", "created_by", "body", "visibility", "created_at", "updated_at"}).
+			AddRow(1, 1, "approver@email.com", "checked with security", domain.CommentVisibilityPrivate, timeNow, timeNow)
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(1).WillReturnRows(expectedRows)
+
+		actualResult, actualError := s.repository.GetComments(context.Background(), 1)
+
+		s.Nil(actualError)
+		s.Equal(expectedRecords, actualResult)
+	})
+}
+
+func (s *RepositoryTestSuite) TestWithTransaction() {
+	expectedQuery := regexp.QuoteMeta(`SELECT * FROM "appeal_comments" WHERE "appeal_id" = $1 AND "appeal_comments"."deleted_at" IS NULL ORDER BY created_at`)
+
+	s.Run("should commit the transaction if the function succeeds", func() {
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		s.dbmock.ExpectCommit()
+
+		actualError := s.repository.WithTransaction(context.Background(), func(tx domain.AppealRepository) error {
+			_, err := tx.GetComments(context.Background(), 1)
+			return err
+		})
+
+		s.Nil(actualError)
+		s.NoError(s.dbmock.ExpectationsWereMet())
+	})
+
+	s.Run("should roll back the transaction if the function fails", func() {
+		expectedError := errors.New("function error")
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		s.dbmock.ExpectRollback()
+
+		actualError := s.repository.WithTransaction(context.Background(), func(tx domain.AppealRepository) error {
+			if _, err := tx.GetComments(context.Background(), 1); err != nil {
+				return err
+			}
+			return expectedError
+		})
+
+		s.ErrorIs(actualError, expectedError)
+		s.NoError(s.dbmock.ExpectationsWereMet())
+	})
+}
+
 func TestRepository(t *testing.T) {
 	suite.Run(t, new(RepositoryTestSuite))
 }
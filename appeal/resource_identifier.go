@@ -0,0 +1,58 @@
+package appeal
+
+import (
+	"context"
+
+	"github.com/odpf/guardian/domain"
+)
+
+type resourceIdentifier struct {
+	providerType string
+	providerURN  string
+	urn          string
+}
+
+// resolveResourceIDs sets the resource id of the appeals identifying their resource by its provider type, provider
+// urn, and urn instead, i.e. having the resource without its id. The appeals having the resource id are left as is
+func (s *Service) resolveResourceIDs(ctx context.Context, appeals []*domain.Appeal) error {
+	urns := []string{}
+	for _, a := range appeals {
+		if a.ResourceID != 0 {
+			continue
+		}
+		r := a.Resource
+		if r == nil || r.ProviderType == "" || r.ProviderURN == "" || r.URN == "" {
+			return ErrInvalidResourceIdentifier
+		}
+		urns = append(urns, r.URN)
+	}
+	if len(urns) == 0 {
+		return nil
+	}
+
+	resources, err := s.findResources(ctx, map[string]interface{}{"urns": urns})
+	if err != nil {
+		return err
+	}
+	resourceIDs := map[resourceIdentifier]uint{}
+	for _, r := range resources {
+		resourceIDs[resourceIdentifier{r.ProviderType, r.ProviderURN, r.URN}] = r.ID
+	}
+
+	for _, a := range appeals {
+		if a.ResourceID != 0 {
+			continue
+		}
+		r := a.Resource
+		id, ok := resourceIDs[resourceIdentifier{r.ProviderType, r.ProviderURN, r.URN}]
+		if !ok {
+			return &UnresolvedResourceError{
+				ProviderType: r.ProviderType,
+				ProviderURN:  r.ProviderURN,
+				URN:          r.URN,
+			}
+		}
+		a.ResourceID = id
+	}
+	return nil
+}
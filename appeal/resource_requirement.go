@@ -0,0 +1,40 @@
+package appeal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mcuadros/go-lookup"
+	"github.com/odpf/guardian/domain"
+)
+
+// checkResourceRequirements returns an error describing the first of the role's resource requirements the resource
+// doesn't match, e.g. a write role requested on a resource synced as read-only, which the provider would reject
+// when granting the access anyway. The values are compared as they're printed, so the number types decoded from
+// the resource details and the provider config match each other
+func checkResourceRequirements(r *domain.Resource, role string, requirements []*domain.Condition) error {
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	mapResource, err := structToMap(r)
+	if err != nil {
+		return err
+	}
+	for _, c := range requirements {
+		if c.Match == nil {
+			continue
+		}
+		expected := fmt.Sprint(c.Match.Eq)
+
+		path := strings.TrimPrefix(c.Field, fmt.Sprintf("%s.", domain.ApproversKeyResource))
+		value, err := lookup.LookupString(mapResource, path)
+		if err != nil || !value.IsValid() {
+			return fmt.Errorf("%w: %q on %q requires %s to be %s, the resource doesn't have it", ErrRoleIncompatibleWithResource, role, r.URN, c.Field, expected)
+		}
+		if actual := fmt.Sprint(value.Interface()); actual != expected {
+			return fmt.Errorf("%w: %q on %q requires %s to be %s, got %s", ErrRoleIncompatibleWithResource, role, r.URN, c.Field, expected, actual)
+		}
+	}
+	return nil
+}
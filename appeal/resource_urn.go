@@ -0,0 +1,99 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// getResourceKey identifies the resource by its provider, type, and URN rather than its id, so the same resource
+// re-imported under another id shares the key. The URN is normalized by parsing and formatting it back with the
+// provider's URN format, and kept as is if the provider doesn't support it or fails to parse it
+func (s *Service) getResourceKey(r *domain.Resource) string {
+	urn := r.URN
+	if parsed, err := s.providerService.ParseURN(r.ProviderType, r.Type, r.URN); err == nil {
+		if formatted, err := s.providerService.FormatURN(r.ProviderType, parsed); err == nil {
+			urn = formatted
+		}
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", r.ProviderType, r.ProviderURN, r.Type, urn)
+}
+
+// getPendingAppealsByResourceKey groups the pending appeals by user, resource key, and role
+func (s *Service) getPendingAppealsByResourceKey(ctx context.Context, pendingAppeals map[string]map[uint]map[string]*domain.Appeal) (map[string]map[string]map[string]*domain.Appeal, error) {
+	resourceIDs := []uint{}
+	for _, resources := range pendingAppeals {
+		for id := range resources {
+			resourceIDs = append(resourceIDs, id)
+		}
+	}
+	result := map[string]map[string]map[string]*domain.Appeal{}
+	if len(resourceIDs) == 0 {
+		return result, nil
+	}
+	resources, err := s.getResourceMap(ctx, resourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[uint]string{}
+	for user, appealsByResource := range pendingAppeals {
+		for id, appealsByRole := range appealsByResource {
+			r := resources[id]
+			if r == nil {
+				continue
+			}
+			if _, ok := keys[id]; !ok {
+				keys[id] = s.getResourceKey(r)
+			}
+			if result[user] == nil {
+				result[user] = map[string]map[string]*domain.Appeal{}
+			}
+			if result[user][keys[id]] == nil {
+				result[user][keys[id]] = map[string]*domain.Appeal{}
+			}
+			for role, a := range appealsByRole {
+				result[user][keys[id]][role] = a
+			}
+		}
+	}
+	return result, nil
+}
+
+// getActiveAccess returns the active and unexpired appeal of the user for the resource and role. If the resource
+// URN normalization is enabled, the access to the other resources sharing the resource key counts as well
+func (s *Service) getActiveAccess(ctx context.Context, user string, r *domain.Resource, role string) (*domain.Appeal, error) {
+	if !s.config.NormalizeResourceURN {
+		return s.repo.GetActiveAccess(ctx, user, r.ID, role, s.TimeNow())
+	}
+
+	resources, err := s.findResources(ctx, map[string]interface{}{
+		"type_keys": []domain.ResourceTypeKey{{
+			ProviderType: r.ProviderType,
+			ProviderURN:  r.ProviderURN,
+			Type:         r.Type,
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key := s.getResourceKey(r)
+	resourceIDs := []uint{r.ID}
+	for _, other := range resources {
+		if other.ID != r.ID && s.getResourceKey(other) == key {
+			resourceIDs = append(resourceIDs, other.ID)
+		}
+	}
+	for _, id := range resourceIDs {
+		appeal, err := s.repo.GetActiveAccess(ctx, user, id, role, s.TimeNow())
+		if err != nil {
+			return nil, err
+		}
+		if appeal != nil {
+			return appeal, nil
+		}
+	}
+	return nil, nil
+}
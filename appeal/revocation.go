@@ -0,0 +1,104 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRevocationRetryInterval   = 10 * time.Minute
+	defaultRevocationRetryAlertAfter = 3
+)
+
+// RevocationRetryConfig configures the retry of the failed revocations. The appeal stays revocation pending,
+// with its access still granted, until a retry of the revocation succeeds
+type RevocationRetryConfig struct {
+	// Interval is the minimum duration between the attempts to revoke the access of the same appeal
+	Interval time.Duration `mapstructure:"interval" default:"10m"`
+	// AlertAfter is the number of the failed attempts after which the admins are alerted, and again after every
+	// AlertAfter more failed attempts
+	AlertAfter int `mapstructure:"alert_after" default:"3"`
+}
+
+func (c RevocationRetryConfig) getInterval() time.Duration {
+	if c.Interval <= 0 {
+		return defaultRevocationRetryInterval
+	}
+	return c.Interval
+}
+
+func (c RevocationRetryConfig) getAlertAfter() int {
+	if c.AlertAfter <= 0 {
+		return defaultRevocationRetryAlertAfter
+	}
+	return c.AlertAfter
+}
+
+// markRevocationPending keeps the appeal which access failed to be revoked as revocation pending, along with the
+// actor and the reason of the revocation, for the revocation to be retried. The admins are alerted once the
+// revocation keeps failing
+func (s *Service) markRevocationPending(ctx context.Context, appeal *domain.Appeal, actor, reason string, revokeErr error) error {
+	previousStatus := appeal.Status
+	if previousStatus == domain.AppealStatusActive {
+		appeal.RevokedBy = actor
+		appeal.RevokeReason = reason
+	}
+	appeal.Status = domain.AppealStatusRevocationPending
+	appeal.RevocationAttempts++
+	appeal.RevocationError = revokeErr.Error()
+	appeal.UpdatedAt = s.TimeNow()
+	if err := s.repo.Update(ctx, appeal); err != nil {
+		return err
+	}
+	if previousStatus != appeal.Status {
+		s.publishStatusEvent(appeal, previousStatus, domain.AppealActionNameRevoke, "", actor)
+	}
+
+	if appeal.RevocationAttempts%s.config.RevocationRetry.getAlertAfter() != 0 {
+		return nil
+	}
+	notifications := []domain.Notification{}
+	for _, admin := range s.config.Admins {
+		notifications = append(notifications, domain.Notification{
+			User: admin,
+			Message: fmt.Sprintf("The access of %s to %s as %s is still granted after %d failed attempts to revoke it: %s",
+				appeal.User, appeal.Resource.URN, appeal.Role, appeal.RevocationAttempts, appeal.RevocationError),
+			Critical: true,
+			Labels:   appeal.Labels,
+		})
+	}
+	if err := s.notifier.Notify(notifications); err != nil {
+		s.getLogger(ctx).Error("failed to alert about the failing revocation", zap.Uint("appeal_id", appeal.ID), zap.Error(err))
+	}
+	return nil
+}
+
+// RetryPendingRevocations retries revoking the access of the revocation pending appeals, once the retry interval
+// has passed since their last attempt. The interval is ignored if force is true, e.g. to retry them manually
+func (s *Service) RetryPendingRevocations(ctx context.Context, force bool) error {
+	logger := s.getLogger(ctx)
+
+	appeals, err := s.repo.Find(ctx, map[string]interface{}{
+		"statuses": []string{domain.AppealStatusRevocationPending},
+	})
+	if err != nil {
+		return err
+	}
+
+	now := s.TimeNow()
+	interval := s.config.RevocationRetry.getInterval()
+	for _, a := range appeals {
+		if !force && now.Sub(a.UpdatedAt) < interval {
+			continue
+		}
+		if _, err := s.Revoke(ctx, a.ID, domain.SystemActorName, ""); err != nil {
+			logger.Error("failed to retry the revocation", zap.Uint("appeal_id", a.ID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
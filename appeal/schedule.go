@@ -0,0 +1,51 @@
+package appeal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+func hasStartDate(a *domain.Appeal) bool {
+	return a.Options != nil && a.Options.StartDate != nil && !a.Options.StartDate.IsZero()
+}
+
+func validateStartDate(a *domain.Appeal, now time.Time) error {
+	if !hasStartDate(a) {
+		return nil
+	}
+	if !a.Options.StartDate.After(now) {
+		return fmt.Errorf("%w: start date must be in the future", ErrInvalidStartDate)
+	}
+	if hasExpirationDate(a) && !a.Options.StartDate.Before(*a.Options.ExpirationDate) {
+		return fmt.Errorf("%w: start date must be before the expiration date", ErrInvalidStartDate)
+	}
+	return nil
+}
+
+// getAccessStartTime returns when the access is granted, assuming the appeal is approved at now
+func getAccessStartTime(a *domain.Appeal, now time.Time) time.Time {
+	if hasStartDate(a) && a.Options.StartDate.After(now) {
+		return *a.Options.StartDate
+	}
+	return now
+}
+
+// isAccessStarted returns true if the appeal doesn't have any start date
+func isAccessStarted(a *domain.Appeal, now time.Time) bool {
+	return !getAccessStartTime(a, now).After(now)
+}
+
+// getScheduledGrantTime returns when the scheduled access is granted, once both the start date arrives and the
+// undo window ends
+func getScheduledGrantTime(a *domain.Appeal) time.Time {
+	var grantTime time.Time
+	if hasStartDate(a) {
+		grantTime = *a.Options.StartDate
+	}
+	if a.UndoDeadline != nil && a.UndoDeadline.After(grantTime) {
+		grantTime = *a.UndoDeadline
+	}
+	return grantTime
+}
@@ -0,0 +1,21 @@
+package appeal
+
+import (
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// withSensitivityTier returns a copy of the policy with the steps of the sensitivity tier appended to its steps, so
+// the approvals of the tier are resolved the same as the policy steps. The tier steps always have approvers, the
+// stored appeal doesn't need the tier to advance past them
+func withSensitivityTier(p *domain.Policy, sensitivity string) (*domain.Policy, error) {
+	tier := p.GetSensitivityTier(sensitivity)
+	if tier == nil {
+		return nil, fmt.Errorf("%w: %q on policy %q version %d", ErrSensitivityTierNotFound, sensitivity, p.ID, p.Version)
+	}
+
+	tiered := *p
+	tiered.Steps = append(append([]*domain.Step{}, p.Steps...), tier.Steps...)
+	return &tiered, nil
+}
@@ -0,0 +1,54 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
+)
+
+// checkSeparationOfDuties returns ErrSeparationOfDuties if the appeal's policy requires the separation of duties and
+// the actor already approved another step of the appeal. A delegate acts as both themselves and the approver they
+// act on behalf of, so neither of them can approve another step, and an admin override counts as the admin's
+// approval. The steps resolved by the system don't count. The policy is only looked up once a conflict is found
+func (s *Service) checkSeparationOfDuties(ctx context.Context, a *domain.Appeal, approval *domain.Approval, actor, onBehalfOf string) error {
+	people := []string{actor}
+	if onBehalfOf != "" {
+		people = append(people, onBehalfOf)
+	}
+
+	var conflict *domain.Approval
+	for _, other := range a.Approvals {
+		if other == approval || other.Status != domain.ApprovalStatusApproved || other.Actor == nil || *other.Actor == domain.SystemActorName {
+			continue
+		}
+		if utils.ContainsString(people, *other.Actor) || (other.OnBehalfOf != "" && utils.ContainsString(people, other.OnBehalfOf)) {
+			conflict = other
+			break
+		}
+	}
+	if conflict == nil {
+		return nil
+	}
+
+	p := a.Policy
+	if p == nil {
+		if a.PolicyID == "" {
+			return nil
+		}
+		var err error
+		if p, err = s.policyService.GetOne(ctx, a.PolicyID, a.PolicyVersion); err != nil {
+			return err
+		}
+	}
+	if p == nil || !p.SeparationOfDuties {
+		return nil
+	}
+
+	approver := *conflict.Actor
+	if conflict.OnBehalfOf != "" {
+		approver = fmt.Sprintf("%s on behalf of %s", approver, conflict.OnBehalfOf)
+	}
+	return fmt.Errorf("%w: step %q is already approved by %s", ErrSeparationOfDuties, conflict.Name, approver)
+}
@@ -1,27 +1,81 @@
 package appeal
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/mcuadros/go-lookup"
+	"github.com/odpf/guardian/auth"
 	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/logger"
+	"github.com/odpf/guardian/metrics"
+	"github.com/odpf/guardian/tracing"
 	"github.com/odpf/guardian/utils"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+const tracerName = "github.com/odpf/guardian/appeal"
+
 var TimeNow = time.Now
 
 type resourceConfig struct {
 	policy           *domain.PolicyConfig
+	resourcePolicies map[string]*domain.PolicyConfig
+	tagPolicies      []*domain.TagPolicyConfig
 	availableRoleIDs []string
+	// roleMaxDurations holds the max access duration of each role, falling back to the provider's
+	roleMaxDurations map[string]string
+	// roleAllowedGroups holds the groups allowed to appeal for each role restricted to groups
+	roleAllowedGroups map[string][]string
+	// roleSensitivities holds the sensitivity tier of each role having one
+	roleSensitivities map[string]string
+	// roleResourceRequirements holds the resource attribute conditions of each role having any
+	roleResourceRequirements map[string][]*domain.Condition
+}
+
+// getPolicy returns the policy configured for the resource, then the first policy matching the
+// resource tags, falling back to the resource type policy
+func (c *resourceConfig) getPolicy(r *domain.Resource) *domain.PolicyConfig {
+	if p := c.resourcePolicies[r.URN]; p != nil {
+		return p
+	}
+	for _, tp := range c.tagPolicies {
+		if tp.Match(r) {
+			return tp.Policy
+		}
+	}
+	return c.policy
 }
+
 type providerConfig struct {
 	appeal    *domain.AppealConfig
 	resources map[string]*resourceConfig
+	// resourceTypes lists the configured resource types along with their aliases, in the config order
+	resourceTypes []string
+}
+
+// getResourceConfig returns the config of the resource type, matching either the type or one of its aliases
+func (c *providerConfig) getResourceConfig(resourceType string) (*resourceConfig, error) {
+	if rc := c.resources[resourceType]; rc != nil {
+		return rc, nil
+	}
+	return nil, &ResourceTypeNotFoundError{
+		ResourceType:   resourceType,
+		AvailableTypes: c.resourceTypes,
+	}
 }
 
 // Service handling the business logics
@@ -34,10 +88,35 @@ type Service struct {
 	policyService   domain.PolicyService
 	iamService      domain.IAMService
 	notifier        domain.Notifier
+	blobStorage     domain.BlobStorage
 	logger          *zap.Logger
+	config          *Config
 
 	validator *validator.Validate
 	TimeNow   func() time.Time
+	// AttachmentScanner is called on every uploaded attachment before storing it, if set
+	AttachmentScanner domain.AttachmentScanner
+	// Tracer traces the appeal lifecycle. Default: tracer of the global tracer provider
+	Tracer trace.Tracer
+	// WarningRules flag the unusual appeals on creation. Default: the rules enabled in the config
+	WarningRules []WarningRule
+	// DecisionEvaluator is consulted on appeal creation and on every approval to approve or reject the
+	// appeal without waiting for the remaining approval steps, if set
+	DecisionEvaluator DecisionEvaluator
+	// AccessHooks run around the access changes in the providers. Default: the webhook enabled in the config
+	AccessHooks []AccessHook
+	// Metrics records the appeal lifecycle counters and timings. Default: metrics.Noop
+	Metrics domain.Metrics
+	// StepChecker evaluates the external checks of the approval steps. Default: the HTTP checker
+	StepChecker StepChecker
+	// StatusBroker receives the status events of the actions made on the appeals. Default: a broker with the
+	// default buffer and history sizes
+	StatusBroker *StatusBroker
+	// Signer signs the approval attestations. Required if the approval attestation is enabled
+	Signer domain.Signer
+	// DelegationService lets the delegates of the approvers act on their behalf while the delegations are active,
+	// if set
+	DelegationService domain.ApprovalDelegationService
 }
 
 // NewService returns service struct
@@ -49,9 +128,15 @@ func NewService(
 	policyService domain.PolicyService,
 	iamService domain.IAMService,
 	notifier domain.Notifier,
+	blobStorage domain.BlobStorage,
 	logger *zap.Logger,
+	config *Config,
 ) *Service {
-	return &Service{
+	if config == nil {
+		config = &Config{}
+	}
+
+	s := &Service{
 		repo:            appealRepository,
 		approvalService: approvalService,
 		resourceService: resourceService,
@@ -59,149 +144,526 @@ func NewService(
 		policyService:   policyService,
 		iamService:      iamService,
 		notifier:        notifier,
+		blobStorage:     blobStorage,
 		validator:       validator.New(),
 		logger:          logger,
+		config:          config,
 		TimeNow:         time.Now,
+		Tracer:          tracing.Tracer(tracerName),
+		Metrics:         metrics.Noop{},
+		StepChecker:     NewHTTPStepChecker(),
+		StatusBroker:    NewStatusBroker(0, 0),
+	}
+	s.WarningRules = config.Warnings.getRules(func() time.Time { return s.TimeNow() })
+	if webhook := NewAccessWebhook(config.AccessWebhook); webhook != nil {
+		s.AccessHooks = append(s.AccessHooks, webhook)
 	}
+
+	return s
 }
 
 // GetByID returns one record by id
-func (s *Service) GetByID(id uint) (*domain.Appeal, error) {
+func (s *Service) GetByID(ctx context.Context, id uint) (*domain.Appeal, error) {
 	if id == 0 {
 		return nil, ErrAppealIDEmptyParam
 	}
 
-	return s.repo.GetByID(id)
+	return s.repo.GetByID(ctx, id)
+}
+
+// HasActiveAccess checks whether the user currently has an active and unexpired access to the resource
+// with the role, along with the appeal granting it
+func (s *Service) HasActiveAccess(ctx context.Context, user string, resourceID uint, role string) (bool, *domain.Appeal, error) {
+	if user == "" || resourceID == 0 || role == "" {
+		return false, nil, ErrAccessCheckInvalidParams
+	}
+
+	resource := &domain.Resource{ID: resourceID}
+	if s.config.NormalizeResourceURN {
+		resources, err := s.getResourceMap(ctx, []uint{resourceID})
+		if err != nil {
+			return false, nil, err
+		}
+		if resources[resourceID] == nil {
+			return false, nil, ErrResourceNotFound
+		}
+		resource = resources[resourceID]
+	}
+
+	appeal, err := s.getActiveAccess(ctx, user, resource, role)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return appeal != nil, appeal, nil
+}
+
+// GetUserAccessSummary returns every active access of the user across the providers, i.e. what the user can
+// currently do. If withPermissions is true, each entry lists the concrete permissions implied by its role
+// according to the provider config
+func (s *Service) GetUserAccessSummary(ctx context.Context, user string, withPermissions bool) ([]domain.AccessEntry, error) {
+	if user == "" {
+		return nil, ErrUserEmptyParam
+	}
+
+	entries, err := s.repo.GetAccessSummary(ctx, user, s.TimeNow())
+	if err != nil {
+		return nil, err
+	}
+	if !withPermissions {
+		return entries, nil
+	}
+
+	permissions := map[string][]interface{}{}
+	for i, e := range entries {
+		key := strings.Join([]string{e.ProviderType, e.ProviderURN, e.ResourceType, e.Role}, "/")
+		p, cached := permissions[key]
+		if !cached {
+			if p, err = s.providerService.GetRolePermissions(ctx, e.ProviderType, e.ProviderURN, e.ResourceType, e.Role); err != nil {
+				return nil, fmt.Errorf("getting permissions of role %q on %s: %w", e.Role, e.ResourceURN, err)
+			}
+			permissions[key] = p
+		}
+		entries[i].Permissions = p
+	}
+
+	return entries, nil
 }
 
 // Find appeals by filters
-func (s *Service) Find(filters map[string]interface{}) ([]*domain.Appeal, error) {
-	return s.repo.Find(filters)
+func (s *Service) Find(ctx context.Context, filters map[string]interface{}) ([]*domain.Appeal, error) {
+	return s.repo.Find(ctx, filters)
 }
 
-// Create record
-func (s *Service) Create(appeals []*domain.Appeal) error {
+// Create creates the appeals. The resource of an appeal is identified by either its resource id, or its provider
+// type, provider urn, and urn in the appeal resource, which are resolved to the resource id
+func (s *Service) Create(ctx context.Context, appeals []*domain.Appeal) (err error) {
+	ctx, span := s.Tracer.Start(ctx, "appeal.Create", trace.WithAttributes(tracing.AppealCountKey.Int(len(appeals))))
+	start := time.Now()
+	defer func() {
+		tracing.End(span, err)
+		s.recordDuration(metrics.AppealCreateDuration, start, err, nil)
+	}()
+
+	logger := s.getLogger(ctx)
+	logger.Info("creating appeals", zap.Int("count", len(appeals)))
+	defer func() {
+		if err != nil {
+			logger.Error("failed to create appeals", zap.Int("count", len(appeals)), zap.Error(err))
+			return
+		}
+		for _, a := range appeals {
+			tags := getResourceTags(a)
+			tags["status"] = a.Status
+			s.Metrics.Count(metrics.AppealCreated, 1, tags)
+			logger.Info("appeal created",
+				zap.Uint("appeal_id", a.ID),
+				zap.String("user", a.User),
+				zap.Uint("resource_id", a.ResourceID),
+				zap.String("role", a.Role),
+				zap.String("status", a.Status),
+			)
+		}
+	}()
+
+	if err := s.checkRateLimit(ctx, appeals); err != nil {
+		return err
+	}
+	if err := s.resolveResourceIDs(ctx, appeals); err != nil {
+		return err
+	}
+
 	resourceIDs := []uint{}
 	for _, a := range appeals {
 		resourceIDs = append(resourceIDs, a.ResourceID)
 	}
-	resources, err := s.getResourceMap(resourceIDs)
+	resources, err := s.getResourceMap(ctx, resourceIDs)
 	if err != nil {
 		return err
 	}
-	providerConfigs, err := s.getProviderConfigs()
+	providerConfigs, err := s.getProviderConfigs(ctx)
 	if err != nil {
 		return err
 	}
-	policies, err := s.getPolicies()
+	policies, err := s.getPolicies(ctx)
 	if err != nil {
 		return err
 	}
-	pendingAppeals, err := s.getPendingAppeals()
+	pendingAppeals, err := s.getPendingAppeals(ctx)
 	if err != nil {
 		return err
 	}
+	var pendingAppealsByResourceKey map[string]map[string]map[string]*domain.Appeal
+	if s.config.NormalizeResourceURN {
+		if pendingAppealsByResourceKey, err = s.getPendingAppealsByResourceKey(ctx, pendingAppeals); err != nil {
+			return err
+		}
+	}
 
-	notifications := []domain.Notification{}
+	groupID := ""
+	if len(appeals) > 1 {
+		if groupID, err = newGroupID(); err != nil {
+			return err
+		}
+	}
 
-	for _, a := range appeals {
-		if pendingAppeals[a.User] != nil &&
-			pendingAppeals[a.User][a.ResourceID] != nil &&
-			pendingAppeals[a.User][a.ResourceID][a.Role] != nil {
-			return ErrAppealDuplicate
+	batch := &createBatch{
+		groupID:                     groupID,
+		resources:                   resources,
+		providerConfigs:             providerConfigs,
+		policies:                    policies,
+		pendingAppeals:              pendingAppeals,
+		pendingAppealsByResourceKey: pendingAppealsByResourceKey,
+	}
+	decisions, err := s.prepareAppeals(ctx, logger, appeals, batch)
+	if err != nil {
+		return err
+	}
+
+	notifications := []domain.Notification{}
+	activatedAppeals := []*domain.Appeal{}
+	// approverLoads spreads the appeals created together across the approvers of the assigned steps
+	approverLoads := map[string]*domain.ApproverLoad{}
+
+	for i, a := range appeals {
+		decision := decisions[i]
+		if decision != nil && decision.Result == DecisionApprove {
+			if err := s.activate(ctx, a); err != nil {
+				return err
+			}
+			activatedAppeals = append(activatedAppeals, a)
+			notifications = append(notifications, domain.Notification{
+				User:    a.User,
+				Message: fmt.Sprintf("Your appeal to %s has been approved", a.Resource.URN),
+				Labels:  a.Labels,
+			})
+		} else if decision != nil && decision.Result == DecisionReject {
+			notifications = append(notifications, domain.Notification{
+				User:    a.User,
+				Message: fmt.Sprintf("Your appeal to %s is rejected", a.Resource.URN),
+				Labels:  a.Labels,
+			})
+		} else {
+			if err := s.assignApprover(ctx, a, approverLoads); err != nil {
+				return err
+			}
+			notifications = append(notifications, s.getApprovalNotifications(a)...)
 		}
+	}
 
-		r := resources[a.ResourceID]
-		if r == nil {
-			return ErrResourceNotFound
+	if err := s.repo.BulkInsert(ctx, appeals); err != nil {
+		// revoke the access granted by the policy decisions as the appeals are not stored
+		for _, a := range activatedAppeals {
+			if a.AccessScheduled || a.AccessWindowClosed {
+				continue
+			}
+			if err := s.providerService.RevokeAccess(ctx, a); err != nil {
+				logger.Error("failed to revoke access granted by policy decision", zap.String("user", a.User), zap.Uint("resource_id", a.ResourceID), zap.Error(err))
+			}
 		}
-		a.Resource = r
+		return err
+	}
 
-		if providerConfigs[a.Resource.ProviderType] == nil {
-			return ErrProviderTypeNotFound
-		} else if providerConfigs[a.Resource.ProviderType][a.Resource.ProviderURN] == nil {
-			return ErrProviderURNNotFound
+	notifications = append(notifications, getGroupSubmittedNotifications(appeals)...)
+	notifications = uniqueNotifications(notifications)
+	if len(notifications) > 0 {
+		if err := s.notifier.Notify(notifications); err != nil {
+			s.logger.Error(err.Error())
 		}
-		providerConfig := providerConfigs[a.Resource.ProviderType][a.Resource.ProviderURN]
+	}
+
+	return nil
+}
+
+// createBatch holds the lookups shared by the appeals created together
+type createBatch struct {
+	groupID         string
+	resources       map[uint]*domain.Resource
+	providerConfigs map[string]map[string]*providerConfig
+	policies        map[string]map[uint]*domain.Policy
+	pendingAppeals  map[string]map[uint]map[string]*domain.Appeal
+	// pendingAppealsByResourceKey is only set if the resource URN normalization is enabled
+	pendingAppealsByResourceKey map[string]map[string]map[string]*domain.Appeal
+}
+
+// prepareAppeals validates the appeals and resolves their approval steps, with up to the configured number
+// of appeals processed concurrently. It returns the policy decision of each appeal, in the same order. The
+// errors of all the failed appeals are reported, along with their index in the batch
+func (s *Service) prepareAppeals(ctx context.Context, logger *zap.Logger, appeals []*domain.Appeal, batch *createBatch) ([]*Decision, error) {
+	decisions := make([]*Decision, len(appeals))
+	errs := make([]error, len(appeals))
+
+	sem := make(chan struct{}, s.config.getCreateConcurrency())
+	var wg sync.WaitGroup
+	for i, a := range appeals {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, a *domain.Appeal) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			decisions[i], errs[i] = s.prepareAppeal(ctx, logger, a, batch)
+		}(i, a)
+	}
+	wg.Wait()
+
+	if err := newBatchCreateError(errs); err != nil {
+		return nil, err
+	}
+	return decisions, nil
+}
+
+// prepareAppeal validates the appeal and resolves its approval steps. It doesn't make any change outside the
+// appeal so the appeals of a batch can be prepared concurrently
+func (s *Service) prepareAppeal(ctx context.Context, logger *zap.Logger, a *domain.Appeal, batch *createBatch) (*Decision, error) {
+	if a.GroupID == "" {
+		a.GroupID = batch.groupID
+	}
+	if a.Priority == "" {
+		a.Priority = domain.AppealPriorityNormal
+	} else if !utils.ContainsString(domain.AppealPriorities, a.Priority) {
+		return nil, ErrInvalidPriority
+	}
+	if err := validateLabels(a.Labels, s.config.LabelSchema); err != nil {
+		return nil, err
+	}
 
-		if providerConfig.resources[a.Resource.Type] == nil {
-			return ErrResourceTypeNotFound
+	if a.Options != nil && a.Options.AccessWindow != nil {
+		if _, err := parseAccessWindow(a.Options.AccessWindow); err != nil {
+			return nil, err
 		}
+	}
+	if err := validateStartDate(a, s.TimeNow()); err != nil {
+		return nil, err
+	}
 
-		appealConfig := providerConfig.appeal
-		if !appealConfig.AllowPermanentAccess {
-			if a.Options == nil || a.Options.ExpirationDate == nil {
-				return ErrOptionsExpirationDateOptionNotFound
-			} else if a.Options.ExpirationDate.IsZero() {
-				return ErrExpirationDateIsRequired
-			}
+	if batch.pendingAppeals[a.User] != nil &&
+		batch.pendingAppeals[a.User][a.ResourceID] != nil &&
+		batch.pendingAppeals[a.User][a.ResourceID][a.Role] != nil {
+		return nil, ErrAppealDuplicate
+	}
+
+	r := batch.resources[a.ResourceID]
+	if r == nil {
+		return nil, ErrResourceNotFound
+	}
+	if r.DenyAppeals {
+		logger.Warn("appeal to a resource on the deny list",
+			zap.String("user", a.User),
+			zap.Uint("resource_id", r.ID),
+			zap.String("resource_urn", r.URN),
+			zap.String("role", a.Role),
+		)
+		if r.DenyAppealsReason != "" {
+			return nil, fmt.Errorf("%w: %s", ErrResourceNotAppealable, r.DenyAppealsReason)
 		}
+		return nil, ErrResourceNotAppealable
+	}
+	if batch.pendingAppealsByResourceKey != nil &&
+		batch.pendingAppealsByResourceKey[a.User][s.getResourceKey(r)][a.Role] != nil {
+		return nil, ErrAppealDuplicate
+	}
+	a.Resource = r
+	a.OrganizationID = r.OrganizationID
+
+	if batch.providerConfigs[a.Resource.ProviderType] == nil {
+		return nil, ErrProviderTypeNotFound
+	} else if batch.providerConfigs[a.Resource.ProviderType][a.Resource.ProviderURN] == nil {
+		return nil, ErrProviderURNNotFound
+	}
+	providerConfig := batch.providerConfigs[a.Resource.ProviderType][a.Resource.ProviderURN]
+
+	resourceConfig, err := providerConfig.getResourceConfig(a.Resource.Type)
+	if err != nil {
+		return nil, err
+	}
 
-		resourceConfig := providerConfig.resources[a.Resource.Type]
-		if !utils.ContainsString(resourceConfig.availableRoleIDs, a.Role) {
-			return ErrInvalidRole
+	appealConfig := providerConfig.appeal
+	if !appealConfig.AllowPermanentAccess {
+		if a.Options == nil || a.Options.ExpirationDate == nil {
+			return nil, ErrOptionsExpirationDateOptionNotFound
+		} else if a.Options.ExpirationDate.IsZero() {
+			return nil, ErrExpirationDateIsRequired
+		}
+	} else if a.Options == nil || a.Options.ExpirationDate == nil || a.Options.ExpirationDate.IsZero() {
+		capabilities, err := s.providerService.GetCapabilities(a.Resource.ProviderType)
+		if err != nil {
+			return nil, err
 		}
+		if !capabilities.PermanentAccess {
+			return nil, ErrPermanentAccessNotSupported
+		}
+	}
 
-		policyConfig := resourceConfig.policy
-		if policies[policyConfig.ID] == nil {
-			return ErrPolicyIDNotFound
-		} else if policies[policyConfig.ID][uint(policyConfig.Version)] == nil {
-			return ErrPolicyVersionNotFound
+	if !utils.ContainsString(resourceConfig.availableRoleIDs, a.Role) {
+		return nil, &InvalidRoleError{
+			ResourceType:   a.Resource.Type,
+			Role:           a.Role,
+			AvailableRoles: resourceConfig.availableRoleIDs,
+		}
+	}
+	if err := validateRoleMaxDuration(a, resourceConfig.roleMaxDurations[a.Role], s.TimeNow()); err != nil {
+		return nil, err
+	}
+	if allowedGroups := resourceConfig.roleAllowedGroups[a.Role]; len(allowedGroups) > 0 {
+		userGroups, err := s.iamService.GetUserGroups(a.User)
+		if err != nil {
+			return nil, fmt.Errorf("resolving the groups of %q: %w", a.User, err)
+		}
+		if !isRoleEligible(allowedGroups, userGroups) {
+			return nil, fmt.Errorf("%w: %s", ErrRoleNotEligible, strings.Join(allowedGroups, ", "))
 		}
-		a.Policy = policies[policyConfig.ID][uint(policyConfig.Version)]
+	}
+	if err := checkResourceRequirements(a.Resource, a.Role, resourceConfig.roleResourceRequirements[a.Role]); err != nil {
+		return nil, err
+	}
 
-		approvals := []*domain.Approval{}
-		for i, step := range a.Policy.Steps { // TODO: move this logic to approvalService
-			var approvers []string
-			if step.Approvers != "" {
-				approvers, err = s.resolveApprovers(a.User, a.Resource, step.Approvers)
-				if err != nil {
-					return err
-				}
-			}
+	policyConfig, policy, err := s.getResourcePolicy(logger, providerConfig, batch.policies, a.Resource)
+	if err != nil {
+		return nil, err
+	}
+	a.Policy = policy
+	if sensitivity := resourceConfig.roleSensitivities[a.Role]; sensitivity != "" {
+		if a.Policy, err = withSensitivityTier(policy, sensitivity); err != nil {
+			return nil, err
+		}
+	}
 
+	approvals := []*domain.Approval{}
+	for i, step := range a.Policy.Steps { // TODO: move this logic to approvalService
+		required, err := isStepRequired(step, a, s.TimeNow())
+		if err != nil {
+			return nil, err
+		}
+		if !required {
 			approvals = append(approvals, &domain.Approval{
-				Name:          step.Name,
-				Index:         i,
-				Status:        domain.ApprovalStatusPending,
-				PolicyID:      policyConfig.ID,
-				PolicyVersion: uint(policyConfig.Version),
-				Approvers:     approvers,
+				Name:                step.Name,
+				Index:               i,
+				Status:              domain.ApprovalStatusSkipped,
+				PolicyID:            policyConfig.ID,
+				PolicyVersion:       uint(policyConfig.Version),
+				NotificationChannel: step.NotificationChannel,
 			})
+			continue
 		}
 
-		a.PolicyID = policyConfig.ID
-		a.PolicyVersion = uint(policyConfig.Version)
-		a.Status = domain.AppealStatusPending
-		a.Approvals = approvals
+		var approvers []string
+		status := domain.ApprovalStatusPending
+		if step.Approvers != "" {
+			approvers, err = s.resolveApprovers(a.User, a.Resource, step)
+			if err != nil {
+				if approvers, status, err = s.applyApproverResolutionFallback(logger, a, step, err); err != nil {
+					return nil, err
+				}
+			}
+			if len(approvers) == 0 && status == domain.ApprovalStatusPending {
+				return nil, ErrApproversNotFound
+			}
+		}
 
-		if err := s.approvalService.AdvanceApproval(a); err != nil {
-			return err
+		if step.SLA != "" {
+			if _, err := time.ParseDuration(step.SLA); err != nil {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidStepSLA, step.SLA)
+			}
 		}
-		a.Policy = nil
 
-		notifications = append(notifications, getApprovalNotifications(a)...)
+		approvals = append(approvals, &domain.Approval{
+			Name:                step.Name,
+			Index:               i,
+			Status:              status,
+			PolicyID:            policyConfig.ID,
+			PolicyVersion:       uint(policyConfig.Version),
+			Approvers:           approvers,
+			NotificationChannel: step.NotificationChannel,
+			SLA:                 step.SLA,
+			AssignmentStrategy:  step.AssignmentStrategy,
+			ReassignAfter:       step.ReassignAfter,
+			RequireReason:       step.RequireReason,
+			DelegationChain:     step.DelegationChain,
+		})
 	}
 
-	if err := s.repo.BulkInsert(appeals); err != nil {
-		return err
+	a.PolicyID = policyConfig.ID
+	a.PolicyVersion = uint(policyConfig.Version)
+	a.Status = domain.AppealStatusPending
+	a.Approvals = approvals
+
+	warnings, err := s.evaluateWarnings(ctx, a)
+	if err != nil {
+		return nil, err
 	}
+	a.Warnings = warnings
 
-	if len(notifications) > 0 {
-		if err := s.notifier.Notify(notifications); err != nil {
-			s.logger.Error(err.Error())
-		}
+	decision, err := s.evaluateDecision(ctx, DecisionPointCreate, a, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyDecision(a, decision, s.TimeNow()); err != nil {
+		return nil, err
 	}
+	checked := s.runStepChecks(ctx, logger, a)
 
-	return nil
+	if err := s.approvalService.AdvanceApproval(ctx, a); err != nil {
+		return nil, err
+	}
+	a.Policy = nil
+
+	// the appeal is approved once the checks resolve all of the remaining steps
+	if checked && a.Status == domain.AppealStatusPending && isApprovalCompleted(a.Approvals) {
+		decision = &Decision{Result: DecisionApprove, Reason: stepCheckPassedReason}
+	}
+
+	return decision, nil
 }
 
-// Approve an approval step
-func (s *Service) MakeAction(approvalAction domain.ApprovalAction) (*domain.Appeal, error) {
-	if err := utils.ValidateStruct(approvalAction); err != nil {
+// MakeAction approves, rejects, or requests changes on an approval step. It returns the just-persisted appeal along with its
+// approvals, the same as a fresh GetByID would return, or nil if the appeal is not found
+func (s *Service) MakeAction(ctx context.Context, approvalAction domain.ApprovalAction) (result *domain.Appeal, err error) {
+	ctx, span := s.Tracer.Start(ctx, "appeal.MakeAction", trace.WithAttributes(
+		tracing.AppealID(approvalAction.AppealID),
+		tracing.ApprovalNameKey.String(approvalAction.ApprovalName),
+		tracing.ActionKey.String(approvalAction.Action),
+	))
+	start := time.Now()
+	defer func() {
+		tags := getResourceTags(result)
+		tags["action"] = approvalAction.Action
+		if result != nil {
+			span.SetAttributes(tracing.AppealStatusKey.String(result.Status))
+			tags["status"] = result.Status
+		}
+		tracing.End(span, err)
+		s.recordDuration(metrics.ApprovalActionDuration, start, err, tags)
+		if err == nil && result != nil {
+			s.Metrics.Count(metrics.ApprovalAction, 1, tags)
+		}
+	}()
+
+	logger := s.getLogger(ctx).With(
+		zap.Uint("appeal_id", approvalAction.AppealID),
+		zap.String("approval_name", approvalAction.ApprovalName),
+		zap.String("actor", approvalAction.Actor),
+		zap.String("action", approvalAction.Action),
+	)
+	logger.Info("making action on approval step")
+	defer func() {
+		if err != nil {
+			logger.Error("failed to make action on approval step", zap.Error(err))
+		} else if result == nil {
+			logger.Info("appeal not found")
+		} else {
+			logger.Info("action made on approval step", zap.String("user", result.User), zap.String("status", result.Status))
+		}
+	}()
+
+	if err := validateApprovalAction(approvalAction); err != nil {
+		return nil, err
+	}
+	if err := checkActor(ctx, approvalAction.Actor); err != nil {
 		return nil, err
 	}
-	appeal, err := s.repo.GetByID(approvalAction.AppealID)
+	appeal, err := s.repo.GetByID(ctx, approvalAction.AppealID)
 	if err != nil {
 		return nil, err
 	}
@@ -209,156 +671,1481 @@ func (s *Service) MakeAction(approvalAction domain.ApprovalAction) (*domain.Appe
 		return nil, nil
 	}
 
-	if err := checkIfAppealStatusStillPending(appeal.Status); err != nil {
+	return s.applyApprovalAction(ctx, appeal, approvalAction, false)
+}
+
+// validateApprovalAction validates the action fields, a comment is required along with the request changes action
+func validateApprovalAction(approvalAction domain.ApprovalAction) error {
+	if err := utils.ValidateStruct(approvalAction); err != nil {
+		return err
+	}
+	if approvalAction.Action == domain.AppealActionNameRequestChanges && strings.TrimSpace(approvalAction.Comment) == "" {
+		return ErrChangesCommentRequired
+	}
+	return nil
+}
+
+// checkActor makes sure the actor of the action is the authenticated actor carried by ctx, if any
+func checkActor(ctx context.Context, actor string) error {
+	if ctxActor, ok := auth.ActorFromContext(ctx); ok && ctxActor != actor {
+		return ErrActorMismatch
+	}
+	return nil
+}
+
+// FilterKeyAllowBroad is the MakeActionByFilter filter key to opt in for the filters that
+// don't narrow down the appeals by resource or user
+const FilterKeyAllowBroad = "allow_broad"
+
+// MakeActionByFilter makes the same action on every pending appeal matching the filters which
+// current approval step is the action's approval name and waiting for the actor. The action's
+// appeal id is ignored. The action is checked on all the matching appeals before it's made on any
+// of them, nothing is changed and a *BulkActionError listing the appeals that failed is returned if
+// it can't be made on every one of them. The appeals are stored in a single transaction
+func (s *Service) MakeActionByFilter(ctx context.Context, filters map[string]interface{}, approvalAction domain.ApprovalAction) ([]*domain.Appeal, error) {
+	logger := s.getLogger(ctx).With(
+		zap.String("approval_name", approvalAction.ApprovalName),
+		zap.String("actor", approvalAction.Actor),
+		zap.String("action", approvalAction.Action),
+	)
+
+	if err := checkActor(ctx, approvalAction.Actor); err != nil {
+		return nil, err
+	}
+	conditions, err := getBulkConditions(filters, domain.AppealStatusPending)
+	if err != nil {
 		return nil, err
 	}
+	conditions["with_approvals"] = true
 
-	for i, approval := range appeal.Approvals {
-		if approval.Name != approvalAction.ApprovalName {
-			if err := checkPreviousApprovalStatus(approval.Status); err != nil {
-				return nil, err
-			}
+	appeals, err := s.repo.Find(ctx, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	stepActions := []*approvalStepAction{}
+	failed := map[uint]error{}
+	for _, appeal := range appeals {
+		approval := appeal.GetNextPendingApproval()
+		if approval == nil || approval.Name != approvalAction.ApprovalName {
 			continue
-		} else {
-			if approval.Status != domain.ApprovalStatusPending {
-				if err := checkApprovalStatus(approval.Status); err != nil {
-					return nil, err
-				}
-			}
+		}
+		isAllowed, err := s.isAllowedActor(ctx, approval, approvalAction.Actor)
+		if err != nil {
+			failed[appeal.ID] = err
+			continue
+		}
+		if !isAllowed {
+			continue
+		}
 
-			if !utils.ContainsString(approval.Approvers, approvalAction.Actor) {
-				return nil, ErrActionForbidden
-			}
+		action := approvalAction
+		action.AppealID = appeal.ID
+		if err := validateApprovalAction(action); err != nil {
+			return nil, err
+		}
 
-			approval.Actor = &approvalAction.Actor
-			approval.UpdatedAt = TimeNow()
+		stepAction, err := s.checkApprovalAction(ctx, appeal, action, false)
+		if err != nil {
+			failed[appeal.ID] = err
+			continue
+		}
+		stepActions = append(stepActions, stepAction)
+	}
+	if len(failed) > 0 {
+		logger.Info("action by filter rejected", zap.Int("matched", len(appeals)), zap.Int("failed", len(failed)))
+		return nil, &BulkActionError{Failed: failed}
+	}
 
-			if approvalAction.Action == domain.AppealActionNameApprove {
-				approval.Status = domain.ApprovalStatusApproved
-				if err := s.approvalService.AdvanceApproval(appeal); err != nil {
-					return nil, err
-				}
+	result := []*domain.Appeal{}
+	if approvalAction.Action == domain.AppealActionNameRequestChanges {
+		for _, sa := range stepActions {
+			appeal, err := s.requestChanges(ctx, sa.appeal, sa.appeal.Approvals[sa.index], sa.action)
+			if err != nil {
+				return result, &BulkActionError{Failed: map[uint]error{sa.appeal.ID: err}}
+			}
+			result = append(result, appeal)
+		}
+		return result, nil
+	}
 
-				// TODO: decide if appeal status should be marked as active by checking
-				// through all approval step statuses
-				if i == len(appeal.Approvals)-1 {
-					if err := s.providerService.GrantAccess(appeal); err != nil {
-						return nil, err
-					}
+	// the access granted in the provider can't be part of the transaction, it's revoked back if any of the
+	// appeals fails to be updated or stored
+	activated := []*domain.Appeal{}
+	revokeActivated := func() {
+		for _, a := range activated {
+			if err := s.providerService.RevokeAccess(ctx, a); err != nil {
+				logger.Error("failed to revoke access of the unstored appeal", zap.Uint("appeal_id", a.ID), zap.Error(err))
+			}
+		}
+	}
+	for _, sa := range stepActions {
+		if err := s.updateApprovalStep(ctx, sa); err != nil {
+			revokeActivated()
+			return nil, &BulkActionError{Failed: map[uint]error{sa.appeal.ID: err}}
+		}
+		if sa.appeal.Status == domain.AppealStatusActive && !sa.appeal.AccessScheduled && !sa.appeal.AccessWindowClosed {
+			activated = append(activated, sa.appeal)
+		}
+	}
+	messages := []*domain.OutboxMessage{}
+	if err := s.repo.WithTransaction(ctx, func(tx domain.AppealRepository) error {
+		for _, sa := range stepActions {
+			if err := s.enforceTeamQuota(ctx, tx, sa); err != nil {
+				return err
+			}
+			if err := tx.Update(ctx, sa.appeal); err != nil {
+				return err
+			}
+		}
+		// the messages are added once all the appeals are stored, for the groups resolved by the batch
+		for _, sa := range stepActions {
+			message, err := s.addApprovalActionMessage(ctx, tx, sa)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, message)
+		}
+		return nil
+	}); err != nil {
+		revokeActivated()
+		return nil, err
+	}
 
-					appeal.Status = domain.AppealStatusActive
-				}
+	for _, message := range messages {
+		s.dispatchOutboxMessage(ctx, message)
+	}
+	for _, sa := range stepActions {
+		result = append(result, sa.appeal)
+	}
 
-			} else if approvalAction.Action == domain.AppealActionNameReject {
-				approval.Status = domain.ApprovalStatusRejected
-				appeal.Status = domain.AppealStatusRejected
+	logger.Info("action made on appeals by filter", zap.Int("matched", len(appeals)), zap.Int("succeeded", len(result)))
+	return result, nil
+}
 
-				if i < len(appeal.Approvals)-1 {
-					for j := i + 1; j < len(appeal.Approvals); j++ {
-						appeal.Approvals[j].Status = domain.ApprovalStatusSkipped
-						appeal.Approvals[j].UpdatedAt = TimeNow()
-					}
-				}
-			} else {
-				return nil, ErrActionInvalidValue
-			}
+// getBulkConditions returns the repository filters of a bulk operation on the appeals with the status.
+// The filters should narrow down the appeals by resource or user unless FilterKeyAllowBroad is set
+func getBulkConditions(filters map[string]interface{}, status string) (map[string]interface{}, error) {
+	allowBroad, _ := filters[FilterKeyAllowBroad].(bool)
+	if !allowBroad && filters["resource_id"] == nil && filters["user"] == nil {
+		return nil, ErrBroadFilter
+	}
 
-			if err := s.repo.Update(appeal); err != nil {
-				if err := s.providerService.RevokeAccess(appeal); err != nil {
-					return nil, err
-				}
-				return nil, err
-			}
+	conditions := map[string]interface{}{}
+	for k, v := range filters {
+		if k != FilterKeyAllowBroad {
+			conditions[k] = v
+		}
+	}
+	conditions["statuses"] = []string{status}
+	return conditions, nil
+}
 
-			notifications := []domain.Notification{}
-			if appeal.Status == domain.AppealStatusActive {
-				notifications = append(notifications, domain.Notification{
-					User:    appeal.User,
-					Message: fmt.Sprintf("Your appeal to %s has been approved", appeal.Resource.URN),
-				})
-			} else if appeal.Status == domain.AppealStatusRejected {
-				notifications = append(notifications, domain.Notification{
-					User:    appeal.User,
-					Message: fmt.Sprintf("Your appeal to %s is rejected", appeal.Resource.URN),
-				})
-			} else {
-				notifications = append(notifications, getApprovalNotifications(appeal)...)
-			}
-			if len(notifications) > 0 {
-				if err := s.notifier.Notify(notifications); err != nil {
-					s.logger.Error(err.Error())
-				}
+// AdminApprove approves an approval step on behalf of the approvers. It is
+// meant to unblock stuck appeals, so it is restricted to the configured admins
+// and the approval is flagged as overridden along with the reason.
+func (s *Service) AdminApprove(ctx context.Context, appealID uint, approvalName, adminActor, reason string) (result *domain.Appeal, err error) {
+	logger := s.getLogger(ctx).With(
+		zap.Uint("appeal_id", appealID),
+		zap.String("approval_name", approvalName),
+		zap.String("actor", adminActor),
+		zap.String("reason", reason),
+	)
+	logger.Info("overriding approval step")
+	defer func() {
+		if err != nil {
+			logger.Error("failed to override approval step", zap.Error(err))
+		} else {
+			logger.Info("approval step overridden", zap.String("user", result.User), zap.String("status", result.Status))
+		}
+	}()
+
+	approvalAction := domain.ApprovalAction{
+		AppealID:     appealID,
+		ApprovalName: approvalName,
+		Actor:        adminActor,
+		Action:       domain.AppealActionNameApprove,
+		Reason:       reason,
+	}
+	if err := utils.ValidateStruct(approvalAction); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrOverrideReasonRequired
+	}
+	if err := checkActor(ctx, adminActor); err != nil {
+		return nil, err
+	}
+	if !s.config.isAdmin(adminActor) {
+		return nil, ErrOverrideForbidden
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+
+	return s.applyApprovalAction(ctx, appeal, approvalAction, true)
+}
+
+// GetApprovalProvenance returns, for every approval step of the appeal, the nominal approvers along with
+// the effective approver and how they acted, e.g. as an approver or through an admin override
+func (s *Service) GetApprovalProvenance(ctx context.Context, appealID uint) ([]*domain.ApprovalProvenance, error) {
+	if appealID == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+
+	provenance := []*domain.ApprovalProvenance{}
+	for _, approval := range appeal.Approvals {
+		provenance = append(provenance, approval.GetProvenance())
+	}
+	return provenance, nil
+}
+
+// RevertApproval undoes an approval made by the actor while the appeal is still pending, moving the
+// approval step back to pending so it becomes the current step again. It's not allowed once the
+// appeal is finalized or any of the next steps has been actioned. The revert is recorded as a private
+// comment on the appeal
+func (s *Service) RevertApproval(ctx context.Context, appealID uint, approvalName, actor string) (result *domain.Appeal, err error) {
+	logger := s.getLogger(ctx).With(
+		zap.Uint("appeal_id", appealID),
+		zap.String("approval_name", approvalName),
+		zap.String("actor", actor),
+	)
+	logger.Info("reverting approval step")
+	defer func() {
+		if err != nil {
+			logger.Error("failed to revert approval step", zap.Error(err))
+		} else {
+			logger.Info("approval step reverted", zap.String("user", result.User), zap.String("status", result.Status))
+		}
+	}()
+
+	if appealID == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+	if approvalName == "" {
+		return nil, ErrApprovalNameNotFound
+	}
+	if err := s.validator.Var(actor, "required,email"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	}
+	if err := checkActor(ctx, actor); err != nil {
+		return nil, err
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+	if err := checkIfAppealStatusStillPending(appeal.Status); err != nil {
+		return nil, err
+	}
+
+	for i, approval := range appeal.Approvals {
+		if approval.Name != approvalName {
+			continue
+		}
+
+		if approval.Status != domain.ApprovalStatusApproved || approval.Actor == nil {
+			return nil, ErrRevertApprovalNotApproved
+		}
+		if *approval.Actor != actor {
+			return nil, ErrRevertForbidden
+		}
+		for _, next := range appeal.Approvals[i+1:] {
+			if next.Actor != nil {
+				return nil, ErrRevertNextStepActioned
+			}
+		}
+
+		approval.Status = domain.ApprovalStatusPending
+		approval.Actor = nil
+		approval.Reason = ""
+		approval.IsOverridden = false
+		approval.Attestation = nil
+		approval.SLAMet = nil
+		approval.ReminderLevel = 0
+		approval.UpdatedAt = TimeNow()
+		revertedAt := approval.UpdatedAt
+		approval.StatusChangedAt = &revertedAt
+
+		// the revert is only stored along with its audit comment
+		if err := s.repo.WithTransaction(ctx, func(tx domain.AppealRepository) error {
+			if err := tx.Update(ctx, appeal); err != nil {
+				return err
+			}
+			return tx.AddComment(ctx, &domain.AppealComment{
+				AppealID:   appeal.ID,
+				CreatedBy:  actor,
+				Body:       fmt.Sprintf("reverted the approval of step %q", approval.Name),
+				Visibility: domain.CommentVisibilityPrivate,
+			})
+		}); err != nil {
+			return nil, err
+		}
+		logger.Info("approval reverted", zap.String("approval_name", approval.Name))
+
+		return appeal, nil
+	}
+
+	return nil, ErrApprovalNameNotFound
+}
+
+func (s *Service) applyApprovalAction(ctx context.Context, appeal *domain.Appeal, approvalAction domain.ApprovalAction, isOverride bool) (*domain.Appeal, error) {
+	stepAction, err := s.checkApprovalAction(ctx, appeal, approvalAction, isOverride)
+	if err != nil {
+		return nil, err
+	}
+	if approvalAction.Action == domain.AppealActionNameRequestChanges {
+		return s.requestChanges(ctx, appeal, appeal.Approvals[stepAction.index], approvalAction)
+	}
+
+	if err := s.updateApprovalStep(ctx, stepAction); err != nil {
+		return nil, err
+	}
+
+	// the access is granted in the provider before the appeal is stored as it can't be part of the
+	// transaction, it's revoked back if the transaction fails
+	var message *domain.OutboxMessage
+	if err := s.repo.WithTransaction(ctx, func(tx domain.AppealRepository) error {
+		if err := s.enforceTeamQuota(ctx, tx, stepAction); err != nil {
+			return err
+		}
+		if err := tx.Update(ctx, appeal); err != nil {
+			return err
+		}
+		message, err = s.addApprovalActionMessage(ctx, tx, stepAction)
+		return err
+	}); err != nil {
+		if err := s.providerService.RevokeAccess(ctx, appeal); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	s.dispatchOutboxMessage(ctx, message)
+	return appeal, nil
+}
+
+// approvalStepAction is an action checked to be allowed on an approval step of the appeal
+type approvalStepAction struct {
+	appeal     *domain.Appeal
+	index      int
+	onBehalfOf string
+	action     domain.ApprovalAction
+	isOverride bool
+
+	// exceededQuota is the team quota rejecting the appeal once its approval is completed, if any
+	exceededQuota *domain.TeamQuotaConfig
+	// teamQuota is the team quota the activated appeal is checked against again while it's stored, if any
+	teamQuota *domain.TeamQuotaConfig
+}
+
+// checkApprovalAction checks whether the action is allowed on the approval step of the appeal without making it.
+// The approval conditions, if any, are applied to the appeal as they're checked
+func (s *Service) checkApprovalAction(ctx context.Context, appeal *domain.Appeal, approvalAction domain.ApprovalAction, isOverride bool) (*approvalStepAction, error) {
+	if err := checkIfAppealStatusStillPending(appeal.Status); err != nil {
+		return nil, err
+	}
+
+	for i, approval := range appeal.Approvals {
+		if approval.Name != approvalAction.ApprovalName {
+			if err := checkPreviousApprovalStatus(approval.Status); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if approval.Status != domain.ApprovalStatusPending {
+			if err := checkApprovalStatus(approval.Status); err != nil {
+				return nil, err
+			}
+		}
+
+		var onBehalfOf string
+		if !isOverride && !utils.ContainsString(approval.Approvers, approvalAction.Actor) {
+			approver, err := s.getDelegatingApprover(ctx, approval, approvalAction.Actor)
+			if err != nil {
+				return nil, err
+			}
+			if approver == "" {
+				return nil, ErrActionForbidden
+			}
+			onBehalfOf = approver
+		}
+		if approval.IsReasonRequired(approvalAction.Action) && strings.TrimSpace(approvalAction.Reason) == "" {
+			return nil, fmt.Errorf("%w: to %s step %q", ErrReasonRequired, approvalAction.Action, approval.Name)
+		}
+		switch approvalAction.Action {
+		case domain.AppealActionNameApprove:
+			if err := s.checkSeparationOfDuties(ctx, appeal, approval, approvalAction.Actor, onBehalfOf); err != nil {
+				return nil, err
+			}
+			if approvalAction.Conditions != nil {
+				if err := s.applyApprovalConditions(ctx, appeal, approvalAction.Conditions); err != nil {
+					return nil, err
+				}
+				s.getLogger(ctx).Info("approving with conditions",
+					zap.Uint("appeal_id", appeal.ID),
+					zap.String("requested_role", appeal.Requested.Role),
+					zap.String("granted_role", appeal.Role),
+				)
+			}
+		case domain.AppealActionNameReject, domain.AppealActionNameRequestChanges:
+			if approvalAction.Conditions != nil {
+				return nil, fmt.Errorf("%w: only applicable to the approve action", ErrInvalidApprovalConditions)
+			}
+		default:
+			return nil, ErrActionInvalidValue
+		}
+
+		return &approvalStepAction{
+			appeal:     appeal,
+			index:      i,
+			onBehalfOf: onBehalfOf,
+			action:     approvalAction,
+			isOverride: isOverride,
+		}, nil
+	}
+
+	return nil, ErrApprovalNameNotFound
+}
+
+// updateApprovalStep makes the checked action on the approval step and moves the appeal forward accordingly.
+// The access is granted if the appeal gets approved, the appeal isn't stored
+func (s *Service) updateApprovalStep(ctx context.Context, stepAction *approvalStepAction) error {
+	appeal, i, approvalAction := stepAction.appeal, stepAction.index, stepAction.action
+	approval := appeal.Approvals[i]
+
+	approval.Actor = &approvalAction.Actor
+	approval.Reason = approvalAction.Reason
+	approval.IsOverridden = stepAction.isOverride
+	approval.OnBehalfOf = stepAction.onBehalfOf
+	approval.ReminderLevel = 0
+	approval.UpdatedAt = TimeNow()
+	actionedAt := approval.UpdatedAt
+	approval.StatusChangedAt = &actionedAt
+
+	deadline, hasSLA, err := getSLADeadline(appeal, i)
+	if err != nil {
+		return err
+	}
+	if hasSLA {
+		slaMet := !approval.UpdatedAt.After(deadline)
+		approval.SLAMet = &slaMet
+	}
+
+	if s.config.ApprovalAttestation {
+		if s.Signer == nil {
+			return ErrAttestationSignerNotConfigured
+		}
+		attestation, err := NewApprovalAttestation(s.Signer, appeal, approval.Name, approvalAction.Actor, approvalAction.Action, approval.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		approval.Attestation = attestation
+	}
+
+	if approvalAction.Action == domain.AppealActionNameApprove {
+		approval.Status = domain.ApprovalStatusApproved
+		// the appeal goes back to the approvers of the stale steps before it completes
+		reopened, err := s.reopenStaleApprovals(ctx, appeal, i)
+		if err != nil {
+			return err
+		}
+		for _, r := range reopened {
+			s.getLogger(ctx).Info("stale approval reopened", zap.Uint("appeal_id", appeal.ID), zap.String("approval_name", r.Name))
+		}
+		if err := s.approvalService.AdvanceApproval(ctx, appeal); err != nil {
+			return err
+		}
+
+		if !isApprovalCompleted(appeal.Approvals) {
+			decision, err := s.evaluateDecision(ctx, DecisionPointAction, appeal, &approvalAction)
+			if err != nil {
+				return err
+			}
+			if err := applyDecision(appeal, decision, s.TimeNow()); err != nil {
+				return err
+			}
+		}
+
+		// the next steps may have been skipped, e.g. for not being required for the requested duration
+		if isApprovalCompleted(appeal.Approvals) {
+			quota, err := s.getTeamQuota(ctx, appeal)
+			if err != nil {
+				return err
+			}
+			exceeded := false
+			if quota != nil {
+				if exceeded, err = s.isTeamQuotaExceeded(ctx, s.repo, appeal, quota); err != nil {
+					return err
+				}
+			}
+			if exceeded {
+				appeal.Status = domain.AppealStatusRejected
+				stepAction.exceededQuota = quota
+			} else {
+				if err := s.activate(ctx, appeal); err != nil {
+					return err
+				}
+				stepAction.teamQuota = quota
+			}
+		} else if appeal.Status == domain.AppealStatusPending {
+			if err := s.assignApprover(ctx, appeal, map[string]*domain.ApproverLoad{}); err != nil {
+				return err
+			}
+		}
+	} else {
+		approval.Status = domain.ApprovalStatusRejected
+		appeal.Status = domain.AppealStatusRejected
+
+		for j := i + 1; j < len(appeal.Approvals); j++ {
+			appeal.Approvals[j].Status = domain.ApprovalStatusSkipped
+			appeal.Approvals[j].UpdatedAt = TimeNow()
+			appeal.Approvals[j].StatusChangedAt = &actionedAt
+		}
+	}
+
+	return nil
+}
+
+// addApprovalActionMessage stores the status event of the approval action and the notifications of the users
+// involved within the transaction storing the appeal
+func (s *Service) addApprovalActionMessage(ctx context.Context, tx domain.AppealRepository, stepAction *approvalStepAction) (*domain.OutboxMessage, error) {
+	appeal, approvalAction := stepAction.appeal, stepAction.action
+	exceededQuota := stepAction.exceededQuota
+
+	// the policy is only needed to advance the approval steps, the returned appeal has the
+	// same shape as the one returned by GetByID regardless of the action
+	appeal.Policy = nil
+	event := s.newStatusEvent(appeal, domain.AppealStatusPending, approvalAction.Action, approvalAction.ApprovalName, approvalAction.Actor)
+
+	notifications := []domain.Notification{}
+	if appeal.Status == domain.AppealStatusActive {
+		message := fmt.Sprintf("Your appeal to %s has been approved", appeal.Resource.URN)
+		if appeal.AccessScheduled {
+			message = fmt.Sprintf("%s, the access will be granted at %s", message, getScheduledGrantTime(appeal).Format(time.RFC3339))
+		}
+		notifications = append(notifications, domain.Notification{
+			User:    appeal.User,
+			Message: message,
+			Labels:  appeal.Labels,
+		})
+	} else if appeal.Status == domain.AppealStatusRejected && exceededQuota != nil {
+		notifications = append(notifications, domain.Notification{
+			User:    appeal.User,
+			Message: fmt.Sprintf("Your appeal to %s is rejected, your team already holds the maximum of %d active grant(s) of %s", appeal.Resource.URN, exceededQuota.Limit, appeal.Role),
+			Labels:  appeal.Labels,
+		})
+	} else if appeal.Status == domain.AppealStatusRejected {
+		notifications = append(notifications, domain.Notification{
+			User:    appeal.User,
+			Message: fmt.Sprintf("Your appeal to %s is rejected", appeal.Resource.URN),
+			Labels:  appeal.Labels,
+		})
+	} else {
+		notifications = append(notifications, s.getApprovalNotifications(appeal)...)
+	}
+	if appeal.Status != domain.AppealStatusPending {
+		notifications = append(notifications, s.getGroupResolvedNotifications(ctx, tx, appeal)...)
+	}
+	return s.addOutboxMessage(ctx, tx, event, notifications)
+}
+
+// requestChanges sends the appeal back to the requester for more information. The step stays pending for the
+// approvers to act on once the requester resubmits the appeal, the comment is stored as a public comment recording
+// the action and sent to the requester
+func (s *Service) requestChanges(ctx context.Context, appeal *domain.Appeal, approval *domain.Approval, approvalAction domain.ApprovalAction) (*domain.Appeal, error) {
+	comment := strings.TrimSpace(approvalAction.Comment)
+	now := s.TimeNow()
+	approval.ChangesRequestedAt = &now
+	appeal.Policy = nil
+	var message *domain.OutboxMessage
+	if err := s.repo.WithTransaction(ctx, func(tx domain.AppealRepository) error {
+		if err := tx.Update(ctx, appeal); err != nil {
+			return err
+		}
+		if err := tx.AddComment(ctx, &domain.AppealComment{
+			AppealID:   appeal.ID,
+			CreatedBy:  approvalAction.Actor,
+			Body:       fmt.Sprintf("requested changes on step %q: %s", approval.Name, comment),
+			Visibility: domain.CommentVisibilityPublic,
+		}); err != nil {
+			return err
+		}
+
+		var err error
+		message, err = s.addOutboxMessage(ctx, tx,
+			s.newStatusEvent(appeal, domain.AppealStatusPending, approvalAction.Action, approval.Name, approvalAction.Actor),
+			[]domain.Notification{{
+				User:    appeal.User,
+				Message: fmt.Sprintf("Changes are requested on your appeal to %s, resubmit it once they're made: %s", appeal.Resource.URN, comment),
+				Labels:  appeal.Labels,
+			}},
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	s.dispatchOutboxMessage(ctx, message)
+	return appeal, nil
+}
+
+// Resubmit follows up the changes requested on the appeal. Only the requester can resubmit the appeal, the comment
+// describing the changes made is stored as a public comment and sent to the approvers of the step the changes were
+// requested on, for them to act on the appeal again
+func (s *Service) Resubmit(ctx context.Context, id uint, user, comment string) (*domain.Appeal, error) {
+	if id == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return nil, ErrResubmitCommentRequired
+	}
+	if err := checkActor(ctx, user); err != nil {
+		return nil, err
+	}
+
+	appeal, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+	if appeal.User != user {
+		return nil, ErrResubmitForbidden
+	}
+	if err := checkIfAppealStatusStillPending(appeal.Status); err != nil {
+		return nil, err
+	}
+	approval := appeal.GetNextPendingApproval()
+	if approval == nil || approval.ChangesRequestedAt == nil {
+		return nil, ErrChangesNotRequested
+	}
+
+	approval.ChangesRequestedAt = nil
+	appeal.Policy = nil
+	var message *domain.OutboxMessage
+	if err := s.repo.WithTransaction(ctx, func(tx domain.AppealRepository) error {
+		if err := tx.Update(ctx, appeal); err != nil {
+			return err
+		}
+		if err := tx.AddComment(ctx, &domain.AppealComment{
+			AppealID:   appeal.ID,
+			CreatedBy:  user,
+			Body:       fmt.Sprintf("resubmitted on step %q: %s", approval.Name, comment),
+			Visibility: domain.CommentVisibilityPublic,
+		}); err != nil {
+			return err
+		}
+
+		notifications := []domain.Notification{}
+		for _, approver := range approval.GetNotifiedApprovers() {
+			notifications = append(notifications, domain.Notification{
+				User:    approver,
+				Message: fmt.Sprintf("The appeal from %s to access %s is resubmitted after the changes you requested: %s", appeal.User, appeal.Resource.URN, comment),
+				Channel: approval.NotificationChannel,
+				Labels:  appeal.Labels,
+			})
+		}
+		message, err = s.addOutboxMessage(ctx, tx,
+			s.newStatusEvent(appeal, domain.AppealStatusPending, domain.AppealActionNameResubmit, approval.Name, user),
+			notifications,
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	s.dispatchOutboxMessage(ctx, message)
+	return appeal, nil
+}
+
+// Clone creates a new appeal for the user requesting the same resource and role as an existing appeal.
+// The access duration of the source appeal is kept, counted from now. The new appeal goes through the
+// same validations and approval steps as the ones created with Create
+func (s *Service) Clone(ctx context.Context, id uint, user string) (*domain.Appeal, error) {
+	if id == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+	if err := s.validator.Var(user, "required,email"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	}
+	if err := checkActor(ctx, user); err != nil {
+		return nil, err
+	}
+
+	source, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, ErrAppealNotFound
+	}
+
+	var labels map[string]string
+	if source.Labels != nil {
+		labels = map[string]string{}
+		for k, v := range source.Labels {
+			labels[k] = v
+		}
+	}
+
+	var options *domain.AppealOptions
+	if source.Options != nil {
+		options = &domain.AppealOptions{}
+		if source.Options.ExpirationDate != nil {
+			expirationDate := s.TimeNow().Add(source.Options.ExpirationDate.Sub(source.CreatedAt))
+			options.ExpirationDate = &expirationDate
+		}
+		if source.Options.AccessWindow != nil {
+			accessWindow := *source.Options.AccessWindow
+			options.AccessWindow = &accessWindow
+		}
+	}
+
+	appeal := &domain.Appeal{
+		ResourceID: source.ResourceID,
+		User:       user,
+		Role:       source.Role,
+		Priority:   source.Priority,
+		Options:    options,
+		Labels:     labels,
+	}
+	if err := s.Create(ctx, []*domain.Appeal{appeal}); err != nil {
+		return nil, err
+	}
+
+	return appeal, nil
+}
+
+// SuggestApprovers resolves the approvers the user's appeal to the resource would be assigned to,
+// without creating the appeal. Dynamic approver keys such as $user_approvers are resolved live for the
+// user. Every step with approvers is included since the steps skipped by duration depend on the appeal
+func (s *Service) SuggestApprovers(ctx context.Context, resourceID uint, user string) ([]string, error) {
+	if resourceID == 0 {
+		return nil, ErrResourceNotFound
+	}
+	if err := s.validator.Var(user, "required,email"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	}
+
+	resources, err := s.getResourceMap(ctx, []uint{resourceID})
+	if err != nil {
+		return nil, err
+	}
+	resource := resources[resourceID]
+	if resource == nil {
+		return nil, ErrResourceNotFound
+	}
+
+	providerConfigs, err := s.getProviderConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if providerConfigs[resource.ProviderType] == nil {
+		return nil, ErrProviderTypeNotFound
+	} else if providerConfigs[resource.ProviderType][resource.ProviderURN] == nil {
+		return nil, ErrProviderURNNotFound
+	}
+	providerConfig := providerConfigs[resource.ProviderType][resource.ProviderURN]
+	if _, err := providerConfig.getResourceConfig(resource.Type); err != nil {
+		return nil, err
+	}
+
+	policies, err := s.getPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, policy, err := s.getResourcePolicy(s.getLogger(ctx), providerConfig, policies, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	approvers := []string{}
+	for _, step := range policy.Steps {
+		if step.Approvers == "" {
+			continue
+		}
+		stepApprovers, err := s.resolveApprovers(user, resource, step)
+		if err != nil {
+			return nil, err
+		}
+		for _, approver := range stepApprovers {
+			if !utils.ContainsString(approvers, approver) {
+				approvers = append(approvers, approver)
+			}
+		}
+	}
+
+	return approvers, nil
+}
+
+// Cancel cancels a pending appeal. The actor is the user canceling it, or the system actor if it's
+// canceled automatically
+func (s *Service) Cancel(ctx context.Context, id uint, actor string) (result *domain.Appeal, err error) {
+	logger := s.getLogger(ctx).With(zap.Uint("appeal_id", id), zap.String("actor", actor))
+	logger.Info("canceling appeal")
+	defer func() {
+		if err != nil {
+			logger.Error("failed to cancel appeal", zap.Error(err))
+		} else if result == nil {
+			logger.Info("appeal not found")
+		} else {
+			logger.Info("appeal canceled", zap.String("user", result.User), zap.String("status", result.Status))
+		}
+	}()
+
+	appeal, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, nil
+	}
+
+	// TODO: check only appeal creator who is allowed to cancel the appeal
+
+	if err := checkIfAppealStatusStillPending(appeal.Status); err != nil {
+		return nil, err
+	}
+
+	appeal.Status = domain.AppealStatusCanceled
+	appeal.CanceledBy = actor
+	if err := s.repo.Update(ctx, appeal); err != nil {
+		return nil, err
+	}
+	s.publishStatusEvent(appeal, domain.AppealStatusPending, domain.AppealActionNameCancel, "", actor)
+
+	if notifications := s.getGroupResolvedNotifications(ctx, s.repo, appeal); len(notifications) > 0 {
+		if err := s.notifier.Notify(notifications); err != nil {
+			logger.Error("failed to notify about the resolved appeal group", zap.Error(err))
+		}
+	}
+
+	return appeal, nil
+}
+
+func (s *Service) Revoke(ctx context.Context, id uint, actor, reason string) (result *domain.Appeal, err error) {
+	ctx, span := s.Tracer.Start(ctx, "appeal.Revoke", trace.WithAttributes(tracing.AppealID(id)))
+	defer func() {
+		if result != nil {
+			span.SetAttributes(tracing.AppealStatusKey.String(result.Status))
+		}
+		tracing.End(span, err)
+		if err == nil && result != nil {
+			s.Metrics.Count(metrics.AppealRevoked, 1, getResourceTags(result))
+		}
+	}()
+
+	logger := s.getLogger(ctx).With(zap.Uint("appeal_id", id), zap.String("actor", actor))
+	logger.Info("revoking appeal", zap.String("reason", reason))
+	defer func() {
+		if err != nil {
+			logger.Error("failed to revoke appeal", zap.Error(err))
+		} else {
+			logger.Info("appeal revoked", zap.String("user", result.User), zap.String("status", result.Status))
+		}
+	}()
+
+	appeal, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+	if appeal.Status == domain.AppealStatusTerminated {
+		// revoking is safe to retry, the access has already been revoked and the user notified
+		logger.Info("appeal is already revoked")
+		return appeal, nil
+	}
+	if appeal.Status != domain.AppealStatusActive && appeal.Status != domain.AppealStatusRevocationPending {
+		return nil, fmt.Errorf("%w: appeal is %s", ErrAppealNotActive, appeal.Status)
+	}
+	if appeal.Status == domain.AppealStatusRevocationPending && appeal.RevokedBy != "" {
+		// a retry keeps the actor and the reason of the revocation that failed
+		actor, reason = appeal.RevokedBy, appeal.RevokeReason
+	}
+
+	// revoke the access in the provider first so the appeal is only marked as terminated
+	// once the access is actually removed
+	hasAccess := !appeal.AccessWindowClosed && !appeal.AccessScheduled
+	if hasAccess {
+		if err := s.revokeAccess(ctx, appeal); err != nil {
+			// the access is still granted, the revocation is retried until it succeeds
+			pendingAppeal := &domain.Appeal{}
+			*pendingAppeal = *appeal
+			if err := s.markRevocationPending(ctx, pendingAppeal, actor, reason, err); err != nil {
+				logger.Error("failed to mark the revocation as pending", zap.Error(err))
+			}
+			return nil, err
+		}
+	}
+
+	revokedAppeal := &domain.Appeal{}
+	*revokedAppeal = *appeal
+	revokedAppeal.Status = domain.AppealStatusTerminated
+	revokedAppeal.RevokedAt = s.TimeNow()
+	revokedAppeal.RevokedBy = actor
+	revokedAppeal.RevokeReason = reason
+	revokedAppeal.RevocationError = ""
+
+	if err := s.repo.Update(ctx, revokedAppeal); err != nil {
+		// restore the access to keep the provider consistent with the still active appeal
+		if hasAccess {
+			if err := s.providerService.GrantAccess(ctx, appeal); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+	s.publishStatusEvent(revokedAppeal, appeal.Status, domain.AppealActionNameRevoke, "", actor)
+
+	if err := s.notifier.Notify([]domain.Notification{{
+		User:     appeal.User,
+		Message:  fmt.Sprintf("Your access to %s has been revoked", appeal.Resource.URN),
+		Critical: true,
+		Labels:   appeal.Labels,
+	}}); err != nil {
+		logger.Error("failed to notify the revoked access", zap.Error(err))
+	}
+
+	return revokedAppeal, nil
+}
+
+// GetPendingForApprover returns pending appeals waiting for the approver's action, ordered by
+// the priority so the most critical ones come first, then by the oldest
+func (s *Service) GetPendingForApprover(ctx context.Context, approver string) ([]*domain.Appeal, error) {
+	if err := s.validator.Var(approver, "required,email"); err != nil {
+		return nil, err
+	}
+
+	appeals, err := s.getPendingAppealDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.TimeNow()
+	pendingAppeals := []*domain.Appeal{}
+	for _, a := range appeals {
+		approval := a.GetNextPendingApproval()
+		if approval != nil && utils.ContainsString(approval.Approvers, approver) {
+			isBreached, err := isSLABreached(a, now)
+			if err != nil {
+				return nil, err
+			}
+			a.IsSLABreached = isBreached
+			pendingAppeals = append(pendingAppeals, a)
+		}
+	}
+
+	sortByPriority(pendingAppeals)
+
+	return pendingAppeals, nil
+}
+
+// sortByPriority orders the appeals by the priority so the most critical ones come first, then by the oldest
+func sortByPriority(appeals []*domain.Appeal) {
+	sort.SliceStable(appeals, func(i, j int) bool {
+		pi, pj := appeals[i].GetPriorityLevel(), appeals[j].GetPriorityLevel()
+		if pi != pj {
+			return pi > pj
+		}
+		return appeals[i].CreatedAt.Before(appeals[j].CreatedAt)
+	})
+}
+
+// GetSLAReport summarizes the SLA compliance of the actioned approval steps of the appeals matching the
+// filters, grouped by the approvers who actioned them and by the team label of the appeals
+func (s *Service) GetSLAReport(ctx context.Context, filters map[string]interface{}) (*domain.SLAReport, error) {
+	conditions := map[string]interface{}{}
+	for k, v := range filters {
+		conditions[k] = v
+	}
+	conditions["with_approvals"] = true
+	appeals, err := s.repo.Find(ctx, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	teamLabel := s.config.getSLAReportTeamLabel()
+	report := domain.NewSLAReport()
+	for _, appeal := range appeals {
+		for _, approval := range appeal.Approvals {
+			if approval.SLAMet == nil || approval.Actor == nil {
+				continue
+			}
+			report.Add(*approval.Actor, appeal.Labels[teamLabel], *approval.SLAMet)
+		}
+	}
+
+	return report, nil
+}
+
+// FindDeadlockedAppeals returns pending appeals which current approval step has no approver.
+// Those appeals are unable to progress and need an admin intervention
+func (s *Service) FindDeadlockedAppeals(ctx context.Context) ([]*domain.Appeal, error) {
+	appeals, err := s.getPendingAppealDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadlockedAppeals := []*domain.Appeal{}
+	for _, a := range appeals {
+		if a.GetDeadlockedApproval() != nil {
+			deadlockedAppeals = append(deadlockedAppeals, a)
+		}
+	}
+
+	return deadlockedAppeals, nil
+}
+
+// ReassignDeadlockedAppeal assigns approvers to the deadlocked approval step of an appeal and notifies them
+func (s *Service) ReassignDeadlockedAppeal(ctx context.Context, id uint, approvers []string) (*domain.Appeal, error) {
+	if err := s.validator.Var(approvers, "required,dive,email"); err != nil {
+		return nil, err
+	}
+
+	appeal, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+
+	approval := appeal.GetDeadlockedApproval()
+	if approval == nil {
+		return nil, ErrAppealNotDeadlocked
+	}
+
+	for _, email := range approvers {
+		if err := s.approvalService.AddApprover(ctx, &domain.Approver{
+			ApprovalID: approval.ID,
+			AppealID:   appeal.ID,
+			Email:      email,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	approval.Approvers = approvers
+
+	notifications := s.getApprovalNotifications(appeal)
+	if len(notifications) > 0 {
+		if err := s.notifier.Notify(notifications); err != nil {
+			s.logger.Error(err.Error())
+		}
+	}
+
+	return appeal, nil
+}
+
+// AddApprovalStep inserts an ad-hoc approval step into a pending appeal, e.g. to also get legal to sign off.
+// The step is inserted at the position among the approval steps, which can't be before an approved step.
+// Only admins and the approvers of the appeal are allowed to add a step, the actor is recorded as the
+// step's AddedBy. The approvers of the new step are notified if it becomes the current step
+func (s *Service) AddApprovalStep(ctx context.Context, appealID uint, step domain.Approval, position int, actor string) (result *domain.Appeal, err error) {
+	logger := s.getLogger(ctx).With(
+		zap.Uint("appeal_id", appealID),
+		zap.String("approval_name", step.Name),
+		zap.Int("position", position),
+		zap.String("actor", actor),
+	)
+	logger.Info("adding approval step")
+	defer func() {
+		if err != nil {
+			logger.Error("failed to add approval step", zap.Error(err))
+		} else {
+			logger.Info("approval step added", zap.Strings("approvers", step.Approvers))
+		}
+	}()
+
+	if appealID == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+	if err := s.validator.Var(actor, "required,email"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	}
+	if strings.TrimSpace(step.Name) == "" {
+		return nil, ErrApprovalStepNameRequired
+	}
+	if err := s.validator.Var(step.Approvers, "required,dive,email"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrApprovalStepApproversInvalid, err)
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+	if err := checkIfAppealStatusStillPending(appeal.Status); err != nil {
+		return nil, err
+	}
+	if !s.config.isAdmin(actor) && !appeal.IsApprover(actor) {
+		return nil, ErrAddApprovalStepForbidden
+	}
+
+	if position < 0 || position > len(appeal.Approvals) {
+		return nil, ErrInvalidApprovalStepPosition
+	}
+	for i, approval := range appeal.Approvals {
+		if approval.Name == step.Name {
+			return nil, ErrApprovalStepDuplicate
+		}
+		if i >= position && approval.Status == domain.ApprovalStatusApproved {
+			return nil, ErrInvalidApprovalStepPosition
+		}
+	}
+
+	newApproval := &domain.Approval{
+		Name:                step.Name,
+		Index:               position,
+		AppealID:            appeal.ID,
+		Status:              domain.ApprovalStatusPending,
+		PolicyID:            appeal.PolicyID,
+		PolicyVersion:       appeal.PolicyVersion,
+		AddedBy:             actor,
+		NotificationChannel: step.NotificationChannel,
+		Approvers:           step.Approvers,
+	}
+	// the step is inserted and the next steps shifted together, so a failure doesn't leave an orphaned step
+	var message *domain.OutboxMessage
+	if err := s.repo.WithTransaction(ctx, func(repo domain.AppealRepository) error {
+		if err := repo.AddApproval(ctx, newApproval); err != nil {
+			return err
+		}
+
+		approvals := append([]*domain.Approval{}, appeal.Approvals[:position]...)
+		approvals = append(approvals, newApproval)
+		for _, approval := range appeal.Approvals[position:] {
+			approval.Index++
+			approvals = append(approvals, approval)
+		}
+		appeal.Approvals = approvals
+		if err := repo.Update(ctx, appeal); err != nil {
+			return err
+		}
+
+		if current := appeal.GetNextPendingApproval(); current != nil && current.Name == newApproval.Name {
+			var err error
+			message, err = s.addOutboxMessage(ctx, repo, nil, s.getApprovalNotifications(appeal))
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	s.dispatchOutboxMessage(ctx, message)
+	return appeal, nil
+}
+
+// RemindPendingApprovers re-notifies the approvers of the current approval step that has been
+// idle for longer than idleFor. The same approval step won't be reminded again until another idleFor has passed.
+// Each reminder climbs a rung of the reminder escalation ladder. The configured idle duration is used if idleFor is 0.
+// The appeals which reminders are muted are skipped
+func (s *Service) RemindPendingApprovers(ctx context.Context, idleFor time.Duration) error {
+	if idleFor <= 0 {
+		idleFor = s.config.Reminder.getIdleFor()
+	}
+
+	appeals, err := s.getPendingAppealDetails(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.TimeNow()
+	policies := map[string]*domain.Policy{}
+	for _, a := range appeals {
+		if a.RemindersMutedBy != "" {
+			continue
+		}
+		approval := a.GetNextPendingApproval()
+		if approval == nil {
+			continue
+		}
+
+		if now.Sub(getIdleSince(a, approval)) < idleFor {
+			continue
+		}
+		if approval.LastRemindedAt != nil && now.Sub(*approval.LastRemindedAt) < idleFor {
+			continue
+		}
+
+		rung, err := s.getReminderRung(ctx, approval, policies)
+		if err != nil {
+			return err
+		}
+
+		notifications := []domain.Notification{}
+		for _, approver := range approval.GetNotifiedApprovers() {
+			notifications = append(notifications, domain.Notification{
+				User:    approver,
+				Message: fmt.Sprintf("Reminder: you have a pending appeal from %s to access %s", a.User, a.Resource.URN),
+				Channel: rung.Channel,
+				Labels:  a.Labels,
+			})
+		}
+		for _, recipient := range rung.Recipients {
+			notifications = append(notifications, domain.Notification{
+				User:    recipient,
+				Message: fmt.Sprintf("Escalation: the approval step %q of the appeal from %s to access %s is still pending after %d reminders", approval.Name, a.User, a.Resource.URN, approval.ReminderLevel),
+				Channel: rung.Channel,
+				Labels:  a.Labels,
+			})
+		}
+		if err := s.notifier.Notify(notifications); err != nil {
+			s.logger.Error(err.Error())
+			continue
+		}
+
+		approval.LastRemindedAt = &now
+		approval.ReminderLevel++
+		if err := s.repo.Update(ctx, a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getIdleSince returns the time the approval step started awaiting approval, i.e. the last status change of the
+// step or the steps before it, or the appeal creation if none
+func getIdleSince(a *domain.Appeal, approval *domain.Approval) time.Time {
+	idleSince := a.CreatedAt
+	for _, step := range a.Approvals {
+		if step.StatusChangedAt != nil && step.StatusChangedAt.After(idleSince) {
+			idleSince = *step.StatusChangedAt
+		}
+		if step == approval {
+			break
+		}
+	}
+	return idleSince
+}
+
+// ToggleAccessWindows revokes the active access outside of their access window and grants it
+// back once the window reopens. Only the appeals whose window opens or closes by now are loaded
+func (s *Service) ToggleAccessWindows(ctx context.Context) error {
+	logger := s.getLogger(ctx)
+
+	now := s.TimeNow()
+	appeals, err := s.repo.Find(ctx, map[string]interface{}{
+		"statuses":                 []string{domain.AppealStatusActive},
+		"access_window_toggle_due": now,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, a := range appeals {
+		if a.Options == nil || a.Options.AccessWindow == nil || a.AccessScheduled {
+			continue
+		}
+
+		w, err := parseAccessWindow(a.Options.AccessWindow)
+		if err != nil {
+			logger.Error("invalid access window", zap.Uint("appeal_id", a.ID), zap.Error(err))
+			continue
+		}
+
+		// toggling requires the resource details
+		appeal, err := s.repo.GetByID(ctx, a.ID)
+		if err != nil {
+			return err
+		}
+
+		isWindowOpen := w.isOpen(now)
+		toggled := isWindowOpen == appeal.AccessWindowClosed
+		if toggled && isWindowOpen {
+			if err := s.grantAccess(ctx, appeal); err != nil {
+				logger.Error("failed to grant access on access window opened", zap.Uint("appeal_id", a.ID), zap.Error(err))
+				continue
+			}
+		} else if toggled {
+			if err := s.revokeAccess(ctx, appeal); err != nil {
+				logger.Error("failed to revoke access on access window closed", zap.Uint("appeal_id", a.ID), zap.Error(err))
+				continue
+			}
+		}
+
+		appeal.AccessWindowClosed = !isWindowOpen
+		nextToggle := w.nextToggle(now)
+		appeal.AccessWindowToggleAt = &nextToggle
+		if err := s.repo.Update(ctx, appeal); err != nil {
+			return err
+		}
+		if toggled {
+			logger.Info("access window toggled", zap.Uint("appeal_id", a.ID), zap.Bool("access_window_closed", appeal.AccessWindowClosed))
+		}
+	}
+
+	return nil
+}
+
+// ActivateScheduledAccess grants the approved access once their start date arrives. The appeals still
+// pending on their start date are granted as soon as they get approved
+func (s *Service) ActivateScheduledAccess(ctx context.Context) error {
+	logger := s.getLogger(ctx)
+
+	// granting requires the resource details, loaded along with the appeals
+	appeals, err := s.repo.Find(ctx, map[string]interface{}{
+		"statuses":         []string{domain.AppealStatusActive},
+		"access_scheduled": true,
+		"with_approvals":   true,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := s.TimeNow()
+	for _, appeal := range appeals {
+		if !appeal.AccessScheduled || !isAccessStarted(appeal, now) || isUndoable(appeal, now) {
+			continue
+		}
+		if hasExpirationDate(appeal) && !appeal.Options.ExpirationDate.After(now) {
+			// expired before being granted, left to be revoked by the expiration job
+			continue
+		}
+
+		isWindowOpen, err := isAccessWindowOpen(appeal, now)
+		if err != nil {
+			logger.Error("invalid access window", zap.Uint("appeal_id", appeal.ID), zap.Error(err))
+			continue
+		}
+		if isWindowOpen {
+			if err := s.grantAccess(ctx, appeal); err != nil {
+				logger.Error("failed to grant scheduled access", zap.Uint("appeal_id", appeal.ID), zap.Error(err))
+				continue
 			}
+		} else {
+			// the access is granted later by ToggleAccessWindows once the window opens
+			appeal.AccessWindowClosed = true
+		}
 
-			return appeal, nil
+		appeal.AccessScheduled = false
+		if err := s.repo.Update(ctx, appeal); err != nil {
+			return err
+		}
+		logger.Info("scheduled access activated", zap.Uint("appeal_id", appeal.ID), zap.Bool("access_window_closed", appeal.AccessWindowClosed))
+
+		if err := s.notifier.Notify([]domain.Notification{{
+			User:    appeal.User,
+			Message: fmt.Sprintf("Your scheduled access to %s is now active", appeal.Resource.URN),
+			Labels:  appeal.Labels,
+		}}); err != nil {
+			logger.Error(err.Error())
 		}
 	}
 
-	return nil, ErrApprovalNameNotFound
+	return nil
 }
 
-func (s *Service) Cancel(id uint) (*domain.Appeal, error) {
-	appeal, err := s.GetByID(id)
+// activate marks the approved appeal as active and grants the access, unless it's scheduled to
+// start later or it's outside of its access window
+func (s *Service) activate(ctx context.Context, appeal *domain.Appeal) error {
+	now := s.TimeNow()
+	isWindowOpen, err := isAccessWindowOpen(appeal, now)
 	if err != nil {
-		return nil, err
-	}
-	if appeal == nil {
-		return nil, nil
+		return err
 	}
 
-	// TODO: check only appeal creator who is allowed to cancel the appeal
-
-	if err := checkIfAppealStatusStillPending(appeal.Status); err != nil {
-		return nil, err
+	if s.config.UndoWindow > 0 {
+		undoDeadline := now.Add(s.config.UndoWindow)
+		appeal.UndoDeadline = &undoDeadline
 	}
 
-	appeal.Status = domain.AppealStatusCanceled
-	if err := s.repo.Update(appeal); err != nil {
-		return nil, err
+	// an appeal approved after its start date is granted right away
+	if !isAccessStarted(appeal, now) || isUndoable(appeal, now) {
+		// the access is granted later by ActivateScheduledAccess once the start date arrives and the undo
+		// window ends
+		appeal.AccessScheduled = true
+	} else if isWindowOpen {
+		if err := s.grantAccess(ctx, appeal); err != nil {
+			return err
+		}
+	} else {
+		// the access is granted later by ToggleAccessWindows once the window opens
+		appeal.AccessWindowClosed = true
 	}
 
-	return appeal, nil
+	appeal.Status = domain.AppealStatusActive
+	return nil
 }
 
-func (s *Service) Revoke(id uint, actor, reason string) (*domain.Appeal, error) {
-	appeal, err := s.repo.GetByID(id)
-	if err != nil {
-		return nil, err
-	}
-	if appeal == nil {
-		return nil, ErrAppealNotFound
-	}
+// grantAccess grants the access in the provider along with the access hooks
+func (s *Service) grantAccess(ctx context.Context, appeal *domain.Appeal) error {
+	return s.changeAccess(ctx, AccessOperationGrant, appeal, func() error {
+		return s.grantAndVerifyAccess(ctx, appeal)
+	})
+}
 
-	revokedAppeal := &domain.Appeal{}
-	*revokedAppeal = *appeal
-	revokedAppeal.Status = domain.AppealStatusTerminated
-	revokedAppeal.RevokedAt = s.TimeNow()
-	revokedAppeal.RevokedBy = actor
-	revokedAppeal.RevokeReason = reason
+// revokeAccess revokes the access in the provider along with the access hooks
+func (s *Service) revokeAccess(ctx context.Context, appeal *domain.Appeal) error {
+	return s.changeAccess(ctx, AccessOperationRevoke, appeal, func() error {
+		return s.providerService.RevokeAccess(ctx, appeal)
+	})
+}
 
-	if err := s.repo.Update(revokedAppeal); err != nil {
-		return nil, err
+// grantAndVerifyAccess grants the access in the provider and verifies it if configured
+func (s *Service) grantAndVerifyAccess(ctx context.Context, appeal *domain.Appeal) error {
+	if err := s.providerService.GrantAccess(ctx, appeal); err != nil {
+		return err
 	}
-
-	if err := s.providerService.RevokeAccess(appeal); err != nil {
-		if err := s.repo.Update(appeal); err != nil {
-			return nil, err
+	if s.config.VerifyGrantedAccess {
+		// the providers not supporting the verification are trusted
+		granted, err := s.providerService.VerifyAccess(ctx, appeal)
+		if err == nil && !granted {
+			err = ErrAccessNotGranted
+		}
+		if err != nil {
+			// revoke the just granted access to keep the provider consistent with the appeal
+			// that isn't activated
+			if revokeErr := s.providerService.RevokeAccess(ctx, appeal); revokeErr != nil {
+				return fmt.Errorf("%w: unable to revoke the unverified access: %v", err, revokeErr)
+			}
+			return err
 		}
-		return nil, err
 	}
+	return nil
+}
 
-	if err := s.notifier.Notify([]domain.Notification{{
-		User:    appeal.User,
-		Message: fmt.Sprintf("Your access to %s has been revoked", appeal.Resource.URN),
-	}}); err != nil {
-		s.logger.Error(err.Error())
-	}
+// getLogger returns the request-scoped logger if any, otherwise the service logger
+func (s *Service) getLogger(ctx context.Context) *zap.Logger {
+	return logger.FromContext(ctx, s.logger)
+}
 
-	return revokedAppeal, nil
+// getPendingAppealDetails returns pending appeals along with the approvals and the approvers
+func (s *Service) getPendingAppealDetails(ctx context.Context) ([]*domain.Appeal, error) {
+	return s.repo.Find(ctx, map[string]interface{}{
+		"statuses":       []string{domain.AppealStatusPending},
+		"with_approvals": true,
+	})
 }
 
-func (s *Service) getPendingAppeals() (map[string]map[uint]map[string]*domain.Appeal, error) {
-	appeals, err := s.repo.Find(map[string]interface{}{
+func (s *Service) getPendingAppeals(ctx context.Context) (map[string]map[uint]map[string]*domain.Appeal, error) {
+	appeals, err := s.repo.Find(ctx, map[string]interface{}{
 		"statuses": []string{domain.AppealStatusPending},
 	})
 	if err != nil {
@@ -379,9 +2166,21 @@ func (s *Service) getPendingAppeals() (map[string]map[uint]map[string]*domain.Ap
 	return appealsMap, nil
 }
 
-func (s *Service) getResourceMap(ids []uint) (map[uint]*domain.Resource, error) {
+// findResources finds the resources matching the filters, restricted to the organization carried by ctx if any
+func (s *Service) findResources(ctx context.Context, filters map[string]interface{}) ([]*domain.Resource, error) {
+	if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+		scopedFilters := map[string]interface{}{"organization_id": organizationID}
+		for k, v := range filters {
+			scopedFilters[k] = v
+		}
+		filters = scopedFilters
+	}
+	return s.resourceService.Find(ctx, filters)
+}
+
+func (s *Service) getResourceMap(ctx context.Context, ids []uint) (map[uint]*domain.Resource, error) {
 	filters := map[string]interface{}{"ids": ids}
-	resources, err := s.resourceService.Find(filters)
+	resources, err := s.findResources(ctx, filters)
 	if err != nil {
 		return nil, err
 	}
@@ -394,8 +2193,8 @@ func (s *Service) getResourceMap(ids []uint) (map[uint]*domain.Resource, error)
 	return result, nil
 }
 
-func (s *Service) getProviderConfigs() (map[string]map[string]*providerConfig, error) {
-	providers, err := s.providerService.Find()
+func (s *Service) getProviderConfigs(ctx context.Context) (map[string]map[string]*providerConfig, error) {
+	providers, err := s.providerService.Find(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -417,12 +2216,62 @@ func (s *Service) getProviderConfigs() (map[string]map[string]*providerConfig, e
 			resourceType := r.Type
 
 			availableRoleIDs := []string{}
+			roleMaxDurations := map[string]string{}
+			roleAllowedGroups := map[string][]string{}
+			roleSensitivities := map[string]string{}
+			roleResourceRequirements := map[string][]*domain.Condition{}
 			for _, role := range r.Roles {
 				availableRoleIDs = append(availableRoleIDs, role.ID)
+				if len(role.ResourceRequirements) > 0 {
+					roleResourceRequirements[role.ID] = role.ResourceRequirements
+				}
+				if role.Sensitivity != "" {
+					roleSensitivities[role.ID] = role.Sensitivity
+				}
+				if len(role.AllowedGroups) > 0 {
+					roleAllowedGroups[role.ID] = role.AllowedGroups
+				}
+				if role.MaxDuration != "" {
+					roleMaxDurations[role.ID] = role.MaxDuration
+				} else if p.Config.Appeal != nil && p.Config.Appeal.MaxDuration != "" {
+					roleMaxDurations[role.ID] = p.Config.Appeal.MaxDuration
+				}
+			}
+			resourcePolicies := map[string]*domain.PolicyConfig{}
+			for _, rp := range r.ResourcePolicies {
+				resourcePolicies[rp.URN] = rp.Policy
+			}
+			rc := &resourceConfig{
+				policy:            r.Policy,
+				resourcePolicies:  resourcePolicies,
+				tagPolicies:       r.TagPolicies,
+				availableRoleIDs:  availableRoleIDs,
+				roleMaxDurations:  roleMaxDurations,
+				roleAllowedGroups: roleAllowedGroups,
+				roleSensitivities: roleSensitivities,
+
+				roleResourceRequirements: roleResourceRequirements,
+			}
+			pc := providerConfigs[providerType][providerURN]
+			pc.resources[resourceType] = rc
+
+			resourceTypeName := resourceType
+			if len(r.Aliases) > 0 {
+				resourceTypeName = fmt.Sprintf("%s (aliases: %s)", resourceType, strings.Join(r.Aliases, ", "))
 			}
-			providerConfigs[providerType][providerURN].resources[resourceType] = &resourceConfig{
-				policy:           r.Policy,
-				availableRoleIDs: availableRoleIDs,
+			pc.resourceTypes = append(pc.resourceTypes, resourceTypeName)
+		}
+	}
+
+	// the aliases are registered after all the types so a type always takes precedence over an alias
+	// regardless of the config order. The provider service rejects the configs with duplicate aliases
+	for _, p := range providers {
+		pc := providerConfigs[p.Type][p.URN]
+		for _, r := range p.Config.Resources {
+			for _, alias := range r.Aliases {
+				if pc.resources[alias] == nil {
+					pc.resources[alias] = pc.resources[r.Type]
+				}
 			}
 		}
 	}
@@ -430,8 +2279,8 @@ func (s *Service) getProviderConfigs() (map[string]map[string]*providerConfig, e
 	return providerConfigs, nil
 }
 
-func (s *Service) getPolicies() (map[string]map[uint]*domain.Policy, error) {
-	policies, err := s.policyService.Find()
+func (s *Service) getPolicies(ctx context.Context) (map[string]map[uint]*domain.Policy, error) {
+	policies, err := s.policyService.Find(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -448,8 +2297,72 @@ func (s *Service) getPolicies() (map[string]map[uint]*domain.Policy, error) {
 	return policiesMap, nil
 }
 
-func (s *Service) resolveApprovers(user string, resource *domain.Resource, approversKey string) ([]string, error) {
+// evaluateWarnings runs the warning rules on the appeal against the user's previous appeals
+func (s *Service) evaluateWarnings(ctx context.Context, a *domain.Appeal) ([]*domain.AppealWarning, error) {
+	if len(s.WarningRules) == 0 {
+		return nil, nil
+	}
+
+	history, err := s.repo.GetHistory(ctx, a.User, a.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []*domain.AppealWarning
+	for _, rule := range s.WarningRules {
+		warning, err := rule.Evaluate(ctx, a, history)
+		if err != nil {
+			return nil, err
+		}
+		if warning != nil {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings, nil
+}
+
+// getResourcePolicy returns the policy configured for the resource, falling back to the provider's
+// and then the service's default policy
+func (s *Service) getResourcePolicy(logger *zap.Logger, providerConfig *providerConfig, policies map[string]map[uint]*domain.Policy, resource *domain.Resource) (*domain.PolicyConfig, *domain.Policy, error) {
+	resourceConfig, err := providerConfig.getResourceConfig(resource.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	policyConfig := resourceConfig.getPolicy(resource)
+	if policyConfig == nil || policyConfig.ID == "" {
+		policyConfig = providerConfig.appeal.DefaultPolicy
+		if policyConfig == nil {
+			policyConfig = s.config.getDefaultPolicy()
+		}
+		if policyConfig == nil {
+			return nil, nil, ErrPolicyIDNotFound
+		}
+		logger.Warn("resource type has no policy configured, falling back to the default policy",
+			zap.String("provider_type", resource.ProviderType),
+			zap.String("provider_urn", resource.ProviderURN),
+			zap.String("resource_type", resource.Type),
+			zap.String("policy_id", policyConfig.ID),
+			zap.Int("policy_version", policyConfig.Version),
+		)
+	}
+	if policies[policyConfig.ID] == nil {
+		return nil, nil, ErrPolicyIDNotFound
+	} else if policies[policyConfig.ID][uint(policyConfig.Version)] == nil {
+		return nil, nil, ErrPolicyVersionNotFound
+	}
+	policy := policies[policyConfig.ID][uint(policyConfig.Version)]
+	// a resource can only use the policies of its own organization, the policies without an organization are only
+	// usable by the resources without one
+	if policy.OrganizationID != resource.OrganizationID {
+		return nil, nil, ErrPolicyIDNotFound
+	}
+
+	return policyConfig, policy, nil
+}
+
+func (s *Service) resolveApprovers(user string, resource *domain.Resource, step *domain.Step) ([]string, error) {
 	var approvers []string
+	approversKey := step.Approvers
 
 	if strings.HasPrefix(approversKey, domain.ApproversKeyResource) {
 		mapResource, err := structToMap(resource)
@@ -483,33 +2396,154 @@ func (s *Service) resolveApprovers(user string, resource *domain.Resource, appro
 	} else if strings.HasPrefix(approversKey, domain.ApproversKeyUserApprovers) {
 		approverEmails, err := s.iamService.GetUserApproverEmails(user)
 		if err != nil {
-			return nil, err
+			return nil, &approverResolutionError{err}
 		}
 		approvers = approverEmails
 	} else {
 		return nil, ErrApproverKeyNotRecognized
 	}
 
+	if step.CheckApproverAvailability {
+		approvers = s.getAvailableApprovers(step, approvers)
+	}
+
 	if err := s.validator.Var(approvers, "dive,email"); err != nil {
 		return nil, err
 	}
+	for _, approver := range approvers {
+		if !s.config.isApproverDomainAllowed(approver) {
+			return nil, fmt.Errorf("%w: %q", ErrApproverDomainNotAllowed, approver)
+		}
+	}
 	return approvers, nil
 }
 
-func getApprovalNotifications(appeal *domain.Appeal) []domain.Notification {
+// getAvailableApprovers skips the approvers who are currently unavailable, falling back to the step's
+// fallback approvers if none is available, or to all the approvers if the step has no fallback. An approver
+// is considered available if the availability can't be checked, so an IAM failure doesn't block the step
+func (s *Service) getAvailableApprovers(step *domain.Step, approvers []string) []string {
+	available := []string{}
+	for _, approver := range approvers {
+		isAvailable, err := s.iamService.IsUserAvailable(approver)
+		if err != nil {
+			s.logger.Warn("unable to check approver availability",
+				zap.String("approval_name", step.Name),
+				zap.String("approver", approver),
+				zap.Error(err),
+			)
+			isAvailable = true
+		}
+		if isAvailable {
+			available = append(available, approver)
+		}
+	}
+
+	if len(available) == 0 {
+		if len(step.FallbackApprovers) == 0 {
+			return approvers
+		}
+		s.logger.Info("none of the approvers is available, assigning the fallback approvers",
+			zap.String("approval_name", step.Name),
+			zap.Strings("approvers", approvers),
+			zap.Strings("fallback_approvers", step.FallbackApprovers),
+		)
+		return step.FallbackApprovers
+	}
+	return available
+}
+
+// validateRoleMaxDuration checks the requested access duration against the max duration of the role. Permanent
+// access always exceeds the max duration, regardless of the provider allowing permanent access
+func validateRoleMaxDuration(a *domain.Appeal, maxDuration string, now time.Time) error {
+	if maxDuration == "" {
+		return nil
+	}
+	max, err := time.ParseDuration(maxDuration)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidMaxDuration, maxDuration)
+	}
+
+	if a.Options == nil || a.Options.ExpirationDate == nil || a.Options.ExpirationDate.IsZero() {
+		return fmt.Errorf("%w: permanent access is requested, max duration of role %q is %s", ErrDurationExceedsRoleMax, a.Role, maxDuration)
+	}
+	if a.Options.ExpirationDate.Sub(getAccessStartTime(a, now)) > max {
+		return fmt.Errorf("%w: max duration of role %q is %s", ErrDurationExceedsRoleMax, a.Role, maxDuration)
+	}
+	return nil
+}
+
+// isStepRequired checks the requested access duration against the step's min duration. The appeals
+// without expiration date request permanent access, which requires every step
+func isStepRequired(step *domain.Step, a *domain.Appeal, now time.Time) (bool, error) {
+	if step.MinDuration == "" {
+		return true, nil
+	}
+	minDuration, err := time.ParseDuration(step.MinDuration)
+	if err != nil {
+		return false, fmt.Errorf("%w: %q", ErrInvalidStepMinDuration, step.MinDuration)
+	}
+
+	if a.Options == nil || a.Options.ExpirationDate == nil || a.Options.ExpirationDate.IsZero() {
+		return true, nil
+	}
+	return a.Options.ExpirationDate.Sub(getAccessStartTime(a, now)) >= minDuration, nil
+}
+
+// getApprovalNotifications notifies the approvers of the next pending approval step about the appeal. The
+// approvers opting into the digest are left out unless the appeal is high priority
+func (s *Service) getApprovalNotifications(appeal *domain.Appeal) []domain.Notification {
 	notifications := []domain.Notification{}
 	approval := appeal.GetNextPendingApproval()
 	if approval != nil {
-		for _, approver := range approval.Approvers {
+		message := fmt.Sprintf("You have an appeal from %s to access %s", appeal.User, appeal.Resource.URN)
+		if appeal.IsHighPriority() {
+			message = fmt.Sprintf("[%s] %s", strings.ToUpper(appeal.Priority), message)
+		}
+		for _, approver := range approval.GetNotifiedApprovers() {
+			if s.config.Digest.isOptedIn(approver) && !appeal.IsHighPriority() {
+				continue
+			}
 			notifications = append(notifications, domain.Notification{
 				User:    approver,
-				Message: fmt.Sprintf("You have an appeal from %s to access %s", appeal.User, appeal.Resource.URN),
+				Message: message,
+				Channel: approval.NotificationChannel,
+				Labels:  appeal.Labels,
 			})
 		}
 	}
 	return notifications
 }
 
+// uniqueNotifications removes the identical notifications sent to the same user while keeping the order
+func uniqueNotifications(notifications []domain.Notification) []domain.Notification {
+	type key struct {
+		user, message, channel, labels string
+		critical                       bool
+	}
+	seen := map[key]bool{}
+	result := []domain.Notification{}
+	for _, n := range notifications {
+		k := key{n.User, n.Message, n.Channel, labelsKey(n.Labels), n.Critical}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		result = append(result, n)
+	}
+	return result
+}
+
+// labelsKey returns the labels in a stable form to be compared, as the notifications with different labels might
+// be routed to different channels
+func labelsKey(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, fmt.Sprintf("%q=%q", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
 func checkIfAppealStatusStillPending(status string) error {
 	if status == domain.AppealStatusPending {
 		return nil
@@ -531,13 +2565,24 @@ func checkIfAppealStatusStillPending(status string) error {
 	return err
 }
 
+// isApprovalCompleted returns true if every approval step is either approved or skipped
+func isApprovalCompleted(approvals []*domain.Approval) bool {
+	for _, a := range approvals {
+		if a.Status != domain.ApprovalStatusApproved && a.Status != domain.ApprovalStatusSkipped {
+			return false
+		}
+	}
+	return true
+}
+
 func checkPreviousApprovalStatus(status string) error {
 	var err error
 	switch status {
 	case domain.ApprovalStatusApproved,
 		domain.ApprovalStatusSkipped:
 		err = nil
-	case domain.ApprovalStatusPending:
+	case domain.ApprovalStatusPending,
+		domain.ApprovalStatusPendingApproverResolution:
 		err = ErrApprovalDependencyIsPending
 	case domain.ApprovalStatusRejected:
 		err = ErrAppealStatusRejected
@@ -556,6 +2601,8 @@ func checkApprovalStatus(status string) error {
 		err = ErrApprovalStatusRejected
 	case domain.ApprovalStatusSkipped:
 		err = ErrApprovalStatusSkipped
+	case domain.ApprovalStatusPendingApproverResolution:
+		err = ErrApprovalApproversUnresolved
 	default:
 		err = ErrApprovalStatusUnrecognized
 	}
@@ -578,3 +2625,219 @@ func structToMap(item interface{}) (map[string]interface{}, error) {
 
 	return result, nil
 }
+
+// AddAttachment uploads a supporting document to the blob storage and records it to the appeal. Only the requester,
+// the approvers, and the admins can attach documents to the appeal
+func (s *Service) AddAttachment(ctx context.Context, appealID uint, actor, filename string, content io.Reader) (*domain.Attachment, error) {
+	if appealID == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+	filename = path.Base(strings.TrimSpace(filename))
+	if filename == "" || filename == "." || filename == "/" {
+		return nil, ErrAttachmentFilenameRequired
+	}
+	if s.blobStorage == nil {
+		return nil, ErrAttachmentStorageNotConfigured
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+	if !s.isParticipant(appeal, actor) {
+		return nil, ErrAttachmentForbidden
+	}
+
+	maxSize := s.config.getAttachmentMaxSize()
+	data, err := ioutil.ReadAll(io.LimitReader(content, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading attachment: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return nil, fmt.Errorf("detecting attachment content type: %w", err)
+	}
+	if !s.config.isAttachmentTypeAllowed(contentType) {
+		return nil, ErrAttachmentTypeNotAllowed
+	}
+
+	attachment := &domain.Attachment{
+		AppealID:    appealID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  fmt.Sprintf("appeals/%d/%d_%s", appealID, s.TimeNow().UnixNano(), filename),
+	}
+
+	if s.AttachmentScanner != nil {
+		if err := s.AttachmentScanner.Scan(ctx, attachment, bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrAttachmentRejected, err)
+		}
+	}
+
+	if err := s.blobStorage.Put(ctx, attachment.StorageKey, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("storing attachment: %w", err)
+	}
+
+	if err := s.repo.AddAttachment(ctx, attachment); err != nil {
+		if err := s.blobStorage.Delete(ctx, attachment.StorageKey); err != nil {
+			s.getLogger(ctx).Error("failed to clean up stored attachment", zap.String("storage_key", attachment.StorageKey), zap.Error(err))
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// ListAttachments returns the attachments metadata of an appeal to its requester, approvers, and the admins
+func (s *Service) ListAttachments(ctx context.Context, appealID uint, viewer string) ([]*domain.Attachment, error) {
+	if appealID == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+	if !s.isParticipant(appeal, viewer) {
+		return nil, ErrAttachmentForbidden
+	}
+
+	return s.repo.GetAttachments(ctx, appealID)
+}
+
+// GetAttachmentContent returns the attachment metadata along with its content to the requester, the approvers, and
+// the admins of its appeal. The caller is responsible to close the content
+func (s *Service) GetAttachmentContent(ctx context.Context, id uint, viewer string) (*domain.Attachment, io.ReadCloser, error) {
+	if s.blobStorage == nil {
+		return nil, nil, ErrAttachmentStorageNotConfigured
+	}
+
+	attachment, err := s.repo.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil {
+		return nil, nil, ErrAttachmentNotFound
+	}
+
+	appeal, err := s.repo.GetByID(ctx, attachment.AppealID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if appeal == nil {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if !s.isParticipant(appeal, viewer) {
+		return nil, nil, ErrAttachmentForbidden
+	}
+
+	content, err := s.blobStorage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading attachment: %w", err)
+	}
+
+	return attachment, content, nil
+}
+
+// AddComment leaves a comment on an appeal. The requester can only leave public comments,
+// while the approvers and admins can also leave private ones
+func (s *Service) AddComment(ctx context.Context, appealID uint, actor, body, visibility string) (*domain.AppealComment, error) {
+	if appealID == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+	if strings.TrimSpace(body) == "" {
+		return nil, ErrCommentBodyRequired
+	}
+	if visibility == "" {
+		visibility = domain.CommentVisibilityPublic
+	} else if visibility != domain.CommentVisibilityPublic && visibility != domain.CommentVisibilityPrivate {
+		return nil, ErrInvalidCommentVisibility
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+
+	if !s.isParticipant(appeal, actor) {
+		return nil, ErrCommentForbidden
+	}
+	if visibility == domain.CommentVisibilityPrivate && !s.canSeePrivateComments(appeal, actor) {
+		return nil, ErrPrivateCommentForbidden
+	}
+
+	comment := &domain.AppealComment{
+		AppealID:   appealID,
+		CreatedBy:  actor,
+		Body:       body,
+		Visibility: visibility,
+	}
+	if err := s.repo.AddComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// GetComments returns the comments of an appeal visible to the viewer. Only the requester, the approvers,
+// and the admins can see the comments, and the private ones are only returned to the approvers and admins
+func (s *Service) GetComments(ctx context.Context, appealID uint, viewer string) ([]*domain.AppealComment, error) {
+	if appealID == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+	if !s.isParticipant(appeal, viewer) {
+		return nil, ErrCommentForbidden
+	}
+
+	comments, err := s.repo.GetComments(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.canSeePrivateComments(appeal, viewer) {
+		return comments, nil
+	}
+
+	publicComments := []*domain.AppealComment{}
+	for _, c := range comments {
+		if !c.IsPrivate() {
+			publicComments = append(publicComments, c)
+		}
+	}
+	return publicComments, nil
+}
+
+// isParticipant returns true if the user is the requester, one of the approvers, or an admin of the appeal
+func (s *Service) isParticipant(appeal *domain.Appeal, user string) bool {
+	return user != "" && (user == appeal.User || appeal.IsApprover(user) || s.config.isAdmin(user))
+}
+
+// canSeePrivateComments returns false for the requester even if they are also an approver
+func (s *Service) canSeePrivateComments(appeal *domain.Appeal, user string) bool {
+	if user == appeal.User {
+		return false
+	}
+	return appeal.IsApprover(user) || s.config.isAdmin(user)
+}
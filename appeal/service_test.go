@@ -1,13 +1,22 @@
 package appeal_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/odpf/guardian/appeal"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/crypto"
 	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/iam"
+	"github.com/odpf/guardian/metrics"
 	"github.com/odpf/guardian/mocks"
+	"github.com/odpf/guardian/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
@@ -22,6 +31,7 @@ type ServiceTestSuite struct {
 	mockPolicyService   *mocks.PolicyService
 	mockIAMService      *mocks.IAMService
 	mockNotifier        *mocks.Notifier
+	mockBlobStorage     *mocks.BlobStorage
 
 	service *appeal.Service
 	now     time.Time
@@ -35,6 +45,7 @@ func (s *ServiceTestSuite) SetupTest() {
 	s.mockPolicyService = new(mocks.PolicyService)
 	s.mockIAMService = new(mocks.IAMService)
 	s.mockNotifier = new(mocks.Notifier)
+	s.mockBlobStorage = new(mocks.BlobStorage)
 	s.now = time.Now()
 
 	service := appeal.NewService(
@@ -45,7 +56,11 @@ func (s *ServiceTestSuite) SetupTest() {
 		s.mockPolicyService,
 		s.mockIAMService,
 		s.mockNotifier,
-		&zap.Logger{},
+		s.mockBlobStorage,
+		zap.NewNop(),
+		&appeal.Config{
+			Admins: []string{"admin@email.com"},
+		},
 	)
 	service.TimeNow = func() time.Time {
 		return s.now
@@ -54,11 +69,24 @@ func (s *ServiceTestSuite) SetupTest() {
 	s.service = service
 }
 
+// runInTransaction makes the mocked WithTransaction run the function with the same mocked repository
+func runInTransaction(repo *mocks.AppealRepository) func(context.Context, func(domain.AppealRepository) error) error {
+	return func(_ context.Context, fn func(domain.AppealRepository) error) error {
+		return fn(repo)
+	}
+}
+
+// expectOutboxMessage expects the message of a change to be stored within its transaction and deleted once it's sent
+func (s *ServiceTestSuite) expectOutboxMessage() {
+	s.mockRepository.On("AddOutboxMessage", mock.Anything, mock.Anything).Return(nil).Once()
+	s.mockRepository.On("DeleteOutboxMessages", mock.Anything, []uint{0}).Return(nil).Once()
+}
+
 func (s *ServiceTestSuite) TestGetByID() {
 	s.Run("should return error if id is empty/0", func() {
 		expectedError := appeal.ErrAppealIDEmptyParam
 
-		actualResult, actualError := s.service.GetByID(0)
+		actualResult, actualError := s.service.GetByID(context.Background(), 0)
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -66,9 +94,9 @@ func (s *ServiceTestSuite) TestGetByID() {
 
 	s.Run("should return error if got any from repository", func() {
 		expectedError := errors.New("repository error")
-		s.mockRepository.On("GetByID", mock.Anything).Return(nil, expectedError).Once()
+		s.mockRepository.On("GetByID", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
 
-		actualResult, actualError := s.service.GetByID(1)
+		actualResult, actualError := s.service.GetByID(context.Background(), 1)
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -79,21 +107,133 @@ func (s *ServiceTestSuite) TestGetByID() {
 		expectedResult := &domain.Appeal{
 			ID: expectedID,
 		}
-		s.mockRepository.On("GetByID", expectedID).Return(expectedResult, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, expectedID).Return(expectedResult, nil).Once()
+
+		actualResult, actualError := s.service.GetByID(context.Background(), expectedID)
+
+		s.Equal(expectedResult, actualResult)
+		s.Nil(actualError)
+	})
+}
+
+func (s *ServiceTestSuite) TestHasActiveAccess() {
+	timeNow := time.Now()
+	s.service.TimeNow = func() time.Time {
+		return timeNow
+	}
+
+	s.Run("should return error if params are incomplete", func() {
+		actualHasAccess, actualAppeal, actualError := s.service.HasActiveAccess(context.Background(), "user@email.com", 0, "viewer")
+
+		s.False(actualHasAccess)
+		s.Nil(actualAppeal)
+		s.EqualError(actualError, appeal.ErrAccessCheckInvalidParams.Error())
+	})
+
+	s.Run("should return error if got any from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("GetActiveAccess", mock.Anything, "user@email.com", uint(1), "viewer", timeNow).Return(nil, expectedError).Once()
+
+		actualHasAccess, actualAppeal, actualError := s.service.HasActiveAccess(context.Background(), "user@email.com", 1, "viewer")
+
+		s.False(actualHasAccess)
+		s.Nil(actualAppeal)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return false if user doesn't have any active access", func() {
+		s.mockRepository.On("GetActiveAccess", mock.Anything, "user@email.com", uint(1), "viewer", timeNow).Return(nil, nil).Once()
+
+		actualHasAccess, actualAppeal, actualError := s.service.HasActiveAccess(context.Background(), "user@email.com", 1, "viewer")
+
+		s.False(actualHasAccess)
+		s.Nil(actualAppeal)
+		s.Nil(actualError)
+	})
+
+	s.Run("should return true along with the appeal granting the access", func() {
+		expectedAppeal := &domain.Appeal{ID: 1, User: "user@email.com", ResourceID: 1, Role: "viewer", Status: domain.AppealStatusActive}
+		s.mockRepository.On("GetActiveAccess", mock.Anything, "user@email.com", uint(1), "viewer", timeNow).Return(expectedAppeal, nil).Once()
+
+		actualHasAccess, actualAppeal, actualError := s.service.HasActiveAccess(context.Background(), "user@email.com", 1, "viewer")
+
+		s.True(actualHasAccess)
+		s.Equal(expectedAppeal, actualAppeal)
+		s.Nil(actualError)
+	})
+}
+
+func (s *ServiceTestSuite) TestGetUserAccessSummary() {
+	user := "user@email.com"
+
+	s.Run("should return error if user is empty", func() {
+		actualResult, actualError := s.service.GetUserAccessSummary(context.Background(), "", false)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrUserEmptyParam)
+	})
+
+	s.Run("should return error if got any from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("GetAccessSummary", mock.Anything, user, s.now).Return(nil, expectedError).Once()
+
+		actualResult, actualError := s.service.GetUserAccessSummary(context.Background(), user, false)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	newEntries := func() []domain.AccessEntry {
+		return []domain.AccessEntry{
+			{AppealID: 1, ProviderType: "google_bigquery", ProviderURN: "provider_1", ResourceType: "dataset", ResourceURN: "dataset_1", Role: "viewer"},
+			{AppealID: 2, ProviderType: "google_bigquery", ProviderURN: "provider_1", ResourceType: "dataset", ResourceURN: "dataset_2", Role: "viewer"},
+			{AppealID: 3, ProviderType: "metabase", ProviderURN: "provider_2", ResourceType: "collection", ResourceURN: "collection_1", Role: "editor"},
+		}
+	}
+
+	s.Run("should return the access entries without permissions if not requested", func() {
+		expectedResult := newEntries()
+		s.mockRepository.On("GetAccessSummary", mock.Anything, user, s.now).Return(expectedResult, nil).Once()
 
-		actualResult, actualError := s.service.GetByID(expectedID)
+		actualResult, actualError := s.service.GetUserAccessSummary(context.Background(), user, false)
 
+		s.Nil(actualError)
 		s.Equal(expectedResult, actualResult)
+	})
+
+	s.Run("should enrich the access entries with the role permissions", func() {
+		s.mockRepository.On("GetAccessSummary", mock.Anything, user, s.now).Return(newEntries(), nil).Once()
+		s.mockProviderService.On("GetRolePermissions", mock.Anything, "google_bigquery", "provider_1", "dataset", "viewer").Return([]interface{}{"READER"}, nil).Once()
+		s.mockProviderService.On("GetRolePermissions", mock.Anything, "metabase", "provider_2", "collection", "editor").Return([]interface{}{"write"}, nil).Once()
+
+		actualResult, actualError := s.service.GetUserAccessSummary(context.Background(), user, true)
+
 		s.Nil(actualError)
+		s.Len(actualResult, 3)
+		s.Equal([]interface{}{"READER"}, actualResult[0].Permissions)
+		s.Equal([]interface{}{"READER"}, actualResult[1].Permissions)
+		s.Equal([]interface{}{"write"}, actualResult[2].Permissions)
+		s.mockProviderService.AssertExpectations(s.T())
+	})
+
+	s.Run("should return error if failed to get the role permissions", func() {
+		expectedError := errors.New("provider error")
+		s.mockRepository.On("GetAccessSummary", mock.Anything, user, s.now).Return(newEntries(), nil).Once()
+		s.mockProviderService.On("GetRolePermissions", mock.Anything, "google_bigquery", "provider_1", "dataset", "viewer").Return(nil, expectedError).Once()
+
+		actualResult, actualError := s.service.GetUserAccessSummary(context.Background(), user, true)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, expectedError)
 	})
 }
 
 func (s *ServiceTestSuite) TestFind() {
 	s.Run("should return error if got any from repository", func() {
 		expectedError := errors.New("unexpected repository error")
-		s.mockRepository.On("Find", mock.Anything).Return(nil, expectedError).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
 
-		actualResult, actualError := s.service.Find(map[string]interface{}{})
+		actualResult, actualError := s.service.Find(context.Background(), map[string]interface{}{})
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -111,9 +251,9 @@ func (s *ServiceTestSuite) TestFind() {
 				Role:       "viewer",
 			},
 		}
-		s.mockRepository.On("Find", expectedFilters).Return(expectedResult, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, expectedFilters).Return(expectedResult, nil).Once()
 
-		actualResult, actualError := s.service.Find(expectedFilters)
+		actualResult, actualError := s.service.Find(context.Background(), expectedFilters)
 
 		s.Equal(expectedResult, actualResult)
 		s.Nil(actualError)
@@ -123,20 +263,20 @@ func (s *ServiceTestSuite) TestFind() {
 func (s *ServiceTestSuite) TestCreate() {
 	s.Run("should return error if got error from resource service", func() {
 		expectedError := errors.New("resource service error")
-		s.mockResourceService.On("Find", mock.Anything).Return(nil, expectedError).Once()
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
 
-		actualError := s.service.Create([]*domain.Appeal{})
+		actualError := s.service.Create(context.Background(), []*domain.Appeal{})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
 	s.Run("should return error if got error from provider service", func() {
 		expectedResources := []*domain.Resource{}
-		s.mockResourceService.On("Find", mock.Anything).Return(expectedResources, nil).Once()
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(expectedResources, nil).Once()
 		expectedError := errors.New("provider service error")
-		s.mockProviderService.On("Find").Return(nil, expectedError).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return(nil, expectedError).Once()
 
-		actualError := s.service.Create([]*domain.Appeal{})
+		actualError := s.service.Create(context.Background(), []*domain.Appeal{})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -144,12 +284,12 @@ func (s *ServiceTestSuite) TestCreate() {
 	s.Run("should return error if got error from policy service", func() {
 		expectedResources := []*domain.Resource{}
 		expectedProviders := []*domain.Provider{}
-		s.mockResourceService.On("Find", mock.Anything).Return(expectedResources, nil).Once()
-		s.mockProviderService.On("Find").Return(expectedProviders, nil).Once()
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(expectedResources, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return(expectedProviders, nil).Once()
 		expectedError := errors.New("policy service error")
-		s.mockPolicyService.On("Find").Return(nil, expectedError).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return(nil, expectedError).Once()
 
-		actualError := s.service.Create([]*domain.Appeal{})
+		actualError := s.service.Create(context.Background(), []*domain.Appeal{})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -158,17 +298,324 @@ func (s *ServiceTestSuite) TestCreate() {
 		expectedResources := []*domain.Resource{}
 		expectedProviders := []*domain.Provider{}
 		expectedPolicies := []*domain.Policy{}
-		s.mockResourceService.On("Find", mock.Anything).Return(expectedResources, nil).Once()
-		s.mockProviderService.On("Find").Return(expectedProviders, nil).Once()
-		s.mockPolicyService.On("Find").Return(expectedPolicies, nil).Once()
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(expectedResources, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return(expectedProviders, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return(expectedPolicies, nil).Once()
 		expectedError := errors.New("appeal repository error")
-		s.mockRepository.On("Find", mock.Anything).Return(nil, expectedError).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
 
-		actualError := s.service.Create([]*domain.Appeal{})
+		actualError := s.service.Create(context.Background(), []*domain.Appeal{})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
+	s.Run("should return field errors for the labels not matching the label schema", func() {
+		service := appeal.NewService(
+			s.mockRepository,
+			s.mockApprovalService,
+			s.mockResourceService,
+			s.mockProviderService,
+			s.mockPolicyService,
+			s.mockIAMService,
+			s.mockNotifier,
+			s.mockBlobStorage,
+			zap.NewNop(),
+			&appeal.Config{
+				LabelSchema: []appeal.LabelSchemaField{
+					{Key: "ticket", Required: true},
+					{Key: "environment", AllowedValues: []string{"staging", "production"}},
+					{Key: "headcount", Type: appeal.LabelTypeNumber},
+					{Key: "oncall", Type: appeal.LabelTypeBoolean},
+				},
+			},
+		)
+		testCases := []struct {
+			labels         map[string]string
+			expectedFields map[string]string
+		}{
+			{
+				labels: map[string]string{
+					"environment": "dev",
+					"headcount":   "a few",
+					"oncall":      "sometimes",
+					"team":        "data",
+				},
+				expectedFields: map[string]string{
+					"ticket":      "is required",
+					"environment": `"dev" is not one of the allowed values`,
+					"headcount":   `"a few" is not a number`,
+					"oncall":      `"sometimes" is not a boolean`,
+					"team":        "is not allowed by the label schema",
+				},
+			},
+			{
+				labels: map[string]string{
+					"ticket":      "SEC-1",
+					"environment": "production",
+					"headcount":   "2",
+					"oncall":      "true",
+				},
+			},
+		}
+		for _, tc := range testCases {
+			s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{}, nil).Once()
+			s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{}, nil).Once()
+			s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+			s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+
+			actualError := service.Create(context.Background(), []*domain.Appeal{{ResourceID: 1, Labels: tc.labels}})
+
+			var labelsError *appeal.InvalidLabelsError
+			if tc.expectedFields != nil {
+				s.Require().True(errors.As(actualError, &labelsError))
+				s.True(errors.Is(actualError, appeal.ErrInvalidLabels))
+				s.Equal(tc.expectedFields, labelsError.Fields)
+			} else {
+				// the labels are valid, the appeal fails afterwards for its missing resource
+				s.EqualError(actualError, appeal.ErrResourceNotFound.Error())
+			}
+		}
+	})
+
+	s.Run("should return error if the resource belongs to another organization than the requester's", func() {
+		// the resource of another organization isn't found within the requester's organization
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{
+			"organization_id": "org-a",
+			"ids":             []uint{1},
+		}).Return([]*domain.Resource{}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		actualError := s.service.Create(ctx, []*domain.Appeal{{ResourceID: 1, User: "user@email.com", Role: "viewer"}})
+
+		s.EqualError(actualError, appeal.ErrResourceNotFound.Error())
+	})
+
+	s.Run("should validate the requested duration against the max duration of the role", func() {
+		providers := []*domain.Provider{{
+			ID:   1,
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{
+					AllowPermanentAccess: true,
+					MaxDuration:          "720h",
+				},
+				Resources: []*domain.ResourceConfig{{
+					Type: "resource_type",
+					Policy: &domain.PolicyConfig{
+						ID:      "policy_id",
+						Version: 1,
+					},
+					Roles: []*domain.RoleConfig{
+						{ID: "admin", MaxDuration: "4h"},
+						{ID: "viewer"},
+						{ID: "auditor", MaxDuration: "2160h"},
+					},
+				}},
+			},
+		}}
+		resources := []*domain.Resource{{
+			ID:           1,
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+			Type:         "resource_type",
+		}}
+		testCases := []struct {
+			name          string
+			role          string
+			duration      time.Duration
+			expectedError error
+		}{
+			{
+				name:          "longer than the role max duration",
+				role:          "admin",
+				duration:      8 * time.Hour,
+				expectedError: appeal.ErrDurationExceedsRoleMax,
+			},
+			{
+				name:          "permanent access of a role with max duration",
+				role:          "admin",
+				expectedError: appeal.ErrDurationExceedsRoleMax,
+			},
+			{
+				name:          "longer than the provider max duration",
+				role:          "viewer",
+				duration:      1000 * time.Hour,
+				expectedError: appeal.ErrDurationExceedsRoleMax,
+			},
+			{
+				name:          "within the provider max duration",
+				role:          "viewer",
+				duration:      24 * time.Hour,
+				expectedError: appeal.ErrPolicyIDNotFound,
+			},
+			{
+				name:          "role max duration overriding the provider max duration",
+				role:          "auditor",
+				duration:      1000 * time.Hour,
+				expectedError: appeal.ErrPolicyIDNotFound,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				options := &domain.AppealOptions{}
+				if tc.duration != 0 {
+					expirationDate := time.Now().Add(tc.duration)
+					options.ExpirationDate = &expirationDate
+				} else {
+					s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Once()
+				}
+				s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(resources, nil).Once()
+				s.mockProviderService.On("Find", mock.Anything).Return(providers, nil).Once()
+				s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+				s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+
+				actualError := s.service.Create(context.Background(), []*domain.Appeal{{
+					ResourceID: 1,
+					Role:       tc.role,
+					Options:    options,
+				}})
+
+				s.ErrorIs(actualError, tc.expectedError)
+			})
+		}
+	})
+
+	s.Run("should return error if the user is not in the groups allowed to appeal for the role", func() {
+		providers := []*domain.Provider{{
+			ID:   1,
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{{
+					Type:   "resource_type",
+					Policy: &domain.PolicyConfig{ID: "policy_id", Version: 1},
+					Roles: []*domain.RoleConfig{
+						{ID: "admin", AllowedGroups: []string{"data-platform", "sre"}},
+					},
+				}},
+			},
+		}}
+		resources := []*domain.Resource{{
+			ID:           1,
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+			Type:         "resource_type",
+		}}
+		testCases := []struct {
+			name          string
+			userGroups    []string
+			groupsErr     error
+			expectedError error
+		}{
+			{
+				name:          "not a member of the allowed groups",
+				userGroups:    []string{"marketing"},
+				expectedError: appeal.ErrRoleNotEligible,
+			},
+			{
+				name:          "groups can't be resolved",
+				groupsErr:     iam.ErrGroupsNotSupported,
+				expectedError: iam.ErrGroupsNotSupported,
+			},
+			{
+				name:          "member of an allowed group",
+				userGroups:    []string{"marketing", "sre"},
+				expectedError: appeal.ErrPolicyIDNotFound,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(resources, nil).Once()
+				s.mockProviderService.On("Find", mock.Anything).Return(providers, nil).Once()
+				s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+				s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+				s.mockIAMService.On("GetUserGroups", "user@email.com").Return(tc.userGroups, tc.groupsErr).Once()
+				expirationDate := time.Now().Add(24 * time.Hour)
+
+				actualError := s.service.Create(context.Background(), []*domain.Appeal{{
+					ResourceID: 1,
+					User:       "user@email.com",
+					Role:       "admin",
+					Options:    &domain.AppealOptions{ExpirationDate: &expirationDate},
+				}})
+
+				s.ErrorIs(actualError, tc.expectedError)
+			})
+		}
+	})
+
+	s.Run("should return error if the role is not compatible with the resource attributes", func() {
+		providers := []*domain.Provider{{
+			ID:   1,
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{{
+					Type:   "resource_type",
+					Policy: &domain.PolicyConfig{ID: "policy_id", Version: 1},
+					Roles: []*domain.RoleConfig{
+						{ID: "editor", ResourceRequirements: []*domain.Condition{
+							{Field: "$resource.details.read_only", Match: &domain.MatchCondition{Eq: false}},
+							{Field: "$resource.details.replicas", Match: &domain.MatchCondition{Eq: 1}},
+						}},
+					},
+				}},
+			},
+		}}
+		testCases := []struct {
+			name          string
+			details       map[string]interface{}
+			expectedError error
+		}{
+			{
+				name:          "read-only resource",
+				details:       map[string]interface{}{"read_only": true, "replicas": 1},
+				expectedError: appeal.ErrRoleIncompatibleWithResource,
+			},
+			{
+				name:          "resource missing the attribute",
+				details:       map[string]interface{}{"replicas": 1},
+				expectedError: appeal.ErrRoleIncompatibleWithResource,
+			},
+			{
+				name:          "compatible resource",
+				details:       map[string]interface{}{"read_only": false, "replicas": float64(1)},
+				expectedError: appeal.ErrPolicyIDNotFound,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				resources := []*domain.Resource{{
+					ID:           1,
+					ProviderType: "provider_type",
+					ProviderURN:  "provider_urn",
+					Type:         "resource_type",
+					Details:      tc.details,
+				}}
+				s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(resources, nil).Once()
+				s.mockProviderService.On("Find", mock.Anything).Return(providers, nil).Once()
+				s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+				s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+				expirationDate := time.Now().Add(24 * time.Hour)
+
+				actualError := s.service.Create(context.Background(), []*domain.Appeal{{
+					ResourceID: 1,
+					User:       "user@email.com",
+					Role:       "editor",
+					Options:    &domain.AppealOptions{ExpirationDate: &expirationDate},
+				}})
+
+				s.ErrorIs(actualError, tc.expectedError)
+			})
+		}
+	})
+
 	s.Run("should return error for invalid appeals", func() {
 		provider := &domain.Provider{
 			ID:   1,
@@ -218,6 +665,43 @@ func (s *ServiceTestSuite) TestCreate() {
 				}},
 				expectedError: appeal.ErrAppealDuplicate,
 			},
+			{
+				name:          "invalid priority",
+				appeals:       []*domain.Appeal{{ResourceID: 1, Priority: "critical"}},
+				expectedError: appeal.ErrInvalidPriority,
+			},
+			{
+				name: "multiple invalid appeals in a batch",
+				appeals: []*domain.Appeal{
+					{ResourceID: 1},
+					{ResourceID: 2, Priority: "critical"},
+				},
+				expectedError: &appeal.BatchCreateError{
+					Failed: map[int]error{
+						0: appeal.ErrResourceNotFound,
+						1: appeal.ErrInvalidPriority,
+					},
+				},
+			},
+			{
+				name: "resource on the deny list",
+				resources: []*domain.Resource{{
+					ID:          1,
+					DenyAppeals: true,
+				}},
+				appeals:       []*domain.Appeal{{ResourceID: 1}},
+				expectedError: appeal.ErrResourceNotAppealable,
+			},
+			{
+				name: "resource on the deny list with a reason",
+				resources: []*domain.Resource{{
+					ID:                1,
+					DenyAppeals:       true,
+					DenyAppealsReason: "contains customer PII, reach out to the data owner",
+				}},
+				appeals:       []*domain.Appeal{{ResourceID: 1}},
+				expectedError: fmt.Errorf("%w: %s", appeal.ErrResourceNotAppealable, "contains customer PII, reach out to the data owner"),
+			},
 			{
 				name: "provider type not found",
 				resources: []*domain.Resource{{
@@ -248,9 +732,12 @@ func (s *ServiceTestSuite) TestCreate() {
 					ProviderURN:  "provider_urn",
 					Type:         "invalid_resource_type",
 				}},
-				providers:     []*domain.Provider{provider},
-				appeals:       []*domain.Appeal{{ResourceID: 1}},
-				expectedError: appeal.ErrResourceTypeNotFound,
+				providers: []*domain.Provider{provider},
+				appeals:   []*domain.Appeal{{ResourceID: 1}},
+				expectedError: &appeal.ResourceTypeNotFoundError{
+					ResourceType:   "invalid_resource_type",
+					AvailableTypes: []string{"resource_type"},
+				},
 			},
 			{
 				name: "expiration date nil or not found when the appeal config disallow permanent access",
@@ -299,7 +786,11 @@ func (s *ServiceTestSuite) TestCreate() {
 						ExpirationDate: &timeNow,
 					},
 				}},
-				expectedError: appeal.ErrInvalidRole,
+				expectedError: &appeal.InvalidRoleError{
+					ResourceType:   "resource_type",
+					Role:           "invalid_role",
+					AvailableRoles: []string{"role_1"},
+				},
 			},
 			{
 				name: "policy id not found",
@@ -340,16 +831,63 @@ func (s *ServiceTestSuite) TestCreate() {
 				}},
 				expectedError: appeal.ErrPolicyVersionNotFound,
 			},
+			{
+				name: "policy of another organization",
+				resources: []*domain.Resource{{
+					ID:             1,
+					ProviderType:   "provider_type",
+					ProviderURN:    "provider_urn",
+					Type:           "resource_type",
+					OrganizationID: "org-a",
+				}},
+				providers: []*domain.Provider{provider},
+				policies: []*domain.Policy{{
+					ID:             "policy_id",
+					Version:        1,
+					OrganizationID: "org-b",
+				}},
+				appeals: []*domain.Appeal{{
+					ResourceID: 1,
+					Role:       "role_1",
+					Options: &domain.AppealOptions{
+						ExpirationDate: &timeNow,
+					},
+				}},
+				expectedError: appeal.ErrPolicyIDNotFound,
+			},
+			{
+				name: "policy without an organization for a resource of an organization",
+				resources: []*domain.Resource{{
+					ID:             1,
+					ProviderType:   "provider_type",
+					ProviderURN:    "provider_urn",
+					Type:           "resource_type",
+					OrganizationID: "org-a",
+				}},
+				providers: []*domain.Provider{provider},
+				policies: []*domain.Policy{{
+					ID:      "policy_id",
+					Version: 1,
+				}},
+				appeals: []*domain.Appeal{{
+					ResourceID: 1,
+					Role:       "role_1",
+					Options: &domain.AppealOptions{
+						ExpirationDate: &timeNow,
+					},
+				}},
+				expectedError: appeal.ErrPolicyIDNotFound,
+			},
 		}
 
 		for _, tc := range testCases {
 			s.Run(tc.name, func() {
-				s.mockResourceService.On("Find", mock.Anything).Return(tc.resources, nil).Once()
-				s.mockProviderService.On("Find").Return(tc.providers, nil).Once()
-				s.mockPolicyService.On("Find").Return(tc.policies, nil).Once()
-				s.mockRepository.On("Find", mock.Anything).Return(tc.pendingAppeals, nil).Once()
+				s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(tc.resources, nil).Once()
+				s.mockProviderService.On("Find", mock.Anything).Return(tc.providers, nil).Once()
+				s.mockPolicyService.On("Find", mock.Anything).Return(tc.policies, nil).Once()
+				s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(tc.pendingAppeals, nil).Once()
 
-				actualError := s.service.Create(tc.appeals)
+				actualError := s.service.Create(context.Background(), tc.appeals)
 
 				s.EqualError(actualError, tc.expectedError.Error())
 			})
@@ -361,14 +899,14 @@ func (s *ServiceTestSuite) TestCreate() {
 		expectedProviders := []*domain.Provider{}
 		expectedPolicies := []*domain.Policy{}
 		expectedPendingAppeals := []*domain.Appeal{}
-		s.mockResourceService.On("Find", mock.Anything).Return(expectedResources, nil).Once()
-		s.mockProviderService.On("Find").Return(expectedProviders, nil).Once()
-		s.mockPolicyService.On("Find").Return(expectedPolicies, nil).Once()
-		s.mockRepository.On("Find", mock.Anything).Return(expectedPendingAppeals, nil).Once()
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(expectedResources, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return(expectedProviders, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return(expectedPolicies, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(expectedPendingAppeals, nil).Once()
 		expectedError := errors.New("repository error")
-		s.mockRepository.On("BulkInsert", mock.Anything).Return(expectedError).Once()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(expectedError).Once()
 
-		actualError := s.service.Create([]*domain.Appeal{})
+		actualError := s.service.Create(context.Background(), []*domain.Appeal{})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -439,6 +977,8 @@ func (s *ServiceTestSuite) TestCreate() {
 			Status:        domain.AppealStatusPending,
 			User:          user,
 			Role:          "role_id",
+			Priority:      domain.AppealPriorityNormal,
+			GroupID:       "group-1",
 			Approvals: []*domain.Approval{
 				{
 					Name:          "step_1",
@@ -469,6 +1009,8 @@ func (s *ServiceTestSuite) TestCreate() {
 			Status:        domain.AppealStatusPending,
 			User:          user,
 			Role:          "role_id",
+			Priority:      domain.AppealPriorityNormal,
+			GroupID:       "group-1",
 			Approvals: []*domain.Approval{
 				{
 					ID:            1,
@@ -499,6 +1041,8 @@ func (s *ServiceTestSuite) TestCreate() {
 			Status:        domain.AppealStatusPending,
 			User:          user,
 			Role:          "role_id",
+			Priority:      domain.AppealPriorityNormal,
+			GroupID:       "group-1",
 			Approvals: []*domain.Approval{
 				{
 					ID:            1,
@@ -524,21 +1068,22 @@ func (s *ServiceTestSuite) TestCreate() {
 
 	s.Run("should return appeals on success", func() {
 		expectedResourceFilters := map[string]interface{}{"ids": resourceIDs}
-		s.mockResourceService.On("Find", expectedResourceFilters).Return(resources, nil).Once()
-		s.mockProviderService.On("Find").Return(providers, nil).Once()
-		s.mockPolicyService.On("Find").Return(policies, nil).Once()
+		s.mockResourceService.On("Find", mock.Anything, expectedResourceFilters).Return(resources, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return(providers, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return(policies, nil).Once()
 		expectedPendingAppealsFilters := map[string]interface{}{
 			"statuses": []string{domain.AppealStatusPending},
 		}
-		s.mockRepository.On("Find", expectedPendingAppealsFilters).Return([]*domain.Appeal{}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, expectedPendingAppealsFilters).Return([]*domain.Appeal{}, nil).Once()
 		expectedUserApprovers := []string{"user.approver@email.com"}
 		s.mockIAMService.On("GetUserApproverEmails", user).Return(expectedUserApprovers, nil)
-		s.mockApprovalService.On("AdvanceApproval", mock.Anything).Return(nil)
+		s.mockProviderService.On("GetCapabilities", mock.Anything).Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Twice()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil)
 		s.mockRepository.
-			On("BulkInsert", expectedAppealsInsertionParam).
+			On("BulkInsert", mock.Anything, expectedAppealsInsertionParam).
 			Return(nil).
 			Run(func(args mock.Arguments) {
-				appeals := args.Get(0).([]*domain.Appeal)
+				appeals := args.Get(1).([]*domain.Appeal)
 				for i, a := range appeals {
 					a.ID = expectedResult[i].ID
 					for j, approval := range a.Approvals {
@@ -557,7 +1102,8 @@ func (s *ServiceTestSuite) TestCreate() {
 					ID:  1,
 					URN: "urn",
 				},
-				Role: "role_id",
+				Role:    "role_id",
+				GroupID: "group-1",
 			},
 			{
 				User:       user,
@@ -566,658 +1112,6230 @@ func (s *ServiceTestSuite) TestCreate() {
 					ID:  2,
 					URN: "urn",
 				},
-				Role: "role_id",
+				Role:    "role_id",
+				GroupID: "group-1",
 			},
 		}
-		actualError := s.service.Create(appeals)
+		actualError := s.service.Create(context.Background(), appeals)
 
 		s.Equal(expectedResult, appeals)
 		s.Nil(actualError)
 	})
-}
 
-func (s *ServiceTestSuite) TestMakeAction() {
-	timeNow := time.Now()
-	appeal.TimeNow = func() time.Time {
-		return timeNow
-	}
-	s.Run("should return error if approval action parameter is invalid", func() {
-		invalidApprovalActionParameters := []domain.ApprovalAction{
-			{
-				ApprovalName: "approval_1",
-				Actor:        "user@email.com",
-				Action:       "name",
-			},
-			{
-				AppealID: 1,
-				Actor:    "user@email.com",
-				Action:   "name",
-			},
-			{
-				AppealID:     1,
-				ApprovalName: "approval_1",
-				Actor:        "invalidemail",
-				Action:       "name",
+	s.Run("should fall back to the default policy if resource type has no policy configured", func() {
+		resource := &domain.Resource{
+			ID:           1,
+			Type:         "resource_type",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+		}
+		newProvider := func(defaultPolicy *domain.PolicyConfig) *domain.Provider {
+			return &domain.Provider{
+				Type: "provider_type",
+				URN:  "provider_urn",
+				Config: &domain.ProviderConfig{
+					Appeal: &domain.AppealConfig{
+						AllowPermanentAccess: true,
+						DefaultPolicy:        defaultPolicy,
+					},
+					Resources: []*domain.ResourceConfig{
+						{
+							Type:  "resource_type",
+							Roles: []*domain.RoleConfig{{ID: "role_1"}},
+						},
+					},
+				},
+			}
+		}
+		policies := []*domain.Policy{
+			{ID: "provider_default", Version: 1, Steps: []*domain.Step{{Name: "step_1"}}},
+			{ID: "global_default", Version: 2, Steps: []*domain.Step{{Name: "step_1"}}},
+		}
+		service := appeal.NewService(
+			s.mockRepository,
+			s.mockApprovalService,
+			s.mockResourceService,
+			s.mockProviderService,
+			s.mockPolicyService,
+			s.mockIAMService,
+			s.mockNotifier,
+			s.mockBlobStorage,
+			zap.NewNop(),
+			&appeal.Config{
+				DefaultPolicy: appeal.DefaultPolicyConfig{ID: "global_default", Version: 2},
 			},
+		)
+		testCases := []struct {
+			name                  string
+			provider              *domain.Provider
+			expectedPolicyID      string
+			expectedPolicyVersion uint
+		}{
 			{
-				AppealID:     1,
-				ApprovalName: "approval_1",
-				Action:       "name",
+				name:                  "provider default policy",
+				provider:              newProvider(&domain.PolicyConfig{ID: "provider_default", Version: 1}),
+				expectedPolicyID:      "provider_default",
+				expectedPolicyVersion: 1,
 			},
 			{
-				AppealID:     1,
-				ApprovalName: "approval_1",
-				Actor:        "user@email.com",
+				name:                  "global default policy",
+				provider:              newProvider(nil),
+				expectedPolicyID:      "global_default",
+				expectedPolicyVersion: 2,
 			},
 		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{resource}, nil).Once()
+				s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{tc.provider}, nil).Once()
+				s.mockPolicyService.On("Find", mock.Anything).Return(policies, nil).Once()
+				s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+				s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Once()
+				s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Once()
+				s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+				appeals := []*domain.Appeal{{ResourceID: 1, User: "user@email.com", Role: "role_1"}}
 
-		for _, param := range invalidApprovalActionParameters {
-			actualResult, actualError := s.service.MakeAction(param)
+				actualError := service.Create(context.Background(), appeals)
 
-			s.Nil(actualResult)
-			s.Error(actualError)
+				s.Nil(actualError)
+				s.Equal(tc.expectedPolicyID, appeals[0].PolicyID)
+				s.Equal(tc.expectedPolicyVersion, appeals[0].PolicyVersion)
+			})
 		}
 	})
 
-	validApprovalActionParam := domain.ApprovalAction{
-		AppealID:     1,
-		ApprovalName: "approval_1",
-		Actor:        "user@email.com",
-		Action:       "approve",
-	}
-
-	s.Run("should return error if got any from repository while getting appeal details", func() {
-		expectedError := errors.New("repository error")
-		s.mockRepository.On("GetByID", mock.Anything).Return(nil, expectedError).Once()
+	s.Run("should group the appeals created together", func() {
+		resources := []*domain.Resource{
+			{ID: 1, Type: "resource_type", ProviderType: "provider_type", ProviderURN: "provider_urn"},
+			{ID: 2, Type: "resource_type", ProviderType: "provider_type", ProviderURN: "provider_urn"},
+		}
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(resources, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+				Resources: []*domain.ResourceConfig{
+					{
+						Type:   "resource_type",
+						Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "role_1"}},
+					},
+				},
+			},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{ID: "policy_1", Version: 1, Steps: []*domain.Step{{Name: "step_1"}}}}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Twice()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Twice()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "user@email.com",
+			Message: "2 appeals submitted, awaiting approval",
+		}}).Return(nil).Once()
+		appeals := []*domain.Appeal{
+			{ResourceID: 1, User: "user@email.com", Role: "role_1"},
+			{ResourceID: 2, User: "user@email.com", Role: "role_1"},
+		}
 
-		actualResult, actualError := s.service.MakeAction(validApprovalActionParam)
+		actualError := s.service.Create(context.Background(), appeals)
 
-		s.Nil(actualResult)
-		s.EqualError(actualError, expectedError.Error())
+		s.Nil(actualError)
+		s.NotEmpty(appeals[0].GroupID)
+		s.Equal(appeals[0].GroupID, appeals[1].GroupID)
 	})
 
-	s.Run("should return nil and nil error if appeal not found", func() {
-		s.mockRepository.On("GetByID", validApprovalActionParam.AppealID).Return(nil, nil).Once()
+	s.Run("should spread the appeals across the approvers of an assigned step", func() {
+		approvers := []interface{}{"approver1@email.com", "approver2@email.com"}
+		resources := []*domain.Resource{
+			{ID: 1, URN: "urn_1", Type: "resource_type", ProviderType: "provider_type", ProviderURN: "provider_urn", Details: map[string]interface{}{"approvers": approvers}},
+			{ID: 2, URN: "urn_2", Type: "resource_type", ProviderType: "provider_type", ProviderURN: "provider_urn", Details: map[string]interface{}{"approvers": approvers}},
+		}
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(resources, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+				Resources: []*domain.ResourceConfig{
+					{
+						Type:   "resource_type",
+						Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "role_1"}},
+					},
+				},
+			},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{ID: "policy_1", Version: 1, Steps: []*domain.Step{{
+			Name:               "step_1",
+			Approvers:          "$resource.details.approvers",
+			AssignmentStrategy: domain.AssignmentStrategyRoundRobin,
+		}}}}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Twice()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Twice()
+		s.mockApprovalService.On("GetApproverLoads", mock.Anything, []string{"approver1@email.com", "approver2@email.com"}).Return(map[string]*domain.ApproverLoad{}, nil).Once()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{
+			{User: "approver1@email.com", Message: "You have an appeal from user@email.com to access urn_1"},
+			{User: "approver2@email.com", Message: "You have an appeal from user@email.com to access urn_2"},
+			{User: "user@email.com", Message: "2 appeals submitted, awaiting approval from approver1@email.com, approver2@email.com"},
+		}).Return(nil).Once()
+		appeals := []*domain.Appeal{
+			{ResourceID: 1, User: "user@email.com", Role: "role_1"},
+			{ResourceID: 2, User: "user@email.com", Role: "role_1"},
+		}
 
-		actualResult, actualError := s.service.MakeAction(validApprovalActionParam)
+		actualError := s.service.Create(context.Background(), appeals)
 
-		s.Nil(actualResult)
 		s.Nil(actualError)
+		s.Equal("approver1@email.com", appeals[0].Approvals[0].Assignee)
+		s.Equal("approver2@email.com", appeals[1].Approvals[0].Assignee)
+		s.mockNotifier.AssertExpectations(s.T())
 	})
 
-	s.Run("should return error based on statuses conditions", func() {
+	s.Run("should apply the approver resolution fallback of the policy once the user approvers can't be resolved", func() {
+		iamError := errors.New("iam is unavailable")
 		testCases := []struct {
-			name          string
-			appealStatus  string
-			approvals     []*domain.Approval
-			expectedError error
+			name              string
+			fallback          *domain.ApproverResolutionFallback
+			expectedError     error
+			expectedStatus    string
+			expectedApprovers []string
 		}{
 			{
-				name:          "appeal not eligible, status: active",
-				appealStatus:  domain.AppealStatusActive,
-				expectedError: appeal.ErrAppealStatusApproved,
-			},
-			{
-				name:          "appeal not eligible, status: rejected",
-				appealStatus:  domain.AppealStatusRejected,
-				expectedError: appeal.ErrAppealStatusRejected,
+				name:          "without fallback",
+				expectedError: iamError,
 			},
 			{
-				name:          "appeal not eligible, status: terminated",
-				appealStatus:  domain.AppealStatusTerminated,
-				expectedError: appeal.ErrAppealStatusTerminated,
+				name:           "deferred",
+				fallback:       &domain.ApproverResolutionFallback{Strategy: domain.ApproverResolutionFallbackDefer},
+				expectedStatus: domain.ApprovalStatusPendingApproverResolution,
 			},
 			{
-				name:          "invalid appeal status",
-				appealStatus:  "invalidstatus",
-				expectedError: appeal.ErrAppealStatusUnrecognized,
+				name: "default approvers",
+				fallback: &domain.ApproverResolutionFallback{
+					Strategy:  domain.ApproverResolutionFallbackDefaultApprovers,
+					Approvers: []string{"default.approver@email.com"},
+				},
+				expectedStatus:    domain.ApprovalStatusPending,
+				expectedApprovers: []string{"default.approver@email.com"},
 			},
-			{
-				name:         "previous approval step still on pending",
-				appealStatus: domain.AppealStatusPending,
-				approvals: []*domain.Approval{
-					{
-						Name:   "approval_0",
-						Status: domain.ApprovalStatusPending,
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{{
+					ID:           1,
+					URN:          "urn",
+					Type:         "resource_type",
+					ProviderType: "provider_type",
+					ProviderURN:  "provider_urn",
+				}}, nil).Once()
+				s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+					Type: "provider_type",
+					URN:  "provider_urn",
+					Config: &domain.ProviderConfig{
+						Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+						Resources: []*domain.ResourceConfig{
+							{
+								Type:   "resource_type",
+								Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+								Roles:  []*domain.RoleConfig{{ID: "role_1"}},
+							},
+						},
 					},
+				}}, nil).Once()
+				s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{
+					ID:                         "policy_1",
+					Version:                    1,
+					Steps:                      []*domain.Step{{Name: "manager_approval", Approvers: domain.ApproversKeyUserApprovers}},
+					ApproverResolutionFallback: tc.fallback,
+				}}, nil).Once()
+				s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+				s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Once()
+				s.mockIAMService.On("GetUserApproverEmails", "user@email.com").Return(nil, iamError).Once()
+				if tc.expectedError == nil {
+					s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Once()
+					s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+				}
+				if tc.expectedApprovers != nil {
+					s.mockNotifier.On("Notify", []domain.Notification{{
+						User:    "default.approver@email.com",
+						Message: "You have an appeal from user@email.com to access urn",
+					}}).Return(nil).Once()
+				}
+				appeals := []*domain.Appeal{{ResourceID: 1, User: "user@email.com", Role: "role_1"}}
+
+				actualError := s.service.Create(context.Background(), appeals)
+
+				if tc.expectedError != nil {
+					s.ErrorIs(actualError, tc.expectedError)
+					return
+				}
+				s.Nil(actualError)
+				s.Equal(tc.expectedStatus, appeals[0].Approvals[0].Status)
+				s.Equal(tc.expectedApprovers, appeals[0].Approvals[0].Approvers)
+			})
+		}
+	})
+
+	s.Run("should return error if resource type has no policy configured and no default policy", func() {
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{{
+			ID:           1,
+			Type:         "resource_type",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+		}}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+				Resources: []*domain.ResourceConfig{
 					{
-						Name:   "approval_1",
-						Status: domain.ApprovalStatusPending,
+						Type:  "resource_type",
+						Roles: []*domain.RoleConfig{{ID: "role_1"}},
 					},
 				},
-				expectedError: appeal.ErrApprovalDependencyIsPending,
 			},
-			{
-				name:         "found one previous approval is reject",
-				appealStatus: domain.AppealStatusPending,
-				approvals: []*domain.Approval{
-					{
-						Name:   "approval_0",
-						Status: domain.ApprovalStatusRejected,
-					},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Once()
+
+		actualError := s.service.Create(context.Background(), []*domain.Appeal{{ResourceID: 1, Role: "role_1"}})
+
+		s.EqualError(actualError, appeal.ErrPolicyIDNotFound.Error())
+	})
+
+	s.Run("should not send duplicate notifications to the same approver", func() {
+		resource := &domain.Resource{
+			ID:           1,
+			URN:          "urn",
+			Type:         "resource_type",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+			Details:      map[string]interface{}{"owner": "approver@email.com"},
+		}
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{resource}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+				Resources: []*domain.ResourceConfig{
 					{
-						Name:   "approval_1",
-						Status: domain.ApprovalStatusPending,
+						Type:   "resource_type",
+						Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "role_1"}, {ID: "role_2"}},
 					},
 				},
-				expectedError: appeal.ErrAppealStatusRejected,
 			},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{ID: "policy_1", Version: 1, Steps: []*domain.Step{{Name: "step_1", Approvers: "$resource.details.owner"}}}}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Twice()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Twice()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).
+			Return(nil).Once()
+		expectedNotifications := []domain.Notification{
 			{
-				name:         "invalid approval status",
-				appealStatus: domain.AppealStatusPending,
-				approvals: []*domain.Approval{
-					{
-						Name:   "approval_0",
-						Status: "invalidstatus",
-					},
-					{
-						Name:   "approval_1",
-						Status: domain.ApprovalStatusPending,
-					},
-				},
-				expectedError: appeal.ErrApprovalStatusUnrecognized,
+				User:    "approver@email.com",
+				Message: "You have an appeal from user@email.com to access urn",
 			},
 			{
-				name:         "approval step already approved",
-				appealStatus: domain.AppealStatusPending,
-				approvals: []*domain.Approval{
-					{
-						Name:   "approval_0",
-						Status: domain.ApprovalStatusApproved,
-					},
+				User:    "user@email.com",
+				Message: "2 appeals submitted, awaiting approval from approver@email.com",
+			},
+		}
+		s.mockNotifier.On("Notify", expectedNotifications).Return(nil).Once()
+		appeals := []*domain.Appeal{
+			{ResourceID: 1, User: "user@email.com", Role: "role_1"},
+			{ResourceID: 1, User: "user@email.com", Role: "role_2"},
+		}
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.Nil(actualError)
+		s.mockNotifier.AssertExpectations(s.T())
+	})
+
+	s.Run("should keep the same notifications with different labels as they might be routed to different channels", func() {
+		resource := &domain.Resource{
+			ID:           1,
+			URN:          "urn",
+			Type:         "resource_type",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+			Details:      map[string]interface{}{"owner": "approver@email.com"},
+		}
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{resource}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+				Resources: []*domain.ResourceConfig{
 					{
-						Name:   "approval_1",
-						Status: domain.ApprovalStatusApproved,
+						Type:   "resource_type",
+						Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "role_1"}, {ID: "role_2"}},
 					},
 				},
-				expectedError: appeal.ErrApprovalStatusApproved,
 			},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{ID: "policy_1", Version: 1, Steps: []*domain.Step{{Name: "step_1", Approvers: "$resource.details.owner"}}}}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Twice()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Twice()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).
+			Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.MatchedBy(func(notifications []domain.Notification) bool {
+			approverNotifications := 0
+			for _, n := range notifications {
+				if n.User == "approver@email.com" {
+					approverNotifications++
+				}
+			}
+			return approverNotifications == 2
+		})).Return(nil).Once()
+		appeals := []*domain.Appeal{
+			{ResourceID: 1, User: "user@email.com", Role: "role_1", Labels: map[string]string{"team": "a"}},
+			{ResourceID: 1, User: "user@email.com", Role: "role_2", Labels: map[string]string{"team": "b"}},
+		}
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.Nil(actualError)
+		s.mockNotifier.AssertExpectations(s.T())
+	})
+
+	s.Run("should return error if access window is invalid", func() {
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		appeals := []*domain.Appeal{{
+			ResourceID: 1,
+			Role:       "role_1",
+			Options: &domain.AppealOptions{
+				AccessWindow: &domain.AccessWindow{Days: []string{"someday"}, StartTime: "09:00", EndTime: "17:00"},
+			},
+		}}
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.EqualError(actualError, appeal.ErrInvalidAccessWindow.Error()+`: unrecognized day "someday"`)
+	})
+
+	s.Run("should return error if start date is invalid", func() {
+		past := s.now.Add(-1 * time.Hour)
+		future := s.now.Add(24 * time.Hour)
+		testCases := []struct {
+			options       *domain.AppealOptions
+			expectedError string
+		}{
 			{
-				name:         "approval step already rejected",
-				appealStatus: domain.AppealStatusPending,
-				approvals: []*domain.Approval{
-					{
-						Name:   "approval_0",
-						Status: domain.ApprovalStatusApproved,
-					},
-					{
-						Name:   "approval_1",
-						Status: domain.ApprovalStatusRejected,
-					},
-				},
-				expectedError: appeal.ErrApprovalStatusRejected,
+				options:       &domain.AppealOptions{StartDate: &past},
+				expectedError: appeal.ErrInvalidStartDate.Error() + ": start date must be in the future",
 			},
 			{
-				name:         "approval step already skipped",
-				appealStatus: domain.AppealStatusPending,
-				approvals: []*domain.Approval{
-					{
-						Name:   "approval_0",
-						Status: domain.ApprovalStatusApproved,
-					},
+				options:       &domain.AppealOptions{StartDate: &future, ExpirationDate: &future},
+				expectedError: appeal.ErrInvalidStartDate.Error() + ": start date must be before the expiration date",
+			},
+		}
+		for _, tc := range testCases {
+			s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{}, nil).Once()
+			s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{}, nil).Once()
+			s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+			s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+			appeals := []*domain.Appeal{{
+				ResourceID: 1,
+				Role:       "role_1",
+				Options:    tc.options,
+			}}
+
+			actualError := s.service.Create(context.Background(), appeals)
+
+			s.EqualError(actualError, tc.expectedError)
+		}
+	})
+
+	s.Run("should return error if permanent access is not supported by the provider", func() {
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{{
+			ID:           1,
+			Type:         "resource_type",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+		}}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+				Resources: []*domain.ResourceConfig{
 					{
-						Name:   "approval_1",
-						Status: domain.ApprovalStatusSkipped,
+						Type:   "resource_type",
+						Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "role_1"}},
 					},
 				},
-				expectedError: appeal.ErrApprovalStatusSkipped,
 			},
-			{
-				name:         "invalid approval status",
-				appealStatus: domain.AppealStatusPending,
-				approvals: []*domain.Approval{
-					{
-						Name:   "approval_0",
-						Status: domain.ApprovalStatusApproved,
-					},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{}, nil).Once()
+
+		actualError := s.service.Create(context.Background(), []*domain.Appeal{{ResourceID: 1, Role: "role_1"}})
+
+		s.EqualError(actualError, appeal.ErrPermanentAccessNotSupported.Error())
+	})
+
+	s.Run("should use the resource specific policy over the resource type policy", func() {
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{
+			{ID: 1, URN: "sensitive_table", Type: "table", ProviderType: "provider_type", ProviderURN: "provider_urn"},
+			{ID: 2, URN: "other_table", Type: "table", ProviderType: "provider_type", ProviderURN: "provider_urn"},
+		}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+				Resources: []*domain.ResourceConfig{
 					{
-						Name:   "approval_1",
-						Status: "invalidstatus",
+						Type:   "table",
+						Policy: &domain.PolicyConfig{ID: "table_policy", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "viewer"}},
+						ResourcePolicies: []*domain.ResourcePolicyConfig{
+							{URN: "sensitive_table", Policy: &domain.PolicyConfig{ID: "strict_policy", Version: 2}},
+						},
 					},
 				},
-				expectedError: appeal.ErrApprovalStatusUnrecognized,
 			},
-			{
-				name:         "user doesn't have permission",
-				appealStatus: domain.AppealStatusPending,
-				approvals: []*domain.Approval{
-					{
-						Name:   "approval_0",
-						Status: domain.ApprovalStatusApproved,
-					},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{
+			{ID: "table_policy", Version: 1, Steps: []*domain.Step{{Name: "step_1"}}},
+			{ID: "strict_policy", Version: 2, Steps: []*domain.Step{{Name: "step_1"}, {Name: "step_2"}}},
+		}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Twice()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Twice()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+		appeals := []*domain.Appeal{
+			{ResourceID: 1, User: "user@email.com", Role: "viewer"},
+			{ResourceID: 2, User: "user@email.com", Role: "viewer"},
+		}
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.Nil(actualError)
+		s.Equal("strict_policy", appeals[0].PolicyID)
+		s.Equal(uint(2), appeals[0].PolicyVersion)
+		s.Len(appeals[0].Approvals, 2)
+		s.Equal("table_policy", appeals[1].PolicyID)
+		s.Equal(uint(1), appeals[1].PolicyVersion)
+	})
+
+	s.Run("should use the policy matching the resource tags over the resource type policy", func() {
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{
+			{ID: 1, URN: "prod_table", Type: "table", ProviderType: "provider_type", ProviderURN: "provider_urn", Tags: map[string]string{"environment": "production", "team": "data"}},
+			{ID: 2, URN: "staging_table", Type: "table", ProviderType: "provider_type", ProviderURN: "provider_urn", Tags: map[string]string{"environment": "staging"}},
+		}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+				Resources: []*domain.ResourceConfig{
 					{
-						Name:      "approval_1",
-						Status:    domain.ApprovalStatusPending,
-						Approvers: []string{"another.user@email.com"},
+						Type:   "table",
+						Policy: &domain.PolicyConfig{ID: "table_policy", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "viewer"}},
+						TagPolicies: []*domain.TagPolicyConfig{
+							{Tags: map[string]string{"environment": "production"}, Policy: &domain.PolicyConfig{ID: "production_policy", Version: 1}},
+						},
 					},
 				},
-				expectedError: appeal.ErrActionForbidden,
 			},
-			{
-				name:         "approval step not found",
-				appealStatus: domain.AppealStatusPending,
-				approvals: []*domain.Approval{
-					{
-						Name:   "approval_0",
-						Status: domain.ApprovalStatusApproved,
-					},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{
+			{ID: "table_policy", Version: 1, Steps: []*domain.Step{{Name: "step_1"}}},
+			{ID: "production_policy", Version: 1, Steps: []*domain.Step{{Name: "step_1"}, {Name: "step_2"}}},
+		}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Twice()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Twice()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+		appeals := []*domain.Appeal{
+			{ResourceID: 1, User: "user@email.com", Role: "viewer"},
+			{ResourceID: 2, User: "user@email.com", Role: "viewer"},
+		}
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.Nil(actualError)
+		s.Equal("production_policy", appeals[0].PolicyID)
+		s.Len(appeals[0].Approvals, 2)
+		s.Equal("table_policy", appeals[1].PolicyID)
+	})
+
+	s.Run("should skip the approval steps not required for the requested duration", func() {
+		timeNow := time.Now()
+		s.service.TimeNow = func() time.Time {
+			return timeNow
+		}
+		shortExpirationDate := timeNow.Add(time.Hour)
+		longExpirationDate := timeNow.Add(7 * 24 * time.Hour)
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{
+			{ID: 1, URN: "urn", Type: "resource_type", ProviderType: "provider_type", ProviderURN: "provider_urn", Details: map[string]interface{}{"owner": "owner@email.com"}},
+		}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+				Resources: []*domain.ResourceConfig{
 					{
-						Name:   "approval_x",
-						Status: domain.ApprovalStatusApproved,
+						Type:   "resource_type",
+						Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "role_1"}, {ID: "role_2"}, {ID: "role_3"}},
 					},
 				},
-				expectedError: appeal.ErrApprovalNameNotFound,
 			},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{ID: "policy_1", Version: 1, Steps: []*domain.Step{
+			{Name: "owner_approval", Approvers: "$resource.details.owner"},
+			{Name: "security_approval", Approvers: "$resource.details.owner", MinDuration: "24h"},
+		}}}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockProviderService.On("GetCapabilities", "provider_type").Return(&domain.ProviderCapabilities{PermanentAccess: true}, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Times(3)
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+		appeals := []*domain.Appeal{
+			{ResourceID: 1, User: "user@email.com", Role: "role_1", Options: &domain.AppealOptions{ExpirationDate: &shortExpirationDate}},
+			{ResourceID: 1, User: "user@email.com", Role: "role_2", Options: &domain.AppealOptions{ExpirationDate: &longExpirationDate}},
+			{ResourceID: 1, User: "user@email.com", Role: "role_3"},
 		}
 
-		for _, tc := range testCases {
-			expectedAppeal := &domain.Appeal{
-				ID:        validApprovalActionParam.AppealID,
-				Status:    tc.appealStatus,
-				Approvals: tc.approvals,
-			}
-			s.mockRepository.On("GetByID", validApprovalActionParam.AppealID).Return(expectedAppeal, nil).Once()
-
-			actualResult, actualError := s.service.MakeAction(validApprovalActionParam)
+		actualError := s.service.Create(context.Background(), appeals)
 
-			s.Nil(actualResult)
-			s.EqualError(actualError, tc.expectedError.Error())
-		}
+		s.Nil(actualError)
+		s.Equal(domain.ApprovalStatusSkipped, appeals[0].Approvals[1].Status)
+		s.Empty(appeals[0].Approvals[1].Approvers)
+		s.Equal(domain.ApprovalStatusPending, appeals[1].Approvals[1].Status)
+		s.Equal(domain.ApprovalStatusPending, appeals[2].Approvals[1].Status)
 	})
 
-	s.Run("should return error if got any from repository while updating appeal", func() {
-		expectedAppeal := &domain.Appeal{
-			ID:     validApprovalActionParam.AppealID,
-			Status: domain.AppealStatusPending,
-			Approvals: []*domain.Approval{
-				{
-					Name:   "approval_0",
-					Status: domain.ApprovalStatusApproved,
-				},
-				{
-					Name:      "approval_1",
-					Status:    domain.ApprovalStatusPending,
-					Approvers: []string{"user@email.com"},
+	s.Run("should return error if a resolved approver's email domain is not allowed", func() {
+		service := appeal.NewService(
+			s.mockRepository,
+			s.mockApprovalService,
+			s.mockResourceService,
+			s.mockProviderService,
+			s.mockPolicyService,
+			s.mockIAMService,
+			s.mockNotifier,
+			s.mockBlobStorage,
+			zap.NewNop(),
+			&appeal.Config{ApproverAllowedDomains: []string{"company.com"}},
+		)
+		expDate := time.Now().Add(24 * time.Hour)
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{{
+			ID:           1,
+			Type:         "resource_type",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+			Details: map[string]interface{}{
+				"owners": []interface{}{"owner@company.com", "owner@gmial.com"},
+			},
+		}}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{
+					{
+						Type:   "resource_type",
+						Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "role_1"}},
+					},
 				},
 			},
-		}
-		s.mockRepository.On("GetByID", validApprovalActionParam.AppealID).Return(expectedAppeal, nil).Once()
-		expectedError := errors.New("repository error")
-		s.mockApprovalService.On("AdvanceApproval", expectedAppeal).Return(nil).Once()
-		s.mockProviderService.On("GrantAccess", expectedAppeal).Return(nil).Once()
-		s.mockRepository.On("Update", mock.Anything).Return(expectedError).Once()
-		s.mockProviderService.On("RevokeAccess", expectedAppeal).Return(nil).Once()
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{
+			ID:      "policy_1",
+			Version: 1,
+			Steps:   []*domain.Step{{Name: "step_1", Approvers: "$resource.details.owners"}},
+		}}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
 
-		actualResult, actualError := s.service.MakeAction(validApprovalActionParam)
+		actualError := service.Create(context.Background(), []*domain.Appeal{{
+			ResourceID: 1,
+			User:       "user@company.com",
+			Role:       "role_1",
+			Options:    &domain.AppealOptions{ExpirationDate: &expDate},
+		}})
 
-		s.Nil(actualResult)
-		s.EqualError(actualError, expectedError.Error())
+		s.True(errors.Is(actualError, appeal.ErrApproverDomainNotAllowed))
+		s.Contains(actualError.Error(), "owner@gmial.com")
 	})
 
-	s.Run("should return updated appeal on success", func() {
-		user := "user@email.com"
-		testCases := []struct {
-			name                   string
-			expectedApprovalAction domain.ApprovalAction
-			expectedAppealDetails  *domain.Appeal
-			expectedResult         *domain.Appeal
-			expectedNotifications  []domain.Notification
-		}{
-			{
-				name:                   "approve",
-				expectedApprovalAction: validApprovalActionParam,
-				expectedAppealDetails: &domain.Appeal{
-					ID:         validApprovalActionParam.AppealID,
-					User:       "user@email.com",
-					ResourceID: 1,
-					Resource: &domain.Resource{
-						ID:  1,
-						URN: "urn",
-					},
-					Status: domain.AppealStatusPending,
-					Approvals: []*domain.Approval{
-						{
-							Name:   "approval_0",
-							Status: domain.ApprovalStatusApproved,
-						},
-						{
-							Name:      "approval_1",
-							Status:    domain.ApprovalStatusPending,
-							Approvers: []string{"user@email.com"},
-						},
-					},
-				},
-				expectedResult: &domain.Appeal{
-					ID:         validApprovalActionParam.AppealID,
-					User:       "user@email.com",
-					ResourceID: 1,
-					Resource: &domain.Resource{
-						ID:  1,
-						URN: "urn",
-					},
-					Status: domain.AppealStatusActive,
-					Approvals: []*domain.Approval{
-						{
-							Name:   "approval_0",
-							Status: domain.ApprovalStatusApproved,
-						},
-						{
-							Name:      "approval_1",
-							Status:    domain.ApprovalStatusApproved,
-							Approvers: []string{"user@email.com"},
-							Actor:     &user,
-							UpdatedAt: timeNow,
-						},
-					},
-				},
-				expectedNotifications: []domain.Notification{
+	s.Run("should attach the warnings of the unusual appeals", func() {
+		service := appeal.NewService(
+			s.mockRepository,
+			s.mockApprovalService,
+			s.mockResourceService,
+			s.mockProviderService,
+			s.mockPolicyService,
+			s.mockIAMService,
+			s.mockNotifier,
+			s.mockBlobStorage,
+			zap.NewNop(),
+			&appeal.Config{Warnings: appeal.WarningsConfig{
+				PrivilegedRoles:       []string{"owner"},
+				UnusualDurationFactor: 2,
+			}},
+		)
+		timeNow := time.Now()
+		service.TimeNow = func() time.Time {
+			return timeNow
+		}
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{{
+			ID:           1,
+			Type:         "resource_type",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+		}}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{
 					{
-						User:    "user@email.com",
-						Message: "Your appeal to urn has been approved",
+						Type:   "resource_type",
+						Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "viewer"}, {ID: "owner"}},
 					},
 				},
 			},
-			{
-				name: "reject",
-				expectedApprovalAction: domain.ApprovalAction{
-					AppealID:     1,
-					ApprovalName: "approval_1",
-					Actor:        "user@email.com",
-					Action:       domain.AppealActionNameReject,
-				},
-				expectedAppealDetails: &domain.Appeal{
-					ID:         validApprovalActionParam.AppealID,
-					User:       "user@email.com",
-					ResourceID: 1,
-					Resource: &domain.Resource{
-						ID:  1,
-						URN: "urn",
-					},
-					Status: domain.AppealStatusPending,
-					Approvals: []*domain.Approval{
-						{
-							Name:   "approval_0",
-							Status: domain.ApprovalStatusApproved,
-						},
-						{
-							Name:      "approval_1",
-							Status:    domain.ApprovalStatusPending,
-							Approvers: []string{"user@email.com"},
-						},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{
+			ID:      "policy_1",
+			Version: 1,
+			Steps:   []*domain.Step{{Name: "step_1"}},
+		}}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, map[string]interface{}{
+			"statuses": []string{domain.AppealStatusPending},
+		}).Return([]*domain.Appeal{}, nil).Once()
+		s.mockRepository.On("GetHistory", mock.Anything, "user@email.com", "owner").Return(&domain.AppealHistory{
+			LongestRequestedDuration: 24 * time.Hour,
+		}, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+		expDate := timeNow.Add(7 * 24 * time.Hour)
+		appeals := []*domain.Appeal{{
+			ResourceID: 1,
+			User:       "user@email.com",
+			Role:       "owner",
+			Options:    &domain.AppealOptions{ExpirationDate: &expDate},
+		}}
+
+		actualError := service.Create(context.Background(), appeals)
+
+		s.Nil(actualError)
+		s.Require().Len(appeals[0].Warnings, 2)
+		s.Equal(appeal.WarningCodeFirstPrivilegedAccess, appeals[0].Warnings[0].Code)
+		s.Equal(appeal.WarningCodeUnusualDuration, appeals[0].Warnings[1].Code)
+	})
+
+	s.Run("should approve and grant the appeal based on the policy decision", func() {
+		evaluator, err := appeal.NewRegoEvaluator(context.Background(), testRegoPolicy, "data.guardian.appeal.decision")
+		s.Require().Nil(err)
+		s.service.DecisionEvaluator = evaluator
+		defer func() { s.service.DecisionEvaluator = nil }()
+
+		resource := &domain.Resource{
+			ID:           1,
+			URN:          "urn",
+			Type:         "resource_type",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+			Details:      map[string]interface{}{"owner": "approver@email.com"},
+		}
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{resource}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{
+					{
+						Type:   "resource_type",
+						Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "viewer"}},
 					},
 				},
-				expectedResult: &domain.Appeal{
-					ID:         validApprovalActionParam.AppealID,
-					User:       "user@email.com",
-					ResourceID: 1,
-					Resource: &domain.Resource{
-						ID:  1,
-						URN: "urn",
-					},
-					Status: domain.AppealStatusRejected,
-					Approvals: []*domain.Approval{
-						{
-							Name:   "approval_0",
-							Status: domain.ApprovalStatusApproved,
-						},
-						{
-							Name:      "approval_1",
-							Status:    domain.ApprovalStatusRejected,
-							Approvers: []string{"user@email.com"},
-							Actor:     &user,
-							UpdatedAt: timeNow,
-						},
+			},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{
+			ID:      "policy_1",
+			Version: 1,
+			Steps: []*domain.Step{
+				{Name: "step_1", Approvers: "$resource.details.owner"},
+			},
+		}}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockProviderService.On("GrantAccess", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "user@email.com",
+			Message: "Your appeal to urn has been approved",
+		}}).Return(nil).Once()
+		expDate := s.now.Add(12 * time.Hour)
+		appeals := []*domain.Appeal{{
+			ResourceID: 1,
+			User:       "user@email.com",
+			Role:       "viewer",
+			Options:    &domain.AppealOptions{ExpirationDate: &expDate},
+		}}
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusActive, appeals[0].Status)
+		s.Equal(domain.ApprovalStatusApproved, appeals[0].Approvals[0].Status)
+		s.Equal(domain.SystemActorName, *appeals[0].Approvals[0].Actor)
+		s.Equal("short viewer access", appeals[0].Approvals[0].Reason)
+		s.mockProviderService.AssertCalled(s.T(), "GrantAccess", mock.Anything, appeals[0])
+	})
+
+	s.Run("should match the resource type against the configured aliases", func() {
+		expDate := time.Now().Add(24 * time.Hour)
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{{
+			ID:           1,
+			Type:         "bq_table",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+		}}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{
+					{
+						Type:    "dataset",
+						Aliases: []string{"bq_dataset"},
+						Policy:  &domain.PolicyConfig{ID: "policy_1", Version: 1},
+						Roles:   []*domain.RoleConfig{{ID: "viewer"}},
 					},
-				},
-				expectedNotifications: []domain.Notification{
 					{
-						User:    "user@email.com",
-						Message: "Your appeal to urn is rejected",
+						Type:    "table",
+						Aliases: []string{"bq_table"},
+						Policy:  &domain.PolicyConfig{ID: "policy_2", Version: 1},
+						Roles:   []*domain.RoleConfig{{ID: "viewer"}},
 					},
 				},
 			},
-			{
-				name: "reject in the middle step",
-				expectedApprovalAction: domain.ApprovalAction{
-					AppealID:     1,
-					ApprovalName: "approval_1",
-					Actor:        user,
-					Action:       domain.AppealActionNameReject,
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{
+			{ID: "policy_1", Version: 1, Steps: []*domain.Step{{Name: "step_1"}}},
+			{ID: "policy_2", Version: 1, Steps: []*domain.Step{{Name: "step_1"}}},
+		}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+		appeals := []*domain.Appeal{{
+			ResourceID: 1,
+			User:       "user@email.com",
+			Role:       "viewer",
+			Options:    &domain.AppealOptions{ExpirationDate: &expDate},
+		}}
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.Nil(actualError)
+		s.Equal("policy_2", appeals[0].PolicyID)
+	})
+
+	s.Run("should list the resource types and aliases if none matches", func() {
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{{
+			ID:           1,
+			Type:         "view",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+		}}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{
+					{Type: "dataset", Aliases: []string{"bq_dataset", "schema"}},
+					{Type: "table"},
 				},
-				expectedAppealDetails: &domain.Appeal{
-					ID:         validApprovalActionParam.AppealID,
-					User:       "user@email.com",
+			},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+
+		actualError := s.service.Create(context.Background(), []*domain.Appeal{{ResourceID: 1}})
+
+		s.True(errors.Is(actualError, appeal.ErrResourceTypeNotFound))
+		s.EqualError(actualError, `unable to find matching resource config for specified resource type: "view", available resource types: dataset (aliases: bq_dataset, schema), table`)
+	})
+}
+
+type stepCheckerFunc func(ctx context.Context, check *domain.StepCheck, input *appeal.StepCheckInput) (bool, error)
+
+func (f stepCheckerFunc) Check(ctx context.Context, check *domain.StepCheck, input *appeal.StepCheckInput) (bool, error) {
+	return f(ctx, check, input)
+}
+
+func (s *ServiceTestSuite) TestCreateWithRateLimit() {
+	service := appeal.NewService(
+		s.mockRepository,
+		s.mockApprovalService,
+		s.mockResourceService,
+		s.mockProviderService,
+		s.mockPolicyService,
+		s.mockIAMService,
+		s.mockNotifier,
+		s.mockBlobStorage,
+		zap.NewNop(),
+		&appeal.Config{RateLimit: appeal.RateLimitConfig{MaxAppeals: 3, Window: time.Hour}},
+	)
+	service.TimeNow = func() time.Time {
+		return s.now
+	}
+	user := "user@email.com"
+	since := s.now.Add(-time.Hour)
+	createdTimes := []time.Time{s.now.Add(-50 * time.Minute), s.now.Add(-10 * time.Minute)}
+	newAppeals := func(count int) []*domain.Appeal {
+		appeals := []*domain.Appeal{}
+		for i := 0; i < count; i++ {
+			appeals = append(appeals, &domain.Appeal{ResourceID: uint(i + 1), User: user, Role: "role_1"})
+		}
+		return appeals
+	}
+
+	s.Run("should return error if the user exceeds the rate limit", func() {
+		testCases := []struct {
+			name               string
+			count              int
+			expectedRetryAfter time.Duration
+		}{
+			{
+				name:               "until the oldest appeal leaves the window",
+				count:              2,
+				expectedRetryAfter: 10 * time.Minute,
+			},
+			{
+				name:               "until the excess appeals leave the window",
+				count:              3,
+				expectedRetryAfter: 50 * time.Minute,
+			},
+			{
+				name:               "batch larger than the limit",
+				count:              4,
+				expectedRetryAfter: time.Hour,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockRepository.On("GetCreatedTimes", mock.Anything, user, since).Return(createdTimes, nil).Once()
+
+				actualError := service.Create(context.Background(), newAppeals(tc.count))
+
+				s.ErrorIs(actualError, appeal.ErrAppealRateLimited)
+				var rateLimitErr *appeal.RateLimitError
+				s.Require().True(errors.As(actualError, &rateLimitErr))
+				s.Equal(user, rateLimitErr.User)
+				s.Equal(tc.expectedRetryAfter, rateLimitErr.RetryAfter)
+			})
+		}
+	})
+
+	s.Run("should create the appeals within the rate limit", func() {
+		expectedError := errors.New("resources error")
+		s.mockRepository.On("GetCreatedTimes", mock.Anything, user, since).Return(createdTimes, nil).Once()
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualError := service.Create(context.Background(), newAppeals(1))
+
+		s.ErrorIs(actualError, expectedError)
+	})
+}
+
+func (s *ServiceTestSuite) TestCreateWithResourceIdentifier() {
+	identifier := &domain.Resource{ProviderType: "provider_type", ProviderURN: "provider_urn", URN: "urn"}
+
+	s.Run("should return error if the resource identifier is incomplete", func() {
+		for _, r := range []*domain.Resource{nil, {ProviderType: "provider_type", URN: "urn"}} {
+			actualError := s.service.Create(context.Background(), []*domain.Appeal{{User: "user@email.com", Role: "viewer", Resource: r}})
+
+			s.ErrorIs(actualError, appeal.ErrInvalidResourceIdentifier)
+		}
+	})
+
+	s.Run("should return error if the resource is not found", func() {
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{"urns": []string{"urn"}}).Return([]*domain.Resource{
+			{ID: 1, ProviderType: "provider_type", ProviderURN: "another_provider_urn", URN: "urn"},
+		}, nil).Once()
+
+		actualError := s.service.Create(context.Background(), []*domain.Appeal{{User: "user@email.com", Role: "viewer", Resource: identifier}})
+
+		s.ErrorIs(actualError, appeal.ErrResourceNotFound)
+		var unresolvedErr *appeal.UnresolvedResourceError
+		s.Require().True(errors.As(actualError, &unresolvedErr))
+		s.Equal("provider_urn", unresolvedErr.ProviderURN)
+	})
+
+	s.Run("should resolve the resource id and keep the appeals having the id as is", func() {
+		appeals := []*domain.Appeal{
+			{User: "user@email.com", Role: "viewer", Resource: identifier},
+			{User: "user@email.com", Role: "viewer", ResourceID: 3},
+		}
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{"urns": []string{"urn"}}).Return([]*domain.Resource{
+			{ID: 1, ProviderType: "provider_type", ProviderURN: "another_provider_urn", URN: "urn"},
+			{ID: 2, ProviderType: "provider_type", ProviderURN: "provider_urn", URN: "urn"},
+		}, nil).Once()
+		expectedError := errors.New("resources error")
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{"ids": []uint{2, 3}}).Return(nil, expectedError).Once()
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.ErrorIs(actualError, expectedError)
+		s.Equal(uint(2), appeals[0].ResourceID)
+	})
+
+	s.Run("should only resolve the resources of the organization", func() {
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{
+			"organization_id": "org-a",
+			"urns":            []string{"urn"},
+		}).Return([]*domain.Resource{}, nil).Once()
+
+		actualError := s.service.Create(ctx, []*domain.Appeal{{User: "user@email.com", Role: "viewer", Resource: identifier}})
+
+		s.ErrorIs(actualError, appeal.ErrResourceNotFound)
+	})
+}
+
+func (s *ServiceTestSuite) TestNormalizeResourceURN() {
+	service := appeal.NewService(
+		s.mockRepository,
+		s.mockApprovalService,
+		s.mockResourceService,
+		s.mockProviderService,
+		s.mockPolicyService,
+		s.mockIAMService,
+		s.mockNotifier,
+		s.mockBlobStorage,
+		zap.NewNop(),
+		&appeal.Config{NormalizeResourceURN: true},
+	)
+	service.TimeNow = func() time.Time {
+		return s.now
+	}
+	user := "user@email.com"
+	// the dataset is re-imported under another id with a different representation of the same urn
+	reimported := &domain.Resource{ID: 2, ProviderType: "bigquery", ProviderURN: "provider_urn", Type: "dataset", URN: " project:dataset"}
+	original := &domain.Resource{ID: 1, ProviderType: "bigquery", ProviderURN: "provider_urn", Type: "dataset", URN: "project:dataset"}
+	another := &domain.Resource{ID: 3, ProviderType: "bigquery", ProviderURN: "provider_urn", Type: "dataset", URN: "project:another_dataset"}
+	mockURNFormat := func() {
+		parsedURNs := map[string]*domain.ResourceURN{
+			" project:dataset":        {Type: "dataset", Parts: []string{"project", "dataset"}},
+			"project:dataset":         {Type: "dataset", Parts: []string{"project", "dataset"}},
+			"project:another_dataset": {Type: "dataset", Parts: []string{"project", "another_dataset"}},
+		}
+		for urn, parsed := range parsedURNs {
+			s.mockProviderService.On("ParseURN", "bigquery", "dataset", urn).Return(parsed, nil).Maybe()
+			s.mockProviderService.On("FormatURN", "bigquery", parsed).Return(strings.Join(parsed.Parts, ":"), nil).Maybe()
+		}
+	}
+
+	s.Run("should find the active access to the resource re-imported under another id", func() {
+		mockURNFormat()
+		expectedAppeal := &domain.Appeal{ID: 1, User: user, ResourceID: original.ID, Role: "viewer", Status: domain.AppealStatusActive}
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{"ids": []uint{reimported.ID}}).Return([]*domain.Resource{reimported}, nil).Once()
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{
+			"type_keys": []domain.ResourceTypeKey{{ProviderType: "bigquery", ProviderURN: "provider_urn", Type: "dataset"}},
+		}).Return([]*domain.Resource{original, reimported, another}, nil).Once()
+		s.mockRepository.On("GetActiveAccess", mock.Anything, user, reimported.ID, "viewer", s.now).Return(nil, nil).Once()
+		s.mockRepository.On("GetActiveAccess", mock.Anything, user, original.ID, "viewer", s.now).Return(expectedAppeal, nil).Once()
+
+		actualHasAccess, actualAppeal, actualError := service.HasActiveAccess(context.Background(), user, reimported.ID, "viewer")
+
+		s.Nil(actualError)
+		s.True(actualHasAccess)
+		s.Equal(expectedAppeal, actualAppeal)
+	})
+
+	s.Run("should return error if the resource is not found", func() {
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{"ids": []uint{4}}).Return([]*domain.Resource{}, nil).Once()
+
+		_, _, actualError := service.HasActiveAccess(context.Background(), user, 4, "viewer")
+
+		s.ErrorIs(actualError, appeal.ErrResourceNotFound)
+	})
+
+	s.Run("should reject the appeal duplicating a pending appeal to the resource re-imported under another id", func() {
+		mockURNFormat()
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{"ids": []uint{reimported.ID}}).Return([]*domain.Resource{reimported}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, map[string]interface{}{
+			"statuses": []string{domain.AppealStatusPending},
+		}).Return([]*domain.Appeal{{ID: 1, User: user, ResourceID: original.ID, Role: "viewer", Status: domain.AppealStatusPending}}, nil).Once()
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{"ids": []uint{original.ID}}).Return([]*domain.Resource{original}, nil).Once()
+
+		actualError := service.Create(context.Background(), []*domain.Appeal{{User: user, ResourceID: reimported.ID, Role: "viewer"}})
+
+		s.ErrorIs(actualError, appeal.ErrAppealDuplicate)
+	})
+}
+
+func (s *ServiceTestSuite) TestCreateWithSensitivityTier() {
+	setup := func(role string, tiers []*domain.SensitivityTier) []*domain.Appeal {
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{{
+			ID:           1,
+			URN:          "urn",
+			Type:         "resource_type",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+			Details:      map[string]interface{}{"owner": "owner@email.com", "vp": "vp@email.com"},
+		}}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{{
+					Type:   "resource_type",
+					Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+					Roles:  []*domain.RoleConfig{{ID: "viewer"}, {ID: "owner", Sensitivity: "high"}},
+				}},
+			},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{
+			ID:               "policy_1",
+			Version:          1,
+			Steps:            []*domain.Step{{Name: "owner_approval", Approvers: "$resource.details.owner"}},
+			SensitivityTiers: tiers,
+		}}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		expDate := s.now.Add(24 * time.Hour)
+		return []*domain.Appeal{{
+			ResourceID: 1,
+			User:       "user@email.com",
+			Role:       role,
+			Options:    &domain.AppealOptions{ExpirationDate: &expDate},
+		}}
+	}
+	tiers := []*domain.SensitivityTier{{
+		Sensitivity: "high",
+		Steps:       []*domain.Step{{Name: "vp_approval", Approvers: "$resource.details.vp"}},
+	}}
+
+	s.Run("should append the approval steps of the role sensitivity tier", func() {
+		appeals := setup("owner", tiers)
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.Nil(actualError)
+		s.Require().Len(appeals[0].Approvals, 2)
+		s.Equal("owner_approval", appeals[0].Approvals[0].Name)
+		s.Equal("vp_approval", appeals[0].Approvals[1].Name)
+		s.Equal(1, appeals[0].Approvals[1].Index)
+		s.Equal([]string{"vp@email.com"}, appeals[0].Approvals[1].Approvers)
+	})
+
+	s.Run("should only require the policy steps for the roles without sensitivity", func() {
+		appeals := setup("viewer", tiers)
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.Nil(actualError)
+		s.Len(appeals[0].Approvals, 1)
+	})
+
+	s.Run("should return error if the policy has no tier for the role sensitivity", func() {
+		appeals := setup("owner", nil)
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.ErrorIs(actualError, appeal.ErrSensitivityTierNotFound)
+	})
+}
+
+func (s *ServiceTestSuite) TestCreateWithStepCheck() {
+	check := &domain.StepCheck{URL: "https://lms.example.com/training"}
+	setup := func(passed bool, checkErr error) []*domain.Appeal {
+		s.service.StepChecker = stepCheckerFunc(func(ctx context.Context, c *domain.StepCheck, input *appeal.StepCheckInput) (bool, error) {
+			s.Equal(check, c)
+			s.Equal("training", input.Step)
+			s.Equal("user@email.com", input.Appeal.User)
+			return passed, checkErr
+		})
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{{
+			ID:           1,
+			URN:          "urn",
+			Type:         "resource_type",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+			Details:      map[string]interface{}{"owner": "approver@email.com"},
+		}}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{{
+					Type:   "resource_type",
+					Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+					Roles:  []*domain.RoleConfig{{ID: "viewer"}},
+				}},
+			},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{
+			ID:      "policy_1",
+			Version: 1,
+			Steps: []*domain.Step{
+				{Name: "training", Approvers: "$resource.details.owner", Check: check},
+			},
+		}}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+		expDate := s.now.Add(24 * time.Hour)
+		return []*domain.Appeal{{
+			ResourceID: 1,
+			User:       "user@email.com",
+			Role:       "viewer",
+			Options:    &domain.AppealOptions{ExpirationDate: &expDate},
+		}}
+	}
+
+	s.Run("should auto-approve the step and grant the access if the check passes", func() {
+		appeals := setup(true, nil)
+		s.mockProviderService.On("GrantAccess", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "user@email.com",
+			Message: "Your appeal to urn has been approved",
+		}}).Return(nil).Once()
+
+		actualError := s.service.Create(context.Background(), appeals)
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusActive, appeals[0].Status)
+		s.Equal(domain.ApprovalStatusApproved, appeals[0].Approvals[0].Status)
+		s.Equal(domain.SystemActorName, *appeals[0].Approvals[0].Actor)
+		s.Equal("passed the external check", appeals[0].Approvals[0].Reason)
+	})
+
+	s.Run("should route the step to the approvers if the check fails", func() {
+		for _, checkErr := range []error{nil, errors.New("lms unavailable")} {
+			appeals := setup(false, checkErr)
+			s.mockNotifier.On("Notify", []domain.Notification{{
+				User:    "approver@email.com",
+				Message: "You have an appeal from user@email.com to access urn",
+			}}).Return(nil).Once()
+
+			actualError := s.service.Create(context.Background(), appeals)
+
+			s.Nil(actualError)
+			s.Equal(domain.AppealStatusPending, appeals[0].Status)
+			s.Equal(domain.ApprovalStatusPending, appeals[0].Approvals[0].Status)
+			s.Equal([]string{"approver@email.com"}, appeals[0].Approvals[0].Approvers)
+		}
+	})
+}
+
+func (s *ServiceTestSuite) TestGetAppealableResources() {
+	providers := []*domain.Provider{
+		{
+			Type: "google_bigquery",
+			URN:  "project",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{
+					{
+						Type:    "dataset",
+						Aliases: []string{"bq_dataset"},
+						Roles:   []*domain.RoleConfig{{ID: "viewer"}, {ID: "owner", AllowedGroups: []string{"data-platform"}}},
+					},
+					{
+						Type:  "table",
+						Roles: []*domain.RoleConfig{{ID: "admin", AllowedGroups: []string{"sre"}}},
+					},
+				},
+			},
+		},
+		{
+			Type: "metabase",
+			URN:  "metabase",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{
+					{
+						Type:  "collection",
+						Roles: []*domain.RoleConfig{{ID: "editor", AllowedGroups: []string{"analysts", "data-platform"}}},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run("should return error if the user is invalid", func() {
+		actualResources, actualError := s.service.GetAppealableResources(context.Background(), "user", nil)
+
+		s.Nil(actualResources)
+		s.ErrorIs(actualError, appeal.ErrInvalidUser)
+	})
+
+	s.Run("should return error if the groups of the user can't be resolved", func() {
+		s.mockProviderService.On("Find", mock.Anything).Return(providers, nil).Once()
+		s.mockIAMService.On("GetUserGroups", "user@email.com").Return(nil, iam.ErrGroupsNotSupported).Once()
+
+		_, actualError := s.service.GetAppealableResources(context.Background(), "user@email.com", nil)
+
+		s.ErrorIs(actualError, iam.ErrGroupsNotSupported)
+	})
+
+	s.Run("should only find the appealable resources of the resource types the user is eligible for", func() {
+		expectedResources := []*domain.Resource{{ID: 1}}
+		s.mockProviderService.On("Find", mock.Anything).Return(providers, nil).Once()
+		s.mockIAMService.On("GetUserGroups", "user@email.com").Return([]string{"analysts"}, nil).Once()
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{
+			"tags": map[string]string{"environment": "production"},
+			"type_keys": []domain.ResourceTypeKey{
+				{ProviderType: "google_bigquery", ProviderURN: "project", Type: "bq_dataset"},
+				{ProviderType: "google_bigquery", ProviderURN: "project", Type: "dataset"},
+				{ProviderType: "metabase", ProviderURN: "metabase", Type: "collection"},
+			},
+			"deny_appeals": false,
+			"limit":        100,
+			"offset":       200,
+		}).Return(expectedResources, nil).Once()
+
+		actualResources, actualError := s.service.GetAppealableResources(context.Background(), "user@email.com", map[string]interface{}{
+			"tags":   map[string]string{"environment": "production"},
+			"offset": 200,
+		})
+
+		s.Nil(actualError)
+		s.Equal(expectedResources, actualResources)
+	})
+
+	s.Run("should return empty list without querying the resources if the user is not eligible for any", func() {
+		s.mockProviderService.On("Find", mock.Anything).Return(providers[1:], nil).Once()
+		s.mockIAMService.On("GetUserGroups", "user@email.com").Return([]string{}, nil).Once()
+		previousCalls := len(s.mockResourceService.Calls)
+
+		actualResources, actualError := s.service.GetAppealableResources(context.Background(), "user@email.com", nil)
+
+		s.Nil(actualError)
+		s.Empty(actualResources)
+		s.Len(s.mockResourceService.Calls, previousCalls)
+	})
+}
+
+func (s *ServiceTestSuite) TestResubmit() {
+	user := "user@email.com"
+	comment := "linked the incident ticket"
+	newAppeal := func(changesRequestedAt *time.Time) *domain.Appeal {
+		return &domain.Appeal{
+			ID:       1,
+			User:     user,
+			Status:   domain.AppealStatusPending,
+			Resource: &domain.Resource{URN: "urn"},
+			Approvals: []*domain.Approval{
+				{
+					Name:               "approval_1",
+					Status:             domain.ApprovalStatusPending,
+					Approvers:          []string{"approver@email.com"},
+					ChangesRequestedAt: changesRequestedAt,
+				},
+			},
+		}
+	}
+
+	s.Run("should return error if comment is empty", func() {
+		actualResult, actualError := s.service.Resubmit(context.Background(), 1, user, " ")
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrResubmitCommentRequired)
+	})
+
+	s.Run("should return error if user doesn't match the authenticated user", func() {
+		ctx := auth.NewContext(context.Background(), "spoofed@email.com")
+		actualResult, actualError := s.service.Resubmit(ctx, 1, user, comment)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrActorMismatch)
+		s.mockRepository.AssertNotCalled(s.T(), "GetByID", mock.Anything, uint(1))
+	})
+
+	s.Run("should return error if user is not the requester", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(newAppeal(&time.Time{}), nil).Once()
+
+		actualResult, actualError := s.service.Resubmit(context.Background(), 1, "other@email.com", comment)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrResubmitForbidden)
+	})
+
+	s.Run("should return error if no changes are requested on the appeal", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(newAppeal(nil), nil).Once()
+
+		actualResult, actualError := s.service.Resubmit(context.Background(), 1, user, comment)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrChangesNotRequested)
+	})
+
+	s.Run("should clear the requested changes and notify the approvers", func() {
+		appealDetails := newAppeal(&time.Time{})
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("AddComment", mock.Anything, &domain.AppealComment{
+			AppealID:   1,
+			CreatedBy:  user,
+			Body:       `resubmitted on step "approval_1": linked the incident ticket`,
+			Visibility: domain.CommentVisibilityPublic,
+		}).Return(nil).Once()
+		s.expectOutboxMessage()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "approver@email.com",
+			Message: "The appeal from user@email.com to access urn is resubmitted after the changes you requested: linked the incident ticket",
+		}}).Return(nil).Once()
+
+		actualResult, actualError := s.service.Resubmit(context.Background(), 1, user, comment)
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusPending, actualResult.Status)
+		s.Nil(actualResult.Approvals[0].ChangesRequestedAt)
+		s.mockNotifier.AssertExpectations(s.T())
+	})
+}
+
+func (s *ServiceTestSuite) TestClone() {
+	timeNow := time.Now()
+	s.service.TimeNow = func() time.Time {
+		return timeNow
+	}
+
+	s.Run("should return error if user is not a valid email", func() {
+		actualResult, actualError := s.service.Clone(context.Background(), 1, "invalid")
+
+		s.Nil(actualResult)
+		s.True(errors.Is(actualError, appeal.ErrInvalidUser))
+	})
+
+	s.Run("should return error if user doesn't match the authenticated user", func() {
+		ctx := auth.NewContext(context.Background(), "user@email.com")
+		actualResult, actualError := s.service.Clone(ctx, 1, "spoofed@email.com")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrActorMismatch.Error())
+		s.mockRepository.AssertNotCalled(s.T(), "GetByID", mock.Anything, uint(1))
+	})
+
+	s.Run("should return error if got any from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(nil, expectedError).Once()
+
+		actualResult, actualError := s.service.Clone(context.Background(), 1, "user@email.com")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return error if appeal is not found", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(nil, nil).Once()
+
+		actualResult, actualError := s.service.Clone(context.Background(), 1, "user@email.com")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAppealNotFound.Error())
+	})
+
+	s.Run("should create a new appeal with the same resource, role, and access duration", func() {
+		sourceCreatedAt := timeNow.Add(-30 * 24 * time.Hour)
+		sourceExpirationDate := sourceCreatedAt.Add(7 * 24 * time.Hour)
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{
+			ID:         1,
+			ResourceID: 1,
+			User:       "bob@email.com",
+			Role:       "role_1",
+			Status:     domain.AppealStatusTerminated,
+			Options:    &domain.AppealOptions{ExpirationDate: &sourceExpirationDate},
+			Labels:     map[string]string{"team": "data"},
+			CreatedAt:  sourceCreatedAt,
+		}, nil).Once()
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{{
+			ID:           1,
+			Type:         "resource_type",
+			ProviderType: "provider_type",
+			ProviderURN:  "provider_urn",
+		}}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return([]*domain.Provider{{
+			Type: "provider_type",
+			URN:  "provider_urn",
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{},
+				Resources: []*domain.ResourceConfig{
+					{
+						Type:   "resource_type",
+						Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+						Roles:  []*domain.RoleConfig{{ID: "role_1"}},
+					},
+				},
+			},
+		}}, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{ID: "policy_1", Version: 1, Steps: []*domain.Step{{Name: "step_1"}}}}, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{}, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := s.service.Clone(context.Background(), 1, "user@email.com")
+
+		s.Nil(actualError)
+		s.Equal("user@email.com", actualResult.User)
+		s.Equal(uint(1), actualResult.ResourceID)
+		s.Equal("role_1", actualResult.Role)
+		s.Equal(domain.AppealStatusPending, actualResult.Status)
+		s.Equal(map[string]string{"team": "data"}, actualResult.Labels)
+		s.Equal(timeNow.Add(7*24*time.Hour), *actualResult.Options.ExpirationDate)
+		s.Len(actualResult.Approvals, 1)
+		s.Equal(domain.ApprovalStatusPending, actualResult.Approvals[0].Status)
+	})
+}
+
+func (s *ServiceTestSuite) TestSuggestApprovers() {
+	resource := &domain.Resource{
+		ID:           1,
+		URN:          "urn",
+		Type:         "resource_type",
+		ProviderType: "provider_type",
+		ProviderURN:  "provider_urn",
+		Details: map[string]interface{}{
+			"owners": []interface{}{"owner@email.com", "manager@email.com"},
+		},
+	}
+	providers := []*domain.Provider{{
+		Type: "provider_type",
+		URN:  "provider_urn",
+		Config: &domain.ProviderConfig{
+			Appeal: &domain.AppealConfig{},
+			Resources: []*domain.ResourceConfig{
+				{
+					Type:   "resource_type",
+					Policy: &domain.PolicyConfig{ID: "policy_1", Version: 1},
+					Roles:  []*domain.RoleConfig{{ID: "role_1"}},
+				},
+			},
+		},
+	}}
+
+	s.Run("should return error if user is not a valid email", func() {
+		actualResult, actualError := s.service.SuggestApprovers(context.Background(), 1, "invalid")
+
+		s.Nil(actualResult)
+		s.True(errors.Is(actualError, appeal.ErrInvalidUser))
+	})
+
+	s.Run("should return error if resource is not found", func() {
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{}, nil).Once()
+
+		actualResult, actualError := s.service.SuggestApprovers(context.Background(), 1, "user@email.com")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrResourceNotFound.Error())
+	})
+
+	s.Run("should return error if the resource policy is not found", func() {
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{resource}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return(providers, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{}, nil).Once()
+
+		actualResult, actualError := s.service.SuggestApprovers(context.Background(), 1, "user@email.com")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrPolicyIDNotFound.Error())
+	})
+
+	s.Run("should return the unique approvers of every step resolved for the user", func() {
+		s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{resource}, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return(providers, nil).Once()
+		s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{
+			ID:      "policy_1",
+			Version: 1,
+			Steps: []*domain.Step{
+				{
+					Name:       "check_pii",
+					Conditions: []*domain.Condition{{Field: "$resource.details.is_pii", Match: &domain.MatchCondition{Eq: true}}},
+				},
+				{
+					Name:      "manager_approval",
+					Approvers: domain.ApproversKeyUserApprovers,
+				},
+				{
+					Name:      "owner_approval",
+					Approvers: "$resource.details.owners",
+				},
+			},
+		}}, nil).Once()
+		s.mockIAMService.On("GetUserApproverEmails", "user@email.com").Return([]string{"manager@email.com"}, nil).Once()
+
+		actualResult, actualError := s.service.SuggestApprovers(context.Background(), 1, "user@email.com")
+
+		s.Nil(actualError)
+		s.Equal([]string{"manager@email.com", "owner@email.com"}, actualResult)
+		s.mockIAMService.AssertExpectations(s.T())
+	})
+
+	s.Run("should skip the unavailable approvers if the step checks the availability", func() {
+		testCases := []struct {
+			name              string
+			fallbackApprovers []string
+			availability      map[string]bool
+			availabilityError error
+			expectedApprovers []string
+		}{
+			{
+				name:              "some approvers are available",
+				fallbackApprovers: []string{"backup@email.com"},
+				availability:      map[string]bool{"manager@email.com": false, "manager.2@email.com": true},
+				expectedApprovers: []string{"manager.2@email.com"},
+			},
+			{
+				name:              "none of the approvers is available",
+				fallbackApprovers: []string{"backup@email.com"},
+				availability:      map[string]bool{"manager@email.com": false, "manager.2@email.com": false},
+				expectedApprovers: []string{"backup@email.com"},
+			},
+			{
+				name:              "none of the approvers is available without fallback approvers",
+				availability:      map[string]bool{"manager@email.com": false, "manager.2@email.com": false},
+				expectedApprovers: []string{"manager@email.com", "manager.2@email.com"},
+			},
+			{
+				name:              "availability is unknown",
+				fallbackApprovers: []string{"backup@email.com"},
+				availability:      map[string]bool{"manager@email.com": false, "manager.2@email.com": false},
+				availabilityError: errors.New("iam error"),
+				expectedApprovers: []string{"manager@email.com", "manager.2@email.com"},
+			},
+		}
+
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockResourceService.On("Find", mock.Anything, mock.Anything).Return([]*domain.Resource{resource}, nil).Once()
+				s.mockProviderService.On("Find", mock.Anything).Return(providers, nil).Once()
+				s.mockPolicyService.On("Find", mock.Anything).Return([]*domain.Policy{{
+					ID:      "policy_1",
+					Version: 1,
+					Steps: []*domain.Step{
+						{
+							Name:                      "manager_approval",
+							Approvers:                 domain.ApproversKeyUserApprovers,
+							CheckApproverAvailability: true,
+							FallbackApprovers:         tc.fallbackApprovers,
+						},
+					},
+				}}, nil).Once()
+				s.mockIAMService.On("GetUserApproverEmails", "user@email.com").Return([]string{"manager@email.com", "manager.2@email.com"}, nil).Once()
+				for approver, available := range tc.availability {
+					s.mockIAMService.On("IsUserAvailable", approver).Return(available, tc.availabilityError).Once()
+				}
+
+				actualResult, actualError := s.service.SuggestApprovers(context.Background(), 1, "user@email.com")
+
+				s.Nil(actualError)
+				s.Equal(tc.expectedApprovers, actualResult)
+			})
+		}
+	})
+}
+
+func (s *ServiceTestSuite) TestMakeAction() {
+	timeNow := time.Now()
+	appeal.TimeNow = func() time.Time {
+		return timeNow
+	}
+	s.Run("should return error if approval action parameter is invalid", func() {
+		invalidApprovalActionParameters := []domain.ApprovalAction{
+			{
+				ApprovalName: "approval_1",
+				Actor:        "user@email.com",
+				Action:       "name",
+			},
+			{
+				AppealID: 1,
+				Actor:    "user@email.com",
+				Action:   "name",
+			},
+			{
+				AppealID:     1,
+				ApprovalName: "approval_1",
+				Actor:        "invalidemail",
+				Action:       "name",
+			},
+			{
+				AppealID:     1,
+				ApprovalName: "approval_1",
+				Action:       "name",
+			},
+			{
+				AppealID:     1,
+				ApprovalName: "approval_1",
+				Actor:        "user@email.com",
+			},
+		}
+
+		for _, param := range invalidApprovalActionParameters {
+			actualResult, actualError := s.service.MakeAction(context.Background(), param)
+
+			s.Nil(actualResult)
+			s.Error(actualError)
+		}
+	})
+
+	s.Run("should return error if actor doesn't match the authenticated user", func() {
+		ctx := auth.NewContext(context.Background(), "approver@email.com")
+		actualResult, actualError := s.service.MakeAction(ctx, domain.ApprovalAction{
+			AppealID:     1,
+			ApprovalName: "approval_1",
+			Actor:        "spoofed@email.com",
+			Action:       domain.AppealActionNameApprove,
+		})
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrActorMismatch.Error())
+	})
+
+	validApprovalActionParam := domain.ApprovalAction{
+		AppealID:     1,
+		ApprovalName: "approval_1",
+		Actor:        "user@email.com",
+		Action:       "approve",
+	}
+
+	s.Run("should return error if got any from repository while getting appeal details", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("GetByID", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualResult, actualError := s.service.MakeAction(context.Background(), validApprovalActionParam)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return nil and nil error if appeal not found", func() {
+		s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).Return(nil, nil).Once()
+
+		actualResult, actualError := s.service.MakeAction(context.Background(), validApprovalActionParam)
+
+		s.Nil(actualResult)
+		s.Nil(actualError)
+	})
+
+	s.Run("should return error based on statuses conditions", func() {
+		testCases := []struct {
+			name          string
+			appealStatus  string
+			approvals     []*domain.Approval
+			expectedError error
+		}{
+			{
+				name:          "appeal not eligible, status: active",
+				appealStatus:  domain.AppealStatusActive,
+				expectedError: appeal.ErrAppealStatusApproved,
+			},
+			{
+				name:          "appeal not eligible, status: rejected",
+				appealStatus:  domain.AppealStatusRejected,
+				expectedError: appeal.ErrAppealStatusRejected,
+			},
+			{
+				name:          "appeal not eligible, status: terminated",
+				appealStatus:  domain.AppealStatusTerminated,
+				expectedError: appeal.ErrAppealStatusTerminated,
+			},
+			{
+				name:          "invalid appeal status",
+				appealStatus:  "invalidstatus",
+				expectedError: appeal.ErrAppealStatusUnrecognized,
+			},
+			{
+				name:         "previous approval step still on pending",
+				appealStatus: domain.AppealStatusPending,
+				approvals: []*domain.Approval{
+					{
+						Name:   "approval_0",
+						Status: domain.ApprovalStatusPending,
+					},
+					{
+						Name:   "approval_1",
+						Status: domain.ApprovalStatusPending,
+					},
+				},
+				expectedError: appeal.ErrApprovalDependencyIsPending,
+			},
+			{
+				name:         "found one previous approval is reject",
+				appealStatus: domain.AppealStatusPending,
+				approvals: []*domain.Approval{
+					{
+						Name:   "approval_0",
+						Status: domain.ApprovalStatusRejected,
+					},
+					{
+						Name:   "approval_1",
+						Status: domain.ApprovalStatusPending,
+					},
+				},
+				expectedError: appeal.ErrAppealStatusRejected,
+			},
+			{
+				name:         "invalid approval status",
+				appealStatus: domain.AppealStatusPending,
+				approvals: []*domain.Approval{
+					{
+						Name:   "approval_0",
+						Status: "invalidstatus",
+					},
+					{
+						Name:   "approval_1",
+						Status: domain.ApprovalStatusPending,
+					},
+				},
+				expectedError: appeal.ErrApprovalStatusUnrecognized,
+			},
+			{
+				name:         "approval step already approved",
+				appealStatus: domain.AppealStatusPending,
+				approvals: []*domain.Approval{
+					{
+						Name:   "approval_0",
+						Status: domain.ApprovalStatusApproved,
+					},
+					{
+						Name:   "approval_1",
+						Status: domain.ApprovalStatusApproved,
+					},
+				},
+				expectedError: appeal.ErrApprovalStatusApproved,
+			},
+			{
+				name:         "approval step already rejected",
+				appealStatus: domain.AppealStatusPending,
+				approvals: []*domain.Approval{
+					{
+						Name:   "approval_0",
+						Status: domain.ApprovalStatusApproved,
+					},
+					{
+						Name:   "approval_1",
+						Status: domain.ApprovalStatusRejected,
+					},
+				},
+				expectedError: appeal.ErrApprovalStatusRejected,
+			},
+			{
+				name:         "approval step already skipped",
+				appealStatus: domain.AppealStatusPending,
+				approvals: []*domain.Approval{
+					{
+						Name:   "approval_0",
+						Status: domain.ApprovalStatusApproved,
+					},
+					{
+						Name:   "approval_1",
+						Status: domain.ApprovalStatusSkipped,
+					},
+				},
+				expectedError: appeal.ErrApprovalStatusSkipped,
+			},
+			{
+				name:         "invalid approval status",
+				appealStatus: domain.AppealStatusPending,
+				approvals: []*domain.Approval{
+					{
+						Name:   "approval_0",
+						Status: domain.ApprovalStatusApproved,
+					},
+					{
+						Name:   "approval_1",
+						Status: "invalidstatus",
+					},
+				},
+				expectedError: appeal.ErrApprovalStatusUnrecognized,
+			},
+			{
+				name:         "user doesn't have permission",
+				appealStatus: domain.AppealStatusPending,
+				approvals: []*domain.Approval{
+					{
+						Name:   "approval_0",
+						Status: domain.ApprovalStatusApproved,
+					},
+					{
+						Name:      "approval_1",
+						Status:    domain.ApprovalStatusPending,
+						Approvers: []string{"another.user@email.com"},
+					},
+				},
+				expectedError: appeal.ErrActionForbidden,
+			},
+			{
+				name:         "approval step not found",
+				appealStatus: domain.AppealStatusPending,
+				approvals: []*domain.Approval{
+					{
+						Name:   "approval_0",
+						Status: domain.ApprovalStatusApproved,
+					},
+					{
+						Name:   "approval_x",
+						Status: domain.ApprovalStatusApproved,
+					},
+				},
+				expectedError: appeal.ErrApprovalNameNotFound,
+			},
+		}
+
+		for _, tc := range testCases {
+			expectedAppeal := &domain.Appeal{
+				ID:        validApprovalActionParam.AppealID,
+				Status:    tc.appealStatus,
+				Approvals: tc.approvals,
+			}
+			s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).Return(expectedAppeal, nil).Once()
+
+			actualResult, actualError := s.service.MakeAction(context.Background(), validApprovalActionParam)
+
+			s.Nil(actualResult)
+			s.EqualError(actualError, tc.expectedError.Error())
+		}
+	})
+
+	s.Run("should return error if got any from repository while updating appeal", func() {
+		expectedAppeal := &domain.Appeal{
+			ID:     validApprovalActionParam.AppealID,
+			Status: domain.AppealStatusPending,
+			Resource: &domain.Resource{
+				ID:  1,
+				URN: "urn",
+			},
+			Approvals: []*domain.Approval{
+				{
+					Name:   "approval_0",
+					Status: domain.ApprovalStatusApproved,
+				},
+				{
+					Name:      "approval_1",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{"user@email.com"},
+				},
+			},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).Return(expectedAppeal, nil).Once()
+		expectedError := errors.New("repository error")
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, expectedAppeal).Return(nil).Once()
+		s.mockProviderService.On("GetTeamQuota", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		s.mockProviderService.On("GrantAccess", mock.Anything, expectedAppeal).Return(nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.Anything).Return(expectedError).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, expectedAppeal).Return(nil).Once()
+
+		actualResult, actualError := s.service.MakeAction(context.Background(), validApprovalActionParam)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return updated appeal on success", func() {
+		user := "user@email.com"
+		testCases := []struct {
+			name                   string
+			expectedApprovalAction domain.ApprovalAction
+			expectedAppealDetails  *domain.Appeal
+			expectedResult         *domain.Appeal
+			expectedNotifications  []domain.Notification
+		}{
+			{
+				name:                   "approve",
+				expectedApprovalAction: validApprovalActionParam,
+				expectedAppealDetails: &domain.Appeal{
+					ID:         validApprovalActionParam.AppealID,
+					User:       "user@email.com",
+					ResourceID: 1,
+					Resource: &domain.Resource{
+						ID:  1,
+						URN: "urn",
+					},
+					Status: domain.AppealStatusPending,
+					Approvals: []*domain.Approval{
+						{
+							Name:   "approval_0",
+							Status: domain.ApprovalStatusApproved,
+						},
+						{
+							Name:      "approval_1",
+							Status:    domain.ApprovalStatusPending,
+							Approvers: []string{"user@email.com"},
+						},
+					},
+				},
+				expectedResult: &domain.Appeal{
+					ID:         validApprovalActionParam.AppealID,
+					User:       "user@email.com",
+					ResourceID: 1,
+					Resource: &domain.Resource{
+						ID:  1,
+						URN: "urn",
+					},
+					Status: domain.AppealStatusActive,
+					Approvals: []*domain.Approval{
+						{
+							Name:   "approval_0",
+							Status: domain.ApprovalStatusApproved,
+						},
+						{
+							Name:            "approval_1",
+							Status:          domain.ApprovalStatusApproved,
+							Approvers:       []string{"user@email.com"},
+							Actor:           &user,
+							UpdatedAt:       timeNow,
+							StatusChangedAt: &timeNow,
+						},
+					},
+				},
+				expectedNotifications: []domain.Notification{
+					{
+						User:    "user@email.com",
+						Message: "Your appeal to urn has been approved",
+					},
+				},
+			},
+			{
+				name: "reject",
+				expectedApprovalAction: domain.ApprovalAction{
+					AppealID:     1,
+					ApprovalName: "approval_1",
+					Actor:        "user@email.com",
+					Action:       domain.AppealActionNameReject,
+				},
+				expectedAppealDetails: &domain.Appeal{
+					ID:         validApprovalActionParam.AppealID,
+					User:       "user@email.com",
+					ResourceID: 1,
+					Resource: &domain.Resource{
+						ID:  1,
+						URN: "urn",
+					},
+					Status: domain.AppealStatusPending,
+					Approvals: []*domain.Approval{
+						{
+							Name:   "approval_0",
+							Status: domain.ApprovalStatusApproved,
+						},
+						{
+							Name:      "approval_1",
+							Status:    domain.ApprovalStatusPending,
+							Approvers: []string{"user@email.com"},
+						},
+					},
+				},
+				expectedResult: &domain.Appeal{
+					ID:         validApprovalActionParam.AppealID,
+					User:       "user@email.com",
+					ResourceID: 1,
+					Resource: &domain.Resource{
+						ID:  1,
+						URN: "urn",
+					},
+					Status: domain.AppealStatusRejected,
+					Approvals: []*domain.Approval{
+						{
+							Name:   "approval_0",
+							Status: domain.ApprovalStatusApproved,
+						},
+						{
+							Name:            "approval_1",
+							Status:          domain.ApprovalStatusRejected,
+							Approvers:       []string{"user@email.com"},
+							Actor:           &user,
+							UpdatedAt:       timeNow,
+							StatusChangedAt: &timeNow,
+						},
+					},
+				},
+				expectedNotifications: []domain.Notification{
+					{
+						User:    "user@email.com",
+						Message: "Your appeal to urn is rejected",
+					},
+				},
+			},
+			{
+				name: "reject in the middle step",
+				expectedApprovalAction: domain.ApprovalAction{
+					AppealID:     1,
+					ApprovalName: "approval_1",
+					Actor:        user,
+					Action:       domain.AppealActionNameReject,
+				},
+				expectedAppealDetails: &domain.Appeal{
+					ID:         validApprovalActionParam.AppealID,
+					User:       "user@email.com",
+					ResourceID: 1,
+					Resource: &domain.Resource{
+						ID:  1,
+						URN: "urn",
+					},
+					Status: domain.AppealStatusPending,
+					Approvals: []*domain.Approval{
+						{
+							Name:   "approval_0",
+							Status: domain.ApprovalStatusApproved,
+						},
+						{
+							Name:      "approval_1",
+							Status:    domain.ApprovalStatusPending,
+							Approvers: []string{"user@email.com"},
+						},
+						{
+							Name:   "approval_2",
+							Status: domain.ApprovalStatusPending,
+						},
+					},
+				},
+				expectedResult: &domain.Appeal{
+					ID:         validApprovalActionParam.AppealID,
+					User:       "user@email.com",
+					ResourceID: 1,
+					Resource: &domain.Resource{
+						ID:  1,
+						URN: "urn",
+					},
+					Status: domain.AppealStatusRejected,
+					Approvals: []*domain.Approval{
+						{
+							Name:   "approval_0",
+							Status: domain.ApprovalStatusApproved,
+						},
+						{
+							Name:            "approval_1",
+							Status:          domain.ApprovalStatusRejected,
+							Approvers:       []string{"user@email.com"},
+							Actor:           &user,
+							UpdatedAt:       timeNow,
+							StatusChangedAt: &timeNow,
+						},
+						{
+							Name:            "approval_2",
+							Status:          domain.ApprovalStatusSkipped,
+							UpdatedAt:       timeNow,
+							StatusChangedAt: &timeNow,
+						},
+					},
+				},
+				expectedNotifications: []domain.Notification{
+					{
+						User:    "user@email.com",
+						Message: "Your appeal to urn is rejected",
+					},
+				},
+			},
+			{
+				name: "should notify the next approvers if there's still manual approvals remaining ahead after approved",
+				expectedApprovalAction: domain.ApprovalAction{
+					AppealID:     validApprovalActionParam.AppealID,
+					ApprovalName: "approval_0",
+					Actor:        user,
+					Action:       domain.AppealActionNameApprove,
+				},
+				expectedAppealDetails: &domain.Appeal{
+					ID:         validApprovalActionParam.AppealID,
+					User:       "user@email.com",
+					ResourceID: 1,
+					Resource: &domain.Resource{
+						ID:  1,
+						URN: "urn",
+					},
+					Status: domain.AppealStatusPending,
+					Approvals: []*domain.Approval{
+						{
+							Name:      "approval_0",
+							Status:    domain.ApprovalStatusPending,
+							Approvers: []string{user},
+						},
+						{
+							Name:   "approval_1",
+							Status: domain.ApprovalStatusPending,
+							Approvers: []string{
+								"nextapprover1@email.com",
+								"nextapprover2@email.com",
+							},
+						},
+					},
+				},
+				expectedResult: &domain.Appeal{
+					ID:         validApprovalActionParam.AppealID,
+					User:       "user@email.com",
+					ResourceID: 1,
+					Resource: &domain.Resource{
+						ID:  1,
+						URN: "urn",
+					},
+					Status: domain.AppealStatusPending,
+					Approvals: []*domain.Approval{
+						{
+							Name:            "approval_0",
+							Status:          domain.ApprovalStatusApproved,
+							Approvers:       []string{user},
+							Actor:           &user,
+							UpdatedAt:       timeNow,
+							StatusChangedAt: &timeNow,
+						},
+						{
+							Name:   "approval_1",
+							Status: domain.ApprovalStatusPending,
+							Approvers: []string{
+								"nextapprover1@email.com",
+								"nextapprover2@email.com",
+							},
+						},
+					},
+				},
+				expectedNotifications: []domain.Notification{
+					{
+						User:    "nextapprover1@email.com",
+						Message: "You have an appeal from user@email.com to access urn",
+					},
+					{
+						User:    "nextapprover2@email.com",
+						Message: "You have an appeal from user@email.com to access urn",
+					},
+				},
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).
+					Return(tc.expectedAppealDetails, nil).
+					Once()
+				s.mockApprovalService.On("AdvanceApproval", mock.Anything, tc.expectedAppealDetails).
+					Return(nil).Once()
+				s.mockProviderService.On("GetTeamQuota", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+				s.mockProviderService.On("GrantAccess", mock.Anything, tc.expectedAppealDetails).
+					Return(nil).
+					Once()
+				s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+				s.expectOutboxMessage()
+				s.mockRepository.On("Update", mock.Anything, mock.Anything).
+					Return(nil).
+					Once()
+				s.mockNotifier.On("Notify", tc.expectedNotifications).Return(nil).Once()
+
+				actualResult, actualError := s.service.MakeAction(context.Background(), tc.expectedApprovalAction)
+
+				s.Equal(tc.expectedResult, actualResult)
+				s.Nil(actualError)
+			})
+		}
+	})
+
+	s.Run("should return the appeal as stored regardless of the action", func() {
+		user := "user@email.com"
+		for _, action := range []string{domain.AppealActionNameApprove, domain.AppealActionNameReject} {
+			s.Run(action, func() {
+				appealDetails := &domain.Appeal{
+					ID:            validApprovalActionParam.AppealID,
+					User:          user,
+					ResourceID:    1,
+					Resource:      &domain.Resource{ID: 1, URN: "urn"},
+					PolicyID:      "policy_1",
+					PolicyVersion: 1,
+					Policy:        &domain.Policy{ID: "policy_1", Version: 1},
+					Status:        domain.AppealStatusPending,
+					Approvals: []*domain.Approval{
+						{
+							Name:      "approval_0",
+							Index:     0,
+							Status:    domain.ApprovalStatusPending,
+							Approvers: []string{user},
+						},
+						{
+							Name:      "approval_1",
+							Index:     1,
+							Status:    domain.ApprovalStatusPending,
+							Approvers: []string{"next.approver@email.com"},
+						},
+					},
+				}
+				s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).Return(appealDetails, nil).Once()
+				s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+				s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+				s.expectOutboxMessage()
+				s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+				s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+				actualResult, actualError := s.service.MakeAction(context.Background(), domain.ApprovalAction{
+					AppealID:     validApprovalActionParam.AppealID,
+					ApprovalName: "approval_0",
+					Actor:        user,
+					Action:       action,
+				})
+
+				s.Nil(actualError)
+				m := new(model.Appeal)
+				s.Nil(m.FromDomain(actualResult))
+				storedAppeal, err := m.ToDomain()
+				s.Nil(err)
+				s.Equal(storedAppeal, actualResult)
+				s.Len(actualResult.Approvals, 2)
+				s.Equal([]string{user}, actualResult.Approvals[0].Approvers)
+			})
+		}
+	})
+
+	s.Run("should revoke the granted access and return error if it is not found in the provider", func() {
+		service := appeal.NewService(
+			s.mockRepository,
+			s.mockApprovalService,
+			s.mockResourceService,
+			s.mockProviderService,
+			s.mockPolicyService,
+			s.mockIAMService,
+			s.mockNotifier,
+			s.mockBlobStorage,
+			zap.NewNop(),
+			&appeal.Config{VerifyGrantedAccess: true},
+		)
+		appealDetails := &domain.Appeal{
+			ID:     validApprovalActionParam.AppealID,
+			Status: domain.AppealStatusPending,
+			Resource: &domain.Resource{
+				ID:  1,
+				URN: "urn",
+			},
+			Approvals: []*domain.Approval{
+				{
+					Name:      validApprovalActionParam.ApprovalName,
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{validApprovalActionParam.Actor},
+				},
+			},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).Return(appealDetails, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockProviderService.On("GetTeamQuota", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		s.mockProviderService.On("GrantAccess", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockProviderService.On("VerifyAccess", mock.Anything, appealDetails).Return(false, nil).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, appealDetails).Return(nil).Once()
+
+		actualResult, actualError := service.MakeAction(context.Background(), validApprovalActionParam)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAccessNotGranted.Error())
+		s.mockProviderService.AssertCalled(s.T(), "RevokeAccess", mock.Anything, appealDetails)
+	})
+
+	s.Run("should activate the appeal if the next approval steps are skipped", func() {
+		appealDetails := &domain.Appeal{
+			ID:     validApprovalActionParam.AppealID,
+			Status: domain.AppealStatusPending,
+			Resource: &domain.Resource{
+				ID:  1,
+				URN: "urn",
+			},
+			Approvals: []*domain.Approval{
+				{
+					Name:      validApprovalActionParam.ApprovalName,
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{validApprovalActionParam.Actor},
+				},
+				{
+					Name:   "long_lived_access_approval",
+					Status: domain.ApprovalStatusSkipped,
+				},
+			},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).Return(appealDetails, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockProviderService.On("GetTeamQuota", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		s.mockProviderService.On("GrantAccess", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := s.service.MakeAction(context.Background(), validApprovalActionParam)
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusActive, actualResult.Status)
+	})
+
+	s.Run("should schedule the access if the start date hasn't arrived", func() {
+		startDate := s.now.Add(24 * time.Hour)
+		appealDetails := &domain.Appeal{
+			ID:     validApprovalActionParam.AppealID,
+			User:   "user@email.com",
+			Status: domain.AppealStatusPending,
+			Resource: &domain.Resource{
+				ID:  1,
+				URN: "urn",
+			},
+			Options: &domain.AppealOptions{StartDate: &startDate},
+			Approvals: []*domain.Approval{
+				{
+					Name:      validApprovalActionParam.ApprovalName,
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{validApprovalActionParam.Actor},
+				},
+			},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).Return(appealDetails, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "user@email.com",
+			Message: fmt.Sprintf("Your appeal to urn has been approved, the access will be granted at %s", startDate.Format(time.RFC3339)),
+		}}).Return(nil).Once()
+
+		actualResult, actualError := s.service.MakeAction(context.Background(), validApprovalActionParam)
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusActive, actualResult.Status)
+		s.True(actualResult.AccessScheduled)
+		s.mockProviderService.AssertNotCalled(s.T(), "GrantAccess", mock.Anything, appealDetails)
+	})
+
+	s.Run("should reject the remaining approval steps based on the policy decision", func() {
+		evaluator, err := appeal.NewRegoEvaluator(context.Background(), testRegoPolicy, "data.guardian.appeal.decision")
+		s.Require().Nil(err)
+		s.service.DecisionEvaluator = evaluator
+		defer func() { s.service.DecisionEvaluator = nil }()
+
+		appealDetails := &domain.Appeal{
+			ID:     validApprovalActionParam.AppealID,
+			User:   "user@email.com",
+			Role:   "owner",
+			Status: domain.AppealStatusPending,
+			Labels: map[string]string{"team": "data"},
+			Resource: &domain.Resource{
+				ID:  1,
+				URN: "urn",
+			},
+			Approvals: []*domain.Approval{
+				{
+					Name:      validApprovalActionParam.ApprovalName,
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{validApprovalActionParam.Actor},
+				},
+				{
+					Name:      "approval_2",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{"next.approver@email.com"},
+				},
+				{
+					Name:      "approval_3",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{"last.approver@email.com"},
+				},
+			},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).Return(appealDetails, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "user@email.com",
+			Message: "Your appeal to urn is rejected",
+			Labels:  map[string]string{"team": "data"},
+		}}).Return(nil).Once()
+
+		actualResult, actualError := s.service.MakeAction(context.Background(), validApprovalActionParam)
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusRejected, actualResult.Status)
+		s.Equal(domain.ApprovalStatusApproved, actualResult.Approvals[0].Status)
+		s.Equal(domain.ApprovalStatusRejected, actualResult.Approvals[1].Status)
+		s.Equal(domain.SystemActorName, *actualResult.Approvals[1].Actor)
+		s.Equal(domain.ApprovalStatusSkipped, actualResult.Approvals[2].Status)
+	})
+
+	s.Run("should return error if attestation is enabled without any signer", func() {
+		service := appeal.NewService(
+			s.mockRepository,
+			s.mockApprovalService,
+			s.mockResourceService,
+			s.mockProviderService,
+			s.mockPolicyService,
+			s.mockIAMService,
+			s.mockNotifier,
+			s.mockBlobStorage,
+			zap.NewNop(),
+			&appeal.Config{ApprovalAttestation: true},
+		)
+		appealDetails := &domain.Appeal{
+			ID:     validApprovalActionParam.AppealID,
+			Status: domain.AppealStatusPending,
+			Resource: &domain.Resource{
+				ID:  1,
+				URN: "urn",
+			},
+			Approvals: []*domain.Approval{
+				{
+					Name:      validApprovalActionParam.ApprovalName,
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{validApprovalActionParam.Actor},
+				},
+			},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).Return(appealDetails, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+
+		actualResult, actualError := service.MakeAction(context.Background(), validApprovalActionParam)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAttestationSignerNotConfigured.Error())
+	})
+
+	s.Run("should store the attestation of the action if enabled", func() {
+		service := appeal.NewService(
+			s.mockRepository,
+			s.mockApprovalService,
+			s.mockResourceService,
+			s.mockProviderService,
+			s.mockPolicyService,
+			s.mockIAMService,
+			s.mockNotifier,
+			s.mockBlobStorage,
+			zap.NewNop(),
+			&appeal.Config{ApprovalAttestation: true},
+		)
+		service.Signer = crypto.NewHMAC("secret")
+		appealDetails := &domain.Appeal{
+			ID:     validApprovalActionParam.AppealID,
+			User:   "user@email.com",
+			Role:   "viewer",
+			Status: domain.AppealStatusPending,
+			Resource: &domain.Resource{
+				ID:  1,
+				URN: "urn",
+			},
+			Approvals: []*domain.Approval{
+				{
+					Name:      validApprovalActionParam.ApprovalName,
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{validApprovalActionParam.Actor},
+				},
+				{
+					Name:      "approval_2",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{"next.approver@email.com"},
+				},
+			},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).Return(appealDetails, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := service.MakeAction(context.Background(), validApprovalActionParam)
+
+		s.Nil(actualError)
+		attestation := actualResult.Approvals[0].Attestation
+		s.Require().NotNil(attestation)
+		s.Equal(validApprovalActionParam.Actor, attestation.Actor)
+		s.Equal(validApprovalActionParam.Action, attestation.Action)
+		verified, err := appeal.VerifyApprovalAttestation(service.Signer, actualResult, actualResult.Approvals[0])
+		s.Nil(err)
+		s.True(verified)
+	})
+
+	s.Run("should record whether the step is actioned within its sla", func() {
+		testCases := []struct {
+			createdAt      time.Time
+			expectedSLAMet bool
+		}{
+			{timeNow.Add(-1 * time.Hour), true},
+			{timeNow.Add(-25 * time.Hour), false},
+		}
+		for _, tc := range testCases {
+			appealDetails := &domain.Appeal{
+				ID:     validApprovalActionParam.AppealID,
+				Status: domain.AppealStatusPending,
+				Resource: &domain.Resource{
+					ID:  1,
+					URN: "urn",
+				},
+				Approvals: []*domain.Approval{
+					{
+						Name:      validApprovalActionParam.ApprovalName,
+						Status:    domain.ApprovalStatusPending,
+						Approvers: []string{validApprovalActionParam.Actor},
+						SLA:       "24h",
+					},
+					{
+						Name:      "approval_2",
+						Status:    domain.ApprovalStatusPending,
+						Approvers: []string{"next.approver@email.com"},
+					},
+				},
+				CreatedAt: tc.createdAt,
+			}
+			s.mockRepository.On("GetByID", mock.Anything, validApprovalActionParam.AppealID).Return(appealDetails, nil).Once()
+			s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+			s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+			s.expectOutboxMessage()
+			s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+			s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+			actualResult, actualError := s.service.MakeAction(context.Background(), validApprovalActionParam)
+
+			s.Nil(actualError)
+			s.Require().NotNil(actualResult.Approvals[0].SLAMet)
+			s.Equal(tc.expectedSLAMet, *actualResult.Approvals[0].SLAMet)
+			s.Nil(actualResult.Approvals[1].SLAMet)
+		}
+	})
+
+	s.Run("should require a reason according to the step's require_reason", func() {
+		testCases := []struct {
+			requireReason  string
+			action         string
+			reason         string
+			expectedToFail bool
+		}{
+			{"", domain.AppealActionNameApprove, "", false},
+			{"", domain.AppealActionNameReject, "", false},
+			{domain.RequireReasonNone, domain.AppealActionNameApprove, "", false},
+			{domain.RequireReasonNone, domain.AppealActionNameReject, "", false},
+			{domain.RequireReasonApprove, domain.AppealActionNameApprove, "", true},
+			{domain.RequireReasonApprove, domain.AppealActionNameApprove, "  ", true},
+			{domain.RequireReasonApprove, domain.AppealActionNameApprove, "needed for the audit", false},
+			{domain.RequireReasonApprove, domain.AppealActionNameReject, "", false},
+			{domain.RequireReasonReject, domain.AppealActionNameApprove, "", false},
+			{domain.RequireReasonReject, domain.AppealActionNameReject, "", true},
+			{domain.RequireReasonReject, domain.AppealActionNameReject, "not needed", false},
+			{domain.RequireReasonBoth, domain.AppealActionNameApprove, "", true},
+			{domain.RequireReasonBoth, domain.AppealActionNameReject, "", true},
+			{domain.RequireReasonBoth, domain.AppealActionNameApprove, "needed for the audit", false},
+		}
+		for _, tc := range testCases {
+			s.Run(fmt.Sprintf("%q to %s with reason %q", tc.requireReason, tc.action, tc.reason), func() {
+				appealDetails := &domain.Appeal{
+					ID:     validApprovalActionParam.AppealID,
+					Status: domain.AppealStatusPending,
+					Resource: &domain.Resource{
+						ID:  1,
+						URN: "urn",
+					},
+					Approvals: []*domain.Approval{
+						{
+							Name:          validApprovalActionParam.ApprovalName,
+							Status:        domain.ApprovalStatusPending,
+							Approvers:     []string{validApprovalActionParam.Actor},
+							RequireReason: tc.requireReason,
+						},
+						{
+							Name:      "approval_2",
+							Status:    domain.ApprovalStatusPending,
+							Approvers: []string{"next.approver@email.com"},
+						},
+					},
+				}
+				action := validApprovalActionParam
+				action.Action = tc.action
+				action.Reason = tc.reason
+				s.mockRepository.On("GetByID", mock.Anything, action.AppealID).Return(appealDetails, nil).Once()
+				if !tc.expectedToFail {
+					if tc.action == domain.AppealActionNameApprove {
+						s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+					}
+					s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+					s.expectOutboxMessage()
+					s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+					s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+				}
+
+				actualResult, actualError := s.service.MakeAction(context.Background(), action)
+
+				if tc.expectedToFail {
+					s.Nil(actualResult)
+					s.True(errors.Is(actualError, appeal.ErrReasonRequired))
+				} else {
+					s.Nil(actualError)
+					s.Equal(tc.reason, actualResult.Approvals[0].Reason)
+				}
+			})
+		}
+	})
+
+	s.Run("should require a comment to request changes", func() {
+		for _, comment := range []string{"", "  "} {
+			action := validApprovalActionParam
+			action.Action = domain.AppealActionNameRequestChanges
+			action.Comment = comment
+
+			actualResult, actualError := s.service.MakeAction(context.Background(), action)
+
+			s.Nil(actualResult)
+			s.ErrorIs(actualError, appeal.ErrChangesCommentRequired)
+		}
+	})
+
+	s.Run("should keep the step pending and send the comment to the requester on request changes", func() {
+		appealDetails := &domain.Appeal{
+			ID:     validApprovalActionParam.AppealID,
+			User:   "requester@email.com",
+			Status: domain.AppealStatusPending,
+			Resource: &domain.Resource{
+				ID:  1,
+				URN: "urn",
+			},
+			Approvals: []*domain.Approval{
+				{
+					Name:      validApprovalActionParam.ApprovalName,
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{validApprovalActionParam.Actor},
+				},
+			},
+		}
+		action := validApprovalActionParam
+		action.Action = domain.AppealActionNameRequestChanges
+		action.Comment = "please link the incident ticket"
+		timeNow := time.Now()
+		s.service.TimeNow = func() time.Time {
+			return timeNow
+		}
+		s.mockRepository.On("GetByID", mock.Anything, action.AppealID).Return(appealDetails, nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("AddComment", mock.Anything, &domain.AppealComment{
+			AppealID:   action.AppealID,
+			CreatedBy:  action.Actor,
+			Body:       fmt.Sprintf("requested changes on step %q: please link the incident ticket", action.ApprovalName),
+			Visibility: domain.CommentVisibilityPublic,
+		}).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "requester@email.com",
+			Message: "Changes are requested on your appeal to urn, resubmit it once they're made: please link the incident ticket",
+		}}).Return(nil).Once()
+
+		actualResult, actualError := s.service.MakeAction(context.Background(), action)
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusPending, actualResult.Status)
+		s.Equal(domain.ApprovalStatusPending, actualResult.Approvals[0].Status)
+		s.Nil(actualResult.Approvals[0].Actor)
+		s.Equal(&timeNow, actualResult.Approvals[0].ChangesRequestedAt)
+	})
+
+	s.Run("should return error if the request changes comment fails to be stored", func() {
+		appealDetails := &domain.Appeal{
+			ID:     validApprovalActionParam.AppealID,
+			Status: domain.AppealStatusPending,
+			Approvals: []*domain.Approval{
+				{
+					Name:      validApprovalActionParam.ApprovalName,
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{validApprovalActionParam.Actor},
+				},
+			},
+		}
+		action := validApprovalActionParam
+		action.Action = domain.AppealActionNameRequestChanges
+		action.Comment = "please link the incident ticket"
+		expectedError := errors.New("db error")
+		s.mockRepository.On("GetByID", mock.Anything, action.AppealID).Return(appealDetails, nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("AddComment", mock.Anything, mock.Anything).Return(expectedError).Once()
+
+		actualResult, actualError := s.service.MakeAction(context.Background(), action)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, expectedError)
+	})
+}
+
+func (s *ServiceTestSuite) TestMakeActionWithTeamQuota() {
+	approvalAction := domain.ApprovalAction{
+		AppealID:     1,
+		ApprovalName: "approval_1",
+		Actor:        "user@email.com",
+		Action:       domain.AppealActionNameApprove,
+	}
+
+	s.Run("should reject the appeal exceeding the team quota", func() {
+		quota := &domain.TeamQuotaConfig{Role: "admin", Limit: 2}
+		grants := []*domain.Appeal{
+			{ID: 2, User: "member.1@email.com"},
+			{ID: 3, User: "member.2@email.com"},
+			{ID: 4, User: "other.team@email.com"},
+			{ID: 5, User: "requester@email.com"},
+		}
+		testCases := []struct {
+			name           string
+			quota          *domain.TeamQuotaConfig
+			grants         []*domain.Appeal
+			lockedGrants   []*domain.Appeal
+			expectedStatus string
+			expectedError  error
+		}{
+			{
+				name:           "without quota",
+				expectedStatus: domain.AppealStatusActive,
+			},
+			{
+				name:           "quota not reached",
+				quota:          quota,
+				grants:         grants[1:],
+				lockedGrants:   grants[1:],
+				expectedStatus: domain.AppealStatusActive,
+			},
+			{
+				name:           "quota reached",
+				quota:          quota,
+				grants:         grants,
+				expectedStatus: domain.AppealStatusRejected,
+			},
+			{
+				name:          "quota reached by a concurrent approval",
+				quota:         quota,
+				grants:        grants[1:],
+				lockedGrants:  grants,
+				expectedError: appeal.ErrTeamQuotaExceeded,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				appealDetails := &domain.Appeal{
+					ID:         approvalAction.AppealID,
+					ResourceID: 1,
+					User:       "requester@email.com",
+					Role:       "admin",
+					Status:     domain.AppealStatusPending,
+					Resource: &domain.Resource{
+						ID:           1,
+						ProviderType: "provider_type",
+						ProviderURN:  "provider_urn",
+						Type:         "resource_type",
+						URN:          "urn",
+					},
+					Approvals: []*domain.Approval{
+						{
+							Name:      approvalAction.ApprovalName,
+							Status:    domain.ApprovalStatusPending,
+							Approvers: []string{approvalAction.Actor},
+						},
+					},
+				}
+				expectTeamGrants := func(grants []*domain.Appeal) {
+					s.mockIAMService.On("GetUserTeam", "requester@email.com").Return("team_a", nil).Once()
+					s.mockRepository.On("Find", mock.Anything, map[string]interface{}{
+						"resource_id": uint(1),
+						"role":        "admin",
+						"statuses":    []string{domain.AppealStatusActive},
+					}).Return(grants, nil).Once()
+					for _, g := range grants {
+						team := "team_a"
+						if g.User == "other.team@email.com" {
+							team = "team_b"
+						}
+						if g.User != "requester@email.com" {
+							s.mockIAMService.On("GetUserTeam", g.User).Return(team, nil).Once()
+						}
+					}
+				}
+				s.mockRepository.On("GetByID", mock.Anything, approvalAction.AppealID).Return(appealDetails, nil).Once()
+				s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+				s.mockProviderService.On("GetTeamQuota", mock.Anything, "provider_type", "provider_urn", "resource_type", "urn", "admin").Return(tc.quota, nil).Once()
+				if tc.quota != nil {
+					expectTeamGrants(tc.grants)
+				}
+				s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+				s.expectOutboxMessage()
+				if tc.lockedGrants != nil {
+					s.mockProviderService.On("GrantAccess", mock.Anything, appealDetails).Return(nil).Once()
+					s.mockRepository.On("LockGrants", mock.Anything, uint(1), "admin").Return(nil).Once()
+					expectTeamGrants(tc.lockedGrants)
+				} else if tc.quota == nil {
+					s.mockProviderService.On("GrantAccess", mock.Anything, appealDetails).Return(nil).Once()
+				}
+				if tc.expectedError != nil {
+					s.mockProviderService.On("RevokeAccess", mock.Anything, appealDetails).Return(nil).Once()
+				} else {
+					s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+					s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+				}
+
+				actualResult, actualError := s.service.MakeAction(context.Background(), approvalAction)
+
+				if tc.expectedError != nil {
+					s.Nil(actualResult)
+					s.ErrorIs(actualError, tc.expectedError)
+				} else {
+					s.Nil(actualError)
+					s.Equal(tc.expectedStatus, actualResult.Status)
+				}
+			})
+		}
+	})
+}
+
+func (s *ServiceTestSuite) TestMakeActionWithApprovalValidity() {
+	approvalAction := domain.ApprovalAction{
+		AppealID:     1,
+		ApprovalName: "approval_2",
+		Actor:        "approver.2@email.com",
+		Action:       domain.AppealActionNameApprove,
+	}
+	approver := "approver.1@email.com"
+	systemActor := domain.SystemActorName
+	newAppeal := func(approvedAt time.Time) *domain.Appeal {
+		return &domain.Appeal{
+			ID:            approvalAction.AppealID,
+			ResourceID:    1,
+			User:          "requester@email.com",
+			Role:          "viewer",
+			Status:        domain.AppealStatusPending,
+			PolicyID:      "policy_1",
+			PolicyVersion: 1,
+			Resource: &domain.Resource{
+				ID:           1,
+				ProviderType: "provider_type",
+				ProviderURN:  "provider_urn",
+				Type:         "resource_type",
+				URN:          "urn",
+			},
+			Approvals: []*domain.Approval{
+				{
+					Name:            "check",
+					Index:           0,
+					Status:          domain.ApprovalStatusApproved,
+					Actor:           &systemActor,
+					UpdatedAt:       approvedAt,
+					StatusChangedAt: &approvedAt,
+				},
+				{
+					Name:            "approval_1",
+					Index:           1,
+					Status:          domain.ApprovalStatusApproved,
+					Actor:           &approver,
+					Reason:          "looks good",
+					Approvers:       []string{approver},
+					ReminderLevel:   1,
+					UpdatedAt:       s.now,
+					StatusChangedAt: &approvedAt,
+				},
+				{
+					Name:      approvalAction.ApprovalName,
+					Index:     2,
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{approvalAction.Actor},
+				},
+			},
+		}
+	}
+	policy := &domain.Policy{ID: "policy_1", Version: 1, ApprovalValidity: "720h"}
+
+	s.Run("should reopen the stale approvals of the earlier steps", func() {
+		appealDetails := newAppeal(s.now.Add(-31 * 24 * time.Hour))
+		s.mockRepository.On("GetByID", mock.Anything, approvalAction.AppealID).Return(appealDetails, nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(policy, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    approver,
+			Message: "You have an appeal from requester@email.com to access urn",
+		}}).Return(nil).Once()
+
+		actualResult, actualError := s.service.MakeAction(context.Background(), approvalAction)
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusPending, actualResult.Status)
+		s.Equal(domain.ApprovalStatusApproved, actualResult.Approvals[0].Status)
+		reopened := actualResult.Approvals[1]
+		s.Equal(domain.ApprovalStatusPending, reopened.Status)
+		s.Nil(reopened.Actor)
+		s.Empty(reopened.Reason)
+		s.Zero(reopened.ReminderLevel)
+		s.Equal(s.now, *reopened.StatusChangedAt)
+		s.Equal(domain.ApprovalStatusApproved, actualResult.Approvals[2].Status)
+		s.Equal(approvalAction.Actor, *actualResult.Approvals[2].Actor)
+	})
+
+	s.Run("should complete the appeal if the earlier approvals are still valid", func() {
+		appealDetails := newAppeal(s.now.Add(-24 * time.Hour))
+		s.mockRepository.On("GetByID", mock.Anything, approvalAction.AppealID).Return(appealDetails, nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(policy, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockProviderService.On("GetTeamQuota", mock.Anything, "provider_type", "provider_urn", "resource_type", "urn", "viewer").Return(nil, nil).Once()
+		s.mockProviderService.On("GrantAccess", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := s.service.MakeAction(context.Background(), approvalAction)
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusActive, actualResult.Status)
+		s.Equal(domain.ApprovalStatusApproved, actualResult.Approvals[1].Status)
+	})
+}
+
+func (s *ServiceTestSuite) TestMakeActionByDelegation() {
+	s.Run("should allow the delegation chain of an unavailable approver to act on the step", func() {
+		testCases := []struct {
+			name                string
+			actor               string
+			depth               int
+			isApproverAvailable bool
+			expectedError       error
+		}{
+			{
+				name:  "manager of the unavailable approver",
+				actor: "manager@email.com",
+				depth: 2,
+			},
+			{
+				name:          "member beyond the chain depth",
+				actor:         "head@email.com",
+				depth:         2,
+				expectedError: appeal.ErrActionForbidden,
+			},
+			{
+				name:  "member of the whole chain",
+				actor: "head@email.com",
+			},
+			{
+				name:                "manager of an available approver",
+				actor:               "manager@email.com",
+				isApproverAvailable: true,
+				expectedError:       appeal.ErrActionForbidden,
+			},
+			{
+				name:          "outside the chain",
+				actor:         "someone@email.com",
+				expectedError: appeal.ErrActionForbidden,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				action := domain.ApprovalAction{
+					AppealID:     1,
+					ApprovalName: "approval_1",
+					Actor:        tc.actor,
+					Action:       domain.AppealActionNameApprove,
+				}
+				appealDetails := &domain.Appeal{
+					ID:         1,
+					ResourceID: 1,
+					User:       "user@email.com",
+					Role:       "viewer",
+					Status:     domain.AppealStatusPending,
+					Resource: &domain.Resource{
+						ID:           1,
+						ProviderType: "provider_type",
+						ProviderURN:  "provider_urn",
+						Type:         "resource_type",
+						URN:          "urn",
+					},
+					Approvals: []*domain.Approval{
+						{
+							Name:      "approval_1",
+							Status:    domain.ApprovalStatusPending,
+							Approvers: []string{"approver@email.com"},
+							DelegationChain: &domain.DelegationChain{
+								Members: []string{domain.DelegationKeyManagerChain, "head@email.com"},
+								Depth:   tc.depth,
+							},
+						},
+					},
+				}
+				s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+				s.mockIAMService.On("IsUserAvailable", "approver@email.com").Return(tc.isApproverAvailable, nil).Once()
+				if !tc.isApproverAvailable {
+					s.mockIAMService.On("GetManagerChain", "approver@email.com").Return([]string{"manager@email.com", "director@email.com"}, nil).Once()
+				}
+				if tc.expectedError == nil {
+					s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+					s.mockProviderService.On("GetTeamQuota", mock.Anything, "provider_type", "provider_urn", "resource_type", "urn", "viewer").Return(nil, nil).Once()
+					s.mockProviderService.On("GrantAccess", mock.Anything, appealDetails).Return(nil).Once()
+					s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+					s.expectOutboxMessage()
+					s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+					s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+				}
+
+				actualResult, actualError := s.service.MakeAction(context.Background(), action)
+
+				if tc.expectedError != nil {
+					s.Nil(actualResult)
+					s.ErrorIs(actualError, tc.expectedError)
+					return
+				}
+				s.Nil(actualError)
+				s.Equal(domain.AppealStatusActive, actualResult.Status)
+				provenance := actualResult.Approvals[0].GetProvenance()
+				s.Equal(tc.actor, provenance.EffectiveApprover)
+				s.Equal(domain.ApprovalActedAsDelegate, provenance.ActedAs)
+				s.Equal("approver@email.com", provenance.OnBehalfOf)
+			})
+		}
+	})
+
+	s.Run("should return error if the manager chain can't be resolved", func() {
+		expectedError := errors.New("iam error")
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{
+			ID:     1,
+			Status: domain.AppealStatusPending,
+			Approvals: []*domain.Approval{
+				{
+					Name:            "approval_1",
+					Status:          domain.ApprovalStatusPending,
+					Approvers:       []string{"approver@email.com"},
+					DelegationChain: &domain.DelegationChain{Members: []string{domain.DelegationKeyManagerChain}},
+				},
+			},
+		}, nil).Once()
+		s.mockIAMService.On("IsUserAvailable", "approver@email.com").Return(false, nil).Once()
+		s.mockIAMService.On("GetManagerChain", "approver@email.com").Return(nil, expectedError).Once()
+
+		actualResult, actualError := s.service.MakeAction(context.Background(), domain.ApprovalAction{
+			AppealID:     1,
+			ApprovalName: "approval_1",
+			Actor:        "manager@email.com",
+			Action:       domain.AppealActionNameApprove,
+		})
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, expectedError)
+	})
+
+	s.Run("should allow the delegate of an approver to act on the step while the delegation is active", func() {
+		testCases := []struct {
+			name          string
+			delegators    []string
+			expectedError error
+		}{
+			{
+				name:       "active delegation of the approver",
+				delegators: []string{"other.approver@email.com", "approver@email.com"},
+			},
+			{
+				name:          "expired or another approver's delegation",
+				delegators:    []string{"other.approver@email.com"},
+				expectedError: appeal.ErrActionForbidden,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				mockDelegationService := new(mocks.ApprovalDelegationService)
+				s.service.DelegationService = mockDelegationService
+				defer func() { s.service.DelegationService = nil }()
+
+				appealDetails := &domain.Appeal{
+					ID:         1,
 					ResourceID: 1,
+					User:       "user@email.com",
+					Role:       "viewer",
+					Status:     domain.AppealStatusPending,
 					Resource: &domain.Resource{
-						ID:  1,
-						URN: "urn",
+						ID:           1,
+						ProviderType: "provider_type",
+						ProviderURN:  "provider_urn",
+						Type:         "resource_type",
+						URN:          "urn",
+					},
+					Approvals: []*domain.Approval{
+						{
+							Name:      "approval_1",
+							Status:    domain.ApprovalStatusPending,
+							Approvers: []string{"approver@email.com"},
+						},
+					},
+				}
+				s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+				mockDelegationService.On("GetActiveDelegators", mock.Anything, "delegate@email.com").Return(tc.delegators, nil).Once()
+				if tc.expectedError == nil {
+					s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+					s.mockProviderService.On("GetTeamQuota", mock.Anything, "provider_type", "provider_urn", "resource_type", "urn", "viewer").Return(nil, nil).Once()
+					s.mockProviderService.On("GrantAccess", mock.Anything, appealDetails).Return(nil).Once()
+					s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+					s.expectOutboxMessage()
+					s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+					s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+				}
+
+				actualResult, actualError := s.service.MakeAction(context.Background(), domain.ApprovalAction{
+					AppealID:     1,
+					ApprovalName: "approval_1",
+					Actor:        "delegate@email.com",
+					Action:       domain.AppealActionNameApprove,
+				})
+
+				if tc.expectedError != nil {
+					s.Nil(actualResult)
+					s.ErrorIs(actualError, tc.expectedError)
+					return
+				}
+				s.Nil(actualError)
+				s.Equal(domain.AppealStatusActive, actualResult.Status)
+				provenance := actualResult.Approvals[0].GetProvenance()
+				s.Equal("delegate@email.com", provenance.EffectiveApprover)
+				s.Equal(domain.ApprovalActedAsDelegate, provenance.ActedAs)
+				s.Equal("approver@email.com", provenance.OnBehalfOf)
+			})
+		}
+	})
+}
+
+func (s *ServiceTestSuite) TestMakeActionWithSeparationOfDuties() {
+	approver := "approver@email.com"
+	delegate := "delegate@email.com"
+	admin := "admin@email.com"
+	newAppeal := func(firstApproval *domain.Approval, secondApprovers []string) *domain.Appeal {
+		firstApproval.Name = "approval_1"
+		firstApproval.Status = domain.ApprovalStatusApproved
+		return &domain.Appeal{
+			ID:            1,
+			User:          "user@email.com",
+			Status:        domain.AppealStatusPending,
+			PolicyID:      "policy_1",
+			PolicyVersion: 1,
+			Resource:      &domain.Resource{ID: 1, URN: "urn"},
+			Approvals: []*domain.Approval{
+				firstApproval,
+				{
+					Name:            "approval_2",
+					Status:          domain.ApprovalStatusPending,
+					Approvers:       secondApprovers,
+					DelegationChain: &domain.DelegationChain{Members: []string{domain.DelegationKeyManagerChain}},
+				},
+				{Name: "approval_3", Status: domain.ApprovalStatusPending, Approvers: []string{"other.approver@email.com"}},
+			},
+		}
+	}
+	approve := func(actor string) (*domain.Appeal, error) {
+		return s.service.MakeAction(context.Background(), domain.ApprovalAction{
+			AppealID:     1,
+			ApprovalName: "approval_2",
+			Actor:        actor,
+			Action:       domain.AppealActionNameApprove,
+		})
+	}
+
+	s.Run("should reject the approver of another step", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(newAppeal(&domain.Approval{Actor: &approver}, []string{approver}), nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(&domain.Policy{ID: "policy_1", Version: 1, SeparationOfDuties: true}, nil).Once()
+
+		actualResult, actualError := approve(approver)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrSeparationOfDuties)
+	})
+
+	s.Run("should reject the approver whose delegate approved another step on their behalf", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(newAppeal(&domain.Approval{Actor: &delegate, OnBehalfOf: approver}, []string{approver}), nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(&domain.Policy{ID: "policy_1", Version: 1, SeparationOfDuties: true}, nil).Once()
+
+		actualResult, actualError := approve(approver)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrSeparationOfDuties)
+	})
+
+	s.Run("should reject the delegate acting on behalf of the approver of another step", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(newAppeal(&domain.Approval{Actor: &approver}, []string{approver}), nil).Once()
+		s.mockIAMService.On("IsUserAvailable", approver).Return(false, nil).Once()
+		s.mockIAMService.On("GetManagerChain", approver).Return([]string{delegate}, nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(&domain.Policy{ID: "policy_1", Version: 1, SeparationOfDuties: true}, nil).Once()
+
+		actualResult, actualError := approve(delegate)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrSeparationOfDuties)
+	})
+
+	s.Run("should reject the approver who overrode another step as an admin", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(newAppeal(&domain.Approval{Actor: &admin, IsOverridden: true}, []string{admin}), nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(&domain.Policy{ID: "policy_1", Version: 1, SeparationOfDuties: true}, nil).Once()
+
+		actualResult, actualError := approve(admin)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrSeparationOfDuties)
+	})
+
+	s.Run("should reject the admin overriding a step after approving another step", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(newAppeal(&domain.Approval{Actor: &admin}, []string{approver}), nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(&domain.Policy{ID: "policy_1", Version: 1, SeparationOfDuties: true}, nil).Once()
+
+		actualResult, actualError := s.service.AdminApprove(context.Background(), 1, "approval_2", admin, "approver is on leave")
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrSeparationOfDuties)
+	})
+
+	s.Run("should allow the approver of another step if the policy doesn't require the separation of duties", func() {
+		appealDetails := newAppeal(&domain.Approval{Actor: &approver}, []string{approver})
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+		// the policy is looked up for both the separation of duties and the approval validity
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(&domain.Policy{ID: "policy_1", Version: 1}, nil).Twice()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := approve(approver)
+
+		s.Nil(actualError)
+		s.Equal(domain.ApprovalStatusApproved, actualResult.Approvals[1].Status)
+	})
+}
+
+func (s *ServiceTestSuite) TestMakeActionWithApprovalConditions() {
+	approver := "approver@email.com"
+	resource := &domain.Resource{ID: 1, ProviderType: "bigquery", ProviderURN: "provider_urn", Type: "dataset", URN: "urn"}
+	requestedExpirationDate := s.now.Add(7 * 24 * time.Hour)
+	newAppeal := func() *domain.Appeal {
+		expirationDate := requestedExpirationDate
+		return &domain.Appeal{
+			ID:            1,
+			User:          "user@email.com",
+			Role:          "editor",
+			Status:        domain.AppealStatusPending,
+			PolicyID:      "policy_1",
+			PolicyVersion: 1,
+			Options:       &domain.AppealOptions{ExpirationDate: &expirationDate},
+			Resource:      resource,
+			Approvals: []*domain.Approval{
+				{Name: "approval_1", Status: domain.ApprovalStatusPending, Approvers: []string{approver}},
+				{Name: "approval_2", Status: domain.ApprovalStatusPending, Approvers: []string{"other.approver@email.com"}},
+			},
+		}
+	}
+	makeAction := func(action string, conditions *domain.ApprovalConditions) (*domain.Appeal, error) {
+		return s.service.MakeAction(context.Background(), domain.ApprovalAction{
+			AppealID:     1,
+			ApprovalName: "approval_1",
+			Actor:        approver,
+			Action:       action,
+			Conditions:   conditions,
+		})
+	}
+	mockRolePermissions := func() {
+		s.mockProviderService.On("GetRolePermissions", mock.Anything, "bigquery", "provider_urn", "dataset", "editor").Return([]interface{}{"READER", "WRITER"}, nil).Once()
+		s.mockProviderService.On("GetRolePermissions", mock.Anything, "bigquery", "provider_urn", "dataset", "viewer").Return([]interface{}{"READER"}, nil).Maybe()
+		s.mockProviderService.On("GetRolePermissions", mock.Anything, "bigquery", "provider_urn", "dataset", "owner").Return([]interface{}{"OWNER"}, nil).Maybe()
+	}
+
+	s.Run("should return error if the conditions don't narrow down the access to a subset of the request", func() {
+		testCases := []struct {
+			name       string
+			action     string
+			conditions *domain.ApprovalConditions
+		}{
+			{"reject action", domain.AppealActionNameReject, &domain.ApprovalConditions{Role: "viewer"}},
+			{"empty conditions", domain.AppealActionNameApprove, &domain.ApprovalConditions{}},
+			{"role with more permissions", domain.AppealActionNameApprove, &domain.ApprovalConditions{Role: "owner"}},
+			{"invalid duration", domain.AppealActionNameApprove, &domain.ApprovalConditions{Duration: "a day"}},
+			{"longer duration", domain.AppealActionNameApprove, &domain.ApprovalConditions{Duration: "240h"}},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(newAppeal(), nil).Once()
+				if tc.conditions.Role != "" && tc.action == domain.AppealActionNameApprove {
+					mockRolePermissions()
+				}
+
+				actualResult, actualError := makeAction(tc.action, tc.conditions)
+
+				s.Nil(actualResult)
+				s.ErrorIs(actualError, appeal.ErrInvalidApprovalConditions)
+			})
+		}
+	})
+
+	s.Run("should grant the narrowed down role and duration and keep the requested ones", func() {
+		appealDetails := newAppeal()
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+		mockRolePermissions()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(&domain.Policy{ID: "policy_1", Version: 1}, nil).Maybe()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Maybe()
+
+		actualResult, actualError := makeAction(domain.AppealActionNameApprove, &domain.ApprovalConditions{Role: "viewer", Duration: "24h"})
+
+		s.Require().Nil(actualError)
+		s.Equal("viewer", actualResult.Role)
+		s.Equal(s.now.Add(24*time.Hour), *actualResult.Options.ExpirationDate)
+		s.Equal(&domain.GrantParameters{
+			Role:    "editor",
+			Options: &domain.AppealOptions{ExpirationDate: &requestedExpirationDate},
+		}, actualResult.Requested)
+	})
+}
+
+func (s *ServiceTestSuite) TestMakeActionByFilter() {
+	action := domain.ApprovalAction{
+		ApprovalName: "approval_1",
+		Actor:        "approver@email.com",
+		Action:       domain.AppealActionNameReject,
+	}
+
+	s.Run("should return error if actor doesn't match the authenticated user", func() {
+		ctx := auth.NewContext(context.Background(), "another.approver@email.com")
+		actualResult, actualError := s.service.MakeActionByFilter(ctx, map[string]interface{}{"user": "user@email.com"}, action)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrActorMismatch.Error())
+		s.mockRepository.AssertNotCalled(s.T(), "Find", mock.Anything, mock.Anything)
+	})
+
+	s.Run("should return error if filter is broad and not explicitly allowed", func() {
+		actualResult, actualError := s.service.MakeActionByFilter(context.Background(), map[string]interface{}{"role": "viewer"}, action)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrBroadFilter.Error())
+	})
+
+	s.Run("should return error if got error from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualResult, actualError := s.service.MakeActionByFilter(context.Background(), map[string]interface{}{"allow_broad": true}, action)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	newAppeal := func(id uint, approvers []string) *domain.Appeal {
+		return &domain.Appeal{
+			ID:       id,
+			Status:   domain.AppealStatusPending,
+			Resource: &domain.Resource{URN: "urn"},
+			Approvals: []*domain.Approval{
+				{
+					Name:      "approval_1",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: approvers,
+				},
+			},
+		}
+	}
+	expectedFilters := map[string]interface{}{
+		"resource_id":    uint(1),
+		"statuses":       []string{domain.AppealStatusPending},
+		"with_approvals": true,
+	}
+
+	s.Run("should make action on the matching appeals in a single transaction", func() {
+		appeal1 := newAppeal(1, []string{action.Actor})
+		appeal2 := newAppeal(2, []string{"other@email.com"})
+		appeal3 := newAppeal(3, []string{action.Actor})
+		s.mockRepository.On("Find", mock.Anything, expectedFilters).Return([]*domain.Appeal{appeal1, appeal2, appeal3}, nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("Update", mock.Anything, appeal1).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, appeal3).Return(nil).Once()
+		s.expectOutboxMessage()
+		s.expectOutboxMessage()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Twice()
+
+		actualResult, actualError := s.service.MakeActionByFilter(context.Background(), map[string]interface{}{"resource_id": uint(1)}, action)
+
+		s.NoError(actualError)
+		s.Equal([]*domain.Appeal{appeal1, appeal3}, actualResult)
+		s.Equal(domain.AppealStatusRejected, appeal1.Status)
+		s.Equal(domain.AppealStatusPending, appeal2.Status)
+		s.Equal(domain.AppealStatusRejected, appeal3.Status)
+		s.mockRepository.AssertNotCalled(s.T(), "GetByID", mock.Anything, mock.Anything)
+	})
+
+	s.Run("should not make action on any appeal if it can't be made on one of them", func() {
+		appeal1 := newAppeal(1, []string{action.Actor})
+		appeal2 := newAppeal(2, []string{action.Actor})
+		appeal2.Status = domain.AppealStatusCanceled
+		s.mockRepository.On("Find", mock.Anything, expectedFilters).Return([]*domain.Appeal{appeal1, appeal2}, nil).Once()
+
+		actualResult, actualError := s.service.MakeActionByFilter(context.Background(), map[string]interface{}{"resource_id": uint(1)}, action)
+
+		s.Nil(actualResult)
+		var bulkErr *appeal.BulkActionError
+		s.True(errors.As(actualError, &bulkErr))
+		s.Equal(map[uint]error{2: appeal.ErrAppealStatusCanceled}, bulkErr.Failed)
+		s.Equal(domain.AppealStatusPending, appeal1.Status)
+		s.Equal(domain.ApprovalStatusPending, appeal1.Approvals[0].Status)
+	})
+
+	s.Run("should return error if the appeals fail to be stored", func() {
+		appeal1 := newAppeal(1, []string{action.Actor})
+		appeal2 := newAppeal(2, []string{action.Actor})
+		expectedError := errors.New("update error")
+		s.mockRepository.On("Find", mock.Anything, expectedFilters).Return([]*domain.Appeal{appeal1, appeal2}, nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("Update", mock.Anything, appeal1).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, appeal2).Return(expectedError).Once()
+
+		actualResult, actualError := s.service.MakeActionByFilter(context.Background(), map[string]interface{}{"resource_id": uint(1)}, action)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, expectedError)
+	})
+
+	s.Run("should make action on the appeals of the approvers delegating to the actor", func() {
+		mockDelegationService := new(mocks.ApprovalDelegationService)
+		s.service.DelegationService = mockDelegationService
+		defer func() { s.service.DelegationService = nil }()
+
+		delegateAction := action
+		delegateAction.Actor = "delegate@email.com"
+		appeal1 := newAppeal(1, []string{action.Actor})
+		appeal2 := newAppeal(2, []string{"other@email.com"})
+		s.mockRepository.On("Find", mock.Anything, expectedFilters).Return([]*domain.Appeal{appeal1, appeal2}, nil).Once()
+		mockDelegationService.On("GetActiveDelegators", mock.Anything, delegateAction.Actor).Return([]string{action.Actor}, nil)
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("Update", mock.Anything, appeal1).Return(nil).Once()
+		s.expectOutboxMessage()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := s.service.MakeActionByFilter(context.Background(), map[string]interface{}{"resource_id": uint(1)}, delegateAction)
+
+		s.NoError(actualError)
+		s.Equal([]*domain.Appeal{appeal1}, actualResult)
+		s.Equal(domain.AppealStatusRejected, appeal1.Status)
+		s.Equal(domain.AppealStatusPending, appeal2.Status)
+	})
+
+	s.Run("should not make action on any appeal if the delegations can't be checked", func() {
+		mockDelegationService := new(mocks.ApprovalDelegationService)
+		s.service.DelegationService = mockDelegationService
+		defer func() { s.service.DelegationService = nil }()
+
+		delegateAction := action
+		delegateAction.Actor = "delegate@email.com"
+		appeal1 := newAppeal(1, []string{action.Actor})
+		expectedError := errors.New("delegation error")
+		s.mockRepository.On("Find", mock.Anything, expectedFilters).Return([]*domain.Appeal{appeal1}, nil).Once()
+		mockDelegationService.On("GetActiveDelegators", mock.Anything, delegateAction.Actor).Return(nil, expectedError).Once()
+
+		actualResult, actualError := s.service.MakeActionByFilter(context.Background(), map[string]interface{}{"resource_id": uint(1)}, delegateAction)
+
+		s.Nil(actualResult)
+		var bulkErr *appeal.BulkActionError
+		s.True(errors.As(actualError, &bulkErr))
+		s.ErrorIs(bulkErr.Failed[1], expectedError)
+		s.Equal(domain.AppealStatusPending, appeal1.Status)
+	})
+}
+
+func (s *ServiceTestSuite) TestMakeGroupAction() {
+	action := domain.ApprovalAction{
+		ApprovalName: "approval_1",
+		Actor:        "approver@email.com",
+		Action:       domain.AppealActionNameReject,
+	}
+
+	s.Run("should return error if group id is empty", func() {
+		actualResult, actualError := s.service.MakeGroupAction(context.Background(), "", action)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrGroupIDEmptyParam.Error())
+	})
+
+	s.Run("should return error if the group has no appeals", func() {
+		s.mockRepository.On("Find", mock.Anything, map[string]interface{}{"group_id": "group-1"}).Return([]*domain.Appeal{}, nil).Once()
+
+		actualResult, actualError := s.service.MakeGroupAction(context.Background(), "group-1", action)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAppealGroupNotFound.Error())
+	})
+
+	s.Run("should make action on the appeals waiting for the actor and skip the others", func() {
+		newAppeal := func(id uint, approvalName string) *domain.Appeal {
+			return &domain.Appeal{
+				ID:       id,
+				Status:   domain.AppealStatusPending,
+				GroupID:  "group-1",
+				Resource: &domain.Resource{URN: "urn"},
+				Approvals: []*domain.Approval{
+					{
+						Name:      approvalName,
+						Status:    domain.ApprovalStatusPending,
+						Approvers: []string{action.Actor},
+					},
+				},
+			}
+		}
+		appeal1 := newAppeal(1, "approval_1")
+		appeal2 := newAppeal(2, "approval_2")
+		appeal4 := newAppeal(4, "approval_1")
+		s.mockRepository.On("Find", mock.Anything, map[string]interface{}{"group_id": "group-1"}).Return([]*domain.Appeal{
+			{ID: 1, Status: domain.AppealStatusPending},
+			{ID: 2, Status: domain.AppealStatusPending},
+			{ID: 3, Status: domain.AppealStatusActive},
+			{ID: 4, Status: domain.AppealStatusPending},
+		}, nil).Twice()
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appeal1, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(2)).Return(appeal2, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(4)).Return(appeal4, nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("Update", mock.Anything, appeal1).Return(nil).Once()
+		expectedError := errors.New("update error")
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, appeal4).Return(expectedError).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, appeal4).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := s.service.MakeGroupAction(context.Background(), "group-1", action)
+
+		s.NoError(actualError)
+		s.Equal([]*domain.Appeal{appeal1}, actualResult.Actioned)
+		s.Equal(domain.AppealStatusRejected, appeal1.Status)
+		s.Equal(domain.AppealStatusPending, appeal2.Status)
+		s.Contains(actualResult.Skipped, uint(2))
+		s.Contains(actualResult.Skipped, uint(3))
+		s.Equal(map[uint]string{4: expectedError.Error()}, actualResult.Failed)
+	})
+
+	s.Run("should summarize the group to the requester once the last pending appeal is resolved", func() {
+		pendingAppeal := &domain.Appeal{
+			ID:       2,
+			User:     "user@email.com",
+			Status:   domain.AppealStatusPending,
+			GroupID:  "group-1",
+			Resource: &domain.Resource{URN: "urn"},
+			Approvals: []*domain.Approval{
+				{Name: "approval_1", Status: domain.ApprovalStatusPending, Approvers: []string{action.Actor}},
+			},
+		}
+		s.mockRepository.On("Find", mock.Anything, map[string]interface{}{"group_id": "group-1"}).Return([]*domain.Appeal{
+			{ID: 1, User: "user@email.com", Status: domain.AppealStatusActive},
+			{ID: 2, User: "user@email.com", Status: domain.AppealStatusPending},
+			{ID: 3, User: "user@email.com", Status: domain.AppealStatusCanceled},
+			{ID: 4, User: "other-user@email.com", Status: domain.AppealStatusPending},
+		}, nil).Twice()
+		s.mockRepository.On("GetByID", mock.Anything, uint(2)).Return(pendingAppeal, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(4)).Return(&domain.Appeal{
+			ID:     4,
+			Status: domain.AppealStatusPending,
+			Approvals: []*domain.Approval{
+				{Name: "approval_1", Status: domain.ApprovalStatusPending, Approvers: []string{"another-approver@email.com"}},
+			},
+		}, nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, pendingAppeal).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{
+			{User: "user@email.com", Message: "Your appeal to urn is rejected"},
+			{User: "user@email.com", Message: "All 3 appeals you submitted together are resolved: 1 approved, 1 rejected, 1 canceled"},
+		}).Return(nil).Once()
+
+		actualResult, actualError := s.service.MakeGroupAction(context.Background(), "group-1", action)
+
+		s.NoError(actualError)
+		s.Equal([]*domain.Appeal{pendingAppeal}, actualResult.Actioned)
+		s.mockNotifier.AssertExpectations(s.T())
+	})
+
+	s.Run("should make action on the appeals of the approvers delegating to the actor", func() {
+		mockDelegationService := new(mocks.ApprovalDelegationService)
+		s.service.DelegationService = mockDelegationService
+		defer func() { s.service.DelegationService = nil }()
+
+		delegateAction := action
+		delegateAction.Actor = "delegate@email.com"
+		newAppeal := func(id uint, approver string) *domain.Appeal {
+			return &domain.Appeal{
+				ID:       id,
+				Status:   domain.AppealStatusPending,
+				GroupID:  "group-1",
+				Resource: &domain.Resource{URN: "urn"},
+				Approvals: []*domain.Approval{
+					{Name: "approval_1", Status: domain.ApprovalStatusPending, Approvers: []string{approver}},
+				},
+			}
+		}
+		appeal1 := newAppeal(1, action.Actor)
+		appeal2 := newAppeal(2, "other@email.com")
+		s.mockRepository.On("Find", mock.Anything, map[string]interface{}{"group_id": "group-1"}).Return([]*domain.Appeal{
+			{ID: 1, Status: domain.AppealStatusPending},
+			{ID: 2, Status: domain.AppealStatusPending},
+		}, nil).Twice()
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appeal1, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(2)).Return(appeal2, nil).Once()
+		mockDelegationService.On("GetActiveDelegators", mock.Anything, delegateAction.Actor).Return([]string{action.Actor}, nil)
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, appeal1).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := s.service.MakeGroupAction(context.Background(), "group-1", delegateAction)
+
+		s.NoError(actualError)
+		s.Equal([]*domain.Appeal{appeal1}, actualResult.Actioned)
+		s.Equal(domain.AppealStatusRejected, appeal1.Status)
+		s.Equal(map[uint]string{2: appeal.ErrActionForbidden.Error()}, actualResult.Skipped)
+		s.Equal(domain.ApprovalActedAsDelegate, appeal1.Approvals[0].GetProvenance().ActedAs)
+	})
+}
+
+func (s *ServiceTestSuite) TestAdminApprove() {
+	timeNow := time.Now()
+	appeal.TimeNow = func() time.Time {
+		return timeNow
+	}
+	admin := "admin@email.com"
+	reason := "approver is on leave"
+
+	s.Run("should return error if reason is empty", func() {
+		actualResult, actualError := s.service.AdminApprove(context.Background(), 1, "approval_1", admin, " ")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrOverrideReasonRequired.Error())
+	})
+
+	s.Run("should return error if actor doesn't match the authenticated user", func() {
+		ctx := auth.NewContext(context.Background(), "user@email.com")
+		actualResult, actualError := s.service.AdminApprove(ctx, 1, "approval_1", admin, reason)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrActorMismatch.Error())
+	})
+
+	s.Run("should return error if actor is not an admin", func() {
+		actualResult, actualError := s.service.AdminApprove(context.Background(), 1, "approval_1", "user@email.com", reason)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrOverrideForbidden.Error())
+	})
+
+	s.Run("should return error if appeal not found", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(nil, nil).Once()
+
+		actualResult, actualError := s.service.AdminApprove(context.Background(), 1, "approval_1", admin, reason)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAppealNotFound.Error())
+	})
+
+	s.Run("should approve the step on behalf of the approvers and flag it as overridden", func() {
+		appealDetails := &domain.Appeal{
+			ID:         1,
+			User:       "user@email.com",
+			ResourceID: 1,
+			Resource: &domain.Resource{
+				ID:  1,
+				URN: "urn",
+			},
+			Status: domain.AppealStatusPending,
+			Approvals: []*domain.Approval{
+				{
+					Name:      "approval_1",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{"approver@email.com"},
+				},
+			},
+		}
+		expectedResult := &domain.Appeal{
+			ID:         1,
+			User:       "user@email.com",
+			ResourceID: 1,
+			Resource: &domain.Resource{
+				ID:  1,
+				URN: "urn",
+			},
+			Status: domain.AppealStatusActive,
+			Approvals: []*domain.Approval{
+				{
+					Name:            "approval_1",
+					Status:          domain.ApprovalStatusApproved,
+					Approvers:       []string{"approver@email.com"},
+					Actor:           &admin,
+					Reason:          reason,
+					IsOverridden:    true,
+					UpdatedAt:       timeNow,
+					StatusChangedAt: &timeNow,
+				},
+			},
+		}
+		expectedNotifications := []domain.Notification{
+			{
+				User:    "user@email.com",
+				Message: "Your appeal to urn has been approved",
+			},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+		s.mockApprovalService.On("AdvanceApproval", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockProviderService.On("GetTeamQuota", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		s.mockProviderService.On("GrantAccess", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, expectedResult).Return(nil).Once()
+		s.mockNotifier.On("Notify", expectedNotifications).Return(nil).Once()
+
+		actualResult, actualError := s.service.AdminApprove(context.Background(), 1, "approval_1", admin, reason)
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+	})
+}
+
+// func (s *ServiceTestSuite) TestCancel() {
+// 	s.Run("should return error from")
+// }
+
+func (s *ServiceTestSuite) TestGetApprovalProvenance() {
+	s.Run("should return error if the appeal is not found", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(nil, nil).Once()
+
+		actualResult, actualError := s.service.GetApprovalProvenance(context.Background(), 1)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAppealNotFound.Error())
+	})
+
+	s.Run("should return the nominal and effective approvers of every step", func() {
+		approver := "approver@email.com"
+		admin := "admin@email.com"
+		actedAt := time.Now()
+		// the later writes to the steps, e.g. the reminders, don't change when the steps were acted on
+		updatedAt := actedAt.Add(time.Hour)
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{
+			ID: 1,
+			Approvals: []*domain.Approval{
+				{Name: "check_pii", Status: domain.ApprovalStatusSkipped, UpdatedAt: actedAt},
+				{Name: "owner_approval", Status: domain.ApprovalStatusApproved, Approvers: []string{approver}, Actor: &approver, StatusChangedAt: &actedAt, UpdatedAt: updatedAt},
+				{Name: "team_lead_approval", Status: domain.ApprovalStatusApproved, Approvers: []string{"lead@email.com"}, Actor: &admin, IsOverridden: true, Reason: "lead is on leave", StatusChangedAt: &actedAt, UpdatedAt: updatedAt},
+				{Name: "security_approval", Status: domain.ApprovalStatusPending, Approvers: []string{"security@email.com"}},
+			},
+		}, nil).Once()
+		expectedResult := []*domain.ApprovalProvenance{
+			{
+				ApprovalName: "check_pii",
+				Status:       domain.ApprovalStatusSkipped,
+				ActedAs:      domain.ApprovalActedAsSystem,
+				ActedAt:      &actedAt,
+			},
+			{
+				ApprovalName:      "owner_approval",
+				Status:            domain.ApprovalStatusApproved,
+				NominalApprovers:  []string{approver},
+				EffectiveApprover: approver,
+				ActedAs:           domain.ApprovalActedAsApprover,
+				ActedAt:           &actedAt,
+			},
+			{
+				ApprovalName:      "team_lead_approval",
+				Status:            domain.ApprovalStatusApproved,
+				NominalApprovers:  []string{"lead@email.com"},
+				EffectiveApprover: admin,
+				ActedAs:           domain.ApprovalActedAsAdminOverride,
+				Reason:            "lead is on leave",
+				ActedAt:           &actedAt,
+			},
+			{
+				ApprovalName:     "security_approval",
+				Status:           domain.ApprovalStatusPending,
+				NominalApprovers: []string{"security@email.com"},
+			},
+		}
+
+		actualResult, actualError := s.service.GetApprovalProvenance(context.Background(), 1)
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+	})
+}
+
+func (s *ServiceTestSuite) TestRevertApproval() {
+	approver := "approver@email.com"
+	newAppeal := func(status string, approvals ...*domain.Approval) *domain.Appeal {
+		return &domain.Appeal{
+			ID:        1,
+			User:      "user@email.com",
+			Status:    status,
+			Approvals: approvals,
+		}
+	}
+	approved := func(name, actor string) *domain.Approval {
+		return &domain.Approval{
+			Name:      name,
+			Status:    domain.ApprovalStatusApproved,
+			Actor:     &actor,
+			Reason:    "looks good",
+			Approvers: []string{actor},
+		}
+	}
+	pending := func(name string) *domain.Approval {
+		return &domain.Approval{
+			Name:      name,
+			Status:    domain.ApprovalStatusPending,
+			Approvers: []string{"next.approver@email.com"},
+		}
+	}
+
+	s.Run("should return error if the params are invalid", func() {
+		_, actualError := s.service.RevertApproval(context.Background(), 0, "step_1", approver)
+		s.EqualError(actualError, appeal.ErrAppealIDEmptyParam.Error())
+
+		_, actualError = s.service.RevertApproval(context.Background(), 1, "step_1", "invalid")
+		s.True(errors.Is(actualError, appeal.ErrInvalidUser))
+	})
+
+	s.Run("should return error if the appeal is not found", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(nil, nil).Once()
+
+		actualResult, actualError := s.service.RevertApproval(context.Background(), 1, "step_1", approver)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAppealNotFound.Error())
+	})
+
+	s.Run("should return error if the revert is not allowed", func() {
+		testCases := []struct {
+			name          string
+			appeal        *domain.Appeal
+			expectedError error
+		}{
+			{
+				name:          "appeal is finalized",
+				appeal:        newAppeal(domain.AppealStatusActive, approved("step_1", approver)),
+				expectedError: appeal.ErrAppealStatusApproved,
+			},
+			{
+				name:          "approval step is not approved",
+				appeal:        newAppeal(domain.AppealStatusPending, pending("step_1")),
+				expectedError: appeal.ErrRevertApprovalNotApproved,
+			},
+			{
+				name:          "approval step is approved by another approver",
+				appeal:        newAppeal(domain.AppealStatusPending, approved("step_1", "other@email.com"), pending("step_2")),
+				expectedError: appeal.ErrRevertForbidden,
+			},
+			{
+				name:          "next approval step is actioned",
+				appeal:        newAppeal(domain.AppealStatusPending, approved("step_1", approver), approved("step_2", "next.approver@email.com"), pending("step_3")),
+				expectedError: appeal.ErrRevertNextStepActioned,
+			},
+			{
+				name:          "approval step is not found",
+				appeal:        newAppeal(domain.AppealStatusPending, approved("step_0", approver), pending("step_2")),
+				expectedError: appeal.ErrApprovalNameNotFound,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(tc.appeal, nil).Once()
+
+				actualResult, actualError := s.service.RevertApproval(context.Background(), 1, "step_1", approver)
+
+				s.Nil(actualResult)
+				s.EqualError(actualError, tc.expectedError.Error())
+			})
+		}
+	})
+
+	s.Run("should move the approval step back to pending and record the revert", func() {
+		appealDetails := newAppeal(domain.AppealStatusPending, approved("step_1", approver), pending("step_2"))
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("AddComment", mock.Anything, mock.MatchedBy(func(c *domain.AppealComment) bool {
+			return c.AppealID == 1 && c.CreatedBy == approver && c.Visibility == domain.CommentVisibilityPrivate
+		})).Return(nil).Once()
+
+		actualResult, actualError := s.service.RevertApproval(context.Background(), 1, "step_1", approver)
+
+		s.Nil(actualError)
+		s.Equal(domain.ApprovalStatusPending, actualResult.Approvals[0].Status)
+		s.Nil(actualResult.Approvals[0].Actor)
+		s.Empty(actualResult.Approvals[0].Reason)
+		s.Equal("step_1", actualResult.GetNextPendingApproval().Name)
+		s.mockRepository.AssertExpectations(s.T())
+	})
+
+	s.Run("should return error if the revert can't be recorded along with the update", func() {
+		appealDetails := newAppeal(domain.AppealStatusPending, approved("step_1", approver), pending("step_2"))
+		expectedError := errors.New("comment error")
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("AddComment", mock.Anything, mock.Anything).Return(expectedError).Once()
+
+		actualResult, actualError := s.service.RevertApproval(context.Background(), 1, "step_1", approver)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, expectedError)
+	})
+}
+
+func (s *ServiceTestSuite) TestUndoApproval() {
+	approver := "approver@email.com"
+	withinWindow := s.now.Add(10 * time.Minute)
+	pastWindow := s.now.Add(-time.Second)
+	newAppeal := func(undoDeadline *time.Time) *domain.Appeal {
+		actor := approver
+		return &domain.Appeal{
+			ID:              1,
+			User:            "user@email.com",
+			Status:          domain.AppealStatusActive,
+			Resource:        &domain.Resource{URN: "urn"},
+			AccessScheduled: true,
+			UndoDeadline:    undoDeadline,
+			Approvals: []*domain.Approval{
+				{Name: "step_1", Status: domain.ApprovalStatusApproved, Actor: &actor, Approvers: []string{approver}},
+				{Name: "step_2", Status: domain.ApprovalStatusApproved, Actor: &actor, Reason: "looks good", Approvers: []string{approver}},
+			},
+		}
+	}
+
+	s.Run("should return error if the params are invalid", func() {
+		_, actualError := s.service.UndoApproval(context.Background(), 0, approver)
+		s.EqualError(actualError, appeal.ErrAppealIDEmptyParam.Error())
+
+		_, actualError = s.service.UndoApproval(context.Background(), 1, "invalid")
+		s.True(errors.Is(actualError, appeal.ErrInvalidUser))
+	})
+
+	s.Run("should return error if the undo is not allowed", func() {
+		granted := newAppeal(&withinWindow)
+		granted.AccessScheduled = false
+		testCases := []struct {
+			name          string
+			appeal        *domain.Appeal
+			actor         string
+			expectedError error
+		}{
+			{
+				name:          "appeal without undo window",
+				appeal:        newAppeal(nil),
+				actor:         approver,
+				expectedError: appeal.ErrUndoWindowClosed,
+			},
+			{
+				name:          "undo window has ended",
+				appeal:        newAppeal(&pastWindow),
+				actor:         approver,
+				expectedError: appeal.ErrUndoWindowClosed,
+			},
+			{
+				name:          "access is already granted",
+				appeal:        granted,
+				actor:         approver,
+				expectedError: appeal.ErrUndoWindowClosed,
+			},
+			{
+				name:          "actor is not the final approver",
+				appeal:        newAppeal(&withinWindow),
+				actor:         "user@email.com",
+				expectedError: appeal.ErrUndoForbidden,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(tc.appeal, nil).Once()
+
+				actualResult, actualError := s.service.UndoApproval(context.Background(), 1, tc.actor)
+
+				s.Nil(actualResult)
+				s.ErrorIs(actualError, tc.expectedError)
+			})
+		}
+	})
+
+	s.Run("should move the final approval step and the appeal back to pending", func() {
+		for _, actor := range []string{approver, "admin@email.com"} {
+			appealDetails := newAppeal(&withinWindow)
+			s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+			s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+			s.expectOutboxMessage()
+			s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+			s.mockRepository.On("AddComment", mock.Anything, mock.MatchedBy(func(c *domain.AppealComment) bool {
+				return c.AppealID == 1 && c.CreatedBy == actor && c.Visibility == domain.CommentVisibilityPrivate
+			})).Return(nil).Once()
+			s.mockNotifier.On("Notify", []domain.Notification{
+				{User: "user@email.com", Message: "The approval of your appeal to urn has been undone, it's pending again"},
+				{User: approver, Message: "You have an appeal from user@email.com to access urn"},
+			}).Return(nil).Once()
+
+			actualResult, actualError := s.service.UndoApproval(context.Background(), 1, actor)
+
+			s.Nil(actualError)
+			s.Equal(domain.AppealStatusPending, actualResult.Status)
+			s.False(actualResult.AccessScheduled)
+			s.Nil(actualResult.UndoDeadline)
+			s.Equal(domain.ApprovalStatusApproved, actualResult.Approvals[0].Status)
+			s.Equal(domain.ApprovalStatusPending, actualResult.Approvals[1].Status)
+			s.Nil(actualResult.Approvals[1].Actor)
+			s.Empty(actualResult.Approvals[1].Reason)
+			s.mockNotifier.AssertExpectations(s.T())
+		}
+	})
+}
+
+func (s *ServiceTestSuite) TestAcknowledgeApproval() {
+	approver := "approver@email.com"
+	newAppeal := func(status string, approvals ...*domain.Approval) *domain.Appeal {
+		return &domain.Appeal{
+			ID:        1,
+			User:      "user@email.com",
+			Status:    status,
+			Resource:  &domain.Resource{URN: "urn"},
+			Approvals: approvals,
+		}
+	}
+	approval := func(name, status, acknowledgedBy string) *domain.Approval {
+		return &domain.Approval{
+			Name:           name,
+			Status:         status,
+			Approvers:      []string{approver, "another.approver@email.com"},
+			AcknowledgedBy: acknowledgedBy,
+		}
+	}
+
+	s.Run("should return error if the params are invalid", func() {
+		_, actualError := s.service.AcknowledgeApproval(context.Background(), 0, "step_1", approver)
+		s.EqualError(actualError, appeal.ErrAppealIDEmptyParam.Error())
+
+		_, actualError = s.service.AcknowledgeApproval(context.Background(), 1, "step_1", "invalid")
+		s.True(errors.Is(actualError, appeal.ErrInvalidUser))
+	})
+
+	s.Run("should return error if the acknowledgment is not allowed", func() {
+		testCases := []struct {
+			name          string
+			appeal        *domain.Appeal
+			actor         string
+			expectedError error
+		}{
+			{
+				name:          "appeal is finalized",
+				appeal:        newAppeal(domain.AppealStatusActive, approval("step_1", domain.ApprovalStatusApproved, "")),
+				actor:         approver,
+				expectedError: appeal.ErrAppealStatusApproved,
+			},
+			{
+				name:          "approval step is already approved",
+				appeal:        newAppeal(domain.AppealStatusPending, approval("step_1", domain.ApprovalStatusApproved, ""), approval("step_2", domain.ApprovalStatusPending, "")),
+				actor:         approver,
+				expectedError: appeal.ErrApprovalStatusApproved,
+			},
+			{
+				name:          "previous approval step is pending",
+				appeal:        newAppeal(domain.AppealStatusPending, approval("step_0", domain.ApprovalStatusPending, ""), approval("step_1", domain.ApprovalStatusPending, "")),
+				actor:         approver,
+				expectedError: appeal.ErrApprovalDependencyIsPending,
+			},
+			{
+				name:          "actor is not an approver",
+				appeal:        newAppeal(domain.AppealStatusPending, approval("step_1", domain.ApprovalStatusPending, "")),
+				actor:         "user@email.com",
+				expectedError: appeal.ErrActionForbidden,
+			},
+			{
+				name:          "approval step is acknowledged by another approver",
+				appeal:        newAppeal(domain.AppealStatusPending, approval("step_1", domain.ApprovalStatusPending, "another.approver@email.com")),
+				actor:         approver,
+				expectedError: appeal.ErrApprovalAlreadyAcknowledged,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(tc.appeal, nil).Once()
+
+				actualResult, actualError := s.service.AcknowledgeApproval(context.Background(), 1, "step_1", tc.actor)
+
+				s.Nil(actualResult)
+				s.ErrorIs(actualError, tc.expectedError)
+			})
+		}
+	})
+
+	s.Run("should record the acknowledgment without advancing the approval step", func() {
+		appealDetails := newAppeal(domain.AppealStatusPending, approval("step_1", domain.ApprovalStatusPending, ""))
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("AddComment", mock.Anything, mock.MatchedBy(func(c *domain.AppealComment) bool {
+			return c.AppealID == 1 && c.CreatedBy == approver && c.Visibility == domain.CommentVisibilityPublic
+		})).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "user@email.com",
+			Message: "approver@email.com is reviewing your appeal to urn",
+		}}).Return(nil).Once()
+
+		actualResult, actualError := s.service.AcknowledgeApproval(context.Background(), 1, "step_1", approver)
+
+		s.Nil(actualError)
+		acknowledged := actualResult.Approvals[0]
+		s.Equal(domain.ApprovalStatusPending, acknowledged.Status)
+		s.Nil(acknowledged.Actor)
+		s.Equal(approver, acknowledged.AcknowledgedBy)
+		s.Equal(s.now, *acknowledged.AcknowledgedAt)
+		s.mockRepository.AssertExpectations(s.T())
+		s.mockNotifier.AssertExpectations(s.T())
+	})
+
+	s.Run("should not record the acknowledgment again", func() {
+		appealDetails := newAppeal(domain.AppealStatusPending, approval("step_1", domain.ApprovalStatusPending, approver))
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+		callsBefore := len(s.mockRepository.Calls)
+
+		actualResult, actualError := s.service.AcknowledgeApproval(context.Background(), 1, "step_1", approver)
+
+		s.Nil(actualError)
+		s.Equal(appealDetails, actualResult)
+		s.Len(s.mockRepository.Calls, callsBefore+1)
+	})
+}
+
+func (s *ServiceTestSuite) TestPreviewRevoke() {
+	s.Run("should return error if filter is broad and not explicitly allowed", func() {
+		actualResult, actualError := s.service.PreviewRevoke(context.Background(), map[string]interface{}{"role": "viewer"})
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrBroadFilter.Error())
+	})
+
+	s.Run("should return the active appeals matching the filters without revoking them", func() {
+		activeAppeal := &domain.Appeal{
+			ID:       1,
+			User:     "user@email.com",
+			Status:   domain.AppealStatusActive,
+			Resource: &domain.Resource{URN: "urn_1"},
+		}
+		expectedFilters := map[string]interface{}{
+			"user":     "user@email.com",
+			"statuses": []string{domain.AppealStatusActive},
+		}
+		s.mockRepository.On("Find", mock.Anything, expectedFilters).Return([]*domain.Appeal{{ID: 1}, {ID: 2}}, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(activeAppeal, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(2)).Return(&domain.Appeal{ID: 2, Status: domain.AppealStatusTerminated}, nil).Once()
+
+		actualResult, actualError := s.service.PreviewRevoke(context.Background(), map[string]interface{}{"user": "user@email.com"})
+
+		s.Nil(actualError)
+		s.Equal([]*domain.Appeal{activeAppeal}, actualResult)
+		s.mockProviderService.AssertNotCalled(s.T(), "RevokeAccess", mock.Anything, mock.Anything)
+		s.mockRepository.AssertNotCalled(s.T(), "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func (s *ServiceTestSuite) TestRevokeByFilter() {
+	actor := "admin@email.com"
+	reason := "offboarding"
+
+	s.Run("should return error if filter is broad and not explicitly allowed", func() {
+		actualResult, actualError := s.service.RevokeByFilter(context.Background(), map[string]interface{}{}, actor, reason)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrBroadFilter.Error())
+	})
+
+	s.Run("should revoke the appeals previewed with the same filters and summarize the failures", func() {
+		newAppeal := func(id uint) *domain.Appeal {
+			return &domain.Appeal{
+				ID:         id,
+				User:       "user@email.com",
+				ResourceID: id,
+				Status:     domain.AppealStatusActive,
+				Resource:   &domain.Resource{ID: id, URN: fmt.Sprintf("urn_%d", id)},
+			}
+		}
+		appeal1 := newAppeal(1)
+		appeal2 := newAppeal(2)
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{{ID: 1}, {ID: 2}}, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appeal1, nil).Twice()
+		s.mockRepository.On("GetByID", mock.Anything, uint(2)).Return(appeal2, nil).Twice()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, appeal1).Return(nil).Once()
+		expectedError := errors.New("provider error")
+		s.mockProviderService.On("RevokeAccess", mock.Anything, appeal2).Return(expectedError).Once()
+		// the failed revocation is kept pending for a retry
+		s.mockRepository.On("Update", mock.Anything, mock.Anything).Return(nil).Twice()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := s.service.RevokeByFilter(context.Background(), map[string]interface{}{"user": "user@email.com"}, actor, reason)
+
+		s.Len(actualResult, 1)
+		s.Equal(uint(1), actualResult[0].ID)
+		s.Equal(domain.AppealStatusTerminated, actualResult[0].Status)
+		s.Equal(actor, actualResult[0].RevokedBy)
+		var bulkErr *appeal.BulkActionError
+		s.True(errors.As(actualError, &bulkErr))
+		s.Equal(map[uint]error{2: expectedError}, bulkErr.Failed)
+	})
+}
+
+func (s *ServiceTestSuite) TestRevoke() {
+	s.Run("should return error if got any while getting appeal details", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("GetByID", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualResult, actualError := s.service.Revoke(context.Background(), 0, "", "")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return error if appeal not found", func() {
+		s.mockRepository.On("GetByID", mock.Anything, mock.Anything).Return(nil, nil).Once()
+		expectedError := appeal.ErrAppealNotFound
+
+		actualResult, actualError := s.service.Revoke(context.Background(), 0, "", "")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	appealID := uint(1)
+	actor := "user@email.com"
+	reason := "test-reason"
+
+	appealDetails := &domain.Appeal{
+		ID:         appealID,
+		ResourceID: 1,
+		Status:     domain.AppealStatusActive,
+		Resource: &domain.Resource{
+			ID:  1,
+			URN: "urn",
+		},
+	}
+
+	s.Run("should return the appeal without revoking it again if it's already revoked", func() {
+		revokedAppeal := &domain.Appeal{
+			ID:        appealID,
+			Status:    domain.AppealStatusTerminated,
+			RevokedBy: actor,
+		}
+		s.mockRepository.On("GetByID", mock.Anything, appealID).Return(revokedAppeal, nil).Once()
+
+		actualResult, actualError := s.service.Revoke(context.Background(), appealID, actor, reason)
+
+		s.Nil(actualError)
+		s.Equal(revokedAppeal, actualResult)
+		s.mockProviderService.AssertNotCalled(s.T(), "RevokeAccess", mock.Anything, revokedAppeal)
+		s.mockNotifier.AssertNotCalled(s.T(), "Notify", mock.Anything)
+	})
+
+	s.Run("should return error if the appeal is not active", func() {
+		for _, status := range []string{domain.AppealStatusPending, domain.AppealStatusCanceled, domain.AppealStatusRejected} {
+			s.mockRepository.On("GetByID", mock.Anything, appealID).Return(&domain.Appeal{ID: appealID, Status: status}, nil).Once()
+
+			actualResult, actualError := s.service.Revoke(context.Background(), appealID, actor, reason)
+
+			s.Nil(actualResult)
+			s.True(errors.Is(actualError, appeal.ErrAppealNotActive))
+		}
+	})
+
+	s.Run("should return error and keep the appeal revocation pending if failed revoking the access from the provider", func() {
+		s.mockRepository.On("GetByID", mock.Anything, appealID).Return(appealDetails, nil).Once()
+		expectedError := errors.New("provider service error")
+		s.mockProviderService.On("RevokeAccess", mock.Anything, appealDetails).Return(expectedError).Once()
+		var pendingAppeal *domain.Appeal
+		s.mockRepository.On("Update", mock.Anything, mock.AnythingOfType("*domain.Appeal")).
+			Run(func(args mock.Arguments) { pendingAppeal = args.Get(1).(*domain.Appeal) }).
+			Return(nil).
+			Once()
+
+		actualResult, actualError := s.service.Revoke(context.Background(), appealID, actor, reason)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+		s.Equal(domain.AppealStatusRevocationPending, pendingAppeal.Status)
+		s.Equal(1, pendingAppeal.RevocationAttempts)
+		s.Equal(expectedError.Error(), pendingAppeal.RevocationError)
+		s.Equal(actor, pendingAppeal.RevokedBy)
+		s.Equal(reason, pendingAppeal.RevokeReason)
+		s.Equal(domain.AppealStatusActive, appealDetails.Status)
+	})
+
+	s.Run("should alert the admins once the revocation keeps failing", func() {
+		pendingAppeal := &domain.Appeal{
+			ID:                 appealID,
+			User:               "user@email.com",
+			Role:               "viewer",
+			Status:             domain.AppealStatusRevocationPending,
+			RevokedBy:          actor,
+			RevokeReason:       reason,
+			RevocationAttempts: 2,
+			Resource:           &domain.Resource{ID: 1, URN: "urn"},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, appealID).Return(pendingAppeal, nil).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, pendingAppeal).Return(errors.New("provider is unavailable")).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.AnythingOfType("*domain.Appeal")).Return(nil).Once()
+		expectedNotifications := []domain.Notification{{
+			User:     "admin@email.com",
+			Message:  "The access of user@email.com to urn as viewer is still granted after 3 failed attempts to revoke it: provider is unavailable",
+			Critical: true,
+		}}
+		s.mockNotifier.On("Notify", expectedNotifications).Return(nil).Once()
+
+		_, actualError := s.service.Revoke(context.Background(), appealID, domain.SystemActorName, "")
+
+		s.Error(actualError)
+		s.mockNotifier.AssertExpectations(s.T())
+	})
+
+	s.Run("should terminate the revocation pending appeal on a successful retry along with the original actor", func() {
+		pendingAppeal := &domain.Appeal{
+			ID:                 appealID,
+			Status:             domain.AppealStatusRevocationPending,
+			RevokedBy:          actor,
+			RevokeReason:       reason,
+			RevocationAttempts: 1,
+			RevocationError:    "provider is unavailable",
+			Resource:           &domain.Resource{ID: 1, URN: "urn"},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, appealID).Return(pendingAppeal, nil).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, pendingAppeal).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.AnythingOfType("*domain.Appeal")).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := s.service.Revoke(context.Background(), appealID, domain.SystemActorName, "")
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusTerminated, actualResult.Status)
+		s.Equal(actor, actualResult.RevokedBy)
+		s.Equal(reason, actualResult.RevokeReason)
+		s.Empty(actualResult.RevocationError)
+	})
+
+	s.Run("should return error and restore the access if got any while updating appeal", func() {
+		s.mockRepository.On("GetByID", mock.Anything, appealID).Return(appealDetails, nil).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, appealDetails).Return(nil).Once()
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("Update", mock.Anything, mock.Anything).Return(expectedError).Once()
+		s.mockProviderService.On("GrantAccess", mock.Anything, appealDetails).Return(nil).Once()
+
+		actualResult, actualError := s.service.Revoke(context.Background(), appealID, actor, reason)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+		s.mockProviderService.AssertExpectations(s.T())
+	})
+
+	s.Run("should return appeal and nil error on success", func() {
+		s.mockRepository.On("GetByID", mock.Anything, appealID).Return(appealDetails, nil).Once()
+		expectedAppeal := &domain.Appeal{}
+		*expectedAppeal = *appealDetails
+		expectedAppeal.Status = domain.AppealStatusTerminated
+		expectedAppeal.RevokedAt = s.now
+		expectedAppeal.RevokedBy = actor
+		expectedAppeal.RevokeReason = reason
+		s.mockRepository.On("Update", mock.Anything, expectedAppeal).Return(nil).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := s.service.Revoke(context.Background(), appealID, actor, reason)
+
+		s.Equal(expectedAppeal, actualResult)
+		s.Nil(actualError)
+	})
+
+	s.Run("should publish the status event to the subscribers of the appeal", func() {
+		sub, _ := s.service.StatusBroker.Subscribe([]uint{appealID}, 0)
+		defer sub.Close()
+		s.mockRepository.On("GetByID", mock.Anything, appealID).Return(appealDetails, nil).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		_, actualError := s.service.Revoke(context.Background(), appealID, actor, reason)
+
+		s.Nil(actualError)
+		actualEvent := <-sub.Events()
+		s.NotZero(actualEvent.ID)
+		s.Equal(appealID, actualEvent.AppealID)
+		s.Equal(domain.AppealStatusTerminated, actualEvent.Status)
+		s.Equal(domain.AppealStatusActive, actualEvent.PreviousStatus)
+		s.Equal(domain.AppealActionNameRevoke, actualEvent.Action)
+		s.Equal(actor, actualEvent.Actor)
+	})
+
+	s.Run("should not revoke the access if a pre-hook aborts it", func() {
+		defer func() { s.service.AccessHooks = nil }()
+		s.service.AccessHooks = []appeal.AccessHook{&appeal.AccessHookFuncs{
+			BeforeFunc: func(ctx context.Context, e *appeal.AccessHookEvent) error {
+				s.Equal(appeal.AccessOperationRevoke, e.Operation)
+				return errors.New("ticket creation failed")
+			},
+		}}
+		s.mockRepository.On("GetByID", mock.Anything, appealID).Return(appealDetails, nil).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.AnythingOfType("*domain.Appeal")).Return(nil).Once()
+
+		actualResult, actualError := s.service.Revoke(context.Background(), appealID, actor, reason)
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrAccessHookAborted)
+	})
+
+	s.Run("should revoke the access regardless of the post-hook result", func() {
+		defer func() { s.service.AccessHooks = nil }()
+		var postHookEvents []*appeal.AccessHookEvent
+		s.service.AccessHooks = []appeal.AccessHook{&appeal.AccessHookFuncs{
+			AfterFunc: func(ctx context.Context, e *appeal.AccessHookEvent) error {
+				postHookEvents = append(postHookEvents, e)
+				return errors.New("compliance system is unavailable")
+			},
+		}}
+		s.mockRepository.On("GetByID", mock.Anything, appealID).Return(appealDetails, nil).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := s.service.Revoke(context.Background(), appealID, actor, reason)
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusTerminated, actualResult.Status)
+		s.Equal([]*appeal.AccessHookEvent{{Operation: appeal.AccessOperationRevoke, Appeal: appealDetails}}, postHookEvents)
+	})
+
+	s.Run("should record the access change timing and the revocation count", func() {
+		defer func() { s.service.Metrics = metrics.Noop{} }()
+		m := &recordedMetrics{}
+		s.service.Metrics = m
+		s.mockRepository.On("GetByID", mock.Anything, appealID).Return(appealDetails, nil).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, appealDetails).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		_, actualError := s.service.Revoke(context.Background(), appealID, actor, reason)
+
+		s.Nil(actualError)
+		s.Equal([]string{metrics.AccessChangeDuration, metrics.AppealRevoked}, m.names)
+		s.Equal("revoke", m.tags[0]["operation"])
+		s.Equal("success", m.tags[0]["result"])
+	})
+}
+
+type recordedMetrics struct {
+	names []string
+	tags  []map[string]string
+}
+
+func (m *recordedMetrics) Count(name string, value int64, tags map[string]string) {
+	m.names = append(m.names, name)
+	m.tags = append(m.tags, tags)
+}
+
+func (m *recordedMetrics) Timing(name string, d time.Duration, tags map[string]string) {
+	m.names = append(m.names, name)
+	m.tags = append(m.tags, tags)
+}
+
+func (s *ServiceTestSuite) TestGetPendingForApprover() {
+	s.Run("should return error if approver is not a valid email", func() {
+		actualResult, actualError := s.service.GetPendingForApprover(context.Background(), "invalid")
+
+		s.Nil(actualResult)
+		s.Error(actualError)
+	})
+
+	s.Run("should return error if got error from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualResult, actualError := s.service.GetPendingForApprover(context.Background(), "approver@email.com")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return appeals waiting for the approver ordered by priority then age", func() {
+		approver := "approver@email.com"
+		newAppeal := func(id uint, priority string, createdAt time.Time, approvers []string) *domain.Appeal {
+			return &domain.Appeal{
+				ID:        id,
+				Status:    domain.AppealStatusPending,
+				Priority:  priority,
+				CreatedAt: createdAt,
+				Approvals: []*domain.Approval{
+					{
+						Name:      "step_1",
+						Status:    domain.ApprovalStatusPending,
+						Approvers: approvers,
 					},
-					Status: domain.AppealStatusPending,
-					Approvals: []*domain.Approval{
-						{
-							Name:   "approval_0",
-							Status: domain.ApprovalStatusApproved,
-						},
-						{
-							Name:      "approval_1",
-							Status:    domain.ApprovalStatusPending,
-							Approvers: []string{"user@email.com"},
-						},
-						{
-							Name:   "approval_2",
-							Status: domain.ApprovalStatusPending,
-						},
+				},
+			}
+		}
+		oldNormalAppeal := newAppeal(1, domain.AppealPriorityNormal, s.now.Add(-2*time.Hour), []string{approver})
+		newNormalAppeal := newAppeal(2, domain.AppealPriorityNormal, s.now.Add(-1*time.Hour), []string{approver})
+		urgentAppeal := newAppeal(3, domain.AppealPriorityUrgent, s.now, []string{approver})
+		lowAppeal := newAppeal(4, domain.AppealPriorityLow, s.now.Add(-3*time.Hour), []string{approver})
+		otherApproverAppeal := newAppeal(5, domain.AppealPriorityUrgent, s.now, []string{"other@email.com"})
+
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).
+			Return([]*domain.Appeal{oldNormalAppeal, newNormalAppeal, urgentAppeal, lowAppeal, otherApproverAppeal}, nil).Once()
+		expectedResult := []*domain.Appeal{urgentAppeal, oldNormalAppeal, newNormalAppeal, lowAppeal}
+
+		actualResult, actualError := s.service.GetPendingForApprover(context.Background(), approver)
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+	})
+
+	s.Run("should flag the appeals which current step is past its sla", func() {
+		approver := "approver@email.com"
+		newAppeal := func(id uint, createdAt time.Time) *domain.Appeal {
+			return &domain.Appeal{
+				ID:        id,
+				Status:    domain.AppealStatusPending,
+				CreatedAt: createdAt,
+				Approvals: []*domain.Approval{
+					{
+						Name:      "step_1",
+						Status:    domain.ApprovalStatusPending,
+						Approvers: []string{approver},
+						SLA:       "24h",
 					},
 				},
-				expectedResult: &domain.Appeal{
-					ID:         validApprovalActionParam.AppealID,
-					User:       "user@email.com",
-					ResourceID: 1,
-					Resource: &domain.Resource{
-						ID:  1,
-						URN: "urn",
+			}
+		}
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).
+			Return([]*domain.Appeal{newAppeal(1, s.now.Add(-25*time.Hour)), newAppeal(2, s.now.Add(-23*time.Hour))}, nil).Once()
+
+		actualResult, actualError := s.service.GetPendingForApprover(context.Background(), approver)
+
+		s.Nil(actualError)
+		s.Len(actualResult, 2)
+		s.True(actualResult[0].IsSLABreached)
+		s.False(actualResult[1].IsSLABreached)
+	})
+
+	s.Run("should count the sla from the time the previous step is actioned regardless of its later updates", func() {
+		approver := "approver@email.com"
+		previousActionedAt := s.now.Add(-2 * time.Hour)
+		pendingAppeal := &domain.Appeal{
+			ID:        1,
+			Status:    domain.AppealStatusPending,
+			CreatedAt: s.now.Add(-3 * time.Hour),
+			Approvals: []*domain.Approval{
+				{
+					Name:            "step_1",
+					Status:          domain.ApprovalStatusApproved,
+					StatusChangedAt: &previousActionedAt,
+					UpdatedAt:       s.now,
+				},
+				{
+					Name:      "step_2",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{approver},
+					SLA:       "1h",
+				},
+			},
+		}
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{pendingAppeal}, nil).Once()
+
+		actualResult, actualError := s.service.GetPendingForApprover(context.Background(), approver)
+
+		s.Nil(actualError)
+		s.Require().Len(actualResult, 1)
+		s.True(actualResult[0].IsSLABreached)
+	})
+}
+
+func (s *ServiceTestSuite) TestGetSLAReport() {
+	s.Run("should return error if got error from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualResult, actualError := s.service.GetSLAReport(context.Background(), nil)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should summarize the actioned steps with sla by approver and team", func() {
+		approver1 := "approver1@email.com"
+		approver2 := "approver2@email.com"
+		met := true
+		breached := false
+		filters := map[string]interface{}{"policy_id": "policy_1"}
+		expectedFilters := map[string]interface{}{"policy_id": "policy_1", "with_approvals": true}
+		s.mockRepository.On("Find", mock.Anything, expectedFilters).Return([]*domain.Appeal{{
+			ID:     1,
+			Labels: map[string]string{"team": "data"},
+			Approvals: []*domain.Approval{
+				{Name: "step_1", Status: domain.ApprovalStatusApproved, Actor: &approver1, SLA: "24h", SLAMet: &met},
+				{Name: "step_2", Status: domain.ApprovalStatusApproved, Actor: &approver2, SLA: "24h", SLAMet: &breached},
+			},
+		}, {
+			ID: 2,
+			Approvals: []*domain.Approval{
+				{Name: "step_1", Status: domain.ApprovalStatusApproved, Actor: &approver1, SLA: "24h", SLAMet: &breached},
+				{Name: "step_2", Status: domain.ApprovalStatusApproved, Actor: &approver2},
+				{Name: "step_3", Status: domain.ApprovalStatusPending, SLA: "24h"},
+			},
+		}}, nil).Once()
+		expectedResult := &domain.SLAReport{
+			SLAStats: domain.SLAStats{OnTime: 1, Breached: 2},
+			ByApprover: map[string]*domain.SLAStats{
+				approver1: {OnTime: 1, Breached: 1},
+				approver2: {Breached: 1},
+			},
+			ByTeam: map[string]*domain.SLAStats{
+				"data": {OnTime: 1, Breached: 1},
+			},
+		}
+
+		actualResult, actualError := s.service.GetSLAReport(context.Background(), filters)
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+		s.Equal(map[string]interface{}{"policy_id": "policy_1"}, filters)
+	})
+}
+
+func (s *ServiceTestSuite) TestGetAccessReview() {
+	s.Run("should return error if the period is invalid", func() {
+		actualResult, actualError := s.service.GetAccessReview(context.Background(), domain.AccessReviewFilter{
+			From: s.now,
+			To:   s.now.Add(-time.Hour),
+		})
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrInvalidAccessReviewPeriod)
+	})
+
+	s.Run("should list the access granted within the period matching the filter along with its outcome", func() {
+		approver := "approver@email.com"
+		from := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		grantedAt := time.Date(2026, 8, 1, 10, 0, 0, 0, time.UTC)
+		expirationDate := grantedAt.Add(24 * time.Hour)
+		resource := &domain.Resource{ProviderType: "bigquery", ProviderURN: "bq-prod", Type: "dataset", URN: "project:dataset"}
+		newAppeal := func(id uint, status string, approvedAt time.Time) *domain.Appeal {
+			// the later unrelated updates of the step don't change when the access was granted
+			updatedAt := approvedAt.Add(48 * time.Hour)
+			return &domain.Appeal{
+				ID:        id,
+				User:      "user@email.com",
+				Role:      "viewer",
+				Status:    status,
+				Labels:    map[string]string{"team": "data"},
+				Options:   &domain.AppealOptions{ExpirationDate: &expirationDate},
+				Resource:  resource,
+				CreatedAt: approvedAt.Add(-time.Hour),
+				Approvals: []*domain.Approval{
+					{Name: "check", Status: domain.ApprovalStatusSkipped},
+					{Name: "owner_approval", Status: domain.ApprovalStatusApproved, Actor: &approver, Approvers: []string{approver}, StatusChangedAt: &approvedAt, UpdatedAt: updatedAt},
+				},
+			}
+		}
+		expiredAppeal := newAppeal(1, domain.AppealStatusTerminated, grantedAt)
+		expiredAppeal.RevokedBy = domain.SystemActorName
+		expiredAppeal.RevokedAt = expirationDate
+		revokedAppeal := newAppeal(2, domain.AppealStatusTerminated, grantedAt)
+		revokedAppeal.RevokedBy = "admin@email.com"
+		revokedAppeal.RevokedAt = grantedAt.Add(time.Hour)
+		revokedAppeal.RevokeReason = "left the team"
+		grantedBeforeAppeal := newAppeal(3, domain.AppealStatusActive, from.Add(-time.Hour))
+		otherTeamAppeal := newAppeal(4, domain.AppealStatusActive, grantedAt)
+		otherTeamAppeal.Labels = map[string]string{"team": "marketing"}
+		s.mockRepository.On("Find", mock.Anything, map[string]interface{}{
+			"statuses": []string{domain.AppealStatusActive, domain.AppealStatusRevocationPending, domain.AppealStatusTerminated},
+		}).Return([]*domain.Appeal{{ID: 2}, {ID: 1}, {ID: 3}, {ID: 4}}, nil).Once()
+		for _, a := range []*domain.Appeal{expiredAppeal, revokedAppeal, grantedBeforeAppeal, otherTeamAppeal} {
+			s.mockRepository.On("GetByID", mock.Anything, a.ID).Return(a, nil).Once()
+		}
+		approvedAt := grantedAt
+		approvals := []*domain.ApprovalProvenance{{
+			ApprovalName:      "owner_approval",
+			Status:            domain.ApprovalStatusApproved,
+			NominalApprovers:  []string{approver},
+			EffectiveApprover: approver,
+			ActedAs:           domain.ApprovalActedAsApprover,
+			ActedAt:           &approvedAt,
+		}}
+		revokedAt := grantedAt.Add(time.Hour)
+		expectedResult := []*domain.AccessReviewEntry{
+			{
+				AppealID:       2,
+				User:           "user@email.com",
+				ProviderType:   "bigquery",
+				ProviderURN:    "bq-prod",
+				ResourceType:   "dataset",
+				ResourceURN:    "project:dataset",
+				Role:           "viewer",
+				Team:           "data",
+				Approvals:      approvals,
+				GrantedAt:      grantedAt,
+				ExpirationDate: &expirationDate,
+				Outcome:        domain.AccessReviewOutcomeRevoked,
+				RevokedBy:      "admin@email.com",
+				RevokedAt:      &revokedAt,
+				RevokeReason:   "left the team",
+			},
+			{
+				AppealID:       1,
+				User:           "user@email.com",
+				ProviderType:   "bigquery",
+				ProviderURN:    "bq-prod",
+				ResourceType:   "dataset",
+				ResourceURN:    "project:dataset",
+				Role:           "viewer",
+				Team:           "data",
+				Approvals:      approvals,
+				GrantedAt:      grantedAt,
+				ExpirationDate: &expirationDate,
+				Outcome:        domain.AccessReviewOutcomeExpired,
+				RevokedBy:      domain.SystemActorName,
+				RevokedAt:      &expirationDate,
+			},
+		}
+
+		actualResult, actualError := s.service.GetAccessReview(context.Background(), domain.AccessReviewFilter{
+			From:          from,
+			To:            to,
+			ProviderTypes: []string{"bigquery"},
+			Teams:         []string{"data"},
+		})
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+		s.Equal(24*time.Hour, actualResult[1].GetDuration())
+	})
+}
+
+func (s *ServiceTestSuite) TestFindDeadlockedAppeals() {
+	s.Run("should return error if got any from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualResult, actualError := s.service.FindDeadlockedAppeals(context.Background())
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return only appeals which current approval step has no approver", func() {
+		deadlockedAppeal := &domain.Appeal{
+			ID:     1,
+			Status: domain.AppealStatusPending,
+			Approvals: []*domain.Approval{
+				{
+					Name:   "approval_0",
+					Status: domain.ApprovalStatusApproved,
+				},
+				{
+					Name:   "approval_1",
+					Status: domain.ApprovalStatusPending,
+				},
+			},
+		}
+		healthyAppeal := &domain.Appeal{
+			ID:     2,
+			Status: domain.AppealStatusPending,
+			Approvals: []*domain.Approval{
+				{
+					Name:      "approval_0",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{"approver@email.com"},
+				},
+				{
+					Name:   "approval_1",
+					Status: domain.ApprovalStatusPending,
+				},
+			},
+		}
+		expectedFilters := map[string]interface{}{
+			"statuses":       []string{domain.AppealStatusPending},
+			"with_approvals": true,
+		}
+		s.mockRepository.On("Find", mock.Anything, expectedFilters).Return([]*domain.Appeal{deadlockedAppeal, healthyAppeal}, nil).Once()
+
+		actualResult, actualError := s.service.FindDeadlockedAppeals(context.Background())
+
+		s.Equal([]*domain.Appeal{deadlockedAppeal}, actualResult)
+		s.Nil(actualError)
+	})
+}
+
+func (s *ServiceTestSuite) TestReassignDeadlockedAppeal() {
+	s.Run("should return error if approvers are invalid", func() {
+		invalidApprovers := [][]string{
+			nil,
+			{"invalidemail"},
+		}
+
+		for _, approvers := range invalidApprovers {
+			actualResult, actualError := s.service.ReassignDeadlockedAppeal(context.Background(), 1, approvers)
+
+			s.Nil(actualResult)
+			s.Error(actualError)
+		}
+	})
+
+	approvers := []string{"approver@email.com"}
+
+	s.Run("should return error if appeal not found", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(nil, nil).Once()
+
+		actualResult, actualError := s.service.ReassignDeadlockedAppeal(context.Background(), 1, approvers)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAppealNotFound.Error())
+	})
+
+	s.Run("should return error if appeal is not deadlocked", func() {
+		expectedAppeal := &domain.Appeal{
+			ID:     1,
+			Status: domain.AppealStatusPending,
+			Approvals: []*domain.Approval{
+				{
+					Name:      "approval_0",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{"another.approver@email.com"},
+				},
+			},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(expectedAppeal, nil).Once()
+
+		actualResult, actualError := s.service.ReassignDeadlockedAppeal(context.Background(), 1, approvers)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAppealNotDeadlocked.Error())
+	})
+
+	s.Run("should add approvers to the deadlocked approval step and notify them", func() {
+		expectedAppeal := &domain.Appeal{
+			ID:     1,
+			User:   "user@email.com",
+			Status: domain.AppealStatusPending,
+			Resource: &domain.Resource{
+				URN: "urn",
+			},
+			Approvals: []*domain.Approval{
+				{
+					ID:     11,
+					Name:   "approval_0",
+					Status: domain.ApprovalStatusPending,
+				},
+			},
+		}
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(expectedAppeal, nil).Once()
+		s.mockApprovalService.On("AddApprover", mock.Anything, &domain.Approver{
+			ApprovalID: 11,
+			AppealID:   1,
+			Email:      "approver@email.com",
+		}).Return(nil).Once()
+		expectedNotifications := []domain.Notification{{
+			User:    "approver@email.com",
+			Message: "You have an appeal from user@email.com to access urn",
+		}}
+		s.mockNotifier.On("Notify", expectedNotifications).Return(nil).Once()
+
+		actualResult, actualError := s.service.ReassignDeadlockedAppeal(context.Background(), 1, approvers)
+
+		s.Nil(actualError)
+		s.Equal(approvers, actualResult.Approvals[0].Approvers)
+		s.Nil(actualResult.GetDeadlockedApproval())
+	})
+}
+
+func (s *ServiceTestSuite) TestAddApprovalStep() {
+	step := domain.Approval{Name: "legal_approval", Approvers: []string{"legal@email.com"}}
+	getAppeal := func() *domain.Appeal {
+		return &domain.Appeal{
+			ID:            1,
+			User:          "user@email.com",
+			Status:        domain.AppealStatusPending,
+			PolicyID:      "policy_1",
+			PolicyVersion: 1,
+			Resource:      &domain.Resource{URN: "urn"},
+			Approvals: []*domain.Approval{
+				{ID: 11, Name: "approval_0", Index: 0, Status: domain.ApprovalStatusApproved, Approvers: []string{"approver@email.com"}},
+				{ID: 12, Name: "approval_1", Index: 1, Status: domain.ApprovalStatusPending, Approvers: []string{"approver@email.com"}},
+			},
+		}
+	}
+
+	s.Run("should return error if the step is invalid", func() {
+		testCases := []struct {
+			step          domain.Approval
+			expectedError error
+		}{
+			{domain.Approval{Approvers: []string{"legal@email.com"}}, appeal.ErrApprovalStepNameRequired},
+			{domain.Approval{Name: "legal_approval"}, appeal.ErrApprovalStepApproversInvalid},
+			{domain.Approval{Name: "legal_approval", Approvers: []string{"invalid"}}, appeal.ErrApprovalStepApproversInvalid},
+		}
+
+		for _, tc := range testCases {
+			actualResult, actualError := s.service.AddApprovalStep(context.Background(), 1, tc.step, 1, "admin@email.com")
+
+			s.Nil(actualResult)
+			s.ErrorIs(actualError, tc.expectedError)
+		}
+	})
+
+	s.Run("should return error if the step can't be added to the appeal", func() {
+		finalizedAppeal := getAppeal()
+		finalizedAppeal.Status = domain.AppealStatusActive
+		testCases := []struct {
+			name          string
+			appeal        *domain.Appeal
+			step          domain.Approval
+			position      int
+			actor         string
+			expectedError error
+		}{
+			{"finalized appeal", finalizedAppeal, step, 2, "admin@email.com", appeal.ErrAppealStatusApproved},
+			{"not an admin nor an approver", getAppeal(), step, 2, "user@email.com", appeal.ErrAddApprovalStepForbidden},
+			{"before an approved step", getAppeal(), step, 0, "admin@email.com", appeal.ErrInvalidApprovalStepPosition},
+			{"out of range", getAppeal(), step, 3, "admin@email.com", appeal.ErrInvalidApprovalStepPosition},
+			{"duplicate name", getAppeal(), domain.Approval{Name: "approval_1", Approvers: step.Approvers}, 2, "admin@email.com", appeal.ErrApprovalStepDuplicate},
+		}
+
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(tc.appeal, nil).Once()
+
+				actualResult, actualError := s.service.AddApprovalStep(context.Background(), 1, tc.step, tc.position, tc.actor)
+
+				s.Nil(actualResult)
+				s.ErrorIs(actualError, tc.expectedError)
+			})
+		}
+	})
+
+	s.Run("should insert the step and notify the new approvers if it becomes the current step", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(getAppeal(), nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.expectOutboxMessage()
+		s.mockRepository.On("AddApproval", mock.Anything, &domain.Approval{
+			Name:          "legal_approval",
+			Index:         1,
+			AppealID:      1,
+			Status:        domain.ApprovalStatusPending,
+			PolicyID:      "policy_1",
+			PolicyVersion: 1,
+			AddedBy:       "approver@email.com",
+			Approvers:     []string{"legal@email.com"},
+		}).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "legal@email.com",
+			Message: "You have an appeal from user@email.com to access urn",
+		}}).Return(nil).Once()
+
+		actualResult, actualError := s.service.AddApprovalStep(context.Background(), 1, step, 1, "approver@email.com")
+
+		s.Nil(actualError)
+		var actualSteps []string
+		for _, approval := range actualResult.Approvals {
+			actualSteps = append(actualSteps, fmt.Sprintf("%d:%s", approval.Index, approval.Name))
+		}
+		s.Equal([]string{"0:approval_0", "1:legal_approval", "2:approval_1"}, actualSteps)
+		s.Equal("legal_approval", actualResult.GetNextPendingApproval().Name)
+	})
+
+	s.Run("should not notify the new approvers if the step isn't the current step", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(getAppeal(), nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("AddApproval", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		actualResult, actualError := s.service.AddApprovalStep(context.Background(), 1, step, 2, "admin@email.com")
+
+		s.Nil(actualError)
+		s.Equal("legal_approval", actualResult.Approvals[2].Name)
+		s.Equal("approval_1", actualResult.GetNextPendingApproval().Name)
+	})
+
+	s.Run("should return error without notifying if the appeal fails to be updated along with the step", func() {
+		expectedError := errors.New("update error")
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(getAppeal(), nil).Once()
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("AddApproval", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.Anything).Return(expectedError).Once()
+
+		actualResult, actualError := s.service.AddApprovalStep(context.Background(), 1, step, 1, "approver@email.com")
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, expectedError)
+	})
+}
+
+func (s *ServiceTestSuite) TestCancelAbandonedAppeals() {
+	s.Run("should return error if got any from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualError := s.service.CancelAbandonedAppeals(context.Background())
+
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should only cancel the appeals pending past the policy's age without any approver action", func() {
+		approver := "approver@email.com"
+		system := domain.SystemActorName
+		newAppeal := func(id uint, policyID string, createdAt time.Time, actor *string) *domain.Appeal {
+			return &domain.Appeal{
+				ID:            id,
+				User:          "user@email.com",
+				Role:          "viewer",
+				PolicyID:      policyID,
+				PolicyVersion: 1,
+				Status:        domain.AppealStatusPending,
+				Resource:      &domain.Resource{URN: "urn"},
+				Approvals: []*domain.Approval{
+					{
+						Name:   "approval_0",
+						Status: domain.ApprovalStatusApproved,
+						Actor:  actor,
 					},
-					Status: domain.AppealStatusRejected,
-					Approvals: []*domain.Approval{
-						{
-							Name:   "approval_0",
-							Status: domain.ApprovalStatusApproved,
-						},
-						{
-							Name:      "approval_1",
-							Status:    domain.ApprovalStatusRejected,
-							Approvers: []string{"user@email.com"},
-							Actor:     &user,
-							UpdatedAt: timeNow,
-						},
-						{
-							Name:      "approval_2",
-							Status:    domain.ApprovalStatusSkipped,
-							UpdatedAt: timeNow,
-						},
+					{
+						Name:      "approval_1",
+						Status:    domain.ApprovalStatusPending,
+						Approvers: []string{approver},
 					},
 				},
-				expectedNotifications: []domain.Notification{
+				CreatedAt: createdAt,
+			}
+		}
+		abandonedAppeal := newAppeal(1, "policy_1", s.now.Add(-200*time.Hour), &system)
+		recentAppeal := newAppeal(2, "policy_1", s.now.Add(-2*time.Hour), nil)
+		actionedAppeal := newAppeal(3, "policy_1", s.now.Add(-200*time.Hour), &approver)
+		disabledAppeal := newAppeal(4, "policy_2", s.now.Add(-200*time.Hour), nil)
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{abandonedAppeal, recentAppeal, actionedAppeal, disabledAppeal}, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(abandonedAppeal, nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(&domain.Policy{
+			ID:         "policy_1",
+			Version:    1,
+			AutoCancel: &domain.AutoCancelConfig{Enabled: true, PendingFor: "168h"},
+		}, nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_2", uint(1)).Return(&domain.Policy{ID: "policy_2", Version: 1}, nil).Once()
+		s.mockRepository.On("Update", mock.Anything, abandonedAppeal).Return(nil).Once()
+		expectedNotifications := []domain.Notification{{
+			User:    "user@email.com",
+			Message: "Your appeal to access urn as viewer has been canceled after being pending for 168h0m0s without any action from the approvers. You can appeal again if the access is still needed.",
+		}}
+		s.mockNotifier.On("Notify", expectedNotifications).Return(nil).Once()
+
+		actualError := s.service.CancelAbandonedAppeals(context.Background())
+
+		s.Nil(actualError)
+		s.Equal(domain.AppealStatusCanceled, abandonedAppeal.Status)
+		s.Equal(domain.SystemActorName, abandonedAppeal.CanceledBy)
+		s.Equal(domain.AppealStatusPending, recentAppeal.Status)
+		s.Equal(domain.AppealStatusPending, actionedAppeal.Status)
+		s.Equal(domain.AppealStatusPending, disabledAppeal.Status)
+		s.mockNotifier.AssertExpectations(s.T())
+		s.mockRepository.AssertExpectations(s.T())
+	})
+}
+
+func (s *ServiceTestSuite) TestReassignIdleApprovals() {
+	s.Run("should return error if got any from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualError := s.service.ReassignIdleApprovals(context.Background())
+
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should reassign the idle assigned steps to the least loaded of the other approvers", func() {
+		recentlyAssigned := s.now.Add(-time.Hour)
+		longAssigned := s.now.Add(-48 * time.Hour)
+		newAppeal := func(id uint, assignedAt time.Time) *domain.Appeal {
+			return &domain.Appeal{
+				ID:       id,
+				User:     "user@email.com",
+				Status:   domain.AppealStatusPending,
+				Resource: &domain.Resource{URN: "urn"},
+				Approvals: []*domain.Approval{
 					{
-						User:    "user@email.com",
-						Message: "Your appeal to urn is rejected",
+						Name:               "approval_0",
+						Status:             domain.ApprovalStatusPending,
+						Approvers:          []string{"approver1@email.com", "approver2@email.com", "approver3@email.com"},
+						AssignmentStrategy: domain.AssignmentStrategyLeastLoaded,
+						ReassignAfter:      "24h",
+						Assignee:           "approver1@email.com",
+						AssignedAt:         &assignedAt,
 					},
 				},
+			}
+		}
+		recentAppeal := newAppeal(1, recentlyAssigned)
+		idleAppeal := newAppeal(2, longAssigned)
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{recentAppeal, idleAppeal}, nil).Once()
+		s.mockApprovalService.On("GetApproverLoads", mock.Anything, []string{"approver1@email.com", "approver2@email.com", "approver3@email.com"}).Return(map[string]*domain.ApproverLoad{
+			"approver1@email.com": {Approver: "approver1@email.com", PendingCount: 1},
+			"approver2@email.com": {Approver: "approver2@email.com", PendingCount: 5},
+			"approver3@email.com": {Approver: "approver3@email.com", PendingCount: 2},
+		}, nil).Once()
+		s.mockRepository.On("Update", mock.Anything, idleAppeal).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "approver3@email.com",
+			Message: "You have an appeal from user@email.com to access urn",
+		}}).Return(nil).Once()
+
+		actualError := s.service.ReassignIdleApprovals(context.Background())
+
+		s.Nil(actualError)
+		s.Equal("approver1@email.com", recentAppeal.Approvals[0].Assignee)
+		s.Equal("approver3@email.com", idleAppeal.Approvals[0].Assignee)
+		s.Equal(s.now, *idleAppeal.Approvals[0].AssignedAt)
+		s.mockNotifier.AssertExpectations(s.T())
+		s.mockRepository.AssertExpectations(s.T())
+	})
+}
+
+func (s *ServiceTestSuite) TestResolvePendingApprovers() {
+	s.Run("should resolve the deferred steps and notify the approvers of the current step", func() {
+		iamError := errors.New("iam is unavailable")
+		newAppeal := func(id uint, user string) *domain.Appeal {
+			return &domain.Appeal{
+				ID:       id,
+				User:     user,
+				Status:   domain.AppealStatusPending,
+				Resource: &domain.Resource{URN: "urn"},
+				Approvals: []*domain.Approval{
+					{ID: id * 10, Name: "manager_approval", Status: domain.ApprovalStatusPendingApproverResolution, PolicyID: "policy_1", PolicyVersion: 1},
+					{ID: id*10 + 1, Name: "owner_approval", Status: domain.ApprovalStatusPending, PolicyID: "policy_1", PolicyVersion: 1, Approvers: []string{"owner@email.com"}},
+				},
+			}
+		}
+		resolvedAppeal := newAppeal(1, "user@email.com")
+		stillDeferredAppeal := newAppeal(2, "other.user@email.com")
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{resolvedAppeal, stillDeferredAppeal}, nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(&domain.Policy{
+			ID:      "policy_1",
+			Version: 1,
+			Steps: []*domain.Step{
+				{Name: "manager_approval", Approvers: domain.ApproversKeyUserApprovers},
+				{Name: "owner_approval", Approvers: "$resource.details.owner"},
 			},
-			{
-				name: "should notify the next approvers if there's still manual approvals remaining ahead after approved",
-				expectedApprovalAction: domain.ApprovalAction{
-					AppealID:     validApprovalActionParam.AppealID,
-					ApprovalName: "approval_0",
-					Actor:        user,
-					Action:       domain.AppealActionNameApprove,
+		}, nil).Once()
+		s.mockIAMService.On("GetUserApproverEmails", "user@email.com").Return([]string{"manager@email.com"}, nil).Once()
+		s.mockIAMService.On("GetUserApproverEmails", "other.user@email.com").Return(nil, iamError).Once()
+		s.mockApprovalService.On("AddApprover", mock.Anything, &domain.Approver{ApprovalID: 10, AppealID: 1, Email: "manager@email.com"}).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, resolvedAppeal).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "manager@email.com",
+			Message: "You have an appeal from user@email.com to access urn",
+		}}).Return(nil).Once()
+
+		actualError := s.service.ResolvePendingApprovers(context.Background())
+
+		s.Nil(actualError)
+		s.Equal(domain.ApprovalStatusPending, resolvedAppeal.Approvals[0].Status)
+		s.Equal([]string{"manager@email.com"}, resolvedAppeal.Approvals[0].Approvers)
+		s.Equal(domain.ApprovalStatusPendingApproverResolution, stillDeferredAppeal.Approvals[0].Status)
+		s.mockRepository.AssertNotCalled(s.T(), "Update", mock.Anything, stillDeferredAppeal)
+		s.mockNotifier.AssertExpectations(s.T())
+	})
+}
+
+func (s *ServiceTestSuite) TestSendPendingApprovalsDigest() {
+	s.Run("should not look up the appeals if none of the approvers opts into the digest", func() {
+		actualError := s.service.SendPendingApprovalsDigest(context.Background())
+
+		s.Nil(actualError)
+		s.mockRepository.AssertNotCalled(s.T(), "Find", mock.Anything, mock.Anything)
+	})
+
+	s.Run("should send each opted in approver a single digest ordered by the priority then the age", func() {
+		service := appeal.NewService(
+			s.mockRepository,
+			s.mockApprovalService,
+			s.mockResourceService,
+			s.mockProviderService,
+			s.mockPolicyService,
+			s.mockIAMService,
+			s.mockNotifier,
+			s.mockBlobStorage,
+			zap.NewNop(),
+			&appeal.Config{Digest: appeal.DigestConfig{Approvers: []string{"digest@email.com", "idle@email.com"}}},
+		)
+		service.TimeNow = func() time.Time { return s.now }
+		newAppeal := func(id uint, priority string, pendingFor time.Duration, approvers ...string) *domain.Appeal {
+			return &domain.Appeal{
+				ID:        id,
+				User:      "user@email.com",
+				Role:      "viewer",
+				Priority:  priority,
+				Status:    domain.AppealStatusPending,
+				Resource:  &domain.Resource{URN: fmt.Sprintf("urn_%d", id)},
+				CreatedAt: s.now.Add(-pendingFor),
+				Approvals: []*domain.Approval{{
+					Name:      "approval_0",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: approvers,
+				}},
+			}
+		}
+		appeals := []*domain.Appeal{
+			newAppeal(1, domain.AppealPriorityNormal, 50*time.Hour, "digest@email.com", "other@email.com"),
+			newAppeal(2, domain.AppealPriorityNormal, 3*time.Hour, "digest@email.com"),
+			newAppeal(3, domain.AppealPriorityUrgent, time.Hour, "digest@email.com"),
+			newAppeal(4, domain.AppealPriorityNormal, time.Hour, "other@email.com"),
+		}
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(appeals, nil).Once()
+		expectedNotifications := []domain.Notification{{
+			User: "digest@email.com",
+			Message: "You have 3 appeal(s) pending your approval:" +
+				"\n- [URGENT] #3 from user@email.com to access urn_3 as viewer, pending for 1h0m0s" +
+				"\n- [NORMAL] #1 from user@email.com to access urn_1 as viewer, pending for 50h0m0s" +
+				"\n- [NORMAL] #2 from user@email.com to access urn_2 as viewer, pending for 3h0m0s",
+		}}
+		s.mockNotifier.On("Notify", expectedNotifications).Return(nil).Once()
+
+		actualError := service.SendPendingApprovalsDigest(context.Background())
+
+		s.Nil(actualError)
+		s.mockNotifier.AssertExpectations(s.T())
+	})
+}
+
+func (s *ServiceTestSuite) TestRetryPendingRevocations() {
+	s.Run("should return error if got any from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualError := s.service.RetryPendingRevocations(context.Background(), false)
+
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	newAppeal := func(id uint, lastAttemptAt time.Time) *domain.Appeal {
+		return &domain.Appeal{
+			ID:                 id,
+			Status:             domain.AppealStatusRevocationPending,
+			RevokedBy:          domain.SystemActorName,
+			RevocationAttempts: 1,
+			Resource:           &domain.Resource{ID: 1, URN: "urn"},
+			UpdatedAt:          lastAttemptAt,
+		}
+	}
+
+	s.Run("should only retry the revocations attempted longer than the retry interval ago", func() {
+		dueAppeal := newAppeal(1, s.now.Add(-time.Hour))
+		recentAppeal := newAppeal(2, s.now.Add(-time.Minute))
+		expectedFilters := map[string]interface{}{"statuses": []string{domain.AppealStatusRevocationPending}}
+		s.mockRepository.On("Find", mock.Anything, expectedFilters).Return([]*domain.Appeal{dueAppeal, recentAppeal}, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(dueAppeal, nil).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, dueAppeal).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.AnythingOfType("*domain.Appeal")).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualError := s.service.RetryPendingRevocations(context.Background(), false)
+
+		s.Nil(actualError)
+		s.mockProviderService.AssertNotCalled(s.T(), "RevokeAccess", mock.Anything, recentAppeal)
+	})
+
+	s.Run("should retry every revocation regardless of the interval if forced", func() {
+		recentAppeal := newAppeal(2, s.now.Add(-time.Minute))
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{recentAppeal}, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(2)).Return(recentAppeal, nil).Once()
+		s.mockProviderService.On("RevokeAccess", mock.Anything, recentAppeal).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.AnythingOfType("*domain.Appeal")).Return(nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+
+		actualError := s.service.RetryPendingRevocations(context.Background(), true)
+
+		s.Nil(actualError)
+		s.mockProviderService.AssertExpectations(s.T())
+	})
+}
+
+func (s *ServiceTestSuite) TestMuteReminders() {
+	requester := "user@email.com"
+	approver := "approver@email.com"
+	newAppeal := func() *domain.Appeal {
+		return &domain.Appeal{
+			ID:       1,
+			User:     requester,
+			Status:   domain.AppealStatusPending,
+			Resource: &domain.Resource{URN: "urn"},
+			Approvals: []*domain.Approval{
+				{Name: "approval_0", Status: domain.ApprovalStatusPending, Approvers: []string{approver}},
+			},
+		}
+	}
+
+	s.Run("should return error if the actor is neither the requester nor an approver", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(newAppeal(), nil).Once()
+
+		actualResult, actualError := s.service.MuteReminders(context.Background(), 1, "someone@email.com", "waiting on the vendor")
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrActionForbidden)
+	})
+
+	s.Run("should return error if the appeal is no longer pending", func() {
+		appealDetails := newAppeal()
+		appealDetails.Status = domain.AppealStatusActive
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+
+		actualResult, actualError := s.service.MuteReminders(context.Background(), 1, requester, "waiting on the vendor")
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, appeal.ErrAppealStatusApproved)
+	})
+
+	s.Run("should record who muted the reminders", func() {
+		for _, actor := range []string{requester, approver} {
+			appealDetails := newAppeal()
+			s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+			s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+
+			actualResult, actualError := s.service.MuteReminders(context.Background(), 1, actor, "waiting on the vendor")
+
+			s.Nil(actualError)
+			s.Equal(actor, actualResult.RemindersMutedBy)
+			s.Equal(s.now, *actualResult.RemindersMutedAt)
+			s.Equal("waiting on the vendor", actualResult.RemindersMutedReason)
+		}
+	})
+
+	s.Run("should clear the muted reminders", func() {
+		mutedAt := s.now.Add(-time.Hour)
+		appealDetails := newAppeal()
+		appealDetails.RemindersMutedBy = requester
+		appealDetails.RemindersMutedAt = &mutedAt
+		appealDetails.RemindersMutedReason = "waiting on the vendor"
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+		s.mockRepository.On("Update", mock.Anything, appealDetails).Return(nil).Once()
+
+		actualResult, actualError := s.service.UnmuteReminders(context.Background(), 1, approver)
+
+		s.Nil(actualError)
+		s.Empty(actualResult.RemindersMutedBy)
+		s.Nil(actualResult.RemindersMutedAt)
+		s.Empty(actualResult.RemindersMutedReason)
+	})
+}
+
+func (s *ServiceTestSuite) TestRemindPendingApprovers() {
+	s.Run("should return error if got any from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualError := s.service.RemindPendingApprovers(context.Background(), time.Hour)
+
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should only remind approvers of the idle approval steps outside the cooldown and not muted", func() {
+		recentlyReminded := s.now.Add(-30 * time.Minute)
+		newAppeal := &domain.Appeal{
+			ID:       1,
+			User:     "user@email.com",
+			Status:   domain.AppealStatusPending,
+			Resource: &domain.Resource{URN: "urn_1"},
+			Approvals: []*domain.Approval{
+				{
+					Name:      "approval_0",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{"approver1@email.com"},
 				},
-				expectedAppealDetails: &domain.Appeal{
-					ID:         validApprovalActionParam.AppealID,
-					User:       "user@email.com",
-					ResourceID: 1,
-					Resource: &domain.Resource{
-						ID:  1,
-						URN: "urn",
-					},
-					Status: domain.AppealStatusPending,
-					Approvals: []*domain.Approval{
-						{
-							Name:      "approval_0",
-							Status:    domain.ApprovalStatusPending,
-							Approvers: []string{user},
-						},
-						{
-							Name:   "approval_1",
-							Status: domain.ApprovalStatusPending,
-							Approvers: []string{
-								"nextapprover1@email.com",
-								"nextapprover2@email.com",
-							},
-						},
-					},
+			},
+			CreatedAt: s.now.Add(-10 * time.Minute),
+		}
+		remindedAppeal := &domain.Appeal{
+			ID:       2,
+			User:     "user@email.com",
+			Status:   domain.AppealStatusPending,
+			Resource: &domain.Resource{URN: "urn_2"},
+			Approvals: []*domain.Approval{
+				{
+					Name:           "approval_0",
+					Status:         domain.ApprovalStatusPending,
+					Approvers:      []string{"approver2@email.com"},
+					LastRemindedAt: &recentlyReminded,
 				},
-				expectedResult: &domain.Appeal{
-					ID:         validApprovalActionParam.AppealID,
-					User:       "user@email.com",
-					ResourceID: 1,
-					Resource: &domain.Resource{
-						ID:  1,
-						URN: "urn",
-					},
-					Status: domain.AppealStatusPending,
-					Approvals: []*domain.Approval{
-						{
-							Name:      "approval_0",
-							Status:    domain.ApprovalStatusApproved,
-							Approvers: []string{user},
-							Actor:     &user,
-							UpdatedAt: timeNow,
-						},
-						{
-							Name:   "approval_1",
-							Status: domain.ApprovalStatusPending,
-							Approvers: []string{
-								"nextapprover1@email.com",
-								"nextapprover2@email.com",
-							},
-						},
-					},
+			},
+			CreatedAt: s.now.Add(-3 * time.Hour),
+		}
+		approvedAt := s.now.Add(-2 * time.Hour)
+		idleAppeal := &domain.Appeal{
+			ID:       3,
+			User:     "user@email.com",
+			Status:   domain.AppealStatusPending,
+			Resource: &domain.Resource{URN: "urn_3"},
+			Approvals: []*domain.Approval{
+				{
+					Name:            "approval_0",
+					Status:          domain.ApprovalStatusApproved,
+					Approvers:       []string{"approver1@email.com"},
+					StatusChangedAt: &approvedAt,
+				},
+				{
+					Name:      "approval_1",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{"approver3@email.com"},
+					// an unrelated update of the appeal doesn't postpone the reminder
+					UpdatedAt: s.now.Add(-time.Minute),
+				},
+			},
+			CreatedAt: s.now.Add(-3 * time.Hour),
+		}
+		mutedAt := s.now.Add(-3 * time.Hour)
+		mutedAppeal := &domain.Appeal{
+			ID:               4,
+			User:             "user@email.com",
+			Status:           domain.AppealStatusPending,
+			Resource:         &domain.Resource{URN: "urn_4"},
+			RemindersMutedBy: "user@email.com",
+			RemindersMutedAt: &mutedAt,
+			Approvals: []*domain.Approval{
+				{
+					Name:      "approval_0",
+					Status:    domain.ApprovalStatusPending,
+					Approvers: []string{"approver4@email.com"},
 				},
-				expectedNotifications: []domain.Notification{
-					{
-						User:    "nextapprover1@email.com",
-						Message: "You have an appeal from user@email.com to access urn",
-					},
+			},
+			CreatedAt: s.now.Add(-2 * time.Hour),
+		}
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{newAppeal, remindedAppeal, idleAppeal, mutedAppeal}, nil).Once()
+		expectedNotifications := []domain.Notification{{
+			User:    "approver3@email.com",
+			Message: "Reminder: you have a pending appeal from user@email.com to access urn_3",
+		}}
+		s.mockNotifier.On("Notify", expectedNotifications).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, idleAppeal).Return(nil).Once()
+
+		actualError := s.service.RemindPendingApprovers(context.Background(), time.Hour)
+
+		s.Nil(actualError)
+		s.Equal(s.now, *idleAppeal.Approvals[1].LastRemindedAt)
+		s.Equal(1, idleAppeal.Approvals[1].ReminderLevel)
+		s.mockNotifier.AssertExpectations(s.T())
+		s.mockRepository.AssertExpectations(s.T())
+	})
+
+	s.Run("should escalate the reminders through the reminder ladder of the policy", func() {
+		newAppeal := func(id uint, reminderLevel int) *domain.Appeal {
+			return &domain.Appeal{
+				ID:       id,
+				User:     "user@email.com",
+				Status:   domain.AppealStatusPending,
+				Resource: &domain.Resource{URN: "urn_1"},
+				Approvals: []*domain.Approval{
 					{
-						User:    "nextapprover2@email.com",
-						Message: "You have an appeal from user@email.com to access urn",
+						Name:                "approval_0",
+						Status:              domain.ApprovalStatusPending,
+						PolicyID:            "policy_1",
+						PolicyVersion:       1,
+						NotificationChannel: "slack",
+						ReminderLevel:       reminderLevel,
+						Approvers:           []string{"approver@email.com"},
 					},
 				},
+				CreatedAt: s.now.Add(-2 * time.Hour),
+			}
+		}
+		remindedOnceAppeal := newAppeal(1, 1)
+		exhaustedAppeal := newAppeal(2, 5)
+		policy := &domain.Policy{
+			ID:      "policy_1",
+			Version: 1,
+			ReminderEscalation: []*domain.ReminderRung{
+				{},
+				{Channel: "email"},
+				{Channel: "pagerduty", Recipients: []string{"oncall@email.com"}},
+			},
+		}
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{remindedOnceAppeal, exhaustedAppeal}, nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_1", uint(1)).Return(policy, nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "approver@email.com",
+			Message: "Reminder: you have a pending appeal from user@email.com to access urn_1",
+			Channel: "email",
+		}}).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{
+			{
+				User:    "approver@email.com",
+				Message: "Reminder: you have a pending appeal from user@email.com to access urn_1",
+				Channel: "pagerduty",
+			},
+			{
+				User:    "oncall@email.com",
+				Message: "Escalation: the approval step \"approval_0\" of the appeal from user@email.com to access urn_1 is still pending after 5 reminders",
+				Channel: "pagerduty",
+			},
+		}).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, remindedOnceAppeal).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, exhaustedAppeal).Return(nil).Once()
+
+		actualError := s.service.RemindPendingApprovers(context.Background(), time.Hour)
+
+		s.Nil(actualError)
+		s.Equal(2, remindedOnceAppeal.Approvals[0].ReminderLevel)
+		s.Equal(6, exhaustedAppeal.Approvals[0].ReminderLevel)
+		s.mockPolicyService.AssertExpectations(s.T())
+		s.mockNotifier.AssertExpectations(s.T())
+		s.mockRepository.AssertExpectations(s.T())
+	})
+
+	s.Run("should use the configured reminder ladder if the policy doesn't have any", func() {
+		service := appeal.NewService(
+			s.mockRepository,
+			s.mockApprovalService,
+			s.mockResourceService,
+			s.mockProviderService,
+			s.mockPolicyService,
+			s.mockIAMService,
+			s.mockNotifier,
+			s.mockBlobStorage,
+			zap.NewNop(),
+			&appeal.Config{
+				Reminder: appeal.ReminderConfig{
+					IdleFor:            time.Hour,
+					EscalationChannels: []string{"", "email"},
+					OnCall:             []string{"oncall@email.com"},
+				},
+			},
+		)
+		service.TimeNow = func() time.Time {
+			return s.now
+		}
+		idleAppeal := &domain.Appeal{
+			ID:       1,
+			User:     "user@email.com",
+			Status:   domain.AppealStatusPending,
+			Resource: &domain.Resource{URN: "urn_1"},
+			Approvals: []*domain.Approval{
+				{
+					Name:          "approval_0",
+					Status:        domain.ApprovalStatusPending,
+					PolicyID:      "policy_2",
+					PolicyVersion: 1,
+					ReminderLevel: 2,
+					Approvers:     []string{"approver@email.com"},
+					UpdatedAt:     s.now.Add(-2 * time.Hour),
+				},
+			},
+		}
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return([]*domain.Appeal{idleAppeal}, nil).Once()
+		s.mockPolicyService.On("GetOne", mock.Anything, "policy_2", uint(1)).Return(&domain.Policy{ID: "policy_2", Version: 1}, nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{
+			{
+				User:    "approver@email.com",
+				Message: "Reminder: you have a pending appeal from user@email.com to access urn_1",
+				Channel: "email",
+			},
+			{
+				User:    "oncall@email.com",
+				Message: "Escalation: the approval step \"approval_0\" of the appeal from user@email.com to access urn_1 is still pending after 2 reminders",
+				Channel: "email",
+			},
+		}).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, idleAppeal).Return(nil).Once()
+
+		actualError := service.RemindPendingApprovers(context.Background(), 0)
+
+		s.Nil(actualError)
+		s.Equal(3, idleAppeal.Approvals[0].ReminderLevel)
+		s.mockNotifier.AssertExpectations(s.T())
+		s.mockRepository.AssertExpectations(s.T())
+	})
+}
+
+func (s *ServiceTestSuite) TestToggleAccessWindows() {
+	weekdays := []string{"monday", "tuesday", "wednesday", "thursday", "friday"}
+	newAppeal := func(id uint, window *domain.AccessWindow, closed bool) *domain.Appeal {
+		return &domain.Appeal{
+			ID:                 id,
+			Status:             domain.AppealStatusActive,
+			Options:            &domain.AppealOptions{AccessWindow: window},
+			AccessWindowClosed: closed,
+		}
+	}
+
+	s.Run("should return error if got error from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+
+		actualError := s.service.ToggleAccessWindows(context.Background())
+
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should grant or revoke the access based on the access window", func() {
+		testCases := []struct {
+			name             string
+			now              time.Time
+			window           *domain.AccessWindow
+			closed           bool
+			expectedClosed   bool
+			expectedToggleAt time.Time
+		}{
+			{
+				name:             "grant on window opened",
+				now:              time.Date(2021, time.August, 2, 10, 0, 0, 0, time.UTC), // monday
+				window:           &domain.AccessWindow{Days: weekdays, StartTime: "09:00", EndTime: "17:00"},
+				closed:           true,
+				expectedClosed:   false,
+				expectedToggleAt: time.Date(2021, time.August, 2, 17, 0, 0, 0, time.UTC),
+			},
+			{
+				name:             "revoke on excluded day",
+				now:              time.Date(2021, time.August, 7, 10, 0, 0, 0, time.UTC), // saturday
+				window:           &domain.AccessWindow{Days: weekdays, StartTime: "09:00", EndTime: "17:00"},
+				closed:           false,
+				expectedClosed:   true,
+				expectedToggleAt: time.Date(2021, time.August, 9, 9, 0, 0, 0, time.UTC), // next monday
+			},
+			{
+				name:             "revoke outside the hours in the window timezone",
+				now:              time.Date(2021, time.August, 2, 10, 0, 0, 0, time.UTC), // 17:00 in Jakarta
+				window:           &domain.AccessWindow{StartTime: "09:00", EndTime: "17:00", Timezone: "Asia/Jakarta"},
+				closed:           false,
+				expectedClosed:   true,
+				expectedToggleAt: time.Date(2021, time.August, 3, 2, 0, 0, 0, time.UTC), // 09:00 in Jakarta
+			},
+			{
+				name:             "grant within overnight window opened on the previous day",
+				now:              time.Date(2021, time.August, 3, 2, 0, 0, 0, time.UTC), // tuesday
+				window:           &domain.AccessWindow{Days: []string{"monday"}, StartTime: "22:00", EndTime: "06:00"},
+				closed:           true,
+				expectedClosed:   false,
+				expectedToggleAt: time.Date(2021, time.August, 3, 6, 0, 0, 0, time.UTC),
 			},
 		}
 		for _, tc := range testCases {
 			s.Run(tc.name, func() {
-				s.mockRepository.On("GetByID", validApprovalActionParam.AppealID).
-					Return(tc.expectedAppealDetails, nil).
-					Once()
-				s.mockApprovalService.On("AdvanceApproval", tc.expectedAppealDetails).
-					Return(nil).Once()
-				s.mockProviderService.On("GrantAccess", tc.expectedAppealDetails).
-					Return(nil).
-					Once()
-				s.mockRepository.On("Update", mock.Anything).
-					Return(nil).
-					Once()
-				s.mockNotifier.On("Notify", tc.expectedNotifications).Return(nil).Once()
+				s.now = tc.now
+				appeal := newAppeal(1, tc.window, tc.closed)
+				untoggledAppeal := newAppeal(3, tc.window, tc.expectedClosed)
+				expectedFilters := map[string]interface{}{
+					"statuses":                 []string{domain.AppealStatusActive},
+					"access_window_toggle_due": tc.now,
+				}
+				s.mockRepository.On("Find", mock.Anything, expectedFilters).Return([]*domain.Appeal{
+					appeal,
+					newAppeal(2, nil, false),
+					untoggledAppeal,
+				}, nil).Once()
+				s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appeal, nil).Once()
+				s.mockRepository.On("GetByID", mock.Anything, uint(3)).Return(untoggledAppeal, nil).Once()
+				if tc.expectedClosed {
+					s.mockProviderService.On("RevokeAccess", mock.Anything, appeal).Return(nil).Once()
+				} else {
+					s.mockProviderService.On("GrantAccess", mock.Anything, appeal).Return(nil).Once()
+				}
+				s.mockRepository.On("Update", mock.Anything, appeal).Return(nil).Once()
+				s.mockRepository.On("Update", mock.Anything, untoggledAppeal).Return(nil).Once()
 
-				actualResult, actualError := s.service.MakeAction(tc.expectedApprovalAction)
+				actualError := s.service.ToggleAccessWindows(context.Background())
 
-				s.Equal(tc.expectedResult, actualResult)
 				s.Nil(actualError)
+				s.Equal(tc.expectedClosed, appeal.AccessWindowClosed)
+				s.Equal(tc.expectedClosed, untoggledAppeal.AccessWindowClosed)
+				s.True(tc.expectedToggleAt.Equal(*appeal.AccessWindowToggleAt), "expected %v, got %v", tc.expectedToggleAt, *appeal.AccessWindowToggleAt)
+				s.True(tc.expectedToggleAt.Equal(*untoggledAppeal.AccessWindowToggleAt))
 			})
 		}
 	})
 }
 
-// func (s *ServiceTestSuite) TestCancel() {
-// 	s.Run("should return error from")
-// }
+func (s *ServiceTestSuite) TestActivateScheduledAccess() {
+	s.Run("should return error if got error from repository", func() {
+		expectedError := errors.New("repository error")
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
 
-func (s *ServiceTestSuite) TestRevoke() {
-	s.Run("should return error if got any while getting appeal details", func() {
+		actualError := s.service.ActivateScheduledAccess(context.Background())
+
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should grant the scheduled access once the start date arrives", func() {
+		started := s.now.Add(-1 * time.Minute)
+		notStarted := s.now.Add(1 * time.Hour)
+		expired := s.now.Add(-1 * time.Second)
+		dueAppeal := &domain.Appeal{
+			ID:              1,
+			User:            "user@email.com",
+			Status:          domain.AppealStatusActive,
+			Resource:        &domain.Resource{URN: "urn"},
+			Options:         &domain.AppealOptions{StartDate: &started},
+			AccessScheduled: true,
+		}
+		s.mockRepository.On("Find", mock.Anything, map[string]interface{}{
+			"statuses":         []string{domain.AppealStatusActive},
+			"access_scheduled": true,
+			"with_approvals":   true,
+		}).Return([]*domain.Appeal{
+			dueAppeal,
+			{ID: 2, Status: domain.AppealStatusActive, Options: &domain.AppealOptions{StartDate: &notStarted}, AccessScheduled: true},
+			{ID: 3, Status: domain.AppealStatusActive, Options: &domain.AppealOptions{StartDate: &started, ExpirationDate: &expired}, AccessScheduled: true},
+			{ID: 4, Status: domain.AppealStatusActive, Options: &domain.AppealOptions{StartDate: &started}},
+			{ID: 5, Status: domain.AppealStatusActive, Options: &domain.AppealOptions{StartDate: &started}, AccessScheduled: true, UndoDeadline: &notStarted},
+		}, nil).Once()
+		s.mockProviderService.On("GrantAccess", mock.Anything, dueAppeal).Return(nil).Once()
+		s.mockRepository.On("Update", mock.Anything, dueAppeal).Return(nil).Once()
+		s.mockNotifier.On("Notify", []domain.Notification{{
+			User:    "user@email.com",
+			Message: "Your scheduled access to urn is now active",
+		}}).Return(nil).Once()
+
+		actualError := s.service.ActivateScheduledAccess(context.Background())
+
+		s.Nil(actualError)
+		s.False(dueAppeal.AccessScheduled)
+		s.mockRepository.AssertNotCalled(s.T(), "GetByID", mock.Anything, mock.Anything)
+		s.mockProviderService.AssertNumberOfCalls(s.T(), "GrantAccess", 1)
+	})
+}
+
+func (s *ServiceTestSuite) TestAddAttachment() {
+	s.Run("should return error if filename is empty", func() {
+		actualResult, actualError := s.service.AddAttachment(context.Background(), 1, "requester@email.com", " ", strings.NewReader("content"))
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAttachmentFilenameRequired.Error())
+	})
+
+	s.Run("should return error if appeal is not found", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(nil, nil).Once()
+
+		actualResult, actualError := s.service.AddAttachment(context.Background(), 1, "requester@email.com", "evidence.txt", strings.NewReader("content"))
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAppealNotFound.Error())
+	})
+
+	s.Run("should return error if actor is neither the requester, an approver, nor an admin", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{
+			ID:   1,
+			User: "requester@email.com",
+			Approvals: []*domain.Approval{
+				{Approvers: []string{"approver@email.com"}},
+			},
+		}, nil).Once()
+
+		actualResult, actualError := s.service.AddAttachment(context.Background(), 1, "outsider@email.com", "evidence.txt", strings.NewReader("content"))
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAttachmentForbidden.Error())
+		s.mockBlobStorage.AssertNotCalled(s.T(), "Put", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	s.Run("should validate the attachment size and content type", func() {
+		service := appeal.NewService(
+			s.mockRepository,
+			s.mockApprovalService,
+			s.mockResourceService,
+			s.mockProviderService,
+			s.mockPolicyService,
+			s.mockIAMService,
+			s.mockNotifier,
+			s.mockBlobStorage,
+			zap.NewNop(),
+			&appeal.Config{
+				Attachment: appeal.AttachmentConfig{
+					MaxSize:             10,
+					AllowedContentTypes: []string{"application/pdf"},
+				},
+			},
+		)
+		testCases := []struct {
+			content       string
+			expectedError error
+		}{
+			{"more than ten bytes", appeal.ErrAttachmentTooLarge},
+			{"text", appeal.ErrAttachmentTypeNotAllowed},
+		}
+		for _, tc := range testCases {
+			s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1, User: "requester@email.com"}, nil).Once()
+
+			actualResult, actualError := service.AddAttachment(context.Background(), 1, "requester@email.com", "evidence.txt", strings.NewReader(tc.content))
+
+			s.Nil(actualResult)
+			s.EqualError(actualError, tc.expectedError.Error())
+		}
+	})
+
+	s.Run("should return error if rejected by the scanner", func() {
+		mockScanner := new(mocks.AttachmentScanner)
+		s.service.AttachmentScanner = mockScanner
+		defer func() { s.service.AttachmentScanner = nil }()
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1, User: "requester@email.com"}, nil).Once()
+		mockScanner.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("infected")).Once()
+
+		actualResult, actualError := s.service.AddAttachment(context.Background(), 1, "requester@email.com", "evidence.txt", strings.NewReader("content"))
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAttachmentRejected.Error()+": infected")
+	})
+
+	s.Run("should remove the stored content if failed to record the attachment", func() {
 		expectedError := errors.New("repository error")
-		s.mockRepository.On("GetByID", mock.Anything).Return(nil, expectedError).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1, User: "requester@email.com"}, nil).Once()
+		s.mockBlobStorage.On("Put", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockRepository.On("AddAttachment", mock.Anything, mock.Anything).Return(expectedError).Once()
+		s.mockBlobStorage.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
 
-		actualResult, actualError := s.service.Revoke(0, "", "")
+		actualResult, actualError := s.service.AddAttachment(context.Background(), 1, "requester@email.com", "evidence.txt", strings.NewReader("content"))
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
+		s.mockBlobStorage.AssertExpectations(s.T())
 	})
 
-	s.Run("should return error if appeal not found", func() {
-		s.mockRepository.On("GetByID", mock.Anything).Return(nil, nil).Once()
-		expectedError := appeal.ErrAppealNotFound
+	s.Run("should store the content and record the attachment on success", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1, User: "requester@email.com"}, nil).Once()
+		expectedKey := fmt.Sprintf("appeals/1/%d_evidence.txt", s.now.UnixNano())
+		s.mockBlobStorage.On("Put", mock.Anything, expectedKey, mock.Anything).Return(nil).Once()
+		expectedAttachment := &domain.Attachment{
+			AppealID:    1,
+			Filename:    "evidence.txt",
+			ContentType: "text/plain",
+			Size:        7,
+			StorageKey:  expectedKey,
+		}
+		s.mockRepository.On("AddAttachment", mock.Anything, expectedAttachment).Return(nil).Once()
+
+		actualResult, actualError := s.service.AddAttachment(context.Background(), 1, "requester@email.com", "../evidence.txt", strings.NewReader("content"))
+
+		s.Nil(actualError)
+		s.Equal(expectedAttachment, actualResult)
+	})
+}
 
-		actualResult, actualError := s.service.Revoke(0, "", "")
+func (s *ServiceTestSuite) TestListAttachments() {
+	s.Run("should return error if viewer is neither the requester, an approver, nor an admin", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1, User: "requester@email.com"}, nil).Once()
+
+		actualResult, actualError := s.service.ListAttachments(context.Background(), 1, "outsider@email.com")
 
 		s.Nil(actualResult)
-		s.EqualError(actualError, expectedError.Error())
+		s.EqualError(actualError, appeal.ErrAttachmentForbidden.Error())
+		s.mockRepository.AssertNotCalled(s.T(), "GetAttachments", mock.Anything, uint(1))
 	})
 
-	appealID := uint(1)
-	actor := "user@email.com"
-	reason := "test-reason"
+	s.Run("should return the attachments of the appeal to the requester", func() {
+		expectedAttachments := []*domain.Attachment{{ID: 1, AppealID: 1}}
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(&domain.Appeal{ID: 1, User: "requester@email.com"}, nil).Once()
+		s.mockRepository.On("GetAttachments", mock.Anything, uint(1)).Return(expectedAttachments, nil).Once()
+
+		actualResult, actualError := s.service.ListAttachments(context.Background(), 1, "requester@email.com")
+
+		s.Nil(actualError)
+		s.Equal(expectedAttachments, actualResult)
+	})
+}
+
+func (s *ServiceTestSuite) TestGetAttachmentContent() {
+	s.Run("should return error if attachment is not found", func() {
+		s.mockRepository.On("GetAttachmentByID", mock.Anything, uint(1)).Return(nil, nil).Once()
+
+		actualAttachment, actualContent, actualError := s.service.GetAttachmentContent(context.Background(), 1, "requester@email.com")
+
+		s.Nil(actualAttachment)
+		s.Nil(actualContent)
+		s.EqualError(actualError, appeal.ErrAttachmentNotFound.Error())
+	})
+
+	s.Run("should return error if viewer is neither the requester, an approver, nor an admin", func() {
+		s.mockRepository.On("GetAttachmentByID", mock.Anything, uint(1)).Return(&domain.Attachment{ID: 1, AppealID: 2}, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(2)).Return(&domain.Appeal{ID: 2, User: "requester@email.com"}, nil).Once()
+
+		actualAttachment, actualContent, actualError := s.service.GetAttachmentContent(context.Background(), 1, "outsider@email.com")
+
+		s.Nil(actualAttachment)
+		s.Nil(actualContent)
+		s.EqualError(actualError, appeal.ErrAttachmentForbidden.Error())
+	})
 
+	s.Run("should return the attachment content from the storage", func() {
+		expectedAttachment := &domain.Attachment{ID: 1, AppealID: 2, StorageKey: "appeals/2/evidence.txt"}
+		expectedContent := ioutil.NopCloser(strings.NewReader("content"))
+		s.mockRepository.On("GetAttachmentByID", mock.Anything, uint(1)).Return(expectedAttachment, nil).Once()
+		s.mockRepository.On("GetByID", mock.Anything, uint(2)).Return(&domain.Appeal{
+			ID:   2,
+			User: "requester@email.com",
+			Approvals: []*domain.Approval{
+				{Approvers: []string{"approver@email.com"}},
+			},
+		}, nil).Once()
+		s.mockBlobStorage.On("Get", mock.Anything, expectedAttachment.StorageKey).Return(expectedContent, nil).Once()
+
+		actualAttachment, actualContent, actualError := s.service.GetAttachmentContent(context.Background(), 1, "approver@email.com")
+
+		s.Nil(actualError)
+		s.Equal(expectedAttachment, actualAttachment)
+		s.Equal(expectedContent, actualContent)
+	})
+}
+
+func (s *ServiceTestSuite) TestAddComment() {
 	appealDetails := &domain.Appeal{
-		ID:         appealID,
-		ResourceID: 1,
-		Resource: &domain.Resource{
-			ID:  1,
-			URN: "urn",
+		ID:   1,
+		User: "requester@email.com",
+		Approvals: []*domain.Approval{
+			{Name: "step_1", Approvers: []string{"approver@email.com"}},
 		},
 	}
 
-	s.Run("should return error if got any while updating appeal", func() {
-		s.mockRepository.On("GetByID", appealID).Return(appealDetails, nil).Once()
-		expectedError := errors.New("repository error")
-		s.mockRepository.On("Update", mock.Anything).Return(expectedError).Once()
+	s.Run("should return error if body is empty", func() {
+		actualResult, actualError := s.service.AddComment(context.Background(), 1, "approver@email.com", " ", "")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrCommentBodyRequired.Error())
+	})
 
-		actualResult, actualError := s.service.Revoke(appealID, actor, reason)
+	s.Run("should return error if visibility is invalid", func() {
+		actualResult, actualError := s.service.AddComment(context.Background(), 1, "approver@email.com", "body", "invalid")
 
 		s.Nil(actualResult)
-		s.EqualError(actualError, expectedError.Error())
+		s.EqualError(actualError, appeal.ErrInvalidCommentVisibility.Error())
 	})
 
-	s.Run("should return error and rollback updated appeal if failed granting the access to the provider", func() {
-		s.mockRepository.On("GetByID", appealID).Return(appealDetails, nil).Once()
-		s.mockRepository.On("Update", mock.Anything).Return(nil).Once()
-		expectedError := errors.New("provider service error")
-		s.mockProviderService.On("RevokeAccess", mock.Anything).Return(expectedError).Once()
-		s.mockRepository.On("Update", appealDetails).Return(nil).Once()
+	s.Run("should return error if actor is neither the requester nor an approver", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
 
-		actualResult, actualError := s.service.Revoke(appealID, actor, reason)
+		actualResult, actualError := s.service.AddComment(context.Background(), 1, "other@email.com", "body", "")
 
 		s.Nil(actualResult)
-		s.EqualError(actualError, expectedError.Error())
+		s.EqualError(actualError, appeal.ErrCommentForbidden.Error())
 	})
 
-	s.Run("should return appeal and nil error on success", func() {
-		s.mockRepository.On("GetByID", appealID).Return(appealDetails, nil).Once()
-		expectedAppeal := &domain.Appeal{}
-		*expectedAppeal = *appealDetails
-		expectedAppeal.Status = domain.AppealStatusTerminated
-		expectedAppeal.RevokedAt = s.now
-		expectedAppeal.RevokedBy = actor
-		expectedAppeal.RevokeReason = reason
-		s.mockRepository.On("Update", expectedAppeal).Return(nil).Once()
-		s.mockProviderService.On("RevokeAccess", appealDetails).Return(nil).Once()
-		s.mockNotifier.On("Notify", mock.Anything).Return(nil).Once()
+	s.Run("should return error if requester leaves a private comment", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
 
-		actualResult, actualError := s.service.Revoke(appealID, actor, reason)
+		actualResult, actualError := s.service.AddComment(context.Background(), 1, "requester@email.com", "body", domain.CommentVisibilityPrivate)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrPrivateCommentForbidden.Error())
+	})
+
+	s.Run("should store the comment with public visibility by default", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+		expectedComment := &domain.AppealComment{
+			AppealID:   1,
+			CreatedBy:  "requester@email.com",
+			Body:       "body",
+			Visibility: domain.CommentVisibilityPublic,
+		}
+		s.mockRepository.On("AddComment", mock.Anything, expectedComment).Return(nil).Once()
+
+		actualResult, actualError := s.service.AddComment(context.Background(), 1, "requester@email.com", "body", "")
+
+		s.Nil(actualError)
+		s.Equal(expectedComment, actualResult)
+	})
+}
+
+func (s *ServiceTestSuite) TestGetComments() {
+	appealDetails := &domain.Appeal{
+		ID:   1,
+		User: "requester@email.com",
+		Approvals: []*domain.Approval{
+			{Name: "step_1", Approvers: []string{"approver@email.com", "requester@email.com"}},
+		},
+	}
+	publicComment := &domain.AppealComment{ID: 1, AppealID: 1, Visibility: domain.CommentVisibilityPublic}
+	privateComment := &domain.AppealComment{ID: 2, AppealID: 1, Visibility: domain.CommentVisibilityPrivate}
+
+	s.Run("should return error if appeal is not found", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(nil, nil).Once()
+
+		actualResult, actualError := s.service.GetComments(context.Background(), 1, "approver@email.com")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrAppealNotFound.Error())
+	})
+
+	s.Run("should return error if viewer is neither the requester, an approver, nor an admin", func() {
+		s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+
+		actualResult, actualError := s.service.GetComments(context.Background(), 1, "other@email.com")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, appeal.ErrCommentForbidden.Error())
+		s.mockRepository.AssertNotCalled(s.T(), "GetComments", mock.Anything, uint(1))
+	})
+
+	s.Run("should filter the comments based on the viewer", func() {
+		testCases := []struct {
+			name             string
+			viewer           string
+			expectedComments []*domain.AppealComment
+		}{
+			{
+				name:             "approver sees all comments",
+				viewer:           "approver@email.com",
+				expectedComments: []*domain.AppealComment{publicComment, privateComment},
+			},
+			{
+				name:             "admin sees all comments",
+				viewer:           "admin@email.com",
+				expectedComments: []*domain.AppealComment{publicComment, privateComment},
+			},
+			{
+				name:             "requester only sees public comments even if listed as an approver",
+				viewer:           "requester@email.com",
+				expectedComments: []*domain.AppealComment{publicComment},
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				s.mockRepository.On("GetByID", mock.Anything, uint(1)).Return(appealDetails, nil).Once()
+				s.mockRepository.On("GetComments", mock.Anything, uint(1)).Return([]*domain.AppealComment{publicComment, privateComment}, nil).Once()
+
+				actualResult, actualError := s.service.GetComments(context.Background(), 1, tc.viewer)
+
+				s.Nil(actualError)
+				s.Equal(tc.expectedComments, actualResult)
+			})
+		}
+	})
+}
+
+func (s *ServiceTestSuite) TestImport() {
+	s.Run("should return error if a required column is missing", func() {
+		actualReport, actualError := s.service.Import(context.Background(), strings.NewReader("user,role\n"))
+
+		s.Nil(actualReport)
+		s.ErrorIs(actualError, appeal.ErrInvalidImportHeader)
+	})
+
+	s.Run("should import the active access and report the failed rows", func() {
+		resources := []*domain.Resource{
+			{ID: 1, ProviderType: "provider_type", ProviderURN: "provider_urn", Type: "resource_type", URN: "urn-1"},
+			{ID: 2, ProviderType: "provider_type", ProviderURN: "provider_urn", Type: "resource_type", URN: "urn-2"},
+			{ID: 3, ProviderType: "other_provider_type", ProviderURN: "provider_urn", Type: "resource_type", URN: "urn-2"},
+		}
+		providers := []*domain.Provider{
+			{
+				Type: "provider_type",
+				URN:  "provider_urn",
+				Config: &domain.ProviderConfig{
+					Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+					Resources: []*domain.ResourceConfig{
+						{Type: "resource_type", Roles: []*domain.RoleConfig{{ID: "viewer"}}},
+					},
+				},
+			},
+		}
+		rows := strings.Join([]string{
+			"user,resource_urn,role,expiration_date,status,provider_type",
+			"user@email.com,urn-1,viewer,,active,",
+			"user@email.com,urn-2,viewer,,active,",
+			"user@email.com,urn-2,viewer,,active,provider_type",
+			"user@email.com,urn-1,editor,,active,",
+			"user@email.com,urn-1,viewer,2020-01-01,active,",
+			"user@email.com,urn-1,viewer,,granted,",
+			"user@email.com,urn-3,viewer,,active,",
+			"invalid-email,urn-1,viewer,,active,",
+		}, "\n")
+		s.mockResourceService.On("Find", mock.Anything, map[string]interface{}{
+			"urns": []string{"urn-1", "urn-2", "urn-3"},
+		}).Return(resources, nil).Once()
+		s.mockProviderService.On("Find", mock.Anything).Return(providers, nil).Once()
+		s.mockRepository.On("GetActiveAccess", mock.Anything, "user@email.com", uint(1), "viewer", mock.Anything).Return(nil, nil).Once()
+		s.mockRepository.On("GetActiveAccess", mock.Anything, "user@email.com", uint(2), "viewer", mock.Anything).Return(&domain.Appeal{ID: 1}, nil).Once()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			appeals := args.Get(1).([]*domain.Appeal)
+			s.Equal(domain.AppealStatusActive, appeals[0].Status)
+			s.Empty(appeals[0].Approvals)
+			appeals[0].ID = 10
+		}).Return(nil).Once()
+
+		actualReport, actualError := s.service.Import(context.Background(), strings.NewReader(rows))
+
+		s.Nil(actualError)
+		s.Equal(1, actualReport.Succeeded)
+		s.Equal(7, actualReport.Failed)
+		s.Equal(&appeal.ImportRowResult{
+			Line:        2,
+			User:        "user@email.com",
+			ResourceURN: "urn-1",
+			Role:        "viewer",
+			AppealID:    10,
+			Status:      domain.AppealStatusActive,
+		}, actualReport.Rows[0])
+		expectedErrors := []error{
+			appeal.ErrImportResourceAmbiguous,
+			appeal.ErrAppealDuplicate,
+			appeal.ErrInvalidRole,
+			appeal.ErrImportAccessExpired,
+			appeal.ErrInvalidImportStatus,
+			appeal.ErrResourceNotFound,
+			appeal.ErrInvalidUser,
+		}
+		for i, expectedError := range expectedErrors {
+			s.Contains(actualReport.Rows[i+1].Error, expectedError.Error())
+		}
+	})
+}
+
+func (s *ServiceTestSuite) TestDispatchOutboxMessages() {
+	s.Run("should return error if the messages fail to be fetched", func() {
+		expectedError := errors.New("db error")
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("GetOutboxMessages", mock.Anything, s.now.Add(-time.Minute), 100).Return(nil, expectedError).Once()
+
+		actualError := s.service.DispatchOutboxMessages(context.Background())
+
+		s.ErrorIs(actualError, expectedError)
+	})
+
+	s.Run("should send the messages left unsent and delete them", func() {
+		sub, _ := s.service.StatusBroker.Subscribe([]uint{1}, 0)
+		defer sub.Close()
+		notifications := []domain.Notification{{User: "user@email.com", Message: "Your appeal to urn has been approved"}}
+		messages := []*domain.OutboxMessage{
+			{ID: 1, StatusEvent: &domain.AppealStatusEvent{AppealID: 1, Status: domain.AppealStatusActive}, Notifications: notifications},
+			{ID: 2, Notifications: notifications},
+		}
+		s.mockRepository.On("WithTransaction", mock.Anything, mock.Anything).Return(runInTransaction(s.mockRepository)).Once()
+		s.mockRepository.On("GetOutboxMessages", mock.Anything, s.now.Add(-time.Minute), 100).Return(messages, nil).Once()
+		s.mockNotifier.On("Notify", notifications).Return(nil).Twice()
+		s.mockRepository.On("DeleteOutboxMessages", mock.Anything, []uint{1, 2}).Return(nil).Once()
+
+		actualError := s.service.DispatchOutboxMessages(context.Background())
 
-		s.Equal(expectedAppeal, actualResult)
 		s.Nil(actualError)
+		actualEvent := <-sub.Events()
+		s.Equal(domain.AppealStatusActive, actualEvent.Status)
+		s.mockNotifier.AssertExpectations(s.T())
 	})
 }
 
@@ -0,0 +1,45 @@
+package appeal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// getSLADeadline returns the time the approval step at the index breaches its SLA. The SLA counts from
+// the time the step started awaiting approval, which is when the previous step completed. It returns
+// false if the step has no SLA
+func getSLADeadline(a *domain.Appeal, index int) (time.Time, bool, error) {
+	approval := a.Approvals[index]
+	if approval.SLA == "" {
+		return time.Time{}, false, nil
+	}
+	sla, err := time.ParseDuration(approval.SLA)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: %q", ErrInvalidStepSLA, approval.SLA)
+	}
+
+	startedAt := a.CreatedAt
+	if index > 0 {
+		if previousCompletedAt := a.Approvals[index-1].StatusChangedAt; previousCompletedAt != nil && previousCompletedAt.After(startedAt) {
+			startedAt = *previousCompletedAt
+		}
+	}
+	return startedAt.Add(sla), true, nil
+}
+
+// isSLABreached returns true if the current pending approval step of the appeal is past its SLA
+func isSLABreached(a *domain.Appeal, now time.Time) (bool, error) {
+	for i, approval := range a.Approvals {
+		if approval.Status != domain.ApprovalStatusPending {
+			continue
+		}
+		deadline, ok, err := getSLADeadline(a, i)
+		if err != nil || !ok {
+			return false, err
+		}
+		return now.After(deadline), nil
+	}
+	return false, nil
+}
@@ -0,0 +1,111 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+// isUndoable checks whether the final approval of the appeal is still within the undo window
+func isUndoable(a *domain.Appeal, now time.Time) bool {
+	return a.UndoDeadline != nil && now.Before(*a.UndoDeadline)
+}
+
+// UndoApproval undoes the final approval of an approved appeal within the undo window, before its access is
+// granted. The final approval step goes back to pending, along with the appeal, for the approvers to act on it
+// again. Only the approver of the step and the admins are allowed to undo it. The undo is recorded as a private
+// comment on the appeal
+func (s *Service) UndoApproval(ctx context.Context, appealID uint, actor string) (result *domain.Appeal, err error) {
+	logger := s.getLogger(ctx).With(zap.Uint("appeal_id", appealID), zap.String("actor", actor))
+	logger.Info("undoing final approval")
+	defer func() {
+		if err != nil {
+			logger.Error("failed to undo final approval", zap.Error(err))
+		} else {
+			logger.Info("final approval undone", zap.String("user", result.User), zap.String("status", result.Status))
+		}
+	}()
+
+	if appealID == 0 {
+		return nil, ErrAppealIDEmptyParam
+	}
+	if err := s.validator.Var(actor, "required,email"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	}
+	if err := checkActor(ctx, actor); err != nil {
+		return nil, err
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal == nil {
+		return nil, ErrAppealNotFound
+	}
+	if appeal.Status != domain.AppealStatusActive || !appeal.AccessScheduled || !isUndoable(appeal, s.TimeNow()) {
+		return nil, ErrUndoWindowClosed
+	}
+
+	var finalApproval *domain.Approval
+	for i := len(appeal.Approvals) - 1; i >= 0; i-- {
+		if a := appeal.Approvals[i]; a.Status == domain.ApprovalStatusApproved && a.Actor != nil {
+			finalApproval = a
+			break
+		}
+	}
+	if finalApproval == nil {
+		return nil, ErrUndoWindowClosed
+	}
+	if *finalApproval.Actor != actor && !s.config.isAdmin(actor) {
+		return nil, ErrUndoForbidden
+	}
+
+	finalApproval.Status = domain.ApprovalStatusPending
+	finalApproval.Actor = nil
+	finalApproval.Reason = ""
+	finalApproval.IsOverridden = false
+	finalApproval.OnBehalfOf = ""
+	finalApproval.Attestation = nil
+	finalApproval.SLAMet = nil
+	finalApproval.ReminderLevel = 0
+	finalApproval.UpdatedAt = s.TimeNow()
+	reopenedAt := finalApproval.UpdatedAt
+	finalApproval.StatusChangedAt = &reopenedAt
+	appeal.Status = domain.AppealStatusPending
+	appeal.AccessScheduled = false
+	appeal.UndoDeadline = nil
+
+	// the undo is only stored along with its audit comment
+	var message *domain.OutboxMessage
+	if err := s.repo.WithTransaction(ctx, func(tx domain.AppealRepository) error {
+		if err := tx.Update(ctx, appeal); err != nil {
+			return err
+		}
+		if err := tx.AddComment(ctx, &domain.AppealComment{
+			AppealID:   appeal.ID,
+			CreatedBy:  actor,
+			Body:       fmt.Sprintf("undid the final approval of step %q", finalApproval.Name),
+			Visibility: domain.CommentVisibilityPrivate,
+		}); err != nil {
+			return err
+		}
+
+		notifications := append([]domain.Notification{{
+			User:    appeal.User,
+			Message: fmt.Sprintf("The approval of your appeal to %s has been undone, it's pending again", appeal.Resource.URN),
+			Labels:  appeal.Labels,
+		}}, s.getApprovalNotifications(appeal)...)
+		var err error
+		message, err = s.addOutboxMessage(ctx, tx, s.newStatusEvent(appeal, domain.AppealStatusActive, domain.AppealActionNameUndo, finalApproval.Name, actor), notifications)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	s.dispatchOutboxMessage(ctx, message)
+	return appeal, nil
+}
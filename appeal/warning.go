@@ -0,0 +1,92 @@
+package appeal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
+)
+
+const (
+	WarningCodeFirstPrivilegedAccess = "first_privileged_access"
+	WarningCodeUnusualDuration       = "unusual_duration"
+)
+
+// WarningRule evaluates an appeal being created against the summary of the user's previous appeals and
+// returns a warning if the appeal looks unusual, or nil otherwise
+type WarningRule interface {
+	Evaluate(ctx context.Context, a *domain.Appeal, history *domain.AppealHistory) (*domain.AppealWarning, error)
+}
+
+// WarningsConfig configures the built-in rules flagging unusual appeals to the approvers
+type WarningsConfig struct {
+	// PrivilegedRoles flags the first appeal of a user to any of these roles
+	PrivilegedRoles []string `mapstructure:"privileged_roles"`
+	// UnusualDurationFactor flags the appeals requesting an access longer than the user's longest
+	// previously requested access multiplied by this factor. Set it to 0 to disable the rule
+	UnusualDurationFactor float64 `mapstructure:"unusual_duration_factor"`
+}
+
+func (c WarningsConfig) getRules(now func() time.Time) []WarningRule {
+	var rules []WarningRule
+	if len(c.PrivilegedRoles) > 0 {
+		rules = append(rules, &firstPrivilegedAccessRule{roles: c.PrivilegedRoles})
+	}
+	if c.UnusualDurationFactor > 0 {
+		rules = append(rules, &unusualDurationRule{factor: c.UnusualDurationFactor, now: now})
+	}
+	return rules
+}
+
+type firstPrivilegedAccessRule struct {
+	roles []string
+}
+
+func (r *firstPrivilegedAccessRule) Evaluate(ctx context.Context, a *domain.Appeal, history *domain.AppealHistory) (*domain.AppealWarning, error) {
+	if !utils.ContainsString(r.roles, a.Role) || history.RoleGranted {
+		return nil, nil
+	}
+	return &domain.AppealWarning{
+		Code:    WarningCodeFirstPrivilegedAccess,
+		Message: fmt.Sprintf("first time %s requests the privileged role %q", a.User, a.Role),
+	}, nil
+}
+
+type unusualDurationRule struct {
+	factor float64
+	now    func() time.Time
+}
+
+func (r *unusualDurationRule) Evaluate(ctx context.Context, a *domain.Appeal, history *domain.AppealHistory) (*domain.AppealWarning, error) {
+	// the user requested permanent access before, no requested duration is unusual
+	if history.PermanentAccessRequested {
+		return nil, nil
+	}
+	longest := history.LongestRequestedDuration
+	if longest <= 0 {
+		return nil, nil
+	}
+
+	var requested time.Duration
+	if hasExpirationDate(a) {
+		requested = a.Options.ExpirationDate.Sub(getAccessStartTime(a, r.now()))
+	}
+	if requested > 0 && float64(requested) <= float64(longest)*r.factor {
+		return nil, nil
+	}
+
+	requestedText := "permanent access"
+	if requested > 0 {
+		requestedText = requested.Round(time.Hour).String()
+	}
+	return &domain.AppealWarning{
+		Code:    WarningCodeUnusualDuration,
+		Message: fmt.Sprintf("requested %s while the longest previous access of %s was %s", requestedText, a.User, longest.Round(time.Hour)),
+	}, nil
+}
+
+func hasExpirationDate(a *domain.Appeal) bool {
+	return a.Options != nil && a.Options.ExpirationDate != nil && !a.Options.ExpirationDate.IsZero()
+}
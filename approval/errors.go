@@ -8,4 +8,6 @@ var (
 	ErrApprovalStepConditionNotFound  = errors.New("unable to resolve designated condition")
 	ErrNilResourceInAppeal            = errors.New("unable to resolve resource from the appeal")
 	ErrInvalidConditionField          = errors.New("invalid condition field")
+	ErrEmptyApprover                  = errors.New("approver can't be empty")
+	ErrInvalidDateRange               = errors.New("the start of the date range should be before its end")
 )
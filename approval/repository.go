@@ -1,6 +1,10 @@
 package approval
 
 import (
+	"context"
+	"time"
+
+	"github.com/odpf/guardian/auth"
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/model"
 	"github.com/odpf/guardian/utils"
@@ -15,12 +19,23 @@ func NewRepository(db *gorm.DB) *repository {
 	return &repository{db}
 }
 
-func (r *repository) ListApprovals(conditions *domain.ListApprovalsFilter) ([]*domain.Approval, error) {
+// scoped restricts the queries to the approvals of the appeals of the organization carried by ctx, if any. The
+// approvals of the appeals without an organization are only visible to the callers without an organization
+func (r *repository) scoped(ctx context.Context) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+		appealIDs := r.db.WithContext(ctx).Model(&model.Appeal{}).Select(`"id"`).Where(`"organization_id" = ?`, organizationID)
+		db = db.Where(`"approvals"."appeal_id" IN (?)`, appealIDs)
+	}
+	return db
+}
+
+func (r *repository) ListApprovals(ctx context.Context, conditions *domain.ListApprovalsFilter) ([]*domain.Approval, error) {
 	if err := utils.ValidateStruct(conditions); err != nil {
 		return nil, err
 	}
 
-	db := r.db
+	db := r.db.WithContext(ctx)
 	if conditions.User != "" {
 		db = db.Where("email = ?", conditions.User)
 	}
@@ -35,7 +50,7 @@ func (r *repository) ListApprovals(conditions *domain.ListApprovalsFilter) ([]*d
 		approvalIDs = append(approvalIDs, a.ApprovalID)
 	}
 
-	db = r.db.Joins("Appeal")
+	db = r.scoped(ctx).Joins("Appeal")
 	if conditions.Statuses != nil {
 		db = db.Where(`"approvals"."status" IN ?`, conditions.Statuses)
 	}
@@ -60,7 +75,7 @@ func (r *repository) ListApprovals(conditions *domain.ListApprovalsFilter) ([]*d
 	return records, nil
 }
 
-func (r *repository) BulkInsert(approvals []*domain.Approval) error {
+func (r *repository) BulkInsert(ctx context.Context, approvals []*domain.Approval) error {
 	models := []*model.Approval{}
 	for _, a := range approvals {
 		m := new(model.Approval)
@@ -71,7 +86,7 @@ func (r *repository) BulkInsert(approvals []*domain.Approval) error {
 		models = append(models, m)
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(models).Error; err != nil {
 			return err
 		}
@@ -88,3 +103,112 @@ func (r *repository) BulkInsert(approvals []*domain.Approval) error {
 		return nil
 	})
 }
+
+func (r *repository) AddApprover(ctx context.Context, approver *domain.Approver) error {
+	m := new(model.Approver)
+	if err := m.FromDomain(approver); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	newApprover, err := m.ToDomain()
+	if err != nil {
+		return err
+	}
+
+	*approver = *newApprover
+	return nil
+}
+
+type approverActions struct {
+	ApprovedCount      int
+	RejectedCount      int
+	AvgResponseSeconds *float64
+}
+
+// GetApproverStats aggregates the approval steps actioned by the approver within the date range, the admin
+// overrides excluded. The response time of a step starts once it's assigned, or once it's created otherwise
+func (r *repository) GetApproverStats(ctx context.Context, approver string, filter *domain.ApproverStatsFilter) (*domain.ApproverStats, error) {
+	db := r.scoped(ctx).
+		Model(&model.Approval{}).
+		Select(`COUNT(CASE WHEN "approvals"."status" = ? THEN 1 END) AS "approved_count", COUNT(CASE WHEN "approvals"."status" = ? THEN 1 END) AS "rejected_count", AVG(EXTRACT(EPOCH FROM ("approvals"."updated_at" - COALESCE("approvals"."assigned_at", "approvals"."created_at")))) AS "avg_response_seconds"`, domain.ApprovalStatusApproved, domain.ApprovalStatusRejected).
+		Where(`"approvals"."actor" = ? AND "approvals"."is_overridden" = ?`, approver, false).
+		Where(`"approvals"."status" IN ?`, []string{domain.ApprovalStatusApproved, domain.ApprovalStatusRejected})
+	if filter.From != nil {
+		db = db.Where(`"approvals"."updated_at" >= ?`, *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where(`"approvals"."updated_at" < ?`, *filter.To)
+	}
+
+	var actions approverActions
+	if err := db.Find(&actions).Error; err != nil {
+		return nil, err
+	}
+
+	var backlog struct {
+		Backlog int
+	}
+	if err := r.scoped(ctx).
+		Model(&model.Approval{}).
+		Select(`COUNT(DISTINCT "approvals"."id") AS "backlog"`).
+		Joins(`JOIN "approvers" ON "approvers"."approval_id" = "approvals"."id" AND "approvers"."deleted_at" IS NULL`).
+		Joins(`JOIN "appeals" ON "appeals"."id" = "approvals"."appeal_id"`).
+		Where(`"approvers"."email" = ? AND "approvals"."status" = ? AND "appeals"."status" = ?`, approver, domain.ApprovalStatusPending, domain.AppealStatusPending).
+		Find(&backlog).
+		Error; err != nil {
+		return nil, err
+	}
+
+	stats := &domain.ApproverStats{
+		Approver:      approver,
+		ApprovedCount: actions.ApprovedCount,
+		RejectedCount: actions.RejectedCount,
+		Backlog:       backlog.Backlog,
+	}
+	if actions.AvgResponseSeconds != nil {
+		stats.AverageResponseTime = time.Duration(*actions.AvgResponseSeconds * float64(time.Second))
+	}
+	return stats, nil
+}
+
+type approverLoad struct {
+	Approver       string
+	PendingCount   int
+	LastAssignedAt *time.Time
+}
+
+// GetApproverLoads returns the review load of each of the approvers, counting the approval steps assigned
+// to them on the pending appeals. Every approver is listed, the ones never assigned have an empty load
+func (r *repository) GetApproverLoads(ctx context.Context, approvers []string) (map[string]*domain.ApproverLoad, error) {
+	loads := map[string]*domain.ApproverLoad{}
+	for _, approver := range approvers {
+		loads[approver] = &domain.ApproverLoad{Approver: approver}
+	}
+	if len(approvers) == 0 {
+		return loads, nil
+	}
+
+	var rows []*approverLoad
+	if err := r.scoped(ctx).
+		Model(&model.Approval{}).
+		Select(`"approvals"."assignee" AS "approver", COUNT(CASE WHEN "approvals"."status" = ? AND "appeals"."status" = ? THEN 1 END) AS "pending_count", MAX("approvals"."assigned_at") AS "last_assigned_at"`, domain.ApprovalStatusPending, domain.AppealStatusPending).
+		Joins(`JOIN "appeals" ON "appeals"."id" = "approvals"."appeal_id"`).
+		Where(`"approvals"."assignee" IN ?`, approvers).
+		Group(`"approvals"."assignee"`).
+		Find(&rows).
+		Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if load, ok := loads[row.Approver]; ok {
+			load.PendingCount = row.PendingCount
+			load.LastAssignedAt = row.LastAssignedAt
+		}
+	}
+	return loads, nil
+}
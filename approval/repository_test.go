@@ -1,14 +1,18 @@
 package approval_test
 
 import (
+	"context"
+
 	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/odpf/guardian/approval"
+	"github.com/odpf/guardian/auth"
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/mocks"
 	"github.com/odpf/guardian/utils"
@@ -57,7 +61,7 @@ func (s *RepositoryTestSuite) TearDownTest() {
 }
 
 func (s *RepositoryTestSuite) TestBulkInsert() {
-	expectedQuery := regexp.QuoteMeta(`INSERT INTO "approvals" ("name","index","appeal_id","status","actor","policy_id","policy_version","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10),($11,$12,$13,$14,$15,$16,$17,$18,$19,$20) RETURNING "id"`)
+	expectedQuery := regexp.QuoteMeta(`INSERT INTO "approvals" ("name","index","appeal_id","status","actor","policy_id","policy_version","reason","is_overridden","added_by","last_reminded_at","reminder_level","attestation","notification_channel","sla","sla_met","assignment_strategy","reassign_after","require_reason","delegation_chain","on_behalf_of","assignee","assigned_at","acknowledged_by","acknowledged_at","status_changed_at","changes_requested_at","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30),($31,$32,$33,$34,$35,$36,$37,$38,$39,$40,$41,$42,$43,$44,$45,$46,$47,$48,$49,$50,$51,$52,$53,$54,$55,$56,$57,$58,$59,$60) RETURNING "id"`)
 
 	actor := "user@email.com"
 	approvals := []*domain.Approval{
@@ -91,6 +95,26 @@ func (s *RepositoryTestSuite) TestBulkInsert() {
 			a.Actor,
 			a.PolicyID,
 			a.PolicyVersion,
+			a.Reason,
+			a.IsOverridden,
+			a.AddedBy,
+			a.LastRemindedAt,
+			a.ReminderLevel,
+			nil,
+			a.NotificationChannel,
+			a.SLA,
+			a.SLAMet,
+			a.AssignmentStrategy,
+			a.ReassignAfter,
+			a.RequireReason,
+			nil,
+			a.OnBehalfOf,
+			a.Assignee,
+			a.AssignedAt,
+			a.AcknowledgedBy,
+			a.AcknowledgedAt,
+			a.StatusChangedAt,
+			a.ChangesRequestedAt,
 			utils.AnyTime{},
 			utils.AnyTime{},
 			gorm.DeletedAt{},
@@ -105,7 +129,7 @@ func (s *RepositoryTestSuite) TestBulkInsert() {
 			WillReturnError(expectedError)
 		s.dbmock.ExpectRollback()
 
-		actualError := s.repository.BulkInsert(approvals)
+		actualError := s.repository.BulkInsert(context.Background(), approvals)
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -123,7 +147,7 @@ func (s *RepositoryTestSuite) TestBulkInsert() {
 			WillReturnRows(expectedRows)
 		s.dbmock.ExpectCommit()
 
-		actualError := s.repository.BulkInsert(approvals)
+		actualError := s.repository.BulkInsert(context.Background(), approvals)
 
 		s.Nil(actualError)
 		for i, a := range approvals {
@@ -132,6 +156,159 @@ func (s *RepositoryTestSuite) TestBulkInsert() {
 	})
 }
 
+func (s *RepositoryTestSuite) TestAddApprover() {
+	expectedQuery := regexp.QuoteMeta(`INSERT INTO "approvers" ("approval_id","appeal_id","email","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)
+
+	approver := &domain.Approver{
+		ApprovalID: 11,
+		AppealID:   1,
+		Email:      "approver@email.com",
+	}
+	expectedArgs := []driver.Value{
+		approver.ApprovalID,
+		approver.AppealID,
+		approver.Email,
+		utils.AnyTime{},
+		utils.AnyTime{},
+		gorm.DeletedAt{},
+	}
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs(expectedArgs...).
+			WillReturnError(expectedError)
+		s.dbmock.ExpectRollback()
+
+		actualError := s.repository.AddApprover(context.Background(), approver)
+
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return nil error on success", func() {
+		expectedID := uint(1)
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs(expectedArgs...).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+		s.dbmock.ExpectCommit()
+
+		actualError := s.repository.AddApprover(context.Background(), approver)
+
+		s.Nil(actualError)
+		s.Equal(expectedID, approver.ID)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetApproverLoads() {
+	expectedQuery := regexp.QuoteMeta(`SELECT "approvals"."assignee" AS "approver", COUNT(CASE WHEN "approvals"."status" = $1 AND "appeals"."status" = $2 THEN 1 END) AS "pending_count", MAX("approvals"."assigned_at") AS "last_assigned_at" FROM "approvals" JOIN "appeals" ON "appeals"."id" = "approvals"."appeal_id" WHERE "approvals"."assignee" IN ($3,$4) AND "approvals"."deleted_at" IS NULL GROUP BY "approvals"."assignee"`)
+	approvers := []string{"approver1@email.com", "approver2@email.com"}
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs(domain.ApprovalStatusPending, domain.AppealStatusPending, approvers[0], approvers[1]).
+			WillReturnError(expectedError)
+
+		actualResult, actualError := s.repository.GetApproverLoads(context.Background(), approvers)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return the load of every approver", func() {
+		lastAssignedAt := time.Now()
+		expectedRows := sqlmock.NewRows([]string{"approver", "pending_count", "last_assigned_at"}).
+			AddRow(approvers[0], 3, lastAssignedAt)
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs(domain.ApprovalStatusPending, domain.AppealStatusPending, approvers[0], approvers[1]).
+			WillReturnRows(expectedRows)
+		expectedResult := map[string]*domain.ApproverLoad{
+			approvers[0]: {Approver: approvers[0], PendingCount: 3, LastAssignedAt: &lastAssignedAt},
+			approvers[1]: {Approver: approvers[1]},
+		}
+
+		actualResult, actualError := s.repository.GetApproverLoads(context.Background(), approvers)
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+	})
+
+	s.Run("should only count the approvals of the appeals of the organization", func() {
+		expectedOrganizationQuery := regexp.QuoteMeta(`SELECT "approvals"."assignee" AS "approver", COUNT(CASE WHEN "approvals"."status" = $1 AND "appeals"."status" = $2 THEN 1 END) AS "pending_count", MAX("approvals"."assigned_at") AS "last_assigned_at" FROM "approvals" JOIN "appeals" ON "appeals"."id" = "approvals"."appeal_id" WHERE "approvals"."appeal_id" IN (SELECT "id" FROM "appeals" WHERE ("organization_id" = $3) AND "appeals"."deleted_at" IS NULL) AND "approvals"."assignee" IN ($4,$5) AND "approvals"."deleted_at" IS NULL GROUP BY "approvals"."assignee"`)
+		s.dbmock.ExpectQuery(expectedOrganizationQuery).
+			WithArgs(domain.ApprovalStatusPending, domain.AppealStatusPending, "org-a", approvers[0], approvers[1]).
+			WillReturnRows(sqlmock.NewRows([]string{"approver", "pending_count", "last_assigned_at"}))
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		_, actualError := s.repository.GetApproverLoads(ctx, approvers)
+
+		s.Nil(actualError)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetApproverStats() {
+	approver := "approver@email.com"
+	expectedActionsQuery := regexp.QuoteMeta(`SELECT COUNT(CASE WHEN "approvals"."status" = $1 THEN 1 END) AS "approved_count", COUNT(CASE WHEN "approvals"."status" = $2 THEN 1 END) AS "rejected_count", AVG(EXTRACT(EPOCH FROM ("approvals"."updated_at" - COALESCE("approvals"."assigned_at", "approvals"."created_at")))) AS "avg_response_seconds" FROM "approvals" WHERE ("approvals"."actor" = $3 AND "approvals"."is_overridden" = $4) AND "approvals"."status" IN ($5,$6) AND "approvals"."updated_at" >= $7 AND "approvals"."updated_at" < $8 AND "approvals"."deleted_at" IS NULL`)
+	expectedBacklogQuery := regexp.QuoteMeta(`SELECT COUNT(DISTINCT "approvals"."id") AS "backlog" FROM "approvals" JOIN "approvers" ON "approvers"."approval_id" = "approvals"."id" AND "approvers"."deleted_at" IS NULL JOIN "appeals" ON "appeals"."id" = "approvals"."appeal_id" WHERE ("approvers"."email" = $1 AND "approvals"."status" = $2 AND "appeals"."status" = $3) AND "approvals"."deleted_at" IS NULL`)
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	filter := &domain.ApproverStatsFilter{From: &from, To: &to}
+
+	s.Run("should return error if got any from db", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectQuery(expectedActionsQuery).
+			WithArgs(domain.ApprovalStatusApproved, domain.ApprovalStatusRejected, approver, false, domain.ApprovalStatusApproved, domain.ApprovalStatusRejected, from, to).
+			WillReturnError(expectedError)
+
+		actualResult, actualError := s.repository.GetApproverStats(context.Background(), approver, filter)
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return the actions and the backlog of the approver", func() {
+		s.dbmock.ExpectQuery(expectedActionsQuery).
+			WithArgs(domain.ApprovalStatusApproved, domain.ApprovalStatusRejected, approver, false, domain.ApprovalStatusApproved, domain.ApprovalStatusRejected, from, to).
+			WillReturnRows(sqlmock.NewRows([]string{"approved_count", "rejected_count", "avg_response_seconds"}).AddRow(8, 2, 5400.5))
+		s.dbmock.ExpectQuery(expectedBacklogQuery).
+			WithArgs(approver, domain.ApprovalStatusPending, domain.AppealStatusPending).
+			WillReturnRows(sqlmock.NewRows([]string{"backlog"}).AddRow(3))
+		expectedResult := &domain.ApproverStats{
+			Approver:            approver,
+			ApprovedCount:       8,
+			RejectedCount:       2,
+			AverageResponseTime: 90*time.Minute + 500*time.Millisecond,
+			Backlog:             3,
+		}
+
+		actualResult, actualError := s.repository.GetApproverStats(context.Background(), approver, filter)
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+
+	s.Run("should only aggregate the approvals of the appeals of the organization", func() {
+		expectedOrganizationActionsQuery := regexp.QuoteMeta(`SELECT COUNT(CASE WHEN "approvals"."status" = $1 THEN 1 END) AS "approved_count", COUNT(CASE WHEN "approvals"."status" = $2 THEN 1 END) AS "rejected_count", AVG(EXTRACT(EPOCH FROM ("approvals"."updated_at" - COALESCE("approvals"."assigned_at", "approvals"."created_at")))) AS "avg_response_seconds" FROM "approvals" WHERE "approvals"."appeal_id" IN (SELECT "id" FROM "appeals" WHERE ("organization_id" = $3) AND "appeals"."deleted_at" IS NULL) AND ("approvals"."actor" = $4 AND "approvals"."is_overridden" = $5) AND "approvals"."status" IN ($6,$7) AND "approvals"."updated_at" >= $8 AND "approvals"."updated_at" < $9 AND "approvals"."deleted_at" IS NULL`)
+		expectedOrganizationBacklogQuery := regexp.QuoteMeta(`SELECT COUNT(DISTINCT "approvals"."id") AS "backlog" FROM "approvals" JOIN "approvers" ON "approvers"."approval_id" = "approvals"."id" AND "approvers"."deleted_at" IS NULL JOIN "appeals" ON "appeals"."id" = "approvals"."appeal_id" WHERE "approvals"."appeal_id" IN (SELECT "id" FROM "appeals" WHERE ("organization_id" = $1) AND "appeals"."deleted_at" IS NULL) AND ("approvers"."email" = $2 AND "approvals"."status" = $3 AND "appeals"."status" = $4) AND "approvals"."deleted_at" IS NULL`)
+		s.dbmock.ExpectQuery(expectedOrganizationActionsQuery).
+			WithArgs(domain.ApprovalStatusApproved, domain.ApprovalStatusRejected, "org-a", approver, false, domain.ApprovalStatusApproved, domain.ApprovalStatusRejected, from, to).
+			WillReturnRows(sqlmock.NewRows([]string{"approved_count", "rejected_count", "avg_response_seconds"}).AddRow(0, 0, nil))
+		s.dbmock.ExpectQuery(expectedOrganizationBacklogQuery).
+			WithArgs("org-a", approver, domain.ApprovalStatusPending, domain.AppealStatusPending).
+			WillReturnRows(sqlmock.NewRows([]string{"backlog"}).AddRow(0))
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		_, actualError := s.repository.GetApproverStats(ctx, approver, filter)
+
+		s.Nil(actualError)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+}
+
 func TestRepository(t *testing.T) {
 	suite.Run(t, new(RepositoryTestSuite))
 }
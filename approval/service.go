@@ -1,6 +1,7 @@
 package approval
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -21,18 +22,41 @@ func NewService(
 	return &service{ar, ps}
 }
 
-func (s *service) ListApprovals(filters *domain.ListApprovalsFilter) ([]*domain.Approval, error) {
-	return s.repo.ListApprovals(filters)
+func (s *service) ListApprovals(ctx context.Context, filters *domain.ListApprovalsFilter) ([]*domain.Approval, error) {
+	return s.repo.ListApprovals(ctx, filters)
 }
 
-func (s *service) BulkInsert(approvals []*domain.Approval) error {
-	return s.repo.BulkInsert(approvals)
+func (s *service) BulkInsert(ctx context.Context, approvals []*domain.Approval) error {
+	return s.repo.BulkInsert(ctx, approvals)
 }
 
-func (s *service) AdvanceApproval(appeal *domain.Appeal) error {
+func (s *service) AddApprover(ctx context.Context, approver *domain.Approver) error {
+	return s.repo.AddApprover(ctx, approver)
+}
+
+func (s *service) GetApproverLoads(ctx context.Context, approvers []string) (map[string]*domain.ApproverLoad, error) {
+	return s.repo.GetApproverLoads(ctx, approvers)
+}
+
+// GetApproverStats returns the number of approval steps the approver approved and rejected within the date
+// range along with their average response time, and the current backlog of the approver
+func (s *service) GetApproverStats(ctx context.Context, approver string, filter *domain.ApproverStatsFilter) (*domain.ApproverStats, error) {
+	if approver == "" {
+		return nil, ErrEmptyApprover
+	}
+	if filter == nil {
+		filter = &domain.ApproverStatsFilter{}
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, ErrInvalidDateRange
+	}
+	return s.repo.GetApproverStats(ctx, approver, filter)
+}
+
+func (s *service) AdvanceApproval(ctx context.Context, appeal *domain.Appeal) error {
 	policy := appeal.Policy
 	if policy == nil {
-		p, err := s.policyService.GetOne(appeal.PolicyID, appeal.PolicyVersion)
+		p, err := s.policyService.GetOne(ctx, appeal.PolicyID, appeal.PolicyVersion)
 		if err != nil {
 			return err
 		}
@@ -49,7 +73,7 @@ func (s *service) AdvanceApproval(appeal *domain.Appeal) error {
 	}
 
 	for _, approval := range appeal.Approvals {
-		if approval.Status == domain.ApprovalStatusRejected {
+		if approval.Status == domain.ApprovalStatusRejected || approval.Status == domain.ApprovalStatusPendingApproverResolution {
 			break
 		} else if approval.Status == domain.ApprovalStatusPending {
 			if approval.IsManualApproval() {
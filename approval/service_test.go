@@ -1,8 +1,11 @@
 package approval_test
 
 import (
+	"context"
+
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/odpf/guardian/approval"
 	"github.com/odpf/guardian/domain"
@@ -29,14 +32,37 @@ func (s *ServiceTestSuite) SetupTest() {
 func (s *ServiceTestSuite) TestBulkInsert() {
 	s.Run("should return error if got error from repository", func() {
 		expectedError := errors.New("repository error")
-		s.mockRepository.On("BulkInsert", mock.Anything).Return(expectedError).Once()
+		s.mockRepository.On("BulkInsert", mock.Anything, mock.Anything).Return(expectedError).Once()
 
-		actualError := s.service.BulkInsert([]*domain.Approval{})
+		actualError := s.service.BulkInsert(context.Background(), []*domain.Approval{})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 }
 
+func (s *ServiceTestSuite) TestGetApproverStats() {
+	s.Run("should return error if the approver or the date range is invalid", func() {
+		from := time.Now()
+		to := from.Add(-time.Hour)
+
+		_, actualError := s.service.GetApproverStats(context.Background(), "", nil)
+		s.ErrorIs(actualError, approval.ErrEmptyApprover)
+
+		_, actualError = s.service.GetApproverStats(context.Background(), "approver@email.com", &domain.ApproverStatsFilter{From: &from, To: &to})
+		s.ErrorIs(actualError, approval.ErrInvalidDateRange)
+	})
+
+	s.Run("should return the stats of the approver from the repository", func() {
+		expectedResult := &domain.ApproverStats{Approver: "approver@email.com", ApprovedCount: 1, Backlog: 2}
+		s.mockRepository.On("GetApproverStats", mock.Anything, "approver@email.com", &domain.ApproverStatsFilter{}).Return(expectedResult, nil).Once()
+
+		actualResult, actualError := s.service.GetApproverStats(context.Background(), "approver@email.com", nil)
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+	})
+}
+
 func (s *ServiceTestSuite) TestAdvanceApproval() {
 	// TODO: test
 }
@@ -0,0 +1,36 @@
+package auth
+
+import "context"
+
+type contextKey struct{}
+
+type organizationContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated actor
+func NewContext(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, contextKey{}, actor)
+}
+
+// ActorFromContext returns the authenticated actor carried by ctx
+func ActorFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	actor, ok := ctx.Value(contextKey{}).(string)
+	return actor, ok && actor != ""
+}
+
+// NewOrganizationContext returns a copy of ctx carrying the organization of the authenticated actor. The appeals
+// are scoped to the organization carried by the context
+func NewOrganizationContext(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, organizationContextKey{}, organizationID)
+}
+
+// OrganizationFromContext returns the organization of the authenticated actor carried by ctx
+func OrganizationFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	organizationID, ok := ctx.Value(organizationContextKey{}).(string)
+	return organizationID, ok && organizationID != ""
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// IdentityAwareProxyHeader carries the email of the user authenticated by the identity-aware proxy
+const IdentityAwareProxyHeader = "X-Goog-Authenticated-User-Email"
+
+// ActorOrganizationVerifier verifies the bearer token of the caller, e.g. the OIDC verifier
+type ActorOrganizationVerifier interface {
+	VerifyActorOrganization(ctx context.Context, token string) (string, string, error)
+}
+
+// AuthenticateRequest identifies the caller of a plain HTTP handler by the bearer token if the verifier is set,
+// otherwise by the identity-aware proxy header. It returns the request context carrying the verified actor along
+// with their organization if any
+func AuthenticateRequest(r *http.Request, verifier ActorOrganizationVerifier) (context.Context, string, error) {
+	ctx := r.Context()
+	if verifier == nil {
+		actor := r.Header.Get(IdentityAwareProxyHeader)
+		if actor == "" {
+			return nil, "", ErrUnauthenticatedActor
+		}
+		return NewContext(ctx, actor), actor, nil
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		return nil, "", ErrUnauthenticatedActor
+	}
+	actor, organizationID, err := verifier.VerifyActorOrganization(ctx, token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx = NewContext(ctx, actor)
+	if organizationID != "" {
+		ctx = NewOrganizationContext(ctx, organizationID)
+	}
+	return ctx, actor, nil
+}
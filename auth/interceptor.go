@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const bearerPrefix = "bearer "
+
+// UnaryServerInterceptor requires the requests to carry a valid bearer token and attaches the
+// verified actor, along with their organization if any, to the request context so the handlers don't rely on a
+// caller supplied actor
+func UnaryServerInterceptor(v *OIDCVerifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		token := bearerToken(ctx)
+		if token == "" {
+			return nil, status.Error(codes.Unauthenticated, ErrUnauthenticatedActor.Error())
+		}
+
+		actor, organizationID, err := v.VerifyActorOrganization(ctx, token)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "%s: %v", ErrUnauthenticatedActor, err)
+		}
+
+		ctx = NewContext(ctx, actor)
+		if organizationID != "" {
+			ctx = NewOrganizationContext(ctx, organizationID)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 || len(values[0]) <= len(bearerPrefix) || !strings.EqualFold(values[0][:len(bearerPrefix)], bearerPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(values[0][len(bearerPrefix):])
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+)
+
+const defaultJWKSRefreshInterval = time.Minute
+
+// keySet caches the issuer's JSON web key set. The key set is refetched when a token is signed by an unknown key,
+// at most once per refresh interval so that the tokens with made up key ids can't flood the issuer
+type keySet struct {
+	url             string
+	refreshInterval time.Duration
+	httpClient      *http.Client
+	timeNow         func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]*jose.JSONWebKey
+	lastFetched time.Time
+	// fetching is closed once the ongoing fetch of the key set, if any, is done
+	fetching chan struct{}
+}
+
+func newKeySet(url string, refreshInterval time.Duration, timeNow func() time.Time) *keySet {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultJWKSRefreshInterval
+	}
+	return &keySet{
+		url:             url,
+		refreshInterval: refreshInterval,
+		httpClient:      &http.Client{Timeout: 10 * time.Second},
+		timeNow:         timeNow,
+	}
+}
+
+func (s *keySet) getKey(ctx context.Context, keyID string) (*jose.JSONWebKey, error) {
+	s.mu.Lock()
+	if key, ok := s.keys[keyID]; ok {
+		s.mu.Unlock()
+		return key, nil
+	}
+	if fetching := s.fetching; fetching != nil {
+		// another request is already refetching the key set, wait for it instead of fetching it again
+		s.mu.Unlock()
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return s.cachedKey(keyID)
+	}
+	if !s.lastFetched.IsZero() && s.timeNow().Sub(s.lastFetched) < s.refreshInterval {
+		s.mu.Unlock()
+		return nil, ErrSigningKeyNotFound
+	}
+	fetching := make(chan struct{})
+	s.fetching = fetching
+	s.mu.Unlock()
+
+	// the key set is fetched without holding the lock so the requests with the cached keys aren't blocked by
+	// a slow issuer
+	keys, err := s.fetchKeys(ctx)
+
+	s.mu.Lock()
+	// failed fetches are rate limited as well
+	s.lastFetched = s.timeNow()
+	if err == nil {
+		s.keys = keys
+	}
+	s.fetching = nil
+	close(fetching)
+	s.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return s.cachedKey(keyID)
+}
+
+func (s *keySet) cachedKey(keyID string) (*jose.JSONWebKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[keyID]; ok {
+		return key, nil
+	}
+	return nil, ErrSigningKeyNotFound
+}
+
+func (s *keySet) fetchKeys(ctx context.Context) (map[string]*jose.JSONWebKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching jwks: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching jwks: unexpected status code %d", res.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("decoding jwks: %w", err)
+	}
+
+	keys := map[string]*jose.JSONWebKey{}
+	for _, rawKey := range jwks.Keys {
+		var key jose.JSONWebKey
+		// the keys of the unsupported types are skipped instead of failing the whole key set
+		if err := json.Unmarshal(rawKey, &key); err != nil || !key.Valid() || !key.IsPublic() {
+			continue
+		}
+		keys[key.KeyID] = &key
+	}
+	return keys, nil
+}
@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+var (
+	ErrMalformedToken            = errors.New("malformed token")
+	ErrUnsupportedAlgorithm      = errors.New("unsupported token signing algorithm")
+	ErrInvalidSignature          = errors.New("invalid token signature")
+	ErrSigningKeyNotFound        = errors.New("token signing key not found")
+	ErrInvalidIssuer             = errors.New("invalid token issuer")
+	ErrInvalidAudience           = errors.New("invalid token audience")
+	ErrTokenExpired              = errors.New("token is expired")
+	ErrTokenNotYetValid          = errors.New("token is not valid yet")
+	ErrSubjectNotFound           = errors.New("token doesn't have any subject")
+	ErrActorClaimNotFound        = errors.New("token doesn't have the actor claim")
+	ErrEmailNotVerified          = errors.New("token email is not verified")
+	ErrOrganizationClaimNotFound = errors.New("token doesn't have the organization claim")
+	ErrUnauthenticatedActor      = errors.New("actor is not authenticated")
+	ErrOIDCConfigNotConfigured   = errors.New("oidc issuer and jwks url are required")
+)
+
+const emailClaim = "email"
+
+// OIDCConfig configures the verification of the OIDC ID tokens sent along with the requests
+type OIDCConfig struct {
+	// Enabled requires every request to carry a valid token as the bearer authorization
+	Enabled  bool   `mapstructure:"enabled"`
+	Issuer   string `mapstructure:"issuer"`
+	Audience string `mapstructure:"audience"`
+	JWKSURL  string `mapstructure:"jwks_url"`
+	// JWKSRefreshInterval is the minimum interval between the refetches of the key set on the unknown key ids
+	JWKSRefreshInterval time.Duration `mapstructure:"jwks_refresh_interval" default:"1m"`
+	// ActorClaim is the claim used as the actor, it should hold the user email
+	ActorClaim string `mapstructure:"actor_claim" default:"email"`
+	// AllowUnverifiedEmail accepts the tokens which email_verified claim isn't true when the actor claim is the
+	// email, for the issuers not setting the claim
+	AllowUnverifiedEmail bool `mapstructure:"allow_unverified_email"`
+	// OrganizationClaim is the claim holding the organization of the actor, the requests are then scoped to the
+	// organization. Leave it empty for a single organization
+	OrganizationClaim string `mapstructure:"organization_claim"`
+}
+
+// Claims of a verified token
+type Claims map[string]interface{}
+
+// Subject returns the "sub" claim
+func (c Claims) Subject() string {
+	sub, _ := c["sub"].(string)
+	return sub
+}
+
+// isEmailVerified returns true if the "email_verified" claim is true. Some issuers send it as a string
+func (c Claims) isEmailVerified() bool {
+	switch verified := c["email_verified"].(type) {
+	case bool:
+		return verified
+	case string:
+		return verified == "true"
+	default:
+		return false
+	}
+}
+
+// OIDCVerifier verifies RS256 signed ID tokens against the issuer's JSON web key set
+type OIDCVerifier struct {
+	config *OIDCConfig
+	keySet *keySet
+
+	TimeNow func() time.Time
+}
+
+// NewOIDCVerifier returns the token verifier
+func NewOIDCVerifier(c *OIDCConfig) (*OIDCVerifier, error) {
+	if c.Issuer == "" || c.JWKSURL == "" {
+		return nil, ErrOIDCConfigNotConfigured
+	}
+	if c.ActorClaim == "" {
+		c.ActorClaim = emailClaim
+	}
+
+	v := &OIDCVerifier{
+		config:  c,
+		TimeNow: time.Now,
+	}
+	v.keySet = newKeySet(c.JWKSURL, c.JWKSRefreshInterval, func() time.Time { return v.TimeNow() })
+	return v, nil
+}
+
+// Verify checks the token signature and claims, then returns the claims
+func (v *OIDCVerifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parsedToken, err := jwt.ParseSigned(token)
+	if err != nil || len(parsedToken.Headers) != 1 {
+		return nil, ErrMalformedToken
+	}
+	header := parsedToken.Headers[0]
+	if header.Algorithm != string(jose.RS256) {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	key, err := v.keySet.getKey(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var standardClaims jwt.Claims
+	var claims Claims
+	if err := parsedToken.Claims(key, &standardClaims, &claims); err != nil {
+		if errors.Is(err, jose.ErrCryptoFailure) {
+			return nil, ErrInvalidSignature
+		}
+		return nil, ErrMalformedToken
+	}
+	if err := v.validateClaims(standardClaims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// VerifyActor verifies the token and returns the actor claim of the token subject
+func (v *OIDCVerifier) VerifyActor(ctx context.Context, token string) (string, error) {
+	actor, _, err := v.VerifyActorOrganization(ctx, token)
+	return actor, err
+}
+
+// VerifyActorOrganization verifies the token and returns the actor along with their organization from the
+// organization claim. The organization is empty if the organization claim isn't configured
+func (v *OIDCVerifier) VerifyActorOrganization(ctx context.Context, token string) (string, string, error) {
+	claims, err := v.Verify(ctx, token)
+	if err != nil {
+		return "", "", err
+	}
+
+	actor, _ := claims[v.config.ActorClaim].(string)
+	if actor == "" {
+		return "", "", ErrActorClaimNotFound
+	}
+	// anyone can set an unverified email address on their account at some issuers
+	if v.config.ActorClaim == emailClaim && !v.config.AllowUnverifiedEmail && !claims.isEmailVerified() {
+		return "", "", ErrEmailNotVerified
+	}
+	if v.config.OrganizationClaim == "" {
+		return actor, "", nil
+	}
+	organizationID, _ := claims[v.config.OrganizationClaim].(string)
+	if organizationID == "" {
+		return "", "", ErrOrganizationClaimNotFound
+	}
+	return actor, organizationID, nil
+}
+
+func (v *OIDCVerifier) validateClaims(claims jwt.Claims) error {
+	expected := jwt.Expected{
+		Issuer: v.config.Issuer,
+		Time:   v.TimeNow(),
+	}
+	if v.config.Audience != "" {
+		expected.Audience = jwt.Audience{v.config.Audience}
+	}
+
+	if claims.Expiry == nil {
+		return ErrTokenExpired
+	}
+	switch err := claims.ValidateWithLeeway(expected, 0); err {
+	case nil:
+	case jwt.ErrInvalidIssuer:
+		return ErrInvalidIssuer
+	case jwt.ErrInvalidAudience:
+		return ErrInvalidAudience
+	case jwt.ErrExpired:
+		return ErrTokenExpired
+	case jwt.ErrNotValidYet, jwt.ErrIssuedInTheFuture:
+		return ErrTokenNotYetValid
+	default:
+		return err
+	}
+
+	if claims.Subject == "" {
+		return ErrSubjectNotFound
+	}
+
+	return nil
+}
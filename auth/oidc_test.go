@@ -0,0 +1,417 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "guardian"
+	testKeyID    = "key-1"
+)
+
+func newJWKSServer(t *testing.T, key *rsa.PublicKey) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(jwksHandler(key))
+}
+
+func jwksHandler(key *rsa.PublicKey) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": testKeyID,
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+			},
+		})
+	}
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, keyID string, claims map[string]interface{}) string {
+	t.Helper()
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signingInput := encode(map[string]string{"alg": "RS256", "kid": keyID, "typ": "JWT"}) + "." + encode(claims)
+	hashed := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":            testIssuer,
+		"aud":            testAudience,
+		"sub":            "1234567890",
+		"email":          "user@email.com",
+		"email_verified": true,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestOIDCVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newJWKSServer(t, &key.PublicKey)
+	defer server.Close()
+
+	verifier, err := auth.NewOIDCVerifier(&auth.OIDCConfig{
+		Issuer:   testIssuer,
+		Audience: testAudience,
+		JWKSURL:  server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should return error if issuer or jwks url is not configured", func(t *testing.T) {
+		_, err := auth.NewOIDCVerifier(&auth.OIDCConfig{Issuer: testIssuer})
+
+		assert.Equal(t, auth.ErrOIDCConfigNotConfigured, err)
+	})
+
+	t.Run("should return the actor claim of a valid token", func(t *testing.T) {
+		actor, err := verifier.VerifyActor(context.Background(), signToken(t, key, testKeyID, validClaims()))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "user@email.com", actor)
+	})
+
+	t.Run("should return the organization claim of a valid token if configured", func(t *testing.T) {
+		organizationVerifier, err := auth.NewOIDCVerifier(&auth.OIDCConfig{
+			Issuer:            testIssuer,
+			Audience:          testAudience,
+			JWKSURL:           server.URL,
+			OrganizationClaim: "org",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims := validClaims()
+		claims["org"] = "org-a"
+
+		actor, organizationID, err := organizationVerifier.VerifyActorOrganization(context.Background(), signToken(t, key, testKeyID, claims))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "user@email.com", actor)
+		assert.Equal(t, "org-a", organizationID)
+
+		_, _, err = organizationVerifier.VerifyActorOrganization(context.Background(), signToken(t, key, testKeyID, validClaims()))
+
+		assert.Equal(t, auth.ErrOrganizationClaimNotFound, err)
+	})
+
+	t.Run("should accept audience as an array", func(t *testing.T) {
+		claims := validClaims()
+		claims["aud"] = []string{"other", testAudience}
+
+		_, err := verifier.VerifyActor(context.Background(), signToken(t, key, testKeyID, claims))
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("should accept an unverified email if allowed", func(t *testing.T) {
+		unverifiedEmailVerifier, err := auth.NewOIDCVerifier(&auth.OIDCConfig{
+			Issuer:               testIssuer,
+			Audience:             testAudience,
+			JWKSURL:              server.URL,
+			AllowUnverifiedEmail: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims := validClaims()
+		delete(claims, "email_verified")
+
+		actor, err := unverifiedEmailVerifier.VerifyActor(context.Background(), signToken(t, key, testKeyID, claims))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "user@email.com", actor)
+	})
+
+	testCases := []struct {
+		name          string
+		token         func() string
+		expectedError error
+	}{
+		{
+			name:          "malformed token",
+			token:         func() string { return "not.a-token" },
+			expectedError: auth.ErrMalformedToken,
+		},
+		{
+			name:          "token signed by another key",
+			token:         func() string { return signToken(t, otherKey, testKeyID, validClaims()) },
+			expectedError: auth.ErrInvalidSignature,
+		},
+		{
+			name:          "unknown key id",
+			token:         func() string { return signToken(t, key, "unknown", validClaims()) },
+			expectedError: auth.ErrSigningKeyNotFound,
+		},
+		{
+			name: "different issuer",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://other-issuer.example.com"
+				return signToken(t, key, testKeyID, claims)
+			},
+			expectedError: auth.ErrInvalidIssuer,
+		},
+		{
+			name: "different audience",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "other"
+				return signToken(t, key, testKeyID, claims)
+			},
+			expectedError: auth.ErrInvalidAudience,
+		},
+		{
+			name: "expired token",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return signToken(t, key, testKeyID, claims)
+			},
+			expectedError: auth.ErrTokenExpired,
+		},
+		{
+			name: "token without subject",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "sub")
+				return signToken(t, key, testKeyID, claims)
+			},
+			expectedError: auth.ErrSubjectNotFound,
+		},
+		{
+			name: "token without actor claim",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "email")
+				return signToken(t, key, testKeyID, claims)
+			},
+			expectedError: auth.ErrActorClaimNotFound,
+		},
+		{
+			name: "unverified email",
+			token: func() string {
+				claims := validClaims()
+				claims["email_verified"] = false
+				return signToken(t, key, testKeyID, claims)
+			},
+			expectedError: auth.ErrEmailNotVerified,
+		},
+		{
+			name: "token without email verified claim",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "email_verified")
+				return signToken(t, key, testKeyID, claims)
+			},
+			expectedError: auth.ErrEmailNotVerified,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run("should return error on "+tc.name, func(t *testing.T) {
+			actor, err := verifier.VerifyActor(context.Background(), tc.token())
+
+			assert.Empty(t, actor)
+			assert.Equal(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestOIDCVerifierKeySetRefresh(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetches := 0
+	handler := jwksHandler(&key.PublicKey)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		handler(w, r)
+	}))
+	defer server.Close()
+
+	verifier, err := auth.NewOIDCVerifier(&auth.OIDCConfig{
+		Issuer:              testIssuer,
+		JWKSURL:             server.URL,
+		JWKSRefreshInterval: time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	verifier.TimeNow = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		_, err := verifier.VerifyActor(context.Background(), signToken(t, key, "unknown", validClaims()))
+		assert.Equal(t, auth.ErrSigningKeyNotFound, err)
+	}
+	_, err = verifier.VerifyActor(context.Background(), signToken(t, key, testKeyID, validClaims()))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, fetches, "the key set should be fetched once within the refresh interval")
+
+	now = now.Add(time.Minute)
+	_, err = verifier.VerifyActor(context.Background(), signToken(t, key, "unknown", validClaims()))
+	assert.Equal(t, auth.ErrSigningKeyNotFound, err)
+	assert.Equal(t, 2, fetches, "the key set should be refetched after the refresh interval")
+}
+
+func TestOIDCVerifierKeySetSlowRefresh(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fetches int32
+	release := make(chan struct{})
+	handler := jwksHandler(&key.PublicKey)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the refetches are held until released to simulate a slow issuer
+		if atomic.AddInt32(&fetches, 1) > 1 {
+			<-release
+		}
+		handler(w, r)
+	}))
+	defer server.Close()
+
+	verifier, err := auth.NewOIDCVerifier(&auth.OIDCConfig{
+		Issuer:              testIssuer,
+		JWKSURL:             server.URL,
+		JWKSRefreshInterval: time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	verifier.TimeNow = func() time.Time { return now }
+	_, err = verifier.VerifyActor(context.Background(), signToken(t, key, testKeyID, validClaims()))
+	assert.Nil(t, err)
+	now = now.Add(time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := verifier.VerifyActor(context.Background(), signToken(t, key, "unknown", validClaims()))
+			assert.Equal(t, auth.ErrSigningKeyNotFound, err)
+		}()
+	}
+	for atomic.LoadInt32(&fetches) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err = verifier.VerifyActor(context.Background(), signToken(t, key, testKeyID, validClaims()))
+	assert.Nil(t, err, "the cached keys should be served while the key set is refetched")
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches), "the concurrent refetches should share one fetch")
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newJWKSServer(t, &key.PublicKey)
+	defer server.Close()
+
+	verifier, err := auth.NewOIDCVerifier(&auth.OIDCConfig{Issuer: testIssuer, JWKSURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	interceptor := auth.UnaryServerInterceptor(verifier)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		actor, _ := auth.ActorFromContext(ctx)
+		return actor, nil
+	}
+
+	t.Run("should return unauthenticated if token is missing", func(t *testing.T) {
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("should return unauthenticated if token is invalid", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer invalid"))
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("should pass the verified actor to the handler", func(t *testing.T) {
+		token := signToken(t, key, testKeyID, validClaims())
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+
+		actor, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "user@email.com", actor)
+	})
+}
+
+func TestAuthenticateRequest(t *testing.T) {
+	t.Run("should return unauthenticated if the identity-aware proxy header is missing", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		_, _, err := auth.AuthenticateRequest(r, nil)
+
+		assert.ErrorIs(t, err, auth.ErrUnauthenticatedActor)
+	})
+
+	t.Run("should return the context carrying the identity-aware proxy user", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(auth.IdentityAwareProxyHeader, "user@email.com")
+
+		ctx, actor, err := auth.AuthenticateRequest(r, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user@email.com", actor)
+		ctxActor, ok := auth.ActorFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "user@email.com", ctxActor)
+	})
+}
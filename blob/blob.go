@@ -0,0 +1,35 @@
+package blob
+
+import (
+	"errors"
+
+	"github.com/odpf/guardian/domain"
+)
+
+const (
+	// DriverFile stores the blobs in the local file system
+	DriverFile = "file"
+)
+
+var (
+	ErrUnsupportedDriver = errors.New("unsupported blob storage driver")
+	ErrInvalidKey        = errors.New("invalid blob key")
+	ErrNotFound          = errors.New("blob not found")
+)
+
+// Config for the blob storage
+type Config struct {
+	Driver string `mapstructure:"driver" default:"file"`
+	// Path is the root directory of the blobs for the file driver
+	Path string `mapstructure:"path" default:"./attachments"`
+}
+
+// New returns the blob storage of the configured driver
+func New(c *Config) (domain.BlobStorage, error) {
+	switch c.Driver {
+	case DriverFile:
+		return NewFileStorage(c.Path), nil
+	default:
+		return nil, ErrUnsupportedDriver
+	}
+}
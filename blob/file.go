@@ -0,0 +1,85 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileStorage stores the blobs as files under the root directory
+type FileStorage struct {
+	root string
+}
+
+// NewFileStorage returns the file system blob storage
+func NewFileStorage(root string) *FileStorage {
+	return &FileStorage{root}
+}
+
+// Put writes the content to the file of the key, overwriting the existing one
+func (s *FileStorage) Put(ctx context.Context, key string, content io.Reader) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, content); err != nil {
+		f.Close()
+		os.Remove(p)
+		return err
+	}
+
+	return f.Close()
+}
+
+// Get opens the file of the key
+func (s *FileStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Delete removes the file of the key
+func (s *FileStorage) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *FileStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if key == "" || cleaned == "/" || strings.Contains(key, "..") {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
@@ -0,0 +1,45 @@
+package blob_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/odpf/guardian/blob"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "guardian-blob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	s := blob.NewFileStorage(dir)
+
+	t.Run("should be able to read the stored content", func(t *testing.T) {
+		key := "appeals/1/evidence.txt"
+		assert.Nil(t, s.Put(ctx, key, strings.NewReader("content")))
+
+		r, err := s.Get(ctx, key)
+		assert.Nil(t, err)
+		data, err := ioutil.ReadAll(r)
+		r.Close()
+		assert.Nil(t, err)
+		assert.Equal(t, "content", string(data))
+
+		assert.Nil(t, s.Delete(ctx, key))
+		_, err = s.Get(ctx, key)
+		assert.Equal(t, blob.ErrNotFound, err)
+	})
+
+	t.Run("should return error on invalid key", func(t *testing.T) {
+		for _, key := range []string{"", "/", "../outside", "appeals/../../outside"} {
+			assert.Equal(t, blob.ErrInvalidKey, s.Put(ctx, key, strings.NewReader("content")))
+		}
+	})
+}
@@ -2,12 +2,19 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/odpf/guardian/api/handler/magiclink"
 	pb "github.com/odpf/guardian/api/proto/odpf/guardian"
 	"github.com/odpf/guardian/app"
+	"github.com/odpf/guardian/domain"
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -20,8 +27,18 @@ func appealsCommand(c *app.CLIConfig) *cobra.Command {
 	cmd.AddCommand(listAppealsCommand(c))
 	cmd.AddCommand(createAppealCommand(c))
 	cmd.AddCommand(revokeAppealCommand(c))
+	cmd.AddCommand(bulkRevokeAppealsCommand())
 	cmd.AddCommand(approveApprovalStepCommand(c))
 	cmd.AddCommand(rejectApprovalStepCommand(c))
+	cmd.AddCommand(overrideApprovalStepCommand())
+	cmd.AddCommand(addApprovalStepCommand())
+	cmd.AddCommand(pendingAppealsCommand())
+	cmd.AddCommand(magicLinkCommand())
+	cmd.AddCommand(deadlockedAppealsCommand())
+	cmd.AddCommand(remindPendingApproversCommand())
+	cmd.AddCommand(retryRevocationsCommand())
+	cmd.AddCommand(importAppealsCommand())
+	cmd.AddCommand(accessSummaryCommand())
 
 	return cmd
 }
@@ -155,83 +172,723 @@ func revokeAppealCommand(c *app.CLIConfig) *cobra.Command {
 	return cmd
 }
 
+func bulkRevokeAppealsCommand() *cobra.Command {
+	var user string
+	var resourceID uint
+	var actor string
+	var reason string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "revoke-bulk",
+		Short: "revoke the active access of a user or a resource",
+		Long:  "revoke the active access of a user or a resource, e.g. to offboard a user. Passing --dry-run lists the access that would be revoked without revoking anything",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filters := map[string]interface{}{}
+			if user != "" {
+				filters["user"] = user
+			}
+			if resourceID != 0 {
+				filters["resource_id"] = resourceID
+			}
+
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+
+			var appeals []*domain.Appeal
+			if dryRun {
+				appeals, err = services.AppealService.PreviewRevoke(ctx, filters)
+			} else {
+				appeals, err = services.AppealService.RevokeByFilter(ctx, filters, actor, reason)
+			}
+			if appeals == nil && err != nil {
+				return err
+			}
+
+			t := getTablePrinter(os.Stdout, []string{"ID", "USER", "RESOURCE URN", "ROLE", "STATUS"})
+			for _, a := range appeals {
+				t.Append([]string{
+					fmt.Sprintf("%v", a.ID),
+					a.User,
+					a.Resource.URN,
+					a.Role,
+					a.Status,
+				})
+			}
+			t.Render()
+
+			if dryRun {
+				fmt.Printf("%d access would be revoked\n", len(appeals))
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&user, "user", "", "email of the user which access is revoked")
+	cmd.Flags().UintVar(&resourceID, "resource-id", 0, "id of the resource which access is revoked")
+	cmd.Flags().StringVar(&actor, "actor", "", "email of the user revoking the access")
+	cmd.Flags().StringVarP(&reason, "reason", "r", "", "revocation reason")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "list the access that would be revoked without revoking it")
+
+	return cmd
+}
+
 func approveApprovalStepCommand(c *app.CLIConfig) *cobra.Command {
 	var id uint
 	var approvalName string
+	var actor string
+	var remote bool
+	var group bool
+	var grantRole, grantDuration string
 
 	cmd := &cobra.Command{
-		Use:   "approve",
+		Use:   "approve [id]",
 		Short: "approve an approval step",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
-			client, cancel, err := createClient(ctx, c.Host)
+			appealID, err := getApprovalActionArgs(args, id, approvalName, actor, remote)
 			if err != nil {
 				return err
 			}
-			defer cancel()
+			if remote {
+				if group || grantRole != "" || grantDuration != "" {
+					return errors.New("--group, --grant-role, and --grant-duration aren't supported with --remote")
+				}
+				return updateApprovalRemotely(c.Host, appealID, approvalName, domain.AppealActionNameApprove)
+			}
 
-			_, err = client.UpdateApproval(ctx, &pb.UpdateApprovalRequest{
-				Id:           uint32(id),
-				ApprovalName: approvalName,
-				Action: &pb.UpdateApprovalRequest_Action{
-					Action: "approve",
-				},
-			})
+			var conditions *domain.ApprovalConditions
+			if grantRole != "" || grantDuration != "" {
+				conditions = &domain.ApprovalConditions{Role: grantRole, Duration: grantDuration}
+			}
+			if group {
+				return makeGroupApprovalAction(appealID, approvalName, actor, domain.AppealActionNameApprove, conditions)
+			}
+			return makeApprovalAction(appealID, approvalName, actor, domain.AppealActionNameApprove, conditions)
+		},
+	}
+
+	cmd.Flags().UintVar(&id, "id", 0, "appeal id, same as the id argument")
+	cmd.Flags().StringVarP(&approvalName, "step", "s", "", "approval step name going to be approved")
+	cmd.Flags().StringVarP(&approvalName, "approval-name", "a", "", "approval step name going to be approved, same as --step")
+	cmd.Flags().StringVar(&actor, "actor", "", "email of the approver, required unless --remote is set")
+	cmd.Flags().BoolVar(&remote, "remote", false, "approve through the server at the configured host instead of the database, as the actor identified by the server")
+	cmd.Flags().BoolVar(&group, "group", false, "treat the id as an appeal group id and approve the step on every appeal of the group")
+	cmd.Flags().StringVar(&grantRole, "grant-role", "", "approve with a role narrower than the requested one, e.g. viewer")
+	cmd.Flags().StringVar(&grantDuration, "grant-duration", "", "approve with an access duration shorter than the requested one, e.g. 24h")
+
+	return cmd
+}
+
+func rejectApprovalStepCommand(c *app.CLIConfig) *cobra.Command {
+	var id uint
+	var approvalName string
+	var actor string
+	var remote bool
+	var group bool
+
+	cmd := &cobra.Command{
+		Use:   "reject [id]",
+		Short: "reject an approval step",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appealID, err := getApprovalActionArgs(args, id, approvalName, actor, remote)
 			if err != nil {
 				return err
 			}
+			if remote {
+				if group {
+					return errors.New("--group isn't supported with --remote")
+				}
+				return updateApprovalRemotely(c.Host, appealID, approvalName, domain.AppealActionNameReject)
+			}
 
-			fmt.Printf("appeal with id %v and approval name %v approved successfully", id, approvalName)
+			if group {
+				return makeGroupApprovalAction(appealID, approvalName, actor, domain.AppealActionNameReject, nil)
+			}
+			return makeApprovalAction(appealID, approvalName, actor, domain.AppealActionNameReject, nil)
+		},
+	}
+
+	cmd.Flags().UintVar(&id, "id", 0, "appeal id, same as the id argument")
+	cmd.Flags().StringVarP(&approvalName, "step", "s", "", "approval step name going to be rejected")
+	cmd.Flags().StringVarP(&approvalName, "approval-name", "a", "", "approval step name going to be rejected, same as --step")
+	cmd.Flags().StringVar(&actor, "actor", "", "email of the approver, required unless --remote is set")
+	cmd.Flags().BoolVar(&remote, "remote", false, "reject through the server at the configured host instead of the database, as the actor identified by the server")
+	cmd.Flags().BoolVar(&group, "group", false, "treat the id as an appeal group id and reject the step on every appeal of the group")
+
+	return cmd
+}
+
+// getApprovalActionArgs returns the appeal id given either as the argument or the --id flag, and checks the
+// approval step and the actor are set. The actor is identified by the server if the action is made remotely
+func getApprovalActionArgs(args []string, id uint, approvalName, actor string, remote bool) (string, error) {
+	var appealID string
+	switch {
+	case len(args) == 1:
+		appealID = args[0]
+	case id != 0:
+		appealID = strconv.FormatUint(uint64(id), 10)
+	default:
+		return "", errors.New("appeal id is required, either as the argument or --id")
+	}
+	if approvalName == "" {
+		return "", errors.New("approval step is required, set --step or --approval-name")
+	}
+	if actor == "" && !remote {
+		return "", errors.New("--actor is required unless --remote is set")
+	}
+	return appealID, nil
+}
+
+func overrideApprovalStepCommand() *cobra.Command {
+	var approvalName string
+	var actor string
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:   "override <id>",
+		Short: "approve an approval step on behalf of the approvers",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid appeal id %q: %w", args[0], err)
+			}
+
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+
+			a, err := services.AppealService.AdminApprove(ctx, uint(id), approvalName, actor, reason)
+			if err != nil {
+				return fmt.Errorf("failed to override appeal with id %v on step %q: %w", id, approvalName, err)
+			}
+
+			fmt.Printf("appeal with id %v and approval name %v: %v\n", a.ID, approvalName, a.Status)
 
 			return nil
 		},
 	}
 
-	cmd.Flags().UintVar(&id, "id", 0, "appeal id")
-	cmd.MarkFlagRequired("id")
-	cmd.Flags().StringVarP(&approvalName, "approval-name", "a", "", "approval name going to be approved")
-	cmd.MarkFlagRequired("approval-name")
+	cmd.Flags().StringVarP(&approvalName, "step", "s", "", "approval step name going to be approved")
+	cmd.MarkFlagRequired("step")
+	cmd.Flags().StringVar(&actor, "actor", "", "email of the admin")
+	cmd.MarkFlagRequired("actor")
+	cmd.Flags().StringVarP(&reason, "reason", "r", "", "reason of the override")
+	cmd.MarkFlagRequired("reason")
 
 	return cmd
 }
 
-func rejectApprovalStepCommand(c *app.CLIConfig) *cobra.Command {
-	var id uint
+func addApprovalStepCommand() *cobra.Command {
 	var approvalName string
+	var approvers []string
+	var position int
+	var actor string
 
 	cmd := &cobra.Command{
-		Use:   "reject",
-		Short: "reject an approval step",
+		Use:   "add-step <id>",
+		Short: "add an ad-hoc approval step to a pending appeal",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid appeal id %q: %w", args[0], err)
+			}
+
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
 			ctx := context.Background()
-			client, cancel, err := createClient(ctx, c.Host)
+
+			step := domain.Approval{Name: approvalName, Approvers: approvers}
+			a, err := services.AppealService.AddApprovalStep(ctx, uint(id), step, position, actor)
+			if err != nil {
+				return fmt.Errorf("failed to add step %q to appeal with id %v: %w", approvalName, id, err)
+			}
+
+			fmt.Printf("approval step %v added to appeal with id %v\n", approvalName, a.ID)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&approvalName, "step", "s", "", "name of the new approval step")
+	cmd.MarkFlagRequired("step")
+	cmd.Flags().StringSliceVar(&approvers, "approvers", nil, "emails of the approvers of the new approval step")
+	cmd.MarkFlagRequired("approvers")
+	cmd.Flags().IntVar(&position, "position", 0, "position of the new approval step among the steps, starting from 0")
+	cmd.MarkFlagRequired("position")
+	cmd.Flags().StringVar(&actor, "actor", "", "email of the admin or approver adding the step")
+	cmd.MarkFlagRequired("actor")
+
+	return cmd
+}
+
+func pendingAppealsCommand() *cobra.Command {
+	var approver string
+
+	cmd := &cobra.Command{
+		Use:   "pending",
+		Short: "list appeals waiting for an approver, most critical first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := app.LoadServiceConfig()
 			if err != nil {
 				return err
 			}
-			defer cancel()
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
 
-			_, err = client.UpdateApproval(ctx, &pb.UpdateApprovalRequest{
-				Id:           uint32(id),
-				ApprovalName: approvalName,
-				Action: &pb.UpdateApprovalRequest_Action{
-					Action: "reject",
-				},
-			})
+			appeals, err := services.AppealService.GetPendingForApprover(ctx, approver)
+			if err != nil {
+				return err
+			}
+
+			t := getTablePrinter(os.Stdout, []string{"ID", "PRIORITY", "USER", "RESOURCE ID", "ROLE", "APPROVAL STEP", "CREATED AT"})
+			for _, a := range appeals {
+				t.Append([]string{
+					fmt.Sprintf("%v", a.ID),
+					a.Priority,
+					a.User,
+					fmt.Sprintf("%v", a.ResourceID),
+					a.Role,
+					a.GetNextPendingApproval().Name,
+					a.CreatedAt.Format(time.RFC3339),
+				})
+			}
+			t.Render()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&approver, "approver", "", "email of the approver")
+	cmd.MarkFlagRequired("approver")
+
+	return cmd
+}
+
+func magicLinkCommand() *cobra.Command {
+	var approver string
+	var appealIDs []uint
+
+	cmd := &cobra.Command{
+		Use:   "magic-link",
+		Short: "generate a magic link to approve the pending appeals without an account",
+		Long:  "generate a short-lived magic link opening an approval dashboard scoped to the appeals pending the approver, or to the given ones of them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			if c.MagicLink.Secret == "" || c.MagicLink.URL == "" {
+				return errors.New("magic links require MAGIC_LINK_SECRET and MAGIC_LINK_URL to be set")
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+
+			appeals, err := services.AppealService.GetPendingForApprover(context.Background(), approver)
+			if err != nil {
+				return err
+			}
+			scope := []uint{}
+			for _, a := range appeals {
+				if len(appealIDs) == 0 || containsUint(appealIDs, a.ID) {
+					scope = append(scope, a.ID)
+				}
+			}
+			if len(scope) == 0 {
+				return fmt.Errorf("no appeal is pending the approval of %s", approver)
+			}
+
+			token, expiresAt, err := magiclink.NewSigner(c.MagicLink.Secret, c.MagicLink.TTL).Sign(approver, scope)
 			if err != nil {
 				return err
 			}
+			services.Logger.Info("magic link generated",
+				zap.String("approver", approver),
+				zap.Uints("appeal_ids", scope),
+				zap.Time("expires_at", expiresAt),
+			)
 
-			fmt.Printf("appeal with id %v and approval name %v rejected successfully", id, approvalName)
+			fmt.Println(c.MagicLink.GetLink(token))
+			fmt.Printf("the link is valid for %d appeal(s) until %s\n", len(scope), expiresAt.Format(time.RFC3339))
 
 			return nil
 		},
 	}
 
-	cmd.Flags().UintVar(&id, "id", 0, "appeal id")
-	cmd.MarkFlagRequired("id")
-	cmd.Flags().StringVarP(&approvalName, "approval-name", "a", "", "approval name going to be approved")
-	cmd.MarkFlagRequired("approval-name")
+	cmd.Flags().StringVar(&approver, "approver", "", "email of the approver")
+	cmd.MarkFlagRequired("approver")
+	cmd.Flags().UintSliceVar(&appealIDs, "appeal-ids", nil, "ids of the pending appeals the link is scoped to, default to all of them")
 
 	return cmd
 }
+
+func containsUint(values []uint, v uint) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func deadlockedAppealsCommand() *cobra.Command {
+	var reassignTo []string
+
+	cmd := &cobra.Command{
+		Use:   "deadlocks",
+		Short: "list pending appeals which current approval step has no approver",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+
+			appeals, err := services.AppealService.FindDeadlockedAppeals(ctx)
+			if err != nil {
+				return err
+			}
+
+			t := getTablePrinter(os.Stdout, []string{"ID", "USER", "RESOURCE ID", "ROLE", "APPROVAL STEP", "REASSIGNED TO"})
+			for _, a := range appeals {
+				approvalName := a.GetDeadlockedApproval().Name
+				reassigned := "-"
+				if len(reassignTo) > 0 {
+					if _, err := services.AppealService.ReassignDeadlockedAppeal(ctx, a.ID, reassignTo); err != nil {
+						reassigned = fmt.Sprintf("failed: %s", err)
+					} else {
+						reassigned = strings.Join(reassignTo, ",")
+					}
+				}
+
+				t.Append([]string{
+					fmt.Sprintf("%v", a.ID),
+					a.User,
+					fmt.Sprintf("%v", a.ResourceID),
+					a.Role,
+					approvalName,
+					reassigned,
+				})
+			}
+			t.Render()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&reassignTo, "reassign-to", nil, "approver emails to be assigned to the deadlocked approval steps")
+
+	return cmd
+}
+
+func remindPendingApproversCommand() *cobra.Command {
+	var idleFor time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "remind",
+		Short: "remind approvers of approval steps that have been idle for a while",
+		Long:  "remind approvers of approval steps that have been idle for a while. Intended to be run periodically, e.g. by a cron job",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+
+			if err := services.AppealService.RemindPendingApprovers(ctx, idleFor); err != nil {
+				return err
+			}
+
+			fmt.Println("pending approvers reminded successfully")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&idleFor, "idle-for", 0, "minimum idle duration of an approval step before its approvers are reminded, defaults to the configured duration")
+
+	return cmd
+}
+
+func retryRevocationsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry-revocations",
+		Short: "retry revoking the access of the revocation pending appeals",
+		Long:  "retry revoking the access of the revocation pending appeals right away, regardless of the retry interval",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+
+			if err := services.AppealService.RetryPendingRevocations(context.Background(), true); err != nil {
+				return err
+			}
+
+			fmt.Println("pending revocations retried, the appeals still failing stay revocation pending")
+
+			return nil
+		},
+	}
+}
+
+func importAppealsCommand() *cobra.Command {
+	var filePath string
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "import appeals from a csv file",
+		Long:  "import appeals from a csv file. The header row names the columns: user, resource_urn, and role are required, while expiration_date, status, and provider_type are optional. Rows with the active status are imported as existing access without going through the approval flow",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(filePath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+
+			report, err := services.AppealService.Import(ctx, f)
+			if err != nil {
+				return err
+			}
+
+			t := getTablePrinter(os.Stdout, []string{"LINE", "USER", "RESOURCE URN", "ROLE", "APPEAL ID", "STATUS", "ERROR"})
+			for _, r := range report.Rows {
+				appealID := "-"
+				if r.AppealID != 0 {
+					appealID = fmt.Sprintf("%v", r.AppealID)
+				}
+				t.Append([]string{
+					fmt.Sprintf("%v", r.Line),
+					r.User,
+					r.ResourceURN,
+					r.Role,
+					appealID,
+					r.Status,
+					r.Error,
+				})
+			}
+			t.Render()
+			fmt.Printf("%d imported, %d failed\n", report.Succeeded, report.Failed)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&filePath, "file", "f", "", "path to the csv file")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// makeApprovalAction calls the appeal service directly instead of going through the server,
+// so an approval step can still be actioned while the server is unavailable
+func accessSummaryCommand() *cobra.Command {
+	var user string
+	var withPermissions bool
+
+	cmd := &cobra.Command{
+		Use:   "access",
+		Short: "list the active access of a user across providers",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+
+			entries, err := services.AppealService.GetUserAccessSummary(ctx, user, withPermissions)
+			if err != nil {
+				return err
+			}
+
+			header := []string{"APPEAL ID", "PROVIDER", "RESOURCE TYPE", "RESOURCE URN", "ROLE", "EXPIRATION DATE"}
+			if withPermissions {
+				header = append(header, "PERMISSIONS")
+			}
+			t := getTablePrinter(os.Stdout, header)
+			for _, e := range entries {
+				expirationDate := "-"
+				if e.ExpirationDate != nil {
+					expirationDate = e.ExpirationDate.Format(time.RFC3339)
+				}
+				row := []string{
+					fmt.Sprintf("%v", e.AppealID),
+					fmt.Sprintf("%s/%s", e.ProviderType, e.ProviderURN),
+					e.ResourceType,
+					e.ResourceURN,
+					e.Role,
+					expirationDate,
+				}
+				if withPermissions {
+					permissions := []string{}
+					for _, p := range e.Permissions {
+						permissions = append(permissions, fmt.Sprintf("%v", p))
+					}
+					row = append(row, strings.Join(permissions, ","))
+				}
+				t.Append(row)
+			}
+			t.Render()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&user, "user", "", "email of the user")
+	cmd.MarkFlagRequired("user")
+	cmd.Flags().BoolVar(&withPermissions, "permissions", false, "list the permissions implied by each role")
+
+	return cmd
+}
+
+func makeApprovalAction(appealID, approvalName, actor, action string, conditions *domain.ApprovalConditions) error {
+	id, err := strconv.ParseUint(appealID, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid appeal id %q: %w", appealID, err)
+	}
+
+	c, err := app.LoadServiceConfig()
+	if err != nil {
+		return err
+	}
+	services, err := app.InitServices(c)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	a, err := services.AppealService.MakeAction(ctx, domain.ApprovalAction{
+		AppealID:     uint(id),
+		ApprovalName: approvalName,
+		Actor:        actor,
+		Action:       action,
+		Conditions:   conditions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to %s appeal with id %v on step %q: %w", action, id, approvalName, err)
+	}
+	if a == nil {
+		return fmt.Errorf("appeal with id %v not found", id)
+	}
+
+	fmt.Printf("appeal with id %v and approval name %v: %v\n", a.ID, approvalName, a.Status)
+
+	return nil
+}
+
+// updateApprovalRemotely makes the action through the server at the given host, the same way the other commands
+// talking to the server do
+func updateApprovalRemotely(host, appealID, approvalName, action string) error {
+	id, err := strconv.ParseUint(appealID, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid appeal id %q: %w", appealID, err)
+	}
+
+	ctx := context.Background()
+	client, cancel, err := createClient(ctx, host)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	res, err := client.UpdateApproval(ctx, &pb.UpdateApprovalRequest{
+		Id:           uint32(id),
+		ApprovalName: approvalName,
+		Action: &pb.UpdateApprovalRequest_Action{
+			Action: action,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to %s appeal with id %v on step %q: %w", action, id, approvalName, err)
+	}
+
+	fmt.Printf("appeal with id %v and approval name %v: %v\n", res.GetAppeal().GetId(), approvalName, res.GetAppeal().GetStatus())
+
+	return nil
+}
+
+func makeGroupApprovalAction(groupID, approvalName, actor, action string, conditions *domain.ApprovalConditions) error {
+	c, err := app.LoadServiceConfig()
+	if err != nil {
+		return err
+	}
+	services, err := app.InitServices(c)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	result, err := services.AppealService.MakeGroupAction(ctx, groupID, domain.ApprovalAction{
+		ApprovalName: approvalName,
+		Actor:        actor,
+		Action:       action,
+		Conditions:   conditions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to %s appeal group %q on step %q: %w", action, groupID, approvalName, err)
+	}
+
+	t := getTablePrinter(os.Stdout, []string{"ID", "OUTCOME"})
+	for _, a := range result.Actioned {
+		t.Append([]string{fmt.Sprintf("%v", a.ID), a.Status})
+	}
+	for id, reason := range result.Skipped {
+		t.Append([]string{fmt.Sprintf("%v", id), fmt.Sprintf("skipped: %s", reason)})
+	}
+	for id, reason := range result.Failed {
+		t.Append([]string{fmt.Sprintf("%v", id), fmt.Sprintf("failed: %s", reason)})
+	}
+	t.Render()
+
+	return nil
+}
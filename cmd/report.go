@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/odpf/guardian/app"
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/report"
+	"github.com/spf13/cobra"
+)
+
+const reportDateLayout = "2006-01-02"
+
+func reportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "generate compliance reports",
+	}
+
+	cmd.AddCommand(accessReviewReportCommand())
+	cmd.AddCommand(approverStatsReportCommand())
+
+	return cmd
+}
+
+func accessReviewReportCommand() *cobra.Command {
+	var from, to, format, outputPath string
+	var providerTypes, providerURNs, teams []string
+
+	cmd := &cobra.Command{
+		Use:     "access-review",
+		Short:   "export the access granted within a period for an access review",
+		Long:    "export the access granted within a period for an access review, along with who approved each access, its duration, and whether it has since expired or been revoked. The period includes both the from and the to dates, in UTC",
+		Example: "guardian report access-review --from 2026-07-01 --to 2026-09-30 --format pdf --output access-review-q3.pdf",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fromDate, err := time.Parse(reportDateLayout, from)
+			if err != nil {
+				return fmt.Errorf("invalid from date, expected YYYY-MM-DD: %w", err)
+			}
+			toDate, err := time.Parse(reportDateLayout, to)
+			if err != nil {
+				return fmt.Errorf("invalid to date, expected YYYY-MM-DD: %w", err)
+			}
+			filter := domain.AccessReviewFilter{
+				From:          fromDate,
+				To:            toDate.AddDate(0, 0, 1),
+				ProviderTypes: providerTypes,
+				ProviderURNs:  providerURNs,
+				Teams:         teams,
+			}
+
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+
+			entries, err := services.AppealService.GetAccessReview(context.Background(), filter)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			if outputPath != "" {
+				f, err := os.Create(outputPath)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			if err := report.WriteAccessReview(w, format, filter, entries); err != nil {
+				return err
+			}
+
+			if outputPath != "" {
+				fmt.Printf("access review of %d access written to %s\n", len(entries), outputPath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "first date of the period, e.g. 2026-07-01")
+	cmd.MarkFlagRequired("from")
+	cmd.Flags().StringVar(&to, "to", "", "last date of the period, e.g. 2026-09-30")
+	cmd.MarkFlagRequired("to")
+	cmd.Flags().StringVar(&format, "format", report.FormatCSV, "report format, either csv or pdf")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "path of the report file, defaults to the standard output")
+	cmd.Flags().StringSliceVar(&providerTypes, "provider-type", nil, "restrict the report to the provider types")
+	cmd.Flags().StringSliceVar(&providerURNs, "provider-urn", nil, "restrict the report to the provider urns")
+	cmd.Flags().StringSliceVar(&teams, "team", nil, "restrict the report to the teams, according to the team label of the appeals")
+
+	return cmd
+}
+
+func approverStatsReportCommand() *cobra.Command {
+	var approver, from, to string
+
+	cmd := &cobra.Command{
+		Use:     "approver-stats",
+		Short:   "show how many appeals an approver approved and rejected and their backlog",
+		Long:    "show how many approval steps an approver approved and rejected within a period along with their average response time, and their current backlog of pending appeals. The period includes both the from and the to dates, in UTC, and is unbounded if they're not set",
+		Example: "guardian report approver-stats --approver approver@email.com --from 2026-07-01 --to 2026-09-30",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := &domain.ApproverStatsFilter{}
+			if from != "" {
+				fromDate, err := time.Parse(reportDateLayout, from)
+				if err != nil {
+					return fmt.Errorf("invalid from date, expected YYYY-MM-DD: %w", err)
+				}
+				filter.From = &fromDate
+			}
+			if to != "" {
+				toDate, err := time.Parse(reportDateLayout, to)
+				if err != nil {
+					return fmt.Errorf("invalid to date, expected YYYY-MM-DD: %w", err)
+				}
+				toDate = toDate.AddDate(0, 0, 1)
+				filter.To = &toDate
+			}
+
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+
+			stats, err := services.ApprovalService.GetApproverStats(context.Background(), approver, filter)
+			if err != nil {
+				return err
+			}
+
+			t := getTablePrinter(os.Stdout, []string{"APPROVER", "APPROVED", "REJECTED", "AVERAGE RESPONSE TIME", "BACKLOG"})
+			t.Append([]string{
+				stats.Approver,
+				fmt.Sprintf("%v", stats.ApprovedCount),
+				fmt.Sprintf("%v", stats.RejectedCount),
+				stats.AverageResponseTime.Round(time.Second).String(),
+				fmt.Sprintf("%v", stats.Backlog),
+			})
+			t.Render()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&approver, "approver", "", "email of the approver")
+	cmd.MarkFlagRequired("approver")
+	cmd.Flags().StringVar(&from, "from", "", "first date of the period, e.g. 2026-07-01")
+	cmd.Flags().StringVar(&to, "to", "", "last date of the period, e.g. 2026-09-30")
+
+	return cmd
+}
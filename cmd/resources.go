@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	pb "github.com/odpf/guardian/api/proto/odpf/guardian"
@@ -20,6 +22,9 @@ func resourcesCommand(c *app.CLIConfig) *cobra.Command {
 
 	cmd.AddCommand(listResourcesCommand(c))
 	cmd.AddCommand(metadataCommand(c))
+	cmd.AddCommand(importResourcesCommand())
+	cmd.AddCommand(denyAppealsCommand())
+	cmd.AddCommand(allowAppealsCommand())
 
 	return cmd
 }
@@ -57,6 +62,117 @@ func listResourcesCommand(c *app.CLIConfig) *cobra.Command {
 	}
 }
 
+func importResourcesCommand() *cobra.Command {
+	var providerType, providerURN string
+	var batchSize int
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "import the full resource list of a provider",
+		Long:  "import the full resource list of a registered provider into the resource store in one operation, as the initial load of a newly onboarded provider. The provider is checked to be reachable with its config before importing",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			services, err := app.InitServices(c)
+			if err != nil {
+				return err
+			}
+
+			report, err := services.ProviderService.ImportResources(context.Background(), providerType, providerURN, batchSize)
+			if report != nil {
+				t := getTablePrinter(os.Stdout, []string{"TYPE", "IMPORTED"})
+				types := make([]string, 0, len(report.ResourceTypes))
+				for resourceType := range report.ResourceTypes {
+					types = append(types, resourceType)
+				}
+				sort.Strings(types)
+				for _, resourceType := range types {
+					t.Append([]string{resourceType, fmt.Sprintf("%v", report.ResourceTypes[resourceType])})
+				}
+				t.Render()
+				fmt.Printf("%d resource(s) imported in %d batch(es)\n", report.Imported, report.Batches)
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&providerType, "provider-type", "", "type of the provider")
+	cmd.Flags().StringVar(&providerURN, "provider-urn", "", "urn of the provider")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 500, "number of resources stored at once")
+	cmd.MarkFlagRequired("provider-type")
+	cmd.MarkFlagRequired("provider-urn")
+
+	return cmd
+}
+
+func denyAppealsCommand() *cobra.Command {
+	var actor, reason string
+
+	cmd := &cobra.Command{
+		Use:   "deny <id>",
+		Short: "put a resource on the deny list, rejecting any appeal to it",
+		Long:  "put a resource on the deny list, e.g. to freeze the access during an incident. Any new appeal to the resource is rejected along with the reason, regardless of its policy. The actor must be an admin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setDenyAppeals(args[0], true, reason, actor)
+		},
+	}
+
+	cmd.Flags().StringVar(&actor, "actor", "", "email of the admin")
+	cmd.MarkFlagRequired("actor")
+	cmd.Flags().StringVarP(&reason, "reason", "r", "", "reason returned to the users appealing to the resource")
+	cmd.MarkFlagRequired("reason")
+
+	return cmd
+}
+
+func allowAppealsCommand() *cobra.Command {
+	var actor string
+
+	cmd := &cobra.Command{
+		Use:   "allow <id>",
+		Short: "take a resource off the deny list",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setDenyAppeals(args[0], false, "", actor)
+		},
+	}
+
+	cmd.Flags().StringVar(&actor, "actor", "", "email of the admin")
+	cmd.MarkFlagRequired("actor")
+
+	return cmd
+}
+
+func setDenyAppeals(idArg string, deny bool, reason, actor string) error {
+	id, err := strconv.ParseUint(idArg, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid resource id %q: %w", idArg, err)
+	}
+
+	c, err := app.LoadServiceConfig()
+	if err != nil {
+		return err
+	}
+	services, err := app.InitServices(c)
+	if err != nil {
+		return err
+	}
+
+	if err := services.ResourceService.SetDenyAppeals(context.Background(), uint(id), deny, reason, actor); err != nil {
+		return err
+	}
+
+	if deny {
+		fmt.Printf("resource with id %v is on the deny list\n", id)
+	} else {
+		fmt.Printf("resource with id %v is off the deny list\n", id)
+	}
+	return nil
+}
+
 func metadataCommand(c *app.CLIConfig) *cobra.Command {
 	var id uint
 	var values []string
@@ -24,11 +24,13 @@ func Execute() {
 
 	rootCmd.AddCommand(serveCommand())
 	rootCmd.AddCommand(migrateCommand())
+	rootCmd.AddCommand(workerCommand())
 	rootCmd.AddCommand(configCommand())
 	rootCmd.AddCommand(resourcesCommand(cliConfig))
 	rootCmd.AddCommand(providersCommand(cliConfig, protoAdapter))
 	rootCmd.AddCommand(policiesCommand(cliConfig, protoAdapter))
 	rootCmd.AddCommand(appealsCommand(cliConfig))
+	rootCmd.AddCommand(reportCommand())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/odpf/guardian/app"
+	"github.com/spf13/cobra"
+)
+
+func workerCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "worker",
+		Short: "Run background jobs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := app.LoadServiceConfig()
+			if err != nil {
+				return err
+			}
+			return app.RunWorker(c)
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HMAC does sign and verify using HMAC-SHA256 algorithm
+type HMAC struct {
+	key []byte
+}
+
+// NewHMAC returns string signer
+func NewHMAC(key string) *HMAC {
+	return &HMAC{
+		key: []byte(key),
+	}
+}
+
+// Sign returns the signature of a plain text
+func (c *HMAC) Sign(value string) (string, error) {
+	mac := hmac.New(sha256.New, c.key)
+	if _, err := mac.Write([]byte(value)); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Verify checks whether the signature belongs to the plain text
+func (c *HMAC) Verify(value, signature string) (bool, error) {
+	decodedSignature, err := hex.DecodeString(signature)
+	if err != nil {
+		return false, err
+	}
+
+	mac := hmac.New(sha256.New, c.key)
+	if _, err := mac.Write([]byte(value)); err != nil {
+		return false, err
+	}
+
+	return hmac.Equal(decodedSignature, mac.Sum(nil)), nil
+}
@@ -0,0 +1,12 @@
+package delegation
+
+import "errors"
+
+var (
+	// ErrInvalidTimeRange is the error value when the delegation doesn't end after it starts
+	ErrInvalidTimeRange = errors.New("delegation end time should be after its start time")
+	// ErrAlreadyEnded is the error value when the delegation end time has passed
+	ErrAlreadyEnded = errors.New("delegation end time has passed")
+	// ErrEmptyUser is the error value when the user param is empty
+	ErrEmptyUser = errors.New("user param is required")
+)
@@ -0,0 +1,27 @@
+package delegation
+
+import (
+	"context"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// JobHandler for cronjob
+type JobHandler struct {
+	delegationService domain.ApprovalDelegationService
+}
+
+// NewJobHandler returns *JobHandler
+func NewJobHandler(ds domain.ApprovalDelegationService) *JobHandler {
+	return &JobHandler{ds}
+}
+
+// NotifyStartedDelegations notifies the delegators and the delegates of the started delegations
+func (h *JobHandler) NotifyStartedDelegations() error {
+	return h.delegationService.NotifyStartedDelegations(context.Background())
+}
+
+// DeleteExpiredDelegations notifies the delegators and the delegates of the ended delegations and deletes them
+func (h *JobHandler) DeleteExpiredDelegations() error {
+	return h.delegationService.DeleteExpiredDelegations(context.Background())
+}
@@ -0,0 +1,115 @@
+package delegation
+
+import (
+	"context"
+	"time"
+
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/model"
+	"gorm.io/gorm"
+)
+
+// Repository talks to the store to read or insert the approval delegations
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository returns *Repository
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db}
+}
+
+// scoped restricts the queries to the delegations of the organization carried by ctx, if any
+func (r *Repository) scoped(ctx context.Context) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+		db = db.Where(`"organization_id" = ?`, organizationID)
+	}
+	return db
+}
+
+// Create stores the delegation, in the organization carried by ctx if any, and sets its id
+func (r *Repository) Create(ctx context.Context, d *domain.ApprovalDelegation) error {
+	if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+		d.OrganizationID = organizationID
+	}
+
+	m := new(model.ApprovalDelegation)
+	if err := m.FromDomain(d); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+
+	created, err := m.ToDomain()
+	if err != nil {
+		return err
+	}
+	*d = *created
+
+	return nil
+}
+
+// GetByUser returns the delegations the user is either the delegator or the delegate of, the latest first
+func (r *Repository) GetByUser(ctx context.Context, user string) ([]*domain.ApprovalDelegation, error) {
+	return r.find(r.scoped(ctx).
+		Where(`"delegator" = ? OR "delegate" = ?`, user, user).
+		Order("start_time DESC"))
+}
+
+// GetActiveByDelegate returns the delegations to the delegate active at the given time
+func (r *Repository) GetActiveByDelegate(ctx context.Context, delegate string, now time.Time) ([]*domain.ApprovalDelegation, error) {
+	return r.find(r.scoped(ctx).
+		Where(`"delegate" = ? AND "start_time" <= ? AND "end_time" > ?`, delegate, now, now))
+}
+
+// GetStartedUnnotified returns the active delegations which start isn't notified yet
+func (r *Repository) GetStartedUnnotified(ctx context.Context, now time.Time) ([]*domain.ApprovalDelegation, error) {
+	return r.find(r.db.WithContext(ctx).
+		Where(`"start_notified" = ? AND "start_time" <= ? AND "end_time" > ?`, false, now, now))
+}
+
+// SetStartNotified flags the start of the delegations as notified
+func (r *Repository) SetStartNotified(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&model.ApprovalDelegation{}).
+		Where("id IN ?", ids).
+		Update("start_notified", true).Error
+}
+
+// GetExpired returns the delegations ended at or before the given time
+func (r *Repository) GetExpired(ctx context.Context, now time.Time) ([]*domain.ApprovalDelegation, error) {
+	return r.find(r.db.WithContext(ctx).Where(`"end_time" <= ?`, now))
+}
+
+// Delete removes the delegations
+func (r *Repository) Delete(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&model.ApprovalDelegation{}).Error
+}
+
+func (r *Repository) find(db *gorm.DB) ([]*domain.ApprovalDelegation, error) {
+	var models []*model.ApprovalDelegation
+	if err := db.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	records := []*domain.ApprovalDelegation{}
+	for _, m := range models {
+		d, err := m.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, d)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,167 @@
+package delegation_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/odpf/guardian/auth"
+	"github.com/odpf/guardian/delegation"
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/odpf/guardian/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type RepositoryTestSuite struct {
+	suite.Suite
+	sqldb      *sql.DB
+	dbmock     sqlmock.Sqlmock
+	repository *delegation.Repository
+}
+
+func (s *RepositoryTestSuite) SetupTest() {
+	db, mock, _ := mocks.NewStore()
+	s.sqldb, _ = db.DB()
+	s.dbmock = mock
+	s.repository = delegation.NewRepository(db)
+}
+
+func (s *RepositoryTestSuite) TearDownTest() {
+	s.sqldb.Close()
+}
+
+func (s *RepositoryTestSuite) TestCreate() {
+	s.Run("should insert the delegation and return its id", func() {
+		startTime := time.Date(2022, 1, 6, 8, 0, 0, 0, time.UTC)
+		endTime := startTime.Add(24 * time.Hour)
+		d := &domain.ApprovalDelegation{
+			Delegator: "approver@email.com",
+			Delegate:  "delegate@email.com",
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+		expectedQuery := regexp.QuoteMeta(`INSERT INTO "approval_delegations" ("delegator","delegate","start_time","end_time","start_notified","organization_id","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`)
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs("approver@email.com", "delegate@email.com", startTime, endTime, false, "", utils.AnyTime{}, utils.AnyTime{}).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		s.dbmock.ExpectCommit()
+
+		err := s.repository.Create(context.Background(), d)
+
+		s.Nil(err)
+		s.Equal(uint(1), d.ID)
+	})
+
+	s.Run("should create the delegation in the organization of the context", func() {
+		startTime := time.Date(2022, 1, 6, 8, 0, 0, 0, time.UTC)
+		endTime := startTime.Add(24 * time.Hour)
+		d := &domain.ApprovalDelegation{
+			Delegator: "approver@email.com",
+			Delegate:  "delegate@email.com",
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+		expectedQuery := regexp.QuoteMeta(`INSERT INTO "approval_delegations" ("delegator","delegate","start_time","end_time","start_notified","organization_id","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`)
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs("approver@email.com", "delegate@email.com", startTime, endTime, false, "org-a", utils.AnyTime{}, utils.AnyTime{}).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		s.dbmock.ExpectCommit()
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		err := s.repository.Create(ctx, d)
+
+		s.Nil(err)
+		s.Equal("org-a", d.OrganizationID)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetActiveByDelegate() {
+	expectedQuery := regexp.QuoteMeta(`SELECT * FROM "approval_delegations" WHERE "delegate" = $1 AND "start_time" <= $2 AND "end_time" > $3`)
+	now := time.Date(2022, 1, 6, 8, 0, 0, 0, time.UTC)
+
+	s.Run("should return error if db returns error", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectQuery(expectedQuery).WillReturnError(expectedError)
+
+		actualRecords, actualError := s.repository.GetActiveByDelegate(context.Background(), "delegate@email.com", now)
+
+		s.Nil(actualRecords)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return the active delegations", func() {
+		rows := sqlmock.NewRows([]string{"id", "delegator", "delegate", "start_time", "end_time", "start_notified"}).
+			AddRow(1, "approver@email.com", "delegate@email.com", now.Add(-time.Hour), now.Add(time.Hour), true)
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs("delegate@email.com", now, now).WillReturnRows(rows)
+
+		actualRecords, actualError := s.repository.GetActiveByDelegate(context.Background(), "delegate@email.com", now)
+
+		s.Nil(actualError)
+		s.Equal([]*domain.ApprovalDelegation{{
+			ID:            1,
+			Delegator:     "approver@email.com",
+			Delegate:      "delegate@email.com",
+			StartTime:     now.Add(-time.Hour),
+			EndTime:       now.Add(time.Hour),
+			StartNotified: true,
+		}}, actualRecords)
+	})
+
+	s.Run("should only return the delegations of the organization", func() {
+		expectedOrganizationQuery := regexp.QuoteMeta(`SELECT * FROM "approval_delegations" WHERE ("organization_id" = $1) AND ("delegate" = $2 AND "start_time" <= $3 AND "end_time" > $4)`)
+		s.dbmock.ExpectQuery(expectedOrganizationQuery).
+			WithArgs("org-a", "delegate@email.com", now, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		actualRecords, actualError := s.repository.GetActiveByDelegate(ctx, "delegate@email.com", now)
+
+		s.Nil(actualError)
+		s.Empty(actualRecords)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetExpired() {
+	s.Run("should return the delegations ended at or before the given time", func() {
+		now := time.Date(2022, 1, 6, 8, 0, 0, 0, time.UTC)
+		expectedQuery := regexp.QuoteMeta(`SELECT * FROM "approval_delegations" WHERE "end_time" <= $1`)
+		rows := sqlmock.NewRows([]string{"id", "delegator", "delegate", "end_time"}).
+			AddRow(1, "approver@email.com", "delegate@email.com", now)
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(now).WillReturnRows(rows)
+
+		actualRecords, actualError := s.repository.GetExpired(context.Background(), now)
+
+		s.Nil(actualError)
+		s.Len(actualRecords, 1)
+		s.Equal(uint(1), actualRecords[0].ID)
+	})
+}
+
+func (s *RepositoryTestSuite) TestDelete() {
+	s.Run("should skip the query if there is nothing to delete", func() {
+		s.Nil(s.repository.Delete(context.Background(), []uint{}))
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+
+	s.Run("should delete the delegations", func() {
+		expectedQuery := regexp.QuoteMeta(`DELETE FROM "approval_delegations" WHERE id IN ($1,$2)`)
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectExec(expectedQuery).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 2))
+		s.dbmock.ExpectCommit()
+
+		s.Nil(s.repository.Delete(context.Background(), []uint{1, 2}))
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository(t *testing.T) {
+	suite.Run(t, new(RepositoryTestSuite))
+}
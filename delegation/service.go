@@ -0,0 +1,166 @@
+package delegation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
+	"go.uber.org/zap"
+)
+
+const timeFormat = "2006-01-02 15:04 MST"
+
+// Service manages the approval delegations. The delegator and the delegate are notified when a delegation
+// starts and when it ends, the ended delegations are then deleted
+type Service struct {
+	repo     domain.ApprovalDelegationRepository
+	notifier domain.Notifier
+	logger   *zap.Logger
+
+	TimeNow func() time.Time
+}
+
+// NewService returns *Service
+func NewService(repo domain.ApprovalDelegationRepository, notifier domain.Notifier, logger *zap.Logger) *Service {
+	return &Service{
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
+		TimeNow:  time.Now,
+	}
+}
+
+// Create validates and stores the delegation
+func (s *Service) Create(ctx context.Context, d *domain.ApprovalDelegation) error {
+	if err := utils.ValidateStruct(d); err != nil {
+		return err
+	}
+	if !d.EndTime.After(d.StartTime) {
+		return ErrInvalidTimeRange
+	}
+	if !d.EndTime.After(s.TimeNow()) {
+		return ErrAlreadyEnded
+	}
+	d.StartNotified = false
+
+	if err := s.repo.Create(ctx, d); err != nil {
+		return err
+	}
+	s.logger.Info("approval delegation created",
+		zap.Uint("delegation_id", d.ID),
+		zap.String("delegator", d.Delegator),
+		zap.String("delegate", d.Delegate),
+		zap.Time("start_time", d.StartTime),
+		zap.Time("end_time", d.EndTime),
+	)
+	return nil
+}
+
+// GetByUser returns the delegations the user is either the delegator or the delegate of
+func (s *Service) GetByUser(ctx context.Context, user string) ([]*domain.ApprovalDelegation, error) {
+	if user == "" {
+		return nil, ErrEmptyUser
+	}
+	return s.repo.GetByUser(ctx, user)
+}
+
+// GetActiveDelegators returns the delegators the delegate can currently act for. The expired delegations are
+// ignored even if they're not deleted yet
+func (s *Service) GetActiveDelegators(ctx context.Context, delegate string) ([]string, error) {
+	if delegate == "" {
+		return nil, nil
+	}
+
+	now := s.TimeNow()
+	delegations, err := s.repo.GetActiveByDelegate(ctx, delegate, now)
+	if err != nil {
+		return nil, err
+	}
+
+	delegators := []string{}
+	for _, d := range delegations {
+		if d.IsActive(now) && !utils.ContainsString(delegators, d.Delegator) {
+			delegators = append(delegators, d.Delegator)
+		}
+	}
+	return delegators, nil
+}
+
+// NotifyStartedDelegations notifies the delegator and the delegate of the delegations started since the last run
+func (s *Service) NotifyStartedDelegations(ctx context.Context) error {
+	delegations, err := s.repo.GetStartedUnnotified(ctx, s.TimeNow())
+	if err != nil {
+		return err
+	}
+	if len(delegations) == 0 {
+		return nil
+	}
+
+	notifications := []domain.Notification{}
+	ids := []uint{}
+	for _, d := range delegations {
+		notifications = append(notifications,
+			domain.Notification{
+				User:    d.Delegator,
+				Message: fmt.Sprintf("%s can now act on your approvals until %s", d.Delegate, d.EndTime.Format(timeFormat)),
+			},
+			domain.Notification{
+				User:    d.Delegate,
+				Message: fmt.Sprintf("You can now act on the approvals of %s until %s", d.Delegator, d.EndTime.Format(timeFormat)),
+			},
+		)
+		ids = append(ids, d.ID)
+	}
+
+	if err := s.notifier.Notify(notifications); err != nil {
+		return err
+	}
+	if err := s.repo.SetStartNotified(ctx, ids); err != nil {
+		return err
+	}
+	s.logger.Info("started approval delegations notified", zap.Int("count", len(ids)))
+	return nil
+}
+
+// DeleteExpiredDelegations notifies the delegator and the delegate of the ended delegations, then deletes them.
+// The delegations ended before they were notified to start are deleted silently
+func (s *Service) DeleteExpiredDelegations(ctx context.Context) error {
+	delegations, err := s.repo.GetExpired(ctx, s.TimeNow())
+	if err != nil {
+		return err
+	}
+	if len(delegations) == 0 {
+		return nil
+	}
+
+	notifications := []domain.Notification{}
+	ids := []uint{}
+	for _, d := range delegations {
+		if d.StartNotified {
+			notifications = append(notifications,
+				domain.Notification{
+					User:    d.Delegator,
+					Message: fmt.Sprintf("%s can no longer act on your approvals, the delegation has ended", d.Delegate),
+				},
+				domain.Notification{
+					User:    d.Delegate,
+					Message: fmt.Sprintf("You can no longer act on the approvals of %s, the delegation has ended", d.Delegator),
+				},
+			)
+		}
+		ids = append(ids, d.ID)
+	}
+
+	if len(notifications) > 0 {
+		if err := s.notifier.Notify(notifications); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.Delete(ctx, ids); err != nil {
+		return err
+	}
+	s.logger.Info("expired approval delegations deleted", zap.Int("count", len(ids)))
+	return nil
+}
@@ -0,0 +1,196 @@
+package delegation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/delegation"
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type ServiceTestSuite struct {
+	suite.Suite
+	mockRepository *mocks.ApprovalDelegationRepository
+	mockNotifier   *mocks.Notifier
+	now            time.Time
+
+	service *delegation.Service
+}
+
+func (s *ServiceTestSuite) SetupTest() {
+	s.mockRepository = new(mocks.ApprovalDelegationRepository)
+	s.mockNotifier = new(mocks.Notifier)
+	s.now = time.Date(2022, 1, 6, 8, 0, 0, 0, time.UTC)
+
+	s.service = delegation.NewService(s.mockRepository, s.mockNotifier, zap.NewNop())
+	s.service.TimeNow = func() time.Time { return s.now }
+}
+
+func (s *ServiceTestSuite) TestCreate() {
+	s.Run("should return error if the delegation is invalid", func() {
+		testCases := []struct {
+			name          string
+			delegation    *domain.ApprovalDelegation
+			expectedError error
+		}{
+			{
+				name: "delegate to themselves",
+				delegation: &domain.ApprovalDelegation{
+					Delegator: "approver@email.com",
+					Delegate:  "approver@email.com",
+					StartTime: s.now,
+					EndTime:   s.now.Add(time.Hour),
+				},
+			},
+			{
+				name: "end time before the start time",
+				delegation: &domain.ApprovalDelegation{
+					Delegator: "approver@email.com",
+					Delegate:  "delegate@email.com",
+					StartTime: s.now.Add(time.Hour),
+					EndTime:   s.now,
+				},
+				expectedError: delegation.ErrInvalidTimeRange,
+			},
+			{
+				name: "end time equal to the start time",
+				delegation: &domain.ApprovalDelegation{
+					Delegator: "approver@email.com",
+					Delegate:  "delegate@email.com",
+					StartTime: s.now.Add(time.Hour),
+					EndTime:   s.now.Add(time.Hour),
+				},
+				expectedError: delegation.ErrInvalidTimeRange,
+			},
+			{
+				name: "already ended",
+				delegation: &domain.ApprovalDelegation{
+					Delegator: "approver@email.com",
+					Delegate:  "delegate@email.com",
+					StartTime: s.now.Add(-2 * time.Hour),
+					EndTime:   s.now.Add(-time.Hour),
+				},
+				expectedError: delegation.ErrAlreadyEnded,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				actualError := s.service.Create(context.Background(), tc.delegation)
+
+				s.NotNil(actualError)
+				if tc.expectedError != nil {
+					s.ErrorIs(actualError, tc.expectedError)
+				}
+			})
+		}
+	})
+
+	s.Run("should store the delegation", func() {
+		d := &domain.ApprovalDelegation{
+			Delegator: "approver@email.com",
+			Delegate:  "delegate@email.com",
+			StartTime: s.now,
+			EndTime:   s.now.Add(24 * time.Hour),
+		}
+		s.mockRepository.On("Create", mock.Anything, d).Return(nil).Once()
+
+		actualError := s.service.Create(context.Background(), d)
+
+		s.Nil(actualError)
+		s.mockRepository.AssertExpectations(s.T())
+	})
+}
+
+func (s *ServiceTestSuite) TestGetActiveDelegators() {
+	s.Run("should return the delegators of the active delegations", func() {
+		s.mockRepository.On("GetActiveByDelegate", mock.Anything, "delegate@email.com", s.now).Return([]*domain.ApprovalDelegation{
+			{Delegator: "approver@email.com", Delegate: "delegate@email.com", StartTime: s.now.Add(-time.Hour), EndTime: s.now.Add(time.Hour)},
+			{Delegator: "approver@email.com", Delegate: "delegate@email.com", StartTime: s.now, EndTime: s.now.Add(2 * time.Hour)},
+			{Delegator: "expired@email.com", Delegate: "delegate@email.com", StartTime: s.now.Add(-time.Hour), EndTime: s.now},
+		}, nil).Once()
+
+		actualDelegators, actualError := s.service.GetActiveDelegators(context.Background(), "delegate@email.com")
+
+		s.Nil(actualError)
+		s.Equal([]string{"approver@email.com"}, actualDelegators)
+	})
+
+	s.Run("should return error if got error from repository", func() {
+		expectedError := errors.New("db error")
+		s.mockRepository.On("GetActiveByDelegate", mock.Anything, "delegate@email.com", s.now).Return(nil, expectedError).Once()
+
+		actualDelegators, actualError := s.service.GetActiveDelegators(context.Background(), "delegate@email.com")
+
+		s.Nil(actualDelegators)
+		s.ErrorIs(actualError, expectedError)
+	})
+}
+
+func (s *ServiceTestSuite) TestNotifyStartedDelegations() {
+	s.Run("should notify the delegator and the delegate then flag the delegations", func() {
+		s.mockRepository.On("GetStartedUnnotified", mock.Anything, s.now).Return([]*domain.ApprovalDelegation{
+			{ID: 1, Delegator: "approver@email.com", Delegate: "delegate@email.com", StartTime: s.now, EndTime: s.now.Add(time.Hour)},
+		}, nil).Once()
+		s.mockNotifier.On("Notify", mock.MatchedBy(func(notifications []domain.Notification) bool {
+			return len(notifications) == 2 &&
+				notifications[0].User == "approver@email.com" &&
+				notifications[1].User == "delegate@email.com"
+		})).Return(nil).Once()
+		s.mockRepository.On("SetStartNotified", mock.Anything, []uint{1}).Return(nil).Once()
+
+		actualError := s.service.NotifyStartedDelegations(context.Background())
+
+		s.Nil(actualError)
+		s.mockNotifier.AssertExpectations(s.T())
+		s.mockRepository.AssertExpectations(s.T())
+	})
+
+	s.Run("should not flag the delegations if the notification fails", func() {
+		expectedError := errors.New("notifier error")
+		s.mockRepository.On("GetStartedUnnotified", mock.Anything, s.now).Return([]*domain.ApprovalDelegation{{ID: 1}}, nil).Once()
+		s.mockNotifier.On("Notify", mock.Anything).Return(expectedError).Once()
+
+		actualError := s.service.NotifyStartedDelegations(context.Background())
+
+		s.ErrorIs(actualError, expectedError)
+	})
+}
+
+func (s *ServiceTestSuite) TestDeleteExpiredDelegations() {
+	s.Run("should notify the end of the started delegations and delete every expired delegation", func() {
+		s.mockRepository.On("GetExpired", mock.Anything, s.now).Return([]*domain.ApprovalDelegation{
+			{ID: 1, Delegator: "approver@email.com", Delegate: "delegate@email.com", StartNotified: true},
+			{ID: 2, Delegator: "other.approver@email.com", Delegate: "delegate@email.com"},
+		}, nil).Once()
+		s.mockNotifier.On("Notify", mock.MatchedBy(func(notifications []domain.Notification) bool {
+			return len(notifications) == 2 &&
+				notifications[0].User == "approver@email.com" &&
+				notifications[1].User == "delegate@email.com"
+		})).Return(nil).Once()
+		s.mockRepository.On("Delete", mock.Anything, []uint{1, 2}).Return(nil).Once()
+
+		actualError := s.service.DeleteExpiredDelegations(context.Background())
+
+		s.Nil(actualError)
+		s.mockNotifier.AssertExpectations(s.T())
+		s.mockRepository.AssertExpectations(s.T())
+	})
+
+	s.Run("should do nothing if there is no expired delegation", func() {
+		s.mockRepository.On("GetExpired", mock.Anything, s.now).Return([]*domain.ApprovalDelegation{}, nil).Once()
+
+		actualError := s.service.DeleteExpiredDelegations(context.Background())
+
+		s.Nil(actualError)
+	})
+}
+
+func TestService(t *testing.T) {
+	suite.Run(t, new(ServiceTestSuite))
+}
@@ -1,42 +1,127 @@
 package domain
 
 import (
+	"context"
+	"io"
 	"time"
 )
 
 const (
 	AppealActionNameApprove = "approve"
 	AppealActionNameReject  = "reject"
+	AppealActionNameCancel  = "cancel"
+	AppealActionNameRevoke  = "revoke"
+	AppealActionNameUndo    = "undo"
+	// AppealActionNameRequestChanges sends the appeal back to the requester for more information, the approval
+	// step stays pending
+	AppealActionNameRequestChanges = "request_changes"
+	// AppealActionNameResubmit follows up the changes requested on the appeal, made by the requester
+	AppealActionNameResubmit = "resubmit"
 
 	AppealStatusPending    = "pending"
 	AppealStatusCanceled   = "canceled"
 	AppealStatusActive     = "active"
 	AppealStatusRejected   = "rejected"
 	AppealStatusTerminated = "terminated"
+	// AppealStatusRevocationPending is the status of the appeals which access failed to be revoked, the access is
+	// still granted until a retry of the revocation succeeds
+	AppealStatusRevocationPending = "revocation_pending"
+
+	AppealPriorityLow    = "low"
+	AppealPriorityNormal = "normal"
+	AppealPriorityHigh   = "high"
+	AppealPriorityUrgent = "urgent"
 
 	SystemActorName = "system"
+
+	CommentVisibilityPublic  = "public"
+	CommentVisibilityPrivate = "private"
 )
 
+// AppealPriorities are the allowed appeal priorities, ordered from the lowest
+var AppealPriorities = []string{
+	AppealPriorityLow,
+	AppealPriorityNormal,
+	AppealPriorityHigh,
+	AppealPriorityUrgent,
+}
+
 // AppealOptions
 type AppealOptions struct {
-	ExpirationDate *time.Time `json:"expiration_date,omitempty"`
+	// StartDate schedules the access to be granted at a future time once the appeal is approved
+	StartDate      *time.Time    `json:"start_date,omitempty"`
+	ExpirationDate *time.Time    `json:"expiration_date,omitempty"`
+	AccessWindow   *AccessWindow `json:"access_window,omitempty"`
+}
+
+// AccessWindow restricts the granted access to be only active on certain days and hours.
+// The access is revoked outside the window and granted back once the window reopens
+type AccessWindow struct {
+	// Days are the lowercased week days, e.g. monday. Every day is allowed if empty
+	Days []string `json:"days,omitempty"`
+	// StartTime and EndTime are in HH:MM format. The window ends on the next day if EndTime is before StartTime
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	// Timezone is the IANA time zone name of the window. Default: UTC
+	Timezone string `json:"timezone,omitempty"`
 }
 
 // Appeal struct
 type Appeal struct {
-	ID            uint              `json:"id"`
-	ResourceID    uint              `json:"resource_id"`
-	PolicyID      string            `json:"policy_id"`
-	PolicyVersion uint              `json:"policy_version"`
-	Status        string            `json:"status"`
-	User          string            `json:"user"`
-	Role          string            `json:"role"`
-	Options       *AppealOptions    `json:"options"`
-	Labels        map[string]string `json:"labels"`
+	ID            uint   `json:"id"`
+	ResourceID    uint   `json:"resource_id"`
+	PolicyID      string `json:"policy_id"`
+	PolicyVersion uint   `json:"policy_version"`
+	Status        string `json:"status"`
+	User          string `json:"user"`
+	Role          string `json:"role"`
+	Priority      string `json:"priority"`
+	// OrganizationID is the organization of the appeal's resource. The appeals are only visible to and actioned
+	// within their organization
+	OrganizationID string `json:"organization_id,omitempty"`
+	// GroupID groups the appeals created together, e.g. the appeals of a multi-resource request.
+	// It's empty for the appeals created alone
+	GroupID string            `json:"group_id,omitempty"`
+	Options *AppealOptions    `json:"options"`
+	Labels  map[string]string `json:"labels"`
 
 	RevokedBy    string    `json:"revoked_by"`
 	RevokedAt    time.Time `json:"revoked_at"`
 	RevokeReason string    `json:"revoke_reason"`
+	// RevocationAttempts is the number of the failed attempts to revoke the access
+	RevocationAttempts int `json:"revocation_attempts,omitempty"`
+	// RevocationError is the error of the last failed attempt to revoke the access
+	RevocationError string `json:"revocation_error,omitempty"`
+
+	// CanceledBy is the user canceling the appeal, or the system actor if it's canceled automatically
+	CanceledBy string `json:"canceled_by,omitempty"`
+
+	// AccessWindowClosed is true while the access is revoked for being outside its access window
+	AccessWindowClosed bool `json:"access_window_closed"`
+	// AccessWindowToggleAt is when the access window of the active access opens or closes next
+	AccessWindowToggleAt *time.Time `json:"access_window_toggle_at,omitempty"`
+	// AccessScheduled is true while the approved access waits for its start date to be granted
+	AccessScheduled bool `json:"access_scheduled"`
+	// UndoDeadline is the end of the grace window after the final approval, the approval can be undone until then
+	// and the access is only granted after it
+	UndoDeadline *time.Time `json:"undo_deadline,omitempty"`
+
+	// RemindersMutedBy is the requester or approver muting the reminders of the appeal's idle approval steps,
+	// e.g. while it's parked waiting on something external. The other notifications are still sent
+	RemindersMutedBy     string     `json:"reminders_muted_by,omitempty"`
+	RemindersMutedAt     *time.Time `json:"reminders_muted_at,omitempty"`
+	RemindersMutedReason string     `json:"reminders_muted_reason,omitempty"`
+
+	// IsSLABreached is set on the pending appeals listed for the approvers once the current step is
+	// past its SLA. It's not stored
+	IsSLABreached bool `json:"is_sla_breached,omitempty"`
+
+	// Warnings flag the unusual aspects of the appeal for the approvers to scrutinize. They don't block the appeal
+	Warnings []*AppealWarning `json:"warnings,omitempty"`
+
+	// Requested is the role and options originally requested, set once an approver approves the appeal with
+	// conditions. The appeal's role and options are then the ones granted
+	Requested *GrantParameters `json:"requested,omitempty"`
 
 	Policy    *Policy     `json:"-"`
 	Resource  *Resource   `json:"resource,omitempty"`
@@ -48,6 +133,9 @@ type Appeal struct {
 
 func (a *Appeal) GetNextPendingApproval() *Approval {
 	for _, approval := range a.Approvals {
+		if approval.Status == ApprovalStatusPendingApproverResolution {
+			return nil
+		}
 		if approval.Status == ApprovalStatusPending && approval.IsManualApproval() {
 			return approval
 		}
@@ -55,27 +143,295 @@ func (a *Appeal) GetNextPendingApproval() *Approval {
 	return nil
 }
 
+// IsApprover returns true if the user is an approver of any of the approval steps
+func (a *Appeal) IsApprover(user string) bool {
+	for _, approval := range a.Approvals {
+		for _, approver := range approval.Approvers {
+			if approver == user {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetPriorityLevel returns the index of the appeal priority in AppealPriorities.
+// Unrecognized priorities are considered the lowest
+func (a *Appeal) GetPriorityLevel() int {
+	for i, p := range AppealPriorities {
+		if a.Priority == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsHighPriority returns true if the appeal needs an immediate attention from the approvers
+func (a *Appeal) IsHighPriority() bool {
+	return a.Priority == AppealPriorityHigh || a.Priority == AppealPriorityUrgent
+}
+
+// GetDeadlockedApproval returns the current pending approval step if it doesn't have any approver.
+// Steps without approvers are resolved on creation, so a pending one can never progress
+func (a *Appeal) GetDeadlockedApproval() *Approval {
+	if a.Status != AppealStatusPending {
+		return nil
+	}
+	for _, approval := range a.Approvals {
+		if approval.Status == ApprovalStatusPending {
+			if !approval.IsManualApproval() {
+				return approval
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// AppealWarning is a risk signal raised on an appeal when it's created
+type AppealWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// AppealHistory summarizes the previous appeals of a user, the appeal warning rules compare a new appeal against it
+type AppealHistory struct {
+	// RoleGranted tells whether the user has ever been granted the role of the new appeal
+	RoleGranted bool
+	// PermanentAccessRequested tells whether the user has ever requested a permanent access
+	PermanentAccessRequested bool
+	// LongestRequestedDuration is the longest access duration the user has requested, zero if none
+	LongestRequestedDuration time.Duration
+}
+
+// AppealComment is a note left on an appeal. Private comments are only visible to the approvers
+type AppealComment struct {
+	ID         uint      `json:"id"`
+	AppealID   uint      `json:"appeal_id"`
+	CreatedBy  string    `json:"created_by"`
+	Body       string    `json:"body"`
+	Visibility string    `json:"visibility"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// IsPrivate returns true if the comment is hidden from the requester
+func (c *AppealComment) IsPrivate() bool {
+	return c.Visibility == CommentVisibilityPrivate
+}
+
 type ApprovalAction struct {
 	AppealID     uint   `validate:"required"`
 	ApprovalName string `validate:"required"`
 	Actor        string `validate:"email"`
-	Action       string `validate:"required,oneof=approve reject"`
+	Action       string `validate:"required,oneof=approve reject request_changes"`
+	Reason       string
+	// Comment explains the changes needed to the requester, required by the request changes action
+	Comment string
+	// Conditions narrow down the access granted on approval, only applicable to the approve action
+	Conditions *ApprovalConditions
+}
+
+// ApprovalConditions right-size the requested access at approval, e.g. granting a read-only role for a day
+// instead of the requested write role for a week. The granted access must be a subset of the requested one
+type ApprovalConditions struct {
+	// Role replaces the requested role, its permissions must be a subset of the requested role's permissions
+	Role string `json:"role,omitempty"`
+	// Duration replaces the requested access duration, counted from when the access is granted, e.g. 24h.
+	// The access can't last longer than requested
+	Duration string `json:"duration,omitempty"`
+}
+
+// GrantParameters are the parameters of the access granted by an appeal
+type GrantParameters struct {
+	Role    string         `json:"role"`
+	Options *AppealOptions `json:"options,omitempty"`
+}
+
+// GroupActionResult is the aggregate outcome of an action made on the appeals of a group
+type GroupActionResult struct {
+	GroupID string `json:"group_id"`
+	// Actioned are the appeals the action is made on
+	Actioned []*Appeal `json:"actioned"`
+	// Skipped are the appeals the action doesn't apply to along with the reason, e.g. already approved
+	// or waiting for another approver
+	Skipped map[uint]string `json:"skipped"`
+	// Failed are the appeals the action failed on along with the error, e.g. a provider failure while
+	// granting the access
+	Failed map[uint]string `json:"failed"`
+}
+
+// AppealStatusEvent is a change made on an appeal, e.g. an action on one of its approval steps or its revocation
+type AppealStatusEvent struct {
+	// ID orders the events published by a service instance
+	ID       uint64 `json:"id"`
+	AppealID uint   `json:"appeal_id"`
+	// Status is the appeal status after the change, it may stay pending after an action on an approval step
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status"`
+	// Action is the change made, i.e. approve, reject, cancel, or revoke
+	Action string `json:"action"`
+	// ApprovalName is the approval step the action is made on, if any
+	ApprovalName string    `json:"approval_name,omitempty"`
+	Actor        string    `json:"actor"`
+	Time         time.Time `json:"time"`
+}
+
+// OutboxMessage holds the status event and the notifications of a change, stored in the same transaction as
+// the change and sent once the transaction is committed. The messages left unsent, e.g. on a crash, are sent later
+// by the dispatch_outbox_messages job
+type OutboxMessage struct {
+	ID            uint
+	StatusEvent   *AppealStatusEvent
+	Notifications []Notification
+	CreatedAt     time.Time
+}
+
+// AccessEntry is an active access of a user, i.e. a role granted on a resource
+type AccessEntry struct {
+	AppealID       uint       `json:"appeal_id"`
+	ProviderType   string     `json:"provider_type"`
+	ProviderURN    string     `json:"provider_urn"`
+	ResourceID     uint       `json:"resource_id"`
+	ResourceType   string     `json:"resource_type"`
+	ResourceURN    string     `json:"resource_urn"`
+	ResourceName   string     `json:"resource_name"`
+	Role           string     `json:"role"`
+	ExpirationDate *time.Time `json:"expiration_date,omitempty"`
+	// Permissions are the concrete permissions implied by the role according to the provider config.
+	// They're only set if requested
+	Permissions []interface{} `json:"permissions,omitempty"`
+}
+
+const (
+	// AccessReviewOutcomeActive is the outcome of the access still granted
+	AccessReviewOutcomeActive = "active"
+	// AccessReviewOutcomeExpired is the outcome of the access revoked by the system once it expired
+	AccessReviewOutcomeExpired = "expired"
+	// AccessReviewOutcomeRevoked is the outcome of the access revoked before it expired
+	AccessReviewOutcomeRevoked = "revoked"
+	// AccessReviewOutcomeRevocationPending is the outcome of the access failing to be revoked
+	AccessReviewOutcomeRevocationPending = "revocation_pending"
+)
+
+// AccessReviewFilter selects the access granted within a period for an access review
+type AccessReviewFilter struct {
+	// From and To bound the time the access is granted, from inclusive and to exclusive
+	From time.Time
+	To   time.Time
+	// ProviderTypes, ProviderURNs, and Teams restrict the review to any of the values, if any
+	ProviderTypes []string
+	ProviderURNs  []string
+	Teams         []string
+}
+
+// AccessReviewEntry is an access granted within the period of an access review, along with who approved it and
+// what happened to it since
+type AccessReviewEntry struct {
+	AppealID     uint   `json:"appeal_id"`
+	User         string `json:"user"`
+	ProviderType string `json:"provider_type"`
+	ProviderURN  string `json:"provider_urn"`
+	ResourceType string `json:"resource_type"`
+	ResourceURN  string `json:"resource_urn"`
+	Role         string `json:"role"`
+	Team         string `json:"team,omitempty"`
+	// Approvals are the provenance of the approved steps, in order
+	Approvals []*ApprovalProvenance `json:"approvals"`
+	// GrantedAt is the time of the last approval, or the creation of the appeals approved without any step
+	GrantedAt      time.Time  `json:"granted_at"`
+	ExpirationDate *time.Time `json:"expiration_date,omitempty"`
+	Outcome        string     `json:"outcome"`
+	RevokedBy      string     `json:"revoked_by,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason   string     `json:"revoke_reason,omitempty"`
+}
+
+// GetDuration returns the requested access duration, or zero for the permanent access
+func (e *AccessReviewEntry) GetDuration() time.Duration {
+	if e.ExpirationDate == nil {
+		return 0
+	}
+	return e.ExpirationDate.Sub(e.GrantedAt)
 }
 
 // AppealRepository interface
 type AppealRepository interface {
-	BulkInsert([]*Appeal) error
-	Find(map[string]interface{}) ([]*Appeal, error) // TODO: create ListAppealsFilter as the filter param type
-	GetByID(uint) (*Appeal, error)
-	Update(*Appeal) error
+	BulkInsert(context.Context, []*Appeal) error
+	Find(context.Context, map[string]interface{}) ([]*Appeal, error) // TODO: create ListAppealsFilter as the filter param type
+	GetByID(context.Context, uint) (*Appeal, error)
+	GetActiveAccess(ctx context.Context, user string, resourceID uint, role string, now time.Time) (*Appeal, error)
+	GetAccessSummary(ctx context.Context, user string, now time.Time) ([]AccessEntry, error)
+	// GetCreatedTimes returns the creation times of the appeals the user created since the time, oldest first
+	GetCreatedTimes(ctx context.Context, user string, since time.Time) ([]time.Time, error)
+	// GetHistory summarizes the user's previous appeals against the role of a new appeal
+	GetHistory(ctx context.Context, user, role string) (*AppealHistory, error)
+	// IsPolicyVersionUsed tells whether any appeal, of any organization, ran under the policy version
+	IsPolicyVersionUsed(ctx context.Context, policyID string, policyVersion uint) (bool, error)
+	Update(context.Context, *Appeal) error
+	// AddApproval inserts an approval step, along with its approvers, to an existing appeal
+	AddApproval(context.Context, *Approval) error
+	AddAttachment(context.Context, *Attachment) error
+	GetAttachments(ctx context.Context, appealID uint) ([]*Attachment, error)
+	GetAttachmentByID(context.Context, uint) (*Attachment, error)
+	AddComment(context.Context, *AppealComment) error
+	GetComments(ctx context.Context, appealID uint) ([]*AppealComment, error)
+	// LockGrants serializes the changes of the active grants of the role on the resource until the end of the
+	// transaction, it's meant to be called within WithTransaction
+	LockGrants(ctx context.Context, resourceID uint, role string) error
+	// AddOutboxMessage stores the message of a change, it's meant to be called within WithTransaction
+	AddOutboxMessage(context.Context, *OutboxMessage) error
+	// GetOutboxMessages returns the oldest messages created before the time, up to the limit. The messages are
+	// locked until the end of the transaction and skipped by the concurrent calls
+	GetOutboxMessages(ctx context.Context, createdBefore time.Time, limit int) ([]*OutboxMessage, error)
+	DeleteOutboxMessages(ctx context.Context, ids []uint) error
+	// WithTransaction runs fn with a repository bound to a single transaction, committed if fn returns nil
+	// and rolled back otherwise. The calls to the providers can't be rolled back, they're made outside of fn
+	// and compensated if the transaction fails
+	WithTransaction(ctx context.Context, fn func(AppealRepository) error) error
 }
 
 // AppealService interface
 type AppealService interface {
-	Create([]*Appeal) error
-	Find(map[string]interface{}) ([]*Appeal, error)
-	GetByID(uint) (*Appeal, error)
-	MakeAction(ApprovalAction) (*Appeal, error)
-	Cancel(uint) (*Appeal, error)
-	Revoke(id uint, actor, reason string) (*Appeal, error)
+	Create(context.Context, []*Appeal) error
+	Find(context.Context, map[string]interface{}) ([]*Appeal, error)
+	GetByID(context.Context, uint) (*Appeal, error)
+	HasActiveAccess(ctx context.Context, user string, resourceID uint, role string) (bool, *Appeal, error)
+	GetUserAccessSummary(ctx context.Context, user string, withPermissions bool) ([]AccessEntry, error)
+	MakeAction(context.Context, ApprovalAction) (*Appeal, error)
+	MakeActionByFilter(ctx context.Context, filters map[string]interface{}, approvalAction ApprovalAction) ([]*Appeal, error)
+	MakeGroupAction(ctx context.Context, groupID string, approvalAction ApprovalAction) (*GroupActionResult, error)
+	AdminApprove(ctx context.Context, appealID uint, approvalName, adminActor, reason string) (*Appeal, error)
+	Clone(ctx context.Context, id uint, user string) (*Appeal, error)
+	Resubmit(ctx context.Context, id uint, user, comment string) (*Appeal, error)
+	RevertApproval(ctx context.Context, appealID uint, approvalName, actor string) (*Appeal, error)
+	AcknowledgeApproval(ctx context.Context, appealID uint, approvalName, actor string) (*Appeal, error)
+	MuteReminders(ctx context.Context, appealID uint, actor, reason string) (*Appeal, error)
+	UnmuteReminders(ctx context.Context, appealID uint, actor string) (*Appeal, error)
+	UndoApproval(ctx context.Context, appealID uint, actor string) (*Appeal, error)
+	GetApprovalProvenance(ctx context.Context, appealID uint) ([]*ApprovalProvenance, error)
+	Cancel(ctx context.Context, id uint, actor string) (*Appeal, error)
+	Revoke(ctx context.Context, id uint, actor, reason string) (*Appeal, error)
+	RevokeByFilter(ctx context.Context, filters map[string]interface{}, actor, reason string) ([]*Appeal, error)
+	PreviewRevoke(ctx context.Context, filters map[string]interface{}) ([]*Appeal, error)
+	GetPendingForApprover(ctx context.Context, approver string) ([]*Appeal, error)
+	GetSLAReport(ctx context.Context, filters map[string]interface{}) (*SLAReport, error)
+	GetAccessReview(ctx context.Context, filter AccessReviewFilter) ([]*AccessReviewEntry, error)
+	FindDeadlockedAppeals(context.Context) ([]*Appeal, error)
+	ReassignDeadlockedAppeal(ctx context.Context, id uint, approvers []string) (*Appeal, error)
+	AddApprovalStep(ctx context.Context, appealID uint, step Approval, position int, actor string) (*Appeal, error)
+	RemindPendingApprovers(ctx context.Context, idleFor time.Duration) error
+	CancelAbandonedAppeals(context.Context) error
+	ReassignIdleApprovals(context.Context) error
+	ResolvePendingApprovers(context.Context) error
+	SendPendingApprovalsDigest(context.Context) error
+	RetryPendingRevocations(ctx context.Context, force bool) error
+	DispatchOutboxMessages(context.Context) error
+	AddAttachment(ctx context.Context, appealID uint, actor, filename string, content io.Reader) (*Attachment, error)
+	ListAttachments(ctx context.Context, appealID uint, viewer string) ([]*Attachment, error)
+	GetAttachmentContent(ctx context.Context, id uint, viewer string) (*Attachment, io.ReadCloser, error)
+	ToggleAccessWindows(context.Context) error
+	ActivateScheduledAccess(context.Context) error
+	AddComment(ctx context.Context, appealID uint, actor, body, visibility string) (*AppealComment, error)
+	GetComments(ctx context.Context, appealID uint, viewer string) ([]*AppealComment, error)
 }
@@ -1,12 +1,18 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 const (
 	ApprovalStatusPending  = "pending"
 	ApprovalStatusSkipped  = "skipped"
 	ApprovalStatusApproved = "approved"
 	ApprovalStatusRejected = "rejected"
+	// ApprovalStatusPendingApproverResolution is the status of a step which approvers couldn't be resolved from
+	// the IAM yet. The step and the steps after it wait until the worker resolves them
+	ApprovalStatusPendingApproverResolution = "pending_approver_resolution"
 )
 
 type Approval struct {
@@ -18,6 +24,46 @@ type Approval struct {
 	Actor         *string `json:"actor"`
 	PolicyID      string  `json:"policy_id"`
 	PolicyVersion uint    `json:"policy_version"`
+	Reason        string  `json:"reason,omitempty"`
+	IsOverridden  bool    `json:"is_overridden"`
+	// AddedBy is the user who added the step to the appeal manually, it's empty for the policy steps
+	AddedBy string `json:"added_by,omitempty"`
+
+	LastRemindedAt *time.Time `json:"last_reminded_at,omitempty"`
+	// ReminderLevel is the number of reminders sent to the approvers, i.e. the rung of the reminder
+	// escalation ladder reached. It's reset once the step is actioned
+	ReminderLevel int `json:"reminder_level,omitempty"`
+
+	Attestation         *ApprovalAttestation `json:"attestation,omitempty"`
+	NotificationChannel string               `json:"notification_channel,omitempty"`
+
+	// SLA is the target duration to act on the step, copied from the policy step
+	SLA string `json:"sla,omitempty"`
+	// SLAMet tells whether the step was actioned within its SLA. It's nil until the step is actioned
+	SLAMet *bool `json:"sla_met,omitempty"`
+
+	// AssignmentStrategy and ReassignAfter are copied from the policy step
+	AssignmentStrategy string `json:"assignment_strategy,omitempty"`
+	ReassignAfter      string `json:"reassign_after,omitempty"`
+	// RequireReason is copied from the policy step
+	RequireReason string `json:"require_reason,omitempty"`
+	// DelegationChain is copied from the policy step
+	DelegationChain *DelegationChain `json:"delegation_chain,omitempty"`
+	// OnBehalfOf is the approver the actor acted for, as their delegate or a member of their delegation chain
+	OnBehalfOf string `json:"on_behalf_of,omitempty"`
+	// Assignee is the single approver notified about the step once it's awaiting approval, picked by
+	// the assignment strategy. The other approvers are still allowed to act on the step
+	Assignee   string     `json:"assignee,omitempty"`
+	AssignedAt *time.Time `json:"assigned_at,omitempty"`
+	// AcknowledgedBy is the approver who signaled they're reviewing the step, before acting on it
+	AcknowledgedBy string     `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	// StatusChangedAt is the last time the step was actioned, skipped, or reopened. Unlike UpdatedAt, it isn't
+	// bumped by the unrelated updates of the appeal
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	// ChangesRequestedAt is set while the step waits for the requester to resubmit the appeal after the changes
+	// requested by the approver
+	ChangesRequestedAt *time.Time `json:"changes_requested_at,omitempty"`
 
 	Approvers []string `json:"approvers,omitempty"`
 	Appeal    *Appeal  `json:"appeal,omitempty"`
@@ -26,22 +72,196 @@ type Approval struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// ApprovalAttestation is the evidence of what the actor saw when making an action on an approval step.
+// Signature covers the appeal request along with the actor and the action
+type ApprovalAttestation struct {
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Signature string    `json:"signature"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 func (a *Approval) IsManualApproval() bool {
 	return len(a.Approvers) > 0
 }
 
+// IsAssignable returns true if the step is assigned to a single approver rather than notifying all of them
+func (a *Approval) IsAssignable() bool {
+	return len(a.Approvers) > 1 &&
+		(a.AssignmentStrategy == AssignmentStrategyRoundRobin || a.AssignmentStrategy == AssignmentStrategyLeastLoaded)
+}
+
+// IsReasonRequired returns true if the action on the step has to be justified with a reason
+func (a *Approval) IsReasonRequired(action string) bool {
+	switch a.RequireReason {
+	case RequireReasonBoth:
+		return true
+	case RequireReasonApprove:
+		return action == AppealActionNameApprove
+	case RequireReasonReject:
+		return action == AppealActionNameReject
+	}
+	return false
+}
+
+// GetNotifiedApprovers returns the approvers to be notified about the step, i.e. the assignee if any
+func (a *Approval) GetNotifiedApprovers() []string {
+	if a.Assignee != "" {
+		return []string{a.Assignee}
+	}
+	return a.Approvers
+}
+
+// ApproverLoad is the review load of an approver across the pending appeals
+type ApproverLoad struct {
+	Approver string `json:"approver"`
+	// PendingCount is the number of approval steps currently assigned to the approver and awaiting approval
+	PendingCount   int        `json:"pending_count"`
+	LastAssignedAt *time.Time `json:"last_assigned_at,omitempty"`
+}
+
+const (
+	// ApprovalActedAsApprover is set on the steps actioned by one of their approvers
+	ApprovalActedAsApprover = "approver"
+	// ApprovalActedAsDelegate is set on the steps actioned by the delegate or the delegation chain of an approver
+	ApprovalActedAsDelegate = "delegate"
+	// ApprovalActedAsAdminOverride is set on the steps approved by an admin on behalf of the approvers
+	ApprovalActedAsAdminOverride = "admin_override"
+	// ApprovalActedAsSystem is set on the steps resolved without any actor, e.g. by the step conditions
+	ApprovalActedAsSystem = "system"
+)
+
+// ApprovalProvenance tells who was asked to act on an approval step and who effectively did
+type ApprovalProvenance struct {
+	ApprovalName      string     `json:"approval_name"`
+	Status            string     `json:"status"`
+	NominalApprovers  []string   `json:"nominal_approvers"`
+	EffectiveApprover string     `json:"effective_approver,omitempty"`
+	ActedAs           string     `json:"acted_as,omitempty"`
+	OnBehalfOf        string     `json:"on_behalf_of,omitempty"`
+	Reason            string     `json:"reason,omitempty"`
+	ActedAt           *time.Time `json:"acted_at,omitempty"`
+	IsAttested        bool       `json:"is_attested"`
+	AddedBy           string     `json:"added_by,omitempty"`
+}
+
+// GetProvenance returns the provenance of the approval step
+func (a *Approval) GetProvenance() *ApprovalProvenance {
+	p := &ApprovalProvenance{
+		ApprovalName:     a.Name,
+		Status:           a.Status,
+		NominalApprovers: a.Approvers,
+		Reason:           a.Reason,
+		IsAttested:       a.Attestation != nil,
+		AddedBy:          a.AddedBy,
+	}
+	if a.Status == ApprovalStatusPending {
+		return p
+	}
+
+	// the steps acted on before the status change time was recorded fall back to their last update
+	actedAt := a.UpdatedAt
+	if a.StatusChangedAt != nil {
+		actedAt = *a.StatusChangedAt
+	}
+	p.ActedAt = &actedAt
+	if a.Actor == nil {
+		p.ActedAs = ApprovalActedAsSystem
+		return p
+	}
+
+	p.EffectiveApprover = *a.Actor
+	if a.IsOverridden {
+		p.ActedAs = ApprovalActedAsAdminOverride
+	} else if a.OnBehalfOf != "" {
+		p.ActedAs = ApprovalActedAsDelegate
+		p.OnBehalfOf = a.OnBehalfOf
+	} else {
+		p.ActedAs = ApprovalActedAsApprover
+	}
+	return p
+}
+
+// SLAStats counts the approval steps actioned within and after their SLA
+type SLAStats struct {
+	OnTime   int `json:"on_time"`
+	Breached int `json:"breached"`
+}
+
+func (s *SLAStats) add(met bool) {
+	if met {
+		s.OnTime++
+	} else {
+		s.Breached++
+	}
+}
+
+// SLAReport summarizes the SLA compliance of the actioned approval steps
+type SLAReport struct {
+	SLAStats
+	ByApprover map[string]*SLAStats `json:"by_approver"`
+	ByTeam     map[string]*SLAStats `json:"by_team"`
+}
+
+// NewSLAReport returns an empty SLA report
+func NewSLAReport() *SLAReport {
+	return &SLAReport{
+		ByApprover: map[string]*SLAStats{},
+		ByTeam:     map[string]*SLAStats{},
+	}
+}
+
+// Add counts the approval step for the approver who actioned it and the team, if any
+func (r *SLAReport) Add(approver, team string, met bool) {
+	r.SLAStats.add(met)
+	if r.ByApprover[approver] == nil {
+		r.ByApprover[approver] = &SLAStats{}
+	}
+	r.ByApprover[approver].add(met)
+	if team != "" {
+		if r.ByTeam[team] == nil {
+			r.ByTeam[team] = &SLAStats{}
+		}
+		r.ByTeam[team].add(met)
+	}
+}
+
+// ApproverStats summarizes the actions of an approver and their current backlog
+type ApproverStats struct {
+	Approver      string `json:"approver"`
+	ApprovedCount int    `json:"approved_count"`
+	RejectedCount int    `json:"rejected_count"`
+	// AverageResponseTime is the average duration between a step awaiting the approver and their action on it
+	AverageResponseTime time.Duration `json:"average_response_time"`
+	// Backlog is the number of pending appeals which pending approval steps list the approver. It
+	// isn't restricted by the date range
+	Backlog int `json:"backlog"`
+}
+
+// ApproverStatsFilter restricts the approver stats to the approval steps actioned within the date range
+type ApproverStatsFilter struct {
+	From *time.Time `mapstructure:"from"`
+	To   *time.Time `mapstructure:"to"`
+}
+
 type ListApprovalsFilter struct {
 	User     string   `mapstructure:"user" validate:"omitempty,required"`
 	Statuses []string `mapstructure:"statuses" validate:"omitempty,min=1"`
 }
 
 type ApprovalRepository interface {
-	BulkInsert([]*Approval) error
-	ListApprovals(*ListApprovalsFilter) ([]*Approval, error)
+	BulkInsert(context.Context, []*Approval) error
+	ListApprovals(context.Context, *ListApprovalsFilter) ([]*Approval, error)
+	AddApprover(context.Context, *Approver) error
+	GetApproverLoads(ctx context.Context, approvers []string) (map[string]*ApproverLoad, error)
+	GetApproverStats(ctx context.Context, approver string, filter *ApproverStatsFilter) (*ApproverStats, error)
 }
 
 type ApprovalService interface {
-	BulkInsert([]*Approval) error
-	ListApprovals(*ListApprovalsFilter) ([]*Approval, error)
-	AdvanceApproval(appeal *Appeal) error
+	BulkInsert(context.Context, []*Approval) error
+	ListApprovals(context.Context, *ListApprovalsFilter) ([]*Approval, error)
+	AdvanceApproval(ctx context.Context, appeal *Appeal) error
+	AddApprover(context.Context, *Approver) error
+	GetApproverLoads(ctx context.Context, approvers []string) (map[string]*ApproverLoad, error)
+	GetApproverStats(ctx context.Context, approver string, filter *ApproverStatsFilter) (*ApproverStats, error)
 }
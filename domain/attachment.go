@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Attachment is the metadata of a supporting document attached to an appeal
+type Attachment struct {
+	ID          uint      `json:"id" yaml:"id"`
+	AppealID    uint      `json:"appeal_id" yaml:"appeal_id"`
+	Filename    string    `json:"filename" yaml:"filename"`
+	ContentType string    `json:"content_type" yaml:"content_type"`
+	Size        int64     `json:"size" yaml:"size"`
+	StorageKey  string    `json:"storage_key" yaml:"storage_key"`
+	CreatedAt   time.Time `json:"created_at" yaml:"created_at"`
+}
+
+// BlobStorage is the backend where the attachment contents are stored
+type BlobStorage interface {
+	Put(ctx context.Context, key string, content io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// AttachmentScanner inspects the attachment content before it is stored, e.g. a virus scanner.
+// A non-nil error rejects the attachment
+type AttachmentScanner interface {
+	Scan(ctx context.Context, a *Attachment, content io.Reader) error
+}
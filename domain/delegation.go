@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ApprovalDelegation lets the delegate act on the approval steps of the delegator from the start until the end
+// time, e.g. while the delegator is on leave
+type ApprovalDelegation struct {
+	ID        uint      `json:"id" yaml:"id"`
+	Delegator string    `json:"delegator" yaml:"delegator" validate:"required,email"`
+	Delegate  string    `json:"delegate" yaml:"delegate" validate:"required,email,nefield=Delegator"`
+	StartTime time.Time `json:"start_time" yaml:"start_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" yaml:"end_time" validate:"required"`
+	// OrganizationID is the organization the delegation is created in, the delegate only acts for the delegator on
+	// the appeals of that organization
+	OrganizationID string `json:"organization_id,omitempty" yaml:"organization_id,omitempty"`
+	// StartNotified is set once the delegator and the delegate are notified that the delegation started
+	StartNotified bool      `json:"-" yaml:"-"`
+	CreatedAt     time.Time `json:"created_at" yaml:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" yaml:"updated_at"`
+}
+
+// IsActive returns true if the delegation has started and hasn't ended at the given time
+func (d *ApprovalDelegation) IsActive(now time.Time) bool {
+	return !now.Before(d.StartTime) && now.Before(d.EndTime)
+}
+
+// ApprovalDelegationRepository stores the approval delegations
+type ApprovalDelegationRepository interface {
+	Create(context.Context, *ApprovalDelegation) error
+	// GetByUser returns the delegations the user is either the delegator or the delegate of
+	GetByUser(ctx context.Context, user string) ([]*ApprovalDelegation, error)
+	// GetActiveByDelegate returns the delegations to the delegate active at the given time
+	GetActiveByDelegate(ctx context.Context, delegate string, now time.Time) ([]*ApprovalDelegation, error)
+	// GetStartedUnnotified returns the active delegations which start isn't notified yet
+	GetStartedUnnotified(ctx context.Context, now time.Time) ([]*ApprovalDelegation, error)
+	SetStartNotified(ctx context.Context, ids []uint) error
+	// GetExpired returns the delegations ended at or before the given time
+	GetExpired(ctx context.Context, now time.Time) ([]*ApprovalDelegation, error)
+	Delete(ctx context.Context, ids []uint) error
+}
+
+// ApprovalDelegationService manages the approval delegations
+type ApprovalDelegationService interface {
+	Create(context.Context, *ApprovalDelegation) error
+	GetByUser(ctx context.Context, user string) ([]*ApprovalDelegation, error)
+	// GetActiveDelegators returns the delegators the delegate can currently act for
+	GetActiveDelegators(ctx context.Context, delegate string) ([]string, error)
+	NotifyStartedDelegations(context.Context) error
+	DeleteExpiredDelegations(context.Context) error
+}
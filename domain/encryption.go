@@ -15,3 +15,9 @@ type Crypto interface {
 	Encryptor
 	Decryptor
 }
+
+// Signer does sign a plain text and verify the signature using a server-held key
+type Signer interface {
+	Sign(string) (string, error)
+	Verify(value, signature string) (bool, error)
+}
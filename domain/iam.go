@@ -5,7 +5,32 @@ type IAMClient interface {
 	GetManagerEmails(user string) ([]string, error)
 }
 
+// IAMAvailabilityChecker is implemented by the IAM clients able to tell whether a user is currently
+// available, e.g. not out-of-office
+type IAMAvailabilityChecker interface {
+	IsUserAvailable(user string) (bool, error)
+}
+
+// IAMTeamResolver is implemented by the IAM clients able to tell which team a user belongs to
+type IAMTeamResolver interface {
+	GetUserTeam(user string) (string, error)
+}
+
+// IAMManagerChainResolver is implemented by the IAM clients able to resolve the managers above a user
+type IAMManagerChainResolver interface {
+	GetManagerChain(user string) ([]string, error)
+}
+
+// IAMGroupResolver is implemented by the IAM clients able to tell which groups a user belongs to
+type IAMGroupResolver interface {
+	GetUserGroups(user string) ([]string, error)
+}
+
 // IAMService interface
 type IAMService interface {
 	GetUserApproverEmails(user string) ([]string, error)
+	IsUserAvailable(user string) (bool, error)
+	GetUserTeam(user string) (string, error)
+	GetManagerChain(user string) ([]string, error)
+	GetUserGroups(user string) ([]string, error)
 }
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// Metrics records the counters and timings of the appeal lifecycle to a metrics backend. The tags
+// are dimensions of the metric, e.g. the provider type
+type Metrics interface {
+	Count(name string, value int64, tags map[string]string)
+	Timing(name string, d time.Duration, tags map[string]string)
+}
@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"context"
+	"time"
+)
+
 type Notifier interface {
 	Notify([]Notification) error
 }
@@ -7,4 +12,29 @@ type Notifier interface {
 type Notification struct {
 	User    string
 	Message string
+	// Channel is the hint of which channel the notification should be sent through,
+	// the notifier uses its default channel if it's empty
+	Channel string
+	// Critical notifications are sent right away, even during the quiet hours of the user
+	Critical bool
+	// Labels are the labels of the appeal the notification is about, the channel is chosen by the label routing
+	// rules if the notification isn't hinted to any channel
+	Labels map[string]string
+}
+
+// DeferredNotification is a notification held back during the quiet hours of its user
+type DeferredNotification struct {
+	ID           uint
+	Notification Notification
+	// DeliverAt is the time the quiet hours of the user end
+	DeliverAt time.Time
+	CreatedAt time.Time
+}
+
+// DeferredNotificationRepository stores the notifications until their quiet hours end
+type DeferredNotificationRepository interface {
+	BulkInsert(context.Context, []*DeferredNotification) error
+	// GetDue returns the notifications to be delivered at or before the given time, the earliest first
+	GetDue(context.Context, time.Time) ([]*DeferredNotification, error)
+	Delete(ctx context.Context, ids []uint) error
 }
@@ -1,14 +1,49 @@
 package domain
 
 import (
+	"context"
 	"time"
 )
 
 const (
 	ApproversKeyResource      = "$resource"
 	ApproversKeyUserApprovers = "$user_approvers"
+
+	// DelegationKeyManagerChain is the delegation chain member expanding to the managers above the approver
+	DelegationKeyManagerChain = "$manager_chain"
+
+	// AssignmentStrategyAll notifies every approver of the step
+	AssignmentStrategyAll = "all"
+	// AssignmentStrategyRoundRobin assigns the step to the approver assigned the least recently
+	AssignmentStrategyRoundRobin = "round_robin"
+	// AssignmentStrategyLeastLoaded assigns the step to the approver with the fewest pending assignments
+	AssignmentStrategyLeastLoaded = "least_loaded"
+
+	// RequireReasonApprove requires a reason to approve the step
+	RequireReasonApprove = "approve"
+	// RequireReasonReject requires a reason to reject the step
+	RequireReasonReject = "reject"
+	// RequireReasonBoth requires a reason for either action on the step
+	RequireReasonBoth = "both"
+	// RequireReasonNone doesn't require any reason
+	RequireReasonNone = "none"
 )
 
+// AssignmentStrategies are the allowed assignment strategies of the approval steps
+var AssignmentStrategies = []string{
+	AssignmentStrategyAll,
+	AssignmentStrategyRoundRobin,
+	AssignmentStrategyLeastLoaded,
+}
+
+// RequireReasonModes are the allowed modes of the reason requirement of the approval steps
+var RequireReasonModes = []string{
+	RequireReasonApprove,
+	RequireReasonReject,
+	RequireReasonBoth,
+	RequireReasonNone,
+}
+
 // MatchCondition is for determining the requirement of the condition
 type MatchCondition struct {
 	Eq interface{} `json:"eq" yaml:"eq"`
@@ -29,6 +64,95 @@ type Step struct {
 
 	Dependencies []string `json:"dependencies" yaml:"dependencies"`
 	Approvers    string   `json:"approvers" yaml:"approvers" validate:"required_without=Conditions"`
+
+	// NotificationChannel is the channel used to notify the approvers of this step. The default
+	// channel is used if it's empty
+	NotificationChannel string `json:"notification_channel,omitempty" yaml:"notification_channel,omitempty"`
+
+	// MinDuration makes the step only required for the appeals requesting access at least as long as
+	// the duration, e.g. "24h". The step is skipped for shorter access. Permanent access requires the step
+	MinDuration string `json:"min_duration,omitempty" yaml:"min_duration,omitempty"`
+
+	// SLA is the target duration to act on the step once it's awaiting approval, e.g. "24h"
+	SLA string `json:"sla,omitempty" yaml:"sla,omitempty"`
+
+	// CheckApproverAvailability skips the approvers who are currently unavailable, e.g. out-of-office,
+	// according to the IAM. The fallback approvers are assigned if none of the approvers is available,
+	// the approvers are kept as is if there's no fallback approver
+	CheckApproverAvailability bool     `json:"check_approver_availability,omitempty" yaml:"check_approver_availability,omitempty"`
+	FallbackApprovers         []string `json:"fallback_approvers,omitempty" yaml:"fallback_approvers,omitempty" validate:"omitempty,dive,email"`
+
+	// AssignmentStrategy assigns the step to a single approver instead of notifying all of them, either
+	// round_robin or least_loaded. Every approver is notified if it's empty or "all"
+	AssignmentStrategy string `json:"assignment_strategy,omitempty" yaml:"assignment_strategy,omitempty"`
+	// ReassignAfter reassigns the step to another approver if the assignee doesn't act within the duration, e.g. "24h"
+	ReassignAfter string `json:"reassign_after,omitempty" yaml:"reassign_after,omitempty"`
+
+	// RequireReason makes the approvers justify their action on the step, either approve, reject, or both.
+	// No reason is required if it's empty or "none"
+	RequireReason string `json:"require_reason,omitempty" yaml:"require_reason,omitempty"`
+
+	// DelegationChain lets the fallback chain of the unavailable approvers act on the step on their behalf
+	DelegationChain *DelegationChain `json:"delegation_chain,omitempty" yaml:"delegation_chain,omitempty"`
+
+	// Check auto-approves the step on appeal creation if the external check passes, e.g. whether the user has
+	// completed a mandatory training. The step goes to its approvers if the check fails
+	Check *StepCheck `json:"check,omitempty" yaml:"check,omitempty"`
+}
+
+// StepCheck is the external check of an approval step. The check receives the step name and the appeal as a
+// JSON POST request, it passes on a 2xx response matching the expected value if any
+type StepCheck struct {
+	URL string `json:"url" yaml:"url" validate:"required,url"`
+	// Timeout is the maximum duration of the check, e.g. "5s". Defaults to 10s
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// ResponseField is the path of the JSON response field compared to ExpectedValue, e.g. "training.completed".
+	// Any 2xx response passes the check if it's empty
+	ResponseField string      `json:"response_field,omitempty" yaml:"response_field,omitempty"`
+	ExpectedValue interface{} `json:"expected_value,omitempty" yaml:"expected_value,omitempty"`
+}
+
+// DelegationChain is the standing authority delegated by an unavailable approver, e.g. to their manager then
+// to the department head
+type DelegationChain struct {
+	// Members composes the chain in order. DelegationKeyManagerChain expands to the managers above the
+	// approver according to the IAM, any other member is an email, e.g. the department head
+	Members []string `json:"members" yaml:"members" validate:"required,min=1"`
+	// Depth caps the number of members from the start of the chain allowed to act, the whole chain is
+	// allowed if it's 0
+	Depth int `json:"depth,omitempty" yaml:"depth,omitempty" validate:"min=0"`
+}
+
+// ReminderRung is a rung of the escalation ladder of the reminders sent to the idle approvers
+type ReminderRung struct {
+	// Channel is the notification channel of the reminder. The approval step channel is used if it's empty
+	Channel string `json:"channel,omitempty" yaml:"channel,omitempty" mapstructure:"channel"`
+	// Recipients are reminded along with the approvers, e.g. the on-call
+	Recipients []string `json:"recipients,omitempty" yaml:"recipients,omitempty" mapstructure:"recipients" validate:"omitempty,dive,email"`
+}
+
+// AutoCancelConfig cancels the appeals abandoned in pending, i.e. pending for a while without any action
+// from the approvers
+type AutoCancelConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// PendingFor is the minimum age of a pending appeal before it's canceled, e.g. "168h"
+	PendingFor string `json:"pending_for" yaml:"pending_for"`
+}
+
+const (
+	// ApproverResolutionFallbackDefer leaves the steps awaiting the approver resolution, retried by the worker
+	ApproverResolutionFallbackDefer = "defer"
+	// ApproverResolutionFallbackDefaultApprovers assigns the default approvers of the fallback to the steps
+	ApproverResolutionFallbackDefaultApprovers = "default_approvers"
+)
+
+// ApproverResolutionFallback keeps the appeals from being rejected while the user approvers can't be resolved
+// from the IAM, e.g. during an IAM outage
+type ApproverResolutionFallback struct {
+	// Strategy is either defer or default_approvers
+	Strategy string `json:"strategy" yaml:"strategy"`
+	// Approvers are the default approvers of the default_approvers strategy
+	Approvers []string `json:"approvers,omitempty" yaml:"approvers,omitempty"`
 }
 
 // Policy is the approval policy configuration
@@ -38,21 +162,58 @@ type Policy struct {
 	Description string            `json:"description" yaml:"description"`
 	Steps       []*Step           `json:"steps" yaml:"steps" validate:"required"`
 	Labels      map[string]string `json:"labels" yaml:"labels"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
+	// ReminderEscalation overrides the default escalation ladder of the reminders. The n-th reminder of an
+	// idle approval step uses the n-th rung, the last rung is repeated once the ladder is exhausted
+	ReminderEscalation []*ReminderRung `json:"reminder_escalation,omitempty" yaml:"reminder_escalation,omitempty" validate:"omitempty,dive"`
+	// AutoCancel cancels the appeals under the policy once they're abandoned in pending
+	AutoCancel *AutoCancelConfig `json:"auto_cancel,omitempty" yaml:"auto_cancel,omitempty"`
+	// ApprovalValidity is how long the approvals of the steps stay valid while the appeal is pending, e.g. "720h".
+	// The stale approvals are reopened for their approvers to approve again once a later step is approved. The
+	// approvals don't expire if it's empty
+	ApprovalValidity string `json:"approval_validity,omitempty" yaml:"approval_validity,omitempty"`
+	// SensitivityTiers are the additional approval steps required for the roles of each sensitivity
+	SensitivityTiers []*SensitivityTier `json:"sensitivity_tiers,omitempty" yaml:"sensitivity_tiers,omitempty"`
+	// ApproverResolutionFallback applies to the $user_approvers steps once the IAM fails to resolve the
+	// approvers. The appeals are rejected with the IAM error if it's nil
+	ApproverResolutionFallback *ApproverResolutionFallback `json:"approver_resolution_fallback,omitempty" yaml:"approver_resolution_fallback,omitempty"`
+	// SeparationOfDuties requires the steps of an appeal to be approved by different people, including the
+	// approvers the delegates act on behalf of and the admins overriding the steps
+	SeparationOfDuties bool `json:"separation_of_duties,omitempty" yaml:"separation_of_duties,omitempty"`
+	// OrganizationID is the organization owning the policy, only the resources of the same organization can use it
+	OrganizationID string    `json:"organization_id,omitempty" yaml:"organization_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// SensitivityTier is the approval steps appended to the policy steps for the roles of the sensitivity
+type SensitivityTier struct {
+	// Sensitivity matches the sensitivity of the requested role, e.g. "high"
+	Sensitivity string `json:"sensitivity" yaml:"sensitivity"`
+	// Steps are resolved after the policy steps, in order. They require the approvers
+	Steps []*Step `json:"steps" yaml:"steps"`
+}
+
+// GetSensitivityTier returns the tier of the sensitivity, or nil if it's not configured
+func (p *Policy) GetSensitivityTier(sensitivity string) *SensitivityTier {
+	for _, t := range p.SensitivityTiers {
+		if t.Sensitivity == sensitivity {
+			return t
+		}
+	}
+	return nil
 }
 
 // PolicyRepository interface
 type PolicyRepository interface {
-	Create(*Policy) error
-	Find() ([]*Policy, error)
-	GetOne(id string, version uint) (*Policy, error)
+	Create(context.Context, *Policy) error
+	Find(context.Context) ([]*Policy, error)
+	GetOne(ctx context.Context, id string, version uint) (*Policy, error)
 }
 
 // PolicyService interface
 type PolicyService interface {
-	Create(*Policy) error
-	Find() ([]*Policy, error)
-	GetOne(id string, version uint) (*Policy, error)
-	Update(*Policy) error
+	Create(context.Context, *Policy) error
+	Find(context.Context) ([]*Policy, error)
+	GetOne(ctx context.Context, id string, version uint) (*Policy, error)
+	Update(context.Context, *Policy) error
 }
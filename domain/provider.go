@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 )
 
@@ -13,14 +14,32 @@ const (
 	ProviderTypeGrafana = "grafana"
 	// ProviderTypeTableau is the type name for Tableau provider
 	ProviderTypeTableau = "tableau"
+
+	// TeamQuotaExceededReject rejects the appeals exceeding the team quota once they're approved
+	TeamQuotaExceededReject = "reject"
 )
 
 // RoleConfig is the configuration to define a role and mapping the permissions in the provider
 type RoleConfig struct {
-	ID          string        `json:"id" yaml:"id" validate:"required"`
-	Name        string        `json:"name" yaml:"name" validate:"required"`
-	Description string        `json:"description,omitempty" yaml:"description"`
-	Permissions []interface{} `json:"permissions" yaml:"permissions" validate:"required"`
+	ID          string `json:"id" yaml:"id" validate:"required"`
+	Name        string `json:"name" yaml:"name" validate:"required"`
+	Description string `json:"description,omitempty" yaml:"description"`
+	// Permissions are the provider-specific permissions the role grants. The role is a logical role if it's
+	// empty, its permissions are resolved from the role mappings of the service config
+	Permissions []interface{} `json:"permissions" yaml:"permissions"`
+	// MaxDuration caps the access duration that can be requested for the role, e.g. "4h". It takes
+	// precedence over the max duration of the provider appeal config
+	MaxDuration string `json:"max_duration,omitempty" yaml:"max_duration,omitempty"`
+	// AllowedGroups restricts the appeals for the role to the members of any of the groups according to the
+	// IAM. Anyone can appeal for the role if it's empty
+	AllowedGroups []string `json:"allowed_groups,omitempty" yaml:"allowed_groups,omitempty"`
+	// Sensitivity is the sensitivity tier of the role, e.g. "high". The appeals for the role require the
+	// additional approval steps of the tier configured in the policy
+	Sensitivity string `json:"sensitivity,omitempty" yaml:"sensitivity,omitempty"`
+	// ResourceRequirements are the conditions on the resource attributes the resource has to match for the role
+	// to be appealed for, e.g. $resource.details.read_only matching false for a write role. The resources
+	// missing the attribute don't match
+	ResourceRequirements []*Condition `json:"resource_requirements,omitempty" yaml:"resource_requirements,omitempty" validate:"omitempty,dive"`
 }
 
 // PolicyConfig is the configuration that defines which policy is being used in the provider
@@ -34,12 +53,58 @@ type ResourceConfig struct {
 	Type   string        `json:"type" yaml:"type" validate:"required"`
 	Policy *PolicyConfig `json:"policy" yaml:"policy"`
 	Roles  []*RoleConfig `json:"roles" yaml:"roles" validate:"required"`
+	// Aliases are the alternative names the resource type can be referred to by
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// ResourcePolicies overrides Policy for specific resources of this type
+	ResourcePolicies []*ResourcePolicyConfig `json:"resource_policies,omitempty" yaml:"resource_policies,omitempty" validate:"omitempty,dive"`
+	// TagPolicies overrides Policy for the resources having all of the tags. The first matching one is used
+	TagPolicies []*TagPolicyConfig `json:"tag_policies,omitempty" yaml:"tag_policies,omitempty" validate:"omitempty,dive"`
+	// TeamQuotas limit how many members of a team can hold a role on a resource of this type at once
+	TeamQuotas []*TeamQuotaConfig `json:"team_quotas,omitempty" yaml:"team_quotas,omitempty" validate:"omitempty,dive"`
+}
+
+// TeamQuotaConfig limits the active grants of a role held by the members of the same team on a resource
+type TeamQuotaConfig struct {
+	Role string `json:"role" yaml:"role" validate:"required"`
+	// URN restricts the quota to a single resource. The quota applies to every resource of the type if it's empty
+	URN string `json:"urn,omitempty" yaml:"urn,omitempty"`
+	// Limit is the maximum number of active grants a team can hold on the resource
+	Limit int `json:"limit" yaml:"limit" validate:"required,min=1"`
+	// OnExceeded is what happens to the appeals exceeding the quota once they're approved, only reject is
+	// supported. Default: reject
+	OnExceeded string `json:"on_exceeded,omitempty" yaml:"on_exceeded,omitempty" validate:"omitempty,oneof=reject"`
+}
+
+// ResourcePolicyConfig is the policy configuration of a specific resource
+type ResourcePolicyConfig struct {
+	URN    string        `json:"urn" yaml:"urn" validate:"required"`
+	Policy *PolicyConfig `json:"policy" yaml:"policy" validate:"required"`
+}
+
+// TagPolicyConfig is the policy configuration of the resources having all of the tags
+type TagPolicyConfig struct {
+	Tags   map[string]string `json:"tags" yaml:"tags" validate:"required,min=1"`
+	Policy *PolicyConfig     `json:"policy" yaml:"policy" validate:"required"`
+}
+
+// Match checks whether the resource has all of the tags
+func (c *TagPolicyConfig) Match(r *Resource) bool {
+	for k, v := range c.Tags {
+		if tag, ok := r.Tags[k]; !ok || tag != v {
+			return false
+		}
+	}
+	return true
 }
 
 // AppealConfig is the policy configuration of the appeal
 type AppealConfig struct {
 	AllowPermanentAccess         bool   `json:"allow_permanent_access" yaml:"allow_permanent_access"`
 	AllowActiveAccessExtensionIn string `json:"allow_active_access_extension_in" yaml:"allow_active_access_extension_in" validate:"required"`
+	// MaxDuration caps the access duration that can be requested for the roles without their own max duration
+	MaxDuration string `json:"max_duration,omitempty" yaml:"max_duration,omitempty"`
+	// DefaultPolicy is used for the resource types within the provider that don't have any policy configured
+	DefaultPolicy *PolicyConfig `json:"default_policy,omitempty" yaml:"default_policy"`
 }
 
 // ProviderConfig is the configuration for a data provider
@@ -50,43 +115,180 @@ type ProviderConfig struct {
 	Credentials interface{}       `json:"credentials,omitempty" yaml:"credentials" validate:"required"`
 	Appeal      *AppealConfig     `json:"appeal" yaml:"appeal" validate:"required"`
 	Resources   []*ResourceConfig `json:"resources" yaml:"resources" validate:"required"`
+	// AccessVerification polls the verification of the granted access in the eventually consistent providers
+	AccessVerification *AccessVerificationConfig `json:"access_verification,omitempty" yaml:"access_verification,omitempty"`
+	// Profiles override the config per environment, e.g. dev, staging, and prod. Only the profile selected in the
+	// service config takes effect
+	Profiles map[string]*ProviderProfile `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+}
+
+// ProviderProfile overrides the provider config in an environment. The fields left empty keep the values of
+// the provider config
+type ProviderProfile struct {
+	Labels             map[string]string         `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Credentials        interface{}               `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+	Appeal             *AppealConfig             `json:"appeal,omitempty" yaml:"appeal,omitempty"`
+	Resources          []*ResourceConfig         `json:"resources,omitempty" yaml:"resources,omitempty"`
+	AccessVerification *AccessVerificationConfig `json:"access_verification,omitempty" yaml:"access_verification,omitempty"`
+}
+
+// WithProfile returns the config overridden by the profile, without the profiles. It returns false along with
+// the config as is if the profile isn't defined
+func (pc *ProviderConfig) WithProfile(name string) (*ProviderConfig, bool) {
+	result := *pc
+	result.Profiles = nil
+
+	profile := pc.Profiles[name]
+	if profile == nil {
+		return &result, false
+	}
+	if profile.Labels != nil {
+		result.Labels = profile.Labels
+	}
+	if profile.Credentials != nil {
+		result.Credentials = profile.Credentials
+	}
+	if profile.Appeal != nil {
+		result.Appeal = profile.Appeal
+	}
+	if profile.Resources != nil {
+		result.Resources = profile.Resources
+	}
+	if profile.AccessVerification != nil {
+		result.AccessVerification = profile.AccessVerification
+	}
+	return &result, true
+}
+
+const (
+	// AccessVerificationOnTimeoutFail fails the grant if the access isn't confirmed before the timeout
+	AccessVerificationOnTimeoutFail = "fail"
+	// AccessVerificationOnTimeoutActivate trusts the grant if the access isn't confirmed before the timeout
+	AccessVerificationOnTimeoutActivate = "activate"
+)
+
+// AccessVerificationConfig configures the polling of the granted access verification, for the providers
+// granting the access asynchronously
+type AccessVerificationConfig struct {
+	// PollInterval is the wait before the first retry of the verification, doubled on every next retry
+	PollInterval string `json:"poll_interval,omitempty" yaml:"poll_interval,omitempty"`
+	// Timeout is the maximum duration to wait for the access to be confirmed. The access is verified once
+	// if it's empty
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// OnTimeout is either AccessVerificationOnTimeoutFail, the default, or AccessVerificationOnTimeoutActivate
+	OnTimeout string `json:"on_timeout,omitempty" yaml:"on_timeout,omitempty"`
 }
 
 // Provider domain structure
 type Provider struct {
-	ID        uint            `json:"id"`
-	Type      string          `json:"type"`
-	URN       string          `json:"urn"`
-	Config    *ProviderConfig `json:"config"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID     uint            `json:"id"`
+	Type   string          `json:"type"`
+	URN    string          `json:"urn"`
+	Config *ProviderConfig `json:"config"`
+	// OrganizationID is the organization owning the provider, its resources belong to the same organization
+	OrganizationID string    `json:"organization_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ProviderCapabilities describes which access management operations are supported by a provider
+type ProviderCapabilities struct {
+	// BatchGrant is true if the provider can grant multiple access in a single call
+	BatchGrant bool `json:"batch_grant"`
+	// AccessListing is true if the provider can list the existing access of a resource
+	AccessListing bool `json:"access_listing"`
+	// AccessVerification is true if the provider can check whether a granted access took effect, i.e. it
+	// implements AccessVerifier
+	AccessVerification bool `json:"access_verification"`
+	// PermanentAccess is true if the provider can hold an access without expiration date
+	PermanentAccess bool `json:"permanent_access"`
+	// Revocation is true if the provider can revoke a granted access
+	Revocation bool `json:"revocation"`
 }
 
 // ProviderRepository interface
 type ProviderRepository interface {
-	Create(*Provider) error
-	Update(*Provider) error
-	Find() ([]*Provider, error)
-	GetByID(uint) (*Provider, error)
-	GetOne(pType, urn string) (*Provider, error)
-	Delete(uint) error
+	Create(context.Context, *Provider) error
+	Update(context.Context, *Provider) error
+	Find(context.Context) ([]*Provider, error)
+	GetByID(context.Context, uint) (*Provider, error)
+	GetOne(ctx context.Context, pType, urn string) (*Provider, error)
+	Delete(context.Context, uint) error
 }
 
 // ProviderService interface
 type ProviderService interface {
-	Create(*Provider) error
-	Find() ([]*Provider, error)
-	Update(*Provider) error
-	FetchResources() error
-	GrantAccess(*Appeal) error
-	RevokeAccess(*Appeal) error
+	Create(context.Context, *Provider) error
+	Find(context.Context) ([]*Provider, error)
+	Update(context.Context, *Provider) error
+	FetchResources(context.Context) error
+	GrantAccess(context.Context, *Appeal) error
+	RevokeAccess(context.Context, *Appeal) error
+	VerifyAccess(context.Context, *Appeal) (bool, error)
+	GetCapabilities(providerType string) (*ProviderCapabilities, error)
+	ParseURN(providerType, resourceType, urn string) (*ResourceURN, error)
+	FormatURN(providerType string, urn *ResourceURN) (string, error)
+	GetRolePermissions(ctx context.Context, providerType, providerURN, resourceType, role string) ([]interface{}, error)
+	GetTeamQuota(ctx context.Context, providerType, providerURN, resourceType, resourceURN, role string) (*TeamQuotaConfig, error)
 }
 
 // ProviderInterface abstracts guardian communicates with external data providers
 type ProviderInterface interface {
 	GetType() string
-	CreateConfig(*ProviderConfig) error
-	GetResources(pc *ProviderConfig) ([]*Resource, error)
-	GrantAccess(*ProviderConfig, *Appeal) error
-	RevokeAccess(*ProviderConfig, *Appeal) error
+	Capabilities() ProviderCapabilities
+	CreateConfig(context.Context, *ProviderConfig) error
+	GetResources(ctx context.Context, pc *ProviderConfig) ([]*Resource, error)
+	GrantAccess(context.Context, *ProviderConfig, *Appeal) error
+	RevokeAccess(context.Context, *ProviderConfig, *Appeal) error
+}
+
+// ResourcePager is implemented by providers which are able to list the resources incrementally,
+// so large accounts can be synced without holding every resource in memory at once
+type ResourcePager interface {
+	// GetResourcesPaged calls fn for every page of resources until all of them are listed or fn returns an error
+	GetResourcesPaged(ctx context.Context, pc *ProviderConfig, fn func([]*Resource) error) error
+}
+
+// ResourceURN is the structured form of a resource URN
+type ResourceURN struct {
+	Type string `json:"type"`
+	// Parts are the components of the URN from the top of the resource hierarchy, e.g. the project,
+	// dataset, and table of a BigQuery table
+	Parts []string `json:"parts"`
+}
+
+// URNFormatter is implemented by providers which resource URNs follow the resource hierarchy, so the
+// URNs can be consistently constructed and parsed outside of the provider
+type URNFormatter interface {
+	ParseURN(resourceType, urn string) (*ResourceURN, error)
+	FormatURN(urn *ResourceURN) (string, error)
+}
+
+// ConnectionChecker is implemented by providers which are able to check whether the provider is reachable
+// with the provider config, e.g. by signing in with the credentials
+type ConnectionChecker interface {
+	CheckConnection(context.Context, *ProviderConfig) error
+}
+
+// ClientEvicter is implemented by providers caching their clients per provider URN, so the clients built with the
+// outdated credentials are dropped once the provider config is updated
+type ClientEvicter interface {
+	EvictClients(providerURN string)
+}
+
+// ResourceImportReport summarizes the resources of a provider loaded into the resource store at once
+type ResourceImportReport struct {
+	ProviderType string `json:"provider_type"`
+	ProviderURN  string `json:"provider_urn"`
+	// Imported is the number of resources upserted, counted by resource type in ResourceTypes
+	Imported      int            `json:"imported"`
+	ResourceTypes map[string]int `json:"resource_types"`
+	// Batches is the number of upserts the resources are stored with
+	Batches int `json:"batches"`
+}
+
+// AccessVerifier is implemented by providers which are able to check whether an access
+// granted by guardian actually took effect in the provider
+type AccessVerifier interface {
+	VerifyAccess(context.Context, *ProviderConfig, *Appeal) (bool, error)
 }
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Resource struct
 type Resource struct {
@@ -12,21 +15,36 @@ type Resource struct {
 	Name         string                 `json:"name"`
 	Details      map[string]interface{} `json:"details"`
 	Labels       map[string]string      `json:"labels"`
-	CreatedAt    time.Time              `json:"created_at"`
-	UpdatedAt    time.Time              `json:"updated_at"`
+	Tags         map[string]string      `json:"tags"`
+	// DenyAppeals puts the resource on the deny list, the appeals to it are rejected regardless of the policy
+	DenyAppeals       bool   `json:"deny_appeals"`
+	DenyAppealsReason string `json:"deny_appeals_reason,omitempty"`
+	// OrganizationID is the organization of the resource's provider
+	OrganizationID string    `json:"organization_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ResourceTypeKey identifies a resource type of a provider
+type ResourceTypeKey struct {
+	ProviderType string
+	ProviderURN  string
+	Type         string
 }
 
 // ResourceRepository interface
 type ResourceRepository interface {
-	Find(filters map[string]interface{}) ([]*Resource, error)
-	GetOne(uint) (*Resource, error)
-	BulkUpsert([]*Resource) error
-	Update(*Resource) error
+	Find(ctx context.Context, filters map[string]interface{}) ([]*Resource, error)
+	GetOne(context.Context, uint) (*Resource, error)
+	BulkUpsert(context.Context, []*Resource) error
+	Update(context.Context, *Resource) error
+	SetDenyAppeals(ctx context.Context, id uint, deny bool, reason string) error
 }
 
 // ResourceService interface
 type ResourceService interface {
-	Find(filters map[string]interface{}) ([]*Resource, error)
-	BulkUpsert([]*Resource) error
-	Update(*Resource) error
+	Find(ctx context.Context, filters map[string]interface{}) ([]*Resource, error)
+	BulkUpsert(context.Context, []*Resource) error
+	Update(context.Context, *Resource) error
+	SetDenyAppeals(ctx context.Context, id uint, deny bool, reason, actor string) error
 }
@@ -9,6 +9,7 @@ import (
 const (
 	IAMProviderShield = "shield"
 	IAMProviderHTTP   = "http"
+	IAMProviderLDAP   = "ldap"
 )
 
 type ClientConfig struct {
@@ -18,7 +19,12 @@ type ClientConfig struct {
 	Host string `mapstructure:"host"`
 
 	// http config
-	GetManagersURL string `mapstructure:"get_managers_url"`
+	GetManagersURL         string `mapstructure:"get_managers_url"`
+	GetUserAvailabilityURL string `mapstructure:"get_user_availability_url"`
+	GetUserTeamURL         string `mapstructure:"get_user_team_url"`
+
+	// ldap config
+	LDAP LDAPClientConfig `mapstructure:"ldap"`
 }
 
 func NewClient(config *ClientConfig) (domain.IAMClient, error) {
@@ -28,8 +34,12 @@ func NewClient(config *ClientConfig) (domain.IAMClient, error) {
 		})
 	} else if config.Provider == IAMProviderHTTP {
 		return NewHTTPClient(&HTTPClientConfig{
-			GetManagersURL: config.GetManagersURL,
+			GetManagersURL:         config.GetManagersURL,
+			GetUserAvailabilityURL: config.GetUserAvailabilityURL,
+			GetUserTeamURL:         config.GetUserTeamURL,
 		})
+	} else if config.Provider == IAMProviderLDAP {
+		return NewLDAPClient(&config.LDAP)
 	}
 
 	return nil, errors.New("invalid iam provider type")
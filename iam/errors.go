@@ -7,4 +7,18 @@ var (
 	ErrEmptyUserEmailParam = errors.New("user email param is required")
 	// ErrEmptyApprovers is the error value when the returned approver emails are zero/empty
 	ErrEmptyApprovers = errors.New("got zero approver")
+	// ErrAvailabilityNotSupported is the error value when the iam client doesn't provide the user availability
+	ErrAvailabilityNotSupported = errors.New("user availability is not supported by the iam client")
+	// ErrTeamNotSupported is the error value when the iam client doesn't provide the user team
+	ErrTeamNotSupported = errors.New("user team is not supported by the iam client")
+	// ErrEmptyTeam is the error value when the user doesn't belong to any team
+	ErrEmptyTeam = errors.New("user doesn't belong to any team")
+	// ErrManagerChainNotSupported is the error value when the iam client doesn't provide the manager chain
+	ErrManagerChainNotSupported = errors.New("manager chain is not supported by the iam client")
+	// ErrGroupsNotSupported is the error value when the iam client doesn't provide the user groups
+	ErrGroupsNotSupported = errors.New("user groups are not supported by the iam client")
+	// ErrUserNotFound is the error value when the user isn't found in the directory
+	ErrUserNotFound = errors.New("user not found in the directory")
+	// ErrLDAPRequestFailed is the error value when the directory server fails to respond to a request
+	ErrLDAPRequestFailed = errors.New("ldap request failed")
 )
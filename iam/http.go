@@ -2,6 +2,7 @@ package iam
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
@@ -10,6 +11,10 @@ import (
 // HTTPClientConfig is the configuration required by iam.Client
 type HTTPClientConfig struct {
 	GetManagersURL string `validate:"required,url" mapstructure:"get_managers_url"`
+	// GetUserAvailabilityURL is optional, the user availability isn't supported if it's empty
+	GetUserAvailabilityURL string `validate:"omitempty,url" mapstructure:"get_user_availability_url"`
+	// GetUserTeamURL is optional, the user team isn't supported if it's empty
+	GetUserTeamURL string `validate:"omitempty,url" mapstructure:"get_user_team_url"`
 	HTTPClient     *http.Client
 }
 
@@ -17,10 +22,20 @@ type managerEmailsResponse struct {
 	Emails []string `json:"emails"`
 }
 
+type userAvailabilityResponse struct {
+	Available bool `json:"available"`
+}
+
+type userTeamResponse struct {
+	Team string `json:"team"`
+}
+
 // HTTPClient wraps the http client for external approver resolver service
 type HTTPClient struct {
-	getManagersURL string
-	httpClient     *http.Client
+	getManagersURL         string
+	getUserAvailabilityURL string
+	getUserTeamURL         string
+	httpClient             *http.Client
 }
 
 // NewHTTPClient returns *iam.Client
@@ -33,8 +48,10 @@ func NewHTTPClient(config *HTTPClientConfig) (*HTTPClient, error) {
 		httpClient = http.DefaultClient
 	}
 	return &HTTPClient{
-		getManagersURL: config.GetManagersURL,
-		httpClient:     httpClient,
+		getManagersURL:         config.GetManagersURL,
+		getUserAvailabilityURL: config.GetUserAvailabilityURL,
+		getUserTeamURL:         config.GetUserTeamURL,
+		httpClient:             httpClient,
 	}, nil
 }
 
@@ -62,3 +79,69 @@ func (c *HTTPClient) GetManagerEmails(user string) ([]string, error) {
 
 	return approvers.Emails, nil
 }
+
+// IsUserAvailable fetches to external availability service whether the user is currently available
+func (c *HTTPClient) IsUserAvailable(user string) (bool, error) {
+	if c.getUserAvailabilityURL == "" {
+		return false, ErrAvailabilityNotSupported
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.getUserAvailabilityURL, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	q := req.URL.Query()
+	q.Add("user", user)
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("user availability service responded with status %d", res.StatusCode)
+	}
+
+	var availability userAvailabilityResponse
+	if err := json.NewDecoder(res.Body).Decode(&availability); err != nil {
+		return false, err
+	}
+
+	return availability.Available, nil
+}
+
+// GetUserTeam fetches to external team service the team the user belongs to
+func (c *HTTPClient) GetUserTeam(user string) (string, error) {
+	if c.getUserTeamURL == "" {
+		return "", ErrTeamNotSupported
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.getUserTeamURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	q := req.URL.Query()
+	q.Add("user", user)
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("user team service responded with status %d", res.StatusCode)
+	}
+
+	var team userTeamResponse
+	if err := json.NewDecoder(res.Body).Decode(&team); err != nil {
+		return "", err
+	}
+
+	return team.Team, nil
+}
@@ -0,0 +1,407 @@
+package iam
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	defaultLDAPPoolSize            = 4
+	defaultLDAPCacheTTL            = 5 * time.Minute
+	defaultLDAPTimeout             = 10 * time.Second
+	defaultLDAPMaxManagerChainSize = 10
+)
+
+// LDAPClientConfig is the configuration required by iam.LDAPClient
+type LDAPClientConfig struct {
+	// URL is the directory server address, either ldap:// or ldaps://, e.g. ldap://ldap.company.com:389
+	URL          string `validate:"required,url" mapstructure:"url"`
+	BindDN       string `validate:"required" mapstructure:"bind_dn"`
+	BindPassword string `mapstructure:"bind_password"`
+	// BaseDN is where the users are searched, e.g. ou=people,dc=company,dc=com
+	BaseDN     string         `validate:"required" mapstructure:"base_dn"`
+	Attributes LDAPAttributes `mapstructure:"attributes"`
+	TLS        LDAPTLSConfig  `mapstructure:"tls"`
+	// PoolSize is the maximum number of idle connections kept open. Default: 4
+	PoolSize int `mapstructure:"pool_size"`
+	// CacheTTL is how long the directory entries are cached. Default: 5m, a negative value disables the cache
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// Timeout is the timeout of a directory request. Default: 10s
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxManagerChainSize is the maximum number of managers resolved above a user. Default: 10
+	MaxManagerChainSize int `mapstructure:"max_manager_chain_size"`
+}
+
+// LDAPTLSConfig secures the connections to the directory server, either with an ldaps:// url or with StartTLS
+type LDAPTLSConfig struct {
+	// StartTLS upgrades the ldap:// connections to TLS before binding. It can't be used with an ldaps:// url
+	StartTLS bool `mapstructure:"start_tls"`
+	// CACertFile is the PEM file of the CA certificates verifying the server. Default: the system CA certificates
+	CACertFile string `mapstructure:"ca_cert_file"`
+	// ServerName is the name the server certificate is verified against. Default: the url host
+	ServerName string `mapstructure:"server_name"`
+	// InsecureSkipVerify skips verifying the server certificate, it's only meant for testing
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+}
+
+// LDAPAttributes maps the user details to the directory attributes
+type LDAPAttributes struct {
+	// Email identifies the users and holds the approver emails. Default: mail
+	Email string `mapstructure:"email"`
+	// Manager holds the DN of the user's manager. Default: manager
+	Manager string `mapstructure:"manager"`
+	// Group holds the DNs of the user's groups. Default: memberOf
+	Group string `mapstructure:"group"`
+	// Team holds the user's team. Default: department
+	Team string `mapstructure:"team"`
+}
+
+// LDAPClient resolves the users, their managers, and their groups from an LDAP or Active Directory server.
+// The connections are pooled and the directory entries are cached
+type LDAPClient struct {
+	config    LDAPClientConfig
+	tlsConfig *tls.Config
+
+	pool chan ldap.Client
+
+	cacheMu      sync.Mutex
+	cache        map[string]*ldapCacheItem
+	cacheSweepAt time.Time
+
+	// Dial opens an unbound connection to the directory server
+	Dial func(url string, timeout time.Duration) (ldap.Client, error)
+}
+
+type ldapCacheItem struct {
+	entry     *ldap.Entry
+	expiresAt time.Time
+}
+
+// NewLDAPClient returns *iam.LDAPClient
+func NewLDAPClient(config *LDAPClientConfig) (*LDAPClient, error) {
+	if err := validator.New().Struct(config); err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return nil, fmt.Errorf("unsupported ldap url scheme %q", u.Scheme)
+	}
+	if u.Scheme == "ldaps" && config.TLS.StartTLS {
+		return nil, errors.New("start_tls can't be used with an ldaps url")
+	}
+	tlsConfig, err := getLDAPTLSConfig(config.TLS, u.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	c := &LDAPClient{
+		config:    *config,
+		tlsConfig: tlsConfig,
+		cache:     map[string]*ldapCacheItem{},
+	}
+	c.Dial = c.dial
+	if c.config.Attributes.Email == "" {
+		c.config.Attributes.Email = "mail"
+	}
+	if c.config.Attributes.Manager == "" {
+		c.config.Attributes.Manager = "manager"
+	}
+	if c.config.Attributes.Group == "" {
+		c.config.Attributes.Group = "memberOf"
+	}
+	if c.config.Attributes.Team == "" {
+		c.config.Attributes.Team = "department"
+	}
+	if c.config.PoolSize <= 0 {
+		c.config.PoolSize = defaultLDAPPoolSize
+	}
+	if c.config.CacheTTL == 0 {
+		c.config.CacheTTL = defaultLDAPCacheTTL
+	}
+	if c.config.Timeout <= 0 {
+		c.config.Timeout = defaultLDAPTimeout
+	}
+	if c.config.MaxManagerChainSize <= 0 {
+		c.config.MaxManagerChainSize = defaultLDAPMaxManagerChainSize
+	}
+	c.pool = make(chan ldap.Client, c.config.PoolSize)
+
+	return c, nil
+}
+
+// GetManagerEmails returns the email of the user's direct manager
+func (c *LDAPClient) GetManagerEmails(user string) ([]string, error) {
+	entry, err := c.getUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	managerDN := entry.GetEqualFoldAttributeValue(c.config.Attributes.Manager)
+	if managerDN == "" {
+		return nil, nil
+	}
+	manager, err := c.getEntry(managerDN)
+	if err != nil {
+		return nil, err
+	}
+	if email := manager.GetEqualFoldAttributeValue(c.config.Attributes.Email); email != "" {
+		return []string{email}, nil
+	}
+	return nil, nil
+}
+
+// GetManagerChain returns the emails of the managers above the user, starting from the direct manager
+func (c *LDAPClient) GetManagerChain(user string) ([]string, error) {
+	entry, err := c.getUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	var emails []string
+	visited := map[string]bool{strings.ToLower(entry.DN): true}
+	for len(emails) < c.config.MaxManagerChainSize {
+		managerDN := entry.GetEqualFoldAttributeValue(c.config.Attributes.Manager)
+		// a cycle ends the chain, e.g. the head of the organization being their own manager
+		if managerDN == "" || visited[strings.ToLower(managerDN)] {
+			break
+		}
+		visited[strings.ToLower(managerDN)] = true
+
+		if entry, err = c.getEntry(managerDN); err != nil {
+			return nil, err
+		}
+		if email := entry.GetEqualFoldAttributeValue(c.config.Attributes.Email); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails, nil
+}
+
+// GetUserGroups returns the names of the user's groups, i.e. the value of the first RDN of the group DNs
+func (c *LDAPClient) GetUserGroups(user string) ([]string, error) {
+	entry, err := c.getUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []string
+	for _, groupDN := range entry.GetEqualFoldAttributeValues(c.config.Attributes.Group) {
+		dn, err := ldap.ParseDN(groupDN)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid group dn %q: %v", ErrLDAPRequestFailed, groupDN, err)
+		}
+		if len(dn.RDNs) > 0 && len(dn.RDNs[0].Attributes) > 0 {
+			groups = append(groups, dn.RDNs[0].Attributes[0].Value)
+		}
+	}
+	return groups, nil
+}
+
+// GetUserTeam returns the user's team
+func (c *LDAPClient) GetUserTeam(user string) (string, error) {
+	entry, err := c.getUser(user)
+	if err != nil {
+		return "", err
+	}
+	return entry.GetEqualFoldAttributeValue(c.config.Attributes.Team), nil
+}
+
+func (c *LDAPClient) getUser(email string) (*ldap.Entry, error) {
+	key := "user:" + strings.ToLower(email)
+	if entry := c.getCached(key); entry != nil {
+		return entry, nil
+	}
+
+	filter := fmt.Sprintf("(%s=%s)", ldap.EscapeFilter(c.config.Attributes.Email), ldap.EscapeFilter(email))
+	entries, err := c.search(c.config.BaseDN, ldap.ScopeWholeSubtree, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrUserNotFound, email)
+	} else if len(entries) > 1 {
+		return nil, fmt.Errorf("%w: %q matches %d directory entries", ErrUserNotFound, email, len(entries))
+	}
+
+	c.setCached(key, entries[0])
+	return entries[0], nil
+}
+
+func (c *LDAPClient) getEntry(dn string) (*ldap.Entry, error) {
+	key := "dn:" + strings.ToLower(dn)
+	if entry := c.getCached(key); entry != nil {
+		return entry, nil
+	}
+
+	entries, err := c.search(dn, ldap.ScopeBaseObject, "(objectClass=*)")
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrUserNotFound, dn)
+	}
+
+	c.setCached(key, entries[0])
+	return entries[0], nil
+}
+
+func (c *LDAPClient) getCached(key string) *ldap.Entry {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	if item := c.cache[key]; item != nil && time.Now().Before(item.expiresAt) {
+		return item.entry
+	}
+	delete(c.cache, key)
+	return nil
+}
+
+// setCached caches the entry. The expired entries which aren't looked up again are swept at most once per ttl
+func (c *LDAPClient) setCached(key string, entry *ldap.Entry) {
+	if c.config.CacheTTL < 0 {
+		return
+	}
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	now := time.Now()
+	if now.After(c.cacheSweepAt) {
+		for k, item := range c.cache {
+			if !now.Before(item.expiresAt) {
+				delete(c.cache, k)
+			}
+		}
+		c.cacheSweepAt = now.Add(c.config.CacheTTL)
+	}
+	c.cache[key] = &ldapCacheItem{entry: entry, expiresAt: now.Add(c.config.CacheTTL)}
+}
+
+// search runs the search on a pooled connection. The connection is discarded if the request fails
+func (c *LDAPClient) search(baseDN string, scope int, filter string) ([]*ldap.Entry, error) {
+	conn, err := c.getConn()
+	if err != nil {
+		return nil, err
+	}
+
+	req := ldap.NewSearchRequest(
+		baseDN,
+		scope,
+		ldap.NeverDerefAliases,
+		0, // no size limit
+		int(c.config.Timeout/time.Second),
+		false,
+		filter,
+		[]string{c.config.Attributes.Email, c.config.Attributes.Manager, c.config.Attributes.Group, c.config.Attributes.Team},
+		nil,
+	)
+	// the referrals to other servers aren't followed
+	result, err := conn.Search(req)
+	if err != nil {
+		// the base entry doesn't exist
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			c.putConn(conn)
+			return nil, nil
+		}
+		conn.Close()
+		return nil, toLDAPResultError(err)
+	}
+	c.putConn(conn)
+	return result.Entries, nil
+}
+
+func (c *LDAPClient) getConn() (ldap.Client, error) {
+	select {
+	case conn := <-c.pool:
+		if !conn.IsClosing() {
+			return conn, nil
+		}
+	default:
+	}
+
+	conn, err := c.Dial(c.config.URL, c.config.Timeout)
+	if err != nil {
+		return nil, toLDAPResultError(err)
+	}
+	conn.SetTimeout(c.config.Timeout)
+	if c.config.TLS.StartTLS {
+		if err := conn.StartTLS(c.tlsConfig); err != nil {
+			conn.Close()
+			return nil, toLDAPResultError(err)
+		}
+	}
+	if err := conn.Bind(c.config.BindDN, c.config.BindPassword); err != nil {
+		conn.Close()
+		return nil, toLDAPResultError(err)
+	}
+	return conn, nil
+}
+
+func (c *LDAPClient) putConn(conn ldap.Client) {
+	select {
+	case c.pool <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// dial opens a connection to the directory server, the ldaps:// connections are verified with the tls config
+func (c *LDAPClient) dial(url string, timeout time.Duration) (ldap.Client, error) {
+	return ldap.DialURL(url, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}), ldap.DialWithTLSConfig(c.tlsConfig))
+}
+
+func getLDAPTLSConfig(config LDAPTLSConfig, host string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         config.ServerName,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+	if config.CACertFile != "" {
+		pem, err := ioutil.ReadFile(config.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading the ldap ca cert file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in the ldap ca cert file %q", config.CACertFile)
+		}
+	}
+	return tlsConfig, nil
+}
+
+// LDAPResultError is returned when the directory server responds with a non-success result code
+type LDAPResultError struct {
+	Code    int
+	Message string
+}
+
+func (e *LDAPResultError) Error() string {
+	return fmt.Sprintf("%s: result code %d: %s", ErrLDAPRequestFailed, e.Code, e.Message)
+}
+
+func (e *LDAPResultError) Unwrap() error {
+	return ErrLDAPRequestFailed
+}
+
+func toLDAPResultError(err error) error {
+	var ldapErr *ldap.Error
+	if errors.As(err, &ldapErr) {
+		return &LDAPResultError{Code: int(ldapErr.ResultCode), Message: ldapErr.Err.Error()}
+	}
+	return err
+}
@@ -0,0 +1,303 @@
+package iam_test
+
+import (
+	"crypto/tls"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/odpf/guardian/iam"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeLDAPDirectory answers the bind and search requests of the LDAP client from an in-memory directory
+type fakeLDAPDirectory struct {
+	bindDN       string
+	bindPassword string
+	entries      map[string]map[string][]string
+	// requireTLS rejects the binds made before StartTLS
+	requireTLS bool
+
+	mu          sync.Mutex
+	connections int
+	searches    int
+}
+
+func (d *fakeLDAPDirectory) dial(url string, timeout time.Duration) (ldap.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.connections++
+	return &fakeLDAPConn{directory: d}, nil
+}
+
+func (d *fakeLDAPDirectory) stats() (connections, searches int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.connections, d.searches
+}
+
+// fakeLDAPConn implements the bind and search of ldap.Client, the other methods panic if called
+type fakeLDAPConn struct {
+	ldap.Client
+	directory *fakeLDAPDirectory
+	closed    bool
+	tls       *tls.Config
+}
+
+func (c *fakeLDAPConn) SetTimeout(time.Duration) {}
+
+func (c *fakeLDAPConn) IsClosing() bool { return c.closed }
+
+func (c *fakeLDAPConn) Close() { c.closed = true }
+
+func (c *fakeLDAPConn) StartTLS(config *tls.Config) error {
+	c.tls = config
+	return nil
+}
+
+func (c *fakeLDAPConn) Bind(username, password string) error {
+	if c.directory.requireTLS && c.tls == nil {
+		return ldap.NewError(ldap.LDAPResultConfidentialityRequired, errors.New("confidentiality required"))
+	}
+	if username != c.directory.bindDN || password != c.directory.bindPassword {
+		return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+	return nil
+}
+
+func (c *fakeLDAPConn) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	c.directory.mu.Lock()
+	defer c.directory.mu.Unlock()
+	c.directory.searches++
+
+	var dns []string
+	if req.Scope == ldap.ScopeBaseObject {
+		if c.directory.entries[req.BaseDN] == nil {
+			return nil, ldap.NewError(ldap.LDAPResultNoSuchObject, errors.New("no such object"))
+		}
+		dns = append(dns, req.BaseDN)
+	} else {
+		// the client searches the users by an equality filter, i.e. (attribute=value)
+		assertion := strings.SplitN(strings.Trim(req.Filter, "()"), "=", 2)
+		for dn, attributes := range c.directory.entries {
+			if !strings.HasSuffix(dn, req.BaseDN) {
+				continue
+			}
+			for _, v := range attributes[assertion[0]] {
+				if strings.EqualFold(v, assertion[1]) {
+					dns = append(dns, dn)
+				}
+			}
+		}
+	}
+
+	result := &ldap.SearchResult{}
+	for _, dn := range dns {
+		result.Entries = append(result.Entries, ldap.NewEntry(dn, c.directory.entries[dn]))
+	}
+	return result, nil
+}
+
+type LDAPClientTestSuite struct {
+	suite.Suite
+	directory *fakeLDAPDirectory
+}
+
+func (s *LDAPClientTestSuite) SetupTest() {
+	s.directory = &fakeLDAPDirectory{
+		bindDN:       "cn=guardian,dc=company,dc=com",
+		bindPassword: "secret",
+		entries: map[string]map[string][]string{
+			"uid=ceo,ou=people,dc=company,dc=com": {
+				"mail":    {"ceo@company.com"},
+				"manager": {"uid=ceo,ou=people,dc=company,dc=com"},
+			},
+			"uid=director,ou=people,dc=company,dc=com": {
+				"mail":    {"director@company.com"},
+				"manager": {"uid=ceo,ou=people,dc=company,dc=com"},
+			},
+			"uid=lead,ou=people,dc=company,dc=com": {
+				"mail":    {"lead@company.com"},
+				"manager": {"uid=director,ou=people,dc=company,dc=com"},
+			},
+			"uid=engineer,ou=people,dc=company,dc=com": {
+				"mail":       {"engineer@company.com"},
+				"manager":    {"uid=lead,ou=people,dc=company,dc=com"},
+				"memberOf":   {"cn=data-platform,ou=groups,dc=company,dc=com", "cn=oncall,ou=groups,dc=company,dc=com"},
+				"department": {"Data Platform"},
+			},
+			"uid=intern,ou=people,dc=company,dc=com": {
+				"mail":    {"intern@company.com"},
+				"manager": {"uid=former,ou=people,dc=company,dc=com"},
+			},
+		},
+	}
+}
+
+func (s *LDAPClientTestSuite) newClient(config iam.LDAPClientConfig) *iam.LDAPClient {
+	config.URL = "ldap://ldap.company.com"
+	config.BaseDN = "ou=people,dc=company,dc=com"
+	if config.BindDN == "" {
+		config.BindDN = s.directory.bindDN
+		config.BindPassword = s.directory.bindPassword
+	}
+	config.Timeout = 5 * time.Second
+	client, err := iam.NewLDAPClient(&config)
+	s.Require().Nil(err)
+	client.Dial = s.directory.dial
+	return client
+}
+
+func (s *LDAPClientTestSuite) TestNewLDAPClient() {
+	s.Run("should return error if the config is invalid", func() {
+		_, actualError := iam.NewLDAPClient(&iam.LDAPClientConfig{URL: "ldap://localhost"})
+		s.NotNil(actualError)
+
+		_, actualError = iam.NewLDAPClient(&iam.LDAPClientConfig{URL: "http://localhost", BindDN: "cn=guardian", BaseDN: "dc=company"})
+		s.NotNil(actualError)
+	})
+
+	s.Run("should return error if the tls config is invalid", func() {
+		_, actualError := iam.NewLDAPClient(&iam.LDAPClientConfig{
+			URL:    "ldaps://localhost",
+			BindDN: "cn=guardian",
+			BaseDN: "dc=company",
+			TLS:    iam.LDAPTLSConfig{StartTLS: true},
+		})
+		s.NotNil(actualError)
+
+		_, actualError = iam.NewLDAPClient(&iam.LDAPClientConfig{
+			URL:    "ldaps://localhost",
+			BindDN: "cn=guardian",
+			BaseDN: "dc=company",
+			TLS:    iam.LDAPTLSConfig{CACertFile: "/nonexistent/ca.pem"},
+		})
+		s.NotNil(actualError)
+	})
+}
+
+func (s *LDAPClientTestSuite) TestGetManagerEmails() {
+	s.Run("should return the email of the direct manager", func() {
+		client := s.newClient(iam.LDAPClientConfig{})
+
+		actualResult, actualError := client.GetManagerEmails("engineer@company.com")
+
+		s.Nil(actualError)
+		s.Equal([]string{"lead@company.com"}, actualResult)
+	})
+
+	s.Run("should return error if the user or their manager is not found", func() {
+		client := s.newClient(iam.LDAPClientConfig{})
+
+		_, actualError := client.GetManagerEmails("unknown@company.com")
+		s.ErrorIs(actualError, iam.ErrUserNotFound)
+
+		_, actualError = client.GetManagerEmails("intern@company.com")
+		s.ErrorIs(actualError, iam.ErrUserNotFound)
+	})
+
+	s.Run("should return error if the bind fails", func() {
+		client := s.newClient(iam.LDAPClientConfig{BindDN: s.directory.bindDN, BindPassword: "wrong"})
+
+		_, actualError := client.GetManagerEmails("engineer@company.com")
+
+		var resultError *iam.LDAPResultError
+		s.Require().True(errors.As(actualError, &resultError))
+		s.Equal(49, resultError.Code)
+		s.ErrorIs(actualError, iam.ErrLDAPRequestFailed)
+	})
+
+	s.Run("should start tls before binding if configured", func() {
+		s.directory.requireTLS = true
+		defer func() { s.directory.requireTLS = false }()
+
+		_, actualError := s.newClient(iam.LDAPClientConfig{}).GetManagerEmails("engineer@company.com")
+		s.ErrorIs(actualError, iam.ErrLDAPRequestFailed)
+
+		actualResult, actualError := s.newClient(iam.LDAPClientConfig{TLS: iam.LDAPTLSConfig{StartTLS: true}}).GetManagerEmails("engineer@company.com")
+		s.Nil(actualError)
+		s.Equal([]string{"lead@company.com"}, actualResult)
+	})
+
+	s.Run("should reuse the connections and cache the entries", func() {
+		client := s.newClient(iam.LDAPClientConfig{})
+		connectionsBefore, searchesBefore := s.directory.stats()
+
+		for i := 0; i < 3; i++ {
+			actualResult, actualError := client.GetManagerEmails("engineer@company.com")
+			s.Nil(actualError)
+			s.Equal([]string{"lead@company.com"}, actualResult)
+		}
+
+		connections, searches := s.directory.stats()
+		s.Equal(1, connections-connectionsBefore)
+		s.Equal(2, searches-searchesBefore)
+	})
+
+	s.Run("should search the directory every time if the cache is disabled", func() {
+		client := s.newClient(iam.LDAPClientConfig{CacheTTL: -1})
+		_, searchesBefore := s.directory.stats()
+
+		for i := 0; i < 2; i++ {
+			_, actualError := client.GetManagerEmails("engineer@company.com")
+			s.Nil(actualError)
+		}
+
+		_, searches := s.directory.stats()
+		s.Equal(4, searches-searchesBefore)
+	})
+}
+
+func (s *LDAPClientTestSuite) TestGetManagerChain() {
+	s.Run("should return the managers up to the top of the organization", func() {
+		client := s.newClient(iam.LDAPClientConfig{})
+
+		actualResult, actualError := client.GetManagerChain("engineer@company.com")
+
+		s.Nil(actualError)
+		s.Equal([]string{"lead@company.com", "director@company.com", "ceo@company.com"}, actualResult)
+	})
+
+	s.Run("should stop at the maximum chain size", func() {
+		client := s.newClient(iam.LDAPClientConfig{MaxManagerChainSize: 2})
+
+		actualResult, actualError := client.GetManagerChain("engineer@company.com")
+
+		s.Nil(actualError)
+		s.Equal([]string{"lead@company.com", "director@company.com"}, actualResult)
+	})
+}
+
+func (s *LDAPClientTestSuite) TestGetUserGroups() {
+	s.Run("should return the group names", func() {
+		client := s.newClient(iam.LDAPClientConfig{})
+
+		actualResult, actualError := client.GetUserGroups("engineer@company.com")
+
+		s.Nil(actualError)
+		s.Equal([]string{"data-platform", "oncall"}, actualResult)
+	})
+}
+
+func (s *LDAPClientTestSuite) TestGetUserTeam() {
+	s.Run("should return the team from the mapped attribute", func() {
+		client := s.newClient(iam.LDAPClientConfig{})
+
+		actualResult, actualError := client.GetUserTeam("engineer@company.com")
+
+		s.Nil(actualError)
+		s.Equal("Data Platform", actualResult)
+
+		service := iam.NewService(client)
+		actualGroups, actualError := service.GetUserGroups("engineer@company.com")
+		s.Nil(actualError)
+		s.Equal([]string{"data-platform", "oncall"}, actualGroups)
+	})
+}
+
+func TestLDAPClient(t *testing.T) {
+	suite.Run(t, new(LDAPClientTestSuite))
+}
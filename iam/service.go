@@ -1,15 +1,41 @@
 package iam
 
-import "github.com/odpf/guardian/domain"
+import (
+	"sync"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+const defaultTeamCacheTTL = 5 * time.Minute
 
 // Service handles business logic for identity manager
 type Service struct {
 	client domain.IAMClient
+
+	// TeamCacheTTL is how long the user teams are cached, e.g. the team quota looks up the team of every
+	// grantee of a role. Default: 5m, a negative value disables the cache
+	TeamCacheTTL time.Duration
+	TimeNow      func() time.Time
+
+	teamCacheMu      sync.Mutex
+	teamCache        map[string]*teamCacheItem
+	teamCacheSweepAt time.Time
+}
+
+type teamCacheItem struct {
+	team      string
+	expiresAt time.Time
 }
 
 // NewService returns *iam.Service
 func NewService(client domain.IAMClient) *Service {
-	return &Service{client}
+	return &Service{
+		client:       client,
+		TeamCacheTTL: defaultTeamCacheTTL,
+		TimeNow:      time.Now,
+		teamCache:    map[string]*teamCacheItem{},
+	}
 }
 
 // GetUserApproverEmails returns array of approver emails or error if any
@@ -28,3 +54,101 @@ func (s *Service) GetUserApproverEmails(user string) ([]string, error) {
 
 	return approverEmails, nil
 }
+
+// IsUserAvailable tells whether the user is currently available to act, e.g. not out-of-office.
+// It returns ErrAvailabilityNotSupported if the client doesn't provide the availability
+func (s *Service) IsUserAvailable(user string) (bool, error) {
+	if user == "" {
+		return false, ErrEmptyUserEmailParam
+	}
+
+	checker, ok := s.client.(domain.IAMAvailabilityChecker)
+	if !ok {
+		return false, ErrAvailabilityNotSupported
+	}
+	return checker.IsUserAvailable(user)
+}
+
+// GetUserTeam returns the team the user belongs to. It returns ErrTeamNotSupported if the client doesn't
+// provide the user team
+func (s *Service) GetUserTeam(user string) (string, error) {
+	if user == "" {
+		return "", ErrEmptyUserEmailParam
+	}
+
+	resolver, ok := s.client.(domain.IAMTeamResolver)
+	if !ok {
+		return "", ErrTeamNotSupported
+	}
+	if team, ok := s.getCachedTeam(user); ok {
+		return team, nil
+	}
+	team, err := resolver.GetUserTeam(user)
+	if err != nil {
+		return "", err
+	}
+	if team == "" {
+		return "", ErrEmptyTeam
+	}
+	s.setCachedTeam(user, team)
+	return team, nil
+}
+
+func (s *Service) getCachedTeam(user string) (string, bool) {
+	if s.TeamCacheTTL < 0 {
+		return "", false
+	}
+	s.teamCacheMu.Lock()
+	defer s.teamCacheMu.Unlock()
+	if item := s.teamCache[user]; item != nil && s.TimeNow().Before(item.expiresAt) {
+		return item.team, true
+	}
+	return "", false
+}
+
+func (s *Service) setCachedTeam(user, team string) {
+	if s.TeamCacheTTL < 0 {
+		return
+	}
+	s.teamCacheMu.Lock()
+	defer s.teamCacheMu.Unlock()
+	now := s.TimeNow()
+	// the expired teams of the users who aren't looked up anymore are swept at most once per ttl
+	if now.After(s.teamCacheSweepAt) {
+		for u, item := range s.teamCache {
+			if !now.Before(item.expiresAt) {
+				delete(s.teamCache, u)
+			}
+		}
+		s.teamCacheSweepAt = now.Add(s.TeamCacheTTL)
+	}
+	s.teamCache[user] = &teamCacheItem{team, now.Add(s.TeamCacheTTL)}
+}
+
+// GetManagerChain returns the emails of the managers above the user, starting from the direct manager.
+// It returns ErrManagerChainNotSupported if the client doesn't provide the manager chain
+func (s *Service) GetManagerChain(user string) ([]string, error) {
+	if user == "" {
+		return nil, ErrEmptyUserEmailParam
+	}
+
+	resolver, ok := s.client.(domain.IAMManagerChainResolver)
+	if !ok {
+		return nil, ErrManagerChainNotSupported
+	}
+	return resolver.GetManagerChain(user)
+}
+
+// GetUserGroups returns the groups the user belongs to. It returns ErrGroupsNotSupported if the client
+// doesn't provide the user groups
+func (s *Service) GetUserGroups(user string) ([]string, error) {
+	if user == "" {
+		return nil, ErrEmptyUserEmailParam
+	}
+
+	resolver, ok := s.client.(domain.IAMGroupResolver)
+	if !ok {
+		return nil, ErrGroupsNotSupported
+	}
+	return resolver.GetUserGroups(user)
+}
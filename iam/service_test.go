@@ -1,7 +1,11 @@
 package iam_test
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/odpf/guardian/iam"
 	"github.com/odpf/guardian/mocks"
@@ -48,6 +52,111 @@ func (s *ServiceTestSuite) TestGetUserApproverEmails() {
 	})
 }
 
+func (s *ServiceTestSuite) TestIsUserAvailable() {
+	s.Run("should return error if the client doesn't support availability", func() {
+		actualResult, actualError := s.service.IsUserAvailable("test@email.com")
+
+		s.False(actualResult)
+		s.ErrorIs(actualError, iam.ErrAvailabilityNotSupported)
+	})
+
+	s.Run("should return the availability from the client", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			available := r.URL.Query().Get("user") == "available@email.com"
+			fmt.Fprintf(w, `{"available":%v}`, available)
+		}))
+		defer server.Close()
+		client, err := iam.NewHTTPClient(&iam.HTTPClientConfig{
+			GetManagersURL:         server.URL,
+			GetUserAvailabilityURL: server.URL,
+		})
+		s.Require().Nil(err)
+		service := iam.NewService(client)
+
+		actualResult, actualError := service.IsUserAvailable("available@email.com")
+		s.Nil(actualError)
+		s.True(actualResult)
+
+		actualResult, actualError = service.IsUserAvailable("unavailable@email.com")
+		s.Nil(actualError)
+		s.False(actualResult)
+	})
+
+	s.Run("should return error if the availability url is not configured", func() {
+		client, err := iam.NewHTTPClient(&iam.HTTPClientConfig{GetManagersURL: "http://localhost"})
+		s.Require().Nil(err)
+
+		actualResult, actualError := iam.NewService(client).IsUserAvailable("test@email.com")
+
+		s.False(actualResult)
+		s.ErrorIs(actualError, iam.ErrAvailabilityNotSupported)
+	})
+}
+
+func (s *ServiceTestSuite) TestGetUserTeam() {
+	s.Run("should return error if the client doesn't support teams", func() {
+		actualResult, actualError := s.service.GetUserTeam("test@email.com")
+
+		s.Empty(actualResult)
+		s.ErrorIs(actualError, iam.ErrTeamNotSupported)
+	})
+
+	s.Run("should return the team from the client", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("user") == "member@email.com" {
+				fmt.Fprint(w, `{"team":"data-platform"}`)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		}))
+		defer server.Close()
+		client, err := iam.NewHTTPClient(&iam.HTTPClientConfig{
+			GetManagersURL: server.URL,
+			GetUserTeamURL: server.URL,
+		})
+		s.Require().Nil(err)
+		service := iam.NewService(client)
+
+		actualResult, actualError := service.GetUserTeam("member@email.com")
+		s.Nil(actualError)
+		s.Equal("data-platform", actualResult)
+
+		actualResult, actualError = service.GetUserTeam("outsider@email.com")
+		s.Empty(actualResult)
+		s.ErrorIs(actualError, iam.ErrEmptyTeam)
+	})
+
+	s.Run("should cache the team of the user until the ttl", func() {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			fmt.Fprint(w, `{"team":"data-platform"}`)
+		}))
+		defer server.Close()
+		client, err := iam.NewHTTPClient(&iam.HTTPClientConfig{
+			GetManagersURL: server.URL,
+			GetUserTeamURL: server.URL,
+		})
+		s.Require().Nil(err)
+		service := iam.NewService(client)
+		now := time.Now()
+		service.TimeNow = func() time.Time { return now }
+
+		for i := 0; i < 2; i++ {
+			actualResult, actualError := service.GetUserTeam("member@email.com")
+			s.Nil(actualError)
+			s.Equal("data-platform", actualResult)
+		}
+		s.Equal(1, requests)
+
+		now = now.Add(service.TeamCacheTTL)
+		actualResult, actualError := service.GetUserTeam("member@email.com")
+		s.Nil(actualError)
+		s.Equal("data-platform", actualResult)
+		s.Equal(2, requests)
+	})
+}
+
 func TestService(t *testing.T) {
 	suite.Run(t, new(ServiceTestSuite))
 }
@@ -12,7 +12,7 @@ type Config struct {
 func New(config *Config) (*zap.Logger, error) {
 	defaultConfig := zap.NewProductionConfig()
 	defaultConfig.Level = zap.NewAtomicLevelAt(getZapLogLevelFromString(config.Level))
-	logger, err := zap.NewProductionConfig().Build()
+	logger, err := defaultConfig.Build()
 	return logger, err
 }
 
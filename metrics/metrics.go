@@ -0,0 +1,57 @@
+// Package metrics holds the exporters of the appeal lifecycle metrics
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+const (
+	// ExporterNone discards the metrics
+	ExporterNone = "none"
+	// ExporterStatsD sends the metrics to a StatsD agent, without the tags
+	ExporterStatsD = "statsd"
+	// ExporterDataDog sends the metrics to a DataDog agent through DogStatsD, along with the tags
+	ExporterDataDog = "datadog"
+
+	// Metric names
+	AppealCreated          = "appeal.created"
+	AppealCreateDuration   = "appeal.create.duration"
+	ApprovalAction         = "approval.action"
+	ApprovalActionDuration = "approval.action.duration"
+	AppealRevoked          = "appeal.revoked"
+	AccessChangeDuration   = "access.change.duration"
+)
+
+var ErrUnsupportedExporter = errors.New("unsupported metrics exporter")
+
+// Config for the metrics exporter
+type Config struct {
+	Exporter string `mapstructure:"exporter" default:"none"`
+	// Address is the host:port of the StatsD or DataDog agent
+	Address string `mapstructure:"address" default:"localhost:8125"`
+	// Prefix is prepended to the metric names
+	Prefix string `mapstructure:"prefix" default:"guardian."`
+}
+
+// New returns the metrics of the configured exporter
+func New(c *Config) (domain.Metrics, error) {
+	switch c.Exporter {
+	case "", ExporterNone:
+		return Noop{}, nil
+	case ExporterStatsD:
+		return NewStatsD(c.Address, c.Prefix, false)
+	case ExporterDataDog:
+		return NewStatsD(c.Address, c.Prefix, true)
+	default:
+		return nil, ErrUnsupportedExporter
+	}
+}
+
+// Noop discards the metrics
+type Noop struct{}
+
+func (Noop) Count(string, int64, map[string]string)          {}
+func (Noop) Timing(string, time.Duration, map[string]string) {}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StatsD sends the metrics to a StatsD agent over UDP. The metrics are best-effort, a failed write is
+// dropped so it never slows down or fails the appeal lifecycle
+type StatsD struct {
+	conn   net.Conn
+	prefix string
+	// withTags appends the tags in the DogStatsD format, which the plain StatsD agents don't support
+	withTags bool
+}
+
+// NewStatsD returns the StatsD metrics sending to the address
+func NewStatsD(address, prefix string, withTags bool) (*StatsD, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsD{conn, prefix, withTags}, nil
+}
+
+func (s *StatsD) Count(name string, value int64, tags map[string]string) {
+	s.send(name, fmt.Sprintf("%d|c", value), tags)
+}
+
+func (s *StatsD) Timing(name string, d time.Duration, tags map[string]string) {
+	s.send(name, fmt.Sprintf("%d|ms", d.Milliseconds()), tags)
+}
+
+// Close closes the connection to the agent
+func (s *StatsD) Close() error {
+	return s.conn.Close()
+}
+
+func (s *StatsD) send(name, value string, tags map[string]string) {
+	line := fmt.Sprintf("%s%s:%s", s.prefix, name, value)
+	if s.withTags && len(tags) > 0 {
+		pairs := []string{}
+		for k, v := range tags {
+			pairs = append(pairs, fmt.Sprintf("%s:%s", k, v))
+		}
+		sort.Strings(pairs)
+		line = fmt.Sprintf("%s|#%s", line, strings.Join(pairs, ","))
+	}
+	s.conn.Write([]byte(line))
+}
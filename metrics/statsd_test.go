@@ -0,0 +1,58 @@
+package metrics_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	read := func() string {
+		buf := make([]byte, 512)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		assert.Nil(t, err)
+		return string(buf[:n])
+	}
+	tags := map[string]string{"provider_type": "bigquery", "action": "approve"}
+
+	t.Run("should send the metrics without tags to statsd", func(t *testing.T) {
+		m, err := metrics.New(&metrics.Config{Exporter: metrics.ExporterStatsD, Address: conn.LocalAddr().String(), Prefix: "guardian."})
+		assert.Nil(t, err)
+
+		m.Count(metrics.ApprovalAction, 1, tags)
+		assert.Equal(t, "guardian.approval.action:1|c", read())
+		m.Timing(metrics.ApprovalActionDuration, 1500*time.Millisecond, tags)
+		assert.Equal(t, "guardian.approval.action.duration:1500|ms", read())
+	})
+
+	t.Run("should send the metrics along with the tags to datadog", func(t *testing.T) {
+		m, err := metrics.New(&metrics.Config{Exporter: metrics.ExporterDataDog, Address: conn.LocalAddr().String()})
+		assert.Nil(t, err)
+
+		m.Count(metrics.ApprovalAction, 2, tags)
+		assert.Equal(t, "approval.action:2|c|#action:approve,provider_type:bigquery", read())
+	})
+
+	t.Run("should return error if the exporter is not supported", func(t *testing.T) {
+		m, err := metrics.New(&metrics.Config{Exporter: "prometheus"})
+
+		assert.Nil(t, m)
+		assert.ErrorIs(t, err, metrics.ErrUnsupportedExporter)
+	})
+
+	t.Run("should discard the metrics if no exporter is configured", func(t *testing.T) {
+		m, err := metrics.New(&metrics.Config{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, metrics.Noop{}, m)
+	})
+}
@@ -3,6 +3,9 @@
 package mocks
 
 import (
+	context "context"
+	time "time"
+
 	domain "github.com/odpf/guardian/domain"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +15,83 @@ type AppealRepository struct {
 	mock.Mock
 }
 
-// BulkInsert provides a mock function with given fields: _a0
-func (_m *AppealRepository) BulkInsert(_a0 []*domain.Appeal) error {
-	ret := _m.Called(_a0)
+// AddApproval provides a mock function with given fields: _a0, _a1
+func (_m *AppealRepository) AddApproval(_a0 context.Context, _a1 *domain.Approval) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Approval) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddAttachment provides a mock function with given fields: _a0, _a1
+func (_m *AppealRepository) AddAttachment(_a0 context.Context, _a1 *domain.Attachment) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Attachment) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddComment provides a mock function with given fields: _a0, _a1
+func (_m *AppealRepository) AddComment(_a0 context.Context, _a1 *domain.AppealComment) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AppealComment) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddOutboxMessage provides a mock function with given fields: _a0, _a1
+func (_m *AppealRepository) AddOutboxMessage(_a0 context.Context, _a1 *domain.OutboxMessage) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.OutboxMessage) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BulkInsert provides a mock function with given fields: _a0, _a1
+func (_m *AppealRepository) BulkInsert(_a0 context.Context, _a1 []*domain.Appeal) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Appeal) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteOutboxMessages provides a mock function with given fields: ctx, ids
+func (_m *AppealRepository) DeleteOutboxMessages(ctx context.Context, ids []uint) error {
+	ret := _m.Called(ctx, ids)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]*domain.Appeal) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) error); ok {
+		r0 = rf(ctx, ids)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +99,13 @@ func (_m *AppealRepository) BulkInsert(_a0 []*domain.Appeal) error {
 	return r0
 }
 
-// Find provides a mock function with given fields: _a0
-func (_m *AppealRepository) Find(_a0 map[string]interface{}) ([]*domain.Appeal, error) {
-	ret := _m.Called(_a0)
+// Find provides a mock function with given fields: _a0, _a1
+func (_m *AppealRepository) Find(_a0 context.Context, _a1 map[string]interface{}) ([]*domain.Appeal, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 []*domain.Appeal
-	if rf, ok := ret.Get(0).(func(map[string]interface{}) []*domain.Appeal); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) []*domain.Appeal); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Appeal)
@@ -40,8 +113,100 @@ func (_m *AppealRepository) Find(_a0 map[string]interface{}) ([]*domain.Appeal,
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(map[string]interface{}) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccessSummary provides a mock function with given fields: ctx, user, now
+func (_m *AppealRepository) GetAccessSummary(ctx context.Context, user string, now time.Time) ([]domain.AccessEntry, error) {
+	ret := _m.Called(ctx, user, now)
+
+	var r0 []domain.AccessEntry
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) []domain.AccessEntry); ok {
+		r0 = rf(ctx, user, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AccessEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, user, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetActiveAccess provides a mock function with given fields: ctx, user, resourceID, role, now
+func (_m *AppealRepository) GetActiveAccess(ctx context.Context, user string, resourceID uint, role string, now time.Time) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, user, resourceID, role, now)
+
+	var r0 *domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, string, time.Time) *domain.Appeal); ok {
+		r0 = rf(ctx, user, resourceID, role, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, string, time.Time) error); ok {
+		r1 = rf(ctx, user, resourceID, role, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAttachmentByID provides a mock function with given fields: _a0, _a1
+func (_m *AppealRepository) GetAttachmentByID(_a0 context.Context, _a1 uint) (*domain.Attachment, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *domain.Attachment
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domain.Attachment); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Attachment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAttachments provides a mock function with given fields: ctx, appealID
+func (_m *AppealRepository) GetAttachments(ctx context.Context, appealID uint) ([]*domain.Attachment, error) {
+	ret := _m.Called(ctx, appealID)
+
+	var r0 []*domain.Attachment
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*domain.Attachment); ok {
+		r0 = rf(ctx, appealID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Attachment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, appealID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -49,13 +214,13 @@ func (_m *AppealRepository) Find(_a0 map[string]interface{}) ([]*domain.Appeal,
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: _a0
-func (_m *AppealRepository) GetByID(_a0 uint) (*domain.Appeal, error) {
-	ret := _m.Called(_a0)
+// GetByID provides a mock function with given fields: _a0, _a1
+func (_m *AppealRepository) GetByID(_a0 context.Context, _a1 uint) (*domain.Appeal, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 *domain.Appeal
-	if rf, ok := ret.Get(0).(func(uint) *domain.Appeal); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domain.Appeal); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Appeal)
@@ -63,8 +228,54 @@ func (_m *AppealRepository) GetByID(_a0 uint) (*domain.Appeal, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComments provides a mock function with given fields: ctx, appealID
+func (_m *AppealRepository) GetComments(ctx context.Context, appealID uint) ([]*domain.AppealComment, error) {
+	ret := _m.Called(ctx, appealID)
+
+	var r0 []*domain.AppealComment
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*domain.AppealComment); ok {
+		r0 = rf(ctx, appealID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AppealComment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, appealID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCreatedTimes provides a mock function with given fields: ctx, user, since
+func (_m *AppealRepository) GetCreatedTimes(ctx context.Context, user string, since time.Time) ([]time.Time, error) {
+	ret := _m.Called(ctx, user, since)
+
+	var r0 []time.Time
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) []time.Time); ok {
+		r0 = rf(ctx, user, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]time.Time)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, user, since)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -72,13 +283,108 @@ func (_m *AppealRepository) GetByID(_a0 uint) (*domain.Appeal, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: _a0
-func (_m *AppealRepository) Update(_a0 *domain.Appeal) error {
-	ret := _m.Called(_a0)
+// GetHistory provides a mock function with given fields: ctx, user, role
+func (_m *AppealRepository) GetHistory(ctx context.Context, user string, role string) (*domain.AppealHistory, error) {
+	ret := _m.Called(ctx, user, role)
+
+	var r0 *domain.AppealHistory
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.AppealHistory); ok {
+		r0 = rf(ctx, user, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AppealHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, user, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOutboxMessages provides a mock function with given fields: ctx, createdBefore, limit
+func (_m *AppealRepository) GetOutboxMessages(ctx context.Context, createdBefore time.Time, limit int) ([]*domain.OutboxMessage, error) {
+	ret := _m.Called(ctx, createdBefore, limit)
+
+	var r0 []*domain.OutboxMessage
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*domain.OutboxMessage); ok {
+		r0 = rf(ctx, createdBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.OutboxMessage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, createdBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsPolicyVersionUsed provides a mock function with given fields: ctx, policyID, policyVersion
+func (_m *AppealRepository) IsPolicyVersionUsed(ctx context.Context, policyID string, policyVersion uint) (bool, error) {
+	ret := _m.Called(ctx, policyID, policyVersion)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) bool); ok {
+		r0 = rf(ctx, policyID, policyVersion)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint) error); ok {
+		r1 = rf(ctx, policyID, policyVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockGrants provides a mock function with given fields: ctx, resourceID, role
+func (_m *AppealRepository) LockGrants(ctx context.Context, resourceID uint, role string) error {
+	ret := _m.Called(ctx, resourceID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) error); ok {
+		r0 = rf(ctx, resourceID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: _a0, _a1
+func (_m *AppealRepository) Update(_a0 context.Context, _a1 *domain.Appeal) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Appeal) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithTransaction provides a mock function with given fields: ctx, fn
+func (_m *AppealRepository) WithTransaction(ctx context.Context, fn func(domain.AppealRepository) error) error {
+	ret := _m.Called(ctx, fn)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Appeal) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, func(domain.AppealRepository) error) error); ok {
+		r0 = rf(ctx, fn)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3,6 +3,10 @@
 package mocks
 
 import (
+	context "context"
+	io "io"
+	time "time"
+
 	domain "github.com/odpf/guardian/domain"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +16,179 @@ type AppealService struct {
 	mock.Mock
 }
 
-// Cancel provides a mock function with given fields: _a0
-func (_m *AppealService) Cancel(_a0 uint) (*domain.Appeal, error) {
+// AcknowledgeApproval provides a mock function with given fields: ctx, appealID, approvalName, actor
+func (_m *AppealService) AcknowledgeApproval(ctx context.Context, appealID uint, approvalName string, actor string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, appealID, approvalName, actor)
+
+	var r0 *domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, string) *domain.Appeal); ok {
+		r0 = rf(ctx, appealID, approvalName, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, string) error); ok {
+		r1 = rf(ctx, appealID, approvalName, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ActivateScheduledAccess provides a mock function with given fields: _a0
+func (_m *AppealService) ActivateScheduledAccess(_a0 context.Context) error {
 	ret := _m.Called(_a0)
 
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddApprovalStep provides a mock function with given fields: ctx, appealID, step, position, actor
+func (_m *AppealService) AddApprovalStep(ctx context.Context, appealID uint, step domain.Approval, position int, actor string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, appealID, step, position, actor)
+
 	var r0 *domain.Appeal
-	if rf, ok := ret.Get(0).(func(uint) *domain.Appeal); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uint, domain.Approval, int, string) *domain.Appeal); ok {
+		r0 = rf(ctx, appealID, step, position, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, domain.Approval, int, string) error); ok {
+		r1 = rf(ctx, appealID, step, position, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddAttachment provides a mock function with given fields: ctx, appealID, actor, filename, content
+func (_m *AppealService) AddAttachment(ctx context.Context, appealID uint, actor string, filename string, content io.Reader) (*domain.Attachment, error) {
+	ret := _m.Called(ctx, appealID, actor, filename, content)
+
+	var r0 *domain.Attachment
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, string, io.Reader) *domain.Attachment); ok {
+		r0 = rf(ctx, appealID, actor, filename, content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Attachment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, string, io.Reader) error); ok {
+		r1 = rf(ctx, appealID, actor, filename, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddComment provides a mock function with given fields: ctx, appealID, actor, body, visibility
+func (_m *AppealService) AddComment(ctx context.Context, appealID uint, actor string, body string, visibility string) (*domain.AppealComment, error) {
+	ret := _m.Called(ctx, appealID, actor, body, visibility)
+
+	var r0 *domain.AppealComment
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, string, string) *domain.AppealComment); ok {
+		r0 = rf(ctx, appealID, actor, body, visibility)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AppealComment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, string, string) error); ok {
+		r1 = rf(ctx, appealID, actor, body, visibility)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AdminApprove provides a mock function with given fields: ctx, appealID, approvalName, adminActor, reason
+func (_m *AppealService) AdminApprove(ctx context.Context, appealID uint, approvalName string, adminActor string, reason string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, appealID, approvalName, adminActor, reason)
+
+	var r0 *domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, string, string) *domain.Appeal); ok {
+		r0 = rf(ctx, appealID, approvalName, adminActor, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, string, string) error); ok {
+		r1 = rf(ctx, appealID, approvalName, adminActor, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Cancel provides a mock function with given fields: ctx, id, actor
+func (_m *AppealService) Cancel(ctx context.Context, id uint, actor string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, id, actor)
+
+	var r0 *domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) *domain.Appeal); ok {
+		r0 = rf(ctx, id, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, id, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CancelAbandonedAppeals provides a mock function with given fields: _a0
+func (_m *AppealService) CancelAbandonedAppeals(_a0 context.Context) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
 		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Clone provides a mock function with given fields: ctx, id, user
+func (_m *AppealService) Clone(ctx context.Context, id uint, user string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, id, user)
+
+	var r0 *domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) *domain.Appeal); ok {
+		r0 = rf(ctx, id, user)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Appeal)
@@ -26,8 +196,8 @@ func (_m *AppealService) Cancel(_a0 uint) (*domain.Appeal, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, id, user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -35,12 +205,26 @@ func (_m *AppealService) Cancel(_a0 uint) (*domain.Appeal, error) {
 	return r0, r1
 }
 
-// Create provides a mock function with given fields: _a0
-func (_m *AppealService) Create(_a0 []*domain.Appeal) error {
+// Create provides a mock function with given fields: _a0, _a1
+func (_m *AppealService) Create(_a0 context.Context, _a1 []*domain.Appeal) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Appeal) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DispatchOutboxMessages provides a mock function with given fields: _a0
+func (_m *AppealService) DispatchOutboxMessages(_a0 context.Context) error {
 	ret := _m.Called(_a0)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]*domain.Appeal) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
 		r0 = rf(_a0)
 	} else {
 		r0 = ret.Error(0)
@@ -49,12 +233,35 @@ func (_m *AppealService) Create(_a0 []*domain.Appeal) error {
 	return r0
 }
 
-// Find provides a mock function with given fields: _a0
-func (_m *AppealService) Find(_a0 map[string]interface{}) ([]*domain.Appeal, error) {
+// Find provides a mock function with given fields: _a0, _a1
+func (_m *AppealService) Find(_a0 context.Context, _a1 map[string]interface{}) ([]*domain.Appeal, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) []*domain.Appeal); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDeadlockedAppeals provides a mock function with given fields: _a0
+func (_m *AppealService) FindDeadlockedAppeals(_a0 context.Context) ([]*domain.Appeal, error) {
 	ret := _m.Called(_a0)
 
 	var r0 []*domain.Appeal
-	if rf, ok := ret.Get(0).(func(map[string]interface{}) []*domain.Appeal); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Appeal); ok {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
@@ -63,7 +270,7 @@ func (_m *AppealService) Find(_a0 map[string]interface{}) ([]*domain.Appeal, err
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(map[string]interface{}) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
 		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
@@ -72,13 +279,91 @@ func (_m *AppealService) Find(_a0 map[string]interface{}) ([]*domain.Appeal, err
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: _a0
-func (_m *AppealService) GetByID(_a0 uint) (*domain.Appeal, error) {
-	ret := _m.Called(_a0)
+// GetAccessReview provides a mock function with given fields: ctx, filter
+func (_m *AppealService) GetAccessReview(ctx context.Context, filter domain.AccessReviewFilter) ([]*domain.AccessReviewEntry, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*domain.AccessReviewEntry
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AccessReviewFilter) []*domain.AccessReviewEntry); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AccessReviewEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.AccessReviewFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetApprovalProvenance provides a mock function with given fields: ctx, appealID
+func (_m *AppealService) GetApprovalProvenance(ctx context.Context, appealID uint) ([]*domain.ApprovalProvenance, error) {
+	ret := _m.Called(ctx, appealID)
+
+	var r0 []*domain.ApprovalProvenance
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*domain.ApprovalProvenance); ok {
+		r0 = rf(ctx, appealID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ApprovalProvenance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, appealID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAttachmentContent provides a mock function with given fields: ctx, id, viewer
+func (_m *AppealService) GetAttachmentContent(ctx context.Context, id uint, viewer string) (*domain.Attachment, io.ReadCloser, error) {
+	ret := _m.Called(ctx, id, viewer)
+
+	var r0 *domain.Attachment
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) *domain.Attachment); ok {
+		r0 = rf(ctx, id, viewer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Attachment)
+		}
+	}
+
+	var r1 io.ReadCloser
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) io.ReadCloser); ok {
+		r1 = rf(ctx, id, viewer)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(io.ReadCloser)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, uint, string) error); ok {
+		r2 = rf(ctx, id, viewer)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByID provides a mock function with given fields: _a0, _a1
+func (_m *AppealService) GetByID(_a0 context.Context, _a1 uint) (*domain.Appeal, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 *domain.Appeal
-	if rf, ok := ret.Get(0).(func(uint) *domain.Appeal); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domain.Appeal); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Appeal)
@@ -86,8 +371,54 @@ func (_m *AppealService) GetByID(_a0 uint) (*domain.Appeal, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComments provides a mock function with given fields: ctx, appealID, viewer
+func (_m *AppealService) GetComments(ctx context.Context, appealID uint, viewer string) ([]*domain.AppealComment, error) {
+	ret := _m.Called(ctx, appealID, viewer)
+
+	var r0 []*domain.AppealComment
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) []*domain.AppealComment); ok {
+		r0 = rf(ctx, appealID, viewer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AppealComment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, appealID, viewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPendingForApprover provides a mock function with given fields: ctx, approver
+func (_m *AppealService) GetPendingForApprover(ctx context.Context, approver string) ([]*domain.Appeal, error) {
+	ret := _m.Called(ctx, approver)
+
+	var r0 []*domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.Appeal); ok {
+		r0 = rf(ctx, approver)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, approver)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -95,13 +426,426 @@ func (_m *AppealService) GetByID(_a0 uint) (*domain.Appeal, error) {
 	return r0, r1
 }
 
-// MakeAction provides a mock function with given fields: _a0
-func (_m *AppealService) MakeAction(_a0 domain.ApprovalAction) (*domain.Appeal, error) {
+// GetSLAReport provides a mock function with given fields: ctx, filters
+func (_m *AppealService) GetSLAReport(ctx context.Context, filters map[string]interface{}) (*domain.SLAReport, error) {
+	ret := _m.Called(ctx, filters)
+
+	var r0 *domain.SLAReport
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) *domain.SLAReport); ok {
+		r0 = rf(ctx, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SLAReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}) error); ok {
+		r1 = rf(ctx, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserAccessSummary provides a mock function with given fields: ctx, user, withPermissions
+func (_m *AppealService) GetUserAccessSummary(ctx context.Context, user string, withPermissions bool) ([]domain.AccessEntry, error) {
+	ret := _m.Called(ctx, user, withPermissions)
+
+	var r0 []domain.AccessEntry
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) []domain.AccessEntry); ok {
+		r0 = rf(ctx, user, withPermissions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AccessEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, user, withPermissions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasActiveAccess provides a mock function with given fields: ctx, user, resourceID, role
+func (_m *AppealService) HasActiveAccess(ctx context.Context, user string, resourceID uint, role string) (bool, *domain.Appeal, error) {
+	ret := _m.Called(ctx, user, resourceID, role)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, string) bool); ok {
+		r0 = rf(ctx, user, resourceID, role)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 *domain.Appeal
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, string) *domain.Appeal); ok {
+		r1 = rf(ctx, user, resourceID, role)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*domain.Appeal)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, uint, string) error); ok {
+		r2 = rf(ctx, user, resourceID, role)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListAttachments provides a mock function with given fields: ctx, appealID, viewer
+func (_m *AppealService) ListAttachments(ctx context.Context, appealID uint, viewer string) ([]*domain.Attachment, error) {
+	ret := _m.Called(ctx, appealID, viewer)
+
+	var r0 []*domain.Attachment
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) []*domain.Attachment); ok {
+		r0 = rf(ctx, appealID, viewer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Attachment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, appealID, viewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MakeAction provides a mock function with given fields: _a0, _a1
+func (_m *AppealService) MakeAction(_a0 context.Context, _a1 domain.ApprovalAction) (*domain.Appeal, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ApprovalAction) *domain.Appeal); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ApprovalAction) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MakeActionByFilter provides a mock function with given fields: ctx, filters, approvalAction
+func (_m *AppealService) MakeActionByFilter(ctx context.Context, filters map[string]interface{}, approvalAction domain.ApprovalAction) ([]*domain.Appeal, error) {
+	ret := _m.Called(ctx, filters, approvalAction)
+
+	var r0 []*domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}, domain.ApprovalAction) []*domain.Appeal); ok {
+		r0 = rf(ctx, filters, approvalAction)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}, domain.ApprovalAction) error); ok {
+		r1 = rf(ctx, filters, approvalAction)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MakeGroupAction provides a mock function with given fields: ctx, groupID, approvalAction
+func (_m *AppealService) MakeGroupAction(ctx context.Context, groupID string, approvalAction domain.ApprovalAction) (*domain.GroupActionResult, error) {
+	ret := _m.Called(ctx, groupID, approvalAction)
+
+	var r0 *domain.GroupActionResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ApprovalAction) *domain.GroupActionResult); ok {
+		r0 = rf(ctx, groupID, approvalAction)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.GroupActionResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ApprovalAction) error); ok {
+		r1 = rf(ctx, groupID, approvalAction)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MuteReminders provides a mock function with given fields: ctx, appealID, actor, reason
+func (_m *AppealService) MuteReminders(ctx context.Context, appealID uint, actor string, reason string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, appealID, actor, reason)
+
+	var r0 *domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, string) *domain.Appeal); ok {
+		r0 = rf(ctx, appealID, actor, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, string) error); ok {
+		r1 = rf(ctx, appealID, actor, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PreviewRevoke provides a mock function with given fields: ctx, filters
+func (_m *AppealService) PreviewRevoke(ctx context.Context, filters map[string]interface{}) ([]*domain.Appeal, error) {
+	ret := _m.Called(ctx, filters)
+
+	var r0 []*domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) []*domain.Appeal); ok {
+		r0 = rf(ctx, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}) error); ok {
+		r1 = rf(ctx, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReassignDeadlockedAppeal provides a mock function with given fields: ctx, id, approvers
+func (_m *AppealService) ReassignDeadlockedAppeal(ctx context.Context, id uint, approvers []string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, id, approvers)
+
+	var r0 *domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []string) *domain.Appeal); ok {
+		r0 = rf(ctx, id, approvers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, []string) error); ok {
+		r1 = rf(ctx, id, approvers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReassignIdleApprovals provides a mock function with given fields: _a0
+func (_m *AppealService) ReassignIdleApprovals(_a0 context.Context) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemindPendingApprovers provides a mock function with given fields: ctx, idleFor
+func (_m *AppealService) RemindPendingApprovers(ctx context.Context, idleFor time.Duration) error {
+	ret := _m.Called(ctx, idleFor)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) error); ok {
+		r0 = rf(ctx, idleFor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolvePendingApprovers provides a mock function with given fields: _a0
+func (_m *AppealService) ResolvePendingApprovers(_a0 context.Context) error {
 	ret := _m.Called(_a0)
 
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Resubmit provides a mock function with given fields: ctx, id, user, comment
+func (_m *AppealService) Resubmit(ctx context.Context, id uint, user string, comment string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, id, user, comment)
+
+	var r0 *domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, string) *domain.Appeal); ok {
+		r0 = rf(ctx, id, user, comment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, string) error); ok {
+		r1 = rf(ctx, id, user, comment)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RetryPendingRevocations provides a mock function with given fields: ctx, force
+func (_m *AppealService) RetryPendingRevocations(ctx context.Context, force bool) error {
+	ret := _m.Called(ctx, force)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool) error); ok {
+		r0 = rf(ctx, force)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevertApproval provides a mock function with given fields: ctx, appealID, approvalName, actor
+func (_m *AppealService) RevertApproval(ctx context.Context, appealID uint, approvalName string, actor string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, appealID, approvalName, actor)
+
+	var r0 *domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, string) *domain.Appeal); ok {
+		r0 = rf(ctx, appealID, approvalName, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, string) error); ok {
+		r1 = rf(ctx, appealID, approvalName, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: ctx, id, actor, reason
+func (_m *AppealService) Revoke(ctx context.Context, id uint, actor string, reason string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, id, actor, reason)
+
 	var r0 *domain.Appeal
-	if rf, ok := ret.Get(0).(func(domain.ApprovalAction) *domain.Appeal); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, string) *domain.Appeal); ok {
+		r0 = rf(ctx, id, actor, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, string) error); ok {
+		r1 = rf(ctx, id, actor, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeByFilter provides a mock function with given fields: ctx, filters, actor, reason
+func (_m *AppealService) RevokeByFilter(ctx context.Context, filters map[string]interface{}, actor string, reason string) ([]*domain.Appeal, error) {
+	ret := _m.Called(ctx, filters, actor, reason)
+
+	var r0 []*domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}, string, string) []*domain.Appeal); ok {
+		r0 = rf(ctx, filters, actor, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Appeal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}, string, string) error); ok {
+		r1 = rf(ctx, filters, actor, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendPendingApprovalsDigest provides a mock function with given fields: _a0
+func (_m *AppealService) SendPendingApprovalsDigest(_a0 context.Context) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ToggleAccessWindows provides a mock function with given fields: _a0
+func (_m *AppealService) ToggleAccessWindows(_a0 context.Context) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
 		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UndoApproval provides a mock function with given fields: ctx, appealID, actor
+func (_m *AppealService) UndoApproval(ctx context.Context, appealID uint, actor string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, appealID, actor)
+
+	var r0 *domain.Appeal
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) *domain.Appeal); ok {
+		r0 = rf(ctx, appealID, actor)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Appeal)
@@ -109,8 +853,8 @@ func (_m *AppealService) MakeAction(_a0 domain.ApprovalAction) (*domain.Appeal,
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(domain.ApprovalAction) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, appealID, actor)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -118,13 +862,13 @@ func (_m *AppealService) MakeAction(_a0 domain.ApprovalAction) (*domain.Appeal,
 	return r0, r1
 }
 
-// Revoke provides a mock function with given fields: id, actor, reason
-func (_m *AppealService) Revoke(id uint, actor string, reason string) (*domain.Appeal, error) {
-	ret := _m.Called(id, actor, reason)
+// UnmuteReminders provides a mock function with given fields: ctx, appealID, actor
+func (_m *AppealService) UnmuteReminders(ctx context.Context, appealID uint, actor string) (*domain.Appeal, error) {
+	ret := _m.Called(ctx, appealID, actor)
 
 	var r0 *domain.Appeal
-	if rf, ok := ret.Get(0).(func(uint, string, string) *domain.Appeal); ok {
-		r0 = rf(id, actor, reason)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) *domain.Appeal); ok {
+		r0 = rf(ctx, appealID, actor)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Appeal)
@@ -132,8 +876,8 @@ func (_m *AppealService) Revoke(id uint, actor string, reason string) (*domain.A
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, string, string) error); ok {
-		r1 = rf(id, actor, reason)
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, appealID, actor)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	domain "github.com/odpf/guardian/domain"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,27 @@ type ApprovalRepository struct {
 	mock.Mock
 }
 
-// BulkInsert provides a mock function with given fields: _a0
-func (_m *ApprovalRepository) BulkInsert(_a0 []*domain.Approval) error {
-	ret := _m.Called(_a0)
+// AddApprover provides a mock function with given fields: _a0, _a1
+func (_m *ApprovalRepository) AddApprover(_a0 context.Context, _a1 *domain.Approver) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Approver) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BulkInsert provides a mock function with given fields: _a0, _a1
+func (_m *ApprovalRepository) BulkInsert(_a0 context.Context, _a1 []*domain.Approval) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]*domain.Approval) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Approval) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +42,59 @@ func (_m *ApprovalRepository) BulkInsert(_a0 []*domain.Approval) error {
 	return r0
 }
 
-// ListApprovals provides a mock function with given fields: _a0
-func (_m *ApprovalRepository) ListApprovals(_a0 *domain.ListApprovalsFilter) ([]*domain.Approval, error) {
-	ret := _m.Called(_a0)
+// GetApproverLoads provides a mock function with given fields: ctx, approvers
+func (_m *ApprovalRepository) GetApproverLoads(ctx context.Context, approvers []string) (map[string]*domain.ApproverLoad, error) {
+	ret := _m.Called(ctx, approvers)
+
+	var r0 map[string]*domain.ApproverLoad
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]*domain.ApproverLoad); ok {
+		r0 = rf(ctx, approvers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]*domain.ApproverLoad)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, approvers)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetApproverStats provides a mock function with given fields: ctx, approver, filter
+func (_m *ApprovalRepository) GetApproverStats(ctx context.Context, approver string, filter *domain.ApproverStatsFilter) (*domain.ApproverStats, error) {
+	ret := _m.Called(ctx, approver, filter)
+
+	var r0 *domain.ApproverStats
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.ApproverStatsFilter) *domain.ApproverStats); ok {
+		r0 = rf(ctx, approver, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ApproverStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.ApproverStatsFilter) error); ok {
+		r1 = rf(ctx, approver, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListApprovals provides a mock function with given fields: _a0, _a1
+func (_m *ApprovalRepository) ListApprovals(_a0 context.Context, _a1 *domain.ListApprovalsFilter) ([]*domain.Approval, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 []*domain.Approval
-	if rf, ok := ret.Get(0).(func(*domain.ListApprovalsFilter) []*domain.Approval); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListApprovalsFilter) []*domain.Approval); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Approval)
@@ -40,8 +102,8 @@ func (_m *ApprovalRepository) ListApprovals(_a0 *domain.ListApprovalsFilter) ([]
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*domain.ListApprovalsFilter) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ListApprovalsFilter) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
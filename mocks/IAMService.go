@@ -9,6 +9,29 @@ type IAMService struct {
 	mock.Mock
 }
 
+// GetManagerChain provides a mock function with given fields: user
+func (_m *IAMService) GetManagerChain(user string) ([]string, error) {
+	ret := _m.Called(user)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserApproverEmails provides a mock function with given fields: user
 func (_m *IAMService) GetUserApproverEmails(user string) ([]string, error) {
 	ret := _m.Called(user)
@@ -31,3 +54,68 @@ func (_m *IAMService) GetUserApproverEmails(user string) ([]string, error) {
 
 	return r0, r1
 }
+
+// GetUserGroups provides a mock function with given fields: user
+func (_m *IAMService) GetUserGroups(user string) ([]string, error) {
+	ret := _m.Called(user)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserTeam provides a mock function with given fields: user
+func (_m *IAMService) GetUserTeam(user string) (string, error) {
+	ret := _m.Called(user)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsUserAvailable provides a mock function with given fields: user
+func (_m *IAMService) IsUserAvailable(user string) (bool, error) {
+	ret := _m.Called(user)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	domain "github.com/odpf/guardian/domain"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,13 @@ type PolicyService struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: _a0
-func (_m *PolicyService) Create(_a0 *domain.Policy) error {
-	ret := _m.Called(_a0)
+// Create provides a mock function with given fields: _a0, _a1
+func (_m *PolicyService) Create(_a0 context.Context, _a1 *domain.Policy) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Policy) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Policy) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +28,13 @@ func (_m *PolicyService) Create(_a0 *domain.Policy) error {
 	return r0
 }
 
-// Find provides a mock function with given fields:
-func (_m *PolicyService) Find() ([]*domain.Policy, error) {
-	ret := _m.Called()
+// Find provides a mock function with given fields: _a0
+func (_m *PolicyService) Find(_a0 context.Context) ([]*domain.Policy, error) {
+	ret := _m.Called(_a0)
 
 	var r0 []*domain.Policy
-	if rf, ok := ret.Get(0).(func() []*domain.Policy); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Policy); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Policy)
@@ -40,8 +42,8 @@ func (_m *PolicyService) Find() ([]*domain.Policy, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -49,13 +51,13 @@ func (_m *PolicyService) Find() ([]*domain.Policy, error) {
 	return r0, r1
 }
 
-// GetOne provides a mock function with given fields: id, version
-func (_m *PolicyService) GetOne(id string, version uint) (*domain.Policy, error) {
-	ret := _m.Called(id, version)
+// GetOne provides a mock function with given fields: ctx, id, version
+func (_m *PolicyService) GetOne(ctx context.Context, id string, version uint) (*domain.Policy, error) {
+	ret := _m.Called(ctx, id, version)
 
 	var r0 *domain.Policy
-	if rf, ok := ret.Get(0).(func(string, uint) *domain.Policy); ok {
-		r0 = rf(id, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) *domain.Policy); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Policy)
@@ -63,8 +65,8 @@ func (_m *PolicyService) GetOne(id string, version uint) (*domain.Policy, error)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, uint) error); ok {
-		r1 = rf(id, version)
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint) error); ok {
+		r1 = rf(ctx, id, version)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -72,13 +74,13 @@ func (_m *PolicyService) GetOne(id string, version uint) (*domain.Policy, error)
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: _a0
-func (_m *PolicyService) Update(_a0 *domain.Policy) error {
-	ret := _m.Called(_a0)
+// Update provides a mock function with given fields: _a0, _a1
+func (_m *PolicyService) Update(_a0 context.Context, _a1 *domain.Policy) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Policy) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Policy) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	domain "github.com/odpf/guardian/domain"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,27 @@ type ProviderInterface struct {
 	mock.Mock
 }
 
-// CreateConfig provides a mock function with given fields: _a0
-func (_m *ProviderInterface) CreateConfig(_a0 *domain.ProviderConfig) error {
-	ret := _m.Called(_a0)
+// Capabilities provides a mock function with given fields:
+func (_m *ProviderInterface) Capabilities() domain.ProviderCapabilities {
+	ret := _m.Called()
+
+	var r0 domain.ProviderCapabilities
+	if rf, ok := ret.Get(0).(func() domain.ProviderCapabilities); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(domain.ProviderCapabilities)
+	}
+
+	return r0
+}
+
+// CreateConfig provides a mock function with given fields: _a0, _a1
+func (_m *ProviderInterface) CreateConfig(_a0 context.Context, _a1 *domain.ProviderConfig) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.ProviderConfig) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ProviderConfig) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +42,13 @@ func (_m *ProviderInterface) CreateConfig(_a0 *domain.ProviderConfig) error {
 	return r0
 }
 
-// GetResources provides a mock function with given fields: pc
-func (_m *ProviderInterface) GetResources(pc *domain.ProviderConfig) ([]*domain.Resource, error) {
-	ret := _m.Called(pc)
+// GetResources provides a mock function with given fields: ctx, pc
+func (_m *ProviderInterface) GetResources(ctx context.Context, pc *domain.ProviderConfig) ([]*domain.Resource, error) {
+	ret := _m.Called(ctx, pc)
 
 	var r0 []*domain.Resource
-	if rf, ok := ret.Get(0).(func(*domain.ProviderConfig) []*domain.Resource); ok {
-		r0 = rf(pc)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ProviderConfig) []*domain.Resource); ok {
+		r0 = rf(ctx, pc)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Resource)
@@ -40,8 +56,8 @@ func (_m *ProviderInterface) GetResources(pc *domain.ProviderConfig) ([]*domain.
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*domain.ProviderConfig) error); ok {
-		r1 = rf(pc)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ProviderConfig) error); ok {
+		r1 = rf(ctx, pc)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -63,13 +79,13 @@ func (_m *ProviderInterface) GetType() string {
 	return r0
 }
 
-// GrantAccess provides a mock function with given fields: _a0, _a1
-func (_m *ProviderInterface) GrantAccess(_a0 *domain.ProviderConfig, _a1 *domain.Appeal) error {
-	ret := _m.Called(_a0, _a1)
+// GrantAccess provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ProviderInterface) GrantAccess(_a0 context.Context, _a1 *domain.ProviderConfig, _a2 *domain.Appeal) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.ProviderConfig, *domain.Appeal) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ProviderConfig, *domain.Appeal) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -77,13 +93,13 @@ func (_m *ProviderInterface) GrantAccess(_a0 *domain.ProviderConfig, _a1 *domain
 	return r0
 }
 
-// RevokeAccess provides a mock function with given fields: _a0, _a1
-func (_m *ProviderInterface) RevokeAccess(_a0 *domain.ProviderConfig, _a1 *domain.Appeal) error {
-	ret := _m.Called(_a0, _a1)
+// RevokeAccess provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ProviderInterface) RevokeAccess(_a0 context.Context, _a1 *domain.ProviderConfig, _a2 *domain.Appeal) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.ProviderConfig, *domain.Appeal) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ProviderConfig, *domain.Appeal) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
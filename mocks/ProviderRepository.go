@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	domain "github.com/odpf/guardian/domain"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,13 @@ type ProviderRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: _a0
-func (_m *ProviderRepository) Create(_a0 *domain.Provider) error {
-	ret := _m.Called(_a0)
+// Create provides a mock function with given fields: _a0, _a1
+func (_m *ProviderRepository) Create(_a0 context.Context, _a1 *domain.Provider) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Provider) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Provider) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +28,13 @@ func (_m *ProviderRepository) Create(_a0 *domain.Provider) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: _a0
-func (_m *ProviderRepository) Delete(_a0 uint) error {
-	ret := _m.Called(_a0)
+// Delete provides a mock function with given fields: _a0, _a1
+func (_m *ProviderRepository) Delete(_a0 context.Context, _a1 uint) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -40,13 +42,13 @@ func (_m *ProviderRepository) Delete(_a0 uint) error {
 	return r0
 }
 
-// Find provides a mock function with given fields:
-func (_m *ProviderRepository) Find() ([]*domain.Provider, error) {
-	ret := _m.Called()
+// Find provides a mock function with given fields: _a0
+func (_m *ProviderRepository) Find(_a0 context.Context) ([]*domain.Provider, error) {
+	ret := _m.Called(_a0)
 
 	var r0 []*domain.Provider
-	if rf, ok := ret.Get(0).(func() []*domain.Provider); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Provider); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Provider)
@@ -54,8 +56,8 @@ func (_m *ProviderRepository) Find() ([]*domain.Provider, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -63,13 +65,13 @@ func (_m *ProviderRepository) Find() ([]*domain.Provider, error) {
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: _a0
-func (_m *ProviderRepository) GetByID(_a0 uint) (*domain.Provider, error) {
-	ret := _m.Called(_a0)
+// GetByID provides a mock function with given fields: _a0, _a1
+func (_m *ProviderRepository) GetByID(_a0 context.Context, _a1 uint) (*domain.Provider, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 *domain.Provider
-	if rf, ok := ret.Get(0).(func(uint) *domain.Provider); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domain.Provider); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Provider)
@@ -77,8 +79,8 @@ func (_m *ProviderRepository) GetByID(_a0 uint) (*domain.Provider, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -86,13 +88,13 @@ func (_m *ProviderRepository) GetByID(_a0 uint) (*domain.Provider, error) {
 	return r0, r1
 }
 
-// GetOne provides a mock function with given fields: pType, urn
-func (_m *ProviderRepository) GetOne(pType string, urn string) (*domain.Provider, error) {
-	ret := _m.Called(pType, urn)
+// GetOne provides a mock function with given fields: ctx, pType, urn
+func (_m *ProviderRepository) GetOne(ctx context.Context, pType string, urn string) (*domain.Provider, error) {
+	ret := _m.Called(ctx, pType, urn)
 
 	var r0 *domain.Provider
-	if rf, ok := ret.Get(0).(func(string, string) *domain.Provider); ok {
-		r0 = rf(pType, urn)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Provider); ok {
+		r0 = rf(ctx, pType, urn)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Provider)
@@ -100,8 +102,8 @@ func (_m *ProviderRepository) GetOne(pType string, urn string) (*domain.Provider
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(pType, urn)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, pType, urn)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -109,13 +111,13 @@ func (_m *ProviderRepository) GetOne(pType string, urn string) (*domain.Provider
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: _a0
-func (_m *ProviderRepository) Update(_a0 *domain.Provider) error {
-	ret := _m.Called(_a0)
+// Update provides a mock function with given fields: _a0, _a1
+func (_m *ProviderRepository) Update(_a0 context.Context, _a1 *domain.Provider) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Provider) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Provider) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
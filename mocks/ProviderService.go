@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	domain "github.com/odpf/guardian/domain"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,13 @@ type ProviderService struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: _a0
-func (_m *ProviderService) Create(_a0 *domain.Provider) error {
-	ret := _m.Called(_a0)
+// Create provides a mock function with given fields: _a0, _a1
+func (_m *ProviderService) Create(_a0 context.Context, _a1 *domain.Provider) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Provider) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Provider) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +28,13 @@ func (_m *ProviderService) Create(_a0 *domain.Provider) error {
 	return r0
 }
 
-// FetchResources provides a mock function with given fields:
-func (_m *ProviderService) FetchResources() error {
-	ret := _m.Called()
+// FetchResources provides a mock function with given fields: _a0
+func (_m *ProviderService) FetchResources(_a0 context.Context) error {
+	ret := _m.Called(_a0)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(_a0)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -40,13 +42,13 @@ func (_m *ProviderService) FetchResources() error {
 	return r0
 }
 
-// Find provides a mock function with given fields:
-func (_m *ProviderService) Find() ([]*domain.Provider, error) {
-	ret := _m.Called()
+// Find provides a mock function with given fields: _a0
+func (_m *ProviderService) Find(_a0 context.Context) ([]*domain.Provider, error) {
+	ret := _m.Called(_a0)
 
 	var r0 []*domain.Provider
-	if rf, ok := ret.Get(0).(func() []*domain.Provider); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Provider); ok {
+		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Provider)
@@ -54,8 +56,8 @@ func (_m *ProviderService) Find() ([]*domain.Provider, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -63,13 +65,103 @@ func (_m *ProviderService) Find() ([]*domain.Provider, error) {
 	return r0, r1
 }
 
-// GrantAccess provides a mock function with given fields: _a0
-func (_m *ProviderService) GrantAccess(_a0 *domain.Appeal) error {
-	ret := _m.Called(_a0)
+// FormatURN provides a mock function with given fields: providerType, urn
+func (_m *ProviderService) FormatURN(providerType string, urn *domain.ResourceURN) (string, error) {
+	ret := _m.Called(providerType, urn)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, *domain.ResourceURN) string); ok {
+		r0 = rf(providerType, urn)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *domain.ResourceURN) error); ok {
+		r1 = rf(providerType, urn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCapabilities provides a mock function with given fields: providerType
+func (_m *ProviderService) GetCapabilities(providerType string) (*domain.ProviderCapabilities, error) {
+	ret := _m.Called(providerType)
+
+	var r0 *domain.ProviderCapabilities
+	if rf, ok := ret.Get(0).(func(string) *domain.ProviderCapabilities); ok {
+		r0 = rf(providerType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ProviderCapabilities)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(providerType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRolePermissions provides a mock function with given fields: ctx, providerType, providerURN, resourceType, role
+func (_m *ProviderService) GetRolePermissions(ctx context.Context, providerType string, providerURN string, resourceType string, role string) ([]interface{}, error) {
+	ret := _m.Called(ctx, providerType, providerURN, resourceType, role)
+
+	var r0 []interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) []interface{}); ok {
+		r0 = rf(ctx, providerType, providerURN, resourceType, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, providerType, providerURN, resourceType, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeamQuota provides a mock function with given fields: ctx, providerType, providerURN, resourceType, resourceURN, role
+func (_m *ProviderService) GetTeamQuota(ctx context.Context, providerType string, providerURN string, resourceType string, resourceURN string, role string) (*domain.TeamQuotaConfig, error) {
+	ret := _m.Called(ctx, providerType, providerURN, resourceType, resourceURN, role)
+
+	var r0 *domain.TeamQuotaConfig
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string) *domain.TeamQuotaConfig); ok {
+		r0 = rf(ctx, providerType, providerURN, resourceType, resourceURN, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TeamQuotaConfig)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, string) error); ok {
+		r1 = rf(ctx, providerType, providerURN, resourceType, resourceURN, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantAccess provides a mock function with given fields: _a0, _a1
+func (_m *ProviderService) GrantAccess(_a0 context.Context, _a1 *domain.Appeal) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Appeal) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Appeal) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -77,13 +169,36 @@ func (_m *ProviderService) GrantAccess(_a0 *domain.Appeal) error {
 	return r0
 }
 
-// RevokeAccess provides a mock function with given fields: _a0
-func (_m *ProviderService) RevokeAccess(_a0 *domain.Appeal) error {
-	ret := _m.Called(_a0)
+// ParseURN provides a mock function with given fields: providerType, resourceType, urn
+func (_m *ProviderService) ParseURN(providerType string, resourceType string, urn string) (*domain.ResourceURN, error) {
+	ret := _m.Called(providerType, resourceType, urn)
+
+	var r0 *domain.ResourceURN
+	if rf, ok := ret.Get(0).(func(string, string, string) *domain.ResourceURN); ok {
+		r0 = rf(providerType, resourceType, urn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ResourceURN)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(providerType, resourceType, urn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAccess provides a mock function with given fields: _a0, _a1
+func (_m *ProviderService) RevokeAccess(_a0 context.Context, _a1 *domain.Appeal) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Appeal) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Appeal) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -91,16 +206,37 @@ func (_m *ProviderService) RevokeAccess(_a0 *domain.Appeal) error {
 	return r0
 }
 
-// Update provides a mock function with given fields: _a0
-func (_m *ProviderService) Update(_a0 *domain.Provider) error {
-	ret := _m.Called(_a0)
+// Update provides a mock function with given fields: _a0, _a1
+func (_m *ProviderService) Update(_a0 context.Context, _a1 *domain.Provider) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Provider) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Provider) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
+
+// VerifyAccess provides a mock function with given fields: _a0, _a1
+func (_m *ProviderService) VerifyAccess(_a0 context.Context, _a1 *domain.Appeal) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Appeal) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Appeal) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
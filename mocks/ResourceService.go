@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	domain "github.com/odpf/guardian/domain"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,13 @@ type ResourceService struct {
 	mock.Mock
 }
 
-// BulkUpsert provides a mock function with given fields: _a0
-func (_m *ResourceService) BulkUpsert(_a0 []*domain.Resource) error {
-	ret := _m.Called(_a0)
+// BulkUpsert provides a mock function with given fields: _a0, _a1
+func (_m *ResourceService) BulkUpsert(_a0 context.Context, _a1 []*domain.Resource) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]*domain.Resource) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Resource) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +28,13 @@ func (_m *ResourceService) BulkUpsert(_a0 []*domain.Resource) error {
 	return r0
 }
 
-// Find provides a mock function with given fields: filters
-func (_m *ResourceService) Find(filters map[string]interface{}) ([]*domain.Resource, error) {
-	ret := _m.Called(filters)
+// Find provides a mock function with given fields: ctx, filters
+func (_m *ResourceService) Find(ctx context.Context, filters map[string]interface{}) ([]*domain.Resource, error) {
+	ret := _m.Called(ctx, filters)
 
 	var r0 []*domain.Resource
-	if rf, ok := ret.Get(0).(func(map[string]interface{}) []*domain.Resource); ok {
-		r0 = rf(filters)
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) []*domain.Resource); ok {
+		r0 = rf(ctx, filters)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Resource)
@@ -40,8 +42,8 @@ func (_m *ResourceService) Find(filters map[string]interface{}) ([]*domain.Resou
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(map[string]interface{}) error); ok {
-		r1 = rf(filters)
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]interface{}) error); ok {
+		r1 = rf(ctx, filters)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -49,13 +51,27 @@ func (_m *ResourceService) Find(filters map[string]interface{}) ([]*domain.Resou
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: _a0
-func (_m *ResourceService) Update(_a0 *domain.Resource) error {
-	ret := _m.Called(_a0)
+// SetDenyAppeals provides a mock function with given fields: ctx, id, deny, reason, actor
+func (_m *ResourceService) SetDenyAppeals(ctx context.Context, id uint, deny bool, reason string, actor string) error {
+	ret := _m.Called(ctx, id, deny, reason, actor)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, bool, string, string) error); ok {
+		r0 = rf(ctx, id, deny, reason, actor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: _a0, _a1
+func (_m *ResourceService) Update(_a0 context.Context, _a1 *domain.Resource) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.Resource) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Resource) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
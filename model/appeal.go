@@ -11,19 +11,37 @@ import (
 
 // Appeal database model
 type Appeal struct {
-	ID            uint `gorm:"primaryKey"`
-	ResourceID    uint
-	PolicyID      string
-	PolicyVersion uint
-	Status        string
-	User          string
-	Role          string
-	Options       datatypes.JSON
-	Labels        datatypes.JSON
+	ID             uint `gorm:"primaryKey"`
+	ResourceID     uint `gorm:"index:idx_appeals_access"`
+	PolicyID       string
+	PolicyVersion  uint
+	Status         string
+	User           string `gorm:"index:idx_appeals_access"`
+	Role           string `gorm:"index:idx_appeals_access"`
+	Priority       string
+	OrganizationID string `gorm:"index"`
+	GroupID        string `gorm:"index"`
+	Options        datatypes.JSON
+	Labels         datatypes.JSON
 
 	RevokedBy    string
 	RevokedAt    time.Time
 	RevokeReason string
+	CanceledBy   string
+
+	RevocationAttempts int
+	RevocationError    string
+
+	AccessWindowClosed   bool
+	AccessWindowToggleAt *time.Time `gorm:"index"`
+	AccessScheduled      bool
+	UndoDeadline         *time.Time
+	Warnings             datatypes.JSON
+	Requested            datatypes.JSON
+
+	RemindersMutedBy     string
+	RemindersMutedAt     *time.Time
+	RemindersMutedReason string
 
 	Resource  *Resource `gorm:"ForeignKey:ResourceID;References:ID"`
 	Policy    Policy    `gorm:"ForeignKey:PolicyID,PolicyVersion;References:ID,Version"`
@@ -46,6 +64,16 @@ func (m *Appeal) FromDomain(a *domain.Appeal) error {
 		return err
 	}
 
+	warnings, err := json.Marshal(a.Warnings)
+	if err != nil {
+		return err
+	}
+
+	requested, err := json.Marshal(a.Requested)
+	if err != nil {
+		return err
+	}
+
 	var approvals []*Approval
 	if a.Approvals != nil {
 		for _, approval := range a.Approvals {
@@ -72,8 +100,26 @@ func (m *Appeal) FromDomain(a *domain.Appeal) error {
 	m.Status = a.Status
 	m.User = a.User
 	m.Role = a.Role
+	m.Priority = a.Priority
+	m.OrganizationID = a.OrganizationID
+	m.GroupID = a.GroupID
 	m.Options = datatypes.JSON(options)
 	m.Labels = datatypes.JSON(labels)
+	m.RevokedBy = a.RevokedBy
+	m.RevokedAt = a.RevokedAt
+	m.RevokeReason = a.RevokeReason
+	m.CanceledBy = a.CanceledBy
+	m.RevocationAttempts = a.RevocationAttempts
+	m.RevocationError = a.RevocationError
+	m.AccessWindowClosed = a.AccessWindowClosed
+	m.AccessWindowToggleAt = a.AccessWindowToggleAt
+	m.AccessScheduled = a.AccessScheduled
+	m.UndoDeadline = a.UndoDeadline
+	m.Warnings = datatypes.JSON(warnings)
+	m.Requested = datatypes.JSON(requested)
+	m.RemindersMutedBy = a.RemindersMutedBy
+	m.RemindersMutedAt = a.RemindersMutedAt
+	m.RemindersMutedReason = a.RemindersMutedReason
 	m.Approvals = approvals
 	m.CreatedAt = a.CreatedAt
 	m.UpdatedAt = a.UpdatedAt
@@ -95,6 +141,20 @@ func (m *Appeal) ToDomain() (*domain.Appeal, error) {
 		}
 	}
 
+	var warnings []*domain.AppealWarning
+	if m.Warnings != nil {
+		if err := json.Unmarshal(m.Warnings, &warnings); err != nil {
+			return nil, err
+		}
+	}
+
+	var requested *domain.GrantParameters
+	if m.Requested != nil {
+		if err := json.Unmarshal(m.Requested, &requested); err != nil {
+			return nil, err
+		}
+	}
+
 	var approvals []*domain.Approval
 	if m.Approvals != nil {
 		for _, a := range m.Approvals {
@@ -118,18 +178,36 @@ func (m *Appeal) ToDomain() (*domain.Appeal, error) {
 	}
 
 	return &domain.Appeal{
-		ID:            m.ID,
-		ResourceID:    m.ResourceID,
-		PolicyID:      m.PolicyID,
-		PolicyVersion: m.PolicyVersion,
-		Status:        m.Status,
-		User:          m.User,
-		Role:          m.Role,
-		Options:       options,
-		Labels:        labels,
-		Approvals:     approvals,
-		Resource:      resource,
-		CreatedAt:     m.CreatedAt,
-		UpdatedAt:     m.UpdatedAt,
+		ID:                   m.ID,
+		ResourceID:           m.ResourceID,
+		PolicyID:             m.PolicyID,
+		PolicyVersion:        m.PolicyVersion,
+		Status:               m.Status,
+		User:                 m.User,
+		Role:                 m.Role,
+		Priority:             m.Priority,
+		OrganizationID:       m.OrganizationID,
+		GroupID:              m.GroupID,
+		Options:              options,
+		Labels:               labels,
+		RevokedBy:            m.RevokedBy,
+		RevokedAt:            m.RevokedAt,
+		RevokeReason:         m.RevokeReason,
+		CanceledBy:           m.CanceledBy,
+		RevocationAttempts:   m.RevocationAttempts,
+		RevocationError:      m.RevocationError,
+		AccessWindowClosed:   m.AccessWindowClosed,
+		AccessWindowToggleAt: m.AccessWindowToggleAt,
+		AccessScheduled:      m.AccessScheduled,
+		UndoDeadline:         m.UndoDeadline,
+		Warnings:             warnings,
+		Requested:            requested,
+		RemindersMutedBy:     m.RemindersMutedBy,
+		RemindersMutedAt:     m.RemindersMutedAt,
+		RemindersMutedReason: m.RemindersMutedReason,
+		Approvals:            approvals,
+		Resource:             resource,
+		CreatedAt:            m.CreatedAt,
+		UpdatedAt:            m.UpdatedAt,
 	}, nil
 }
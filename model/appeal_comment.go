@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"gorm.io/gorm"
+)
+
+// AppealComment database model
+type AppealComment struct {
+	ID         uint `gorm:"primaryKey"`
+	AppealID   uint `gorm:"index"`
+	CreatedBy  string
+	Body       string
+	Visibility string
+
+	CreatedAt time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// FromDomain transforms *domain.AppealComment values into the model
+func (m *AppealComment) FromDomain(c *domain.AppealComment) error {
+	m.ID = c.ID
+	m.AppealID = c.AppealID
+	m.CreatedBy = c.CreatedBy
+	m.Body = c.Body
+	m.Visibility = c.Visibility
+	m.CreatedAt = c.CreatedAt
+	m.UpdatedAt = c.UpdatedAt
+
+	return nil
+}
+
+// ToDomain transforms model into *domain.AppealComment
+func (m *AppealComment) ToDomain() (*domain.AppealComment, error) {
+	return &domain.AppealComment{
+		ID:         m.ID,
+		AppealID:   m.AppealID,
+		CreatedBy:  m.CreatedBy,
+		Body:       m.Body,
+		Visibility: m.Visibility,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}, nil
+}
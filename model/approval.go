@@ -1,9 +1,11 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/odpf/guardian/domain"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +19,27 @@ type Approval struct {
 	Actor         *string
 	PolicyID      string
 	PolicyVersion uint
+	Reason        string
+	IsOverridden  bool
+	AddedBy       string
+
+	LastRemindedAt      *time.Time
+	ReminderLevel       int
+	Attestation         datatypes.JSON
+	NotificationChannel string
+	SLA                 string
+	SLAMet              *bool
+	AssignmentStrategy  string
+	ReassignAfter       string
+	RequireReason       string
+	DelegationChain     datatypes.JSON
+	OnBehalfOf          string
+	Assignee            string `gorm:"index"`
+	AssignedAt          *time.Time
+	AcknowledgedBy      string
+	AcknowledgedAt      *time.Time
+	StatusChangedAt     *time.Time
+	ChangesRequestedAt  *time.Time
 
 	Approvers []Approver
 	Appeal    *Appeal
@@ -47,6 +70,15 @@ func (m *Approval) FromDomain(a *domain.Approval) error {
 		m.Appeal = appealModel
 	}
 
+	var attestation datatypes.JSON
+	if a.Attestation != nil {
+		value, err := json.Marshal(a.Attestation)
+		if err != nil {
+			return err
+		}
+		attestation = datatypes.JSON(value)
+	}
+
 	m.ID = a.ID
 	m.Name = a.Name
 	m.Index = a.Index
@@ -55,6 +87,35 @@ func (m *Approval) FromDomain(a *domain.Approval) error {
 	m.Actor = a.Actor
 	m.PolicyID = a.PolicyID
 	m.PolicyVersion = a.PolicyVersion
+	var delegationChain datatypes.JSON
+	if a.DelegationChain != nil {
+		value, err := json.Marshal(a.DelegationChain)
+		if err != nil {
+			return err
+		}
+		delegationChain = datatypes.JSON(value)
+	}
+
+	m.Reason = a.Reason
+	m.IsOverridden = a.IsOverridden
+	m.AddedBy = a.AddedBy
+	m.LastRemindedAt = a.LastRemindedAt
+	m.ReminderLevel = a.ReminderLevel
+	m.Attestation = attestation
+	m.NotificationChannel = a.NotificationChannel
+	m.SLA = a.SLA
+	m.SLAMet = a.SLAMet
+	m.AssignmentStrategy = a.AssignmentStrategy
+	m.ReassignAfter = a.ReassignAfter
+	m.RequireReason = a.RequireReason
+	m.DelegationChain = delegationChain
+	m.OnBehalfOf = a.OnBehalfOf
+	m.Assignee = a.Assignee
+	m.AssignedAt = a.AssignedAt
+	m.AcknowledgedBy = a.AcknowledgedBy
+	m.AcknowledgedAt = a.AcknowledgedAt
+	m.StatusChangedAt = a.StatusChangedAt
+	m.ChangesRequestedAt = a.ChangesRequestedAt
 	m.Approvers = approvers
 	m.CreatedAt = a.CreatedAt
 	m.UpdatedAt = a.UpdatedAt
@@ -84,18 +145,52 @@ func (m *Approval) ToDomain() (*domain.Approval, error) {
 		appeal = a
 	}
 
+	var attestation *domain.ApprovalAttestation
+	if m.Attestation != nil {
+		if err := json.Unmarshal(m.Attestation, &attestation); err != nil {
+			return nil, err
+		}
+	}
+
+	var delegationChain *domain.DelegationChain
+	if m.DelegationChain != nil {
+		if err := json.Unmarshal(m.DelegationChain, &delegationChain); err != nil {
+			return nil, err
+		}
+	}
+
 	return &domain.Approval{
-		ID:            m.ID,
-		Name:          m.Name,
-		Index:         m.Index,
-		AppealID:      m.AppealID,
-		Status:        m.Status,
-		Actor:         m.Actor,
-		PolicyID:      m.PolicyID,
-		PolicyVersion: m.PolicyVersion,
-		Approvers:     approvers,
-		Appeal:        appeal,
-		CreatedAt:     m.CreatedAt,
-		UpdatedAt:     m.UpdatedAt,
+		ID:                  m.ID,
+		Name:                m.Name,
+		Index:               m.Index,
+		AppealID:            m.AppealID,
+		Status:              m.Status,
+		Actor:               m.Actor,
+		PolicyID:            m.PolicyID,
+		PolicyVersion:       m.PolicyVersion,
+		Reason:              m.Reason,
+		IsOverridden:        m.IsOverridden,
+		AddedBy:             m.AddedBy,
+		LastRemindedAt:      m.LastRemindedAt,
+		ReminderLevel:       m.ReminderLevel,
+		Attestation:         attestation,
+		NotificationChannel: m.NotificationChannel,
+		SLA:                 m.SLA,
+		SLAMet:              m.SLAMet,
+		AssignmentStrategy:  m.AssignmentStrategy,
+		ReassignAfter:       m.ReassignAfter,
+		RequireReason:       m.RequireReason,
+		DelegationChain:     delegationChain,
+		OnBehalfOf:          m.OnBehalfOf,
+		Assignee:            m.Assignee,
+		AssignedAt:          m.AssignedAt,
+		AcknowledgedBy:      m.AcknowledgedBy,
+		AcknowledgedAt:      m.AcknowledgedAt,
+		StatusChangedAt:     m.StatusChangedAt,
+		ChangesRequestedAt:  m.ChangesRequestedAt,
+		Approvers:           approvers,
+		Appeal:              appeal,
+		CreatedAt:           m.CreatedAt,
+		UpdatedAt:           m.UpdatedAt,
 	}, nil
 }
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// ApprovalDelegation database model
+type ApprovalDelegation struct {
+	ID             uint   `gorm:"primaryKey"`
+	Delegator      string `gorm:"index"`
+	Delegate       string `gorm:"index"`
+	StartTime      time.Time
+	EndTime        time.Time `gorm:"index"`
+	StartNotified  bool
+	OrganizationID string `gorm:"index"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// FromDomain transforms *domain.ApprovalDelegation values into the model
+func (m *ApprovalDelegation) FromDomain(d *domain.ApprovalDelegation) error {
+	m.ID = d.ID
+	m.Delegator = d.Delegator
+	m.Delegate = d.Delegate
+	m.StartTime = d.StartTime
+	m.EndTime = d.EndTime
+	m.StartNotified = d.StartNotified
+	m.OrganizationID = d.OrganizationID
+	m.CreatedAt = d.CreatedAt
+	m.UpdatedAt = d.UpdatedAt
+
+	return nil
+}
+
+// ToDomain transforms model into *domain.ApprovalDelegation
+func (m *ApprovalDelegation) ToDomain() (*domain.ApprovalDelegation, error) {
+	return &domain.ApprovalDelegation{
+		ID:             m.ID,
+		Delegator:      m.Delegator,
+		Delegate:       m.Delegate,
+		StartTime:      m.StartTime,
+		EndTime:        m.EndTime,
+		StartNotified:  m.StartNotified,
+		OrganizationID: m.OrganizationID,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"gorm.io/gorm"
+)
+
+// Attachment database model
+type Attachment struct {
+	ID          uint `gorm:"primaryKey"`
+	AppealID    uint `gorm:"index"`
+	Filename    string
+	ContentType string
+	Size        int64
+	StorageKey  string
+
+	CreatedAt time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// FromDomain transforms *domain.Attachment values into the model
+func (m *Attachment) FromDomain(a *domain.Attachment) error {
+	m.ID = a.ID
+	m.AppealID = a.AppealID
+	m.Filename = a.Filename
+	m.ContentType = a.ContentType
+	m.Size = a.Size
+	m.StorageKey = a.StorageKey
+	m.CreatedAt = a.CreatedAt
+
+	return nil
+}
+
+// ToDomain transforms model into *domain.Attachment
+func (m *Attachment) ToDomain() (*domain.Attachment, error) {
+	return &domain.Attachment{
+		ID:          m.ID,
+		AppealID:    m.AppealID,
+		Filename:    m.Filename,
+		ContentType: m.ContentType,
+		Size:        m.Size,
+		StorageKey:  m.StorageKey,
+		CreatedAt:   m.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// DeferredNotification database model
+type DeferredNotification struct {
+	ID        uint `gorm:"primaryKey"`
+	User      string
+	Message   string
+	Channel   string
+	DeliverAt time.Time `gorm:"index"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// FromDomain transforms *domain.DeferredNotification values into the model
+func (m *DeferredNotification) FromDomain(n *domain.DeferredNotification) error {
+	m.ID = n.ID
+	m.User = n.Notification.User
+	m.Message = n.Notification.Message
+	m.Channel = n.Notification.Channel
+	m.DeliverAt = n.DeliverAt
+	m.CreatedAt = n.CreatedAt
+
+	return nil
+}
+
+// ToDomain transforms model into *domain.DeferredNotification
+func (m *DeferredNotification) ToDomain() (*domain.DeferredNotification, error) {
+	return &domain.DeferredNotification{
+		ID: m.ID,
+		Notification: domain.Notification{
+			User:    m.User,
+			Message: m.Message,
+			Channel: m.Channel,
+		},
+		DeliverAt: m.DeliverAt,
+		CreatedAt: m.CreatedAt,
+	}, nil
+}
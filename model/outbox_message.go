@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"gorm.io/datatypes"
+)
+
+// OutboxMessage database model
+type OutboxMessage struct {
+	ID            uint `gorm:"primaryKey"`
+	StatusEvent   datatypes.JSON
+	Notifications datatypes.JSON
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+}
+
+// FromDomain transforms *domain.OutboxMessage values into the model
+func (m *OutboxMessage) FromDomain(o *domain.OutboxMessage) error {
+	if o.StatusEvent != nil {
+		statusEvent, err := json.Marshal(o.StatusEvent)
+		if err != nil {
+			return err
+		}
+		m.StatusEvent = datatypes.JSON(statusEvent)
+	}
+
+	notifications, err := json.Marshal(o.Notifications)
+	if err != nil {
+		return err
+	}
+
+	m.ID = o.ID
+	m.Notifications = datatypes.JSON(notifications)
+	m.CreatedAt = o.CreatedAt
+
+	return nil
+}
+
+// ToDomain transforms model into *domain.OutboxMessage
+func (m *OutboxMessage) ToDomain() (*domain.OutboxMessage, error) {
+	var statusEvent *domain.AppealStatusEvent
+	if m.StatusEvent != nil {
+		if err := json.Unmarshal(m.StatusEvent, &statusEvent); err != nil {
+			return nil, err
+		}
+	}
+
+	var notifications []domain.Notification
+	if m.Notifications != nil {
+		if err := json.Unmarshal(m.Notifications, &notifications); err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.OutboxMessage{
+		ID:            m.ID,
+		StatusEvent:   statusEvent,
+		Notifications: notifications,
+		CreatedAt:     m.CreatedAt,
+	}, nil
+}
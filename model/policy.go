@@ -11,14 +11,22 @@ import (
 
 // Policy is the database model for policy
 type Policy struct {
-	ID          string `gorm:"primaryKey"`
-	Version     uint   `gorm:"primaryKey"`
-	Description string
-	Steps       datatypes.JSON
-	Labels      datatypes.JSON
-	CreatedAt   time.Time      `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time      `gorm:"autoUpdateTime"`
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	ID                 string `gorm:"primaryKey"`
+	Version            uint   `gorm:"primaryKey"`
+	Description        string
+	Steps              datatypes.JSON
+	Labels             datatypes.JSON
+	ReminderEscalation datatypes.JSON
+	AutoCancel         datatypes.JSON
+	ApprovalValidity   string
+	SensitivityTiers   datatypes.JSON
+	// ApproverResolutionFallback is nullable, the policies created before the fallback have none
+	ApproverResolutionFallback datatypes.JSON
+	SeparationOfDuties         bool
+	OrganizationID             string         `gorm:"index"`
+	CreatedAt                  time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt                  time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt                  gorm.DeletedAt `gorm:"index"`
 }
 
 // TableName overrides the table name
@@ -38,11 +46,38 @@ func (m *Policy) FromDomain(p *domain.Policy) error {
 		return err
 	}
 
+	reminderEscalation, err := json.Marshal(p.ReminderEscalation)
+	if err != nil {
+		return err
+	}
+
+	autoCancel, err := json.Marshal(p.AutoCancel)
+	if err != nil {
+		return err
+	}
+
+	sensitivityTiers, err := json.Marshal(p.SensitivityTiers)
+	if err != nil {
+		return err
+	}
+
+	approverResolutionFallback, err := json.Marshal(p.ApproverResolutionFallback)
+	if err != nil {
+		return err
+	}
+
 	m.ID = p.ID
 	m.Version = p.Version
 	m.Description = p.Description
 	m.Steps = datatypes.JSON(steps)
 	m.Labels = datatypes.JSON(labels)
+	m.ReminderEscalation = datatypes.JSON(reminderEscalation)
+	m.AutoCancel = datatypes.JSON(autoCancel)
+	m.ApprovalValidity = p.ApprovalValidity
+	m.SensitivityTiers = datatypes.JSON(sensitivityTiers)
+	m.ApproverResolutionFallback = datatypes.JSON(approverResolutionFallback)
+	m.SeparationOfDuties = p.SeparationOfDuties
+	m.OrganizationID = p.OrganizationID
 	m.CreatedAt = p.CreatedAt
 	m.UpdatedAt = p.UpdatedAt
 
@@ -61,13 +96,49 @@ func (m *Policy) ToDomain() (*domain.Policy, error) {
 		return nil, err
 	}
 
+	var reminderEscalation []*domain.ReminderRung
+	if m.ReminderEscalation != nil {
+		if err := json.Unmarshal(m.ReminderEscalation, &reminderEscalation); err != nil {
+			return nil, err
+		}
+	}
+
+	var autoCancel *domain.AutoCancelConfig
+	if m.AutoCancel != nil {
+		if err := json.Unmarshal(m.AutoCancel, &autoCancel); err != nil {
+			return nil, err
+		}
+	}
+
+	var sensitivityTiers []*domain.SensitivityTier
+	if m.SensitivityTiers != nil {
+		if err := json.Unmarshal(m.SensitivityTiers, &sensitivityTiers); err != nil {
+			return nil, err
+		}
+	}
+
+	var approverResolutionFallback *domain.ApproverResolutionFallback
+	if m.ApproverResolutionFallback != nil {
+		if err := json.Unmarshal(m.ApproverResolutionFallback, &approverResolutionFallback); err != nil {
+			return nil, err
+		}
+	}
+
 	return &domain.Policy{
-		ID:          m.ID,
-		Version:     m.Version,
-		Description: m.Description,
-		Steps:       steps,
-		Labels:      labels,
-		CreatedAt:   m.CreatedAt,
-		UpdatedAt:   m.UpdatedAt,
+		ID:                 m.ID,
+		Version:            m.Version,
+		Description:        m.Description,
+		Steps:              steps,
+		Labels:             labels,
+		ReminderEscalation: reminderEscalation,
+		AutoCancel:         autoCancel,
+		ApprovalValidity:   m.ApprovalValidity,
+		SensitivityTiers:   sensitivityTiers,
+
+		ApproverResolutionFallback: approverResolutionFallback,
+		SeparationOfDuties:         m.SeparationOfDuties,
+		OrganizationID:             m.OrganizationID,
+		CreatedAt:                  m.CreatedAt,
+		UpdatedAt:                  m.UpdatedAt,
 	}, nil
 }
@@ -11,13 +11,14 @@ import (
 
 // Provider is the database model for provider
 type Provider struct {
-	ID        uint   `gorm:"autoIncrement;uniqueIndex"`
-	Type      string `gorm:"primaryKey"`
-	URN       string `gorm:"primaryKey"`
-	Config    datatypes.JSON
-	CreatedAt time.Time      `gorm:"autoCreateTime"`
-	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID             uint   `gorm:"autoIncrement;uniqueIndex"`
+	Type           string `gorm:"primaryKey"`
+	URN            string `gorm:"primaryKey"`
+	Config         datatypes.JSON
+	OrganizationID string         `gorm:"index"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
 }
 
 // TableName overrides the table name
@@ -36,6 +37,7 @@ func (m *Provider) FromDomain(p *domain.Provider) error {
 	m.Type = p.Type
 	m.URN = p.URN
 	m.Config = datatypes.JSON(config)
+	m.OrganizationID = p.OrganizationID
 	m.CreatedAt = p.CreatedAt
 	m.UpdatedAt = p.UpdatedAt
 
@@ -50,11 +52,12 @@ func (m *Provider) ToDomain() (*domain.Provider, error) {
 	}
 
 	return &domain.Provider{
-		ID:        m.ID,
-		Type:      m.Type,
-		URN:       m.URN,
-		Config:    config,
-		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
+		ID:             m.ID,
+		Type:           m.Type,
+		URN:            m.URN,
+		Config:         config,
+		OrganizationID: m.OrganizationID,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
 	}, nil
 }
@@ -19,6 +19,11 @@ type Resource struct {
 	Name         string
 	Details      datatypes.JSON
 	Labels       datatypes.JSON
+	Tags         datatypes.JSON
+
+	DenyAppeals       bool
+	DenyAppealsReason string
+	OrganizationID    string `gorm:"index"`
 
 	Provider Provider `gorm:"ForeignKey:ProviderType,ProviderURN;References:Type,URN"`
 
@@ -52,6 +57,17 @@ func (m *Resource) FromDomain(r *domain.Resource) error {
 	m.Name = r.Name
 	m.Details = datatypes.JSON(details)
 	m.Labels = datatypes.JSON(labels)
+	// the tags are left as is on updates without tags, they're maintained by the resource sync
+	if r.Tags != nil {
+		tags, err := json.Marshal(r.Tags)
+		if err != nil {
+			return err
+		}
+		m.Tags = datatypes.JSON(tags)
+	}
+	m.DenyAppeals = r.DenyAppeals
+	m.DenyAppealsReason = r.DenyAppealsReason
+	m.OrganizationID = r.OrganizationID
 	m.CreatedAt = r.CreatedAt
 	m.UpdatedAt = r.UpdatedAt
 
@@ -70,16 +86,27 @@ func (m *Resource) ToDomain() (*domain.Resource, error) {
 		return nil, err
 	}
 
+	var tags map[string]string
+	if m.Tags != nil {
+		if err := json.Unmarshal(m.Tags, &tags); err != nil {
+			return nil, err
+		}
+	}
+
 	return &domain.Resource{
-		ID:           m.ID,
-		ProviderType: m.ProviderType,
-		ProviderURN:  m.ProviderURN,
-		Type:         m.Type,
-		URN:          m.URN,
-		Name:         m.Name,
-		Details:      details,
-		Labels:       labels,
-		CreatedAt:    m.CreatedAt,
-		UpdatedAt:    m.UpdatedAt,
+		ID:                m.ID,
+		ProviderType:      m.ProviderType,
+		ProviderURN:       m.ProviderURN,
+		Type:              m.Type,
+		URN:               m.URN,
+		Name:              m.Name,
+		Details:           details,
+		Labels:            labels,
+		Tags:              tags,
+		DenyAppeals:       m.DenyAppeals,
+		DenyAppealsReason: m.DenyAppealsReason,
+		OrganizationID:    m.OrganizationID,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
 	}, nil
 }
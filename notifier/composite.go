@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// ChannelAll is the channel registered in the server sending the notifications through every other channel
+const ChannelAll = "all"
+
+// NotifyError lists the errors of the notifiers that failed to send the notifications
+type NotifyError struct {
+	Errors []error
+}
+
+func (e *NotifyError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("failed to send notifications through %d notifier(s): %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+type composite struct {
+	notifiers []domain.Notifier
+}
+
+// NewComposite returns a notifier that sends every notification through all of the notifiers. A failing
+// notifier doesn't prevent the others from being called, the failures are returned as a *NotifyError
+func NewComposite(notifiers ...domain.Notifier) *composite {
+	return &composite{notifiers}
+}
+
+func (c *composite) Notify(items []domain.Notification) error {
+	var errs []error
+	for _, n := range c.notifiers {
+		if err := n.Notify(items); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &NotifyError{Errors: errs}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package notifier_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/odpf/guardian/notifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposite(t *testing.T) {
+	notifications := []domain.Notification{
+		{User: "user@email.com", Message: "message"},
+	}
+
+	t.Run("should send the notifications through every notifier", func(t *testing.T) {
+		slack := new(mocks.Notifier)
+		email := new(mocks.Notifier)
+		slack.On("Notify", notifications).Return(nil).Once()
+		email.On("Notify", notifications).Return(nil).Once()
+
+		assert.Nil(t, notifier.NewComposite(slack, email).Notify(notifications))
+		slack.AssertExpectations(t)
+		email.AssertExpectations(t)
+	})
+
+	t.Run("should keep notifying the next notifiers and return the aggregated errors", func(t *testing.T) {
+		slack := new(mocks.Notifier)
+		email := new(mocks.Notifier)
+		webhook := new(mocks.Notifier)
+		slackErr := errors.New("slack error")
+		webhookErr := errors.New("webhook error")
+		slack.On("Notify", notifications).Return(slackErr).Once()
+		email.On("Notify", notifications).Return(nil).Once()
+		webhook.On("Notify", notifications).Return(webhookErr).Once()
+
+		err := notifier.NewComposite(slack, email, webhook).Notify(notifications)
+
+		var notifyErr *notifier.NotifyError
+		assert.True(t, errors.As(err, &notifyErr))
+		assert.Equal(t, []error{slackErr, webhookErr}, notifyErr.Errors)
+		email.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidLabelRoutingRule = errors.New("invalid label routing rule")
+
+// LabelRoutingRule routes the notifications about the appeals having all of the labels through the channel,
+// e.g. the appeals labeled team: finance through the slack channel of the finance team
+type LabelRoutingRule struct {
+	// Name identifies the rule in the logs
+	Name    string            `mapstructure:"name"`
+	Labels  map[string]string `mapstructure:"labels"`
+	Channel string            `mapstructure:"channel"`
+}
+
+func (r LabelRoutingRule) matches(labels map[string]string) bool {
+	for key, value := range r.Labels {
+		if v, ok := labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+type labelRouter struct {
+	next   domain.Notifier
+	rules  []LabelRoutingRule
+	logger *zap.Logger
+}
+
+// NewLabelRouter returns a notifier that hints each notification to the channel of the first rule matching its
+// labels, in the order of the rules, then sends it through next. The notifications already hinted to a channel keep
+// it, and the ones matching no rule are left to the default channel of the router. The rules are validated against
+// the channels registered in the router
+func NewLabelRouter(next domain.Notifier, rules []LabelRoutingRule, channels map[string]domain.Notifier, logger *zap.Logger) (*labelRouter, error) {
+	for i, r := range rules {
+		if len(r.Labels) == 0 || r.Channel == "" {
+			return nil, fmt.Errorf("%w: rule %d %q requires labels and a channel", ErrInvalidLabelRoutingRule, i, r.Name)
+		}
+		if channels[r.Channel] == nil {
+			return nil, fmt.Errorf("%w: %q of rule %d %q", ErrChannelNotFound, r.Channel, i, r.Name)
+		}
+	}
+	return &labelRouter{next, rules, logger}, nil
+}
+
+func (r *labelRouter) Notify(items []domain.Notification) error {
+	routed := make([]domain.Notification, len(items))
+	for i, item := range items {
+		routed[i] = item
+		if item.Channel != "" || len(item.Labels) == 0 {
+			continue
+		}
+
+		rule := r.match(item.Labels)
+		if rule == nil {
+			r.logger.Debug("no label routing rule matched the notification",
+				zap.String("user", item.User),
+				zap.Any("labels", item.Labels),
+			)
+			continue
+		}
+		routed[i].Channel = rule.Channel
+		r.logger.Debug("notification routed by the labels",
+			zap.String("rule", rule.Name),
+			zap.String("channel", rule.Channel),
+			zap.String("user", item.User),
+			zap.Any("labels", item.Labels),
+		)
+	}
+
+	return r.next.Notify(routed)
+}
+
+// match returns the first rule matching the labels, or nil if none does
+func (r *labelRouter) match(labels map[string]string) *LabelRoutingRule {
+	for i := range r.rules {
+		if r.rules[i].matches(labels) {
+			return &r.rules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package notifier_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/odpf/guardian/notifier"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestLabelRouter(t *testing.T) {
+	channels := map[string]domain.Notifier{
+		notifier.ChannelSlack: new(mocks.Notifier),
+		"finance":             new(mocks.Notifier),
+		"finance-oncall":      new(mocks.Notifier),
+	}
+
+	t.Run("should return error if the rule's channel is not registered", func(t *testing.T) {
+		_, err := notifier.NewLabelRouter(new(mocks.Notifier), []notifier.LabelRoutingRule{
+			{Name: "finance", Labels: map[string]string{"team": "finance"}, Channel: "unregistered"},
+		}, channels, zap.NewNop())
+
+		assert.True(t, errors.Is(err, notifier.ErrChannelNotFound))
+	})
+
+	t.Run("should return error if the rule has no labels", func(t *testing.T) {
+		_, err := notifier.NewLabelRouter(new(mocks.Notifier), []notifier.LabelRoutingRule{
+			{Name: "everything", Channel: "finance"},
+		}, channels, zap.NewNop())
+
+		assert.True(t, errors.Is(err, notifier.ErrInvalidLabelRoutingRule))
+	})
+
+	t.Run("should hint the notifications to the channel of the first matching rule", func(t *testing.T) {
+		next := new(mocks.Notifier)
+		r, err := notifier.NewLabelRouter(next, []notifier.LabelRoutingRule{
+			{Name: "finance-critical", Labels: map[string]string{"team": "finance", "tier": "critical"}, Channel: "finance-oncall"},
+			{Name: "finance", Labels: map[string]string{"team": "finance"}, Channel: "finance"},
+			{Name: "finance-critical-unreachable", Labels: map[string]string{"tier": "critical"}, Channel: notifier.ChannelSlack},
+		}, channels, zap.NewNop())
+		assert.Nil(t, err)
+
+		notifications := []domain.Notification{
+			{User: "a@email.com", Message: "message", Labels: map[string]string{"team": "finance", "tier": "critical"}},
+			{User: "b@email.com", Message: "message", Labels: map[string]string{"team": "finance"}},
+			{User: "c@email.com", Message: "message", Labels: map[string]string{"team": "marketing"}},
+			{User: "d@email.com", Message: "message", Labels: map[string]string{"team": "finance"}, Channel: "pager"},
+			{User: "e@email.com", Message: "message"},
+		}
+		expectedNotifications := []domain.Notification{
+			{User: "a@email.com", Message: "message", Labels: map[string]string{"team": "finance", "tier": "critical"}, Channel: "finance-oncall"},
+			{User: "b@email.com", Message: "message", Labels: map[string]string{"team": "finance"}, Channel: "finance"},
+			{User: "c@email.com", Message: "message", Labels: map[string]string{"team": "marketing"}},
+			{User: "d@email.com", Message: "message", Labels: map[string]string{"team": "finance"}, Channel: "pager"},
+			{User: "e@email.com", Message: "message"},
+		}
+		next.On("Notify", expectedNotifications).Return(nil).Once()
+
+		assert.Nil(t, r.Notify(notifications))
+		next.AssertExpectations(t)
+		assert.Empty(t, notifications[1].Channel, "the given notifications should not be modified")
+	})
+}
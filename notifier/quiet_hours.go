@@ -0,0 +1,198 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+var ErrInvalidQuietHours = errors.New("invalid quiet hours config")
+
+// QuietHoursConfig holds back the non-critical notifications during the quiet hours of the users until the
+// quiet hours end
+type QuietHoursConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Start and End are the local times of day the quiet hours start and end, e.g. "22:00" and "08:00". The quiet
+	// hours span midnight if the end is earlier than the start
+	Start string `mapstructure:"start" default:"22:00"`
+	End   string `mapstructure:"end" default:"08:00"`
+	// SkipWeekends keeps the whole saturday and sunday quiet
+	SkipWeekends bool `mapstructure:"skip_weekends"`
+	// Timezone is the IANA timezone of the users without their own timezone, e.g. "Asia/Jakarta"
+	Timezone string `mapstructure:"timezone" default:"UTC"`
+	// Users overrides the timezone of the users working from another timezone
+	Users []UserTimezone `mapstructure:"users"`
+}
+
+// UserTimezone is the IANA timezone of a user
+type UserTimezone struct {
+	User     string `mapstructure:"user"`
+	Timezone string `mapstructure:"timezone"`
+}
+
+// QuietHoursNotifier defers the notifications sent during the quiet hours of their users, the deferred
+// notifications are sent by Flush once the quiet hours end
+type QuietHoursNotifier struct {
+	next domain.Notifier
+	repo domain.DeferredNotificationRepository
+
+	start, end    time.Duration
+	skipWeekends  bool
+	location      *time.Location
+	userLocations map[string]*time.Location
+
+	TimeNow func() time.Time
+}
+
+// NewQuietHoursNotifier returns the notifier deferring the notifications sent through next
+func NewQuietHoursNotifier(next domain.Notifier, repo domain.DeferredNotificationRepository, c QuietHoursConfig) (*QuietHoursNotifier, error) {
+	start, err := parseTimeOfDay(c.Start)
+	if err != nil {
+		return nil, fmt.Errorf("%w: start: %v", ErrInvalidQuietHours, err)
+	}
+	end, err := parseTimeOfDay(c.End)
+	if err != nil {
+		return nil, fmt.Errorf("%w: end: %v", ErrInvalidQuietHours, err)
+	}
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuietHours, err)
+	}
+	userLocations := map[string]*time.Location{}
+	for _, u := range c.Users {
+		l, err := time.LoadLocation(u.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("%w: timezone of %q: %v", ErrInvalidQuietHours, u.User, err)
+		}
+		userLocations[u.User] = l
+	}
+
+	return &QuietHoursNotifier{
+		next:          next,
+		repo:          repo,
+		start:         start,
+		end:           end,
+		skipWeekends:  c.SkipWeekends,
+		location:      location,
+		userLocations: userLocations,
+		TimeNow:       time.Now,
+	}, nil
+}
+
+// Notify sends the critical notifications and the ones outside of the quiet hours right away, the others
+// are stored to be sent once the quiet hours of their users end. A notification is sent right away if it
+// can't be deferred so it isn't lost
+func (n *QuietHoursNotifier) Notify(items []domain.Notification) error {
+	now := n.TimeNow()
+	immediate := []domain.Notification{}
+	deferred := []*domain.DeferredNotification{}
+	for _, item := range items {
+		deliverAt := n.getNextAllowedTime(item.User, now)
+		if item.Critical || !deliverAt.After(now) {
+			immediate = append(immediate, item)
+			continue
+		}
+		deferred = append(deferred, &domain.DeferredNotification{
+			Notification: item,
+			DeliverAt:    deliverAt.UTC(),
+		})
+	}
+
+	var errs []error
+	if err := n.repo.BulkInsert(context.Background(), deferred); err != nil {
+		errs = append(errs, fmt.Errorf("deferring notifications: %w", err))
+		for _, d := range deferred {
+			immediate = append(immediate, d.Notification)
+		}
+	}
+	if len(immediate) > 0 {
+		if err := n.next.Notify(immediate); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &NotifyError{Errors: errs}
+	}
+	return nil
+}
+
+// Flush sends the deferred notifications whose quiet hours have ended. The notifications failed to be sent
+// are kept to be retried on the next flush
+func (n *QuietHoursNotifier) Flush() error {
+	ctx := context.Background()
+	due, err := n.repo.GetDue(ctx, n.TimeNow())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	sentIDs := []uint{}
+	for _, d := range due {
+		if err := n.next.Notify([]domain.Notification{d.Notification}); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sentIDs = append(sentIDs, d.ID)
+	}
+	if err := n.repo.Delete(ctx, sentIDs); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return &NotifyError{Errors: errs}
+	}
+	return nil
+}
+
+// getNextAllowedTime returns the time the notifications can be sent to the user, which is the given time
+// itself if it's outside of the quiet hours of the user
+func (n *QuietHoursNotifier) getNextAllowedTime(user string, now time.Time) time.Time {
+	location := n.location
+	if l, exists := n.userLocations[user]; exists {
+		location = l
+	}
+
+	t := now.In(location)
+	// a week is enough to get out of any quiet period
+	for i := 0; i < 14; i++ {
+		year, month, day := t.Date()
+		midnight := time.Date(year, month, day, 0, 0, 0, 0, location)
+		if n.skipWeekends && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, location)
+			continue
+		}
+		if n.isQuiet(t.Sub(midnight)) {
+			end := midnight.Add(n.end)
+			if !end.After(t) {
+				end = time.Date(year, month, day+1, 0, 0, 0, 0, location).Add(n.end)
+			}
+			t = end
+			continue
+		}
+		return t
+	}
+	return t
+}
+
+// isQuiet checks whether the time of day is within the quiet hours
+func (n *QuietHoursNotifier) isQuiet(timeOfDay time.Duration) bool {
+	if n.start == n.end {
+		return false
+	}
+	if n.start < n.end {
+		return timeOfDay >= n.start && timeOfDay < n.end
+	}
+	return timeOfDay >= n.start || timeOfDay < n.end
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,130 @@
+package notifier_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/odpf/guardian/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQuietHoursNotifier(t *testing.T) {
+	config := notifier.QuietHoursConfig{
+		Enabled:      true,
+		Start:        "22:00",
+		End:          "08:00",
+		SkipWeekends: true,
+		Timezone:     "UTC",
+		Users: []notifier.UserTimezone{
+			{User: "jakarta@email.com", Timezone: "Asia/Jakarta"},
+		},
+	}
+	newNotifier := func(t *testing.T, now time.Time) (*notifier.QuietHoursNotifier, *mocks.Notifier, *mocks.DeferredNotificationRepository) {
+		next := new(mocks.Notifier)
+		repo := new(mocks.DeferredNotificationRepository)
+		n, err := notifier.NewQuietHoursNotifier(next, repo, config)
+		assert.Nil(t, err)
+		n.TimeNow = func() time.Time { return now }
+		return n, next, repo
+	}
+
+	t.Run("should return error if the config is invalid", func(t *testing.T) {
+		testCases := []notifier.QuietHoursConfig{
+			{Start: "10pm", End: "08:00", Timezone: "UTC"},
+			{Start: "22:00", End: "25:00", Timezone: "UTC"},
+			{Start: "22:00", End: "08:00", Timezone: "Mars/Olympus"},
+			{Start: "22:00", End: "08:00", Timezone: "UTC", Users: []notifier.UserTimezone{{User: "user@email.com", Timezone: "invalid"}}},
+		}
+		for _, tc := range testCases {
+			_, err := notifier.NewQuietHoursNotifier(new(mocks.Notifier), new(mocks.DeferredNotificationRepository), tc)
+
+			assert.ErrorIs(t, err, notifier.ErrInvalidQuietHours)
+		}
+	})
+
+	t.Run("should send the notifications outside of the quiet hours right away", func(t *testing.T) {
+		// wednesday 10am UTC, 5pm in Jakarta
+		n, next, repo := newNotifier(t, time.Date(2022, 1, 5, 10, 0, 0, 0, time.UTC))
+		items := []domain.Notification{
+			{User: "user@email.com", Message: "message"},
+			{User: "jakarta@email.com", Message: "message"},
+		}
+		repo.On("BulkInsert", mock.Anything, []*domain.DeferredNotification{}).Return(nil).Once()
+		next.On("Notify", items).Return(nil).Once()
+
+		assert.Nil(t, n.Notify(items))
+		next.AssertExpectations(t)
+	})
+
+	t.Run("should defer the notifications until the quiet hours of each user end", func(t *testing.T) {
+		// wednesday 11pm UTC, 6am thursday in Jakarta
+		n, next, repo := newNotifier(t, time.Date(2022, 1, 5, 23, 0, 0, 0, time.UTC))
+		revocation := domain.Notification{User: "user@email.com", Message: "revoked", Critical: true}
+		reminder := domain.Notification{User: "user@email.com", Message: "reminder"}
+		jakartaReminder := domain.Notification{User: "jakarta@email.com", Message: "reminder"}
+		repo.On("BulkInsert", mock.Anything, []*domain.DeferredNotification{
+			{Notification: reminder, DeliverAt: time.Date(2022, 1, 6, 8, 0, 0, 0, time.UTC)},
+			{Notification: jakartaReminder, DeliverAt: time.Date(2022, 1, 6, 1, 0, 0, 0, time.UTC)},
+		}).Return(nil).Once()
+		next.On("Notify", []domain.Notification{revocation}).Return(nil).Once()
+
+		assert.Nil(t, n.Notify([]domain.Notification{revocation, reminder, jakartaReminder}))
+		repo.AssertExpectations(t)
+		next.AssertExpectations(t)
+	})
+
+	t.Run("should defer the notifications on weekends until monday", func(t *testing.T) {
+		// saturday noon UTC
+		n, next, repo := newNotifier(t, time.Date(2022, 1, 8, 12, 0, 0, 0, time.UTC))
+		item := domain.Notification{User: "user@email.com", Message: "message"}
+		repo.On("BulkInsert", mock.Anything, []*domain.DeferredNotification{
+			{Notification: item, DeliverAt: time.Date(2022, 1, 10, 8, 0, 0, 0, time.UTC)},
+		}).Return(nil).Once()
+
+		assert.Nil(t, n.Notify([]domain.Notification{item}))
+		repo.AssertExpectations(t)
+		next.AssertNotCalled(t, "Notify", mock.Anything)
+	})
+
+	t.Run("should send the notifications right away if they can't be deferred", func(t *testing.T) {
+		n, next, repo := newNotifier(t, time.Date(2022, 1, 5, 23, 0, 0, 0, time.UTC))
+		item := domain.Notification{User: "user@email.com", Message: "message"}
+		repoErr := errors.New("db error")
+		repo.On("BulkInsert", mock.Anything, mock.Anything).Return(repoErr).Once()
+		next.On("Notify", []domain.Notification{item}).Return(nil).Once()
+
+		err := n.Notify([]domain.Notification{item})
+
+		var actualErr *notifier.NotifyError
+		assert.True(t, errors.As(err, &actualErr))
+		assert.Len(t, actualErr.Errors, 1)
+		assert.ErrorIs(t, actualErr.Errors[0], repoErr)
+		next.AssertExpectations(t)
+	})
+
+	t.Run("should flush the due notifications and keep the failed ones", func(t *testing.T) {
+		now := time.Date(2022, 1, 6, 8, 0, 0, 0, time.UTC)
+		n, next, repo := newNotifier(t, now)
+		sent := domain.Notification{User: "user@email.com", Message: "sent"}
+		failed := domain.Notification{User: "another@email.com", Message: "failed"}
+		notifyErr := errors.New("slack error")
+		repo.On("GetDue", mock.Anything, now).Return([]*domain.DeferredNotification{
+			{ID: 1, Notification: sent},
+			{ID: 2, Notification: failed},
+		}, nil).Once()
+		next.On("Notify", []domain.Notification{sent}).Return(nil).Once()
+		next.On("Notify", []domain.Notification{failed}).Return(notifyErr).Once()
+		repo.On("Delete", mock.Anything, []uint{1}).Return(nil).Once()
+
+		err := n.Flush()
+
+		var actualErr *notifier.NotifyError
+		assert.True(t, errors.As(err, &actualErr))
+		assert.Equal(t, []error{notifyErr}, actualErr.Errors)
+		repo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/model"
+	"gorm.io/gorm"
+)
+
+// Repository talks to the store to read or insert the deferred notifications
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository returns *Repository
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db}
+}
+
+// BulkInsert stores the deferred notifications
+func (r *Repository) BulkInsert(ctx context.Context, notifications []*domain.DeferredNotification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	models := []*model.DeferredNotification{}
+	for _, n := range notifications {
+		m := new(model.DeferredNotification)
+		if err := m.FromDomain(n); err != nil {
+			return err
+		}
+		models = append(models, m)
+	}
+
+	if err := r.db.WithContext(ctx).Create(models).Error; err != nil {
+		return err
+	}
+
+	for i, m := range models {
+		n, err := m.ToDomain()
+		if err != nil {
+			return err
+		}
+		*notifications[i] = *n
+	}
+
+	return nil
+}
+
+// GetDue returns the deferred notifications to be delivered at or before the given time, the earliest first
+func (r *Repository) GetDue(ctx context.Context, now time.Time) ([]*domain.DeferredNotification, error) {
+	var models []*model.DeferredNotification
+	if err := r.db.WithContext(ctx).
+		Where(`"deliver_at" <= ?`, now).
+		Order("deliver_at").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	records := []*domain.DeferredNotification{}
+	for _, m := range models {
+		n, err := m.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, n)
+	}
+
+	return records, nil
+}
+
+// Delete removes the delivered notifications
+func (r *Repository) Delete(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&model.DeferredNotification{}).Error
+}
@@ -0,0 +1,109 @@
+package notifier_test
+
+import (
+	"context"
+
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/odpf/guardian/notifier"
+	"github.com/odpf/guardian/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type RepositoryTestSuite struct {
+	suite.Suite
+	sqldb      *sql.DB
+	dbmock     sqlmock.Sqlmock
+	repository *notifier.Repository
+}
+
+func (s *RepositoryTestSuite) SetupTest() {
+	db, mock, _ := mocks.NewStore()
+	s.sqldb, _ = db.DB()
+	s.dbmock = mock
+	s.repository = notifier.NewRepository(db)
+}
+
+func (s *RepositoryTestSuite) TearDownTest() {
+	s.sqldb.Close()
+}
+
+func (s *RepositoryTestSuite) TestBulkInsert() {
+	s.Run("should skip the query if there is nothing to insert", func() {
+		s.Nil(s.repository.BulkInsert(context.Background(), []*domain.DeferredNotification{}))
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+
+	s.Run("should insert the notifications and return their ids", func() {
+		deliverAt := time.Date(2022, 1, 6, 8, 0, 0, 0, time.UTC)
+		notifications := []*domain.DeferredNotification{{
+			Notification: domain.Notification{User: "user@email.com", Message: "message", Channel: "slack"},
+			DeliverAt:    deliverAt,
+		}}
+		expectedQuery := regexp.QuoteMeta(`INSERT INTO "deferred_notifications" ("user","message","channel","deliver_at","created_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs("user@email.com", "message", "slack", deliverAt, utils.AnyTime{}).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		s.dbmock.ExpectCommit()
+
+		err := s.repository.BulkInsert(context.Background(), notifications)
+
+		s.Nil(err)
+		s.Equal(uint(1), notifications[0].ID)
+	})
+}
+
+func (s *RepositoryTestSuite) TestGetDue() {
+	expectedQuery := regexp.QuoteMeta(`SELECT * FROM "deferred_notifications" WHERE "deliver_at" <= $1 ORDER BY deliver_at`)
+	now := time.Date(2022, 1, 6, 8, 0, 0, 0, time.UTC)
+
+	s.Run("should return error if db returns error", func() {
+		expectedError := errors.New("db error")
+		s.dbmock.ExpectQuery(expectedQuery).WillReturnError(expectedError)
+
+		actualRecords, actualError := s.repository.GetDue(context.Background(), now)
+
+		s.Nil(actualRecords)
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should return the due notifications", func() {
+		rows := sqlmock.NewRows([]string{"id", "user", "message", "channel", "deliver_at", "created_at"}).
+			AddRow(1, "user@email.com", "message", "slack", now, now)
+		s.dbmock.ExpectQuery(expectedQuery).WithArgs(now).WillReturnRows(rows)
+
+		actualRecords, actualError := s.repository.GetDue(context.Background(), now)
+
+		s.Nil(actualError)
+		s.Equal([]*domain.DeferredNotification{{
+			ID:           1,
+			Notification: domain.Notification{User: "user@email.com", Message: "message", Channel: "slack"},
+			DeliverAt:    now,
+			CreatedAt:    now,
+		}}, actualRecords)
+	})
+}
+
+func (s *RepositoryTestSuite) TestDelete() {
+	s.Run("should delete the notifications by ids", func() {
+		expectedQuery := regexp.QuoteMeta(`DELETE FROM "deferred_notifications" WHERE id IN ($1,$2)`)
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectExec(expectedQuery).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 2))
+		s.dbmock.ExpectCommit()
+
+		s.Nil(s.repository.Delete(context.Background(), []uint{1, 2}))
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository(t *testing.T) {
+	suite.Run(t, new(RepositoryTestSuite))
+}
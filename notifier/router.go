@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+)
+
+const (
+	ChannelSlack = "slack"
+)
+
+var ErrChannelNotFound = errors.New("notification channel not found")
+
+type router struct {
+	defaultChannel string
+	channels       map[string]domain.Notifier
+}
+
+// NewRouter returns a notifier that sends each notification through the channel it's hinted to.
+// Notifications without a channel hint or hinted to an unregistered channel go through the default channel.
+// A failing channel doesn't prevent the others from being called, the failures are returned as a *NotifyError
+func NewRouter(defaultChannel string, channels map[string]domain.Notifier) (*router, error) {
+	if channels[defaultChannel] == nil {
+		return nil, fmt.Errorf("%w: %q", ErrChannelNotFound, defaultChannel)
+	}
+	return &router{defaultChannel, channels}, nil
+}
+
+func (r *router) Notify(items []domain.Notification) error {
+	groups := map[string][]domain.Notification{}
+	var channels []string
+	for _, item := range items {
+		channel := item.Channel
+		if r.channels[channel] == nil {
+			channel = r.defaultChannel
+		}
+		if _, exists := groups[channel]; !exists {
+			channels = append(channels, channel)
+		}
+		groups[channel] = append(groups[channel], item)
+	}
+
+	var errs []error
+	for _, channel := range channels {
+		if err := r.channels[channel].Notify(groups[channel]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return &NotifyError{Errors: errs}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package notifier_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/odpf/guardian/notifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	t.Run("should return error if default channel is not registered", func(t *testing.T) {
+		_, err := notifier.NewRouter("email", map[string]domain.Notifier{notifier.ChannelSlack: new(mocks.Notifier)})
+
+		assert.True(t, errors.Is(err, notifier.ErrChannelNotFound))
+	})
+
+	t.Run("should send the notifications through their channels", func(t *testing.T) {
+		slack := new(mocks.Notifier)
+		pager := new(mocks.Notifier)
+		r, err := notifier.NewRouter(notifier.ChannelSlack, map[string]domain.Notifier{
+			notifier.ChannelSlack: slack,
+			"pager":               pager,
+		})
+		assert.Nil(t, err)
+
+		notifications := []domain.Notification{
+			{User: "legal@email.com", Message: "message"},
+			{User: "oncall@email.com", Message: "message", Channel: "pager"},
+			{User: "other@email.com", Message: "message", Channel: "unregistered"},
+		}
+		slack.On("Notify", []domain.Notification{notifications[0], notifications[2]}).Return(nil).Once()
+		pager.On("Notify", []domain.Notification{notifications[1]}).Return(nil).Once()
+
+		assert.Nil(t, r.Notify(notifications))
+		slack.AssertExpectations(t)
+		pager.AssertExpectations(t)
+	})
+
+	t.Run("should keep sending through the other channels if a channel fails", func(t *testing.T) {
+		slack := new(mocks.Notifier)
+		pager := new(mocks.Notifier)
+		r, err := notifier.NewRouter(notifier.ChannelSlack, map[string]domain.Notifier{
+			notifier.ChannelSlack: slack,
+			"pager":               pager,
+		})
+		assert.Nil(t, err)
+
+		notifications := []domain.Notification{
+			{User: "legal@email.com", Message: "message"},
+			{User: "oncall@email.com", Message: "message", Channel: "pager"},
+		}
+		slack.On("Notify", notifications[:1]).Return(errors.New("slack error")).Once()
+		pager.On("Notify", notifications[1:]).Return(nil).Once()
+
+		err = r.Notify(notifications)
+
+		var notifyErr *notifier.NotifyError
+		assert.True(t, errors.As(err, &notifyErr))
+		assert.EqualError(t, notifyErr.Errors[0], "slack: slack error")
+		pager.AssertExpectations(t)
+	})
+}
@@ -54,6 +54,33 @@ func (n *slackNotifier) Notify(items []domain.Notification) error {
 	return nil
 }
 
+type slackChannelNotifier struct {
+	*slackNotifier
+	channel string
+}
+
+// NewSlackChannelNotifier returns a notifier posting the notifications to a slack channel, e.g. the channel of a
+// team, instead of sending them as direct messages. The users are mentioned in the messages, or named by their
+// email if they aren't found in slack
+func NewSlackChannelNotifier(n *slackNotifier, channel string) *slackChannelNotifier {
+	return &slackChannelNotifier{n, channel}
+}
+
+func (n *slackChannelNotifier) Notify(items []domain.Notification) error {
+	for _, item := range items {
+		mention := item.User
+		if slackID, err := n.findSlackIDByEmail(item.User); err == nil {
+			mention = "<@" + slackID + ">"
+		}
+
+		if err := n.sendMessage(n.channel, mention+" "+item.Message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (n *slackNotifier) sendMessage(channel, text string) error {
 	url := slackHost + "/api/chat.postMessage"
 	data, err := json.Marshal(map[string]string{
@@ -7,4 +7,28 @@ var (
 	ErrEmptyIDParam = errors.New("id can't be empty")
 	// ErrPolicyDoesNotExists is the error value if the designated policy is not exists
 	ErrPolicyDoesNotExists = errors.New("policy does not exists")
+	// ErrPolicyVersionInUse is the error value if the policy version to be written is already referenced by appeals
+	ErrPolicyVersionInUse = errors.New("policy version is already used by appeals, create a new version instead")
+	// ErrInvalidStepMinDuration is the error value if the approval step min duration is not a positive duration
+	ErrInvalidStepMinDuration = errors.New("approval step min duration should be a positive duration, e.g. 24h")
+	// ErrInvalidStepSLA is the error value if the approval step sla is not a positive duration
+	ErrInvalidStepSLA = errors.New("approval step sla should be a positive duration, e.g. 24h")
+	// ErrInvalidStepAssignmentStrategy is the error value if the approval step assignment strategy is not supported
+	ErrInvalidStepAssignmentStrategy = errors.New("approval step assignment strategy should be one of all, round_robin, or least_loaded")
+	// ErrInvalidStepReassignAfter is the error value if the approval step reassign_after is not a positive duration
+	ErrInvalidStepReassignAfter = errors.New("approval step reassign_after should be a positive duration, e.g. 24h")
+	// ErrInvalidStepRequireReason is the error value if the approval step require_reason is not supported
+	ErrInvalidStepRequireReason = errors.New("approval step require_reason should be one of approve, reject, both, or none")
+	// ErrInvalidStepCheck is the error value if the approval step check has an invalid timeout or no approvers to fall back to
+	ErrInvalidStepCheck = errors.New("invalid approval step check, it requires the approvers and a positive timeout, e.g. 10s")
+	// ErrInvalidAutoCancelPendingFor is the error value if the auto-cancel age of an enabled auto-cancel is not a positive duration
+	ErrInvalidAutoCancelPendingFor = errors.New("auto cancel pending_for should be a positive duration, e.g. 168h")
+	// ErrInvalidApprovalValidity is the error value if the approval validity is not a positive duration
+	ErrInvalidApprovalValidity = errors.New("approval validity should be a positive duration, e.g. 720h")
+	// ErrInvalidSensitivityTier is the error value if a sensitivity tier is duplicated or has a step named the same as another step
+	ErrInvalidSensitivityTier = errors.New("invalid sensitivity tier, the tiers and their steps should be unique across the policy")
+	// ErrInvalidApproverResolutionFallback is the error value if the approver resolution fallback strategy isn't supported or misses its approvers
+	ErrInvalidApproverResolutionFallback = errors.New("approver resolution fallback should have either the defer strategy, or the default_approvers strategy along with the approvers")
+	// ErrInvalidPolicySchema is the error value if the policy doesn't conform to the policy schema
+	ErrInvalidPolicySchema = errors.New("invalid policy")
 )
@@ -1,6 +1,9 @@
 package policy
 
 import (
+	"context"
+
+	"github.com/odpf/guardian/auth"
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/model"
 	"gorm.io/gorm"
@@ -16,14 +19,28 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db}
 }
 
-// Create new record to database
-func (r *Repository) Create(p *domain.Policy) error {
+// scoped restricts the queries to the policies of the organization carried by ctx, if any. The policies without
+// an organization are only visible to the callers without an organization
+func (r *Repository) scoped(ctx context.Context) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+		db = db.Where(`"organization_id" = ?`, organizationID)
+	}
+	return db
+}
+
+// Create new record to database, in the organization carried by ctx if any
+func (r *Repository) Create(ctx context.Context, p *domain.Policy) error {
+	if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+		p.OrganizationID = organizationID
+	}
+
 	m := new(model.Policy)
 	if err := m.FromDomain(p); err != nil {
 		return err
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if result := tx.Create(m); result.Error != nil {
 			return result.Error
 		}
@@ -40,12 +57,13 @@ func (r *Repository) Create(p *domain.Policy) error {
 }
 
 // Find records based on filters
-func (r *Repository) Find() ([]*domain.Policy, error) {
+func (r *Repository) Find(ctx context.Context) ([]*domain.Policy, error) {
 	policies := []*domain.Policy{}
+	db := r.db.WithContext(ctx)
 
 	var models []*model.Policy
-	latestPoliciesQuery := r.db.Model(&model.Policy{}).Select("id, max(version)").Group("id")
-	if err := r.db.Where("(id,version) IN (?)", latestPoliciesQuery).Find(&models).Error; err != nil {
+	latestPoliciesQuery := db.Model(&model.Policy{}).Select("id, max(version)").Group("id")
+	if err := r.scoped(ctx).Where("(id,version) IN (?)", latestPoliciesQuery).Find(&models).Error; err != nil {
 		return nil, err
 	}
 	for _, m := range models {
@@ -62,7 +80,7 @@ func (r *Repository) Find() ([]*domain.Policy, error) {
 
 // GetOne returns a policy record based on the id and version params.
 // If version is 0, the latest version will be returned
-func (r *Repository) GetOne(id string, version uint) (*domain.Policy, error) {
+func (r *Repository) GetOne(ctx context.Context, id string, version uint) (*domain.Policy, error) {
 	m := &model.Policy{}
 	condition := "id = ?"
 	args := []interface{}{id}
@@ -72,7 +90,7 @@ func (r *Repository) GetOne(id string, version uint) (*domain.Policy, error) {
 	}
 
 	conds := append([]interface{}{condition}, args...)
-	if err := r.db.Order("version desc").First(m, conds...).Error; err != nil {
+	if err := r.scoped(ctx).Order("version desc").First(m, conds...).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
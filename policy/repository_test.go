@@ -1,6 +1,7 @@
 package policy_test
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/odpf/guardian/auth"
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/mocks"
 	"github.com/odpf/guardian/policy"
@@ -48,7 +50,7 @@ func (s *RepositoryTestSuite) TearDownTest() {
 }
 
 func (s *RepositoryTestSuite) TestCreate() {
-	expectedQuery := regexp.QuoteMeta(`INSERT INTO "policies" ("id","version","description","steps","labels","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`)
+	expectedQuery := regexp.QuoteMeta(`INSERT INTO "policies" ("id","version","description","steps","labels","reminder_escalation","auto_cancel","approval_validity","sensitivity_tiers","approver_resolution_fallback","separation_of_duties","organization_id","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15)`)
 
 	s.Run("should return error if got error from db transaction", func() {
 		p := &domain.Policy{}
@@ -59,6 +61,13 @@ func (s *RepositoryTestSuite) TestCreate() {
 			p.Description,
 			"null",
 			"null",
+			"null",
+			"null",
+			"",
+			"null",
+			"null",
+			false,
+			"",
 			utils.AnyTime{},
 			utils.AnyTime{},
 			gorm.DeletedAt{},
@@ -71,7 +80,7 @@ func (s *RepositoryTestSuite) TestCreate() {
 			WillReturnError(expectedError)
 		s.dbmock.ExpectRollback()
 
-		actualError := s.repository.Create(p)
+		actualError := s.repository.Create(context.Background(), p)
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -85,6 +94,13 @@ func (s *RepositoryTestSuite) TestCreate() {
 			p.Description,
 			"null",
 			"null",
+			"null",
+			"null",
+			"",
+			"null",
+			"null",
+			false,
+			"",
 			utils.AnyTime{},
 			utils.AnyTime{},
 			gorm.DeletedAt{},
@@ -97,9 +113,43 @@ func (s *RepositoryTestSuite) TestCreate() {
 			WillReturnResult(expectedResult)
 		s.dbmock.ExpectCommit()
 
-		err := s.repository.Create(p)
+		err := s.repository.Create(context.Background(), p)
+
+		s.Nil(err)
+	})
+
+	s.Run("should create the policy in the organization of the context", func() {
+		p := &domain.Policy{}
+
+		expectedArgs := []driver.Value{
+			p.ID,
+			p.Version,
+			p.Description,
+			"null",
+			"null",
+			"null",
+			"null",
+			"",
+			"null",
+			"null",
+			false,
+			"org-a",
+			utils.AnyTime{},
+			utils.AnyTime{},
+			gorm.DeletedAt{},
+		}
+
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectExec(expectedQuery).
+			WithArgs(expectedArgs...).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		s.dbmock.ExpectCommit()
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		err := s.repository.Create(ctx, p)
 
 		s.Nil(err)
+		s.Equal("org-a", p.OrganizationID)
 	})
 }
 
@@ -112,7 +162,7 @@ func (s *RepositoryTestSuite) TestFind() {
 		s.dbmock.ExpectQuery(expectedQuery).
 			WillReturnError(expectedError)
 
-		actualPolicies, actualError := s.repository.Find()
+		actualPolicies, actualError := s.repository.Find(context.Background())
 
 		s.EqualError(actualError, expectedError.Error())
 		s.Nil(actualPolicies)
@@ -142,11 +192,25 @@ func (s *RepositoryTestSuite) TestFind() {
 
 		s.dbmock.ExpectQuery(expectedQuery).WillReturnRows(expectedRows)
 
-		actualPolicies, actualError := s.repository.Find()
+		actualPolicies, actualError := s.repository.Find(context.Background())
 
 		s.Equal(expectedPolicies, actualPolicies)
 		s.Nil(actualError)
 	})
+
+	s.Run("should only return the policies of the organization", func() {
+		expectedOrganizationQuery := regexp.QuoteMeta(`SELECT * FROM "policies" WHERE ("organization_id" = $1) AND (id,version) IN (SELECT id, max(version) FROM "policies" WHERE "policies"."deleted_at" IS NULL GROUP BY "id") AND "policies"."deleted_at" IS NULL`)
+		s.dbmock.ExpectQuery(expectedOrganizationQuery).
+			WithArgs("org-a").
+			WillReturnRows(sqlmock.NewRows(s.rows))
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		actualPolicies, actualError := s.repository.Find(ctx)
+
+		s.Nil(actualError)
+		s.Empty(actualPolicies)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
 }
 
 func (s *RepositoryTestSuite) TestGetOne() {
@@ -155,7 +219,7 @@ func (s *RepositoryTestSuite) TestGetOne() {
 		s.dbmock.ExpectQuery(".*").
 			WillReturnError(expectedError)
 
-		actualResult, actualError := s.repository.GetOne("", 0)
+		actualResult, actualError := s.repository.GetOne(context.Background(), "", 0)
 
 		s.Nil(actualResult)
 		s.Nil(actualError)
@@ -166,7 +230,7 @@ func (s *RepositoryTestSuite) TestGetOne() {
 		s.dbmock.ExpectQuery(".*").
 			WillReturnError(expectedError)
 
-		actualResult, actualError := s.repository.GetOne("", 0)
+		actualResult, actualError := s.repository.GetOne(context.Background(), "", 0)
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -212,13 +276,27 @@ func (s *RepositoryTestSuite) TestGetOne() {
 					WithArgs(tc.expectedArgs...).
 					WillReturnRows(sqlmock.NewRows(s.rows).AddRow(expectedRowValues...))
 
-				_, actualError := s.repository.GetOne(tc.expectedID, tc.expectedVersion)
+				_, actualError := s.repository.GetOne(context.Background(), tc.expectedID, tc.expectedVersion)
 
 				s.Nil(actualError)
 				s.dbmock.ExpectationsWereMet()
 			})
 		}
 	})
+
+	s.Run("should not return the policy of another organization", func() {
+		expectedQuery := regexp.QuoteMeta(`SELECT * FROM "policies" WHERE ("organization_id" = $1) AND id = $2 AND "policies"."deleted_at" IS NULL ORDER BY version desc,"policies"."id" LIMIT 1`)
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs("org-a", "test-id").
+			WillReturnRows(sqlmock.NewRows(s.rows))
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		actualResult, actualError := s.repository.GetOne(ctx, "test-id", 0)
+
+		s.Nil(actualResult)
+		s.Nil(actualError)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
 }
 
 func TestRepository(t *testing.T) {
@@ -0,0 +1,243 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// policySchema is the JSON schema of a policy. It is kept in the code so it is versioned together
+// with the fields and the approver keys understood by the appeal and approval services
+const policySchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Policy",
+  "type": "object",
+  "required": ["id", "steps"],
+  "properties": {
+    "id": {
+      "type": "string",
+      "minLength": 1
+    },
+    "version": {
+      "type": "integer",
+      "minimum": 0
+    },
+    "description": {
+      "type": "string"
+    },
+    "labels": {
+      "type": ["object", "null"],
+      "additionalProperties": {
+        "type": "string"
+      }
+    },
+    "steps": {
+      "type": "array",
+      "minItems": 1,
+      "items": {
+        "$ref": "#/definitions/step"
+      }
+    },
+    "sensitivity_tiers": {
+      "type": ["array", "null"],
+      "items": {
+        "$ref": "#/definitions/sensitivity_tier"
+      }
+    },
+    "approver_resolution_fallback": {
+      "type": ["object", "null"],
+      "required": ["strategy"],
+      "properties": {
+        "strategy": {
+          "type": "string",
+          "enum": ["defer", "default_approvers"]
+        },
+        "approvers": {
+          "type": ["array", "null"],
+          "items": {
+            "type": "string",
+            "pattern": "^[^@\\s]+@[^@\\s]+$"
+          }
+        }
+      }
+    }
+  },
+  "definitions": {
+    "sensitivity_tier": {
+      "type": "object",
+      "required": ["sensitivity", "steps"],
+      "properties": {
+        "sensitivity": {
+          "type": "string",
+          "minLength": 1
+        },
+        "steps": {
+          "type": "array",
+          "minItems": 1,
+          "items": {
+            "allOf": [
+              {
+                "$ref": "#/definitions/step"
+              },
+              {
+                "required": ["approvers"],
+                "properties": {
+                  "approvers": {
+                    "minLength": 1
+                  },
+                  "conditions": {
+                    "type": ["array", "null"],
+                    "maxItems": 0
+                  }
+                }
+              }
+            ]
+          }
+        }
+      }
+    },
+    "step": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {
+          "type": "string",
+          "minLength": 1
+        },
+        "description": {
+          "type": "string"
+        },
+        "allow_failed": {
+          "type": "boolean"
+        },
+        "dependencies": {
+          "type": ["array", "null"],
+          "items": {
+            "type": "string",
+            "minLength": 1
+          }
+        },
+        "approvers": {
+          "type": "string",
+          "pattern": "^$|^\\$resource\\.\\S+|^\\$user_approvers"
+        },
+        "conditions": {
+          "type": ["array", "null"],
+          "items": {
+            "$ref": "#/definitions/condition"
+          }
+        },
+        "notification_channel": {
+          "type": "string"
+        },
+        "min_duration": {
+          "type": "string"
+        },
+        "sla": {
+          "type": "string"
+        },
+        "delegation_chain": {
+          "$ref": "#/definitions/delegation_chain"
+        },
+        "check": {
+          "$ref": "#/definitions/check"
+        }
+      },
+      "anyOf": [
+        {
+          "required": ["approvers"],
+          "properties": {
+            "approvers": {
+              "minLength": 1
+            }
+          }
+        },
+        {
+          "required": ["conditions"],
+          "properties": {
+            "conditions": {
+              "type": "array",
+              "minItems": 1
+            }
+          }
+        }
+      ]
+    },
+    "check": {
+      "type": ["object", "null"],
+      "required": ["url"],
+      "properties": {
+        "url": {
+          "type": "string",
+          "pattern": "^https?://"
+        },
+        "timeout": {
+          "type": "string"
+        },
+        "response_field": {
+          "type": "string"
+        }
+      }
+    },
+    "delegation_chain": {
+      "type": ["object", "null"],
+      "required": ["members"],
+      "properties": {
+        "members": {
+          "type": "array",
+          "minItems": 1,
+          "items": {
+            "type": "string",
+            "pattern": "^\\$manager_chain$|^[^@\\s]+@[^@\\s]+$"
+          }
+        },
+        "depth": {
+          "type": "integer",
+          "minimum": 0
+        }
+      }
+    },
+    "condition": {
+      "type": "object",
+      "required": ["field", "match"],
+      "properties": {
+        "field": {
+          "type": "string",
+          "pattern": "^\\$resource\\.\\S+"
+        },
+        "match": {
+          "type": "object",
+          "required": ["eq"]
+        }
+      }
+    }
+  }
+}`
+
+var policySchemaLoader = gojsonschema.NewStringLoader(policySchema)
+
+// validateSchema validates the policy against the policy schema and reports every violation along
+// with its path, e.g. "steps.1.approvers"
+func validateSchema(p *domain.Policy) error {
+	document, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	result, err := gojsonschema.Validate(policySchemaLoader, gojsonschema.NewBytesLoader(document))
+	if err != nil {
+		return err
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	var violations []string
+	for _, e := range result.Errors() {
+		violations = append(violations, fmt.Sprintf("%s: %s", e.Field(), e.Description()))
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidPolicySchema, strings.Join(violations, "; "))
+}
@@ -1,42 +1,90 @@
 package policy
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
 )
 
 // Service handling the business logics
 type Service struct {
 	policyRepository domain.PolicyRepository
+	appealRepository domain.AppealRepository
 }
 
 // NewService returns service struct
-func NewService(pr domain.PolicyRepository) *Service {
-	return &Service{pr}
+func NewService(pr domain.PolicyRepository, ar domain.AppealRepository) *Service {
+	return &Service{pr, ar}
 }
 
 // Create record
-func (s *Service) Create(p *domain.Policy) error {
+func (s *Service) Create(ctx context.Context, p *domain.Policy) error {
+	if err := validateSchema(p); err != nil {
+		return err
+	}
+	if err := validateSteps(p); err != nil {
+		return err
+	}
+	if err := validateAutoCancel(p); err != nil {
+		return err
+	}
+	if err := validateApprovalValidity(p); err != nil {
+		return err
+	}
+	if err := validateSensitivityTiers(p); err != nil {
+		return err
+	}
+	if err := validateApproverResolutionFallback(p); err != nil {
+		return err
+	}
+
 	p.Version = 1
-	return s.policyRepository.Create(p)
+	if err := s.checkVersionNotInUse(ctx, p.ID, p.Version); err != nil {
+		return err
+	}
+	return s.policyRepository.Create(ctx, p)
 }
 
 // Find records
-func (s *Service) Find() ([]*domain.Policy, error) {
-	return s.policyRepository.Find()
+func (s *Service) Find(ctx context.Context) ([]*domain.Policy, error) {
+	return s.policyRepository.Find(ctx)
 }
 
 // GetOne record
-func (s *Service) GetOne(id string, version uint) (*domain.Policy, error) {
-	return s.policyRepository.GetOne(id, version)
+func (s *Service) GetOne(ctx context.Context, id string, version uint) (*domain.Policy, error) {
+	return s.policyRepository.GetOne(ctx, id, version)
 }
 
 // Update a record
-func (s *Service) Update(p *domain.Policy) error {
+func (s *Service) Update(ctx context.Context, p *domain.Policy) error {
 	if p.ID == "" {
 		return ErrEmptyIDParam
 	}
+	if err := validateSchema(p); err != nil {
+		return err
+	}
+	if err := validateSteps(p); err != nil {
+		return err
+	}
+	if err := validateAutoCancel(p); err != nil {
+		return err
+	}
+	if err := validateApprovalValidity(p); err != nil {
+		return err
+	}
+	if err := validateSensitivityTiers(p); err != nil {
+		return err
+	}
+	if err := validateApproverResolutionFallback(p); err != nil {
+		return err
+	}
 
-	latestPolicy, err := s.policyRepository.GetOne(p.ID, p.Version)
+	// the new version always follows the latest one, even if the update is based on an outdated version,
+	// so an existing version is never rewritten
+	latestPolicy, err := s.policyRepository.GetOne(ctx, p.ID, 0)
 	if err != nil {
 		return err
 	}
@@ -44,6 +92,139 @@ func (s *Service) Update(p *domain.Policy) error {
 		return ErrPolicyDoesNotExists
 	}
 
+	// the policies of other organizations aren't found, so the new version always stays in the organization of the
+	// latest one, even when it is updated by a caller without an organization
 	p.Version = latestPolicy.Version + 1
-	return s.policyRepository.Create(p)
+	p.OrganizationID = latestPolicy.OrganizationID
+	return s.policyRepository.Create(ctx, p)
+}
+
+// checkVersionNotInUse makes sure a policy version that has been used by any appeal is never
+// rewritten, e.g. by creating a policy with an existing id, so the appeals always reflect the
+// policy they ran under
+func (s *Service) checkVersionNotInUse(ctx context.Context, id string, version uint) error {
+	used, err := s.appealRepository.IsPolicyVersionUsed(ctx, id, version)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrPolicyVersionInUse
+	}
+	return nil
+}
+
+func validateSteps(p *domain.Policy) error {
+	for _, step := range p.Steps {
+		if err := validateStep(step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateStep(step *domain.Step) error {
+	if step.MinDuration != "" {
+		if d, err := time.ParseDuration(step.MinDuration); err != nil || d <= 0 {
+			return fmt.Errorf("%w: %q on step %q", ErrInvalidStepMinDuration, step.MinDuration, step.Name)
+		}
+	}
+	if step.SLA != "" {
+		if d, err := time.ParseDuration(step.SLA); err != nil || d <= 0 {
+			return fmt.Errorf("%w: %q on step %q", ErrInvalidStepSLA, step.SLA, step.Name)
+		}
+	}
+	if step.AssignmentStrategy != "" && !utils.ContainsString(domain.AssignmentStrategies, step.AssignmentStrategy) {
+		return fmt.Errorf("%w: %q on step %q", ErrInvalidStepAssignmentStrategy, step.AssignmentStrategy, step.Name)
+	}
+	if step.ReassignAfter != "" {
+		if d, err := time.ParseDuration(step.ReassignAfter); err != nil || d <= 0 {
+			return fmt.Errorf("%w: %q on step %q", ErrInvalidStepReassignAfter, step.ReassignAfter, step.Name)
+		}
+	}
+	if step.RequireReason != "" && !utils.ContainsString(domain.RequireReasonModes, step.RequireReason) {
+		return fmt.Errorf("%w: %q on step %q", ErrInvalidStepRequireReason, step.RequireReason, step.Name)
+	}
+	if step.Check != nil {
+		if step.Approvers == "" {
+			return fmt.Errorf("%w: step %q has no approvers to fall back to", ErrInvalidStepCheck, step.Name)
+		}
+		if step.Check.Timeout != "" {
+			if d, err := time.ParseDuration(step.Check.Timeout); err != nil || d <= 0 {
+				return fmt.Errorf("%w: timeout %q on step %q", ErrInvalidStepCheck, step.Check.Timeout, step.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateSensitivityTiers makes sure every sensitivity has a single tier, and the tier steps are named apart from
+// the policy steps as they're appended to them
+func validateSensitivityTiers(p *domain.Policy) error {
+	policyStepNames := map[string]bool{}
+	for _, step := range p.Steps {
+		policyStepNames[step.Name] = true
+	}
+
+	sensitivities := map[string]bool{}
+	for _, tier := range p.SensitivityTiers {
+		if sensitivities[tier.Sensitivity] {
+			return fmt.Errorf("%w: duplicate tier %q", ErrInvalidSensitivityTier, tier.Sensitivity)
+		}
+		sensitivities[tier.Sensitivity] = true
+
+		tierStepNames := map[string]bool{}
+		for _, step := range tier.Steps {
+			if policyStepNames[step.Name] || tierStepNames[step.Name] {
+				return fmt.Errorf("%w: duplicate step %q on tier %q", ErrInvalidSensitivityTier, step.Name, tier.Sensitivity)
+			}
+			tierStepNames[step.Name] = true
+			if err := validateStep(step); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateApproverResolutionFallback makes sure the fallback strategy is supported, and the default approvers are
+// set for the default_approvers strategy only
+func validateApproverResolutionFallback(p *domain.Policy) error {
+	f := p.ApproverResolutionFallback
+	if f == nil {
+		return nil
+	}
+
+	switch f.Strategy {
+	case domain.ApproverResolutionFallbackDefer:
+		if len(f.Approvers) > 0 {
+			return fmt.Errorf("%w: approvers are not used by the %q strategy", ErrInvalidApproverResolutionFallback, f.Strategy)
+		}
+	case domain.ApproverResolutionFallbackDefaultApprovers:
+		if len(f.Approvers) == 0 {
+			return fmt.Errorf("%w: approvers are required by the %q strategy", ErrInvalidApproverResolutionFallback, f.Strategy)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported strategy %q", ErrInvalidApproverResolutionFallback, f.Strategy)
+	}
+	return nil
+}
+
+func validateApprovalValidity(p *domain.Policy) error {
+	if p.ApprovalValidity == "" {
+		return nil
+	}
+	if d, err := time.ParseDuration(p.ApprovalValidity); err != nil || d <= 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidApprovalValidity, p.ApprovalValidity)
+	}
+	return nil
+}
+
+func validateAutoCancel(p *domain.Policy) error {
+	if p.AutoCancel == nil || !p.AutoCancel.Enabled {
+		return nil
+	}
+	if d, err := time.ParseDuration(p.AutoCancel.PendingFor); err != nil || d <= 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidAutoCancelPendingFor, p.AutoCancel.PendingFor)
+	}
+	return nil
 }
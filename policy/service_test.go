@@ -1,6 +1,7 @@
 package policy_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -14,51 +15,285 @@ import (
 type ServiceTestSuite struct {
 	suite.Suite
 	mockPolicyRepository *mocks.PolicyRepository
+	mockAppealRepository *mocks.AppealRepository
 	service              *policy.Service
 }
 
 func (s *ServiceTestSuite) SetupTest() {
 	s.mockPolicyRepository = new(mocks.PolicyRepository)
-	s.service = policy.NewService(s.mockPolicyRepository)
+	s.mockAppealRepository = new(mocks.AppealRepository)
+	s.service = policy.NewService(s.mockPolicyRepository, s.mockAppealRepository)
+}
+
+var validSteps = []*domain.Step{
+	{
+		Name:      "step_1",
+		Approvers: "$resource.details.owner",
+	},
 }
 
 func (s *ServiceTestSuite) TestCreate() {
 	p := &domain.Policy{
 		ID:      "test",
 		Version: 1,
+		Steps:   validSteps,
 	}
 
 	s.Run("should return error if got error from the policy repository", func() {
 		expectedError := errors.New("error from repository")
-		s.mockPolicyRepository.On("Create", mock.Anything).Return(expectedError).Once()
+		s.mockAppealRepository.On("IsPolicyVersionUsed", mock.Anything, "test", uint(1)).Return(false, nil).Once()
+		s.mockPolicyRepository.On("Create", mock.Anything, mock.Anything).Return(expectedError).Once()
 
-		actualError := s.service.Create(&domain.Policy{})
+		actualError := s.service.Create(context.Background(), &domain.Policy{ID: "test", Steps: validSteps})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
+	s.Run("should return error if step min duration is invalid", func() {
+		for _, minDuration := range []string{"a day", "-1h", "0s"} {
+			actualError := s.service.Create(context.Background(), &domain.Policy{
+				ID:    "test",
+				Steps: []*domain.Step{{Name: "step_1", Approvers: "$resource.details.owner", MinDuration: minDuration}},
+			})
+
+			s.True(errors.Is(actualError, policy.ErrInvalidStepMinDuration))
+		}
+	})
+
+	s.Run("should return error if step sla is invalid", func() {
+		for _, sla := range []string{"a day", "-1h", "0s"} {
+			actualError := s.service.Create(context.Background(), &domain.Policy{
+				ID:    "test",
+				Steps: []*domain.Step{{Name: "step_1", Approvers: "$resource.details.owner", SLA: sla}},
+			})
+
+			s.True(errors.Is(actualError, policy.ErrInvalidStepSLA))
+		}
+	})
+
+	s.Run("should return error if step assignment is invalid", func() {
+		actualError := s.service.Create(context.Background(), &domain.Policy{
+			ID:    "test",
+			Steps: []*domain.Step{{Name: "step_1", Approvers: "$resource.details.owner", AssignmentStrategy: "random"}},
+		})
+		s.True(errors.Is(actualError, policy.ErrInvalidStepAssignmentStrategy))
+
+		actualError = s.service.Create(context.Background(), &domain.Policy{
+			ID:    "test",
+			Steps: []*domain.Step{{Name: "step_1", Approvers: "$resource.details.owner", AssignmentStrategy: domain.AssignmentStrategyRoundRobin, ReassignAfter: "a day"}},
+		})
+		s.True(errors.Is(actualError, policy.ErrInvalidStepReassignAfter))
+	})
+
+	s.Run("should return error if step require_reason is invalid", func() {
+		actualError := s.service.Create(context.Background(), &domain.Policy{
+			ID:    "test",
+			Steps: []*domain.Step{{Name: "step_1", Approvers: "$resource.details.owner", RequireReason: "always"}},
+		})
+		s.True(errors.Is(actualError, policy.ErrInvalidStepRequireReason))
+	})
+
+	s.Run("should return error if step check is invalid", func() {
+		testCases := []*domain.Step{
+			{Name: "step_1", Conditions: []*domain.Condition{{Field: "$resource.details.is_pii", Match: &domain.MatchCondition{Eq: true}}}, Check: &domain.StepCheck{URL: "https://lms.example.com/check"}},
+			{Name: "step_1", Approvers: "$resource.details.owner", Check: &domain.StepCheck{URL: "https://lms.example.com/check", Timeout: "soon"}},
+		}
+		for _, step := range testCases {
+			actualError := s.service.Create(context.Background(), &domain.Policy{
+				ID:    "test",
+				Steps: []*domain.Step{step},
+			})
+
+			s.True(errors.Is(actualError, policy.ErrInvalidStepCheck))
+		}
+	})
+
+	s.Run("should return error if auto cancel is enabled with an invalid age", func() {
+		for _, pendingFor := range []string{"", "a week", "-1h"} {
+			actualError := s.service.Create(context.Background(), &domain.Policy{
+				ID:         "test",
+				Steps:      validSteps,
+				AutoCancel: &domain.AutoCancelConfig{Enabled: true, PendingFor: pendingFor},
+			})
+
+			s.True(errors.Is(actualError, policy.ErrInvalidAutoCancelPendingFor))
+		}
+	})
+
+	s.Run("should return error if the approval validity is not a positive duration", func() {
+		for _, validity := range []string{"a month", "0s", "-1h"} {
+			actualError := s.service.Create(context.Background(), &domain.Policy{
+				ID:               "test",
+				Steps:            validSteps,
+				ApprovalValidity: validity,
+			})
+
+			s.True(errors.Is(actualError, policy.ErrInvalidApprovalValidity))
+		}
+	})
+
+	s.Run("should return error if the approver resolution fallback is invalid", func() {
+		testCases := []struct {
+			fallback      *domain.ApproverResolutionFallback
+			expectedError error
+		}{
+			{&domain.ApproverResolutionFallback{Strategy: "retry"}, policy.ErrInvalidPolicySchema},
+			{&domain.ApproverResolutionFallback{Strategy: domain.ApproverResolutionFallbackDefaultApprovers}, policy.ErrInvalidApproverResolutionFallback},
+			{&domain.ApproverResolutionFallback{Strategy: domain.ApproverResolutionFallbackDefer, Approvers: []string{"approver@email.com"}}, policy.ErrInvalidApproverResolutionFallback},
+		}
+		for _, tc := range testCases {
+			actualError := s.service.Create(context.Background(), &domain.Policy{
+				ID:                         "test",
+				Steps:                      validSteps,
+				ApproverResolutionFallback: tc.fallback,
+			})
+
+			s.True(errors.Is(actualError, tc.expectedError))
+		}
+	})
+
+	s.Run("should return error if the sensitivity tiers aren't unique", func() {
+		tierStep := &domain.Step{Name: "vp_approval", Approvers: "$resource.details.vp"}
+		testCases := [][]*domain.SensitivityTier{
+			{{Sensitivity: "high", Steps: []*domain.Step{tierStep}}, {Sensitivity: "high", Steps: []*domain.Step{tierStep}}},
+			{{Sensitivity: "high", Steps: []*domain.Step{{Name: "step_1", Approvers: "$resource.details.vp"}}}},
+		}
+		for _, tiers := range testCases {
+			actualError := s.service.Create(context.Background(), &domain.Policy{
+				ID:               "test",
+				Steps:            validSteps,
+				SensitivityTiers: tiers,
+			})
+
+			s.True(errors.Is(actualError, policy.ErrInvalidSensitivityTier))
+		}
+	})
+
+	s.Run("should return error if the policy doesn't conform to the schema", func() {
+		testCases := []struct {
+			name          string
+			policy        *domain.Policy
+			expectedField string
+		}{
+			{
+				name:          "missing id",
+				policy:        &domain.Policy{Steps: validSteps},
+				expectedField: "id",
+			},
+			{
+				name:          "empty steps",
+				policy:        &domain.Policy{ID: "test", Steps: []*domain.Step{}},
+				expectedField: "steps",
+			},
+			{
+				name: "missing step name",
+				policy: &domain.Policy{ID: "test", Steps: []*domain.Step{
+					{Approvers: "$resource.details.owner"},
+				}},
+				expectedField: "steps.0.name",
+			},
+			{
+				name: "unrecognized approvers key",
+				policy: &domain.Policy{ID: "test", Steps: []*domain.Step{
+					validSteps[0],
+					{Name: "step_2", Approvers: "$resources.details.owner"},
+				}},
+				expectedField: "steps.1.approvers",
+			},
+			{
+				name: "step without approvers and conditions",
+				policy: &domain.Policy{ID: "test", Steps: []*domain.Step{
+					{Name: "step_1"},
+				}},
+				expectedField: "steps.0",
+			},
+			{
+				name: "condition without match",
+				policy: &domain.Policy{ID: "test", Steps: []*domain.Step{
+					{Name: "step_1", Conditions: []*domain.Condition{{Field: "$resource.details.is_pii"}}},
+				}},
+				expectedField: "steps.0.conditions.0.match",
+			},
+			{
+				name: "unrecognized delegation chain member",
+				policy: &domain.Policy{ID: "test", Steps: []*domain.Step{
+					{
+						Name:      "step_1",
+						Approvers: "$resource.details.owner",
+						DelegationChain: &domain.DelegationChain{
+							Members: []string{domain.DelegationKeyManagerChain, "$department_head"},
+						},
+					},
+				}},
+				expectedField: "steps.0.delegation_chain.members.1",
+			},
+			{
+				name: "check with a non-http url",
+				policy: &domain.Policy{ID: "test", Steps: []*domain.Step{
+					{
+						Name:      "step_1",
+						Approvers: "$resource.details.owner",
+						Check:     &domain.StepCheck{URL: "ftp://lms.example.com/check"},
+					},
+				}},
+				expectedField: "steps.0.check.url",
+			},
+			{
+				name: "sensitivity tier step without approvers",
+				policy: &domain.Policy{ID: "test", Steps: validSteps, SensitivityTiers: []*domain.SensitivityTier{
+					{Sensitivity: "high", Steps: []*domain.Step{
+						{Name: "vp_approval", Conditions: []*domain.Condition{{Field: "$resource.details.is_pii", Match: &domain.MatchCondition{Eq: true}}}},
+					}},
+				}},
+				expectedField: "sensitivity_tiers.0.steps.0",
+			},
+		}
+
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				actualError := s.service.Create(context.Background(), tc.policy)
+
+				s.True(errors.Is(actualError, policy.ErrInvalidPolicySchema))
+				s.Contains(actualError.Error(), tc.expectedField+":")
+			})
+		}
+	})
+
 	s.Run("should set version to 1", func() {
 		p := &domain.Policy{
-			ID: "test",
+			ID:    "test",
+			Steps: validSteps,
 		}
 
 		expectedPolicy := &domain.Policy{
 			ID:      p.ID,
 			Version: 1,
+			Steps:   validSteps,
 		}
-		s.mockPolicyRepository.On("Create", p).Return(nil).Once()
+		s.mockAppealRepository.On("IsPolicyVersionUsed", mock.Anything, p.ID, uint(1)).Return(false, nil).Once()
+		s.mockPolicyRepository.On("Create", mock.Anything, p).Return(nil).Once()
 
-		actualError := s.service.Create(p)
+		actualError := s.service.Create(context.Background(), p)
 
 		s.Nil(actualError)
 		s.Equal(expectedPolicy, p)
 		s.mockPolicyRepository.AssertExpectations(s.T())
 	})
 
+	s.Run("should return error if the policy id exists and its first version is used by an appeal", func() {
+		existingPolicy := &domain.Policy{ID: "test", Version: 1}
+		s.mockAppealRepository.On("IsPolicyVersionUsed", mock.Anything, existingPolicy.ID, uint(1)).Return(true, nil).Once()
+
+		actualError := s.service.Create(context.Background(), &domain.Policy{ID: existingPolicy.ID, Steps: validSteps})
+
+		s.EqualError(actualError, policy.ErrPolicyVersionInUse.Error())
+	})
+
 	s.Run("should pass the model from the param", func() {
-		s.mockPolicyRepository.On("Create", p).Return(nil).Once()
+		s.mockAppealRepository.On("IsPolicyVersionUsed", mock.Anything, p.ID, uint(1)).Return(false, nil).Once()
+		s.mockPolicyRepository.On("Create", mock.Anything, p).Return(nil).Once()
 
-		actualError := s.service.Create(p)
+		actualError := s.service.Create(context.Background(), p)
 
 		s.Nil(actualError)
 		s.mockPolicyRepository.AssertExpectations(s.T())
@@ -68,9 +303,9 @@ func (s *ServiceTestSuite) TestCreate() {
 func (s *ServiceTestSuite) TestFind() {
 	s.Run("should return nil and error if got error from repository", func() {
 		expectedError := errors.New("error from repository")
-		s.mockPolicyRepository.On("Find").Return(nil, expectedError).Once()
+		s.mockPolicyRepository.On("Find", mock.Anything).Return(nil, expectedError).Once()
 
-		actualResult, actualError := s.service.Find()
+		actualResult, actualError := s.service.Find(context.Background())
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -78,9 +313,9 @@ func (s *ServiceTestSuite) TestFind() {
 
 	s.Run("should return list of records on success", func() {
 		expectedResult := []*domain.Policy{}
-		s.mockPolicyRepository.On("Find").Return(expectedResult, nil).Once()
+		s.mockPolicyRepository.On("Find", mock.Anything).Return(expectedResult, nil).Once()
 
-		actualResult, actualError := s.service.Find()
+		actualResult, actualError := s.service.Find(context.Background())
 
 		s.Equal(expectedResult, actualResult)
 		s.Nil(actualError)
@@ -91,9 +326,9 @@ func (s *ServiceTestSuite) TestFind() {
 func (s *ServiceTestSuite) TestGetOne() {
 	s.Run("should return nil and error if got error from repository", func() {
 		expectedError := errors.New("error from repository")
-		s.mockPolicyRepository.On("GetOne", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
+		s.mockPolicyRepository.On("GetOne", mock.Anything, mock.Anything, mock.Anything).Return(nil, expectedError).Once()
 
-		actualResult, actualError := s.service.GetOne("", 0)
+		actualResult, actualError := s.service.GetOne(context.Background(), "", 0)
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -101,9 +336,9 @@ func (s *ServiceTestSuite) TestGetOne() {
 
 	s.Run("should return list of records on success", func() {
 		expectedResult := &domain.Policy{}
-		s.mockPolicyRepository.On("GetOne", mock.Anything, mock.Anything).Return(expectedResult, nil).Once()
+		s.mockPolicyRepository.On("GetOne", mock.Anything, mock.Anything, mock.Anything).Return(expectedResult, nil).Once()
 
-		actualResult, actualError := s.service.GetOne("", 0)
+		actualResult, actualError := s.service.GetOne(context.Background(), "", 0)
 
 		s.Equal(expectedResult, actualResult)
 		s.Nil(actualError)
@@ -116,14 +351,23 @@ func (s *ServiceTestSuite) TestUpdate() {
 		p := &domain.Policy{}
 		expectedError := policy.ErrEmptyIDParam
 
-		actualError := s.service.Update(p)
+		actualError := s.service.Update(context.Background(), p)
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
+	s.Run("should return error if policy doesn't exist", func() {
+		s.mockPolicyRepository.On("GetOne", mock.Anything, "test", uint(0)).Return(nil, nil).Once()
+
+		actualError := s.service.Update(context.Background(), &domain.Policy{ID: "test", Steps: validSteps})
+
+		s.EqualError(actualError, policy.ErrPolicyDoesNotExists.Error())
+	})
+
 	s.Run("should return increment policy version", func() {
 		p := &domain.Policy{
-			ID: "test",
+			ID:    "test",
+			Steps: validSteps,
 		}
 
 		expectedLatestPolicy := &domain.Policy{
@@ -133,14 +377,56 @@ func (s *ServiceTestSuite) TestUpdate() {
 		expectedCreationPolicy := &domain.Policy{
 			ID:      p.ID,
 			Version: expectedLatestPolicy.Version + 1,
+			Steps:   validSteps,
 		}
-		s.mockPolicyRepository.On("GetOne", p.ID, p.Version).Return(expectedLatestPolicy, nil).Once()
-		s.mockPolicyRepository.On("Create", expectedCreationPolicy).Return(nil)
+		s.mockPolicyRepository.On("GetOne", mock.Anything, p.ID, uint(0)).Return(expectedLatestPolicy, nil).Once()
+		s.mockPolicyRepository.On("Create", mock.Anything, expectedCreationPolicy).Return(nil).Once()
 
-		s.service.Update(p)
+		actualError := s.service.Update(context.Background(), p)
 
+		s.Nil(actualError)
 		s.mockPolicyRepository.AssertExpectations(s.T())
 	})
+
+	s.Run("should create the version following the latest one if the update is based on an outdated version", func() {
+		p := &domain.Policy{
+			ID:      "test",
+			Version: 2,
+			Steps:   validSteps,
+		}
+		expectedCreationPolicy := &domain.Policy{
+			ID:      p.ID,
+			Version: 6,
+			Steps:   validSteps,
+		}
+		s.mockPolicyRepository.On("GetOne", mock.Anything, p.ID, uint(0)).Return(&domain.Policy{ID: p.ID, Version: 5}, nil).Once()
+		s.mockPolicyRepository.On("Create", mock.Anything, expectedCreationPolicy).Return(nil).Once()
+
+		actualError := s.service.Update(context.Background(), p)
+
+		s.Nil(actualError)
+		s.Equal(uint(6), p.Version)
+	})
+
+	s.Run("should keep the new version in the organization of the latest one", func() {
+		p := &domain.Policy{
+			ID:    "test",
+			Steps: validSteps,
+		}
+		expectedCreationPolicy := &domain.Policy{
+			ID:             p.ID,
+			Version:        6,
+			Steps:          validSteps,
+			OrganizationID: "org-a",
+		}
+		s.mockPolicyRepository.On("GetOne", mock.Anything, p.ID, uint(0)).Return(&domain.Policy{ID: p.ID, Version: 5, OrganizationID: "org-a"}, nil).Once()
+		s.mockPolicyRepository.On("Create", mock.Anything, expectedCreationPolicy).Return(nil).Once()
+
+		actualError := s.service.Update(context.Background(), p)
+
+		s.Nil(actualError)
+		s.Equal("org-a", p.OrganizationID)
+	})
 }
 
 func TestService(t *testing.T) {
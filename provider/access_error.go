@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/odpf/guardian/domain"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	AccessOperationGrant  = "grant"
+	AccessOperationRevoke = "revoke"
+)
+
+// StatusCodeError can be implemented by the provider errors to expose the status code returned by the
+// provider API
+type StatusCodeError interface {
+	StatusCode() int
+}
+
+// AccessError adds the context of a failed access operation to the error returned by the provider.
+// It unwraps to the provider error so the callers can still match the sentinel errors
+type AccessError struct {
+	Operation    string
+	ProviderType string
+	ProviderURN  string
+	ResourceType string
+	ResourceURN  string
+	Role         string
+	// Code is the status code returned by the provider API. It's 0 if unknown
+	Code int
+	// Details are the provider API error details, e.g. the reasons of a Google API error
+	Details []string
+
+	Err error
+}
+
+func newAccessError(operation string, a *domain.Appeal, err error) *AccessError {
+	e := &AccessError{
+		Operation:    operation,
+		ProviderType: a.Resource.ProviderType,
+		ProviderURN:  a.Resource.ProviderURN,
+		ResourceType: a.Resource.Type,
+		ResourceURN:  a.Resource.URN,
+		Role:         a.Role,
+		Err:          err,
+	}
+
+	var googleErr *googleapi.Error
+	var statusCodeErr StatusCodeError
+	if errors.As(err, &googleErr) {
+		e.Code = googleErr.Code
+		for _, item := range googleErr.Errors {
+			e.Details = append(e.Details, fmt.Sprintf("%s: %s", item.Reason, item.Message))
+		}
+	} else if errors.As(err, &statusCodeErr) {
+		e.Code = statusCodeErr.StatusCode()
+	}
+
+	return e
+}
+
+func (e *AccessError) Error() string {
+	fields := []string{
+		fmt.Sprintf("provider_type=%s", e.ProviderType),
+		fmt.Sprintf("provider_urn=%s", e.ProviderURN),
+		fmt.Sprintf("resource_type=%s", e.ResourceType),
+		fmt.Sprintf("resource_urn=%s", e.ResourceURN),
+		fmt.Sprintf("role=%s", e.Role),
+	}
+	if e.Code != 0 {
+		fields = append(fields, fmt.Sprintf("code=%d", e.Code))
+	}
+	if len(e.Details) > 0 {
+		fields = append(fields, fmt.Sprintf("details=[%s]", strings.Join(e.Details, "; ")))
+	}
+	return fmt.Sprintf("failed to %s access (%s): %s", e.Operation, strings.Join(fields, " "), e.Err)
+}
+
+func (e *AccessError) Unwrap() error {
+	return e.Err
+}
@@ -17,6 +17,7 @@ type bigQueryClient struct {
 }
 
 func newBigQueryClient(projectID string, credentialsJSON []byte) (*bigQueryClient, error) {
+	// the client is cached and reused across requests, so it must not be bound to the caller's context
 	ctx := context.Background()
 	client, err := bq.NewClient(ctx, projectID, option.WithCredentialsJSON(credentialsJSON))
 	if err != nil {
@@ -67,36 +68,50 @@ func (c *bigQueryClient) GetDatasets(ctx context.Context) ([]*Dataset, error) {
 			return nil, err
 		}
 
+		// the labels are only available in the dataset metadata
+		metadata, err := dataset.Metadata(ctx)
+		if err != nil {
+			return nil, err
+		}
+
 		results = append(results, &Dataset{
 			ProjectID: dataset.ProjectID,
 			DatasetID: dataset.DatasetID,
+			Labels:    metadata.Labels,
 		})
 	}
 
 	return results, nil
 }
 
-// GetTables returns all tables within a dataset
-func (c *bigQueryClient) GetTables(ctx context.Context, datasetID string) ([]*Table, error) {
-	var results []*Table
-	it := c.client.Dataset(datasetID).Tables(ctx)
+// ListTables calls fn for every page of the dataset tables, listed page by page upstream
+func (c *bigQueryClient) ListTables(ctx context.Context, datasetID string, pageSize int, fn func([]*Table) error) error {
+	pager := iterator.NewPager(c.client.Dataset(datasetID).Tables(ctx), pageSize, "")
 	for {
-		table, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
+		var page []*bq.Table
+		nextPageToken, err := pager.NextPage(&page)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		results = append(results, &Table{
-			ProjectID: table.ProjectID,
-			DatasetID: table.DatasetID,
-			TableID:   table.TableID,
-		})
-	}
+		tables := []*Table{}
+		for _, t := range page {
+			tables = append(tables, &Table{
+				ProjectID: t.ProjectID,
+				DatasetID: t.DatasetID,
+				TableID:   t.TableID,
+			})
+		}
+		if len(tables) > 0 {
+			if err := fn(tables); err != nil {
+				return err
+			}
+		}
 
-	return results, nil
+		if nextPageToken == "" {
+			return nil
+		}
+	}
 }
 
 func (c *bigQueryClient) ResolveDatasetRole(role string) (bq.AccessRole, error) {
@@ -170,6 +185,26 @@ func (c *bigQueryClient) RevokeDatasetAccess(ctx context.Context, d *Dataset, us
 	return err
 }
 
+// HasDatasetAccess returns true if the user has the role in the dataset access list
+func (c *bigQueryClient) HasDatasetAccess(ctx context.Context, d *Dataset, user, role string) (bool, error) {
+	metadata, err := c.client.Dataset(d.DatasetID).Metadata(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	bqRole, err := c.ResolveDatasetRole(role)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range metadata.Access {
+		if a.Entity == user && a.Role == bqRole {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (c *bigQueryClient) GrantTableAccess(ctx context.Context, t *Table, user, role string) error {
 	resourceName := fmt.Sprintf("projects/%s/datasets/%s/tables/%s", c.projectID, t.DatasetID, t.TableID)
 	member := fmt.Sprintf("user:%s", user)
@@ -249,6 +284,29 @@ func (c *bigQueryClient) RevokeTableAccess(ctx context.Context, t *Table, user,
 	return err
 }
 
+// HasTableAccess returns true if the user is bound to the role in the table IAM policy
+func (c *bigQueryClient) HasTableAccess(ctx context.Context, t *Table, user, role string) (bool, error) {
+	resourceName := fmt.Sprintf("projects/%s/datasets/%s/tables/%s", c.projectID, t.DatasetID, t.TableID)
+	member := fmt.Sprintf("user:%s", user)
+
+	getIamPolicyRequest := &bqApi.GetIamPolicyRequest{
+		Options: &bqApi.GetPolicyOptions{
+			RequestedPolicyVersion: 1,
+		},
+	}
+	policy, err := c.apiClient.Tables.GetIamPolicy(resourceName, getIamPolicyRequest).Context(ctx).Do()
+	if err != nil {
+		return false, err
+	}
+	for _, b := range policy.Bindings {
+		if b.Role == role && containsString(b.Members, member) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func containsString(arr []string, v string) bool {
 	for _, item := range arr {
 		if item == v {
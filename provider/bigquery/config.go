@@ -3,6 +3,7 @@ package bigquery
 import (
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -114,7 +115,7 @@ func (c *Config) parseAndValidate() error {
 	for _, resource := range c.ProviderConfig.Resources {
 		for _, role := range resource.Roles {
 			for i, permission := range role.Permissions {
-				if permissionConfig, err := c.validatePermission(permission); err != nil {
+				if permissionConfig, err := c.validatePermission(resource.Type, permission); err != nil {
 					validationErrors = append(validationErrors, err)
 				} else {
 					role.Permissions[i] = permissionConfig
@@ -154,7 +155,7 @@ func (c *Config) validateCredentials(value interface{}) (*Credentials, error) {
 	return &configValue, nil
 }
 
-func (c *Config) validatePermission(value interface{}) (*PermissionConfig, error) {
+func (c *Config) validatePermission(resourceType string, value interface{}) (*PermissionConfig, error) {
 	permissionConfig, ok := value.(map[string]interface{})
 	if !ok {
 		return nil, ErrInvalidPermissionConfig
@@ -165,5 +166,17 @@ func (c *Config) validatePermission(value interface{}) (*PermissionConfig, error
 		return nil, err
 	}
 
-	return &pc, utils.ValidateStruct(pc)
+	if err := utils.ValidateStruct(pc); err != nil {
+		return nil, err
+	}
+
+	// dataset access uses the legacy roles, while the rest are IAM roles
+	if resourceType == ResourceTypeDataset && pc.Target == "" {
+		datasetRoles := []string{DatasetRoleReader, DatasetRoleWriter, DatasetRoleOwner}
+		if !containsString(datasetRoles, pc.Name) {
+			return nil, fmt.Errorf("%w: %q is not a dataset role, available roles: %s", ErrInvalidRole, pc.Name, strings.Join(datasetRoles, ", "))
+		}
+	}
+
+	return &pc, nil
 }
@@ -79,6 +79,59 @@ func TestValidate(t *testing.T) {
 		}
 	})
 
+	t.Run("should return error if dataset permission is not a dataset role", func(t *testing.T) {
+		pc := &domain.ProviderConfig{
+			Credentials: validCredentials,
+			Resources: []*domain.ResourceConfig{
+				{
+					Type: bigquery.ResourceTypeDataset,
+					Roles: []*domain.RoleConfig{
+						{
+							Permissions: []interface{}{
+								map[string]interface{}{
+									"name": "READR",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		err := bigquery.NewConfig(pc, mockCrypto).ParseAndValidate()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "READER, WRITER, OWNER")
+	})
+
+	t.Run("should allow iam roles for dataset permission with target", func(t *testing.T) {
+		pc := &domain.ProviderConfig{
+			Credentials: validCredentials,
+			Resources: []*domain.ResourceConfig{
+				{
+					Type: bigquery.ResourceTypeDataset,
+					Roles: []*domain.RoleConfig{
+						{
+							Permissions: []interface{}{
+								map[string]interface{}{
+									"name":   "roles/bigquery.jobUser",
+									"target": "project-id",
+								},
+								map[string]interface{}{
+									"name": bigquery.DatasetRoleReader,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		err := bigquery.NewConfig(pc, mockCrypto).ParseAndValidate()
+
+		assert.Nil(t, err)
+	})
+
 	t.Run("should update credentials and permission config values into castable bigquery config", func(t *testing.T) {
 		pc := &domain.ProviderConfig{
 			Credentials: validCredentials,
@@ -16,6 +16,7 @@ var (
 	ErrInvalidRole             = errors.New("invalid role")
 	ErrInvalidResourceType     = errors.New("invalid resource type")
 	ErrInvalidTableURN         = errors.New("table URN is invalid")
+	ErrInvalidDatasetURN       = errors.New("dataset URN is invalid")
 	ErrPermissionAlreadyExists = errors.New("permission already exists")
 	ErrPermissionNotFound      = errors.New("permission not found")
 	ErrNilProviderConfig       = errors.New("provider config can't be nil")
@@ -14,6 +14,7 @@ type iamClient struct {
 }
 
 func newCloudResourceManagerClient(credentialsJSON []byte) (*iamClient, error) {
+	// the token source refreshes with this context for as long as the cached client lives
 	ctx := context.Background()
 	creds, err := google.CredentialsFromJSON(ctx, credentialsJSON, cloudresourcemanager.CloudPlatformScope)
 	if err != nil {
@@ -112,3 +113,20 @@ func (c *iamClient) RevokeAccess(ctx context.Context, projectID, user, role stri
 	_, err = c.cloudResourceManagerService.Projects.SetIamPolicy(projectID, setIamPolicyRequest).Context(ctx).Do()
 	return err
 }
+
+// HasAccess returns true if the user is bound to the role in the project IAM policy
+func (c *iamClient) HasAccess(ctx context.Context, projectID, user, role string) (bool, error) {
+	policy, err := c.cloudResourceManagerService.Projects.GetIamPolicy(projectID, &cloudresourcemanager.GetIamPolicyRequest{}).Context(ctx).Do()
+	if err != nil {
+		return false, err
+	}
+
+	member := fmt.Sprintf("user:%s", user)
+	for _, b := range policy.Bindings {
+		if b.Role == role && containsString(b.Members, member) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -5,13 +5,17 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/provider/clientcache"
 )
 
+// resourcePageSize is the number of tables listed per page while fetching the resources
+const resourcePageSize = 1000
+
 // Provider for bigquery
 type Provider struct {
 	typeName   string
-	bqClients  map[string]*bigQueryClient
-	iamClients map[string]*iamClient
+	bqClients  *clientcache.Cache
+	iamClients *clientcache.Cache
 	crypto     domain.Crypto
 }
 
@@ -19,8 +23,8 @@ type Provider struct {
 func NewProvider(typeName string, crypto domain.Crypto) *Provider {
 	return &Provider{
 		typeName:   typeName,
-		bqClients:  map[string]*bigQueryClient{},
-		iamClients: map[string]*iamClient{},
+		bqClients:  clientcache.New(),
+		iamClients: clientcache.New(),
 		crypto:     crypto,
 	}
 }
@@ -30,8 +34,19 @@ func (p *Provider) GetType() string {
 	return p.typeName
 }
 
+// Capabilities returns the operations supported by the BigQuery provider
+func (p *Provider) Capabilities() domain.ProviderCapabilities {
+	return domain.ProviderCapabilities{
+		BatchGrant:         false,
+		AccessListing:      false,
+		AccessVerification: true,
+		PermanentAccess:    true,
+		Revocation:         true,
+	}
+}
+
 // CreateConfig validates provider config
-func (p *Provider) CreateConfig(pc *domain.ProviderConfig) error {
+func (p *Provider) CreateConfig(ctx context.Context, pc *domain.ProviderConfig) error {
 	c := NewConfig(pc, p.crypto)
 
 	if err := c.ParseAndValidate(); err != nil {
@@ -42,40 +57,56 @@ func (p *Provider) CreateConfig(pc *domain.ProviderConfig) error {
 }
 
 // GetResources returns BigQuery dataset and table resources
-func (p *Provider) GetResources(pc *domain.ProviderConfig) ([]*domain.Resource, error) {
+func (p *Provider) GetResources(ctx context.Context, pc *domain.ProviderConfig) ([]*domain.Resource, error) {
+	resources := []*domain.Resource{}
+	if err := p.GetResourcesPaged(ctx, pc, func(page []*domain.Resource) error {
+		resources = append(resources, page...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+// GetResourcesPaged lists the resources page by page, every dataset and then its tables
+func (p *Provider) GetResourcesPaged(ctx context.Context, pc *domain.ProviderConfig, fn func([]*domain.Resource) error) error {
 	client, err := p.getBigQueryClient(pc.URN, Credentials(pc.Credentials.(string)))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	resources := []*domain.Resource{}
-	ctx := context.Background()
 	datasets, err := client.GetDatasets(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	for _, d := range datasets {
 		dataset := d.toDomain()
 		dataset.ProviderType = pc.Type
 		dataset.ProviderURN = pc.URN
-		resources = append(resources, dataset)
-
-		tables, err := client.GetTables(ctx, dataset.Name)
-		if err != nil {
-			return nil, err
+		if err := fn([]*domain.Resource{dataset}); err != nil {
+			return err
 		}
-		for _, t := range tables {
-			table := t.toDomain()
-			table.ProviderType = pc.Type
-			table.ProviderURN = pc.URN
-			resources = append(resources, table)
+
+		if err := client.ListTables(ctx, dataset.Name, resourcePageSize, func(tables []*Table) error {
+			resources := []*domain.Resource{}
+			for _, t := range tables {
+				table := t.toDomain()
+				table.ProviderType = pc.Type
+				table.ProviderURN = pc.URN
+				table.Tags = dataset.Tags
+				resources = append(resources, table)
+			}
+			return fn(resources)
+		}); err != nil {
+			return err
 		}
 	}
 
-	return resources, nil
+	return nil
 }
 
-func (p *Provider) GrantAccess(pc *domain.ProviderConfig, a *domain.Appeal) error {
+func (p *Provider) GrantAccess(ctx context.Context, pc *domain.ProviderConfig, a *domain.Appeal) error {
 	if err := validateProviderConfigAndAppealParams(pc, a); err != nil {
 		return err
 	}
@@ -95,7 +126,6 @@ func (p *Provider) GrantAccess(pc *domain.ProviderConfig, a *domain.Appeal) erro
 		return err
 	}
 
-	ctx := context.TODO()
 	if a.Resource.Type == ResourceTypeDataset {
 		d := new(Dataset)
 		if err := d.fromDomain(a.Resource); err != nil {
@@ -139,7 +169,7 @@ func (p *Provider) GrantAccess(pc *domain.ProviderConfig, a *domain.Appeal) erro
 	return ErrInvalidResourceType
 }
 
-func (p *Provider) RevokeAccess(pc *domain.ProviderConfig, a *domain.Appeal) error {
+func (p *Provider) RevokeAccess(ctx context.Context, pc *domain.ProviderConfig, a *domain.Appeal) error {
 	if err := validateProviderConfigAndAppealParams(pc, a); err != nil {
 		return err
 	}
@@ -159,7 +189,6 @@ func (p *Provider) RevokeAccess(pc *domain.ProviderConfig, a *domain.Appeal) err
 		return err
 	}
 
-	ctx := context.TODO()
 	if a.Resource.Type == ResourceTypeDataset {
 		d := new(Dataset)
 		if err := d.fromDomain(a.Resource); err != nil {
@@ -203,34 +232,94 @@ func (p *Provider) RevokeAccess(pc *domain.ProviderConfig, a *domain.Appeal) err
 	return ErrInvalidResourceType
 }
 
-func (p *Provider) getBigQueryClient(projectID string, credentials Credentials) (*bigQueryClient, error) {
-	if p.bqClients[projectID] != nil {
-		return p.bqClients[projectID], nil
+// VerifyAccess checks whether all the permissions of the appeal's role are present in BigQuery
+func (p *Provider) VerifyAccess(ctx context.Context, pc *domain.ProviderConfig, a *domain.Appeal) (bool, error) {
+	if err := validateProviderConfigAndAppealParams(pc, a); err != nil {
+		return false, err
 	}
 
-	credentials.Decrypt(p.crypto)
-	client, err := newBigQueryClient(projectID, []byte(credentials))
+	permissions, err := getPermissions(pc.Resources, a)
 	if err != nil {
-		return nil, err
+		return false, err
+	}
+
+	bqClient, err := p.getBigQueryClient(pc.URN, Credentials(pc.Credentials.(string)))
+	if err != nil {
+		return false, err
+	}
+
+	iamClient, err := p.getIamClient(pc.URN, Credentials(pc.Credentials.(string)))
+	if err != nil {
+		return false, err
+	}
+
+	for _, permission := range permissions {
+		var hasAccess bool
+		if permission.Target != "" {
+			hasAccess, err = iamClient.HasAccess(ctx, permission.Target, a.User, permission.Name)
+		} else if a.Resource.Type == ResourceTypeDataset {
+			d := new(Dataset)
+			if err := d.fromDomain(a.Resource); err != nil {
+				return false, err
+			}
+			hasAccess, err = bqClient.HasDatasetAccess(ctx, d, a.User, permission.Name)
+		} else if a.Resource.Type == ResourceTypeTable {
+			t := new(Table)
+			if err := t.fromDomain(a.Resource); err != nil {
+				return false, err
+			}
+			hasAccess, err = bqClient.HasTableAccess(ctx, t, a.User, permission.Name)
+		} else {
+			return false, ErrInvalidResourceType
+		}
+
+		if err != nil {
+			return false, err
+		}
+		if !hasAccess {
+			return false, nil
+		}
 	}
 
-	p.bqClients[projectID] = client
-	return client, nil
+	return true, nil
 }
 
-func (p *Provider) getIamClient(projectID string, credentials Credentials) (*iamClient, error) {
-	if p.iamClients[projectID] != nil {
-		return p.iamClients[projectID], nil
+func (p *Provider) getBigQueryClient(projectID string, credentials Credentials) (*bigQueryClient, error) {
+	client, err := p.bqClients.Get(projectID, credentials, func() (interface{}, error) {
+		credentials.Decrypt(p.crypto)
+		client, err := newBigQueryClient(projectID, []byte(credentials))
+		if err != nil {
+			return nil, err
+		}
+
+		return client, nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return client.(*bigQueryClient), nil
+}
 
-	credentials.Decrypt(p.crypto)
-	client, err := newCloudResourceManagerClient([]byte(credentials))
+func (p *Provider) getIamClient(projectID string, credentials Credentials) (*iamClient, error) {
+	client, err := p.iamClients.Get(projectID, credentials, func() (interface{}, error) {
+		credentials.Decrypt(p.crypto)
+		client, err := newCloudResourceManagerClient([]byte(credentials))
+		if err != nil {
+			return nil, err
+		}
+
+		return client, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	return client.(*iamClient), nil
+}
 
-	p.iamClients[projectID] = client
-	return client, nil
+// EvictClients drops the clients of the project, e.g. once its credentials are updated
+func (p *Provider) EvictClients(projectID string) {
+	p.bqClients.Evict(projectID)
+	p.iamClients.Evict(projectID)
 }
 
 func validateProviderConfigAndAppealParams(pc *domain.ProviderConfig, a *domain.Appeal) error {
@@ -18,6 +18,58 @@ const (
 type Dataset struct {
 	ProjectID string
 	DatasetID string
+	Labels    map[string]string
+}
+
+// ParseURN splits the dataset URN "project:dataset" or the table URN "project:dataset.table"
+// into its project, dataset, and table. Domain-scoped project ids, e.g. "example.com:project", are supported
+func (p *Provider) ParseURN(resourceType, urn string) (*domain.ResourceURN, error) {
+	switch resourceType {
+	case ResourceTypeDataset:
+		projectID, datasetID, ok := splitLast(urn, ":")
+		if !ok {
+			return nil, ErrInvalidDatasetURN
+		}
+		return &domain.ResourceURN{Type: resourceType, Parts: []string{projectID, datasetID}}, nil
+	case ResourceTypeTable:
+		datasetURN, tableID, ok := splitLast(urn, ".")
+		if !ok {
+			return nil, ErrInvalidTableURN
+		}
+		projectID, datasetID, ok := splitLast(datasetURN, ":")
+		if !ok {
+			return nil, ErrInvalidTableURN
+		}
+		return &domain.ResourceURN{Type: resourceType, Parts: []string{projectID, datasetID, tableID}}, nil
+	default:
+		return nil, ErrInvalidResourceType
+	}
+}
+
+// FormatURN builds the dataset or table URN from its project, dataset, and table
+func (p *Provider) FormatURN(urn *domain.ResourceURN) (string, error) {
+	switch urn.Type {
+	case ResourceTypeDataset:
+		if len(urn.Parts) != 2 {
+			return "", ErrInvalidDatasetURN
+		}
+		return formatDatasetURN(urn.Parts[0], urn.Parts[1]), nil
+	case ResourceTypeTable:
+		if len(urn.Parts) != 3 {
+			return "", ErrInvalidTableURN
+		}
+		return formatTableURN(urn.Parts[0], urn.Parts[1], urn.Parts[2]), nil
+	default:
+		return "", ErrInvalidResourceType
+	}
+}
+
+func formatDatasetURN(projectID, datasetID string) string {
+	return fmt.Sprintf("%s:%s", projectID, datasetID)
+}
+
+func formatTableURN(projectID, datasetID, tableID string) string {
+	return fmt.Sprintf("%s.%s", formatDatasetURN(projectID, datasetID), tableID)
 }
 
 func (d *Dataset) fromDomain(r *domain.Resource) error {
@@ -34,7 +86,8 @@ func (d *Dataset) toDomain() *domain.Resource {
 	return &domain.Resource{
 		Type: ResourceTypeDataset,
 		Name: d.DatasetID,
-		URN:  fmt.Sprintf("%s:%s", d.ProjectID, d.DatasetID),
+		URN:  formatDatasetURN(d.ProjectID, d.DatasetID),
+		Tags: d.Labels,
 	}
 }
 
@@ -64,6 +117,15 @@ func (t *Table) toDomain() *domain.Resource {
 	return &domain.Resource{
 		Type: ResourceTypeTable,
 		Name: t.TableID,
-		URN:  fmt.Sprintf("%s:%s.%s", t.ProjectID, t.DatasetID, t.TableID),
+		URN:  formatTableURN(t.ProjectID, t.DatasetID, t.TableID),
+	}
+}
+
+// splitLast splits s around the last separator, both sides must not be empty
+func splitLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i <= 0 || i == len(s)-len(sep) {
+		return "", "", false
 	}
+	return s[:i], s[i+len(sep):], true
 }
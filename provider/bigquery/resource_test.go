@@ -0,0 +1,79 @@
+package bigquery_test
+
+import (
+	"testing"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/provider/bigquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURNFormat(t *testing.T) {
+	p := bigquery.NewProvider("bigquery", nil)
+
+	t.Run("should parse and format the urn back", func(t *testing.T) {
+		testCases := []struct {
+			resourceType string
+			urn          string
+			expectedURN  *domain.ResourceURN
+		}{
+			{
+				resourceType: bigquery.ResourceTypeDataset,
+				urn:          "project:dataset",
+				expectedURN:  &domain.ResourceURN{Type: bigquery.ResourceTypeDataset, Parts: []string{"project", "dataset"}},
+			},
+			{
+				resourceType: bigquery.ResourceTypeTable,
+				urn:          "project:dataset.table",
+				expectedURN:  &domain.ResourceURN{Type: bigquery.ResourceTypeTable, Parts: []string{"project", "dataset", "table"}},
+			},
+			{
+				resourceType: bigquery.ResourceTypeTable,
+				urn:          "example.com:project:dataset.table",
+				expectedURN:  &domain.ResourceURN{Type: bigquery.ResourceTypeTable, Parts: []string{"example.com:project", "dataset", "table"}},
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.urn, func(t *testing.T) {
+				actualURN, actualError := p.ParseURN(tc.resourceType, tc.urn)
+
+				assert.Nil(t, actualError)
+				assert.Equal(t, tc.expectedURN, actualURN)
+
+				formattedURN, actualError := p.FormatURN(actualURN)
+
+				assert.Nil(t, actualError)
+				assert.Equal(t, tc.urn, formattedURN)
+			})
+		}
+	})
+
+	t.Run("should return error if the urn is invalid", func(t *testing.T) {
+		testCases := []struct {
+			resourceType  string
+			urn           string
+			expectedError error
+		}{
+			{bigquery.ResourceTypeDataset, "dataset", bigquery.ErrInvalidDatasetURN},
+			{bigquery.ResourceTypeDataset, "project:", bigquery.ErrInvalidDatasetURN},
+			{bigquery.ResourceTypeTable, "project:dataset", bigquery.ErrInvalidTableURN},
+			{bigquery.ResourceTypeTable, "dataset.table", bigquery.ErrInvalidTableURN},
+			{"view", "project:dataset.view", bigquery.ErrInvalidResourceType},
+		}
+
+		for _, tc := range testCases {
+			actualURN, actualError := p.ParseURN(tc.resourceType, tc.urn)
+
+			assert.Nil(t, actualURN)
+			assert.ErrorIs(t, actualError, tc.expectedError)
+		}
+	})
+
+	t.Run("should return error if the parts don't match the resource type", func(t *testing.T) {
+		actualURN, actualError := p.FormatURN(&domain.ResourceURN{Type: bigquery.ResourceTypeTable, Parts: []string{"project", "dataset"}})
+
+		assert.Empty(t, actualURN)
+		assert.ErrorIs(t, actualError, bigquery.ErrInvalidTableURN)
+	})
+}
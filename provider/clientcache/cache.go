@@ -0,0 +1,94 @@
+package clientcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+type entry struct {
+	mu          sync.Mutex
+	client      interface{}
+	fingerprint string
+}
+
+// Cache holds the clients of a provider, built lazily once per provider URN and shared across the concurrent
+// grant and revoke calls. A client is rebuilt once the credentials it's built with change, e.g. after they're
+// rotated, so the instances not aware of the rotation pick up the new credentials on their next call
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// New returns an empty cache
+func New() *Cache {
+	return &Cache{entries: map[string]*entry{}}
+}
+
+// Get returns the client of the provider URN, built by newClient if it isn't cached yet or the credentials
+// changed since. The credentials are compared as they're stored, i.e. encrypted, so newClient decrypts them only
+// when a client is built. The calls for the same URN wait for the client being built instead of building another
+func (c *Cache) Get(urn string, credentials interface{}, newClient func() (interface{}, error)) (interface{}, error) {
+	fingerprint, err := getFingerprint(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	e := c.getEntry(urn)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.client != nil && (e.fingerprint == "" || e.fingerprint == fingerprint) {
+		e.fingerprint = fingerprint
+		return e.client, nil
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	e.client = client
+	e.fingerprint = fingerprint
+	return client, nil
+}
+
+// Set caches the client of the provider URN, e.g. a client built outside of the cache. It's kept along with the
+// credentials of the next Get, until they change
+func (c *Cache) Set(urn string, client interface{}) {
+	e := c.getEntry(urn)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.client = client
+	e.fingerprint = ""
+}
+
+// Evict removes the client of the provider URN, the next Get builds a new one
+func (c *Cache) Evict(urn string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, urn)
+}
+
+func (c *Cache) getEntry(urn string) *entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := c.entries[urn]
+	if e == nil {
+		e = &entry{}
+		c.entries[urn] = e
+	}
+	return e
+}
+
+func getFingerprint(credentials interface{}) (string, error) {
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
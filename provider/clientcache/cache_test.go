@@ -0,0 +1,102 @@
+package clientcache_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/odpf/guardian/provider/clientcache"
+	"github.com/stretchr/testify/assert"
+)
+
+type client struct {
+	credentials string
+}
+
+func TestCache(t *testing.T) {
+	newClientFunc := func(credentials string, builds *int32) func() (interface{}, error) {
+		return func() (interface{}, error) {
+			atomic.AddInt32(builds, 1)
+			return &client{credentials}, nil
+		}
+	}
+
+	t.Run("should build the client once and share it across the concurrent calls", func(t *testing.T) {
+		c := clientcache.New()
+		var builds int32
+
+		var wg sync.WaitGroup
+		clients := make([]interface{}, 10)
+		for i := range clients {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				clients[i], _ = c.Get("urn", "credentials", newClientFunc("credentials", &builds))
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), builds)
+		for _, actualClient := range clients {
+			assert.Same(t, clients[0], actualClient)
+		}
+	})
+
+	t.Run("should rebuild the client once the credentials change", func(t *testing.T) {
+		c := clientcache.New()
+		var builds int32
+
+		_, err := c.Get("urn", map[string]interface{}{"password": "old"}, newClientFunc("old", &builds))
+		assert.Nil(t, err)
+		actualClient, err := c.Get("urn", map[string]interface{}{"password": "new"}, newClientFunc("new", &builds))
+		assert.Nil(t, err)
+
+		assert.Equal(t, int32(2), builds)
+		assert.Equal(t, &client{"new"}, actualClient)
+	})
+
+	t.Run("should rebuild the client once it's evicted", func(t *testing.T) {
+		c := clientcache.New()
+		var builds int32
+
+		_, err := c.Get("urn", "credentials", newClientFunc("credentials", &builds))
+		assert.Nil(t, err)
+		c.Evict("urn")
+		_, err = c.Get("urn", "credentials", newClientFunc("credentials", &builds))
+		assert.Nil(t, err)
+
+		assert.Equal(t, int32(2), builds)
+	})
+
+	t.Run("should keep the client set along with the credentials of the next call", func(t *testing.T) {
+		c := clientcache.New()
+		var builds int32
+		expectedClient := &client{"set"}
+
+		c.Set("urn", expectedClient)
+		actualClient, err := c.Get("urn", "credentials", newClientFunc("credentials", &builds))
+		assert.Nil(t, err)
+		assert.Same(t, expectedClient, actualClient)
+		actualClient, err = c.Get("urn", "rotated", newClientFunc("rotated", &builds))
+		assert.Nil(t, err)
+
+		assert.Equal(t, int32(1), builds)
+		assert.Equal(t, &client{"rotated"}, actualClient)
+	})
+
+	t.Run("should not cache the client failing to be built", func(t *testing.T) {
+		c := clientcache.New()
+		var builds int32
+		expectedError := errors.New("invalid credentials")
+
+		_, actualError := c.Get("urn", "credentials", func() (interface{}, error) {
+			return nil, expectedError
+		})
+		assert.Equal(t, expectedError, actualError)
+		_, err := c.Get("urn", "credentials", newClientFunc("credentials", &builds))
+		assert.Nil(t, err)
+
+		assert.Equal(t, int32(1), builds)
+	})
+}
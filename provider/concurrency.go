@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ConcurrencyConfig configures the limit of the concurrent access operations
+type ConcurrencyConfig struct {
+	// MaxInFlight is the maximum number of concurrent grant and revoke calls per provider instance.
+	// The calls are unlimited if it's less than 1
+	MaxInFlight int `mapstructure:"max_in_flight" default:"0"`
+}
+
+// ConcurrencyStats are the concurrency metrics of a provider instance, used to tune the limit
+type ConcurrencyStats struct {
+	InFlight int `json:"in_flight"`
+	Waiting  int `json:"waiting"`
+	// Waits is the number of calls that had to wait for a free slot
+	Waits int64 `json:"waits_total"`
+	// WaitTime is the total time spent waiting for a free slot
+	WaitTime time.Duration `json:"wait_time_total_ns"`
+}
+
+type semaphore struct {
+	slots chan struct{}
+	stats ConcurrencyStats
+}
+
+// ConcurrencyLimiter caps the concurrent access operations of each provider instance, identified by its
+// type and URN. Unlike rate limiting, it doesn't limit the throughput as long as the calls complete
+type ConcurrencyLimiter struct {
+	maxInFlight int
+
+	mu         sync.Mutex
+	semaphores map[string]*semaphore
+}
+
+// NewConcurrencyLimiter returns nil if the concurrency is unlimited
+func NewConcurrencyLimiter(c ConcurrencyConfig) *ConcurrencyLimiter {
+	if c.MaxInFlight < 1 {
+		return nil
+	}
+	return &ConcurrencyLimiter{
+		maxInFlight: c.MaxInFlight,
+		semaphores:  map[string]*semaphore{},
+	}
+}
+
+// Stats returns the concurrency metrics keyed by "<provider type>/<provider urn>"
+func (l *ConcurrencyLimiter) Stats() map[string]ConcurrencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := map[string]ConcurrencyStats{}
+	for key, s := range l.semaphores {
+		stats[key] = s.stats
+	}
+	return stats
+}
+
+// acquire waits for a free slot of the provider instance. The returned func releases the slot
+func (l *ConcurrencyLimiter) acquire(ctx context.Context, pc *domain.ProviderConfig) (func(), error) {
+	key := pc.Type + "/" + pc.URN
+
+	l.mu.Lock()
+	s, ok := l.semaphores[key]
+	if !ok {
+		s = &semaphore{slots: make(chan struct{}, l.maxInFlight)}
+		l.semaphores[key] = s
+	}
+	l.mu.Unlock()
+
+	release := func() {
+		<-s.slots
+		l.mu.Lock()
+		s.stats.InFlight--
+		l.mu.Unlock()
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		l.mu.Lock()
+		s.stats.InFlight++
+		l.mu.Unlock()
+		return release, nil
+	default:
+	}
+
+	start := time.Now()
+	l.mu.Lock()
+	s.stats.Waiting++
+	l.mu.Unlock()
+
+	var err error
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	wait := time.Since(start)
+
+	l.mu.Lock()
+	s.stats.Waiting--
+	s.stats.Waits++
+	s.stats.WaitTime += wait
+	if err == nil {
+		s.stats.InFlight++
+	}
+	l.mu.Unlock()
+
+	trace.SpanFromContext(ctx).AddEvent("provider.concurrency.wait", trace.WithAttributes(
+		attribute.String("provider.urn", pc.URN),
+		attribute.Int64("wait_ms", wait.Milliseconds()),
+	))
+
+	if err != nil {
+		return nil, err
+	}
+	return release, nil
+}
+
+type concurrencyLimitedProvider struct {
+	decoratedProvider
+	limiter *ConcurrencyLimiter
+}
+
+// WithConcurrencyLimit decorates the provider to wait for a free slot of the limiter before
+// granting or revoking access. The limiter can be shared by multiple providers
+func WithConcurrencyLimit(p domain.ProviderInterface, l *ConcurrencyLimiter) domain.ProviderInterface {
+	if l == nil {
+		return p
+	}
+	return &concurrencyLimitedProvider{decoratedProvider{p}, l}
+}
+
+func (p *concurrencyLimitedProvider) GrantAccess(ctx context.Context, pc *domain.ProviderConfig, a *domain.Appeal) error {
+	release, err := p.limiter.acquire(ctx, pc)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return p.ProviderInterface.GrantAccess(ctx, pc, a)
+}
+
+func (p *concurrencyLimitedProvider) RevokeAccess(ctx context.Context, pc *domain.ProviderConfig, a *domain.Appeal) error {
+	release, err := p.limiter.acquire(ctx, pc)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return p.ProviderInterface.RevokeAccess(ctx, pc, a)
+}
@@ -0,0 +1,91 @@
+package provider_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/odpf/guardian/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWithConcurrencyLimit(t *testing.T) {
+	pc := &domain.ProviderConfig{Type: "provider_type", URN: "provider_urn"}
+	a := &domain.Appeal{}
+
+	t.Run("should return the provider as is if the concurrency is unlimited", func(t *testing.T) {
+		p := new(mocks.ProviderInterface)
+		limiter := provider.NewConcurrencyLimiter(provider.ConcurrencyConfig{MaxInFlight: 0})
+
+		assert.Nil(t, limiter)
+		assert.Equal(t, p, provider.WithConcurrencyLimit(p, limiter))
+	})
+
+	t.Run("should cap the concurrent calls per provider instance", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+		p := new(mocks.ProviderInterface)
+		p.On("GrantAccess", mock.Anything, pc, a).Return(nil).Run(func(mock.Arguments) {
+			current := atomic.AddInt32(&inFlight, 1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+		})
+		limiter := provider.NewConcurrencyLimiter(provider.ConcurrencyConfig{MaxInFlight: 2})
+		limitedProvider := provider.WithConcurrencyLimit(p, limiter)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.Nil(t, limitedProvider.GrantAccess(context.Background(), pc, a))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(2), maxInFlight)
+		stats := limiter.Stats()["provider_type/provider_urn"]
+		assert.Equal(t, 0, stats.InFlight)
+		assert.Equal(t, 0, stats.Waiting)
+		assert.True(t, stats.Waits > 0)
+		assert.True(t, stats.WaitTime > 0)
+	})
+
+	t.Run("should return error if the context is done while waiting for a free slot", func(t *testing.T) {
+		release := make(chan struct{})
+		p := new(mocks.ProviderInterface)
+		p.On("RevokeAccess", mock.Anything, pc, a).Return(nil).Run(func(mock.Arguments) {
+			<-release
+		}).Once()
+		limiter := provider.NewConcurrencyLimiter(provider.ConcurrencyConfig{MaxInFlight: 1})
+		limitedProvider := provider.WithConcurrencyLimit(p, limiter)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			limitedProvider.RevokeAccess(context.Background(), pc, a)
+		}()
+		assert.Eventually(t, func() bool {
+			return limiter.Stats()["provider_type/provider_urn"].InFlight == 1
+		}, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		actualError := limitedProvider.RevokeAccess(ctx, pc, a)
+
+		assert.ErrorIs(t, actualError, context.DeadlineExceeded)
+		close(release)
+		<-done
+		p.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// decoratedProvider is embedded by the provider decorators. Besides the provider interface, it forwards the
+// optional interfaces to the decorated provider, so that decorating a provider doesn't hide its capabilities
+type decoratedProvider struct {
+	domain.ProviderInterface
+}
+
+// VerifyAccess trusts the decorated provider if it's not an access verifier, the same way
+// the service does
+func (p decoratedProvider) VerifyAccess(ctx context.Context, pc *domain.ProviderConfig, a *domain.Appeal) (bool, error) {
+	if verifier, ok := p.ProviderInterface.(domain.AccessVerifier); ok {
+		return verifier.VerifyAccess(ctx, pc, a)
+	}
+	return true, nil
+}
+
+// CheckConnection trusts the decorated provider if it's not a connection checker, the same way
+// the service does
+func (p decoratedProvider) CheckConnection(ctx context.Context, pc *domain.ProviderConfig) error {
+	if checker, ok := p.ProviderInterface.(domain.ConnectionChecker); ok {
+		return checker.CheckConnection(ctx, pc)
+	}
+	return nil
+}
+
+// GetResourcesPaged delegates to the decorated provider, it lists every resource as a single page
+// if the provider doesn't support pagination
+func (p decoratedProvider) GetResourcesPaged(ctx context.Context, pc *domain.ProviderConfig, fn func([]*domain.Resource) error) error {
+	if pager, ok := p.ProviderInterface.(domain.ResourcePager); ok {
+		return pager.GetResourcesPaged(ctx, pc, fn)
+	}
+
+	resources, err := p.ProviderInterface.GetResources(ctx, pc)
+	if err != nil {
+		return err
+	}
+	return fn(resources)
+}
+
+// EvictClients delegates to the decorated provider if it caches its clients
+func (p decoratedProvider) EvictClients(providerURN string) {
+	if evicter, ok := p.ProviderInterface.(domain.ClientEvicter); ok {
+		evicter.EvictClients(providerURN)
+	}
+}
+
+// ParseURN delegates to the decorated provider
+func (p decoratedProvider) ParseURN(resourceType, urn string) (*domain.ResourceURN, error) {
+	if formatter, ok := p.ProviderInterface.(domain.URNFormatter); ok {
+		return formatter.ParseURN(resourceType, urn)
+	}
+	return nil, ErrURNFormatNotSupported
+}
+
+// FormatURN delegates to the decorated provider
+func (p decoratedProvider) FormatURN(urn *domain.ResourceURN) (string, error) {
+	if formatter, ok := p.ProviderInterface.(domain.URNFormatter); ok {
+		return formatter.FormatURN(urn)
+	}
+	return "", ErrURNFormatNotSupported
+}
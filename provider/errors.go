@@ -14,4 +14,26 @@ var (
 	ErrNilAppeal         = errors.New("appeal can't be nil")
 	ErrNilResource       = errors.New("resource can't be nil")
 	ErrProviderNotFound  = errors.New("provider config not found")
+	// ErrPermanentAccessNotSupported is the error value if the provider config allows permanent access while the provider can't hold one
+	ErrPermanentAccessNotSupported = errors.New("permanent access is not supported by the provider")
+	// ErrDuplicateResourceType is the error value if a resource type or alias is configured more than once
+	ErrDuplicateResourceType = errors.New("resource type or alias is configured more than once")
+	// ErrURNFormatNotSupported is the error value if the provider doesn't expose the format of its resource URNs
+	ErrURNFormatNotSupported = errors.New("resource urn format is not supported by the provider")
+	// ErrResourceTypeNotFound is the error value if the resource type isn't configured in the provider
+	ErrResourceTypeNotFound = errors.New("resource type not found in the provider config")
+	// ErrInvalidMaxDuration is the error value if the max access duration is not a positive duration
+	ErrInvalidMaxDuration = errors.New("max duration should be a positive duration, e.g. 24h")
+	// ErrInvalidAccessVerification is the error value if the access verification polling config is invalid
+	ErrInvalidAccessVerification = errors.New("access verification requires positive poll_interval and timeout durations, e.g. 5s and 2m, and on_timeout either fail or activate")
+	// ErrInvalidResourceRequirement is the error value if a role's resource requirement doesn't match a resource attribute
+	ErrInvalidResourceRequirement = errors.New("resource requirement requires a $resource field, e.g. $resource.details.read_only, and a match")
+	// ErrProviderUnreachable is the error value if the provider can't be reached with the provider config
+	ErrProviderUnreachable = errors.New("provider is unreachable with the provider config")
+	// ErrRoleNotFound is the error value if the role isn't configured for the resource type
+	ErrRoleNotFound = errors.New("role not found in the provider config")
+	// ErrRoleMappingNotFound is the error value if a role without permissions has no role mapping for the provider type
+	ErrRoleMappingNotFound = errors.New("role has neither permissions nor a role mapping for the provider type")
+	// ErrProviderProfileNotFound is the error value if the provider config defining profiles misses the active or a required profile
+	ErrProviderProfileNotFound = errors.New("provider config profile not found")
 )
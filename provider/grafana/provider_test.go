@@ -1,6 +1,7 @@
 package grafana_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestGetResources(t *testing.T) {
 			Credentials: "invalid-creds",
 		}
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.Error(t, actualError)
@@ -51,7 +52,7 @@ func TestGetResources(t *testing.T) {
 			},
 		}
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -62,9 +63,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.GrafanaClient)
 		p := grafana.NewProvider("", crypto)
-		p.Clients = map[string]grafana.GrafanaClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -73,7 +72,7 @@ func TestGetResources(t *testing.T) {
 		expectedError := errors.New("client error")
 		client.On("GetFolders").Return(nil, expectedError).Once()
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -84,9 +83,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.GrafanaClient)
 		p := grafana.NewProvider("", crypto)
-		p.Clients = map[string]grafana.GrafanaClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -102,7 +99,7 @@ func TestGetResources(t *testing.T) {
 		client.On("GetFolders").Return(expectedFolders, nil).Once()
 		client.On("GetDashboards", 1).Return(nil, expectedError).Times(2)
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -113,9 +110,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.GrafanaClient)
 		p := grafana.NewProvider("", crypto)
-		p.Clients = map[string]grafana.GrafanaClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -145,7 +140,7 @@ func TestGetResources(t *testing.T) {
 			},
 		}
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Equal(t, expectedResources, actualResources)
 		assert.Nil(t, actualError)
@@ -222,7 +217,7 @@ func TestGrantAccess(t *testing.T) {
 				Resources: tc.resourceConfigs,
 			}
 
-			actualError := p.GrantAccess(providerConfig, tc.appeal)
+			actualError := p.GrantAccess(context.Background(), providerConfig, tc.appeal)
 			assert.EqualError(t, actualError, tc.expectedError.Error())
 		}
 	})
@@ -256,7 +251,7 @@ func TestGrantAccess(t *testing.T) {
 			Role: "test-role",
 		}
 
-		actualError := p.GrantAccess(pc, a)
+		actualError := p.GrantAccess(context.Background(), pc, a)
 		assert.Error(t, actualError)
 
 	})
@@ -297,7 +292,7 @@ func TestGrantAccess(t *testing.T) {
 			Role: "test-role",
 		}
 
-		actualError := p.GrantAccess(pc, a)
+		actualError := p.GrantAccess(context.Background(), pc, a)
 
 		assert.EqualError(t, actualError, expectedError.Error())
 
@@ -340,7 +335,7 @@ func TestGrantAccess(t *testing.T) {
 			Role: "test-role",
 		}
 
-		actualError := p.GrantAccess(pc, a)
+		actualError := p.GrantAccess(context.Background(), pc, a)
 
 		assert.EqualError(t, actualError, expectedError.Error())
 	})
@@ -352,9 +347,7 @@ func TestGrantAccess(t *testing.T) {
 			crypto := new(mocks.Crypto)
 			client := new(mocks.GrafanaClient)
 			p := grafana.NewProvider("", crypto)
-			p.Clients = map[string]grafana.GrafanaClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantDashboardAccess", mock.Anything, mock.Anything, mock.Anything).Return(expectedError).Once()
 
 			pc := &domain.ProviderConfig{
@@ -389,7 +382,7 @@ func TestGrantAccess(t *testing.T) {
 				Role: "test-role",
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.EqualError(t, actualError, expectedError.Error())
 		})
@@ -405,9 +398,7 @@ func TestGrantAccess(t *testing.T) {
 			expectedUser := "test@email.com"
 			expectedRole := grafana.DashboardRoleViewer
 			p := grafana.NewProvider("", crypto)
-			p.Clients = map[string]grafana.GrafanaClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantDashboardAccess", expectedDatabase, expectedUser, expectedRole).Return(nil).Once()
 
 			pc := &domain.ProviderConfig{
@@ -445,7 +436,7 @@ func TestGrantAccess(t *testing.T) {
 				ID:         999,
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.Nil(t, actualError)
 		})
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+)
+
+const defaultImportBatchSize = 500
+
+// ImportResources loads the full resource list of a registered provider into the resource store in one
+// operation, as the initial load of a newly onboarded provider. The provider is checked to be reachable with
+// its config before anything is stored. The resources are upserted in batches of batchSize, 500 by default,
+// and page by page for the providers supporting pagination. Like the fetched resources, the imported ones belong to
+// the organization of their provider
+func (s *Service) ImportResources(ctx context.Context, providerType, providerURN string, batchSize int) (*domain.ResourceImportReport, error) {
+	provider := s.getProvider(providerType)
+	if provider == nil {
+		return nil, ErrInvalidProviderType
+	}
+	p, err := s.getProviderConfig(ctx, providerType, providerURN)
+	if err != nil {
+		return nil, err
+	}
+
+	if checker, ok := provider.(domain.ConnectionChecker); ok {
+		if err := checker.CheckConnection(ctx, p.Config); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrProviderUnreachable, err)
+		}
+	}
+
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+	report := &domain.ResourceImportReport{
+		ProviderType:  providerType,
+		ProviderURN:   providerURN,
+		ResourceTypes: map[string]int{},
+	}
+	upsert := func(resources []*domain.Resource) error {
+		for start := 0; start < len(resources); start += batchSize {
+			end := start + batchSize
+			if end > len(resources) {
+				end = len(resources)
+			}
+			batch := resources[start:end]
+			setResourcesOrganization(batch, p.OrganizationID)
+			if err := s.resourceService.BulkUpsert(ctx, batch); err != nil {
+				return fmt.Errorf("importing resources %d to %d: %w", report.Imported+1, report.Imported+len(batch), err)
+			}
+
+			report.Batches++
+			report.Imported += len(batch)
+			for _, r := range batch {
+				report.ResourceTypes[r.Type]++
+			}
+		}
+		return nil
+	}
+
+	if pager, ok := provider.(domain.ResourcePager); ok {
+		if err := pager.GetResourcesPaged(ctx, p.Config, upsert); err != nil {
+			return report, err
+		}
+		return report, nil
+	}
+
+	resources, err := provider.GetResources(ctx, p.Config)
+	if err != nil {
+		return nil, err
+	}
+	if err := upsert(resources); err != nil {
+		return report, err
+	}
+	return report, nil
+}
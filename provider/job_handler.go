@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"context"
+
 	"github.com/odpf/guardian/domain"
 )
 
@@ -16,5 +18,5 @@ func NewJobHandler(ps domain.ProviderService) *JobHandler {
 
 // GetResources fetches all resources for all registered providers
 func (h *JobHandler) GetResources() error {
-	return h.providerService.FetchResources()
+	return h.providerService.FetchResources(context.Background())
 }
@@ -1,6 +1,7 @@
 package metabase_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestGetResources(t *testing.T) {
 			Credentials: "invalid-creds",
 		}
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.Error(t, actualError)
@@ -51,7 +52,7 @@ func TestGetResources(t *testing.T) {
 			},
 		}
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -62,9 +63,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.MetabaseClient)
 		p := metabase.NewProvider("", crypto)
-		p.Clients = map[string]metabase.MetabaseClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -73,7 +72,7 @@ func TestGetResources(t *testing.T) {
 		expectedError := errors.New("client error")
 		client.On("GetDatabases").Return(nil, expectedError).Once()
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -84,9 +83,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.MetabaseClient)
 		p := metabase.NewProvider("", crypto)
-		p.Clients = map[string]metabase.MetabaseClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -96,7 +93,7 @@ func TestGetResources(t *testing.T) {
 		expectedError := errors.New("client error")
 		client.On("GetCollections").Return(nil, expectedError).Once()
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -107,9 +104,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.MetabaseClient)
 		p := metabase.NewProvider("", crypto)
-		p.Clients = map[string]metabase.MetabaseClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -153,7 +148,7 @@ func TestGetResources(t *testing.T) {
 			},
 		}
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Equal(t, expectedResources, actualResources)
 		assert.Nil(t, actualError)
@@ -230,7 +225,7 @@ func TestGrantAccess(t *testing.T) {
 				Resources: tc.resourceConfigs,
 			}
 
-			actualError := p.GrantAccess(providerConfig, tc.appeal)
+			actualError := p.GrantAccess(context.Background(), providerConfig, tc.appeal)
 			assert.EqualError(t, actualError, tc.expectedError.Error())
 		}
 	})
@@ -264,7 +259,7 @@ func TestGrantAccess(t *testing.T) {
 			Role: "test-role",
 		}
 
-		actualError := p.GrantAccess(pc, a)
+		actualError := p.GrantAccess(context.Background(), pc, a)
 		assert.Error(t, actualError)
 
 	})
@@ -304,7 +299,7 @@ func TestGrantAccess(t *testing.T) {
 			Role: "test-role",
 		}
 
-		actualError := p.GrantAccess(pc, a)
+		actualError := p.GrantAccess(context.Background(), pc, a)
 
 		assert.EqualError(t, actualError, expectedError.Error())
 
@@ -346,7 +341,7 @@ func TestGrantAccess(t *testing.T) {
 			Role: "test-role",
 		}
 
-		actualError := p.GrantAccess(pc, a)
+		actualError := p.GrantAccess(context.Background(), pc, a)
 
 		assert.EqualError(t, actualError, expectedError.Error())
 	})
@@ -358,9 +353,7 @@ func TestGrantAccess(t *testing.T) {
 			crypto := new(mocks.Crypto)
 			client := new(mocks.MetabaseClient)
 			p := metabase.NewProvider("", crypto)
-			p.Clients = map[string]metabase.MetabaseClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantDatabaseAccess", mock.Anything, mock.Anything, mock.Anything).Return(expectedError).Once()
 
 			pc := &domain.ProviderConfig{
@@ -395,7 +388,7 @@ func TestGrantAccess(t *testing.T) {
 				Role: "test-role",
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.EqualError(t, actualError, expectedError.Error())
 		})
@@ -411,9 +404,7 @@ func TestGrantAccess(t *testing.T) {
 			expectedUser := "test@email.com"
 			expectedRole := metabase.DatabaseRoleViewer
 			p := metabase.NewProvider("", crypto)
-			p.Clients = map[string]metabase.MetabaseClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantDatabaseAccess", expectedDatabase, expectedUser, expectedRole).Return(nil).Once()
 
 			pc := &domain.ProviderConfig{
@@ -451,7 +442,7 @@ func TestGrantAccess(t *testing.T) {
 				ID:         999,
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.Nil(t, actualError)
 		})
@@ -464,9 +455,7 @@ func TestGrantAccess(t *testing.T) {
 			crypto := new(mocks.Crypto)
 			client := new(mocks.MetabaseClient)
 			p := metabase.NewProvider("", crypto)
-			p.Clients = map[string]metabase.MetabaseClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantCollectionAccess", mock.Anything, mock.Anything, mock.Anything).Return(expectedError).Once()
 
 			pc := &domain.ProviderConfig{
@@ -501,7 +490,7 @@ func TestGrantAccess(t *testing.T) {
 				Role: "test-role",
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.EqualError(t, actualError, expectedError.Error())
 		})
@@ -518,9 +507,7 @@ func TestGrantAccess(t *testing.T) {
 			expectedRole := "viewer"
 			p := metabase.NewProvider("", crypto)
 
-			p.Clients = map[string]metabase.MetabaseClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantCollectionAccess", expectedCollection, expectedUser, expectedRole).Return(nil).Once()
 
 			pc := &domain.ProviderConfig{
@@ -558,7 +545,7 @@ func TestGrantAccess(t *testing.T) {
 				ID:         999,
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.Nil(t, actualError)
 		})
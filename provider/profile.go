@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// ProfileConfig selects the profile of the provider configs taking effect in the environment
+type ProfileConfig struct {
+	// Active is the profile taking effect, e.g. prod. The provider configs are used without their profiles if
+	// it's empty, or if they don't define any profile
+	Active string `mapstructure:"active"`
+	// Required are the profiles every provider config defining profiles has to define, along with the active one
+	Required []string `mapstructure:"required"`
+}
+
+func (c ProfileConfig) getRequired() []string {
+	required := append([]string{}, c.Required...)
+	if c.Active != "" {
+		required = append(required, c.Active)
+	}
+	return required
+}
+
+// validateProfiles makes sure the provider config defining profiles defines the required and the active ones
+func validateProfiles(pc *domain.ProviderConfig, c ProfileConfig) error {
+	if pc == nil || len(pc.Profiles) == 0 {
+		return nil
+	}
+
+	for _, name := range c.getRequired() {
+		if pc.Profiles[name] == nil {
+			return fmt.Errorf("%w: %q", ErrProviderProfileNotFound, name)
+		}
+	}
+	return nil
+}
+
+// ValidateProfiles checks the stored provider configs against the profile config, e.g. on startup once the
+// active profile changes
+func (s *Service) ValidateProfiles(ctx context.Context) error {
+	providers, err := s.providerRepository.Find(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range providers {
+		if err := validateProfiles(p.Config, s.Profile); err != nil {
+			return fmt.Errorf("provider %q of type %q: %w", p.URN, p.Type, err)
+		}
+	}
+	return nil
+}
+
+// applyProfile replaces the provider config with the one overridden by the active profile
+func (s *Service) applyProfile(p *domain.Provider) {
+	if p.Config == nil {
+		return
+	}
+	p.Config, _ = p.Config.WithProfile(s.Profile.Active)
+}
+
+// createProfileConfigs validates the config of every profile and encrypts the credentials of the profiles having
+// their own. The profiles are created before the provider config itself, as creating a config modifies it, e.g.
+// encrypting the credentials
+func (s *Service) createProfileConfigs(ctx context.Context, provider domain.ProviderInterface, p *domain.Provider) error {
+	if p.Config == nil {
+		return nil
+	}
+
+	for name, profile := range p.Config.Profiles {
+		base, err := copyConfigWithoutProfiles(p.Config)
+		if err != nil {
+			return err
+		}
+		base.Profiles = map[string]*domain.ProviderProfile{name: profile}
+		pc, _ := base.WithProfile(name)
+
+		if err := validateConfig(provider, p.Type, pc, s.RoleMappings); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+		if err := provider.CreateConfig(ctx, pc); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+		if profile.Credentials != nil {
+			profile.Credentials = pc.Credentials
+		}
+	}
+	return nil
+}
+
+func copyConfigWithoutProfiles(pc *domain.ProviderConfig) (*domain.ProviderConfig, error) {
+	withoutProfiles := *pc
+	withoutProfiles.Profiles = nil
+
+	data, err := json.Marshal(withoutProfiles)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.ProviderConfig{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
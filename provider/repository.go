@@ -1,8 +1,10 @@
 package provider
 
 import (
+	"context"
 	"errors"
 
+	"github.com/odpf/guardian/auth"
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/model"
 	"gorm.io/gorm"
@@ -18,14 +20,28 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db}
 }
 
-// Create new record to database
-func (r *Repository) Create(p *domain.Provider) error {
+// scoped restricts the queries to the providers of the organization carried by ctx, if any. The providers without
+// an organization are only visible to the callers without an organization
+func (r *Repository) scoped(ctx context.Context) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+		db = db.Where(`"organization_id" = ?`, organizationID)
+	}
+	return db
+}
+
+// Create new record to database, in the organization carried by ctx if any
+func (r *Repository) Create(ctx context.Context, p *domain.Provider) error {
+	if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+		p.OrganizationID = organizationID
+	}
+
 	m := new(model.Provider)
 	if err := m.FromDomain(p); err != nil {
 		return err
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if result := tx.Create(m); result.Error != nil {
 			return result.Error
 		}
@@ -42,11 +58,11 @@ func (r *Repository) Create(p *domain.Provider) error {
 }
 
 // Find records based on filters
-func (r *Repository) Find() ([]*domain.Provider, error) {
+func (r *Repository) Find(ctx context.Context) ([]*domain.Provider, error) {
 	providers := []*domain.Provider{}
 
 	var models []*model.Provider
-	if err := r.db.Find(&models).Error; err != nil {
+	if err := r.scoped(ctx).Find(&models).Error; err != nil {
 		return nil, err
 	}
 	for _, m := range models {
@@ -62,7 +78,7 @@ func (r *Repository) Find() ([]*domain.Provider, error) {
 }
 
 // GetByID record by ID
-func (r *Repository) GetByID(id uint) (*domain.Provider, error) {
+func (r *Repository) GetByID(ctx context.Context, id uint) (*domain.Provider, error) {
 	if id == 0 {
 		return nil, ErrEmptyIDParam
 	}
@@ -70,7 +86,7 @@ func (r *Repository) GetByID(id uint) (*domain.Provider, error) {
 	m := &model.Provider{
 		ID: id,
 	}
-	if err := r.db.Take(m).Error; err != nil {
+	if err := r.scoped(ctx).Take(m).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -86,7 +102,7 @@ func (r *Repository) GetByID(id uint) (*domain.Provider, error) {
 }
 
 // GetOne returns provider by type and urn
-func (r *Repository) GetOne(pType, urn string) (*domain.Provider, error) {
+func (r *Repository) GetOne(ctx context.Context, pType, urn string) (*domain.Provider, error) {
 	if pType == "" {
 		return nil, ErrEmptyProviderType
 	}
@@ -98,7 +114,7 @@ func (r *Repository) GetOne(pType, urn string) (*domain.Provider, error) {
 		Type: pType,
 		URN:  urn,
 	}
-	if err := r.db.Take(m).Error; err != nil {
+	if err := r.scoped(ctx).Take(m).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -113,8 +129,8 @@ func (r *Repository) GetOne(pType, urn string) (*domain.Provider, error) {
 	return p, err
 }
 
-// Update record by ID
-func (r *Repository) Update(p *domain.Provider) error {
+// Update record by ID, the provider of another organization than the one carried by ctx isn't found
+func (r *Repository) Update(ctx context.Context, p *domain.Provider) error {
 	if p.ID == 0 {
 		return ErrEmptyIDParam
 	}
@@ -124,9 +140,17 @@ func (r *Repository) Update(p *domain.Provider) error {
 		return err
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Model(m).Updates(*m).Error; err != nil {
-			return err
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		db := tx.Model(m)
+		if organizationID, ok := auth.OrganizationFromContext(ctx); ok {
+			db = db.Where(`"organization_id" = ?`, organizationID)
+		}
+		result := db.Updates(*m)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRecordNotFound
 		}
 
 		newRecord, err := m.ToDomain()
@@ -141,6 +165,6 @@ func (r *Repository) Update(p *domain.Provider) error {
 }
 
 // Delete record by ID
-func (r *Repository) Delete(id uint) error {
+func (r *Repository) Delete(ctx context.Context, id uint) error {
 	return nil
 }
@@ -1,6 +1,8 @@
 package provider_test
 
 import (
+	"context"
+
 	"database/sql"
 	"errors"
 	"regexp"
@@ -8,6 +10,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/odpf/guardian/auth"
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/mocks"
 	"github.com/odpf/guardian/provider"
@@ -45,7 +48,7 @@ func (s *RepositoryTestSuite) TearDownTest() {
 }
 
 func (s *RepositoryTestSuite) TestCreate() {
-	expectedQuery := regexp.QuoteMeta(`INSERT INTO "providers" ("type","urn","config","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)
+	expectedQuery := regexp.QuoteMeta(`INSERT INTO "providers" ("type","urn","config","organization_id","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)
 
 	s.Run("should update model's ID with the returned ID", func() {
 		config := &domain.ProviderConfig{}
@@ -60,13 +63,30 @@ func (s *RepositoryTestSuite) TestCreate() {
 		s.dbmock.ExpectQuery(expectedQuery).WillReturnRows(expectedRows)
 		s.dbmock.ExpectCommit()
 
-		err := s.repository.Create(provider)
+		err := s.repository.Create(context.Background(), provider)
 
 		actualID := provider.ID
 
 		s.Nil(err)
 		s.Equal(expectedID, actualID)
 	})
+
+	s.Run("should create the provider in the organization of the context", func() {
+		provider := &domain.Provider{
+			Config: &domain.ProviderConfig{},
+		}
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectQuery(expectedQuery).
+			WithArgs("", "", sqlmock.AnyArg(), "org-a", sqlmock.AnyArg(), sqlmock.AnyArg(), nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		s.dbmock.ExpectCommit()
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		err := s.repository.Create(ctx, provider)
+
+		s.Nil(err)
+		s.Equal("org-a", provider.OrganizationID)
+	})
 }
 
 func (s *RepositoryTestSuite) TestFind() {
@@ -78,7 +98,7 @@ func (s *RepositoryTestSuite) TestFind() {
 		s.dbmock.ExpectQuery(expectedQuery).
 			WillReturnError(expectedError)
 
-		actualRecords, actualError := s.repository.Find()
+		actualRecords, actualError := s.repository.Find(context.Background())
 
 		s.EqualError(actualError, expectedError.Error())
 		s.Nil(actualRecords)
@@ -108,18 +128,32 @@ func (s *RepositoryTestSuite) TestFind() {
 
 		s.dbmock.ExpectQuery(expectedQuery).WillReturnRows(expectedRows)
 
-		actualRecords, actualError := s.repository.Find()
+		actualRecords, actualError := s.repository.Find(context.Background())
 
 		s.Equal(expectedRecords, actualRecords)
 		s.Nil(actualError)
 	})
+
+	s.Run("should only return the providers of the organization", func() {
+		expectedOrganizationQuery := regexp.QuoteMeta(`SELECT * FROM "providers" WHERE ("organization_id" = $1) AND "providers"."deleted_at" IS NULL`)
+		s.dbmock.ExpectQuery(expectedOrganizationQuery).
+			WithArgs("org-a").
+			WillReturnRows(sqlmock.NewRows(s.rows))
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		actualRecords, actualError := s.repository.Find(ctx)
+
+		s.Nil(actualError)
+		s.Empty(actualRecords)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
 }
 
 func (s *RepositoryTestSuite) TestGetByID() {
 	s.Run("should return error if id is empty", func() {
 		expectedError := provider.ErrEmptyIDParam
 
-		actualResult, actualError := s.repository.GetByID(0)
+		actualResult, actualError := s.repository.GetByID(context.Background(), 0)
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -130,7 +164,7 @@ func (s *RepositoryTestSuite) TestGetByID() {
 		s.dbmock.ExpectQuery(".*").
 			WillReturnError(expectedError)
 
-		actualResult, actualError := s.repository.GetByID(1)
+		actualResult, actualError := s.repository.GetByID(context.Background(), 1)
 
 		s.Nil(actualResult)
 		s.Nil(actualError)
@@ -141,7 +175,7 @@ func (s *RepositoryTestSuite) TestGetByID() {
 		s.dbmock.ExpectQuery(".*").
 			WillReturnError(expectedError)
 
-		actualResult, actualError := s.repository.GetByID(1)
+		actualResult, actualError := s.repository.GetByID(context.Background(), 1)
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -163,7 +197,7 @@ func (s *RepositoryTestSuite) TestGetByID() {
 		s.dbmock.ExpectQuery(expectedQuery).
 			WillReturnRows(expectedRows)
 
-		_, actualError := s.repository.GetByID(expectedID)
+		_, actualError := s.repository.GetByID(context.Background(), expectedID)
 
 		s.Nil(actualError)
 		s.dbmock.ExpectationsWereMet()
@@ -174,7 +208,7 @@ func (s *RepositoryTestSuite) TestUpdate() {
 	s.Run("should return error if id is empty", func() {
 		expectedError := provider.ErrEmptyIDParam
 
-		actualError := s.repository.Update(&domain.Provider{})
+		actualError := s.repository.Update(context.Background(), &domain.Provider{})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -186,7 +220,7 @@ func (s *RepositoryTestSuite) TestUpdate() {
 			WillReturnError(expectedError)
 		s.dbmock.ExpectRollback()
 
-		actualError := s.repository.Update(&domain.Provider{ID: 1, Type: "test-type", URN: "test-urn"})
+		actualError := s.repository.Update(context.Background(), &domain.Provider{ID: 1, Type: "test-type", URN: "test-urn"})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -207,13 +241,27 @@ func (s *RepositoryTestSuite) TestUpdate() {
 			WillReturnResult(sqlmock.NewResult(int64(expectedID), 1))
 		s.dbmock.ExpectCommit()
 
-		err := s.repository.Update(provider)
+		err := s.repository.Update(context.Background(), provider)
 
 		actualID := provider.ID
 
 		s.Nil(err)
 		s.Equal(expectedID, actualID)
 	})
+
+	s.Run("should return not found if the provider belongs to another organization", func() {
+		expectedOrganizationQuery := regexp.QuoteMeta(`UPDATE "providers" SET "id"=$1,"type"=$2,"urn"=$3,"config"=$4,"updated_at"=$5 WHERE ("organization_id" = $6) AND "type" = $7 AND "urn" = $8`)
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectExec(expectedOrganizationQuery).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		s.dbmock.ExpectRollback()
+
+		ctx := auth.NewOrganizationContext(context.Background(), "org-a")
+		err := s.repository.Update(ctx, &domain.Provider{ID: 1, Type: "test-type", URN: "test-urn", Config: &domain.ProviderConfig{}})
+
+		s.ErrorIs(err, provider.ErrRecordNotFound)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
 }
 
 func TestRepository(t *testing.T) {
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"google.golang.org/api/googleapi"
+)
+
+// RetryConfig configures the retry of the provider access operations
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of calls including the first one. Retry is disabled if it's less than 2
+	MaxAttempts int `mapstructure:"max_attempts" default:"3"`
+	// InitialInterval is the wait time before the first retry, doubled on every next retry
+	InitialInterval time.Duration `mapstructure:"initial_interval" default:"500ms"`
+	// MaxInterval caps the wait time between retries
+	MaxInterval time.Duration `mapstructure:"max_interval" default:"5s"`
+	// MaxElapsedTime caps the total time spent on an operation including the retries
+	MaxElapsedTime time.Duration `mapstructure:"max_elapsed_time" default:"30s"`
+}
+
+// RetryableError can be implemented by the provider errors to explicitly mark whether the
+// failed operation is safe to be retried
+type RetryableError interface {
+	Retryable() bool
+}
+
+var googleAPIRetryableReasons = map[string]bool{
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+	"quotaExceeded":         true,
+	"backendError":          true,
+}
+
+// IsRetryable returns true for the transient errors: server errors, rate limits, and network errors.
+// Unrecognized errors are considered permanent
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var retryableErr RetryableError
+	if errors.As(err, &retryableErr) {
+		return retryableErr.Retryable()
+	}
+
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		if googleErr.Code >= http.StatusInternalServerError || googleErr.Code == http.StatusTooManyRequests {
+			return true
+		}
+		for _, e := range googleErr.Errors {
+			if googleAPIRetryableReasons[e.Reason] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+type retryProvider struct {
+	decoratedProvider
+	config RetryConfig
+}
+
+// WithRetry decorates the provider to retry the failed access operations on transient errors
+// with exponential backoff and jitter
+func WithRetry(p domain.ProviderInterface, c RetryConfig) domain.ProviderInterface {
+	if c.MaxAttempts < 2 {
+		return p
+	}
+	return &retryProvider{decoratedProvider{p}, c}
+}
+
+func (p *retryProvider) GrantAccess(ctx context.Context, pc *domain.ProviderConfig, a *domain.Appeal) error {
+	return p.retry(ctx, func() error {
+		return p.ProviderInterface.GrantAccess(ctx, pc, a)
+	})
+}
+
+func (p *retryProvider) RevokeAccess(ctx context.Context, pc *domain.ProviderConfig, a *domain.Appeal) error {
+	return p.retry(ctx, func() error {
+		return p.ProviderInterface.RevokeAccess(ctx, pc, a)
+	})
+}
+
+func (p *retryProvider) VerifyAccess(ctx context.Context, pc *domain.ProviderConfig, a *domain.Appeal) (bool, error) {
+	var granted bool
+	err := p.retry(ctx, func() error {
+		var err error
+		granted, err = p.decoratedProvider.VerifyAccess(ctx, pc, a)
+		return err
+	})
+	return granted, err
+}
+
+func (p *retryProvider) retry(ctx context.Context, fn func() error) error {
+	start := time.Now()
+	interval := p.config.InitialInterval
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || !IsRetryable(err) || attempt >= p.config.MaxAttempts {
+			return err
+		}
+
+		wait := interval/2 + time.Duration(rand.Int63n(int64(interval/2)+1))
+		if p.config.MaxElapsedTime > 0 && time.Since(start)+wait > p.config.MaxElapsedTime {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		interval *= 2
+		if p.config.MaxInterval > 0 && interval > p.config.MaxInterval {
+			interval = p.config.MaxInterval
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/mocks"
+	"github.com/odpf/guardian/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/api/googleapi"
+)
+
+type retryableError bool
+
+func (e retryableError) Error() string   { return "retryable error" }
+func (e retryableError) Retryable() bool { return bool(e) }
+
+func TestIsRetryable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil error", nil, false},
+		{"unrecognized error", errors.New("any error"), false},
+		{"canceled context", context.Canceled, false},
+		{"explicitly retryable error", retryableError(true), true},
+		{"explicitly non-retryable error", retryableError(false), false},
+		{"google api server error", &googleapi.Error{Code: http.StatusServiceUnavailable}, true},
+		{"google api too many requests", &googleapi.Error{Code: http.StatusTooManyRequests}, true},
+		{"google api quota exceeded", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}, true},
+		{"google api permission denied", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "accessDenied"}}}, false},
+		{"google api not found", &googleapi.Error{Code: http.StatusNotFound}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, provider.IsRetryable(tc.err))
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	config := provider.RetryConfig{
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     2 * time.Millisecond,
+		MaxElapsedTime:  time.Second,
+	}
+	pc := &domain.ProviderConfig{}
+	a := &domain.Appeal{}
+
+	t.Run("should return the provider as is if retry is disabled", func(t *testing.T) {
+		p := new(mocks.ProviderInterface)
+
+		assert.Equal(t, p, provider.WithRetry(p, provider.RetryConfig{MaxAttempts: 1}))
+	})
+
+	t.Run("should retry on transient errors until succeeded", func(t *testing.T) {
+		p := new(mocks.ProviderInterface)
+		p.On("GrantAccess", mock.Anything, pc, a).Return(retryableError(true)).Twice()
+		p.On("GrantAccess", mock.Anything, pc, a).Return(nil).Once()
+
+		actualError := provider.WithRetry(p, config).GrantAccess(context.Background(), pc, a)
+
+		assert.Nil(t, actualError)
+		p.AssertExpectations(t)
+	})
+
+	t.Run("should return the last error once reaching the max attempts", func(t *testing.T) {
+		p := new(mocks.ProviderInterface)
+		expectedError := retryableError(true)
+		p.On("RevokeAccess", mock.Anything, pc, a).Return(expectedError).Times(3)
+
+		actualError := provider.WithRetry(p, config).RevokeAccess(context.Background(), pc, a)
+
+		assert.Equal(t, expectedError, actualError)
+		p.AssertExpectations(t)
+	})
+
+	t.Run("should not retry on permanent errors", func(t *testing.T) {
+		p := new(mocks.ProviderInterface)
+		expectedError := errors.New("permission denied")
+		p.On("RevokeAccess", mock.Anything, pc, a).Return(expectedError).Once()
+
+		actualError := provider.WithRetry(p, config).RevokeAccess(context.Background(), pc, a)
+
+		assert.Equal(t, expectedError, actualError)
+		p.AssertExpectations(t)
+	})
+
+	t.Run("should retry the access verification of the verifier providers", func(t *testing.T) {
+		p := &verifiableProvider{new(mocks.ProviderInterface), new(mocks.AccessVerifier)}
+		p.AccessVerifier.On("VerifyAccess", mock.Anything, pc, a).Return(false, retryableError(true)).Once()
+		p.AccessVerifier.On("VerifyAccess", mock.Anything, pc, a).Return(true, nil).Once()
+
+		granted, actualError := provider.WithRetry(p, config).(domain.AccessVerifier).VerifyAccess(context.Background(), pc, a)
+
+		assert.Nil(t, actualError)
+		assert.True(t, granted)
+		p.AccessVerifier.AssertExpectations(t)
+	})
+
+	t.Run("should list the resources of the non-paging providers as a single page", func(t *testing.T) {
+		p := new(mocks.ProviderInterface)
+		resources := []*domain.Resource{{URN: "resource_1"}, {URN: "resource_2"}}
+		p.On("GetResources", mock.Anything, pc).Return(resources, nil).Once()
+		var pages [][]*domain.Resource
+
+		actualError := provider.WithRetry(p, config).(domain.ResourcePager).GetResourcesPaged(context.Background(), pc, func(page []*domain.Resource) error {
+			pages = append(pages, page)
+			return nil
+		})
+
+		assert.Nil(t, actualError)
+		assert.Equal(t, [][]*domain.Resource{resources}, pages)
+	})
+}
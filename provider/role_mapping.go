@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/odpf/guardian/domain"
+)
+
+// RoleMappings maps the logical roles to the provider-specific permissions of each provider type, e.g.
+// read-only maps to roles/bigquery.dataViewer on google_bigquery and to view on metabase. A role of the
+// provider config without permissions is a logical role, its permissions are resolved from the mapping of
+// the provider type. This lets the policies and the appeals of different providers share the role names
+type RoleMappings map[string]map[string][]interface{}
+
+func (m RoleMappings) getPermissions(role, providerType string) ([]interface{}, bool) {
+	permissions, ok := m[role][providerType]
+	return permissions, ok && len(permissions) > 0
+}
+
+// resolve returns a copy of the provider config with the permissions of the logical roles resolved. The
+// config is returned as is if none of its roles is a logical role
+func (m RoleMappings) resolve(providerType string, pc *domain.ProviderConfig) *domain.ProviderConfig {
+	if pc == nil || len(m) == 0 {
+		return pc
+	}
+
+	resolved := *pc
+	resolved.Resources = make([]*domain.ResourceConfig, len(pc.Resources))
+	changed := false
+	for i, rc := range pc.Resources {
+		resolved.Resources[i] = rc
+		for j, role := range rc.Roles {
+			if len(role.Permissions) > 0 {
+				continue
+			}
+			permissions, ok := m.getPermissions(role.ID, providerType)
+			if !ok {
+				continue
+			}
+			if resolved.Resources[i] == rc {
+				resourceConfig := *rc
+				resourceConfig.Roles = append([]*domain.RoleConfig{}, rc.Roles...)
+				resolved.Resources[i] = &resourceConfig
+			}
+			roleConfig := *role
+			roleConfig.Permissions = permissions
+			resolved.Resources[i].Roles[j] = &roleConfig
+			changed = true
+		}
+	}
+	if !changed {
+		return pc
+	}
+	return &resolved
+}
+
+// validateRoleMappings makes sure every role without permissions has a mapping for the provider type
+func validateRoleMappings(providerType string, pc *domain.ProviderConfig, mappings RoleMappings) error {
+	if pc == nil {
+		return nil
+	}
+
+	for _, rc := range pc.Resources {
+		for _, role := range rc.Roles {
+			if len(role.Permissions) > 0 {
+				continue
+			}
+			if _, ok := mappings.getPermissions(role.ID, providerType); !ok {
+				return fmt.Errorf("%w: %q of resource type %q on provider type %q", ErrRoleMappingNotFound, role.ID, rc.Type, providerType)
+			}
+		}
+	}
+	return nil
+}
@@ -1,16 +1,33 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/imdario/mergo"
 	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/tracing"
+	"github.com/odpf/guardian/utils"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const tracerName = "github.com/odpf/guardian/provider"
+
 // Service handling the business logics
 type Service struct {
 	providerRepository domain.ProviderRepository
 	resourceService    domain.ResourceService
 
 	providers map[string]domain.ProviderInterface
+
+	// Tracer traces the access operations. Default: tracer of the global tracer provider
+	Tracer trace.Tracer
+	// RoleMappings resolves the permissions of the logical roles of the provider configs
+	RoleMappings RoleMappings
+	// Profile selects the profile of the provider configs taking effect
+	Profile ProfileConfig
 }
 
 // NewService returns service struct
@@ -24,31 +41,43 @@ func NewService(pr domain.ProviderRepository, rs domain.ResourceService, provide
 		providerRepository: pr,
 		resourceService:    rs,
 		providers:          mapProviders,
+		Tracer:             tracing.Tracer(tracerName),
 	}
 }
 
 // Create record
-func (s *Service) Create(p *domain.Provider) error {
+func (s *Service) Create(ctx context.Context, p *domain.Provider) error {
 	provider := s.getProvider(p.Type)
 	if provider == nil {
 		return ErrInvalidProviderType
 	}
 
-	if err := provider.CreateConfig(p.Config); err != nil {
+	if err := validateConfig(provider, p.Type, p.Config, s.RoleMappings); err != nil {
+		return err
+	}
+	if err := validateProfiles(p.Config, s.Profile); err != nil {
+		return err
+	}
+	if err := s.createProfileConfigs(ctx, provider, p); err != nil {
 		return err
 	}
 
-	return s.providerRepository.Create(p)
+	if err := provider.CreateConfig(ctx, p.Config); err != nil {
+		return err
+	}
+
+	return s.providerRepository.Create(ctx, p)
 }
 
 // Find records
-func (s *Service) Find() ([]*domain.Provider, error) {
-	providers, err := s.providerRepository.Find()
+func (s *Service) Find(ctx context.Context) ([]*domain.Provider, error) {
+	providers, err := s.providerRepository.Find(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, p := range providers {
+		s.applyProfile(p)
 		p.Config.Credentials = nil
 	}
 
@@ -56,8 +85,8 @@ func (s *Service) Find() ([]*domain.Provider, error) {
 }
 
 // Update updates the non-zero value(s) only
-func (s *Service) Update(p *domain.Provider) error {
-	currentProvider, err := s.providerRepository.GetByID(p.ID)
+func (s *Service) Update(ctx context.Context, p *domain.Provider) error {
+	currentProvider, err := s.providerRepository.GetByID(ctx, p.ID)
 	if err != nil {
 		return err
 	}
@@ -73,39 +102,80 @@ func (s *Service) Update(p *domain.Provider) error {
 	if provider == nil {
 		return ErrInvalidProviderType
 	}
-	if err := provider.CreateConfig(p.Config); err != nil {
+	if err := validateConfig(provider, p.Type, p.Config, s.RoleMappings); err != nil {
+		return err
+	}
+	if err := validateProfiles(p.Config, s.Profile); err != nil {
+		return err
+	}
+	if err := s.createProfileConfigs(ctx, provider, p); err != nil {
+		return err
+	}
+	if err := provider.CreateConfig(ctx, p.Config); err != nil {
 		return err
 	}
 
-	return s.providerRepository.Update(p)
+	if err := s.providerRepository.Update(ctx, p); err != nil {
+		return err
+	}
+	// the other instances rebuild their clients once they see the new credentials
+	if evicter, ok := provider.(domain.ClientEvicter); ok {
+		evicter.EvictClients(p.URN)
+	}
+	return nil
 }
 
-// FetchResources fetches all resources for all registered providers
-func (s *Service) FetchResources() error {
-	providers, err := s.providerRepository.Find()
+// FetchResources fetches all resources for all registered providers. Resources of the providers
+// supporting pagination are upserted page by page
+func (s *Service) FetchResources(ctx context.Context) error {
+	providers, err := s.providerRepository.Find(ctx)
 	if err != nil {
 		return err
 	}
 
 	resources := []*domain.Resource{}
 	for _, p := range providers {
+		s.applyProfile(p)
 		provider := s.getProvider(p.Type)
 		if provider == nil {
 			return ErrInvalidProviderType
 		}
 
-		res, err := provider.GetResources(p.Config)
+		// the resources belong to the organization of their provider
+		organizationID := p.OrganizationID
+		if pager, ok := provider.(domain.ResourcePager); ok {
+			upsert := func(page []*domain.Resource) error {
+				setResourcesOrganization(page, organizationID)
+				return s.resourceService.BulkUpsert(ctx, page)
+			}
+			if err := pager.GetResourcesPaged(ctx, p.Config, upsert); err != nil {
+				return err
+			}
+			continue
+		}
+
+		res, err := provider.GetResources(ctx, p.Config)
 		if err != nil {
 			return err
 		}
+		setResourcesOrganization(res, organizationID)
 
 		resources = append(resources, res...)
 	}
 
-	return s.resourceService.BulkUpsert(resources)
+	return s.resourceService.BulkUpsert(ctx, resources)
+}
+
+func setResourcesOrganization(resources []*domain.Resource, organizationID string) {
+	for _, r := range resources {
+		r.OrganizationID = organizationID
+	}
 }
 
-func (s *Service) GrantAccess(a *domain.Appeal) error {
+func (s *Service) GrantAccess(ctx context.Context, a *domain.Appeal) (err error) {
+	ctx, span := s.startAccessSpan(ctx, "provider.GrantAccess", a)
+	defer func() { tracing.End(span, err) }()
+
 	if err := s.validateAppealParam(a); err != nil {
 		return err
 	}
@@ -115,15 +185,21 @@ func (s *Service) GrantAccess(a *domain.Appeal) error {
 		return ErrInvalidProviderType
 	}
 
-	p, err := s.getProviderConfig(a.Resource.ProviderType, a.Resource.ProviderURN)
+	p, err := s.getProviderConfig(ctx, a.Resource.ProviderType, a.Resource.ProviderURN)
 	if err != nil {
 		return err
 	}
 
-	return provider.GrantAccess(p.Config, a)
+	if err := provider.GrantAccess(ctx, p.Config, a); err != nil {
+		return newAccessError(AccessOperationGrant, a, err)
+	}
+	return nil
 }
 
-func (s *Service) RevokeAccess(a *domain.Appeal) error {
+func (s *Service) RevokeAccess(ctx context.Context, a *domain.Appeal) (err error) {
+	ctx, span := s.startAccessSpan(ctx, "provider.RevokeAccess", a)
+	defer func() { tracing.End(span, err) }()
+
 	if err := s.validateAppealParam(a); err != nil {
 		return err
 	}
@@ -133,16 +209,137 @@ func (s *Service) RevokeAccess(a *domain.Appeal) error {
 		return ErrInvalidProviderType
 	}
 
-	p, err := s.getProviderConfig(a.Resource.ProviderType, a.Resource.ProviderURN)
+	p, err := s.getProviderConfig(ctx, a.Resource.ProviderType, a.Resource.ProviderURN)
 	if err != nil {
 		return err
 	}
 
-	return provider.RevokeAccess(p.Config, a)
+	if err := provider.RevokeAccess(ctx, p.Config, a); err != nil {
+		return newAccessError(AccessOperationRevoke, a, err)
+	}
+	return nil
 	// TODO: handle if permission for the given user with the given role is not found
 	// handle the resolution for the appeal status
 }
 
+// VerifyAccess checks whether the appeal's access is present in the provider. Providers that
+// don't support the verification are trusted, so it returns true for them. The verification is
+// polled until the access is confirmed if the provider config sets the access verification timeout
+func (s *Service) VerifyAccess(ctx context.Context, a *domain.Appeal) (bool, error) {
+	if err := s.validateAppealParam(a); err != nil {
+		return false, err
+	}
+
+	provider := s.getProvider(a.Resource.ProviderType)
+	if provider == nil {
+		return false, ErrInvalidProviderType
+	}
+
+	verifier, ok := provider.(domain.AccessVerifier)
+	if !ok {
+		return true, nil
+	}
+
+	p, err := s.getProviderConfig(ctx, a.Resource.ProviderType, a.Resource.ProviderURN)
+	if err != nil {
+		return false, err
+	}
+
+	return pollAccessVerification(ctx, verifier, p.Config, a)
+}
+
+// GetCapabilities returns the operations supported by the provider type
+func (s *Service) GetCapabilities(providerType string) (*domain.ProviderCapabilities, error) {
+	provider := s.getProvider(providerType)
+	if provider == nil {
+		return nil, ErrInvalidProviderType
+	}
+
+	capabilities := provider.Capabilities()
+	return &capabilities, nil
+}
+
+// ParseURN splits the resource URN into its components following the provider's URN format
+func (s *Service) ParseURN(providerType, resourceType, urn string) (*domain.ResourceURN, error) {
+	formatter, err := s.getURNFormatter(providerType)
+	if err != nil {
+		return nil, err
+	}
+	return formatter.ParseURN(resourceType, urn)
+}
+
+// FormatURN builds the resource URN from its components following the provider's URN format
+func (s *Service) FormatURN(providerType string, urn *domain.ResourceURN) (string, error) {
+	formatter, err := s.getURNFormatter(providerType)
+	if err != nil {
+		return "", err
+	}
+	return formatter.FormatURN(urn)
+}
+
+// GetRolePermissions returns the permissions the role implies on the resource type, according to the
+// provider config. The resource type can be one of its aliases
+func (s *Service) GetRolePermissions(ctx context.Context, providerType, providerURN, resourceType, role string) ([]interface{}, error) {
+	p, err := s.getProviderConfig(ctx, providerType, providerURN)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rc := range p.Config.Resources {
+		if rc.Type != resourceType && !utils.ContainsString(rc.Aliases, resourceType) {
+			continue
+		}
+		for _, r := range rc.Roles {
+			if r.ID == role {
+				return r.Permissions, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %q on resource type %q", ErrRoleNotFound, role, resourceType)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrResourceTypeNotFound, resourceType)
+}
+
+// GetTeamQuota returns the team quota configured for the role on the resource, preferring the quota of the
+// specific resource over the one of its resource type. It returns nil if the role has no quota
+func (s *Service) GetTeamQuota(ctx context.Context, providerType, providerURN, resourceType, resourceURN, role string) (*domain.TeamQuotaConfig, error) {
+	p, err := s.getProviderConfig(ctx, providerType, providerURN)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rc := range p.Config.Resources {
+		if rc.Type != resourceType && !utils.ContainsString(rc.Aliases, resourceType) {
+			continue
+		}
+		var quota *domain.TeamQuotaConfig
+		for _, q := range rc.TeamQuotas {
+			if q.Role != role {
+				continue
+			}
+			if q.URN == resourceURN {
+				return q, nil
+			} else if q.URN == "" {
+				quota = q
+			}
+		}
+		return quota, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrResourceTypeNotFound, resourceType)
+}
+
+func (s *Service) getURNFormatter(providerType string) (domain.URNFormatter, error) {
+	provider := s.getProvider(providerType)
+	if provider == nil {
+		return nil, ErrInvalidProviderType
+	}
+
+	formatter, ok := provider.(domain.URNFormatter)
+	if !ok {
+		return nil, ErrURNFormatNotSupported
+	}
+	return formatter, nil
+}
+
 func (s *Service) validateAppealParam(a *domain.Appeal) error {
 	if a == nil {
 		return ErrNilAppeal
@@ -157,13 +354,119 @@ func (s *Service) getProvider(pType string) domain.ProviderInterface {
 	return s.providers[pType]
 }
 
-func (s *Service) getProviderConfig(pType, urn string) (*domain.Provider, error) {
-	p, err := s.providerRepository.GetOne(pType, urn)
+func (s *Service) getProviderConfig(ctx context.Context, pType, urn string) (*domain.Provider, error) {
+	p, err := s.providerRepository.GetOne(ctx, pType, urn)
 	if err != nil {
 		return nil, err
 	}
 	if p == nil {
 		return nil, ErrProviderNotFound
 	}
+	s.applyProfile(p)
+	p.Config = s.RoleMappings.resolve(p.Type, p.Config)
 	return p, nil
 }
+
+// validateConfig checks the provider config against the provider and the service configs
+func validateConfig(provider domain.ProviderInterface, providerType string, pc *domain.ProviderConfig, mappings RoleMappings) error {
+	if err := validateCapabilities(provider, pc); err != nil {
+		return err
+	}
+	if err := validateResourceTypes(pc); err != nil {
+		return err
+	}
+	if err := validateMaxDurations(pc); err != nil {
+		return err
+	}
+	if err := validateAccessVerification(pc); err != nil {
+		return err
+	}
+	if err := validateResourceRequirements(pc); err != nil {
+		return err
+	}
+	return validateRoleMappings(providerType, pc, mappings)
+}
+
+func validateCapabilities(provider domain.ProviderInterface, pc *domain.ProviderConfig) error {
+	if pc == nil || pc.Appeal == nil {
+		return nil
+	}
+
+	capabilities := provider.Capabilities()
+	if pc.Appeal.AllowPermanentAccess && !capabilities.PermanentAccess {
+		return ErrPermanentAccessNotSupported
+	}
+
+	return nil
+}
+
+// validateResourceTypes makes sure every resource type and alias refers to a single resource config
+func validateResourceTypes(pc *domain.ProviderConfig) error {
+	if pc == nil {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for _, rc := range pc.Resources {
+		for _, name := range append([]string{rc.Type}, rc.Aliases...) {
+			if names[name] {
+				return fmt.Errorf("%w: %q", ErrDuplicateResourceType, name)
+			}
+			names[name] = true
+		}
+	}
+	return nil
+}
+
+// validateMaxDurations makes sure the max access durations of the appeal config and the roles are parseable
+func validateMaxDurations(pc *domain.ProviderConfig) error {
+	if pc == nil {
+		return nil
+	}
+
+	if pc.Appeal != nil && pc.Appeal.MaxDuration != "" {
+		if d, err := time.ParseDuration(pc.Appeal.MaxDuration); err != nil || d <= 0 {
+			return fmt.Errorf("%w: %q on the appeal config", ErrInvalidMaxDuration, pc.Appeal.MaxDuration)
+		}
+	}
+	for _, rc := range pc.Resources {
+		for _, role := range rc.Roles {
+			if role.MaxDuration == "" {
+				continue
+			}
+			if d, err := time.ParseDuration(role.MaxDuration); err != nil || d <= 0 {
+				return fmt.Errorf("%w: %q on role %q of resource type %q", ErrInvalidMaxDuration, role.MaxDuration, role.ID, rc.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// validateResourceRequirements makes sure the resource requirements of the roles refer to the resource attributes
+func validateResourceRequirements(pc *domain.ProviderConfig) error {
+	if pc == nil {
+		return nil
+	}
+
+	for _, rc := range pc.Resources {
+		for _, role := range rc.Roles {
+			for _, c := range role.ResourceRequirements {
+				if c == nil || c.Match == nil || !strings.HasPrefix(c.Field, domain.ApproversKeyResource+".") {
+					return fmt.Errorf("%w: on role %q of resource type %q", ErrInvalidResourceRequirement, role.ID, rc.Type)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) startAccessSpan(ctx context.Context, name string, a *domain.Appeal) (context.Context, trace.Span) {
+	ctx, span := s.Tracer.Start(ctx, name)
+	if a != nil {
+		span.SetAttributes(tracing.AppealID(a.ID))
+		if a.Resource != nil {
+			span.SetAttributes(tracing.ProviderTypeKey.String(a.Resource.ProviderType))
+		}
+	}
+	return ctx, span
+}
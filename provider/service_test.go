@@ -1,14 +1,22 @@
 package provider_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/mocks"
 	"github.com/odpf/guardian/provider"
+	"github.com/odpf/guardian/tracing"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/api/googleapi"
 )
 
 const (
@@ -42,7 +50,7 @@ func (s *ServiceTestSuite) TestCreate() {
 	s.Run("should return error if unable to retrieve provider", func() {
 		expectedError := provider.ErrInvalidProviderType
 
-		actualError := s.service.Create(&domain.Provider{
+		actualError := s.service.Create(context.Background(), &domain.Provider{
 			Type: "invalid-provider-type",
 		})
 
@@ -51,28 +59,185 @@ func (s *ServiceTestSuite) TestCreate() {
 
 	s.Run("should return error if got error from the provider config validation", func() {
 		expectedError := errors.New("provider config validation error")
-		s.mockProvider.On("CreateConfig", mock.Anything).Return(expectedError).Once()
+		s.mockProvider.On("CreateConfig", mock.Anything, mock.Anything).Return(expectedError).Once()
 
-		actualError := s.service.Create(p)
+		actualError := s.service.Create(context.Background(), p)
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
+	s.Run("should return error if permanent access is allowed but not supported by the provider", func() {
+		s.mockProvider.On("Capabilities").Return(domain.ProviderCapabilities{}).Once()
+
+		actualError := s.service.Create(context.Background(), &domain.Provider{
+			Type: mockProviderType,
+			Config: &domain.ProviderConfig{
+				Appeal: &domain.AppealConfig{AllowPermanentAccess: true},
+			},
+		})
+
+		s.EqualError(actualError, provider.ErrPermanentAccessNotSupported.Error())
+	})
+
+	s.Run("should return error if a resource type or alias is configured more than once", func() {
+		actualError := s.service.Create(context.Background(), &domain.Provider{
+			Type: mockProviderType,
+			Config: &domain.ProviderConfig{
+				Resources: []*domain.ResourceConfig{
+					{Type: "dataset", Aliases: []string{"schema"}},
+					{Type: "table", Aliases: []string{"schema"}},
+				},
+			},
+		})
+
+		s.True(errors.Is(actualError, provider.ErrDuplicateResourceType))
+	})
+
+	s.Run("should return error if a max duration is invalid", func() {
+		configs := []*domain.ProviderConfig{
+			{
+				Appeal: &domain.AppealConfig{MaxDuration: "30 days"},
+			},
+			{
+				Resources: []*domain.ResourceConfig{{
+					Type:  "dataset",
+					Roles: []*domain.RoleConfig{{ID: "owner", MaxDuration: "-4h"}},
+				}},
+			},
+		}
+		for _, pc := range configs {
+			if pc.Appeal != nil {
+				s.mockProvider.On("Capabilities").Return(domain.ProviderCapabilities{}).Once()
+			}
+
+			actualError := s.service.Create(context.Background(), &domain.Provider{
+				Type:   mockProviderType,
+				Config: pc,
+			})
+
+			s.True(errors.Is(actualError, provider.ErrInvalidMaxDuration))
+		}
+	})
+
+	s.Run("should return error if the access verification is invalid", func() {
+		configs := []*domain.AccessVerificationConfig{
+			{PollInterval: "5 seconds", Timeout: "2m"},
+			{PollInterval: "5s", Timeout: "-2m"},
+			{Timeout: "2m", OnTimeout: "ignore"},
+		}
+		for _, c := range configs {
+			actualError := s.service.Create(context.Background(), &domain.Provider{
+				Type:   mockProviderType,
+				Config: &domain.ProviderConfig{AccessVerification: c},
+			})
+
+			s.True(errors.Is(actualError, provider.ErrInvalidAccessVerification))
+		}
+	})
+
+	s.Run("should return error if a resource requirement doesn't refer to a resource attribute", func() {
+		requirements := [][]*domain.Condition{
+			{{Field: "$user.department", Match: &domain.MatchCondition{Eq: "data"}}},
+			{{Field: "$resource.details.read_only"}},
+		}
+		for _, r := range requirements {
+			actualError := s.service.Create(context.Background(), &domain.Provider{
+				Type: mockProviderType,
+				Config: &domain.ProviderConfig{
+					Resources: []*domain.ResourceConfig{
+						{Type: "dataset", Roles: []*domain.RoleConfig{{ID: "editor", ResourceRequirements: r}}},
+					},
+				},
+			})
+
+			s.True(errors.Is(actualError, provider.ErrInvalidResourceRequirement))
+		}
+	})
+
+	s.Run("should return error if a role without permissions has no role mapping for the provider type", func() {
+		s.service.RoleMappings = provider.RoleMappings{
+			"read-only": {"other_provider_type": []interface{}{"view"}},
+		}
+		defer func() { s.service.RoleMappings = nil }()
+
+		actualError := s.service.Create(context.Background(), &domain.Provider{
+			Type: mockProviderType,
+			Config: &domain.ProviderConfig{
+				Resources: []*domain.ResourceConfig{{
+					Type:  "dataset",
+					Roles: []*domain.RoleConfig{{ID: "read-only"}},
+				}},
+			},
+		})
+
+		s.ErrorIs(actualError, provider.ErrRoleMappingNotFound)
+	})
+
+	s.Run("should return error if the provider config defining profiles misses the active or a required profile", func() {
+		s.service.Profile = provider.ProfileConfig{Active: "prod", Required: []string{"staging"}}
+		defer func() { s.service.Profile = provider.ProfileConfig{} }()
+		profiles := []map[string]*domain.ProviderProfile{
+			{"staging": {}},
+			{"prod": {}},
+		}
+		for _, pp := range profiles {
+			actualError := s.service.Create(context.Background(), &domain.Provider{
+				Type:   mockProviderType,
+				Config: &domain.ProviderConfig{Profiles: pp},
+			})
+
+			s.ErrorIs(actualError, provider.ErrProviderProfileNotFound)
+		}
+	})
+
+	s.Run("should create the config of every profile and keep the encrypted credentials of the profiles", func() {
+		s.service.Profile = provider.ProfileConfig{Active: "prod"}
+		defer func() { s.service.Profile = provider.ProfileConfig{} }()
+		p := &domain.Provider{
+			Type: mockProviderType,
+			Config: &domain.ProviderConfig{
+				Credentials: "dev-credentials",
+				Labels:      map[string]string{"env": "dev"},
+				Profiles: map[string]*domain.ProviderProfile{
+					"prod": {Credentials: "prod-credentials"},
+				},
+			},
+		}
+		createdConfigs := []*domain.ProviderConfig{}
+		s.mockProvider.On("CreateConfig", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				pc := args.Get(1).(*domain.ProviderConfig)
+				pc.Credentials = fmt.Sprintf("encrypted:%s", pc.Credentials)
+				createdConfigs = append(createdConfigs, pc)
+			}).
+			Return(nil).
+			Twice()
+		s.mockProviderRepository.On("Create", mock.Anything, p).Return(nil).Once()
+
+		actualError := s.service.Create(context.Background(), p)
+
+		s.Nil(actualError)
+		s.Len(createdConfigs, 2)
+		s.Equal(map[string]string{"env": "dev"}, createdConfigs[0].Labels)
+		s.Equal("encrypted:prod-credentials", p.Config.Profiles["prod"].Credentials)
+		s.Equal("encrypted:dev-credentials", p.Config.Credentials)
+	})
+
 	s.Run("should return error if got error from the provider repository", func() {
 		expectedError := errors.New("error from repository")
-		s.mockProvider.On("CreateConfig", mock.Anything).Return(nil).Once()
-		s.mockProviderRepository.On("Create", mock.Anything).Return(expectedError).Once()
+		s.mockProvider.On("CreateConfig", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockProviderRepository.On("Create", mock.Anything, mock.Anything).Return(expectedError).Once()
 
-		actualError := s.service.Create(p)
+		actualError := s.service.Create(context.Background(), p)
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
 	s.Run("should pass the model from the param", func() {
-		s.mockProvider.On("CreateConfig", mock.Anything).Return(nil).Once()
-		s.mockProviderRepository.On("Create", p).Return(nil).Once()
+		s.mockProvider.On("CreateConfig", mock.Anything, mock.Anything).Return(nil).Once()
+		s.mockProviderRepository.On("Create", mock.Anything, p).Return(nil).Once()
 
-		actualError := s.service.Create(p)
+		actualError := s.service.Create(context.Background(), p)
 
 		s.Nil(actualError)
 		s.mockProviderRepository.AssertExpectations(s.T())
@@ -82,9 +247,9 @@ func (s *ServiceTestSuite) TestCreate() {
 func (s *ServiceTestSuite) TestFind() {
 	s.Run("should return nil and error if got error from repository", func() {
 		expectedError := errors.New("error from repository")
-		s.mockProviderRepository.On("Find").Return(nil, expectedError).Once()
+		s.mockProviderRepository.On("Find", mock.Anything).Return(nil, expectedError).Once()
 
-		actualResult, actualError := s.service.Find()
+		actualResult, actualError := s.service.Find(context.Background())
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -92,9 +257,9 @@ func (s *ServiceTestSuite) TestFind() {
 
 	s.Run("should return list of records on success", func() {
 		expectedResult := []*domain.Provider{}
-		s.mockProviderRepository.On("Find").Return(expectedResult, nil).Once()
+		s.mockProviderRepository.On("Find", mock.Anything).Return(expectedResult, nil).Once()
 
-		actualResult, actualError := s.service.Find()
+		actualResult, actualError := s.service.Find(context.Background())
 
 		s.Equal(expectedResult, actualResult)
 		s.Nil(actualError)
@@ -126,9 +291,9 @@ func (s *ServiceTestSuite) TestUpdate() {
 				ID: 1,
 			}
 			expectedError := tc.expectedError
-			s.mockProviderRepository.On("GetByID", expectedProvider.ID).Return(tc.expectedExistingProvider, tc.expectedRepositoryError).Once()
+			s.mockProviderRepository.On("GetByID", mock.Anything, expectedProvider.ID).Return(tc.expectedExistingProvider, tc.expectedRepositoryError).Once()
 
-			actualError := s.service.Update(expectedProvider)
+			actualError := s.service.Update(context.Background(), expectedProvider)
 
 			s.EqualError(actualError, expectedError.Error())
 		}
@@ -180,23 +345,43 @@ func (s *ServiceTestSuite) TestUpdate() {
 		}
 
 		for _, tc := range testCases {
-			s.mockProviderRepository.On("GetByID", tc.updatePayload.ID).Return(tc.existingProvider, nil).Once()
-			s.mockProvider.On("CreateConfig", mock.Anything).Return(nil).Once()
-			s.mockProviderRepository.On("Update", tc.expectedNewProvider).Return(nil)
+			s.mockProviderRepository.On("GetByID", mock.Anything, tc.updatePayload.ID).Return(tc.existingProvider, nil).Once()
+			s.mockProvider.On("Capabilities").Return(domain.ProviderCapabilities{PermanentAccess: true}).Once()
+			s.mockProvider.On("CreateConfig", mock.Anything, mock.Anything).Return(nil).Once()
+			s.mockProviderRepository.On("Update", mock.Anything, tc.expectedNewProvider).Return(nil)
 
-			actualError := s.service.Update(tc.updatePayload)
+			actualError := s.service.Update(context.Background(), tc.updatePayload)
 
 			s.Nil(actualError)
 		}
 	})
 }
 
+func (s *ServiceTestSuite) TestGetCapabilities() {
+	s.Run("should return error if provider type is not registered", func() {
+		actualResult, actualError := s.service.GetCapabilities("invalid-provider-type")
+
+		s.Nil(actualResult)
+		s.EqualError(actualError, provider.ErrInvalidProviderType.Error())
+	})
+
+	s.Run("should return the provider capabilities", func() {
+		expectedResult := &domain.ProviderCapabilities{AccessVerification: true, PermanentAccess: true, Revocation: true}
+		s.mockProvider.On("Capabilities").Return(*expectedResult).Once()
+
+		actualResult, actualError := s.service.GetCapabilities(mockProviderType)
+
+		s.Nil(actualError)
+		s.Equal(expectedResult, actualResult)
+	})
+}
+
 func (s *ServiceTestSuite) TestFetchResources() {
 	s.Run("should return error if got any from provider respository", func() {
 		expectedError := errors.New("any error")
-		s.mockProviderRepository.On("Find").Return(nil, expectedError).Once()
+		s.mockProviderRepository.On("Find", mock.Anything).Return(nil, expectedError).Once()
 
-		actualError := s.service.FetchResources()
+		actualError := s.service.FetchResources(context.Background())
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -210,30 +395,30 @@ func (s *ServiceTestSuite) TestFetchResources() {
 	}
 
 	s.Run("should return error if got any from provider's GetResources", func() {
-		s.mockProviderRepository.On("Find").Return(providers, nil).Once()
+		s.mockProviderRepository.On("Find", mock.Anything).Return(providers, nil).Once()
 		expectedError := errors.New("any error")
-		s.mockProvider.On("GetResources", mock.Anything).Return(nil, expectedError).Once()
+		s.mockProvider.On("GetResources", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
 
-		actualError := s.service.FetchResources()
+		actualError := s.service.FetchResources(context.Background())
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
 	s.Run("should return error if got any from resource service", func() {
-		s.mockProviderRepository.On("Find").Return(providers, nil).Once()
+		s.mockProviderRepository.On("Find", mock.Anything).Return(providers, nil).Once()
 		for _, p := range providers {
-			s.mockProvider.On("GetResources", p.Config).Return([]*domain.Resource{}, nil).Once()
+			s.mockProvider.On("GetResources", mock.Anything, p.Config).Return([]*domain.Resource{}, nil).Once()
 		}
 		expectedError := errors.New("any error")
-		s.mockResourceService.On("BulkUpsert", mock.Anything).Return(expectedError).Once()
+		s.mockResourceService.On("BulkUpsert", mock.Anything, mock.Anything).Return(expectedError).Once()
 
-		actualError := s.service.FetchResources()
+		actualError := s.service.FetchResources(context.Background())
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
 	s.Run("should upsert all resources on success", func() {
-		s.mockProviderRepository.On("Find").Return(providers, nil).Once()
+		s.mockProviderRepository.On("Find", mock.Anything).Return(providers, nil).Once()
 		expectedResources := []*domain.Resource{}
 		for _, p := range providers {
 			resources := []*domain.Resource{
@@ -242,15 +427,44 @@ func (s *ServiceTestSuite) TestFetchResources() {
 					ProviderURN:  p.URN,
 				},
 			}
-			s.mockProvider.On("GetResources", p.Config).Return(resources, nil).Once()
+			s.mockProvider.On("GetResources", mock.Anything, p.Config).Return(resources, nil).Once()
 			expectedResources = append(expectedResources, resources...)
 		}
-		s.mockResourceService.On("BulkUpsert", expectedResources).Return(nil).Once()
+		s.mockResourceService.On("BulkUpsert", mock.Anything, expectedResources).Return(nil).Once()
 
-		actualError := s.service.FetchResources()
+		actualError := s.service.FetchResources(context.Background())
 
 		s.Nil(actualError)
 	})
+
+	s.Run("should upsert the resources page by page if provider supports pagination", func() {
+		p := &pagedProvider{new(mocks.ProviderInterface), new(mocks.ResourcePager)}
+		p.ProviderInterface.On("GetType").Return("paged_provider_type").Once()
+		service := provider.NewService(s.mockProviderRepository, s.mockResourceService, []domain.ProviderInterface{p})
+		config := &domain.ProviderConfig{}
+		s.mockProviderRepository.On("Find", mock.Anything).Return([]*domain.Provider{{Type: "paged_provider_type", Config: config}}, nil).Once()
+		pages := [][]*domain.Resource{
+			{{URN: "resource_1"}},
+			{{URN: "resource_2"}, {URN: "resource_3"}},
+		}
+		p.ResourcePager.On("GetResourcesPaged", mock.Anything, config, mock.Anything).
+			Run(func(args mock.Arguments) {
+				fn := args.Get(2).(func([]*domain.Resource) error)
+				for _, page := range pages {
+					s.Nil(fn(page))
+				}
+			}).
+			Return(nil).
+			Once()
+		s.mockResourceService.On("BulkUpsert", mock.Anything, pages[0]).Return(nil).Once()
+		s.mockResourceService.On("BulkUpsert", mock.Anything, pages[1]).Return(nil).Once()
+		s.mockResourceService.On("BulkUpsert", mock.Anything, []*domain.Resource{}).Return(nil).Once()
+
+		actualError := service.FetchResources(context.Background())
+
+		s.Nil(actualError)
+		s.mockResourceService.AssertExpectations(s.T())
+	})
 }
 
 func (s *ServiceTestSuite) TestGrantAccess() {
@@ -269,7 +483,7 @@ func (s *ServiceTestSuite) TestGrantAccess() {
 			},
 		}
 		for _, tc := range testCases {
-			actualError := s.service.GrantAccess(tc.appealParam)
+			actualError := s.service.GrantAccess(context.Background(), tc.appealParam)
 			s.EqualError(actualError, tc.expectedError.Error())
 		}
 	})
@@ -281,7 +495,7 @@ func (s *ServiceTestSuite) TestGrantAccess() {
 			},
 		}
 		expectedError := provider.ErrInvalidProviderType
-		actualError := s.service.GrantAccess(appeal)
+		actualError := s.service.GrantAccess(context.Background(), appeal)
 		s.EqualError(actualError, expectedError.Error())
 	})
 
@@ -294,42 +508,72 @@ func (s *ServiceTestSuite) TestGrantAccess() {
 
 	s.Run("should return error if got any from provider repository", func() {
 		expectedError := errors.New("any error")
-		s.mockProviderRepository.On("GetOne", mock.Anything, mock.Anything).
+		s.mockProviderRepository.On("GetOne", mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, expectedError).
 			Once()
 
-		actualError := s.service.GrantAccess(validAppeal)
+		actualError := s.service.GrantAccess(context.Background(), validAppeal)
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
 	s.Run("should return error if provider not found", func() {
-		s.mockProviderRepository.On("GetOne", mock.Anything, mock.Anything).
+		s.mockProviderRepository.On("GetOne", mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, nil).
 			Once()
 		expectedError := provider.ErrProviderNotFound
 
-		actualError := s.service.GrantAccess(validAppeal)
+		actualError := s.service.GrantAccess(context.Background(), validAppeal)
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
 	s.Run("should return error if error if got error from provider.GrantAccess", func() {
-		provider := &domain.Provider{
+		p := &domain.Provider{
 			Config: &domain.ProviderConfig{},
 		}
 		s.mockProviderRepository.
-			On("GetOne", validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
-			Return(provider, nil).
+			On("GetOne", mock.Anything, validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
+			Return(p, nil).
 			Once()
 		expectedError := errors.New("any error")
-		s.mockProvider.On("GrantAccess", mock.Anything, mock.Anything).
+		s.mockProvider.On("GrantAccess", mock.Anything, mock.Anything, mock.Anything).
 			Return(expectedError).
 			Once()
 
-		actualError := s.service.GrantAccess(validAppeal)
+		actualError := s.service.GrantAccess(context.Background(), validAppeal)
 
-		s.EqualError(actualError, expectedError.Error())
+		s.ErrorIs(actualError, expectedError)
+		var accessErr *provider.AccessError
+		s.Require().ErrorAs(actualError, &accessErr)
+		s.Equal(provider.AccessOperationGrant, accessErr.Operation)
+		s.Equal(validAppeal.Resource.ProviderType, accessErr.ProviderType)
+		s.Equal(validAppeal.Resource.ProviderURN, accessErr.ProviderURN)
+	})
+
+	s.Run("should include the status code returned by the provider API in the error", func() {
+		p := &domain.Provider{
+			Config: &domain.ProviderConfig{},
+		}
+		s.mockProviderRepository.
+			On("GetOne", mock.Anything, validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
+			Return(p, nil).
+			Once()
+		expectedError := &googleapi.Error{
+			Code:   http.StatusForbidden,
+			Errors: []googleapi.ErrorItem{{Reason: "forbidden", Message: "permission denied"}},
+		}
+		s.mockProvider.On("GrantAccess", mock.Anything, mock.Anything, mock.Anything).
+			Return(expectedError).
+			Once()
+
+		actualError := s.service.GrantAccess(context.Background(), validAppeal)
+
+		var accessErr *provider.AccessError
+		s.Require().ErrorAs(actualError, &accessErr)
+		s.Equal(http.StatusForbidden, accessErr.Code)
+		s.Equal([]string{"forbidden: permission denied"}, accessErr.Details)
+		s.Contains(actualError.Error(), "code=403")
 	})
 
 	s.Run("should grant access to the provider on success", func() {
@@ -337,17 +581,110 @@ func (s *ServiceTestSuite) TestGrantAccess() {
 			Config: &domain.ProviderConfig{},
 		}
 		s.mockProviderRepository.
-			On("GetOne", validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
+			On("GetOne", mock.Anything, validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
 			Return(provider, nil).
 			Once()
 		s.mockProvider.
-			On("GrantAccess", provider.Config, validAppeal).
+			On("GrantAccess", mock.Anything, provider.Config, validAppeal).
 			Return(nil).
 			Once()
 
-		actualError := s.service.GrantAccess(validAppeal)
+		actualError := s.service.GrantAccess(context.Background(), validAppeal)
+
+		s.Nil(actualError)
+	})
+
+	s.Run("should resolve the permissions of the logical roles from the role mappings", func() {
+		s.service.RoleMappings = provider.RoleMappings{
+			"read-only": {
+				mockProviderType:      []interface{}{"roles/viewer"},
+				"other_provider_type": []interface{}{"view"},
+			},
+		}
+		defer func() { s.service.RoleMappings = nil }()
+		ownerRole := &domain.RoleConfig{ID: "owner", Permissions: []interface{}{"roles/owner"}}
+		logicalRole := &domain.RoleConfig{ID: "read-only"}
+		p := &domain.Provider{
+			Type: mockProviderType,
+			Config: &domain.ProviderConfig{
+				Resources: []*domain.ResourceConfig{{
+					Type:  "dataset",
+					Roles: []*domain.RoleConfig{ownerRole, logicalRole},
+				}},
+			},
+		}
+		s.mockProviderRepository.
+			On("GetOne", mock.Anything, validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
+			Return(p, nil).
+			Once()
+		var actualConfig *domain.ProviderConfig
+		s.mockProvider.
+			On("GrantAccess", mock.Anything, mock.Anything, validAppeal).
+			Run(func(args mock.Arguments) { actualConfig = args.Get(1).(*domain.ProviderConfig) }).
+			Return(nil).
+			Once()
+
+		actualError := s.service.GrantAccess(context.Background(), validAppeal)
+
+		s.Nil(actualError)
+		s.Equal([]interface{}{"roles/owner"}, actualConfig.Resources[0].Roles[0].Permissions)
+		s.Equal([]interface{}{"roles/viewer"}, actualConfig.Resources[0].Roles[1].Permissions)
+		s.Empty(logicalRole.Permissions)
+	})
+
+	s.Run("should grant access with the config of the active profile", func() {
+		s.service.Profile = provider.ProfileConfig{Active: "prod"}
+		defer func() { s.service.Profile = provider.ProfileConfig{} }()
+		prodResources := []*domain.ResourceConfig{{Type: "dataset"}}
+		p := &domain.Provider{
+			Type: mockProviderType,
+			Config: &domain.ProviderConfig{
+				Credentials: "dev-credentials",
+				Labels:      map[string]string{"team": "data"},
+				Profiles: map[string]*domain.ProviderProfile{
+					"prod": {Credentials: "prod-credentials", Resources: prodResources},
+				},
+			},
+		}
+		s.mockProviderRepository.
+			On("GetOne", mock.Anything, validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
+			Return(p, nil).
+			Once()
+		expectedConfig := &domain.ProviderConfig{
+			Credentials: "prod-credentials",
+			Labels:      map[string]string{"team": "data"},
+			Resources:   prodResources,
+		}
+		s.mockProvider.On("GrantAccess", mock.Anything, expectedConfig, validAppeal).Return(nil).Once()
+
+		actualError := s.service.GrantAccess(context.Background(), validAppeal)
 
 		s.Nil(actualError)
+		s.mockProvider.AssertExpectations(s.T())
+	})
+
+	s.Run("should trace the access operation along with the appeal and provider type", func() {
+		spanRecorder := tracetest.NewSpanRecorder()
+		s.service.Tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+		appeal := &domain.Appeal{ID: 1, Resource: validAppeal.Resource}
+		provider := &domain.Provider{
+			Config: &domain.ProviderConfig{},
+		}
+		s.mockProviderRepository.
+			On("GetOne", mock.Anything, validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
+			Return(provider, nil).
+			Once()
+		expectedError := errors.New("any error")
+		s.mockProvider.On("GrantAccess", mock.Anything, provider.Config, appeal).Return(expectedError).Once()
+
+		s.service.GrantAccess(context.Background(), appeal)
+
+		spans := spanRecorder.Ended()
+		s.Len(spans, 1)
+		s.Equal("provider.GrantAccess", spans[0].Name())
+		s.Contains(spans[0].Attributes(), tracing.AppealID(1))
+		s.Contains(spans[0].Attributes(), tracing.ProviderTypeKey.String(mockProviderType))
+		s.Equal(codes.Error, spans[0].Status().Code)
 	})
 }
 
@@ -367,7 +704,7 @@ func (s *ServiceTestSuite) TestRevokeAccess() {
 			},
 		}
 		for _, tc := range testCases {
-			actualError := s.service.RevokeAccess(tc.appealParam)
+			actualError := s.service.RevokeAccess(context.Background(), tc.appealParam)
 			s.EqualError(actualError, tc.expectedError.Error())
 		}
 	})
@@ -379,7 +716,7 @@ func (s *ServiceTestSuite) TestRevokeAccess() {
 			},
 		}
 		expectedError := provider.ErrInvalidProviderType
-		actualError := s.service.RevokeAccess(appeal)
+		actualError := s.service.RevokeAccess(context.Background(), appeal)
 		s.EqualError(actualError, expectedError.Error())
 	})
 
@@ -392,42 +729,72 @@ func (s *ServiceTestSuite) TestRevokeAccess() {
 
 	s.Run("should return error if got any from provider repository", func() {
 		expectedError := errors.New("any error")
-		s.mockProviderRepository.On("GetOne", mock.Anything, mock.Anything).
+		s.mockProviderRepository.On("GetOne", mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, expectedError).
 			Once()
 
-		actualError := s.service.RevokeAccess(validAppeal)
+		actualError := s.service.RevokeAccess(context.Background(), validAppeal)
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
 	s.Run("should return error if provider not found", func() {
-		s.mockProviderRepository.On("GetOne", mock.Anything, mock.Anything).
+		s.mockProviderRepository.On("GetOne", mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, nil).
 			Once()
 		expectedError := provider.ErrProviderNotFound
 
-		actualError := s.service.RevokeAccess(validAppeal)
+		actualError := s.service.RevokeAccess(context.Background(), validAppeal)
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 
 	s.Run("should return error if error if got error from provider.RevokeAccess", func() {
-		provider := &domain.Provider{
+		p := &domain.Provider{
 			Config: &domain.ProviderConfig{},
 		}
 		s.mockProviderRepository.
-			On("GetOne", validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
-			Return(provider, nil).
+			On("GetOne", mock.Anything, validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
+			Return(p, nil).
 			Once()
 		expectedError := errors.New("any error")
-		s.mockProvider.On("RevokeAccess", mock.Anything, mock.Anything).
+		s.mockProvider.On("RevokeAccess", mock.Anything, mock.Anything, mock.Anything).
 			Return(expectedError).
 			Once()
 
-		actualError := s.service.RevokeAccess(validAppeal)
+		actualError := s.service.RevokeAccess(context.Background(), validAppeal)
 
-		s.EqualError(actualError, expectedError.Error())
+		s.ErrorIs(actualError, expectedError)
+		var accessErr *provider.AccessError
+		s.Require().ErrorAs(actualError, &accessErr)
+		s.Equal(provider.AccessOperationRevoke, accessErr.Operation)
+		s.Equal(validAppeal.Resource.ProviderType, accessErr.ProviderType)
+		s.Equal(validAppeal.Resource.ProviderURN, accessErr.ProviderURN)
+	})
+
+	s.Run("should include the status code returned by the provider API in the error", func() {
+		p := &domain.Provider{
+			Config: &domain.ProviderConfig{},
+		}
+		s.mockProviderRepository.
+			On("GetOne", mock.Anything, validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
+			Return(p, nil).
+			Once()
+		expectedError := &googleapi.Error{
+			Code:   http.StatusForbidden,
+			Errors: []googleapi.ErrorItem{{Reason: "forbidden", Message: "permission denied"}},
+		}
+		s.mockProvider.On("RevokeAccess", mock.Anything, mock.Anything, mock.Anything).
+			Return(expectedError).
+			Once()
+
+		actualError := s.service.RevokeAccess(context.Background(), validAppeal)
+
+		var accessErr *provider.AccessError
+		s.Require().ErrorAs(actualError, &accessErr)
+		s.Equal(http.StatusForbidden, accessErr.Code)
+		s.Equal([]string{"forbidden: permission denied"}, accessErr.Details)
+		s.Contains(actualError.Error(), "code=403")
 	})
 
 	s.Run("should grant access to the provider on success", func() {
@@ -435,20 +802,402 @@ func (s *ServiceTestSuite) TestRevokeAccess() {
 			Config: &domain.ProviderConfig{},
 		}
 		s.mockProviderRepository.
-			On("GetOne", validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
+			On("GetOne", mock.Anything, validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
 			Return(provider, nil).
 			Once()
 		s.mockProvider.
-			On("RevokeAccess", provider.Config, validAppeal).
+			On("RevokeAccess", mock.Anything, provider.Config, validAppeal).
 			Return(nil).
 			Once()
 
-		actualError := s.service.RevokeAccess(validAppeal)
+		actualError := s.service.RevokeAccess(context.Background(), validAppeal)
 
 		s.Nil(actualError)
 	})
 }
 
+func (s *ServiceTestSuite) TestImportResources() {
+	newService := func() (*provider.Service, *checkedProvider) {
+		p := &checkedProvider{new(mocks.ProviderInterface), new(mocks.ConnectionChecker)}
+		p.ProviderInterface.On("GetType").Return("checked_provider_type").Once()
+		service := provider.NewService(s.mockProviderRepository, s.mockResourceService, []domain.ProviderInterface{
+			provider.WithRetry(p, provider.RetryConfig{MaxAttempts: 2}),
+		})
+		return service, p
+	}
+	config := &domain.ProviderConfig{}
+
+	s.Run("should return error if the provider is not registered", func() {
+		actualReport, actualError := s.service.ImportResources(context.Background(), "invalid-provider-type", "urn", 0)
+
+		s.Nil(actualReport)
+		s.ErrorIs(actualError, provider.ErrInvalidProviderType)
+	})
+
+	s.Run("should return error if the provider is unreachable", func() {
+		service, p := newService()
+		s.mockProviderRepository.On("GetOne", mock.Anything, "checked_provider_type", "urn").Return(&domain.Provider{Config: config}, nil).Once()
+		p.ConnectionChecker.On("CheckConnection", mock.Anything, config).Return(errors.New("invalid credentials")).Once()
+
+		actualReport, actualError := service.ImportResources(context.Background(), "checked_provider_type", "urn", 0)
+
+		s.Nil(actualReport)
+		s.ErrorIs(actualError, provider.ErrProviderUnreachable)
+		p.ProviderInterface.AssertNotCalled(s.T(), "GetResources", mock.Anything)
+	})
+
+	s.Run("should upsert the resources in batches and report the counts", func() {
+		service, p := newService()
+		resources := []*domain.Resource{
+			{URN: "dataset_1", Type: "dataset"},
+			{URN: "table_1", Type: "table"},
+			{URN: "table_2", Type: "table"},
+		}
+		s.mockProviderRepository.On("GetOne", mock.Anything, "checked_provider_type", "urn").Return(&domain.Provider{Config: config}, nil).Once()
+		p.ConnectionChecker.On("CheckConnection", mock.Anything, config).Return(nil).Once()
+		p.ProviderInterface.On("GetResources", mock.Anything, config).Return(resources, nil).Once()
+		s.mockResourceService.On("BulkUpsert", mock.Anything, resources[:2]).Return(nil).Once()
+		s.mockResourceService.On("BulkUpsert", mock.Anything, resources[2:]).Return(nil).Once()
+
+		actualReport, actualError := service.ImportResources(context.Background(), "checked_provider_type", "urn", 2)
+
+		s.Nil(actualError)
+		s.Equal(&domain.ResourceImportReport{
+			ProviderType:  "checked_provider_type",
+			ProviderURN:   "urn",
+			Imported:      3,
+			ResourceTypes: map[string]int{"dataset": 1, "table": 2},
+			Batches:       2,
+		}, actualReport)
+	})
+
+	s.Run("should import the resources into the organization of the provider", func() {
+		service, p := newService()
+		resources := []*domain.Resource{{URN: "dataset_1", Type: "dataset"}}
+		s.mockProviderRepository.On("GetOne", mock.Anything, "checked_provider_type", "urn").Return(&domain.Provider{Config: config, OrganizationID: "org-a"}, nil).Once()
+		p.ConnectionChecker.On("CheckConnection", mock.Anything, config).Return(nil).Once()
+		p.ProviderInterface.On("GetResources", mock.Anything, config).Return(resources, nil).Once()
+		s.mockResourceService.On("BulkUpsert", mock.Anything, []*domain.Resource{
+			{URN: "dataset_1", Type: "dataset", OrganizationID: "org-a"},
+		}).Return(nil).Once()
+
+		_, actualError := service.ImportResources(context.Background(), "checked_provider_type", "urn", 0)
+
+		s.Nil(actualError)
+		s.mockResourceService.AssertExpectations(s.T())
+	})
+
+	s.Run("should report the resources imported before a batch fails", func() {
+		service, p := newService()
+		resources := []*domain.Resource{{URN: "dataset_1", Type: "dataset"}, {URN: "dataset_2", Type: "dataset"}}
+		expectedError := errors.New("db error")
+		s.mockProviderRepository.On("GetOne", mock.Anything, "checked_provider_type", "urn").Return(&domain.Provider{Config: config}, nil).Once()
+		p.ConnectionChecker.On("CheckConnection", mock.Anything, config).Return(nil).Once()
+		p.ProviderInterface.On("GetResources", mock.Anything, config).Return(resources, nil).Once()
+		s.mockResourceService.On("BulkUpsert", mock.Anything, resources[:1]).Return(nil).Once()
+		s.mockResourceService.On("BulkUpsert", mock.Anything, resources[1:]).Return(expectedError).Once()
+
+		actualReport, actualError := service.ImportResources(context.Background(), "checked_provider_type", "urn", 1)
+
+		s.ErrorIs(actualError, expectedError)
+		s.Equal(1, actualReport.Imported)
+	})
+}
+
+type checkedProvider struct {
+	*mocks.ProviderInterface
+	*mocks.ConnectionChecker
+}
+
+type pagedProvider struct {
+	*mocks.ProviderInterface
+	*mocks.ResourcePager
+}
+
+type verifiableProvider struct {
+	*mocks.ProviderInterface
+	*mocks.AccessVerifier
+}
+
+func (s *ServiceTestSuite) TestVerifyAccess() {
+	validAppeal := &domain.Appeal{
+		Resource: &domain.Resource{
+			ProviderType: mockProviderType,
+			ProviderURN:  "urn",
+		},
+	}
+
+	s.Run("should return error if provider is not exists", func() {
+		appeal := &domain.Appeal{
+			Resource: &domain.Resource{
+				ProviderType: "invalid-provider-type",
+			},
+		}
+
+		actualResult, actualError := s.service.VerifyAccess(context.Background(), appeal)
+
+		s.False(actualResult)
+		s.EqualError(actualError, provider.ErrInvalidProviderType.Error())
+	})
+
+	s.Run("should return true if provider doesn't support access verification", func() {
+		actualResult, actualError := s.service.VerifyAccess(context.Background(), validAppeal)
+
+		s.True(actualResult)
+		s.Nil(actualError)
+	})
+
+	s.Run("should return the verification result from the provider", func() {
+		mockProvider := new(mocks.ProviderInterface)
+		mockProvider.On("GetType").Return(mockProviderType).Once()
+		mockVerifier := new(mocks.AccessVerifier)
+		service := provider.NewService(s.mockProviderRepository, s.mockResourceService, []domain.ProviderInterface{
+			&verifiableProvider{mockProvider, mockVerifier},
+		})
+		p := &domain.Provider{
+			Config: &domain.ProviderConfig{},
+		}
+		s.mockProviderRepository.
+			On("GetOne", mock.Anything, validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
+			Return(p, nil).
+			Once()
+		mockVerifier.On("VerifyAccess", mock.Anything, p.Config, validAppeal).Return(false, nil).Once()
+
+		actualResult, actualError := service.VerifyAccess(context.Background(), validAppeal)
+
+		s.False(actualResult)
+		s.Nil(actualError)
+	})
+
+	s.Run("should poll the verification until the access is confirmed or the timeout elapses", func() {
+		testCases := []struct {
+			name           string
+			onTimeout      string
+			results        []bool
+			expectedResult bool
+		}{
+			{
+				name:           "confirmed after a few attempts",
+				results:        []bool{false, false, true},
+				expectedResult: true,
+			},
+			{
+				name:    "never confirmed",
+				results: []bool{false},
+			},
+			{
+				name:           "never confirmed and activated on timeout",
+				onTimeout:      domain.AccessVerificationOnTimeoutActivate,
+				results:        []bool{false},
+				expectedResult: true,
+			},
+		}
+		for _, tc := range testCases {
+			s.Run(tc.name, func() {
+				mockProvider := new(mocks.ProviderInterface)
+				mockProvider.On("GetType").Return(mockProviderType).Once()
+				mockVerifier := new(mocks.AccessVerifier)
+				service := provider.NewService(s.mockProviderRepository, s.mockResourceService, []domain.ProviderInterface{
+					&verifiableProvider{mockProvider, mockVerifier},
+				})
+				p := &domain.Provider{
+					Config: &domain.ProviderConfig{
+						AccessVerification: &domain.AccessVerificationConfig{
+							PollInterval: "1ms",
+							Timeout:      "20ms",
+							OnTimeout:    tc.onTimeout,
+						},
+					},
+				}
+				s.mockProviderRepository.
+					On("GetOne", mock.Anything, validAppeal.Resource.ProviderType, validAppeal.Resource.ProviderURN).
+					Return(p, nil).
+					Once()
+				for _, result := range tc.results[:len(tc.results)-1] {
+					mockVerifier.On("VerifyAccess", mock.Anything, p.Config, validAppeal).Return(result, nil).Once()
+				}
+				mockVerifier.On("VerifyAccess", mock.Anything, p.Config, validAppeal).Return(tc.results[len(tc.results)-1], nil)
+
+				actualResult, actualError := service.VerifyAccess(context.Background(), validAppeal)
+
+				s.Nil(actualError)
+				s.Equal(tc.expectedResult, actualResult)
+				if tc.expectedResult && tc.onTimeout == "" {
+					mockVerifier.AssertNumberOfCalls(s.T(), "VerifyAccess", len(tc.results))
+				} else {
+					s.Greater(len(mockVerifier.Calls), 1)
+				}
+			})
+		}
+	})
+}
+
+type urnFormattedProvider struct {
+	*mocks.ProviderInterface
+	*mocks.URNFormatter
+}
+
+func (s *ServiceTestSuite) TestParseURN() {
+	s.Run("should return error if provider is not exists", func() {
+		actualResult, actualError := s.service.ParseURN("invalid-provider-type", "resource_type", "urn")
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, provider.ErrInvalidProviderType)
+	})
+
+	s.Run("should return error if provider doesn't expose its urn format", func() {
+		actualResult, actualError := s.service.ParseURN(mockProviderType, "resource_type", "urn")
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, provider.ErrURNFormatNotSupported)
+	})
+
+	s.Run("should parse and format the urn through the provider", func() {
+		mockProvider := new(mocks.ProviderInterface)
+		mockProvider.On("GetType").Return(mockProviderType).Once()
+		mockFormatter := new(mocks.URNFormatter)
+		service := provider.NewService(s.mockProviderRepository, s.mockResourceService, []domain.ProviderInterface{
+			provider.WithRetry(&urnFormattedProvider{mockProvider, mockFormatter}, provider.RetryConfig{MaxAttempts: 2}),
+		})
+		expectedURN := &domain.ResourceURN{Type: "resource_type", Parts: []string{"parent", "child"}}
+		mockFormatter.On("ParseURN", "resource_type", "parent/child").Return(expectedURN, nil).Once()
+		mockFormatter.On("FormatURN", expectedURN).Return("parent/child", nil).Once()
+
+		actualURN, actualError := service.ParseURN(mockProviderType, "resource_type", "parent/child")
+
+		s.Nil(actualError)
+		s.Equal(expectedURN, actualURN)
+
+		formattedURN, actualError := service.FormatURN(mockProviderType, actualURN)
+
+		s.Nil(actualError)
+		s.Equal("parent/child", formattedURN)
+	})
+}
+
+func (s *ServiceTestSuite) TestGetRolePermissions() {
+	s.Run("should return error if provider config is not found", func() {
+		s.mockProviderRepository.On("GetOne", mock.Anything, mockProviderType, "provider_urn").Return(nil, nil).Once()
+
+		actualResult, actualError := s.service.GetRolePermissions(context.Background(), mockProviderType, "provider_urn", "dataset", "viewer")
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, provider.ErrProviderNotFound)
+	})
+
+	p := &domain.Provider{
+		Type: mockProviderType,
+		URN:  "provider_urn",
+		Config: &domain.ProviderConfig{
+			Resources: []*domain.ResourceConfig{
+				{
+					Type:    "dataset",
+					Aliases: []string{"schema"},
+					Roles: []*domain.RoleConfig{
+						{ID: "viewer", Permissions: []interface{}{"READER"}},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run("should return error if resource type or role is not configured", func() {
+		s.mockProviderRepository.On("GetOne", mock.Anything, mockProviderType, "provider_urn").Return(p, nil).Twice()
+
+		_, actualError := s.service.GetRolePermissions(context.Background(), mockProviderType, "provider_urn", "table", "viewer")
+		s.ErrorIs(actualError, provider.ErrResourceTypeNotFound)
+
+		_, actualError = s.service.GetRolePermissions(context.Background(), mockProviderType, "provider_urn", "dataset", "owner")
+		s.ErrorIs(actualError, provider.ErrRoleNotFound)
+	})
+
+	s.Run("should return the role permissions matching the resource type or its alias", func() {
+		s.mockProviderRepository.On("GetOne", mock.Anything, mockProviderType, "provider_urn").Return(p, nil).Once()
+
+		actualResult, actualError := s.service.GetRolePermissions(context.Background(), mockProviderType, "provider_urn", "schema", "viewer")
+
+		s.Nil(actualError)
+		s.Equal([]interface{}{"READER"}, actualResult)
+	})
+}
+
+func (s *ServiceTestSuite) TestValidateProfiles() {
+	s.service.Profile = provider.ProfileConfig{Active: "prod"}
+
+	s.Run("should return error if a stored provider config misses the active profile", func() {
+		s.mockProviderRepository.On("Find", mock.Anything).Return([]*domain.Provider{
+			{Type: mockProviderType, URN: "without_profiles", Config: &domain.ProviderConfig{}},
+			{Type: mockProviderType, URN: "staging_only", Config: &domain.ProviderConfig{
+				Profiles: map[string]*domain.ProviderProfile{"staging": {}},
+			}},
+		}, nil).Once()
+
+		actualError := s.service.ValidateProfiles(context.Background())
+
+		s.ErrorIs(actualError, provider.ErrProviderProfileNotFound)
+		s.Contains(actualError.Error(), "staging_only")
+	})
+
+	s.Run("should return nil if the stored provider configs define the active profile or none at all", func() {
+		s.mockProviderRepository.On("Find", mock.Anything).Return([]*domain.Provider{
+			{Type: mockProviderType, URN: "without_profiles", Config: &domain.ProviderConfig{}},
+			{Type: mockProviderType, URN: "with_profiles", Config: &domain.ProviderConfig{
+				Profiles: map[string]*domain.ProviderProfile{"prod": {}},
+			}},
+		}, nil).Once()
+
+		actualError := s.service.ValidateProfiles(context.Background())
+
+		s.Nil(actualError)
+	})
+}
+
+func (s *ServiceTestSuite) TestGetTeamQuota() {
+	typeQuota := &domain.TeamQuotaConfig{Role: "owner", Limit: 3}
+	resourceQuota := &domain.TeamQuotaConfig{Role: "owner", URN: "project:prod", Limit: 1}
+	p := &domain.Provider{
+		Type: mockProviderType,
+		URN:  "provider_urn",
+		Config: &domain.ProviderConfig{
+			Resources: []*domain.ResourceConfig{
+				{
+					Type:       "dataset",
+					TeamQuotas: []*domain.TeamQuotaConfig{resourceQuota, typeQuota},
+				},
+			},
+		},
+	}
+
+	s.Run("should return error if resource type is not configured", func() {
+		s.mockProviderRepository.On("GetOne", mock.Anything, mockProviderType, "provider_urn").Return(p, nil).Once()
+
+		actualResult, actualError := s.service.GetTeamQuota(context.Background(), mockProviderType, "provider_urn", "table", "project:prod", "owner")
+
+		s.Nil(actualResult)
+		s.ErrorIs(actualError, provider.ErrResourceTypeNotFound)
+	})
+
+	s.Run("should prefer the quota of the resource over the one of its type", func() {
+		testCases := []struct {
+			resourceURN   string
+			role          string
+			expectedQuota *domain.TeamQuotaConfig
+		}{
+			{"project:prod", "owner", resourceQuota},
+			{"project:staging", "owner", typeQuota},
+			{"project:prod", "viewer", nil},
+		}
+		for _, tc := range testCases {
+			s.mockProviderRepository.On("GetOne", mock.Anything, mockProviderType, "provider_urn").Return(p, nil).Once()
+
+			actualResult, actualError := s.service.GetTeamQuota(context.Background(), mockProviderType, "provider_urn", "dataset", tc.resourceURN, tc.role)
+
+			s.Nil(actualError)
+			s.Equal(tc.expectedQuota, actualResult)
+		}
+	})
+}
+
 func TestService(t *testing.T) {
 	suite.Run(t, new(ServiceTestSuite))
 }
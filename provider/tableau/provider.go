@@ -1,20 +1,23 @@
 package tableau
 
 import (
+	"context"
+
 	"github.com/mitchellh/mapstructure"
 	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/provider/clientcache"
 )
 
 type provider struct {
 	typeName string
-	Clients  map[string]TableauClient
+	Clients  *clientcache.Cache
 	crypto   domain.Crypto
 }
 
 func NewProvider(typeName string, crypto domain.Crypto) *provider {
 	return &provider{
 		typeName: typeName,
-		Clients:  map[string]TableauClient{},
+		Clients:  clientcache.New(),
 		crypto:   crypto,
 	}
 }
@@ -23,7 +26,17 @@ func (p *provider) GetType() string {
 	return p.typeName
 }
 
-func (p *provider) CreateConfig(pc *domain.ProviderConfig) error {
+func (p *provider) Capabilities() domain.ProviderCapabilities {
+	return domain.ProviderCapabilities{
+		BatchGrant:         false,
+		AccessListing:      false,
+		AccessVerification: false,
+		PermanentAccess:    true,
+		Revocation:         true,
+	}
+}
+
+func (p *provider) CreateConfig(ctx context.Context, pc *domain.ProviderConfig) error {
 	c := NewConfig(pc, p.crypto)
 
 	if err := c.ParseAndValidate(); err != nil {
@@ -33,7 +46,7 @@ func (p *provider) CreateConfig(pc *domain.ProviderConfig) error {
 	return c.EncryptCredentials()
 }
 
-func (p *provider) GetResources(pc *domain.ProviderConfig) ([]*domain.Resource, error) {
+func (p *provider) GetResources(ctx context.Context, pc *domain.ProviderConfig) ([]*domain.Resource, error) {
 	var creds Credentials
 	if err := mapstructure.Decode(pc.Credentials, &creds); err != nil {
 		return nil, err
@@ -103,7 +116,7 @@ func (p *provider) GetResources(pc *domain.ProviderConfig) ([]*domain.Resource,
 	return resources, nil
 }
 
-func (p *provider) GrantAccess(pc *domain.ProviderConfig, a *domain.Appeal) error {
+func (p *provider) GrantAccess(ctx context.Context, pc *domain.ProviderConfig, a *domain.Appeal) error {
 
 	permissions, err := getPermissions(pc.Resources, a)
 	if err != nil {
@@ -220,7 +233,7 @@ func (p *provider) GrantAccess(pc *domain.ProviderConfig, a *domain.Appeal) erro
 	return ErrInvalidResourceType
 }
 
-func (p *provider) RevokeAccess(pc *domain.ProviderConfig, a *domain.Appeal) error {
+func (p *provider) RevokeAccess(ctx context.Context, pc *domain.ProviderConfig, a *domain.Appeal) error {
 
 	permissions, err := getPermissions(pc.Resources, a)
 	if err != nil {
@@ -333,27 +346,33 @@ func (p *provider) RevokeAccess(pc *domain.ProviderConfig, a *domain.Appeal) err
 }
 
 func (p *provider) getClient(providerURN string, credentials Credentials) (TableauClient, error) {
-	if p.Clients[providerURN] != nil {
-		return p.Clients[providerURN], nil
-	}
+	client, err := p.Clients.Get(providerURN, credentials, func() (interface{}, error) {
+		err := credentials.Decrypt(p.crypto)
+		if err != nil {
+			return nil, err
+		}
 
-	err := credentials.Decrypt(p.crypto)
-	if err != nil {
-		return nil, err
-	}
+		client, err := NewClient(&ClientConfig{
+			Host:       credentials.Host,
+			Username:   credentials.Username,
+			Password:   credentials.Password,
+			ContentURL: credentials.ContentURL,
+		})
+		if err != nil {
+			return nil, err
+		}
 
-	client, err := NewClient(&ClientConfig{
-		Host:       credentials.Host,
-		Username:   credentials.Username,
-		Password:   credentials.Password,
-		ContentURL: credentials.ContentURL,
+		return client, nil
 	})
 	if err != nil {
 		return nil, err
 	}
+	return client.(TableauClient), nil
+}
 
-	p.Clients[providerURN] = client
-	return client, nil
+// EvictClients drops the client of the provider URN, e.g. once its credentials are updated
+func (p *provider) EvictClients(providerURN string) {
+	p.Clients.Evict(providerURN)
 }
 
 func getPermissions(resourceConfigs []*domain.ResourceConfig, a *domain.Appeal) ([]PermissionConfig, error) {
@@ -1,6 +1,7 @@
 package tableau_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestGetResources(t *testing.T) {
 			Credentials: "invalid-creds",
 		}
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.Error(t, actualError)
@@ -54,7 +55,7 @@ func TestGetResources(t *testing.T) {
 			},
 		}
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -65,9 +66,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.TableauClient)
 		p := tableau.NewProvider("", crypto)
-		p.Clients = map[string]tableau.TableauClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -76,7 +75,7 @@ func TestGetResources(t *testing.T) {
 		expectedError := errors.New("client error")
 		client.On("GetWorkbooks").Return(nil, expectedError).Once()
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -87,9 +86,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.TableauClient)
 		p := tableau.NewProvider("", crypto)
-		p.Clients = map[string]tableau.TableauClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -99,7 +96,7 @@ func TestGetResources(t *testing.T) {
 		expectedError := errors.New("client error")
 		client.On("GetFlows").Return(nil, expectedError).Once()
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -110,9 +107,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.TableauClient)
 		p := tableau.NewProvider("", crypto)
-		p.Clients = map[string]tableau.TableauClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -123,7 +118,7 @@ func TestGetResources(t *testing.T) {
 		expectedError := errors.New("client error")
 		client.On("GetDataSources").Return(nil, expectedError).Once()
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -134,9 +129,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.TableauClient)
 		p := tableau.NewProvider("", crypto)
-		p.Clients = map[string]tableau.TableauClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -148,7 +141,7 @@ func TestGetResources(t *testing.T) {
 		expectedError := errors.New("client error")
 		client.On("GetViews").Return(nil, expectedError).Once()
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -159,9 +152,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.TableauClient)
 		p := tableau.NewProvider("", crypto)
-		p.Clients = map[string]tableau.TableauClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -174,7 +165,7 @@ func TestGetResources(t *testing.T) {
 		expectedError := errors.New("client error")
 		client.On("GetMetrics").Return(nil, expectedError).Once()
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Nil(t, actualResources)
 		assert.EqualError(t, actualError, expectedError.Error())
@@ -185,9 +176,7 @@ func TestGetResources(t *testing.T) {
 		crypto := new(mocks.Crypto)
 		client := new(mocks.TableauClient)
 		p := tableau.NewProvider("", crypto)
-		p.Clients = map[string]tableau.TableauClient{
-			providerURN: client,
-		}
+		p.Clients.Set(providerURN, client)
 
 		pc := &domain.ProviderConfig{
 			URN:         providerURN,
@@ -313,7 +302,7 @@ func TestGetResources(t *testing.T) {
 			},
 		}
 
-		actualResources, actualError := p.GetResources(pc)
+		actualResources, actualError := p.GetResources(context.Background(), pc)
 
 		assert.Equal(t, expectedResources, actualResources)
 		assert.Nil(t, actualError)
@@ -390,7 +379,7 @@ func TestGrantAccess(t *testing.T) {
 				Resources: tc.resourceConfigs,
 			}
 
-			actualError := p.GrantAccess(providerConfig, tc.appeal)
+			actualError := p.GrantAccess(context.Background(), providerConfig, tc.appeal)
 			assert.EqualError(t, actualError, tc.expectedError.Error())
 		}
 	})
@@ -424,7 +413,7 @@ func TestGrantAccess(t *testing.T) {
 			Role: "test-role",
 		}
 
-		actualError := p.GrantAccess(pc, a)
+		actualError := p.GrantAccess(context.Background(), pc, a)
 		assert.Error(t, actualError)
 
 	})
@@ -465,7 +454,7 @@ func TestGrantAccess(t *testing.T) {
 			Role: "test-role",
 		}
 
-		actualError := p.GrantAccess(pc, a)
+		actualError := p.GrantAccess(context.Background(), pc, a)
 
 		assert.EqualError(t, actualError, expectedError.Error())
 
@@ -508,7 +497,7 @@ func TestGrantAccess(t *testing.T) {
 			Role: "test-role",
 		}
 
-		actualError := p.GrantAccess(pc, a)
+		actualError := p.GrantAccess(context.Background(), pc, a)
 
 		assert.EqualError(t, actualError, expectedError.Error())
 	})
@@ -520,9 +509,7 @@ func TestGrantAccess(t *testing.T) {
 			crypto := new(mocks.Crypto)
 			client := new(mocks.TableauClient)
 			p := tableau.NewProvider("", crypto)
-			p.Clients = map[string]tableau.TableauClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantWorkbookAccess", mock.Anything, mock.Anything, mock.Anything).Return(expectedError).Once()
 
 			pc := &domain.ProviderConfig{
@@ -558,7 +545,7 @@ func TestGrantAccess(t *testing.T) {
 				Role: "test-role",
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.EqualError(t, actualError, expectedError.Error())
 		})
@@ -574,9 +561,7 @@ func TestGrantAccess(t *testing.T) {
 			expectedUser := "test@email.com"
 			expectedRole := tableau.PermissionNames[tableau.ResourceTypeWorkbook][0] + ":" + tableau.PermissionModes[0]
 			p := tableau.NewProvider("", crypto)
-			p.Clients = map[string]tableau.TableauClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantWorkbookAccess", expectedWorkbook, expectedUser, expectedRole).Return(nil).Once()
 
 			pc := &domain.ProviderConfig{
@@ -615,7 +600,7 @@ func TestGrantAccess(t *testing.T) {
 				ID:         999,
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.Nil(t, actualError)
 		})
@@ -628,9 +613,7 @@ func TestGrantAccess(t *testing.T) {
 			crypto := new(mocks.Crypto)
 			client := new(mocks.TableauClient)
 			p := tableau.NewProvider("", crypto)
-			p.Clients = map[string]tableau.TableauClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantFlowAccess", mock.Anything, mock.Anything, mock.Anything).Return(expectedError).Once()
 
 			pc := &domain.ProviderConfig{
@@ -666,7 +649,7 @@ func TestGrantAccess(t *testing.T) {
 				Role: "test-role",
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.EqualError(t, actualError, expectedError.Error())
 		})
@@ -682,9 +665,7 @@ func TestGrantAccess(t *testing.T) {
 			expectedUser := "test@email.com"
 			expectedRole := tableau.PermissionNames[tableau.ResourceTypeFlow][0] + ":" + tableau.PermissionModes[0]
 			p := tableau.NewProvider("", crypto)
-			p.Clients = map[string]tableau.TableauClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantFlowAccess", expectedFlow, expectedUser, expectedRole).Return(nil).Once()
 
 			pc := &domain.ProviderConfig{
@@ -723,7 +704,7 @@ func TestGrantAccess(t *testing.T) {
 				ID:         999,
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.Nil(t, actualError)
 		})
@@ -736,9 +717,7 @@ func TestGrantAccess(t *testing.T) {
 			crypto := new(mocks.Crypto)
 			client := new(mocks.TableauClient)
 			p := tableau.NewProvider("", crypto)
-			p.Clients = map[string]tableau.TableauClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantViewAccess", mock.Anything, mock.Anything, mock.Anything).Return(expectedError).Once()
 
 			pc := &domain.ProviderConfig{
@@ -774,7 +753,7 @@ func TestGrantAccess(t *testing.T) {
 				Role: "test-role",
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.EqualError(t, actualError, expectedError.Error())
 		})
@@ -790,9 +769,7 @@ func TestGrantAccess(t *testing.T) {
 			expectedUser := "test@email.com"
 			expectedRole := tableau.PermissionNames[tableau.ResourceTypeView][0] + ":" + tableau.PermissionModes[0]
 			p := tableau.NewProvider("", crypto)
-			p.Clients = map[string]tableau.TableauClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantViewAccess", expectedView, expectedUser, expectedRole).Return(nil).Once()
 
 			pc := &domain.ProviderConfig{
@@ -831,7 +808,7 @@ func TestGrantAccess(t *testing.T) {
 				ID:         99,
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.Nil(t, actualError)
 		})
@@ -844,9 +821,7 @@ func TestGrantAccess(t *testing.T) {
 			crypto := new(mocks.Crypto)
 			client := new(mocks.TableauClient)
 			p := tableau.NewProvider("", crypto)
-			p.Clients = map[string]tableau.TableauClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantMetricAccess", mock.Anything, mock.Anything, mock.Anything).Return(expectedError).Once()
 
 			pc := &domain.ProviderConfig{
@@ -882,7 +857,7 @@ func TestGrantAccess(t *testing.T) {
 				Role: "test-role",
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.EqualError(t, actualError, expectedError.Error())
 		})
@@ -898,9 +873,7 @@ func TestGrantAccess(t *testing.T) {
 			expectedUser := "test@email.com"
 			expectedRole := tableau.PermissionNames[tableau.ResourceTypeMetric][0] + ":" + tableau.PermissionModes[0]
 			p := tableau.NewProvider("", crypto)
-			p.Clients = map[string]tableau.TableauClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantMetricAccess", expectedMetric, expectedUser, expectedRole).Return(nil).Once()
 
 			pc := &domain.ProviderConfig{
@@ -939,7 +912,7 @@ func TestGrantAccess(t *testing.T) {
 				ID:         99,
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.Nil(t, actualError)
 		})
@@ -952,9 +925,7 @@ func TestGrantAccess(t *testing.T) {
 			crypto := new(mocks.Crypto)
 			client := new(mocks.TableauClient)
 			p := tableau.NewProvider("", crypto)
-			p.Clients = map[string]tableau.TableauClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantDataSourceAccess", mock.Anything, mock.Anything, mock.Anything).Return(expectedError).Once()
 
 			pc := &domain.ProviderConfig{
@@ -990,7 +961,7 @@ func TestGrantAccess(t *testing.T) {
 				Role: "test-role",
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.EqualError(t, actualError, expectedError.Error())
 		})
@@ -1006,9 +977,7 @@ func TestGrantAccess(t *testing.T) {
 			expectedUser := "test@email.com"
 			expectedRole := tableau.PermissionNames[tableau.ResourceTypeDataSource][0] + ":" + tableau.PermissionModes[0]
 			p := tableau.NewProvider("", crypto)
-			p.Clients = map[string]tableau.TableauClient{
-				providerURN: client,
-			}
+			p.Clients.Set(providerURN, client)
 			client.On("GrantDataSourceAccess", expectedDatasource, expectedUser, expectedRole).Return(nil).Once()
 
 			pc := &domain.ProviderConfig{
@@ -1047,7 +1016,7 @@ func TestGrantAccess(t *testing.T) {
 				ID:         99,
 			}
 
-			actualError := p.GrantAccess(pc, a)
+			actualError := p.GrantAccess(context.Background(), pc, a)
 
 			assert.Nil(t, actualError)
 		})
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+const (
+	defaultAccessVerificationPollInterval = time.Second
+	maxAccessVerificationPollInterval     = 30 * time.Second
+)
+
+// validateAccessVerification makes sure the access verification durations are parseable
+func validateAccessVerification(pc *domain.ProviderConfig) error {
+	if pc == nil || pc.AccessVerification == nil {
+		return nil
+	}
+
+	c := pc.AccessVerification
+	for _, v := range []string{c.PollInterval, c.Timeout} {
+		if v == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+			return fmt.Errorf("%w: %q", ErrInvalidAccessVerification, v)
+		}
+	}
+	switch c.OnTimeout {
+	case "", domain.AccessVerificationOnTimeoutFail, domain.AccessVerificationOnTimeoutActivate:
+	default:
+		return fmt.Errorf("%w: on_timeout %q", ErrInvalidAccessVerification, c.OnTimeout)
+	}
+	return nil
+}
+
+// pollAccessVerification verifies the access until it's confirmed or the timeout of the access verification
+// config elapses, waiting between the attempts with an exponential backoff. Once timed out, the access is
+// considered granted if the config says to activate on timeout. It verifies the access once if there's no timeout
+func pollAccessVerification(ctx context.Context, verifier domain.AccessVerifier, pc *domain.ProviderConfig, a *domain.Appeal) (bool, error) {
+	c := pc.AccessVerification
+	if c == nil || c.Timeout == "" {
+		return verifier.VerifyAccess(ctx, pc, a)
+	}
+
+	timeout, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		return false, fmt.Errorf("%w: %q", ErrInvalidAccessVerification, c.Timeout)
+	}
+	interval := defaultAccessVerificationPollInterval
+	if c.PollInterval != "" {
+		if interval, err = time.ParseDuration(c.PollInterval); err != nil {
+			return false, fmt.Errorf("%w: %q", ErrInvalidAccessVerification, c.PollInterval)
+		}
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		granted, err := verifier.VerifyAccess(ctx, pc, a)
+		if err != nil || granted {
+			return granted, err
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+		wait := interval
+		if wait > remaining {
+			wait = remaining
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval *= 2
+		if interval > maxAccessVerificationPollInterval {
+			interval = maxAccessVerificationPollInterval
+		}
+	}
+
+	return c.OnTimeout == domain.AccessVerificationOnTimeoutActivate, nil
+}
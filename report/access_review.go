@@ -0,0 +1,191 @@
+// Package report renders the compliance reports for the auditors, e.g. the access reviews
+package report
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+)
+
+const (
+	FormatCSV = "csv"
+	FormatPDF = "pdf"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported report format, expected either csv or pdf")
+
+var accessReviewHeader = []string{
+	"appeal_id",
+	"user",
+	"provider_type",
+	"provider_urn",
+	"resource_type",
+	"resource_urn",
+	"role",
+	"team",
+	"approved_by",
+	"granted_at",
+	"duration",
+	"expiration_date",
+	"outcome",
+	"revoked_by",
+	"revoked_at",
+	"revoke_reason",
+}
+
+// WriteAccessReview writes the access review of the filter period in the format, either csv or pdf
+func WriteAccessReview(w io.Writer, format string, filter domain.AccessReviewFilter, entries []*domain.AccessReviewEntry) error {
+	switch format {
+	case FormatCSV:
+		return writeAccessReviewCSV(w, entries)
+	case FormatPDF:
+		return writeAccessReviewPDF(w, filter, entries)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// writeAccessReviewCSV writes a row for each access, the permanent access has no duration nor expiration date
+func writeAccessReviewCSV(w io.Writer, entries []*domain.AccessReviewEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(accessReviewHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := cw.Write([]string{
+			fmt.Sprintf("%v", e.AppealID),
+			e.User,
+			e.ProviderType,
+			e.ProviderURN,
+			e.ResourceType,
+			e.ResourceURN,
+			e.Role,
+			e.Team,
+			formatApprovers(e.Approvals),
+			formatTime(&e.GrantedAt),
+			formatDuration(e),
+			formatTime(e.ExpirationDate),
+			e.Outcome,
+			e.RevokedBy,
+			formatTime(e.RevokedAt),
+			e.RevokeReason,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeAccessReviewPDF writes a block of lines for each access, following a summary of the outcomes
+func writeAccessReviewPDF(w io.Writer, filter domain.AccessReviewFilter, entries []*domain.AccessReviewEntry) error {
+	outcomes := map[string]int{}
+	for _, e := range entries {
+		outcomes[e.Outcome]++
+	}
+
+	lines := []string{
+		"Access review",
+		fmt.Sprintf("Period: %s to %s", formatTime(&filter.From), formatTime(&filter.To)),
+	}
+	for _, f := range []struct {
+		name   string
+		values []string
+	}{
+		{"Provider types", filter.ProviderTypes},
+		{"Provider URNs", filter.ProviderURNs},
+		{"Teams", filter.Teams},
+	} {
+		if len(f.values) > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %s", f.name, strings.Join(f.values, ", ")))
+		}
+	}
+	lines = append(lines,
+		fmt.Sprintf("Access granted: %d (%d active, %d expired, %d revoked, %d revocation pending)",
+			len(entries),
+			outcomes[domain.AccessReviewOutcomeActive],
+			outcomes[domain.AccessReviewOutcomeExpired],
+			outcomes[domain.AccessReviewOutcomeRevoked],
+			outcomes[domain.AccessReviewOutcomeRevocationPending],
+		),
+		"",
+	)
+
+	for _, e := range entries {
+		team := ""
+		if e.Team != "" {
+			team = fmt.Sprintf(", team %s", e.Team)
+		}
+		lines = append(lines,
+			fmt.Sprintf("#%d %s as %s on %s (%s %s/%s%s)", e.AppealID, e.User, e.Role, e.ResourceURN, e.ResourceType, e.ProviderType, e.ProviderURN, team),
+			fmt.Sprintf("    granted %s for %s, %s", formatTime(&e.GrantedAt), formatDuration(e), formatOutcome(e)),
+			fmt.Sprintf("    approved by %s", formatApprovers(e.Approvals)),
+			"",
+		)
+	}
+
+	return writeTextPDF(w, lines)
+}
+
+// formatApprovers lists the effective approver of each approved step, along with how they acted unless they're one
+// of the step's approvers, e.g. "owner_approval: owner@email.com; security_approval: admin@email.com (admin_override)"
+func formatApprovers(approvals []*domain.ApprovalProvenance) string {
+	if len(approvals) == 0 {
+		return domain.ApprovalActedAsSystem
+	}
+
+	approvers := []string{}
+	for _, p := range approvals {
+		approver := p.EffectiveApprover
+		switch {
+		case approver == "":
+			approver = p.ActedAs
+		case p.ActedAs == domain.ApprovalActedAsDelegate:
+			approver = fmt.Sprintf("%s (delegate of %s)", approver, p.OnBehalfOf)
+		case p.ActedAs != domain.ApprovalActedAsApprover:
+			approver = fmt.Sprintf("%s (%s)", approver, p.ActedAs)
+		}
+		approvers = append(approvers, fmt.Sprintf("%s: %s", p.ApprovalName, approver))
+	}
+	return strings.Join(approvers, "; ")
+}
+
+func formatDuration(e *domain.AccessReviewEntry) string {
+	if e.ExpirationDate == nil {
+		return "permanent"
+	}
+	return e.GetDuration().Round(time.Minute).String()
+}
+
+func formatOutcome(e *domain.AccessReviewEntry) string {
+	switch e.Outcome {
+	case domain.AccessReviewOutcomeExpired:
+		return fmt.Sprintf("expired %s", formatTime(e.RevokedAt))
+	case domain.AccessReviewOutcomeRevoked, domain.AccessReviewOutcomeRevocationPending:
+		outcome := strings.ReplaceAll(e.Outcome, "_", " ")
+		if e.RevokedAt != nil {
+			outcome = fmt.Sprintf("%s %s by %s", outcome, formatTime(e.RevokedAt), e.RevokedBy)
+		}
+		if e.RevokeReason != "" {
+			outcome = fmt.Sprintf("%s: %s", outcome, e.RevokeReason)
+		}
+		return outcome
+	default:
+		if e.ExpirationDate != nil {
+			return fmt.Sprintf("active until %s", formatTime(e.ExpirationDate))
+		}
+		return "active"
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
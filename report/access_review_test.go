@@ -0,0 +1,99 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteAccessReview(t *testing.T) {
+	grantedAt := time.Date(2026, 8, 1, 10, 0, 0, 0, time.UTC)
+	expirationDate := grantedAt.Add(24 * time.Hour)
+	revokedAt := grantedAt.Add(time.Hour)
+	filter := domain.AccessReviewFilter{
+		From:  time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		To:    time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Teams: []string{"data"},
+	}
+	entries := []*domain.AccessReviewEntry{
+		{
+			AppealID:     1,
+			User:         "user@email.com",
+			ProviderType: "bigquery",
+			ProviderURN:  "bq-prod",
+			ResourceType: "dataset",
+			ResourceURN:  "project:dataset",
+			Role:         "viewer",
+			Team:         "data",
+			Approvals: []*domain.ApprovalProvenance{
+				{ApprovalName: "owner_approval", EffectiveApprover: "owner@email.com", ActedAs: domain.ApprovalActedAsApprover},
+				{ApprovalName: "security_approval", EffectiveApprover: "admin@email.com", ActedAs: domain.ApprovalActedAsAdminOverride},
+			},
+			GrantedAt:      grantedAt,
+			ExpirationDate: &expirationDate,
+			Outcome:        domain.AccessReviewOutcomeRevoked,
+			RevokedBy:      "admin@email.com",
+			RevokedAt:      &revokedAt,
+			RevokeReason:   "left the team",
+		},
+		{
+			AppealID:     2,
+			User:         "other.user@email.com",
+			ProviderType: "bigquery",
+			ProviderURN:  "bq-prod",
+			ResourceType: "dataset",
+			ResourceURN:  "project:dataset",
+			Role:         "viewer",
+			Team:         "data",
+			Approvals:    []*domain.ApprovalProvenance{},
+			GrantedAt:    grantedAt,
+			Outcome:      domain.AccessReviewOutcomeActive,
+		},
+	}
+
+	t.Run("should return error if the format is not supported", func(t *testing.T) {
+		err := report.WriteAccessReview(&bytes.Buffer{}, "xlsx", filter, entries)
+
+		assert.True(t, errors.Is(err, report.ErrUnsupportedFormat))
+	})
+
+	t.Run("should write a csv row for each access", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := report.WriteAccessReview(&buf, report.FormatCSV, filter, entries)
+
+		assert.Nil(t, err)
+		rows, err := csv.NewReader(&buf).ReadAll()
+		assert.Nil(t, err)
+		assert.Equal(t, [][]string{
+			{"appeal_id", "user", "provider_type", "provider_urn", "resource_type", "resource_urn", "role", "team", "approved_by", "granted_at", "duration", "expiration_date", "outcome", "revoked_by", "revoked_at", "revoke_reason"},
+			{"1", "user@email.com", "bigquery", "bq-prod", "dataset", "project:dataset", "viewer", "data", "owner_approval: owner@email.com; security_approval: admin@email.com (admin_override)", "2026-08-01T10:00:00Z", "24h0m0s", "2026-08-02T10:00:00Z", "revoked", "admin@email.com", "2026-08-01T11:00:00Z", "left the team"},
+			{"2", "other.user@email.com", "bigquery", "bq-prod", "dataset", "project:dataset", "viewer", "data", "system", "2026-08-01T10:00:00Z", "permanent", "", "active", "", "", ""},
+		}, rows)
+	})
+
+	t.Run("should write a pdf document paginating the access", func(t *testing.T) {
+		many := []*domain.AccessReviewEntry{}
+		for i := 0; i < 30; i++ {
+			many = append(many, entries...)
+		}
+		var buf bytes.Buffer
+
+		err := report.WriteAccessReview(&buf, report.FormatPDF, filter, many)
+
+		assert.Nil(t, err)
+		document := buf.String()
+		assert.True(t, strings.HasPrefix(document, "%PDF-1.4\n"))
+		assert.True(t, strings.HasSuffix(document, "%%EOF\n"))
+		assert.Contains(t, document, "(Access granted: 60 \\(30 active, 0 expired, 30 revoked, 0 revocation pending\\)) Tj")
+		assert.Contains(t, document, "revoked 2026-08-01T11:00:00Z by admin@email.com: left the team")
+		assert.Contains(t, document, "/Count 6 >>")
+	})
+}
@@ -7,4 +7,6 @@ var (
 	ErrEmptyIDParam = errors.New("id can't be empty")
 	// ErrRecordNotFound is the error value if the designated record id is not exists
 	ErrRecordNotFound = errors.New("record not found")
+	// ErrForbidden is the error value if the actor isn't allowed to change the deny list
+	ErrForbidden = errors.New("only admins are allowed to change the resource deny list")
 )
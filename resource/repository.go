@@ -1,6 +1,9 @@
 package resource
 
 import (
+	"context"
+	"encoding/json"
+
 	"github.com/mitchellh/mapstructure"
 	"github.com/odpf/guardian/domain"
 	"github.com/odpf/guardian/model"
@@ -10,7 +13,18 @@ import (
 )
 
 type findFilters struct {
-	IDs []uint `mapstructure:"ids" validate:"omitempty,min=1"`
+	IDs  []uint   `mapstructure:"ids" validate:"omitempty,min=1"`
+	URNs []string `mapstructure:"urns" validate:"omitempty,min=1"`
+	// Tags matches the resources having all of the tags
+	Tags map[string]string `mapstructure:"tags"`
+	// TypeKeys matches the resources of any of the provider resource types
+	TypeKeys    []domain.ResourceTypeKey `mapstructure:"type_keys" validate:"omitempty,min=1"`
+	DenyAppeals *bool                    `mapstructure:"deny_appeals"`
+	// OrganizationID matches the resources of the organization
+	OrganizationID string `mapstructure:"organization_id"`
+	// Limit and Offset paginate the resources ordered by id
+	Limit  int `mapstructure:"limit" validate:"omitempty,min=0"`
+	Offset int `mapstructure:"offset" validate:"omitempty,min=0"`
 }
 
 // Repository talks to the store/database to read/insert data
@@ -24,7 +38,7 @@ func NewRepository(db *gorm.DB) *Repository {
 }
 
 // Find records based on filters
-func (r *Repository) Find(filters map[string]interface{}) ([]*domain.Resource, error) {
+func (r *Repository) Find(ctx context.Context, filters map[string]interface{}) ([]*domain.Resource, error) {
 	var conditions findFilters
 	if err := mapstructure.Decode(filters, &conditions); err != nil {
 		return nil, err
@@ -33,10 +47,42 @@ func (r *Repository) Find(filters map[string]interface{}) ([]*domain.Resource, e
 		return nil, err
 	}
 
-	db := r.db
+	db := r.db.WithContext(ctx)
 	if conditions.IDs != nil {
 		db = db.Where(conditions.IDs)
 	}
+	if conditions.URNs != nil {
+		db = db.Where(`"urn" IN ?`, conditions.URNs)
+	}
+	if len(conditions.Tags) > 0 {
+		tags, err := json.Marshal(conditions.Tags)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(`"tags" @> ?`, string(tags))
+	}
+	if conditions.TypeKeys != nil {
+		keys := [][]interface{}{}
+		for _, k := range conditions.TypeKeys {
+			keys = append(keys, []interface{}{k.ProviderType, k.ProviderURN, k.Type})
+		}
+		db = db.Where(`("provider_type", "provider_urn", "type") IN ?`, keys)
+	}
+	if conditions.DenyAppeals != nil {
+		db = db.Where(`"deny_appeals" = ?`, *conditions.DenyAppeals)
+	}
+	if conditions.OrganizationID != "" {
+		db = db.Where(`"organization_id" = ?`, conditions.OrganizationID)
+	}
+	if conditions.Limit > 0 || conditions.Offset > 0 {
+		db = db.Order("id")
+		if conditions.Limit > 0 {
+			db = db.Limit(conditions.Limit)
+		}
+		if conditions.Offset > 0 {
+			db = db.Offset(conditions.Offset)
+		}
+	}
 	var models []*model.Resource
 	if err := db.Find(&models).Error; err != nil {
 		return nil, err
@@ -56,13 +102,13 @@ func (r *Repository) Find(filters map[string]interface{}) ([]*domain.Resource, e
 }
 
 // GetOne record by ID
-func (r *Repository) GetOne(id uint) (*domain.Resource, error) {
+func (r *Repository) GetOne(ctx context.Context, id uint) (*domain.Resource, error) {
 	if id == 0 {
 		return nil, ErrEmptyIDParam
 	}
 
 	var m model.Resource
-	if err := r.db.Where("id = ?", id).Take(&m).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Take(&m).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -78,7 +124,7 @@ func (r *Repository) GetOne(id uint) (*domain.Resource, error) {
 }
 
 // BulkUpsert inserts records if the records are not exist, or updates the records if they are already exist
-func (r *Repository) BulkUpsert(resources []*domain.Resource) error {
+func (r *Repository) BulkUpsert(ctx context.Context, resources []*domain.Resource) error {
 	var models []*model.Resource
 	for _, r := range resources {
 		m := new(model.Resource)
@@ -89,7 +135,7 @@ func (r *Repository) BulkUpsert(resources []*domain.Resource) error {
 		models = append(models, m)
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		upsertClause := clause.OnConflict{
 			Columns: []clause.Column{
 				{Name: "provider_type"},
@@ -97,9 +143,9 @@ func (r *Repository) BulkUpsert(resources []*domain.Resource) error {
 				{Name: "type"},
 				{Name: "urn"},
 			},
-			DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+			DoUpdates: clause.AssignmentColumns([]string{"name", "tags", "organization_id", "updated_at"}),
 		}
-		if err := r.db.Clauses(upsertClause).Create(models).Error; err != nil {
+		if err := r.db.WithContext(ctx).Clauses(upsertClause).Create(models).Error; err != nil {
 			return err
 		}
 
@@ -115,8 +161,27 @@ func (r *Repository) BulkUpsert(resources []*domain.Resource) error {
 	})
 }
 
+// SetDenyAppeals puts the resource on the deny list or takes it off along with the reason
+func (r *Repository) SetDenyAppeals(ctx context.Context, id uint, deny bool, reason string) error {
+	if id == 0 {
+		return ErrEmptyIDParam
+	}
+
+	result := r.db.WithContext(ctx).Model(&model.Resource{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deny_appeals":        deny,
+		"deny_appeals_reason": reason,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
 // Update record by ID
-func (r *Repository) Update(resource *domain.Resource) error {
+func (r *Repository) Update(ctx context.Context, resource *domain.Resource) error {
 	if resource.ID == 0 {
 		return ErrEmptyIDParam
 	}
@@ -126,7 +191,7 @@ func (r *Repository) Update(resource *domain.Resource) error {
 		return err
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Model(m).Where("id = ?", m.ID).Updates(*m).Error; err != nil {
 			return err
 		}
@@ -1,9 +1,11 @@
 package resource_test
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"regexp"
 	"testing"
 	"time"
@@ -67,12 +69,39 @@ func (s *RepositoryTestSuite) TestFind() {
 				expectedQuery: regexp.QuoteMeta(`SELECT * FROM "resources" WHERE "resources"."id" IN ($1,$2,$3) AND "resources"."deleted_at" IS NULL`),
 				expectedArgs:  []driver.Value{1, 2, 3},
 			},
+			{
+				filters: map[string]interface{}{
+					"urns": []string{"urn-1", "urn-2"},
+				},
+				expectedQuery: regexp.QuoteMeta(`SELECT * FROM "resources" WHERE "urn" IN ($1,$2) AND "resources"."deleted_at" IS NULL`),
+				expectedArgs:  []driver.Value{"urn-1", "urn-2"},
+			},
+			{
+				filters: map[string]interface{}{
+					"tags": map[string]string{"environment": "production"},
+				},
+				expectedQuery: regexp.QuoteMeta(`SELECT * FROM "resources" WHERE "tags" @> $1 AND "resources"."deleted_at" IS NULL`),
+				expectedArgs:  []driver.Value{`{"environment":"production"}`},
+			},
+			{
+				filters: map[string]interface{}{
+					"type_keys": []domain.ResourceTypeKey{
+						{ProviderType: "bigquery", ProviderURN: "project", Type: "dataset"},
+						{ProviderType: "bigquery", ProviderURN: "project", Type: "table"},
+					},
+					"deny_appeals": false,
+					"limit":        10,
+					"offset":       20,
+				},
+				expectedQuery: regexp.QuoteMeta(`SELECT * FROM "resources" WHERE ("provider_type", "provider_urn", "type") IN (($1,$2,$3),($4,$5,$6)) AND "deny_appeals" = $7 AND "resources"."deleted_at" IS NULL ORDER BY id LIMIT 10 OFFSET 20`),
+				expectedArgs:  []driver.Value{"bigquery", "project", "dataset", "bigquery", "project", "table", false},
+			},
 		}
 
 		for _, tc := range testCases {
 			s.dbmock.ExpectQuery(tc.expectedQuery).WithArgs(tc.expectedArgs...).WillReturnRows(sqlmock.NewRows(s.columnNames))
 
-			_, actualError := s.repository.Find(tc.filters)
+			_, actualError := s.repository.Find(context.Background(), tc.filters)
 
 			s.Nil(actualError)
 			s.dbmock.ExpectationsWereMet()
@@ -87,7 +116,7 @@ func (s *RepositoryTestSuite) TestFind() {
 		s.dbmock.ExpectQuery(expectedQuery).
 			WillReturnError(expectedError)
 
-		actualRecords, actualError := s.repository.Find(map[string]interface{}{})
+		actualRecords, actualError := s.repository.Find(context.Background(), map[string]interface{}{})
 
 		s.EqualError(actualError, expectedError.Error())
 		s.Nil(actualRecords)
@@ -121,7 +150,7 @@ func (s *RepositoryTestSuite) TestFind() {
 
 		s.dbmock.ExpectQuery(expectedQuery).WillReturnRows(expectedRows)
 
-		actualRecords, actualError := s.repository.Find(map[string]interface{}{})
+		actualRecords, actualError := s.repository.Find(context.Background(), map[string]interface{}{})
 
 		s.Equal(expectedRecords, actualRecords)
 		s.Nil(actualError)
@@ -132,7 +161,7 @@ func (s *RepositoryTestSuite) TestGetOne() {
 	s.Run("should return error if id is empty", func() {
 		expectedError := resource.ErrEmptyIDParam
 
-		actualResult, actualError := s.repository.GetOne(0)
+		actualResult, actualError := s.repository.GetOne(context.Background(), 0)
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -143,7 +172,7 @@ func (s *RepositoryTestSuite) TestGetOne() {
 		s.dbmock.ExpectQuery(".*").
 			WillReturnError(expectedError)
 
-		actualResult, actualError := s.repository.GetOne(1)
+		actualResult, actualError := s.repository.GetOne(context.Background(), 1)
 
 		s.Nil(actualResult)
 		s.Nil(actualError)
@@ -154,7 +183,7 @@ func (s *RepositoryTestSuite) TestGetOne() {
 		s.dbmock.ExpectQuery(".*").
 			WillReturnError(expectedError)
 
-		actualResult, actualError := s.repository.GetOne(1)
+		actualResult, actualError := s.repository.GetOne(context.Background(), 1)
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -180,7 +209,7 @@ func (s *RepositoryTestSuite) TestGetOne() {
 			WithArgs(expectedID).
 			WillReturnRows(expectedRows)
 
-		_, actualError := s.repository.GetOne(expectedID)
+		_, actualError := s.repository.GetOne(context.Background(), expectedID)
 
 		s.Nil(actualError)
 		s.dbmock.ExpectationsWereMet()
@@ -196,6 +225,7 @@ func (s *RepositoryTestSuite) TestBulkUpsert() {
 				Type:         "resource_type",
 				URN:          "resource_type.resource_name",
 				Name:         "resource_name",
+				Tags:         map[string]string{"environment": "production"},
 			},
 			{
 				ProviderType: "provider_test",
@@ -203,10 +233,11 @@ func (s *RepositoryTestSuite) TestBulkUpsert() {
 				Type:         "resource_type",
 				URN:          "resource_type.resource_name_2",
 				Name:         "resource_name_2",
+				Tags:         map[string]string{"environment": "staging"},
 			},
 		}
 
-		expectedQuery := regexp.QuoteMeta(`INSERT INTO "resources" ("provider_type","provider_urn","type","urn","name","details","labels","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10),($11,$12,$13,$14,$15,$16,$17,$18,$19,$20) ON CONFLICT ("provider_type","provider_urn","type","urn") DO UPDATE SET "name"="excluded"."name","updated_at"="excluded"."updated_at" RETURNING "id"`)
+		expectedQuery := regexp.QuoteMeta(`INSERT INTO "resources" ("provider_type","provider_urn","type","urn","name","details","labels","tags","deny_appeals","deny_appeals_reason","organization_id","created_at","updated_at","deleted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14),($15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28) ON CONFLICT ("provider_type","provider_urn","type","urn") DO UPDATE SET "name"="excluded"."name","tags"="excluded"."tags","organization_id"="excluded"."organization_id","updated_at"="excluded"."updated_at" RETURNING "id"`)
 		expectedArgs := []driver.Value{}
 		for _, r := range resources {
 			expectedArgs = append(expectedArgs,
@@ -217,6 +248,10 @@ func (s *RepositoryTestSuite) TestBulkUpsert() {
 				r.Name,
 				"null",
 				"null",
+				fmt.Sprintf(`{"environment":"%s"}`, r.Tags["environment"]),
+				r.DenyAppeals,
+				r.DenyAppealsReason,
+				r.OrganizationID,
 				utils.AnyTime{},
 				utils.AnyTime{},
 				gorm.DeletedAt{},
@@ -233,7 +268,7 @@ func (s *RepositoryTestSuite) TestBulkUpsert() {
 			WillReturnRows(expectedRows)
 		s.dbmock.ExpectCommit()
 
-		err := s.repository.BulkUpsert(resources)
+		err := s.repository.BulkUpsert(context.Background(), resources)
 
 		s.Nil(err)
 		for i, r := range resources {
@@ -246,7 +281,7 @@ func (s *RepositoryTestSuite) TestUpdate() {
 	s.Run("should return error if id is empty", func() {
 		expectedError := resource.ErrEmptyIDParam
 
-		actualError := s.repository.Update(&domain.Resource{})
+		actualError := s.repository.Update(context.Background(), &domain.Resource{})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -258,7 +293,7 @@ func (s *RepositoryTestSuite) TestUpdate() {
 			WillReturnError(expectedError)
 		s.dbmock.ExpectRollback()
 
-		actualError := s.repository.Update(&domain.Resource{ID: 1})
+		actualError := s.repository.Update(context.Background(), &domain.Resource{ID: 1})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -275,7 +310,7 @@ func (s *RepositoryTestSuite) TestUpdate() {
 			WillReturnResult(sqlmock.NewResult(int64(expectedID), 1))
 		s.dbmock.ExpectCommit()
 
-		err := s.repository.Update(resource)
+		err := s.repository.Update(context.Background(), resource)
 
 		actualID := resource.ID
 
@@ -284,6 +319,40 @@ func (s *RepositoryTestSuite) TestUpdate() {
 	})
 }
 
+func (s *RepositoryTestSuite) TestSetDenyAppeals() {
+	s.Run("should return error if id is empty", func() {
+		actualError := s.repository.SetDenyAppeals(context.Background(), 0, true, "")
+
+		s.EqualError(actualError, resource.ErrEmptyIDParam.Error())
+	})
+
+	expectedQuery := regexp.QuoteMeta(`UPDATE "resources" SET "deny_appeals"=$1,"deny_appeals_reason"=$2,"updated_at"=$3 WHERE id = $4`)
+	s.Run("should return not found error if the resource doesn't exist", func() {
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectExec(expectedQuery).
+			WithArgs(true, "reason", utils.AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		s.dbmock.ExpectCommit()
+
+		actualError := s.repository.SetDenyAppeals(context.Background(), 1, true, "reason")
+
+		s.EqualError(actualError, resource.ErrRecordNotFound.Error())
+	})
+
+	s.Run("should update the deny list flag and reason", func() {
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectExec(expectedQuery).
+			WithArgs(false, "", utils.AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.dbmock.ExpectCommit()
+
+		actualError := s.repository.SetDenyAppeals(context.Background(), 1, false, "")
+
+		s.Nil(actualError)
+		s.Nil(s.dbmock.ExpectationsWereMet())
+	})
+}
+
 func TestRepository(t *testing.T) {
 	suite.Run(t, new(RepositoryTestSuite))
 }
@@ -1,33 +1,50 @@
 package resource
 
 import (
+	"context"
+
 	"github.com/imdario/mergo"
 	"github.com/odpf/guardian/domain"
+	"github.com/odpf/guardian/utils"
 )
 
 // Service handles the business logic for resource
 type Service struct {
 	repo domain.ResourceRepository
+	// admins are the users allowed to change the deny list
+	admins []string
 }
 
 // NewService returns *Service
-func NewService(repo domain.ResourceRepository) *Service {
-	return &Service{repo}
+func NewService(repo domain.ResourceRepository, admins []string) *Service {
+	return &Service{repo, admins}
 }
 
 // Find records based on filters
-func (s *Service) Find(filters map[string]interface{}) ([]*domain.Resource, error) {
-	return s.repo.Find(filters)
+func (s *Service) Find(ctx context.Context, filters map[string]interface{}) ([]*domain.Resource, error) {
+	return s.repo.Find(ctx, filters)
 }
 
 // BulkUpsert inserts or updates records
-func (s *Service) BulkUpsert(resources []*domain.Resource) error {
-	return s.repo.BulkUpsert(resources)
+func (s *Service) BulkUpsert(ctx context.Context, resources []*domain.Resource) error {
+	return s.repo.BulkUpsert(ctx, resources)
+}
+
+// SetDenyAppeals puts the resource on the deny list, or takes it off, on behalf of an admin. The reason is
+// returned to the users appealing to the resource, it's cleared once the resource is taken off the deny list
+func (s *Service) SetDenyAppeals(ctx context.Context, id uint, deny bool, reason, actor string) error {
+	if !utils.ContainsString(s.admins, actor) {
+		return ErrForbidden
+	}
+	if !deny {
+		reason = ""
+	}
+	return s.repo.SetDenyAppeals(ctx, id, deny, reason)
 }
 
 // Update updates only details and labels of a resource by ID
-func (s *Service) Update(r *domain.Resource) error {
-	existingResource, err := s.repo.GetOne(r.ID)
+func (s *Service) Update(ctx context.Context, r *domain.Resource) error {
+	existingResource, err := s.repo.GetOne(ctx, r.ID)
 	if err != nil {
 		return err
 	}
@@ -44,7 +61,7 @@ func (s *Service) Update(r *domain.Resource) error {
 		Details: r.Details,
 		Labels:  r.Labels,
 	}
-	if err := s.repo.Update(res); err != nil {
+	if err := s.repo.Update(ctx, res); err != nil {
 		return err
 	}
 
@@ -1,6 +1,7 @@
 package resource_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -19,15 +20,15 @@ type ServiceTestSuite struct {
 
 func (s *ServiceTestSuite) SetupTest() {
 	s.mockRepository = new(mocks.ResourceRepository)
-	s.service = resource.NewService(s.mockRepository)
+	s.service = resource.NewService(s.mockRepository, []string{"admin@email.com"})
 }
 
 func (s *ServiceTestSuite) TestFind() {
 	s.Run("should return nil and error if got error from repository", func() {
 		expectedError := errors.New("error from repository")
-		s.mockRepository.On("Find", mock.Anything).Return(nil, expectedError).Once()
+		s.mockRepository.On("Find", mock.Anything, mock.Anything).Return(nil, expectedError).Once()
 
-		actualResult, actualError := s.service.Find(map[string]interface{}{})
+		actualResult, actualError := s.service.Find(context.Background(), map[string]interface{}{})
 
 		s.Nil(actualResult)
 		s.EqualError(actualError, expectedError.Error())
@@ -36,9 +37,9 @@ func (s *ServiceTestSuite) TestFind() {
 	s.Run("should return list of records on success", func() {
 		expectedFilters := map[string]interface{}{}
 		expectedResult := []*domain.Resource{}
-		s.mockRepository.On("Find", expectedFilters).Return(expectedResult, nil).Once()
+		s.mockRepository.On("Find", mock.Anything, expectedFilters).Return(expectedResult, nil).Once()
 
-		actualResult, actualError := s.service.Find(expectedFilters)
+		actualResult, actualError := s.service.Find(context.Background(), expectedFilters)
 
 		s.Equal(expectedResult, actualResult)
 		s.Nil(actualError)
@@ -49,14 +50,41 @@ func (s *ServiceTestSuite) TestFind() {
 func (s *ServiceTestSuite) TestBulkUpsert() {
 	s.Run("should return error if got error from repository", func() {
 		expectedError := errors.New("error from repository")
-		s.mockRepository.On("BulkUpsert", mock.Anything).Return(expectedError).Once()
+		s.mockRepository.On("BulkUpsert", mock.Anything, mock.Anything).Return(expectedError).Once()
 
-		actualError := s.service.BulkUpsert([]*domain.Resource{})
+		actualError := s.service.BulkUpsert(context.Background(), []*domain.Resource{})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
 }
 
+func (s *ServiceTestSuite) TestSetDenyAppeals() {
+	s.Run("should return forbidden error if the actor is not an admin", func() {
+		actualError := s.service.SetDenyAppeals(context.Background(), 1, true, "reason", "user@email.com")
+
+		s.EqualError(actualError, resource.ErrForbidden.Error())
+		s.mockRepository.AssertNotCalled(s.T(), "SetDenyAppeals", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	s.Run("should return error if got error from repository", func() {
+		expectedError := errors.New("error from repository")
+		s.mockRepository.On("SetDenyAppeals", mock.Anything, uint(1), true, "reason").Return(expectedError).Once()
+
+		actualError := s.service.SetDenyAppeals(context.Background(), 1, true, "reason", "admin@email.com")
+
+		s.EqualError(actualError, expectedError.Error())
+	})
+
+	s.Run("should clear the reason when taking the resource off the deny list", func() {
+		s.mockRepository.On("SetDenyAppeals", mock.Anything, uint(1), false, "").Return(nil).Once()
+
+		actualError := s.service.SetDenyAppeals(context.Background(), 1, false, "reason", "admin@email.com")
+
+		s.Nil(actualError)
+		s.mockRepository.AssertExpectations(s.T())
+	})
+}
+
 func (s *ServiceTestSuite) TestUpdate() {
 	s.Run("should return error if got error getting existing record", func() {
 		testCases := []struct {
@@ -81,9 +109,9 @@ func (s *ServiceTestSuite) TestUpdate() {
 				ID: 1,
 			}
 			expectedError := tc.expectedError
-			s.mockRepository.On("GetOne", expectedResource.ID).Return(tc.expectedExistingResource, tc.expectedRepositoryError).Once()
+			s.mockRepository.On("GetOne", mock.Anything, expectedResource.ID).Return(tc.expectedExistingResource, tc.expectedRepositoryError).Once()
 
-			actualError := s.service.Update(expectedResource)
+			actualError := s.service.Update(context.Background(), expectedResource)
 
 			s.EqualError(actualError, expectedError.Error())
 		}
@@ -91,10 +119,10 @@ func (s *ServiceTestSuite) TestUpdate() {
 
 	s.Run("should return error if got error from repository", func() {
 		expectedError := errors.New("error from repository")
-		s.mockRepository.On("GetOne", mock.Anything).Return(&domain.Resource{}, nil).Once()
-		s.mockRepository.On("Update", mock.Anything).Return(expectedError).Once()
+		s.mockRepository.On("GetOne", mock.Anything, mock.Anything).Return(&domain.Resource{}, nil).Once()
+		s.mockRepository.On("Update", mock.Anything, mock.Anything).Return(expectedError).Once()
 
-		actualError := s.service.Update(&domain.Resource{})
+		actualError := s.service.Update(context.Background(), &domain.Resource{})
 
 		s.EqualError(actualError, expectedError.Error())
 	})
@@ -154,10 +182,10 @@ func (s *ServiceTestSuite) TestUpdate() {
 		}
 
 		for _, tc := range testCases {
-			s.mockRepository.On("GetOne", tc.resourceUpdatePayload.ID).Return(tc.existingResource, nil).Once()
-			s.mockRepository.On("Update", tc.expectedUpdatedValues).Return(nil).Once()
+			s.mockRepository.On("GetOne", mock.Anything, tc.resourceUpdatePayload.ID).Return(tc.existingResource, nil).Once()
+			s.mockRepository.On("Update", mock.Anything, tc.expectedUpdatedValues).Return(nil).Once()
 
-			actualError := s.service.Update(tc.resourceUpdatePayload)
+			actualError := s.service.Update(context.Background(), tc.resourceUpdatePayload)
 
 			s.Nil(actualError)
 			s.mockRepository.AssertExpectations(s.T())
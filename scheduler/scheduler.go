@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"context"
 	"log"
 	"runtime/debug"
 
@@ -9,6 +10,7 @@ import (
 
 // Task defines a particular job needs to be done by the scheduler
 type Task struct {
+	Name    string
 	CronTab string
 	Func    func() error
 }
@@ -23,7 +25,7 @@ func New(tasks []*Task) (*Scheduler, error) {
 	c := cron.New()
 
 	for _, t := range tasks {
-		_, err := c.AddFunc(t.CronTab, handler(t.Func))
+		_, err := c.AddFunc(t.CronTab, handler(t.Name, t.Func))
 		if err != nil {
 			return nil, err
 		}
@@ -37,16 +39,21 @@ func (s *Scheduler) Run() {
 	s.cron.Start()
 }
 
-func handler(fn func() error) func() {
+// Stop stops scheduling new runs. The returned context is done once the running tasks are completed
+func (s *Scheduler) Stop() context.Context {
+	return s.cron.Stop()
+}
+
+func handler(name string, fn func() error) func() {
 	return func() {
 		defer func() {
 			if err := recover(); err != nil {
-				log.Printf("scheduled job error: %v\n%v", err, string(debug.Stack()))
+				log.Printf("scheduled job %q error: %v\n%v", name, err, string(debug.Stack()))
 			}
 		}()
 
 		if err := fn(); err != nil {
-			log.Printf("scheduled job error: %v", err)
+			log.Printf("scheduled job %q error: %v", name, err)
 		}
 	}
 }
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"hash/fnv"
+
+	"gorm.io/gorm"
+)
+
+// WithAdvisoryLock runs fn while holding a session-level postgres advisory lock keyed by name,
+// so the same fn isn't run concurrently across instances. It returns false without running fn if
+// the lock is currently held by another session. The lock is held on a dedicated connection
+// instead of an open transaction, and is released once fn returns
+func WithAdvisoryLock(ctx context.Context, db *gorm.DB, name string, fn func() error) (bool, error) {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	key := int64(h.Sum64())
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	acquired := false
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		return false, err
+	}
+	if !acquired {
+		return false, nil
+	}
+
+	fnErr := fn()
+	// the lock is released even if ctx is done by now
+	if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+		// the connection still holding the lock must not go back to the pool, discarding it ends the
+		// session and so releases the lock
+		conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		if fnErr == nil {
+			return true, err
+		}
+	}
+
+	return true, fnErr
+}
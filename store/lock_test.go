@@ -0,0 +1,81 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/odpf/guardian/mocks"
+	"github.com/odpf/guardian/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAdvisoryLock(t *testing.T) {
+	expectedLockQuery := regexp.QuoteMeta(`SELECT pg_try_advisory_lock($1)`)
+	expectedUnlockQuery := regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)
+
+	t.Run("should run the function while holding the lock", func(t *testing.T) {
+		db, dbmock, _ := mocks.NewStore()
+		dbmock.ExpectQuery(expectedLockQuery).WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+		dbmock.ExpectExec(expectedUnlockQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+		called := false
+
+		acquired, err := store.WithAdvisoryLock(context.Background(), db, "job", func() error {
+			called = true
+			return nil
+		})
+
+		assert.Nil(t, err)
+		assert.True(t, acquired)
+		assert.True(t, called)
+		assert.Nil(t, dbmock.ExpectationsWereMet())
+	})
+
+	t.Run("should not run the function if the lock is held by another session", func(t *testing.T) {
+		db, dbmock, _ := mocks.NewStore()
+		dbmock.ExpectQuery(expectedLockQuery).WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+		called := false
+
+		acquired, err := store.WithAdvisoryLock(context.Background(), db, "job", func() error {
+			called = true
+			return nil
+		})
+
+		assert.Nil(t, err)
+		assert.False(t, acquired)
+		assert.False(t, called)
+		assert.Nil(t, dbmock.ExpectationsWereMet())
+	})
+
+	t.Run("should return the function error after releasing the lock", func(t *testing.T) {
+		db, dbmock, _ := mocks.NewStore()
+		dbmock.ExpectQuery(expectedLockQuery).WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+		dbmock.ExpectExec(expectedUnlockQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectedError := errors.New("job error")
+
+		acquired, err := store.WithAdvisoryLock(context.Background(), db, "job", func() error {
+			return expectedError
+		})
+
+		assert.Equal(t, expectedError, err)
+		assert.True(t, acquired)
+		assert.Nil(t, dbmock.ExpectationsWereMet())
+	})
+
+	t.Run("should return error if the lock can't be released", func(t *testing.T) {
+		db, dbmock, _ := mocks.NewStore()
+		dbmock.ExpectQuery(expectedLockQuery).WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+		expectedError := errors.New("unlock error")
+		dbmock.ExpectExec(expectedUnlockQuery).WillReturnError(expectedError)
+
+		acquired, err := store.WithAdvisoryLock(context.Background(), db, "job", func() error {
+			return nil
+		})
+
+		assert.Equal(t, expectedError, err)
+		assert.True(t, acquired)
+		assert.Nil(t, dbmock.ExpectationsWereMet())
+	})
+}
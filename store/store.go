@@ -36,7 +36,11 @@ func New(c *Config) (*gorm.DB, error) {
 		log.Panic(err)
 	}
 
-	return db, err
+	if err := db.Use(NewTracingPlugin()); err != nil {
+		return nil, err
+	}
+
+	return db, nil
 }
 
 // Migrate auto migrate models
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/odpf/guardian/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	tracerName      = "github.com/odpf/guardian/store"
+	spanInstanceKey = "tracing:span"
+)
+
+// TracingPlugin traces every database operation made by the repositories as a child span of
+// the operation context
+type TracingPlugin struct {
+	Tracer trace.Tracer
+}
+
+// NewTracingPlugin returns the gorm plugin using the tracer of the global tracer provider
+func NewTracingPlugin() *TracingPlugin {
+	return &TracingPlugin{Tracer: tracing.Tracer(tracerName)}
+}
+
+// Name returns the plugin name
+func (p *TracingPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize registers the callbacks starting and ending the spans around each operation
+func (p *TracingPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	registers := map[string][2]func(string, func(*gorm.DB)) error{
+		"create": {cb.Create().Before("*").Register, cb.Create().After("*").Register},
+		"query":  {cb.Query().Before("*").Register, cb.Query().After("*").Register},
+		"update": {cb.Update().Before("*").Register, cb.Update().After("*").Register},
+		"delete": {cb.Delete().Before("*").Register, cb.Delete().After("*").Register},
+		"row":    {cb.Row().Before("*").Register, cb.Row().After("*").Register},
+		"raw":    {cb.Raw().Before("*").Register, cb.Raw().After("*").Register},
+	}
+	for operation, register := range registers {
+		if err := register[0]("tracing:before_"+operation, p.before("db."+operation)); err != nil {
+			return err
+		}
+		if err := register[1]("tracing:after_"+operation, p.after); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *TracingPlugin) before(spanName string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement.Context == nil {
+			return
+		}
+		ctx, span := p.Tracer.Start(db.Statement.Context, spanName, trace.WithSpanKind(trace.SpanKindClient))
+		db.Statement.Context = ctx
+		db.InstanceSet(spanInstanceKey, span)
+	}
+}
+
+func (p *TracingPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.sql.table", db.Statement.Table),
+		attribute.String("db.statement", db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	err := db.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
@@ -0,0 +1,35 @@
+package store_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/odpf/guardian/mocks"
+	"github.com/odpf/guardian/store"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingPlugin(t *testing.T) {
+	db, dbmock, _ := mocks.NewStore()
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test")
+	assert.Nil(t, db.Use(&store.TracingPlugin{Tracer: tracer}))
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	dbmock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "appeals" WHERE "id" = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var result []map[string]interface{}
+	err := db.WithContext(ctx).Table("appeals").Where(`"id" = ?`, 1).Find(&result).Error
+	parent.End()
+
+	assert.Nil(t, err)
+	spans := spanRecorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "db.query", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
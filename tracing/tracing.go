@@ -0,0 +1,40 @@
+// Package tracing holds the shared helpers to trace the appeal lifecycle with OpenTelemetry.
+// The spans are sent to the global tracer provider, which is a no-op until a provider is
+// registered with otel.SetTracerProvider
+package tracing
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys
+const (
+	AppealIDKey     = attribute.Key("appeal.id")
+	AppealStatusKey = attribute.Key("appeal.status")
+	AppealCountKey  = attribute.Key("appeal.count")
+	ApprovalNameKey = attribute.Key("approval.name")
+	ActionKey       = attribute.Key("approval.action")
+	ProviderTypeKey = attribute.Key("provider.type")
+)
+
+// Tracer returns the named tracer of the global tracer provider
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// End records the error to the span, if any, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// AppealID returns the appeal id attribute
+func AppealID(id uint) attribute.KeyValue {
+	return AppealIDKey.Int64(int64(id))
+}